@@ -23,19 +23,130 @@ type AppOptions = app.AppOptions
 type AuthorizeFunc = app.AuthorizeFunc
 type AuthorizeSourceData = app.AuthorizeSourceData
 type AuthorizeResult = app.AuthorizeResult
+type BotIdentity = app.BotIdentity
 type ErrorHandler = app.ErrorHandler
 type ExtendedErrorHandler = app.ExtendedErrorHandler
+type PanicPolicy = app.PanicPolicy
+type PanicHandler = app.PanicHandler
+type Logger = types.Logger
 type LogLevel = types.LogLevel
 
 // App constructor
 var New = app.New
 
+// AuthorizeWithInstallationStore authorizes events with the installations of an InstallationStore
+var AuthorizeWithInstallationStore = app.AuthorizeWithInstallationStore
+
+// Config file types and loaders
+type Config = app.Config
+type ConfigRoute = app.ConfigRoute
+type ConfigOptions = app.ConfigOptions
+
+var NewFromConfig = app.NewFromConfig
+var LoadConfig = app.LoadConfig
+var ParseConfig = app.ParseConfig
+
+const (
+	ConfigReceiverHTTP       = app.ConfigReceiverHTTP
+	ConfigReceiverSocketMode = app.ConfigReceiverSocketMode
+)
+
+// Environment constructor and the variables it reads
+var NewFromEnv = app.NewFromEnv
+var OptionsFromEnv = app.OptionsFromEnv
+
+const (
+	EnvBotToken      = app.EnvBotToken
+	EnvAppToken      = app.EnvAppToken
+	EnvSigningSecret = app.EnvSigningSecret
+	EnvClientID      = app.EnvClientID
+	EnvClientSecret  = app.EnvClientSecret
+	EnvStateSecret   = app.EnvStateSecret
+	EnvRedirectURI   = app.EnvRedirectURI
+	EnvScopes        = app.EnvScopes
+	EnvLogLevel      = app.EnvLogLevel
+	EnvPort          = app.EnvPort
+)
+
+// Client pool types
+type WebClientPool = app.WebClientPool
+type WebClientPoolOptions = app.WebClientPoolOptions
+type WebClientPoolStats = app.WebClientPoolStats
+type HTTPClientOptions = app.HTTPClientOptions
+type TeamConcurrencyOptions = app.TeamConcurrencyOptions
+type ConcurrencyQueueOptions = app.ConcurrencyQueueOptions
+type ConcurrencyStats = app.ConcurrencyStats
+type OverflowPolicy = app.OverflowPolicy
+type RateLimitOptions = app.RateLimitOptions
+type RateLimitBudget = app.RateLimitBudget
+type Credentials = app.Credentials
+type CredentialsOptions = app.CredentialsOptions
+type SecretProvider = app.SecretProvider
+type MetricsOptions = app.MetricsOptions
+type MetricsSnapshot = app.MetricsSnapshot
+type LatencySnapshot = app.LatencySnapshot
+type Instrumentation = app.Instrumentation
+type ProcessEventInfo = app.ProcessEventInfo
+type ListenerOutcome = app.ListenerOutcome
+type LazyListenerRunner = app.LazyListenerRunner
+type LazyListenerRequest = app.LazyListenerRequest
+type ErrorReporter = app.ErrorReporter
+type ErrorReport = app.ErrorReport
+type EventStartHook = app.EventStartHook
+type EventEndHook = app.EventEndHook
+type ListenerStartHook = app.ListenerStartHook
+type ListenerEndHook = app.ListenerEndHook
+type AuditSink = app.AuditSink
+type AuditOptions = app.AuditOptions
+type AuditRecord = app.AuditRecord
+type AuditListenerOutcome = app.AuditListenerOutcome
+type FanOutSink = app.FanOutSink
+type FanOutOptions = app.FanOutOptions
+type FanOutMessage = app.FanOutMessage
+type EventDeduper = app.EventDeduper
+type MemoryEventDeduper = app.MemoryEventDeduper
+type RetryOptions = app.RetryOptions
+type EventRecorder = app.EventRecorder
+type ManifestOptions = app.ManifestOptions
+type Manifest = app.Manifest
+type ManifestShortcut = app.ManifestShortcut
+type ManifestSlashCommand = app.ManifestSlashCommand
+type ServeOptions = app.ServeOptions
+
+// Client pool constructors
+var NewWebClientPool = app.NewWebClientPool
+var NewWebClientPoolWithOptions = app.NewWebClientPoolWithOptions
+
+// Event deduplication
+var NewMemoryEventDeduper = app.NewMemoryEventDeduper
+
+const DefaultDedupeTTL = app.DefaultDedupeTTL
+
+// DefaultResponseURLHosts are the hosts respond() may post to unless AppOptions.ResponseURLHosts is set
+var DefaultResponseURLHosts = app.DefaultResponseURLHosts
+
+// Correlation IDs identify an event across logs, Slack API calls and error handlers
+var WithCorrelationID = app.WithCorrelationID
+var CorrelationIDFromContext = app.CorrelationIDFromContext
+
+// Replayed events are marked in their context and not recorded again
+// LazyListenerHeader names the lazy function a request re-invoking the app runs
+const LazyListenerHeader = app.LazyListenerHeader
+
+// LazyListenerSignatureHeader authenticates a request re-invoking the app
+const LazyListenerSignatureHeader = app.LazyListenerSignatureHeader
+
+var WithReplay = app.WithReplay
+var IsReplay = app.IsReplay
+
 // Type definitions
 type Context = types.Context
 type Middleware[T any] = types.Middleware[T]
 type NextFn = types.NextFn
 type SayFn = types.SayFn
 type RespondFn = types.RespondFn
+type UpdateMessageFn = types.UpdateMessageFn
+type DeleteMessageFn = types.DeleteMessageFn
 type AckFn[T any] = types.AckFn[T]
 
 // Middleware argument types
@@ -47,9 +158,17 @@ type SlackShortcutMiddlewareArgs = types.SlackShortcutMiddlewareArgs
 type SlackViewMiddlewareArgs = types.SlackViewMiddlewareArgs
 type SlackOptionsMiddlewareArgs = types.SlackOptionsMiddlewareArgs
 type SlackCustomFunctionMiddlewareArgs = types.SlackCustomFunctionMiddlewareArgs
+type ListenerArgs = types.ListenerArgs
+
+// API is the context-aware Slack Web API of listener args
+type API = types.API
+
+var NewAPI = types.NewAPI
+var ErrNoToken = types.ErrNoToken
 
 // Middleware options types
 type SlackEventMiddlewareArgsOptions = middleware.SlackEventMiddlewareArgsOptions
+type Subcommand = middleware.Subcommand
 
 // Constraint types
 type ActionConstraints = types.ActionConstraints
@@ -78,6 +197,36 @@ type ViewSubmission = types.ViewSubmission
 type ViewClosed = types.ViewClosed
 type ViewResponse = types.ViewResponse
 
+// Typed events are the events listeners added with EventTyped receive decoded
+type TypedEvent = types.TypedEvent
+type TypedEventArgs[T TypedEvent] = app.TypedEventArgs[T]
+type AppMentionEvent = types.AppMentionEvent
+type AppHomeOpenedEvent = types.AppHomeOpenedEvent
+type AppUninstalledEvent = types.AppUninstalledEvent
+type ReactionItem = types.ReactionItem
+type ReactionAddedEvent = types.ReactionAddedEvent
+type ReactionRemovedEvent = types.ReactionRemovedEvent
+type MemberJoinedChannelEvent = types.MemberJoinedChannelEvent
+type MemberLeftChannelEvent = types.MemberLeftChannelEvent
+type ChannelInfo = types.ChannelInfo
+type ChannelCreatedEvent = types.ChannelCreatedEvent
+type ChannelRenameEvent = types.ChannelRenameEvent
+type ChannelArchiveEvent = types.ChannelArchiveEvent
+type ChannelUnarchiveEvent = types.ChannelUnarchiveEvent
+type TeamJoinEvent = types.TeamJoinEvent
+type UserChangeEvent = types.UserChangeEvent
+type SharedLink = types.SharedLink
+type LinkSharedEvent = types.LinkSharedEvent
+type FileSharedEvent = types.FileSharedEvent
+type RevokedTokens = types.RevokedTokens
+type TokensRevokedEvent = types.TokensRevokedEvent
+
+// EventTyped adds a listener receiving the events of the type T decodes as T, e.g.
+// bolt.EventTyped(app, func(args bolt.TypedEventArgs[bolt.AppMentionEvent]) error { ... })
+func EventTyped[T TypedEvent](a *App, middleware ...Middleware[TypedEventArgs[T]]) *App {
+	return app.EventTyped(a, middleware...)
+}
+
 type OptionsRequest = types.OptionsRequest
 type OptionsResponse = types.OptionsResponse
 type Option = types.Option
@@ -89,12 +238,26 @@ type Receiver = types.Receiver
 type ReceiverEvent = types.ReceiverEvent
 type ReceiverEndpoints = types.ReceiverEndpoints
 type HTTPReceiverOptions = types.HTTPReceiverOptions
+type MultiAppReceiverOptions = types.MultiAppReceiverOptions
+type HostedAppOptions = types.HostedAppOptions
 type SocketModeReceiverOptions = types.SocketModeReceiverOptions
+type SocketModeHello = types.SocketModeHello
+type ReconnectBackoff = types.ReconnectBackoff
 type AwsLambdaReceiverOptions = types.AwsLambdaReceiverOptions
+type ReceiverAuthenticityErrorHandler = types.ReceiverAuthenticityErrorHandler
+type ReceiverAuthenticityErrorHandlerArgs = types.ReceiverAuthenticityErrorHandlerArgs
+type HTTPErrorStatusCodes = types.HTTPErrorStatusCodes
+type OverloadPolicy = types.OverloadPolicy
+type ReceiverQueueStats = types.ReceiverQueueStats
 
 // Receiver constructors
 var NewHTTPReceiver = receivers.NewHTTPReceiver
 var NewSocketModeReceiver = receivers.NewSocketModeReceiver
+var NewMultiAppReceiver = receivers.NewMultiAppReceiver
+
+// Socket Mode reconnect backoffs
+var ExponentialBackoff = receivers.ExponentialBackoff
+var ConstantBackoff = receivers.ConstantBackoff
 
 // Assistant types
 type Assistant = assistant.Assistant
@@ -144,6 +307,8 @@ var NewWorkflowStep = workflow.NewWorkflowStep
 // Custom function types
 type CustomFunction = functions.CustomFunction
 type CustomFunctionOptions = functions.CustomFunctionOptions
+type FunctionSchema = functions.Schema
+type FunctionParameter = functions.Parameter
 
 // Custom function constructors
 var NewCustomFunctionWithMiddleware = functions.NewCustomFunctionWithMiddleware
@@ -151,27 +316,51 @@ var NewCustomFunctionWithMiddleware = functions.NewCustomFunctionWithMiddleware
 // Error types
 type CodedError = errors.CodedError
 type ErrorCode = errors.ErrorCode
+type MultipleListenerError = errors.MultipleListenerError
+type MultipleNextCallError = errors.MultipleNextCallError
+type ListenerError = errors.ListenerError
+type RetryableError = errors.RetryableError
+type AuthorizationError = errors.AuthorizationError
+type AuthorizationSource = errors.AuthorizationSource
+type TeamConcurrencyLimitError = errors.TeamConcurrencyLimitError
+type ConcurrencyLimitError = errors.ConcurrencyLimitError
+type UserTokenError = errors.UserTokenError
+type FunctionParameterError = errors.FunctionParameterError
+type ContextMissingPropertyError = errors.ContextMissingPropertyError
 
 // Error constructors
 var NewAppInitializationError = errors.NewAppInitializationError
 var NewAssistantInitializationError = errors.NewAssistantInitializationError
 var NewAssistantMissingPropertyError = errors.NewAssistantMissingPropertyError
 var NewAuthorizationError = errors.NewAuthorizationError
+var NewAuthorizationErrorWithSource = errors.NewAuthorizationErrorWithSource
 var NewContextMissingPropertyError = errors.NewContextMissingPropertyError
 var NewInvalidCustomPropertyError = errors.NewInvalidCustomPropertyError
 var NewReceiverMultipleAckError = errors.NewReceiverMultipleAckError
 var NewReceiverAuthenticityError = errors.NewReceiverAuthenticityError
 var NewHTTPReceiverDeferredRequestError = errors.NewHTTPReceiverDeferredRequestError
 var NewMultipleListenerError = errors.NewMultipleListenerError
+var NewMultipleNextCallError = errors.NewMultipleNextCallError
+var NewListenerError = errors.NewListenerError
 var NewWorkflowStepInitializationError = errors.NewWorkflowStepInitializationError
+var NewTeamConcurrencyLimitError = errors.NewTeamConcurrencyLimitError
+var NewConcurrencyLimitError = errors.NewConcurrencyLimitError
+var NewUserTokenError = errors.NewUserTokenError
+var NewFunctionParameterError = errors.NewFunctionParameterError
 
 // Error utilities
 var IsCodedError = errors.IsCodedError
 var AsCodedError = errors.AsCodedError
+var NewRetryableError = errors.NewRetryableError
+var NewNonRetryableError = errors.NewNonRetryableError
+var IsRetryable = errors.IsRetryable
+var IsNonRetryable = errors.IsNonRetryable
+var ErrorLogAttrs = errors.LogAttrs
 
 // Helper types
 type IncomingEventType = helpers.IncomingEventType
 type EventTypeAndConversation = helpers.EventTypeAndConversation
+type SignatureVerifier = helpers.SignatureVerifier
 
 // Helper functions
 var GetTypeAndConversation = helpers.GetTypeAndConversation
@@ -181,7 +370,12 @@ var ExtractEventType = helpers.ExtractEventType
 var CreateSayFunction = helpers.CreateSayFunction
 var CreateRespondFunction = helpers.CreateRespondFunction
 var MatchesPattern = helpers.MatchesPattern
+var NormalizePattern = helpers.NormalizePattern
 var ExtractUserID = helpers.ExtractUserID
+var NewSignatureVerifier = helpers.NewSignatureVerifier
+var FileIDsFromEvent = helpers.FileIDsFromEvent
+var FetchFileInfos = helpers.FetchFileInfos
+var OpenFile = helpers.OpenFile
 
 // Middleware functions
 var OnlyActions = middleware.OnlyActions
@@ -200,8 +394,34 @@ var DirectMention = middleware.DirectMention
 var Subtype = middleware.Subtype
 var MatchCallbackId = middleware.MatchCallbackId
 var IsSlackEventMiddlewareArgsOptions = middleware.IsSlackEventMiddlewareArgsOptions
+var Subcommands = middleware.Subcommands
+
+// Listener adapts built-in middleware to a listener's middleware, e.g.
+// app.Message("", bolt.Listener[bolt.SlackEventMiddlewareArgs](bolt.Subtype("bot_message")), handler)
+func Listener[Args ListenerArgs](m Middleware[AllMiddlewareArgs]) Middleware[Args] {
+	return middleware.Listener[Args](m)
+}
 
 // Constants
+const (
+	PanicPolicyRecover = app.PanicPolicyRecover
+	PanicPolicyCrash   = app.PanicPolicyCrash
+	PanicPolicyHandler = app.PanicPolicyHandler
+)
+
+const (
+	OverflowPolicyBlock  = app.OverflowPolicyBlock
+	OverflowPolicyReject = app.OverflowPolicyReject
+	OverflowPolicyDrop   = app.OverflowPolicyDrop
+)
+
+const (
+	OverloadPolicyBlock          = types.OverloadPolicyBlock
+	OverloadPolicyShedNewest     = types.OverloadPolicyShedNewest
+	OverloadPolicyShedOldest     = types.OverloadPolicyShedOldest
+	OverloadPolicyShedByPriority = types.OverloadPolicyShedByPriority
+)
+
 const (
 	LogLevelDebug = types.LogLevelDebug
 	LogLevelInfo  = types.LogLevelInfo
@@ -231,10 +451,15 @@ const (
 	ReceiverAuthenticityErrorCode          = errors.ReceiverAuthenticityErrorCode
 	ReceiverInconsistentStateErrorCode     = errors.ReceiverInconsistentStateError
 	MultipleListenerErrorCode              = errors.MultipleListenerErrorCode
+	MultipleNextCallErrorCode              = errors.MultipleNextCallErrorCode
 	HTTPReceiverDeferredRequestErrorCode   = errors.HTTPReceiverDeferredRequestErrorCode
 	UnknownErrorCode                       = errors.UnknownErrorCode
 	WorkflowStepInitializationErrorCode    = errors.WorkflowStepInitializationErrorCode
 	CustomFunctionInitializationErrorCode  = errors.CustomFunctionInitializationErrorCode
 	CustomFunctionCompleteSuccessErrorCode = errors.CustomFunctionCompleteSuccessErrorCode
 	CustomFunctionCompleteFailErrorCode    = errors.CustomFunctionCompleteFailErrorCode
+	TeamConcurrencyLimitErrorCode          = errors.TeamConcurrencyLimitErrorCode
+	ConcurrencyLimitErrorCode              = errors.ConcurrencyLimitErrorCode
+	UserTokenErrorCode                     = errors.UserTokenErrorCode
+	FunctionParameterErrorCode             = errors.FunctionParameterErrorCode
 )
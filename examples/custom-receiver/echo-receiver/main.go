@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -11,6 +12,7 @@ import (
 	bolt "github.com/Asafrose/bolt-go"
 	"github.com/Asafrose/bolt-go/pkg/app"
 	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -97,11 +99,14 @@ func (r *EchoReceiver) setupRoutes() {
 
 	// Slack events endpoint
 	r.echo.POST("/slack/events", func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+
 		// Handle URL verification challenge
-		if challenge := c.QueryParam("challenge"); challenge != "" {
-			return c.JSON(http.StatusOK, map[string]string{
-				"challenge": challenge,
-			})
+		if response, ok := receivers.HandleURLVerification(body); ok {
+			return c.JSONBlob(http.StatusOK, response)
 		}
 
 		// In a real implementation, this would process Slack events
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -10,6 +11,7 @@ import (
 	bolt "github.com/Asafrose/bolt-go"
 	"github.com/Asafrose/bolt-go/pkg/app"
 	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/gin-gonic/gin"
 )
@@ -77,11 +79,21 @@ func (r *GinReceiver) setupRoutes() {
 
 	// Slack events endpoint
 	r.router.POST("/slack/events", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+
+		// Handle URL verification challenge
+		if response, ok := receivers.HandleURLVerification(body); ok {
+			c.Data(http.StatusOK, "application/json", response)
+			return
+		}
+
 		// In a real implementation, this would process Slack events
 		// For now, we'll just acknowledge
-		c.JSON(http.StatusOK, gin.H{
-			"challenge": c.Query("challenge"),
-		})
+		c.Status(http.StatusOK)
 	})
 }
 
@@ -14,6 +14,11 @@ import (
 	"github.com/slack-go/slack"
 )
 
+// MyEventPayload is the payload attached to messages posted by this app
+type MyEventPayload struct {
+	Key string `json:"key"`
+}
+
 func main() {
 	// Get required environment variables
 	token := os.Getenv("SLACK_BOT_TOKEN")
@@ -48,64 +53,62 @@ func main() {
 		text := "Message Metadata Posting"
 		_, err := args.Say(&types.SayArguments{
 			Text: text,
-			// Note: Message metadata would need to be added to SayArguments
-			// This is a simplified version - the actual implementation would need
-			// to support metadata in the SayArguments type
+			Metadata: types.MessageMetadata[MyEventPayload]{
+				EventType: "my_event",
+				EventPayload: MyEventPayload{
+					Key: "value",
+				},
+			},
 		})
 		return err
 	})
 
 	// Listen for message_metadata_posted event
 	boltApp.Event(types.EventTypeMessageMetadataPosted, func(args types.SlackEventMiddlewareArgs) error {
-		// Extract event data from generic event
-		if genericEvent, ok := args.Event.(*helpers.GenericSlackEvent); ok {
-			if messageTS, exists := genericEvent.RawData["message_ts"]; exists {
-				if metadata, exists := genericEvent.RawData["metadata"]; exists {
-					// Convert metadata to JSON string for display
-					metadataJSON, err := json.Marshal(metadata)
-					if err != nil {
-						return fmt.Errorf("failed to marshal metadata: %w", err)
-					}
-
-					// Create response blocks
-					blocks := []slack.Block{
-						&slack.SectionBlock{
-							Type: slack.MBTSection,
-							Text: &slack.TextBlockObject{
-								Type: slack.MarkdownType,
-								Text: "Message Metadata Posted",
-							},
-						},
-						&slack.ContextBlock{
-							Type: slack.MBTContext,
-							ContextElements: slack.ContextElements{
-								Elements: []slack.MixedElement{
-									&slack.TextBlockObject{
-										Type: slack.MarkdownType,
-										Text: string(metadataJSON),
-									},
-								},
-							},
-						},
-					}
+		event, err := helpers.ParseMessageMetadataEvent[MyEventPayload](args.Event)
+		if err != nil {
+			return fmt.Errorf("failed to parse metadata event: %w", err)
+		}
+		if event.Metadata == nil {
+			return nil
+		}
 
-					// Reply in thread
-					text := "Message Metadata Posted"
-					threadTS := ""
-					if ts, ok := messageTS.(string); ok {
-						threadTS = ts
-					}
+		// Convert metadata to JSON string for display
+		metadataJSON, err := json.Marshal(event.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
 
-					_, err = args.Say(&types.SayArguments{
-						Text:     text,
-						Blocks:   blocks,
-						ThreadTS: threadTS,
-					})
-					return err
-				}
-			}
+		// Create response blocks
+		blocks := []slack.Block{
+			&slack.SectionBlock{
+				Type: slack.MBTSection,
+				Text: &slack.TextBlockObject{
+					Type: slack.MarkdownType,
+					Text: "Message Metadata Posted",
+				},
+			},
+			&slack.ContextBlock{
+				Type: slack.MBTContext,
+				ContextElements: slack.ContextElements{
+					Elements: []slack.MixedElement{
+						&slack.TextBlockObject{
+							Type: slack.MarkdownType,
+							Text: string(metadataJSON),
+						},
+					},
+				},
+			},
 		}
-		return nil
+
+		// Reply in thread
+		_, err = args.Say(&types.SayArguments{
+			Channel:  event.ChannelID,
+			Text:     "Message Metadata Posted",
+			Blocks:   blocks,
+			ThreadTS: event.MessageTS,
+		})
+		return err
 	})
 
 	// Start your app
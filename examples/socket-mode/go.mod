@@ -6,6 +6,8 @@ replace github.com/Asafrose/bolt-go => ../..
 
 require github.com/Asafrose/bolt-go v0.0.0-20250911113723-50618c94346b
 
+require gopkg.in/yaml.v3 v3.0.1 // indirect
+
 require (
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/samber/lo v1.51.0
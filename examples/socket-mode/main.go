@@ -82,58 +82,45 @@ func main() {
 			return err
 		}
 
-		// Extract trigger_id from shortcut based on its type
-		var triggerIDStr string
-		switch shortcut := args.Shortcut.(type) {
-		case types.GlobalShortcut:
-			triggerIDStr = shortcut.TriggerID
-		case types.MessageShortcut:
-			triggerIDStr = shortcut.TriggerID
-		default:
-			return nil
-		}
-		if triggerIDStr != "" {
-			// Create modal blocks
-			blocks := []slack.Block{
-				&slack.SectionBlock{
-					Type: slack.MBTSection,
-					Text: &slack.TextBlockObject{
-						Type: slack.MarkdownType,
-						Text: "About the simplest modal you could conceive of :smile:\n\nMaybe <https://api.slack.com/reference/block-kit/interactive-components|*make the modal interactive*> or <https://api.slack.com/surfaces/modals/using#modifying|*learn more advanced modal use cases*>.",
-					},
+		// Create modal blocks
+		blocks := []slack.Block{
+			&slack.SectionBlock{
+				Type: slack.MBTSection,
+				Text: &slack.TextBlockObject{
+					Type: slack.MarkdownType,
+					Text: "About the simplest modal you could conceive of :smile:\n\nMaybe <https://api.slack.com/reference/block-kit/interactive-components|*make the modal interactive*> or <https://api.slack.com/surfaces/modals/using#modifying|*learn more advanced modal use cases*>.",
 				},
-				&slack.ContextBlock{
-					Type: slack.MBTContext,
-					ContextElements: slack.ContextElements{
-						Elements: []slack.MixedElement{
-							&slack.TextBlockObject{
-								Type: slack.MarkdownType,
-								Text: "Psssst this modal was designed using <https://api.slack.com/tools/block-kit-builder|*Block Kit Builder*>",
-							},
+			},
+			&slack.ContextBlock{
+				Type: slack.MBTContext,
+				ContextElements: slack.ContextElements{
+					Elements: []slack.MixedElement{
+						&slack.TextBlockObject{
+							Type: slack.MarkdownType,
+							Text: "Psssst this modal was designed using <https://api.slack.com/tools/block-kit-builder|*Block Kit Builder*>",
 						},
 					},
 				},
-			}
+			},
+		}
 
-			// Open modal
-			_, err := args.Client.OpenView(triggerIDStr, slack.ModalViewRequest{
-				Type: slack.VTModal,
-				Title: &slack.TextBlockObject{
-					Type: slack.PlainTextType,
-					Text: "My App",
-				},
-				Close: &slack.TextBlockObject{
-					Type: slack.PlainTextType,
-					Text: "Close",
-				},
-				Blocks: slack.Blocks{BlockSet: blocks},
-			})
-			if err != nil {
-				args.Logger.Error("Failed to open modal", "error", err)
-			}
-			return err
+		// Open modal with the shortcut's trigger_id
+		_, err := args.OpenView(slack.ModalViewRequest{
+			Type: slack.VTModal,
+			Title: &slack.TextBlockObject{
+				Type: slack.PlainTextType,
+				Text: "My App",
+			},
+			Close: &slack.TextBlockObject{
+				Type: slack.PlainTextType,
+				Text: "Close",
+			},
+			Blocks: slack.Blocks{BlockSet: blocks},
+		})
+		if err != nil {
+			args.Logger.Error("Failed to open modal", "error", err)
 		}
-		return nil
+		return err
 	})
 
 	// Subscribe to 'app_mention' event in your App config
@@ -0,0 +1,137 @@
+// Package admin calls the admin.* Web API methods of Enterprise Grid organizations that slack-go
+// does not cover. They need a user token of an org admin or owner, granted admin scopes when the
+// app was installed on the organization; in listeners, args.Admin() selects it:
+//
+//	client, err := args.Admin("admin.users:read")
+//	if err != nil {
+//		return err
+//	}
+//	for users, err := range client.UsersListIter(ctx, admin.UsersListParams{}) {
+//		...
+//	}
+//
+// Errors are the ones slack-go returns: slack.SlackErrorResponse when Slack answers ok=false,
+// *slack.RateLimitedError when rate limited and slack.StatusCodeError for other HTTP failures.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// Options configures a Client
+type Options struct {
+	// HTTPClient sends the requests (default http.DefaultClient)
+	HTTPClient *http.Client
+	// APIURL is the Web API URL methods are appended to (default slack.APIURL)
+	APIURL string
+}
+
+// Client calls admin.* methods with a token
+type Client struct {
+	token      string
+	httpClient *http.Client
+	apiURL     string
+}
+
+// New returns a Client calling methods with token
+func New(token string, options ...Options) *Client {
+	c := &Client{token: token, httpClient: http.DefaultClient, apiURL: slack.APIURL}
+	if len(options) > 0 {
+		if options[0].HTTPClient != nil {
+			c.httpClient = options[0].HTTPClient
+		}
+		if options[0].APIURL != "" {
+			c.apiURL = options[0].APIURL
+		}
+	}
+	return c
+}
+
+// response is the part of every Web API response describing its outcome
+type response struct {
+	OK               bool                   `json:"ok"`
+	Error            string                 `json:"error,omitempty"`
+	ResponseMetadata slack.ResponseMetadata `json:"response_metadata"`
+}
+
+func (r response) err() error {
+	if r.OK {
+		return nil
+	}
+	return slack.SlackErrorResponse{Err: r.Error, ResponseMetadata: r.ResponseMetadata}
+}
+
+// call posts values to method and decodes the response into result, which embeds response
+func (c *Client) call(ctx context.Context, method string, values url.Values, result interface{ err() error }) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+method, strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64)
+		if err != nil {
+			return err
+		}
+		return &slack.RateLimitedError{RetryAfter: time.Duration(retryAfter) * time.Second}
+	case resp.StatusCode != http.StatusOK:
+		return slack.StatusCodeError{Code: resp.StatusCode, Status: resp.Status}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("%s: decoding response: %w", method, err)
+	}
+	return result.err()
+}
+
+// setInt sets key to value unless it is 0
+func setInt(values url.Values, key string, value int) {
+	if value != 0 {
+		values.Set(key, strconv.Itoa(value))
+	}
+}
+
+// setString sets key to value unless it is empty
+func setString(values url.Values, key, value string) {
+	if value != "" {
+		values.Set(key, value)
+	}
+}
+
+// pages iterates over the pages of a cursor-paginated method, starting at cursor; list returns
+// a page and the cursor of the next one
+func pages[T any](cursor string, list func(cursor string) (T, string, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			page, next, err := list(cursor)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(page, nil) || next == "" {
+				return
+			}
+			cursor = next
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"iter"
+	"net/url"
+)
+
+// AppRequest is a request from a member to install an app, as listed by admin.apps.requests.list
+type AppRequest struct {
+	ID          string            `json:"id"`
+	App         AppRequestApp     `json:"app"`
+	User        AppRequestUser    `json:"user"`
+	Team        AppRequestTeam    `json:"team"`
+	Scopes      []AppRequestScope `json:"scopes"`
+	Message     string            `json:"message,omitempty"`
+	DateCreated int64             `json:"date_created"`
+}
+
+// AppRequestApp is the app an AppRequest asks to install
+type AppRequestApp struct {
+	ID                     string `json:"id"`
+	Name                   string `json:"name"`
+	Description            string `json:"description,omitempty"`
+	HelpURL                string `json:"help_url,omitempty"`
+	PrivacyPolicyURL       string `json:"privacy_policy_url,omitempty"`
+	AppHomepageURL         string `json:"app_homepage_url,omitempty"`
+	AppDirectoryURL        string `json:"app_directory_url,omitempty"`
+	IsAppDirectoryApproved bool   `json:"is_app_directory_approved"`
+	IsInternal             bool   `json:"is_internal"`
+	AdditionalInfo         string `json:"additional_info,omitempty"`
+}
+
+// AppRequestUser is the member who made an AppRequest
+type AppRequestUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// AppRequestTeam is the workspace an AppRequest asks to install the app on
+type AppRequestTeam struct {
+	ID     string `json:"id"`
+	Name   string `json:"name,omitempty"`
+	Domain string `json:"domain,omitempty"`
+}
+
+// AppRequestScope is a scope an AppRequest asks to grant
+type AppRequestScope struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsSensitive bool   `json:"is_sensitive"`
+	TokenType   string `json:"token_type,omitempty"`
+}
+
+// AppsRequestsListParams are the parameters of admin.apps.requests.list
+type AppsRequestsListParams struct {
+	// TeamID lists the requests of one workspace
+	TeamID string
+	// EnterpriseID lists the requests of the whole organization
+	EnterpriseID string
+	Cursor       string
+	// Limit is the page size, up to 1000 (default 100)
+	Limit int
+}
+
+func (p AppsRequestsListParams) values() url.Values {
+	values := url.Values{}
+	setString(values, "team_id", p.TeamID)
+	setString(values, "enterprise_id", p.EnterpriseID)
+	setString(values, "cursor", p.Cursor)
+	setInt(values, "limit", p.Limit)
+	return values
+}
+
+type appsRequestsListResponse struct {
+	response
+	AppRequests []AppRequest `json:"app_requests"`
+}
+
+// AppsRequestsList calls admin.apps.requests.list and returns a page of pending requests and
+// the cursor of the next one
+func (c *Client) AppsRequestsList(ctx context.Context, params AppsRequestsListParams) ([]AppRequest, string, error) {
+	var result appsRequestsListResponse
+	if err := c.call(ctx, "admin.apps.requests.list", params.values(), &result); err != nil {
+		return nil, "", err
+	}
+	return result.AppRequests, result.ResponseMetadata.Cursor, nil
+}
+
+// AppsRequestsListIter iterates over the pages of admin.apps.requests.list, starting at
+// params.Cursor
+func (c *Client) AppsRequestsListIter(ctx context.Context, params AppsRequestsListParams) iter.Seq2[[]AppRequest, error] {
+	return pages(params.Cursor, func(cursor string) ([]AppRequest, string, error) {
+		params.Cursor = cursor
+		return c.AppsRequestsList(ctx, params)
+	})
+}
+
+// AppDecision identifies the app approved or restricted by AppsApprove and AppsRestrict: a
+// pending RequestID, or an AppID, on a workspace or the whole organization
+type AppDecision struct {
+	RequestID    string
+	AppID        string
+	TeamID       string
+	EnterpriseID string
+}
+
+func (d AppDecision) values() url.Values {
+	values := url.Values{}
+	setString(values, "request_id", d.RequestID)
+	setString(values, "app_id", d.AppID)
+	setString(values, "team_id", d.TeamID)
+	setString(values, "enterprise_id", d.EnterpriseID)
+	return values
+}
+
+// AppsApprove calls admin.apps.approve, approving an app request or app
+func (c *Client) AppsApprove(ctx context.Context, decision AppDecision) error {
+	var result response
+	return c.call(ctx, "admin.apps.approve", decision.values(), &result)
+}
+
+// AppsRestrict calls admin.apps.restrict, denying an app request or restricting an app
+func (c *Client) AppsRestrict(ctx context.Context, decision AppDecision) error {
+	var result response
+	return c.call(ctx, "admin.apps.restrict", decision.values(), &result)
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strings"
+)
+
+// Conversation is a channel as found by admin.conversations.search
+type Conversation struct {
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	Purpose             string   `json:"purpose,omitempty"`
+	MemberCount         int      `json:"member_count"`
+	Created             int64    `json:"created"`
+	CreatorID           string   `json:"creator_id,omitempty"`
+	IsPrivate           bool     `json:"is_private"`
+	IsArchived          bool     `json:"is_archived"`
+	IsGeneral           bool     `json:"is_general"`
+	IsExtShared         bool     `json:"is_ext_shared"`
+	IsOrgShared         bool     `json:"is_org_shared"`
+	ContextTeamID       string   `json:"context_team_id,omitempty"`
+	ConnectedTeamIDs    []string `json:"connected_team_ids,omitempty"`
+	InternalTeamIDs     []string `json:"internal_team_ids,omitempty"`
+	LastActivityTS      int64    `json:"last_activity_ts,omitempty"`
+	ExternalUserCount   int      `json:"external_user_count,omitempty"`
+	ChannelEmailAddress string   `json:"channel_email_address,omitempty"`
+}
+
+// ConversationsSearchParams are the parameters of admin.conversations.search
+type ConversationsSearchParams struct {
+	// Query matches channel names
+	Query string
+	// TeamIDs limits the search to workspaces
+	TeamIDs []string
+	// ConnectedTeamIDs limits the search to channels shared with these workspaces
+	ConnectedTeamIDs []string
+	// SearchChannelTypes filters channels, e.g. "private", "archived", "exclude_archived",
+	// "multi_workspace", "org_wide", "external_shared_exclude", "external_shared"
+	SearchChannelTypes []string
+	// Sort is "relevant" (default), "name", "member_count" or "created"
+	Sort string
+	// SortDir is "asc" or "desc"
+	SortDir string
+	Cursor  string
+	// Limit is the page size, up to 20 (default 10)
+	Limit int
+}
+
+func (p ConversationsSearchParams) values() url.Values {
+	values := url.Values{}
+	setString(values, "query", p.Query)
+	setString(values, "team_ids", strings.Join(p.TeamIDs, ","))
+	setString(values, "connected_team_ids", strings.Join(p.ConnectedTeamIDs, ","))
+	setString(values, "search_channel_types", strings.Join(p.SearchChannelTypes, ","))
+	setString(values, "sort", p.Sort)
+	setString(values, "sort_dir", p.SortDir)
+	setString(values, "cursor", p.Cursor)
+	setInt(values, "limit", p.Limit)
+	return values
+}
+
+type conversationsSearchResponse struct {
+	response
+	Conversations []Conversation `json:"conversations"`
+	// NextCursor is where admin.conversations.search returns its cursor
+	NextCursor string `json:"next_cursor"`
+}
+
+// ConversationsSearch calls admin.conversations.search and returns a page of channels and the
+// cursor of the next one
+func (c *Client) ConversationsSearch(ctx context.Context, params ConversationsSearchParams) ([]Conversation, string, error) {
+	var result conversationsSearchResponse
+	if err := c.call(ctx, "admin.conversations.search", params.values(), &result); err != nil {
+		return nil, "", err
+	}
+	cursor := result.NextCursor
+	if cursor == "" {
+		cursor = result.ResponseMetadata.Cursor
+	}
+	return result.Conversations, cursor, nil
+}
+
+// ConversationsSearchIter iterates over the pages of admin.conversations.search, starting at
+// params.Cursor
+func (c *Client) ConversationsSearchIter(ctx context.Context, params ConversationsSearchParams) iter.Seq2[[]Conversation, error] {
+	return pages(params.Cursor, func(cursor string) ([]Conversation, string, error) {
+		params.Cursor = cursor
+		return c.ConversationsSearch(ctx, params)
+	})
+}
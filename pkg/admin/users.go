@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// User is a member of the organization as listed by admin.users.list
+type User struct {
+	ID                string   `json:"id"`
+	Email             string   `json:"email,omitempty"`
+	Username          string   `json:"username,omitempty"`
+	FullName          string   `json:"full_name,omitempty"`
+	IsActive          bool     `json:"is_active"`
+	IsAdmin           bool     `json:"is_admin"`
+	IsOwner           bool     `json:"is_owner"`
+	IsPrimaryOwner    bool     `json:"is_primary_owner"`
+	IsRestricted      bool     `json:"is_restricted"`
+	IsUltraRestricted bool     `json:"is_ultra_restricted"`
+	IsBot             bool     `json:"is_bot"`
+	Has2FA            bool     `json:"has_2fa"`
+	HasSSO            bool     `json:"has_sso"`
+	DateCreated       int64    `json:"date_created,omitempty"`
+	DeactivatedTS     int64    `json:"deactivated_ts,omitempty"`
+	ExpirationTS      int64    `json:"expiration_ts,omitempty"`
+	Workspaces        []string `json:"workspaces,omitempty"`
+}
+
+// UsersListParams are the parameters of admin.users.list
+type UsersListParams struct {
+	// TeamID lists the users of one workspace; empty lists the whole organization
+	TeamID string
+	// IsActive, when set, lists only active or only deactivated users
+	IsActive *bool
+	// IncludeDeactivatedUserWorkspaces lists the workspaces deactivated users belonged to
+	IncludeDeactivatedUserWorkspaces bool
+	Cursor                           string
+	// Limit is the page size, up to 1000 (default 100)
+	Limit int
+}
+
+func (p UsersListParams) values() url.Values {
+	values := url.Values{}
+	setString(values, "team_id", p.TeamID)
+	if p.IsActive != nil {
+		values.Set("is_active", strconv.FormatBool(*p.IsActive))
+	}
+	if p.IncludeDeactivatedUserWorkspaces {
+		values.Set("include_deactivated_user_workspaces", "true")
+	}
+	setString(values, "cursor", p.Cursor)
+	setInt(values, "limit", p.Limit)
+	return values
+}
+
+type usersListResponse struct {
+	response
+	Users []User `json:"users"`
+}
+
+// UsersList calls admin.users.list and returns a page of users and the cursor of the next one
+func (c *Client) UsersList(ctx context.Context, params UsersListParams) ([]User, string, error) {
+	var result usersListResponse
+	if err := c.call(ctx, "admin.users.list", params.values(), &result); err != nil {
+		return nil, "", err
+	}
+	return result.Users, result.ResponseMetadata.Cursor, nil
+}
+
+// UsersListIter iterates over the pages of admin.users.list, starting at params.Cursor
+func (c *Client) UsersListIter(ctx context.Context, params UsersListParams) iter.Seq2[[]User, error] {
+	return pages(params.Cursor, func(cursor string) ([]User, string, error) {
+		params.Cursor = cursor
+		return c.UsersList(ctx, params)
+	})
+}
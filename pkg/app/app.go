@@ -1,7 +1,7 @@
 package app
 
 import (
-	"bytes"
+	"container/list"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,16 +11,25 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"runtime"
+	"runtime/debug"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Asafrose/bolt-go/pkg/admin"
+	"github.com/Asafrose/bolt-go/pkg/assistant"
 	"github.com/Asafrose/bolt-go/pkg/conversation"
 	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/functions"
 	"github.com/Asafrose/bolt-go/pkg/helpers"
 	"github.com/Asafrose/bolt-go/pkg/middleware"
+	"github.com/Asafrose/bolt-go/pkg/oauth"
 	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/tunnel"
 	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/Asafrose/bolt-go/pkg/workflow"
 	"github.com/slack-go/slack"
 )
 
@@ -33,8 +42,16 @@ type AppOptions struct {
 	CustomRoutes          []types.CustomRoute      `json:"custom_routes,omitempty"`
 	ProcessBeforeResponse bool                     `json:"process_before_response"`
 	SignatureVerification bool                     `json:"signature_verification"`
-
-	// OAuth configuration
+	// AuthenticityErrorHandler is called when the default HTTP receiver rejects a request with an invalid signature
+	AuthenticityErrorHandler types.ReceiverAuthenticityErrorHandler `json:"-"`
+	// ErrorStatusCodes overrides the status codes the default HTTP receiver returns for failed requests
+	ErrorStatusCodes *types.HTTPErrorStatusCodes `json:"error_status_codes,omitempty"`
+	// SignatureTolerance is how far request timestamps may be from the current time for the
+	// default HTTP receiver to accept their signatures (default 5 minutes)
+	SignatureTolerance time.Duration `json:"signature_tolerance,omitempty"`
+
+	// OAuth configuration: when ClientID and ClientSecret are set, the default receiver serves the
+	// install and redirect paths, asking for Scopes and storing installations in InstallationStore
 	ClientID     string   `json:"client_id,omitempty"`
 	ClientSecret string   `json:"client_secret,omitempty"`
 	StateSecret  string   `json:"state_secret,omitempty"`
@@ -42,6 +59,8 @@ type AppOptions struct {
 	Scopes       []string `json:"scopes,omitempty"`
 
 	// Client configuration
+	// HTTPClient is used for response_url posts made by respond() and for SendWebhook; defaults to
+	// a connection-pooled client configured by HTTPClientOptions
 	HTTPClient    *http.Client   `json:"-"`
 	ClientOptions []slack.Option `json:"-"`
 	Token         string         `json:"token,omitempty"`
@@ -49,14 +68,32 @@ type AppOptions struct {
 	BotID         string         `json:"bot_id,omitempty"`
 	BotUserID     string         `json:"bot_user_id,omitempty"`
 
+	// HTTPClientOptions configures the default HTTP client (timeout, proxy, connection pooling)
+	HTTPClientOptions HTTPClientOptions `json:"-"`
+
+	// ResponseURLHosts lists the hosts respond() and SendWebhook may post to, for enterprise
+	// proxies or regional endpoints; entries are host names, host:port pairs or "*.example.com"
+	// wildcards (default DefaultResponseURLHosts). Loopback hosts are always allowed.
+	ResponseURLHosts []string `json:"response_url_hosts,omitempty"`
+
+	// ClientPoolOptions bounds the pool of per-token Slack clients; unlimited by default
+	ClientPoolOptions WebClientPoolOptions `json:"client_pool_options"`
+
+	// Credentials reads the signing secret, token and client secret from a SecretProvider and
+	// rotates them while the app runs; see also App.UpdateCredentials
+	Credentials CredentialsOptions `json:"-"`
+
 	// Authorization
 	Authorize AuthorizeFunc `json:"-"`
+	// InstallationStore authorizes events with the installations completed through OAuth when
+	// neither Token nor Authorize is set; see AuthorizeWithInstallationStore
+	InstallationStore oauth.InstallationStore `json:"-"`
 
 	// Receiver
 	Receiver types.Receiver `json:"-"`
 
 	// Logging
-	Logger   *slog.Logger    `json:"-"`
+	Logger   types.Logger    `json:"-"`
 	LogLevel *types.LogLevel `json:"log_level,omitempty"`
 
 	// Behavior
@@ -66,7 +103,75 @@ type AppOptions struct {
 	TokenVerificationEnabled bool  `json:"token_verification_enabled"`
 	DeferInitialization      bool  `json:"defer_initialization"`
 	ExtendedErrorHandler     bool  `json:"extended_error_handler"`
-	AttachFunctionToken      bool  `json:"attach_function_token"`
+	// AttachFunctionToken makes the clients of function listeners use the bot token of the
+	// function execution, which Slack sends with function_executed events
+	AttachFunctionToken bool `json:"attach_function_token"`
+	// ReuseEventContexts recycles the Context of each event, including its Custom map, once
+	// ProcessEvent returns, reducing allocations for high-throughput apps. Listeners must not
+	// retain the Context, its Custom map or the say function after they return.
+	ReuseEventContexts bool `json:"reuse_event_contexts"`
+	// ListenerConcurrency is the maximum number of listeners matching one event that run
+	// concurrently; each gets its own copy of the Context. 0 or 1 runs them one at a time in
+	// registration order.
+	ListenerConcurrency int `json:"listener_concurrency"`
+	// LazyListenerRunner starts the lazy functions of listeners added with CommandLazy and the
+	// other Lazy methods once the request was acknowledged; by default they run in goroutines
+	LazyListenerRunner LazyListenerRunner `json:"-"`
+	// AuthTestTTL is how long auth.test results, used for token verification and bot identity
+	// lookups, are cached per token (default 10 minutes)
+	AuthTestTTL time.Duration `json:"auth_test_ttl"`
+
+	// Panic handling
+	// PanicPolicy controls what happens when a listener or middleware panics (default PanicPolicyRecover)
+	PanicPolicy PanicPolicy `json:"panic_policy"`
+	// PanicHandler is called for recovered panics when PanicPolicy is PanicPolicyHandler
+	PanicHandler PanicHandler `json:"-"`
+
+	// MaxConcurrency is the number of events processed at once across all teams; further events
+	// wait in the queue configured by ConcurrencyQueue. 0 processes every event as it arrives.
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+	// ConcurrencyQueue configures the events waiting for MaxConcurrency
+	ConcurrencyQueue ConcurrencyQueueOptions `json:"concurrency_queue"`
+	// TeamConcurrency caps the events of one team processed at once; unlimited by default
+	TeamConcurrency TeamConcurrencyOptions `json:"team_concurrency"`
+
+	// RateLimit queues and retries Slack API calls of the app's clients, including args.Client,
+	// according to Slack's rate limits; off by default
+	RateLimit RateLimitOptions `json:"-"`
+
+	// Metrics records processing latency and listener errors for Metrics, MetricsHandler and expvar
+	Metrics MetricsOptions `json:"metrics"`
+
+	// EventRecorder persists incoming events for replaying them with pkg/replay; it requires DeveloperMode
+	EventRecorder EventRecorder `json:"-"`
+
+	// Tunnel exposes the HTTP receiver on a public URL when the app starts, such as with
+	// tunnel.Ngrok; it requires DeveloperMode
+	Tunnel tunnel.Provider `json:"-"`
+
+	// Audit sends a summary of every processed event to a sink, such as a file or webhook
+	Audit AuditOptions `json:"-"`
+
+	// Deduper drops events already processed, such as Slack retries of an event that was slow to
+	// be acknowledged or Socket Mode deliveries to several replicas, before listeners run. Use
+	// NewMemoryEventDeduper for a single process or pkg/dedupe for Redis; nil disables it.
+	Deduper EventDeduper `json:"-"`
+
+	// Retries skips deliveries Slack retried, such as after a slow acknowledgement; all are
+	// processed by default
+	Retries RetryOptions `json:"-"`
+
+	// FanOut forwards every verified request, once acknowledged, to a message queue for
+	// out-of-process workers, such as Kafka, NATS or SQS
+	FanOut FanOutOptions `json:"-"`
+
+	// Instrumentation traces and measures receivers, event processing, authorization and Slack
+	// API calls; see pkg/observability/otel for OpenTelemetry
+	Instrumentation Instrumentation `json:"-"`
+
+	// ErrorReporter receives the errors reaching the default error handler, to alert on failed
+	// listeners; see pkg/errorreport for Sentry
+	ErrorReporter ErrorReporter `json:"-"`
 
 	// Conversation store
 	ConvoStore conversation.ConversationStore `json:"convo_store,omitempty"`
@@ -85,12 +190,23 @@ type AuthorizeSourceData struct {
 type AuthorizeResult struct {
 	BotToken     string                 `json:"bot_token,omitempty"`
 	UserToken    string                 `json:"user_token,omitempty"`
+	UserScopes   []string               `json:"user_scopes,omitempty"` // granted to UserToken, checked by args.UserClient
 	BotID        string                 `json:"bot_id,omitempty"`
 	BotUserID    string                 `json:"bot_user_id,omitempty"`
 	UserID       string                 `json:"user_id,omitempty"`
 	TeamID       string                 `json:"team_id,omitempty"`
 	EnterpriseID string                 `json:"enterprise_id,omitempty"`
 	Custom       map[string]interface{} `json:"custom,omitempty"`
+	// IsEnterpriseInstall reports the tokens are of an org-wide installation, for
+	// Context.IsEnterpriseInstall when the payload does not say so
+	IsEnterpriseInstall bool `json:"is_enterprise_install,omitempty"`
+	// APIURL is the Web API URL of the installation, e.g. "https://slack-gov.com/api/" for
+	// GovSlack, used by the clients of BotToken and UserToken instead of the app's
+	APIURL string `json:"api_url,omitempty"`
+	// ClientOptions are applied after AppOptions.ClientOptions when creating the clients of
+	// BotToken and UserToken. Clients are pooled by token and APIURL, so changing the options
+	// returned for a token has no effect once its client was created.
+	ClientOptions []slack.Option `json:"-"`
 }
 
 // AuthorizeFunc represents an authorization function
@@ -99,8 +215,26 @@ type AuthorizeFunc func(ctx context.Context, source AuthorizeSourceData, body in
 // ErrorHandler represents an error handler function
 type ErrorHandler func(err error) error
 
-// ExtendedErrorHandler represents an extended error handler function
-type ExtendedErrorHandler func(ctx context.Context, err error, logger *slog.Logger, body interface{}, context *types.Context) error
+// ExtendedErrorHandler represents an extended error handler function.
+// event is the raw receiver event (body, headers, retry info) that produced the error,
+// e.g. for storing the offending payload in a dead-letter queue for replay.
+type ExtendedErrorHandler func(ctx context.Context, err error, logger types.Logger, body interface{}, context *types.Context, event types.ReceiverEvent) error
+
+// PanicPolicy controls how the app handles panics raised while processing listeners
+type PanicPolicy int
+
+const (
+	// PanicPolicyRecover converts the panic into a listener error
+	PanicPolicyRecover PanicPolicy = iota
+	// PanicPolicyCrash lets the panic propagate so the process dies and can be restarted
+	PanicPolicyCrash
+	// PanicPolicyHandler recovers the panic and passes it to the configured PanicHandler
+	PanicPolicyHandler
+)
+
+// PanicHandler is called with the recovered value and the stack of the panicking goroutine.
+// The returned error is reported as the listener's error; return nil to swallow the panic.
+type PanicHandler func(recovered interface{}, stack []byte) error
 
 // listenerConstraints holds the matching constraints for a listener
 type listenerConstraints struct {
@@ -126,68 +260,269 @@ type listenerEntry struct {
 	eventType   helpers.IncomingEventType
 	constraints listenerConstraints
 	middleware  []types.Middleware[types.AllMiddlewareArgs]
+	// source is the file:line of the call that registered the listener
+	source string
+	// lazy is set for listeners with lazy functions, see CommandLazy
+	lazy bool
 }
 
-// WebClientPool manages a pool of Slack clients
+// String identifies the listener by its event type and constraints, e.g. "action(action_id=approve)"
+func (l *listenerEntry) String() string {
+	c := l.constraints
+	var parts []string
+	addConstraint := func(name, value string) {
+		if value != "" {
+			parts = append(parts, name+"="+value)
+		}
+	}
+	addPattern := func(name string, pattern *regexp.Regexp) {
+		if pattern != nil {
+			parts = append(parts, name+"=/"+pattern.String()+"/")
+		}
+	}
+
+	addConstraint("type", c.eventType)
+	addPattern("type", c.eventTypePattern)
+	if c.messagePattern != nil {
+		parts = append(parts, fmt.Sprintf("pattern=%v", c.messagePattern))
+	}
+	addConstraint("action_type", c.actionType)
+	addConstraint("action_id", c.actionID)
+	addPattern("action_id", c.actionIDPattern)
+	addConstraint("block_id", c.blockID)
+	addPattern("block_id", c.blockIDPattern)
+	addConstraint("callback_id", c.callbackID)
+	addPattern("callback_id", c.callbackIDPattern)
+	addConstraint("command", c.command)
+	addPattern("command", c.commandPattern)
+	addConstraint("shortcut_type", c.shortcutType)
+	addConstraint("view_type", c.viewType)
+
+	return fmt.Sprintf("%s(%s)", l.eventType, strings.Join(parts, ", "))
+}
+
+// addListener records where the listener was registered and adds it to the app.
+// Callers must hold a.mu.
+func (a *App) addListener(listener *listenerEntry) {
+	listener.source = registrationSite()
+	a.listenerIndex.add(listener, len(a.listenerEntries))
+	a.listenerEntries = append(a.listenerEntries, listener)
+}
+
+// registrationSite returns the file:line of the first caller outside this package
+func registrationSite() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, appPackagePath+".") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// appPackagePath is the import path of this package, used to skip internal frames
+const appPackagePath = "github.com/Asafrose/bolt-go/pkg/app"
+
+// matchedListener is a listener selected for an incoming event
+type matchedListener struct {
+	entry *listenerEntry
+	// index is the registration index of the listener, or -1 for legacy and placeholder listeners
+	index  int
+	name   string
+	source string
+	// matches holds the submatches of the listener's RegExp message pattern, if any
+	matches []string
+}
+
+// WebClientPool manages a pool of Slack clients keyed by token
 type WebClientPool struct {
-	mu      sync.RWMutex
-	clients map[string]*slack.Client
+	mu          sync.Mutex
+	maxSize     int
+	idleTimeout time.Duration
+	clients     map[string]*list.Element
+	// lru orders pooled clients from most to least recently used
+	lru   *list.List
+	stats WebClientPoolStats
+}
+
+// WebClientPoolOptions bounds the memory used by a WebClientPool
+type WebClientPoolOptions struct {
+	// MaxSize is the maximum number of pooled clients; the least recently used client is evicted
+	// when it is exceeded. 0 means unlimited.
+	MaxSize int `json:"max_size,omitempty"`
+	// IdleTimeout expires clients that have not been used for this long. 0 means clients never expire.
+	IdleTimeout time.Duration `json:"idle_timeout,omitempty"`
+}
+
+// WebClientPoolStats is a snapshot of a WebClientPool's size and activity
+type WebClientPoolStats struct {
+	Size        int   `json:"size"`
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`
+	Evictions   int64 `json:"evictions"`
+	Expirations int64 `json:"expirations"`
+}
+
+// pooledClient is a client held by a WebClientPool
+type pooledClient struct {
+	key      string
+	client   *slack.Client
+	lastUsed time.Time
 }
 
-// NewWebClientPool creates a new WebClientPool
+// NewWebClientPool creates a new WebClientPool without size or idle limits
 func NewWebClientPool() *WebClientPool {
+	return NewWebClientPoolWithOptions(WebClientPoolOptions{})
+}
+
+// NewWebClientPoolWithOptions creates a new WebClientPool bounded by options
+func NewWebClientPoolWithOptions(options WebClientPoolOptions) *WebClientPool {
 	return &WebClientPool{
-		clients: make(map[string]*slack.Client),
+		maxSize:     options.MaxSize,
+		idleTimeout: options.IdleTimeout,
+		clients:     make(map[string]*list.Element),
+		lru:         list.New(),
 	}
 }
 
 // GetOrCreate gets or creates a client for the given token
 func (p *WebClientPool) GetOrCreate(token string, options ...slack.Option) *slack.Client {
-	p.mu.RLock()
-	client, exists := p.clients[token]
-	p.mu.RUnlock()
+	return p.GetOrCreateForAPIURL(token, "", options...)
+}
+
+// GetOrCreateForAPIURL gets or creates a client for the given token calling the Web API at
+// apiURL, or at the URL options set when apiURL is empty. Clients are pooled by token and API
+// URL, so options only apply when the client is created.
+func (p *WebClientPool) GetOrCreateForAPIURL(token, apiURL string, options ...slack.Option) *slack.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.expireIdle(now)
+
+	key := token
+	if apiURL != "" {
+		key = token + "@" + apiURL
+	}
+	if element, exists := p.clients[key]; exists {
+		entry := element.Value.(*pooledClient)
+		entry.lastUsed = now
+		p.lru.MoveToFront(element)
+		p.stats.Hits++
+		return entry.client
+	}
 
-	if exists {
-		return client
+	p.stats.Misses++
+	if apiURL != "" {
+		options = append(slices.Clip(options), slack.OptionAPIURL(apiURL))
 	}
+	client := slack.New(token, options...)
+	p.clients[key] = p.lru.PushFront(&pooledClient{key: key, client: client, lastUsed: now})
 
+	for p.maxSize > 0 && p.lru.Len() > p.maxSize {
+		p.remove(p.lru.Back())
+		p.stats.Evictions++
+	}
+	return client
+}
+
+// Stats returns a snapshot of the pool's size and activity
+func (p *WebClientPool) Stats() WebClientPoolStats {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Double-check after acquiring write lock
-	if client, exists := p.clients[token]; exists {
-		return client
+	p.expireIdle(time.Now())
+	stats := p.stats
+	stats.Size = p.lru.Len()
+	return stats
+}
+
+// expireIdle removes clients unused for longer than the idle timeout. Callers must hold p.mu.
+func (p *WebClientPool) expireIdle(now time.Time) {
+	if p.idleTimeout <= 0 {
+		return
+	}
+	for element := p.lru.Back(); element != nil; element = p.lru.Back() {
+		if now.Sub(element.Value.(*pooledClient).lastUsed) < p.idleTimeout {
+			return
+		}
+		p.remove(element)
+		p.stats.Expirations++
 	}
+}
 
-	client = slack.New(token, options...)
-	p.clients[token] = client
-	return client
+// remove drops element from the pool. Callers must hold p.mu.
+func (p *WebClientPool) remove(element *list.Element) {
+	p.lru.Remove(element)
+	delete(p.clients, element.Value.(*pooledClient).key)
 }
 
 // App represents a Slack app
 type App struct {
 	// Public fields
 	Client *slack.Client
-	Logger *slog.Logger
+	Logger types.Logger
 
 	// Private fields
 	clientOptions            []slack.Option
-	clients                  map[string]*WebClientPool
+	userClientOptions        []slack.Option
+	apiHTTPClient            *http.Client
+	httpClient               *http.Client
+	adminHTTPClient          *http.Client // sends admin.* calls, which slack-go does not cover
+	responseURLValidator     *helpers.ResponseURLValidator
+	clientPool               *WebClientPool
 	receiver                 types.Receiver
 	logLevel                 types.LogLevel
 	authorize                AuthorizeFunc
 	middleware               []types.Middleware[types.AllMiddlewareArgs]
+	hasUserMiddleware        bool
 	listeners                [][]types.Middleware[types.AllMiddlewareArgs] // Deprecated
 	listenerEntries          []*listenerEntry
+	listenerIndex            *listenerIndex
 	errorHandler             interface{} // ErrorHandler or ExtendedErrorHandler
 	socketMode               bool
 	developerMode            bool
+	scopes                   []string
+	redirectURI              string
+	eventRecorder            EventRecorder
+	tunnelProvider           tunnel.Provider
+	tunnel                   tunnel.Tunnel
 	extendedErrorHandler     bool
 	hasCustomErrorHandler    bool
 	tokenVerificationEnabled bool
 	initialized              bool
 	attachFunctionToken      bool
+	reuseEventContexts       bool
+	listenerConcurrency      int
+	lazyListenerRunner       LazyListenerRunner
+	teamLimiter              *teamLimiter
+	concurrencyLimiter       *concurrencyLimiter
+	rateLimiter              *rateLimiter
+	inFlight                 inFlightEvents
+	metrics                  *processingMetrics
+	auditor                  *auditor
+	fanOut                   *fanOut
+	deduper                  EventDeduper
+	retries                  RetryOptions
+	instrumentation          Instrumentation
+	errorReporter            ErrorReporter
+	tracing                  tracingHooks
+	authTestCache            *authTestCache
+	panicPolicy              PanicPolicy
+	panicHandler             PanicHandler
 	conversationStore        conversation.ConversationStore
+	secretProvider           SecretProvider
+	signingSecretWindow      time.Duration
+	credentialUpdater        types.CredentialUpdater
+	credentialRefresher      *credentialRefresher
+	credentialsMu            sync.RWMutex
+	credentials              Credentials
+	identity                 *BotIdentity // of credentials.Token, once verified
 
 	// Used when defer initialization is true
 	argToken         *string
@@ -199,24 +534,79 @@ type App struct {
 
 // New creates a new Slack App
 func New(options AppOptions) (*App, error) {
+	// Read the credentials missing from the options from the secret provider
+	if provider := options.Credentials.Provider; provider != nil {
+		credentials, err := provider.Credentials(context.Background())
+		if err != nil {
+			return nil, bolterrors.NewAppInitializationError(fmt.Sprintf("reading credentials failed: %v", err))
+		}
+		options = options.withCredentials(credentials)
+	}
+
 	// Validate conflicting options
 	if options.Token != "" && options.Authorize != nil {
 		return nil, errors.New("cannot specify both token and authorize callback")
 	}
+	if options.InstallationStore != nil && (options.Token != "" || options.Authorize != nil) {
+		return nil, errors.New("cannot specify installation store with token or authorize callback")
+	}
+	if options.InstallationStore != nil {
+		options.Authorize = AuthorizeWithInstallationStore(options.InstallationStore)
+	}
 
 	if options.SocketMode && options.Receiver != nil {
 		return nil, errors.New("cannot specify both socketMode and custom receiver")
 	}
 
+	if err := validateTokens(options); err != nil {
+		return nil, err
+	}
+
+	// Recordings hold raw payloads, including user content, so they are limited to development
+	if options.EventRecorder != nil && !options.DeveloperMode {
+		return nil, bolterrors.NewAppInitializationError("event recorder requires developer mode")
+	}
+
+	// Tunnels make a local app reachable from the internet, so they are limited to development
+	if options.Tunnel != nil && !options.DeveloperMode {
+		return nil, bolterrors.NewAppInitializationError("tunnel requires developer mode")
+	}
+
+	if options.PanicPolicy == PanicPolicyHandler && options.PanicHandler == nil {
+		return nil, bolterrors.NewAppInitializationError("panic handler required when panic policy is PanicPolicyHandler")
+	}
+
 	app := &App{
 		middleware:               make([]types.Middleware[types.AllMiddlewareArgs], 0),
 		listeners:                make([][]types.Middleware[types.AllMiddlewareArgs], 0),
-		clients:                  make(map[string]*WebClientPool),
+		clientPool:               NewWebClientPoolWithOptions(options.ClientPoolOptions),
+		listenerIndex:            newListenerIndex(),
 		developerMode:            options.DeveloperMode,
+		scopes:                   options.Scopes,
+		redirectURI:              options.RedirectURI,
+		eventRecorder:            options.EventRecorder,
+		tunnelProvider:           options.Tunnel,
 		socketMode:               options.SocketMode,
 		tokenVerificationEnabled: options.TokenVerificationEnabled,
 		extendedErrorHandler:     options.ExtendedErrorHandler,
 		attachFunctionToken:      options.AttachFunctionToken,
+		reuseEventContexts:       options.ReuseEventContexts,
+		listenerConcurrency:      options.ListenerConcurrency,
+		lazyListenerRunner:       options.LazyListenerRunner,
+		teamLimiter:              newTeamLimiter(options.TeamConcurrency),
+		concurrencyLimiter:       newConcurrencyLimiter(options.MaxConcurrency, options.ConcurrencyQueue),
+		deduper:                  options.Deduper,
+		retries:                  options.Retries,
+		panicPolicy:              options.PanicPolicy,
+		panicHandler:             options.PanicHandler,
+		secretProvider:           options.Credentials.Provider,
+		signingSecretWindow:      options.Credentials.SigningSecretWindow,
+		errorReporter:            options.ErrorReporter,
+		credentials: Credentials{
+			SigningSecret: options.SigningSecret,
+			Token:         options.Token,
+			ClientSecret:  options.ClientSecret,
+		},
 	}
 
 	// Set up logging
@@ -242,10 +632,44 @@ func New(options AppOptions) (*App, error) {
 		app.Logger = slog.New(handler)
 	}
 
-	// Set up client options
+	// Set up client options; options passed by the caller take precedence over instrumentation
+	app.instrumentation = options.Instrumentation
 	app.clientOptions = []slack.Option{}
+	app.apiHTTPClient = http.DefaultClient
+	if app.instrumentation != nil {
+		app.apiHTTPClient = app.instrumentation.WrapHTTPClient(nil)
+		app.clientOptions = append(app.clientOptions, slack.OptionHTTPClient(app.apiHTTPClient))
+	}
+	app.rateLimiter = newRateLimiter(options.RateLimit, app.Logger)
+	if app.rateLimiter != nil {
+		app.apiHTTPClient = app.rateLimiter.wrap(app.apiHTTPClient)
+		app.clientOptions = append(app.clientOptions, slack.OptionHTTPClient(app.apiHTTPClient))
+	}
 	if options.ClientOptions != nil {
 		app.clientOptions = append(app.clientOptions, options.ClientOptions...)
+		app.userClientOptions = options.ClientOptions
+	}
+
+	// Share auth.test results across token verification and bot identity lookups
+	app.authTestCache = newAuthTestCache(options.AuthTestTTL, app.getOrCreateClient)
+
+	// Set up the HTTP client shared by response_url and webhook posts
+	if options.HTTPClient != nil {
+		app.httpClient = options.HTTPClient
+	} else {
+		app.httpClient = newHTTPClient(options.HTTPClientOptions)
+	}
+	if app.instrumentation != nil {
+		app.httpClient = app.instrumentation.WrapHTTPClient(app.httpClient)
+	}
+	responseURLHosts := options.ResponseURLHosts
+	if len(responseURLHosts) == 0 {
+		responseURLHosts = DefaultResponseURLHosts
+	}
+	app.responseURLValidator = helpers.NewResponseURLValidator(responseURLHosts)
+	app.adminHTTPClient = app.httpClient
+	if app.rateLimiter != nil {
+		app.adminHTTPClient = app.rateLimiter.wrap(app.adminHTTPClient)
 	}
 
 	// Create the main client
@@ -256,7 +680,7 @@ func New(options AppOptions) (*App, error) {
 	}
 
 	// Set up error handler
-	app.errorHandler = app.defaultErrorHandler
+	app.errorHandler = ExtendedErrorHandler(app.defaultErrorHandler)
 	app.hasCustomErrorHandler = false
 
 	// Set up receiver
@@ -270,6 +694,10 @@ func New(options AppOptions) (*App, error) {
 		}
 		app.receiver = receiver
 	}
+	app.credentialUpdater, _ = app.receiver.(types.CredentialUpdater)
+	if app.instrumentation != nil {
+		app.receiver = app.instrumentation.WrapReceiver(app.receiver)
+	}
 
 	// Set up authorization
 	if options.DeferInitialization {
@@ -313,7 +741,7 @@ func New(options AppOptions) (*App, error) {
 	}
 
 	if ignoreSelfEnabled {
-		app.Use(middleware.IgnoreSelf())
+		app.useBuiltin(middleware.IgnoreSelf())
 	}
 
 	// Initialize conversation store if not provided
@@ -326,7 +754,7 @@ func New(options AppOptions) (*App, error) {
 
 	// Add conversation middleware to provide conversation context
 	if app.conversationStore != nil {
-		app.Use(conversation.ConversationContext(app.conversationStore))
+		app.useBuiltin(conversation.ConversationContext(app.conversationStore))
 	}
 
 	// Initialize receiver
@@ -334,6 +762,18 @@ func New(options AppOptions) (*App, error) {
 		return nil, err
 	}
 
+	// Set up metrics last so a failed New does not leave an expvar behind
+	metrics, err := newProcessingMetrics(options.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	app.metrics = metrics
+
+	// Start background goroutines only once New can no longer fail, so none are left running
+	app.auditor = newAuditor(options.Audit, app.Logger)
+	app.fanOut = newFanOut(options.FanOut, app.Logger)
+	app.credentialRefresher = app.startCredentialRefresh(options.Credentials.RefreshInterval)
+
 	return app, nil
 }
 
@@ -351,6 +791,11 @@ func (a *App) Init(ctx context.Context) error {
 		return err
 	}
 
+	// Verify the token up front so an invalid token fails initialization
+	if err := a.verifyBotToken(ctx); err != nil {
+		return err
+	}
+
 	a.authorize = authorize
 	a.initialized = true
 	return nil
@@ -362,10 +807,23 @@ func (a *App) Use(middleware types.Middleware[types.AllMiddlewareArgs]) *App {
 	defer a.mu.Unlock()
 
 	a.middleware = append(a.middleware, middleware)
+	a.hasUserMiddleware = true
 	return a
 }
 
-// Event registers event listeners
+// useBuiltin registers built-in global middleware, which only prepares or filters events for
+// listeners and therefore does not need to run for events no listener handles
+func (a *App) useBuiltin(middleware types.Middleware[types.AllMiddlewareArgs]) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.middleware = append(a.middleware, middleware)
+}
+
+// Event registers event listeners. The listener only runs for events of eventType, as if
+// OnlyEvents and MatchEventType came first in middleware, so its middleware and handler only see
+// matching events. middleware run in order, each continuing the chain by calling Next (see
+// types.NextFn); built-in middleware can be added with middleware.Listener.
 func (a *App) Event(eventType types.SlackEventType, middleware ...types.Middleware[types.SlackEventMiddlewareArgs]) *App {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -386,7 +844,7 @@ func (a *App) Event(eventType types.SlackEventType, middleware ...types.Middlewa
 		listener.middleware = append(listener.middleware, a.wrapEventMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -409,11 +867,14 @@ func (a *App) EventPattern(pattern *regexp.Regexp, middleware ...types.Middlewar
 		listener.middleware = append(listener.middleware, a.wrapEventMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
-// Message registers message listeners
+// Message registers message listeners. The listener only runs for message events whose text
+// matches pattern, as if OnlyEvents, MatchEventType("message") and MatchMessage(pattern) came
+// first in middleware, with the submatches of a RegExp pattern in Context.Matches. middleware
+// run in order like those of Event.
 func (a *App) Message(pattern interface{}, middleware ...types.Middleware[types.SlackEventMiddlewareArgs]) *App {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -423,7 +884,7 @@ func (a *App) Message(pattern interface{}, middleware ...types.Middleware[types.
 		eventType: helpers.IncomingEventTypeEvent,
 		constraints: listenerConstraints{
 			eventType:      "message",
-			messagePattern: pattern,
+			messagePattern: helpers.NormalizePattern(pattern),
 		},
 		middleware: make([]types.Middleware[types.AllMiddlewareArgs], 0),
 	}
@@ -433,11 +894,13 @@ func (a *App) Message(pattern interface{}, middleware ...types.Middleware[types.
 		listener.middleware = append(listener.middleware, a.wrapEventMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
-// Action registers action listeners
+// Action registers action listeners. The listener only runs for actions matching constraints, as
+// if OnlyActions and MatchConstraints(constraints) came first in middleware. middleware run in
+// order like those of Event.
 func (a *App) Action(constraints types.ActionConstraints, middleware ...types.Middleware[types.SlackActionMiddlewareArgs]) *App {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -462,7 +925,7 @@ func (a *App) Action(constraints types.ActionConstraints, middleware ...types.Mi
 		listener.middleware = append(listener.middleware, a.wrapActionMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -485,7 +948,7 @@ func (a *App) Command(command string, middleware ...types.Middleware[types.Slack
 		listener.middleware = append(listener.middleware, a.wrapCommandMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -508,7 +971,7 @@ func (a *App) CommandPattern(pattern *regexp.Regexp, middleware ...types.Middlew
 		listener.middleware = append(listener.middleware, a.wrapCommandMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -532,7 +995,7 @@ func (a *App) Shortcut(constraints types.ShortcutConstraints, middleware ...type
 		listener.middleware = append(listener.middleware, a.wrapShortcutMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -562,7 +1025,7 @@ func (a *App) ShortcutPattern(pattern *regexp.Regexp, middleware ...types.Middle
 		listener.middleware = append(listener.middleware, a.wrapShortcutMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -586,7 +1049,7 @@ func (a *App) View(constraints types.ViewConstraints, middleware ...types.Middle
 		listener.middleware = append(listener.middleware, a.wrapViewMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -616,7 +1079,7 @@ func (a *App) ViewPattern(pattern *regexp.Regexp, middleware ...types.Middleware
 		listener.middleware = append(listener.middleware, a.wrapViewMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -640,7 +1103,7 @@ func (a *App) Options(constraints types.OptionsConstraints, middleware ...types.
 		listener.middleware = append(listener.middleware, a.wrapOptionsMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
@@ -670,62 +1133,31 @@ func (a *App) OptionsPattern(pattern *regexp.Regexp, middleware ...types.Middlew
 		listener.middleware = append(listener.middleware, a.wrapOptionsMiddleware(m))
 	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 	return a
 }
 
-// Assistant registers an assistant for handling AI assistant events
-func (a *App) Assistant(assistant interface{}) *App {
-	// The assistant should implement a GetMiddleware() method
-	if assistantWithMiddleware, ok := assistant.(interface {
-		GetMiddleware() types.Middleware[types.AllMiddlewareArgs]
-	}); ok {
-		middleware := assistantWithMiddleware.GetMiddleware()
-		a.Use(middleware)
-	}
-	return a
+// Assistant registers an assistant, created with assistant.NewAssistant, for handling AI
+// assistant events
+func (a *App) Assistant(asst *assistant.Assistant) *App {
+	return a.Use(asst.GetMiddleware())
 }
 
-// Function registers a custom function handler
-func (a *App) Function(callbackID string, middleware ...interface{}) *App {
-	// Handle different parameter patterns:
-	// Function(callbackID, handler)
-	// Function(callbackID, options, handler)
-
-	var options *types.CustomFunctionOptions
-	var handler types.Middleware[types.SlackCustomFunctionMiddlewareArgs]
-
-	if len(middleware) == 1 {
-		// Function(callbackID, handler)
-		// Use reflection to check if it's a function with the right signature
-		if h, ok := middleware[0].(func(types.SlackCustomFunctionMiddlewareArgs) error); ok {
-			handler = types.Middleware[types.SlackCustomFunctionMiddlewareArgs](h)
-			options = &types.CustomFunctionOptions{AutoAcknowledge: true} // Default
-		} else if h, ok := middleware[0].(types.Middleware[types.SlackCustomFunctionMiddlewareArgs]); ok {
-			handler = h
-			options = &types.CustomFunctionOptions{AutoAcknowledge: true} // Default
-		}
-	} else if len(middleware) == 2 {
-		// Function(callbackID, options, handler)
-		if opts, ok := middleware[0].(types.CustomFunctionOptions); ok {
-			options = &opts
-		} else if opts, ok := middleware[0].(*types.CustomFunctionOptions); ok {
-			options = opts
-		}
-		if h, ok := middleware[1].(func(types.SlackCustomFunctionMiddlewareArgs) error); ok {
-			handler = types.Middleware[types.SlackCustomFunctionMiddlewareArgs](h)
-		} else if h, ok := middleware[1].(types.Middleware[types.SlackCustomFunctionMiddlewareArgs]); ok {
-			handler = h
-		}
-	}
+// Step registers a workflow step, created with workflow.NewWorkflowStep, which handles its edit,
+// save and execute requests and acknowledges them
+//
+// Deprecated: Steps from Apps are no longer supported; use custom functions with Function
+func (a *App) Step(step *workflow.WorkflowStep) *App {
+	return a.Use(step.GetMiddleware())
+}
 
-	if handler == nil {
-		return a // Invalid parameters, skip
-	}
-	if options == nil {
-		options = &types.CustomFunctionOptions{AutoAcknowledge: true}
-	}
+// Function registers custom function listeners for callbackID that acknowledge the event automatically
+func (a *App) Function(callbackID string, middleware ...types.Middleware[types.SlackCustomFunctionMiddlewareArgs]) *App {
+	return a.FunctionWithOptions(callbackID, types.CustomFunctionOptions{AutoAcknowledge: true}, middleware...)
+}
 
+// FunctionWithOptions registers custom function listeners for callbackID with options
+func (a *App) FunctionWithOptions(callbackID string, options types.CustomFunctionOptions, middleware ...types.Middleware[types.SlackCustomFunctionMiddlewareArgs]) *App {
 	// Create a listener for function_executed events with this callback ID
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -744,10 +1176,12 @@ func (a *App) Function(callbackID string, middleware ...interface{}) *App {
 		listener.middleware = append(listener.middleware, a.createAutoAckMiddleware())
 	}
 
-	// Add the custom function handler
-	listener.middleware = append(listener.middleware, a.wrapCustomFunctionMiddleware(handler))
+	// Add the custom function middleware
+	for _, m := range middleware {
+		listener.middleware = append(listener.middleware, a.wrapCustomFunctionMiddleware(m))
+	}
 
-	a.listenerEntries = append(a.listenerEntries, listener)
+	a.addListener(listener)
 
 	return a
 }
@@ -786,14 +1220,8 @@ func (a *App) wrapCustomFunctionMiddleware(m types.Middleware[types.SlackCustomF
 					Body:              eventArgs.Body,
 					Payload:           eventArgs.Event, // Function payload is in the event
 					Ack:               eventArgs.Ack,
-					Complete: func(outputs map[string]interface{}) error {
-						// TODO: Call Slack API to complete the function
-						return nil
-					},
-					Fail: func(error string) error {
-						// TODO: Call Slack API to fail the function
-						return nil
-					},
+					Complete:          functionComplete(args),
+					Fail:              functionFail(args),
 				}
 
 				return m(customFunctionArgs)
@@ -803,49 +1231,190 @@ func (a *App) wrapCustomFunctionMiddleware(m types.Middleware[types.SlackCustomF
 		// Fallback: create basic custom function args
 		customFunctionArgs := types.SlackCustomFunctionMiddlewareArgs{
 			AllMiddlewareArgs: args,
-			Complete: func(outputs map[string]interface{}) error {
-				// TODO: Call Slack API to complete the function
-				return nil
-			},
-			Fail: func(error string) error {
-				// TODO: Call Slack API to fail the function
-				return nil
-			},
+			Complete:          functionComplete(args),
+			Fail:              functionFail(args),
 		}
 		return m(customFunctionArgs)
 	}
 }
 
-// Start starts the app
+// functionComplete returns the Complete function of the function execution of args, which calls
+// functions.completeSuccess with the listener's client
+func functionComplete(args types.AllMiddlewareArgs) types.FunctionCompleteFn {
+	return func(outputs map[string]interface{}) error {
+		if args.Context == nil || args.Context.FunctionExecutionID == "" {
+			return errors.New("function_execution_id is required to complete the function")
+		}
+		return functions.CompleteSuccess(args.Ctx, args.Client, args.Context.FunctionExecutionID, outputs)
+	}
+}
+
+// functionFail returns the Fail function of the function execution of args, which calls
+// functions.completeError with the listener's client
+func functionFail(args types.AllMiddlewareArgs) types.FunctionFailFn {
+	return func(message string) error {
+		if args.Context == nil || args.Context.FunctionExecutionID == "" {
+			return errors.New("function_execution_id is required to fail the function")
+		}
+		return functions.CompleteError(args.Ctx, args.Client, args.Context.FunctionExecutionID, message)
+	}
+}
+
+// receiverStopGrace is how long the app waits for a receiver to return after ctx is done before
+// stopping it, for receivers whose Start does not watch ctx
+const receiverStopGrace = 5 * time.Second
+
+// Start starts the app and serves until the receiver stops, ctx is done or the receiver fails. It
+// returns the receiver's error, ctx's error once ctx is done.
 func (a *App) Start(ctx context.Context) error {
+	errs, err := a.StartAsync(ctx)
+	if err != nil {
+		return err
+	}
+	return <-errs
+}
+
+// StartAsync starts the app like Start, but serves in the background. Errors raised while starting,
+// such as a token failing auth.test, are returned; the error the receiver stops with, such as a
+// Socket Mode connection failing for good, is sent on the returned channel, which is closed once
+// the receiver stopped. Cancelling ctx stops the receiver.
+func (a *App) StartAsync(ctx context.Context) (<-chan error, error) {
 	if !a.initialized {
 		if err := a.Init(ctx); err != nil {
-			return err
+			return nil, err
+		}
+	}
+	// Fail fast on a revoked or wrong token rather than in every listener
+	if err := a.verifyBotToken(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := a.startTunnel(ctx); err != nil {
+		return nil, err
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		err := a.runReceiver(ctx)
+		// The receiver serves until it is stopped; the tunnel has nothing to forward to after that.
+		// It is closed before errs reports the app stopped.
+		_ = a.closeTunnel()
+		if err != nil {
+			errs <- err
 		}
+	}()
+	return errs, nil
+}
+
+// runReceiver runs the receiver until it returns, stopping it when it is still running
+// receiverStopGrace after ctx is done
+func (a *App) runReceiver(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() { done <- a.receiver.Start(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
 	}
 
-	return a.receiver.Start(ctx)
+	grace := time.NewTimer(receiverStopGrace)
+	defer grace.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-grace.C:
+	}
+
+	a.Logger.Warn("Stopping a receiver still running after the app's context was done")
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), receiverStopGrace)
+	defer cancel()
+	if err := a.receiver.Stop(stopCtx); err != nil {
+		a.Logger.Warn("Stopping the receiver failed", "error", err)
+	}
+	select {
+	case err := <-done:
+		if err == nil || errors.Is(err, http.ErrServerClosed) {
+			err = ctx.Err()
+		}
+		return err
+	case <-stopCtx.Done():
+		return ctx.Err()
+	}
 }
 
 // Stop stops the app
 func (a *App) Stop(ctx context.Context) error {
-	return a.receiver.Stop(ctx)
+	err := a.receiver.Stop(ctx)
+	if refreshErr := a.credentialRefresher.close(ctx); err == nil {
+		err = refreshErr
+	}
+	if tunnelErr := a.closeTunnel(); err == nil {
+		err = tunnelErr
+	}
+	// Flush audit records and forwarded requests of the events processed before the receiver stopped
+	if auditErr := a.auditor.close(ctx); err == nil {
+		err = auditErr
+	}
+	if fanOutErr := a.fanOut.close(ctx); err == nil {
+		err = fanOutErr
+	}
+	if reporter, ok := a.errorReporter.(errorReporterCloser); ok {
+		if reportErr := reporter.Close(ctx); err == nil {
+			err = reportErr
+		}
+	}
+	return err
 }
 
-// ProcessEvent processes an incoming event - this is the core of the framework
+// ProcessEvent processes an incoming event - this is the core of the framework. The event's
+// correlation ID, taken from ctx or CorrelationIDHeaders or generated, tags its logs, its
+// Slack API calls and the context passed to listeners and error handlers.
 func (a *App) ProcessEvent(ctx context.Context, event types.ReceiverEvent) error {
+	a.inFlight.add()
+	defer a.inFlight.done()
+	ctx = a.withCorrelation(ctx, event)
+	a.recordEvent(ctx, event)
+	event, forward := a.fanOut.forwardOnAck(ctx, event)
+	defer forward()
+	hooks := a.currentTracingHooks()
+	if a.instrumentation == nil && a.auditor == nil && !hooks.hasEventHooks() {
+		return a.processEvent(ctx, event, nil)
+	}
+
+	started := time.Now()
+	var end func(info ProcessEventInfo, err error)
+	if a.instrumentation != nil {
+		ctx, end = a.instrumentation.StartProcessEvent(ctx, event)
+	}
+	ctx = hooks.startEvent(ctx, event)
+	var info ProcessEventInfo
+	err := a.processEvent(ctx, event, &info)
+	hooks.endEvent(ctx, event, info, err)
+	if end != nil {
+		end(info, err)
+	}
+	a.auditor.record(ctx, event, info, started, err)
+	return err
+}
+
+// processEvent implements ProcessEvent, filling info for Instrumentation and auditing when it is not nil
+func (a *App) processEvent(ctx context.Context, event types.ReceiverEvent, info *ProcessEventInfo) (err error) {
 	if !a.initialized {
 		return bolterrors.NewAppInitializationError("app not initialized")
 	}
+	started := a.metrics.start()
+	logger := a.eventLogger(ctx)
 
 	if a.developerMode {
-		a.Logger.Debug("Processing event", "body", string(event.Body))
+		logger.Debug("Processing event", "body", string(event.Body))
 	}
 
 	// First check if the body can be parsed as JSON (for proper error handling)
 	if len(event.Body) == 0 {
 		// Empty body should return an error
-		a.Logger.Warn("Empty request body. No listeners will be called.")
+		logger.Warn("Empty request body. No listeners will be called.")
 		return bolterrors.NewBaseError(bolterrors.EventProcessingError, "empty request body")
 	}
 
@@ -861,34 +1430,125 @@ func (a *App) ProcessEvent(ctx context.Context, event types.ReceiverEvent) error
 		}
 	}
 
+	// Parse the body once; everything below reads the parsed envelope
+	envelope, jsonErr := parseRequestEnvelope(event.Body)
+
 	// Only validate JSON if content-type is application/json
-	if strings.Contains(strings.ToLower(contentType), "application/json") {
-		var jsonTest map[string]interface{}
-		if err := json.Unmarshal(event.Body, &jsonTest); err != nil {
-			// If it's not valid JSON but claims to be JSON, this is malformed
-			a.Logger.Warn("Malformed JSON in request body. No listeners will be called.")
-			return bolterrors.NewBaseError(bolterrors.EventProcessingError, "malformed JSON in request body")
-		}
+	if strings.Contains(strings.ToLower(contentType), "application/json") && jsonErr != nil {
+		// If it's not valid JSON but claims to be JSON, this is malformed
+		logger.Warn("Malformed JSON in request body. No listeners will be called.")
+		return bolterrors.NewBaseError(bolterrors.EventProcessingError, "malformed JSON in request body")
 	}
 
 	// Determine event type and conversation context
-	typeAndConv := helpers.GetTypeAndConversation(event.Body)
+	typeAndConv := helpers.GetTypeAndConversationFromParsed(envelope.parsed)
 	if typeAndConv.Type == nil {
 		// Body was parsed but event type is unknown - this is OK, just log and continue
-		a.Logger.Warn("Could not determine the type of an incoming event. No listeners will be called.")
+		logger.Warn("Could not determine the type of an incoming event. No listeners will be called.")
 		return nil
 	}
+	if info != nil {
+		info.Type = typeAndConv.Type.String()
+		if *typeAndConv.Type == helpers.IncomingEventTypeEvent {
+			info.EventType = helpers.ExtractEventTypeFromParsed(envelope.jsonBody)
+		}
+	}
+
+	// Skip authorization and building typed args when nothing would run for this event
+	if !a.hasHandlersFor(*typeAndConv.Type, envelope.parsed) {
+		logger.Debug("No listeners registered for incoming event", "event_type", typeAndConv.Type.String())
+		return nil
+	}
+
+	// Record latencies only for events that reach listeners
+	if a.metrics != nil {
+		event.Ack = a.metrics.timeAck(event.Ack, started)
+		defer a.metrics.observeProcessing(started)
+	}
+
+	// Acknowledge requests re-invoking the app to run a lazy function at once; Slack was answered
+	// when the request was first processed, so they are neither retries nor duplicates. Slack's
+	// signature does not cover the lazy function header, so the app's own signature must.
+	lazyID := lazyListenerID(event.Headers)
+	if lazyID != "" {
+		if verifyErr := a.verifyLazyListenerRequest(lazyID, event.Headers, event.Body); verifyErr != nil {
+			return a.handleError(ctx, verifyErr, event, nil)
+		}
+	}
+	lazyOnly := lazyID != ""
+	if lazyOnly && event.Ack != nil {
+		if ackErr := event.Ack(nil); ackErr != nil {
+			logger.Warn("Failed to acknowledge lazy listener request", bolterrors.LogKeyError, ackErr)
+		}
+	}
+
+	// Acknowledge retries the app is configured to skip, so Slack stops delivering them
+	if !lazyOnly && a.retries.skips(ctx, event) {
+		logger.Debug("Skipping retried event", "event_type", typeAndConv.Type.String(), bolterrors.LogKeyRetryNum, event.RetryNum, "retry_reason", event.RetryReason)
+		if event.Ack != nil {
+			if ackErr := event.Ack(nil); ackErr != nil {
+				logger.Warn("Failed to acknowledge skipped retry", bolterrors.LogKeyError, ackErr)
+			}
+		}
+		return nil
+	}
+
+	// Drop events already processed, acknowledging them so Slack stops delivering them
+	duplicate, settle := false, func(error) {}
+	if !lazyOnly {
+		duplicate, settle = a.claimEvent(ctx, event, envelope.jsonBody)
+	}
+	if duplicate {
+		logger.Debug("Dropping duplicate event", "event_type", typeAndConv.Type.String())
+		if event.Ack != nil {
+			if ackErr := event.Ack(nil); ackErr != nil {
+				logger.Warn("Failed to acknowledge duplicate event", bolterrors.LogKeyError, ackErr)
+			}
+		}
+		return nil
+	}
+	defer func() { settle(err) }()
 
 	// Check if this is an enterprise install
-	isEnterpriseInstall := helpers.IsBodyWithTypeEnterpriseInstall(event.Body)
+	isEnterpriseInstall := helpers.IsParsedBodyWithTypeEnterpriseInstall(envelope.parsed)
 
 	// Build authorization source data
-	source := a.buildAuthorizationSource(*typeAndConv.Type, typeAndConv.ConversationID, event.Body, isEnterpriseInstall)
+	source := a.buildAuthorizationSource(*typeAndConv.Type, typeAndConv.ConversationID, envelope, isEnterpriseInstall)
+	if info != nil {
+		info.Handled = true
+		info.TeamID = source.TeamID
+		info.EnterpriseID = source.EnterpriseID
+		info.UserID = source.UserID
+	}
+
+	// Wait for a free slot if the team already has the maximum number of events in flight
+	releaseTeamSlot, err := a.teamLimiter.acquire(ctx, teamLimitKey(source))
+	if err != nil {
+		return a.handleError(ctx, err, event, nil)
+	}
+	defer releaseTeamSlot()
+
+	// Then wait for one of the slots shared by all teams, so events waiting for their team's slot
+	// do not hold one
+	releaseSlot, err := a.concurrencyLimiter.acquire(ctx)
+	if errors.Is(err, errConcurrencyDropped) {
+		logger.Warn("Too many events in flight, dropped event", "event_type", typeAndConv.Type.String())
+		if event.Ack != nil {
+			if ackErr := event.Ack(nil); ackErr != nil {
+				logger.Warn("Failed to acknowledge dropped event", bolterrors.LogKeyError, ackErr)
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return a.handleError(ctx, err, event, nil)
+	}
+	defer releaseSlot()
 
 	// Skip authorization for certain event types
 	var authorizeResult *AuthorizeResult
 	if *typeAndConv.Type == helpers.IncomingEventTypeEvent {
-		eventType := helpers.ExtractEventType(event.Body)
+		eventType := helpers.ExtractEventTypeFromParsed(envelope.jsonBody)
 		if helpers.IsEventTypeToSkipAuthorize(eventType) {
 			// Use minimal authorization for events like app_uninstalled
 			authorizeResult = &AuthorizeResult{
@@ -898,31 +1558,104 @@ func (a *App) ProcessEvent(ctx context.Context, event types.ReceiverEvent) error
 		} else {
 			// Full authorization
 			var err error
-			authorizeResult, err = a.authorize(ctx, source, event.Body)
+			authorizeResult, err = a.authorizeEvent(ctx, source, event.Body)
 			if err != nil {
-				return bolterrors.NewAuthorizationError("Failed to authorize", err)
+				return a.handleError(ctx, newAuthorizationError(err, source), event, nil)
 			}
 		}
 	} else {
 		// Full authorization for non-events
 		var err error
-		authorizeResult, err = a.authorize(ctx, source, event.Body)
+		authorizeResult, err = a.authorizeEvent(ctx, source, event.Body)
 		if err != nil {
-			return bolterrors.NewAuthorizationError("Failed to authorize", err)
+			return a.handleError(ctx, newAuthorizationError(err, source), event, nil)
 		}
 	}
 
 	// Create the context for this event
-	appContext := a.buildEventContext(authorizeResult, event, *typeAndConv.Type)
+	appContext := a.buildEventContext(authorizeResult, event, *typeAndConv.Type, envelope.parsed)
+	defer a.releaseEventContext(appContext)
+	appContext.CorrelationID = CorrelationIDFromContext(ctx)
+	if isEnterpriseInstall {
+		appContext.IsEnterpriseInstall = true
+	}
 
 	// Build the appropriate middleware arguments based on event type
-	middlewareArgs, err := a.buildMiddlewareArgs(ctx, *typeAndConv.Type, event, appContext, authorizeResult)
+	middlewareArgs, err := a.buildMiddlewareArgs(ctx, *typeAndConv.Type, event, appContext, authorizeResult, envelope.parsed)
 	if err != nil {
 		return err
 	}
 
 	// Process listeners - global middleware will be executed for each listener
-	return a.processMatchingListeners(middlewareArgs, *typeAndConv.Type)
+	if err := a.processMatchingListeners(ctx, middlewareArgs, *typeAndConv.Type, info); err != nil {
+		a.metrics.observeListenerError()
+		return a.handleError(ctx, err, event, appContext)
+	}
+	return nil
+}
+
+// authorizeEvent runs the authorize function for an event, reporting it to Instrumentation
+func (a *App) authorizeEvent(ctx context.Context, source AuthorizeSourceData, body []byte) (*AuthorizeResult, error) {
+	if a.instrumentation == nil {
+		return a.authorize(ctx, source, body)
+	}
+
+	ctx, end := a.instrumentation.StartAuthorize(ctx, source)
+	result, err := a.authorize(ctx, source, body)
+	end(err)
+	return result, err
+}
+
+// Error registers a handler for errors raised while processing events, e.g. to report unhandled
+// listener errors to an alerting service instead of only logging them. It replaces the default
+// handler and any handler registered before. The error returned by the handler is passed on to
+// the receiver; return nil to mark the error as handled.
+func (a *App) Error(handler ErrorHandler) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.errorHandler = handler
+	a.extendedErrorHandler = false
+	a.hasCustomErrorHandler = true
+	return a
+}
+
+// ExtendedError registers an extended error handler for errors raised while processing events.
+// The error returned by the handler is passed on to the receiver; return nil to mark the error as handled.
+func (a *App) ExtendedError(handler ExtendedErrorHandler) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.errorHandler = handler
+	a.extendedErrorHandler = true
+	a.hasCustomErrorHandler = true
+	return a
+}
+
+// handleError passes err to the registered error handler and returns the handler's result
+func (a *App) handleError(ctx context.Context, err error, event types.ReceiverEvent, appContext *types.Context) error {
+	a.mu.RLock()
+	handler := a.errorHandler
+	a.mu.RUnlock()
+
+	switch h := handler.(type) {
+	case ExtendedErrorHandler:
+		if appContext == nil {
+			appContext = &types.Context{
+				Custom:        make(types.StringIndexed),
+				RawBody:       event.Body,
+				Headers:       event.Headers,
+				RetryNum:      event.RetryNum,
+				RetryReason:   event.RetryReason,
+				CorrelationID: CorrelationIDFromContext(ctx),
+			}
+		}
+		return h(ctx, err, a.eventLogger(ctx), helpers.ParseRequestBody(event.Body), appContext, event)
+	case ErrorHandler:
+		return h(err)
+	default:
+		return err
+	}
 }
 
 // Helper methods
@@ -940,6 +1673,14 @@ func (a *App) initReceiver(options AppOptions) (types.Receiver, error) {
 			Logger:           options.Logger,
 			LogLevel:         &[]types.LogLevel{types.LogLevelInfo}[0], // Default value
 			CustomProperties: make(map[string]interface{}),
+			CustomRoutes:     options.CustomRoutes,
+
+			ClientID:          options.ClientID,
+			ClientSecret:      options.ClientSecret,
+			StateSecret:       options.StateSecret,
+			RedirectURI:       options.RedirectURI,
+			Scopes:            options.Scopes,
+			InstallationStore: options.InstallationStore,
 		}
 		if options.LogLevel != nil {
 			receiverOptions.LogLevel = options.LogLevel
@@ -960,6 +1701,18 @@ func (a *App) initReceiver(options AppOptions) (types.Receiver, error) {
 			UnhandledRequestHandler:       nil,
 			UnhandledRequestTimeoutMillis: 3001,
 			CustomProperties:              make(map[string]interface{}),
+			AuthenticityErrorHandler:      options.AuthenticityErrorHandler,
+			ErrorStatusCodes:              options.ErrorStatusCodes,
+			SignatureTolerance:            options.SignatureTolerance,
+			CustomRoutes:                  options.CustomRoutes,
+			Port:                          options.Port,
+
+			ClientID:          options.ClientID,
+			ClientSecret:      options.ClientSecret,
+			StateSecret:       options.StateSecret,
+			RedirectURI:       options.RedirectURI,
+			Scopes:            options.Scopes,
+			InstallationStore: options.InstallationStore,
 		}
 
 		// Create the actual HTTP receiver
@@ -975,23 +1728,88 @@ func (a *App) initAuthorize(token *string, authorize AuthorizeFunc, botID, botUs
 	if token != nil {
 		// Single workspace authorization
 		return func(ctx context.Context, source AuthorizeSourceData, body interface{}) (*AuthorizeResult, error) {
-			return &AuthorizeResult{
-				BotToken:     getStringValue(token),
+			result := &AuthorizeResult{
+				BotToken:     a.botToken(),
 				BotID:        getStringValue(botID),
 				BotUserID:    getStringValue(botUserID),
 				TeamID:       source.TeamID,
 				EnterpriseID: source.EnterpriseID,
 				UserID:       source.UserID,
-			}, nil
+			}
+
+			// Resolve the bot identity verified at startup, or with the cached auth.test result,
+			// when it was not configured
+			if a.tokenVerificationEnabled && (result.BotID == "" || result.BotUserID == "" || result.TeamID == "") {
+				identity, verified := a.BotIdentity()
+				if !verified {
+					authTest, err := a.AuthTest(ctx, result.BotToken)
+					if err != nil {
+						return nil, err
+					}
+					identity = BotIdentity{BotID: authTest.BotID, BotUserID: authTest.UserID, TeamID: authTest.TeamID}
+				}
+				if result.BotID == "" {
+					result.BotID = identity.BotID
+				}
+				if result.BotUserID == "" {
+					result.BotUserID = identity.BotUserID
+				}
+				if result.TeamID == "" {
+					result.TeamID = identity.TeamID
+				}
+			}
+			return result, nil
 		}, nil
 	}
 
 	return nil, bolterrors.NewAppInitializationError("either token or authorize function must be provided")
 }
 
-func (a *App) defaultErrorHandler(err error) error {
-	a.Logger.Error("Unhandled error", "error", err)
-	return nil
+// newAuthorizationError wraps an authorize failure with the source data it was called with
+func newAuthorizationError(err error, source AuthorizeSourceData) *bolterrors.AuthorizationError {
+	return bolterrors.NewAuthorizationErrorWithSource("Failed to authorize", err, bolterrors.AuthorizationSource{
+		TeamID:              source.TeamID,
+		EnterpriseID:        source.EnterpriseID,
+		UserID:              source.UserID,
+		ConversationID:      source.ConversationID,
+		IsEnterpriseInstall: source.IsEnterpriseInstall,
+	})
+}
+
+func (a *App) defaultErrorHandler(ctx context.Context, err error, logger types.Logger, body interface{}, context *types.Context, event types.ReceiverEvent) error {
+	logger.Error("Unhandled error", errorLogAttrs(err, context, event)...)
+	if a.errorReporter != nil {
+		a.errorReporter.ReportError(ctx, newErrorReport(ctx, err, context, event))
+	}
+	return err
+}
+
+// errorLogAttrs returns the structured log fields for an error raised while processing event
+func errorLogAttrs(err error, context *types.Context, event types.ReceiverEvent) []any {
+	attrs := bolterrors.LogAttrs(err)
+
+	eventType := helpers.ExtractEventType(event.Body)
+	if eventType == "" {
+		if incomingType := helpers.GetTypeAndConversation(event.Body).Type; incomingType != nil {
+			eventType = incomingType.String()
+		}
+	}
+	if eventType != "" {
+		attrs = append(attrs, bolterrors.LogKeyEventType, eventType)
+	}
+	teamID := ""
+	if context != nil {
+		teamID = context.TeamID
+	}
+	var authErr *bolterrors.AuthorizationError
+	if teamID == "" && errors.As(err, &authErr) && authErr.Source != nil {
+		teamID = authErr.Source.TeamID
+	}
+	if teamID != "" {
+		attrs = append(attrs, bolterrors.LogKeyTeamID, teamID)
+	}
+
+	return append(attrs, bolterrors.LogKeyRetryNum, event.RetryNum)
 }
 
 // Wrapper methods to convert specific middleware to AllMiddlewareArgs
@@ -1102,45 +1920,93 @@ func (a *App) wrapOptionsMiddleware(m types.Middleware[types.SlackOptionsMiddlew
 
 func (a *App) getClientForContext(context *types.Context) *slack.Client {
 	// Return appropriate client based on context
+	if a.attachFunctionToken && context.FunctionBotAccessToken != "" {
+		return a.getOrCreateContextClient(context, context.FunctionBotAccessToken)
+	}
 	if context.BotToken != "" {
-		return a.getOrCreateClient(context.BotToken)
+		return a.getOrCreateContextClient(context, context.BotToken)
+	}
+	return a.client()
+}
+
+// eventAPI returns the API of an event, using botClient when the event was authorized with a bot
+// token and a client of the user token when it was authorized with one
+func (a *App) eventAPI(ctx context.Context, appContext *types.Context, botClient *slack.Client) *types.API {
+	var bot, user *slack.Client
+	if appContext.BotToken != "" {
+		bot = botClient
 	}
-	return a.Client
+	if appContext.UserToken != "" {
+		user = a.eventClient(a.getOrCreateContextClient(appContext, appContext.UserToken), appContext.CorrelationID)
+	}
+	return types.NewAPI(ctx, bot, user)
 }
 
 func (a *App) getOrCreateClient(token string) *slack.Client {
-	// Use the team ID or enterprise ID as the pool key
-	poolKey := "default"
-	if pool, exists := a.clients[poolKey]; exists {
-		return pool.GetOrCreate(token, a.clientOptions...)
+	return a.clientPool.GetOrCreate(token, a.clientOptions...)
+}
+
+// getOrCreateContextClient returns the client of token for the installation appContext was
+// authorized for, calling its API URL with its client options
+func (a *App) getOrCreateContextClient(appContext *types.Context, token string) *slack.Client {
+	if appContext.APIURL == "" && len(appContext.ClientOptions) == 0 {
+		return a.getOrCreateClient(token)
 	}
+	options := append(slices.Clip(a.clientOptions), appContext.ClientOptions...)
+	return a.clientPool.GetOrCreateForAPIURL(token, appContext.APIURL, options...)
+}
+
+// AuthTest returns the auth.test result for token, reusing a cached result for up to
+// AppOptions.AuthTestTTL. Custom authorize functions can use it to resolve bot identities.
+func (a *App) AuthTest(ctx context.Context, token string) (*slack.AuthTestResponse, error) {
+	return a.authTestCache.lookup(ctx, token)
+}
+
+// ClientPoolStats returns the size and activity of the pool of per-token Slack clients
+func (a *App) ClientPoolStats() WebClientPoolStats {
+	return a.clientPool.Stats()
+}
+
+// requestEnvelope is an incoming request body parsed once and shared across event processing
+type requestEnvelope struct {
+	// parsed is the body parsed as JSON or form data
+	parsed map[string]interface{}
+	// jsonBody is the body parsed as JSON, or nil if the body is not JSON
+	jsonBody map[string]interface{}
+}
 
-	// Create new pool
-	pool := NewWebClientPool()
-	a.clients[poolKey] = pool
-	return pool.GetOrCreate(token, a.clientOptions...)
+// parseRequestEnvelope parses body as JSON, falling back to form data. The returned error
+// reports why the body is not JSON.
+func parseRequestEnvelope(body []byte) (requestEnvelope, error) {
+	var jsonBody map[string]interface{}
+	if err := json.Unmarshal(body, &jsonBody); err != nil {
+		return requestEnvelope{parsed: helpers.ParseRequestBody(body)}, err
+	}
+	return requestEnvelope{parsed: jsonBody, jsonBody: jsonBody}, nil
 }
 
 // buildAuthorizationSource builds the authorization source data
-func (a *App) buildAuthorizationSource(eventType helpers.IncomingEventType, conversationID *string, body []byte, isEnterpriseInstall bool) AuthorizeSourceData {
-	// Parse body as JSON or form data
-	parsed := helpers.ParseRequestBody(body)
+func (a *App) buildAuthorizationSource(eventType helpers.IncomingEventType, conversationID *string, envelope requestEnvelope, isEnterpriseInstall bool) AuthorizeSourceData {
+	parsed := envelope.parsed
 
 	source := AuthorizeSourceData{
 		IsEnterpriseInstall: isEnterpriseInstall,
 		ConversationID:      getStringValue(conversationID),
 	}
 
+	// Commands carry enterprise_id, and other payloads an enterprise object, which is null outside
+	// Enterprise Grid
+	if enterpriseID := helpers.ExtractEnterpriseIDFromParsed(parsed); enterpriseID != nil {
+		source.EnterpriseID = *enterpriseID
+	}
+
 	// Extract team_id based on event type
 	switch eventType {
 	case helpers.IncomingEventTypeEvent:
-		if teamID := helpers.ExtractTeamID(body); teamID != nil {
+		if teamID := helpers.ExtractTeamIDFromParsed(envelope.jsonBody); teamID != nil {
 			source.TeamID = *teamID
 		}
-		if enterpriseID := helpers.ExtractEnterpriseID(body); enterpriseID != nil {
-			source.EnterpriseID = *enterpriseID
-		}
-		if userID := helpers.ExtractUserID(body); userID != nil {
+		if userID := helpers.ExtractUserIDFromParsed(envelope.jsonBody); userID != nil {
 			source.UserID = *userID
 		}
 	case helpers.IncomingEventTypeCommand:
@@ -1185,24 +2051,27 @@ func (a *App) buildAuthorizationSource(eventType helpers.IncomingEventType, conv
 }
 
 // buildEventContext creates the context for an event
-func (a *App) buildEventContext(authResult *AuthorizeResult, event types.ReceiverEvent, eventType helpers.IncomingEventType) *types.Context {
-	context := &types.Context{
-		Custom: make(types.StringIndexed),
-	}
+func (a *App) buildEventContext(authResult *AuthorizeResult, event types.ReceiverEvent, eventType helpers.IncomingEventType, parsed map[string]interface{}) *types.Context {
+	context := a.acquireEventContext()
 
 	// Store the event type and body in context for middleware access
 	context.Custom["eventType"] = eventType
 	context.Custom["body"] = event.Body
+	context.RawBody = event.Body
+	context.Headers = event.Headers
 
 	if authResult != nil {
 		context.BotToken = authResult.BotToken
 		context.UserToken = authResult.UserToken
+		context.UserScopes = authResult.UserScopes
 		context.BotID = authResult.BotID
 		context.BotUserID = authResult.BotUserID
 		context.UserID = authResult.UserID
 		context.TeamID = authResult.TeamID
 		context.EnterpriseID = authResult.EnterpriseID
-		context.IsEnterpriseInstall = authResult.Custom != nil
+		context.APIURL = authResult.APIURL
+		context.ClientOptions = authResult.ClientOptions
+		context.IsEnterpriseInstall = authResult.IsEnterpriseInstall
 
 		// Add custom properties from auth result
 		if authResult.Custom != nil {
@@ -1219,27 +2088,43 @@ func (a *App) buildEventContext(authResult *AuthorizeResult, event types.Receive
 	}
 
 	// Extract function execution ID from body if present
-	parsed := helpers.ParseRequestBody(event.Body)
 	if functionExecutionID, exists := parsed["function_execution_id"]; exists {
 		if functionExecutionIDStr, ok := functionExecutionID.(string); ok {
 			context.FunctionExecutionID = functionExecutionIDStr
 		}
 	}
+	// Interactions with the messages of function executions carry the execution's bot token
+	if botAccessToken, ok := parsed["bot_access_token"].(string); ok {
+		context.FunctionBotAccessToken = botAccessToken
+	}
+
+	// Extract the inputs of function executions
+	if eventMap, ok := parsed["event"].(map[string]interface{}); ok && eventMap["type"] == "function_executed" {
+		if inputs, ok := eventMap["inputs"].(map[string]interface{}); ok {
+			context.FunctionInputs = inputs
+		}
+		if functionExecutionID, ok := eventMap["function_execution_id"].(string); ok && context.FunctionExecutionID == "" {
+			context.FunctionExecutionID = functionExecutionID
+		}
+		if botAccessToken, ok := eventMap["bot_access_token"].(string); ok {
+			context.FunctionBotAccessToken = botAccessToken
+		}
+	}
 
 	return context
 }
 
 // buildMiddlewareArgs builds the appropriate middleware arguments based on event type
-func (a *App) buildMiddlewareArgs(ctx context.Context, eventType helpers.IncomingEventType, event types.ReceiverEvent, appContext *types.Context, authResult *AuthorizeResult) (interface{}, error) {
+func (a *App) buildMiddlewareArgs(ctx context.Context, eventType helpers.IncomingEventType, event types.ReceiverEvent, appContext *types.Context, authResult *AuthorizeResult, parsed map[string]interface{}) (interface{}, error) {
 	baseArgs := types.AllMiddlewareArgs{
 		Context: appContext,
-		Logger:  a.Logger,
-		Client:  a.getClientForContext(appContext),
+		Logger:  a.eventLogger(ctx),
+		Client:  a.eventClient(a.getClientForContext(appContext), appContext.CorrelationID),
 		Next:    func() error { return nil }, // Will be overridden in middleware chain
+		Ctx:     ctx,
 	}
-
-	// Parse body as JSON or form data
-	parsed := helpers.ParseRequestBody(event.Body)
+	baseArgs = baseArgs.WithAPI(a.eventAPI(ctx, appContext, baseArgs.Client)).
+		WithAdminOptions(admin.Options{HTTPClient: a.adminHTTPClient})
 
 	// Extract channel information early for Say function
 	if eventType == helpers.IncomingEventTypeEvent {
@@ -1290,19 +2175,25 @@ func (a *App) buildMiddlewareArgs(ctx context.Context, eventType helpers.Incomin
 				appContext.Custom["channel"] = channelStr
 			}
 		}
+	} else if eventType == helpers.IncomingEventTypeShortcut {
+		// Message shortcuts carry the channel of the selected message
+		if channel, ok := parsed["channel"].(map[string]interface{}); ok {
+			if channelStr, ok := channel["id"].(string); ok && channelStr != "" {
+				appContext.Custom["channel"] = channelStr
+			}
+		}
 	}
 
 	// Create say function if there's a conversation context
 	var sayFn types.SayFn
 	if appContext.BotToken != "" {
-		client := a.getClientForContext(appContext)
-		sayFn = a.createSayFunction(client, appContext)
+		sayFn = a.createSayFunction(ctx, baseArgs.Client, appContext)
 	}
 
 	// Create respond function if there's a response URL
 	var respondFn types.RespondFn
 	if responseURL := a.extractResponseURL(parsed); responseURL != "" {
-		respondFn = a.createRespondFunction(responseURL)
+		respondFn = a.createRespondFunction(ctx, responseURL)
 	}
 
 	switch eventType {
@@ -1345,8 +2236,7 @@ func (a *App) buildMiddlewareArgs(ctx context.Context, eventType helpers.Incomin
 		}
 
 		// Store the full args in context for wrapper functions
-		baseArgs.Context.Custom["middlewareArgs"] = args
-		return args, nil
+		return storeMiddlewareArgs(baseArgs.Context, args), nil
 	case helpers.IncomingEventTypeAction:
 		var actionData interface{}
 		if actions, exists := parsed["actions"]; exists {
@@ -1378,9 +2268,13 @@ func (a *App) buildMiddlewareArgs(ctx context.Context, eventType helpers.Incomin
 			Ack:               a.createActionAckFunction(event.Ack),
 			Say:               sayFn,
 		}
+		actionArgs.UpdateMessage, actionArgs.DeleteMessage = a.createContainerMessageFunctions(baseArgs, parsed)
+		if actionArgs.View, err = helpers.ParseActionView(parsed); err != nil {
+			return nil, fmt.Errorf("failed to parse action view: %w", err)
+		}
+		actionArgs.OpenView, actionArgs.PushView, actionArgs.UpdateView = a.createViewFunctions(baseArgs, parsed)
 		// Store the full args in context for wrapper functions
-		baseArgs.Context.Custom["middlewareArgs"] = actionArgs
-		return actionArgs, nil
+		return storeMiddlewareArgs(baseArgs.Context, actionArgs), nil
 	case helpers.IncomingEventTypeCommand:
 		command, err := helpers.ParseSlashCommand(parsed)
 		if err != nil {
@@ -1397,16 +2291,14 @@ func (a *App) buildMiddlewareArgs(ctx context.Context, eventType helpers.Incomin
 			Say:               sayFn,
 		}
 		// Store the full args in context for wrapper functions
-		baseArgs.Context.Custom["middlewareArgs"] = commandArgs
-		return commandArgs, nil
+		return storeMiddlewareArgs(baseArgs.Context, commandArgs), nil
 	case helpers.IncomingEventTypeShortcut:
 		shortcutArgs, err := a.buildShortcutMiddlewareArgs(baseArgs, event, parsed, sayFn)
 		if err != nil {
 			return &types.SayResponse{}, err
 		}
 		// Store the full args in context for wrapper functions
-		baseArgs.Context.Custom["middlewareArgs"] = shortcutArgs
-		return shortcutArgs, nil
+		return storeMiddlewareArgs(baseArgs.Context, shortcutArgs), nil
 	case helpers.IncomingEventTypeViewAction:
 		// Parse the view action (body)
 		viewAction, err := helpers.ParseSlackView(parsed)
@@ -1427,9 +2319,9 @@ func (a *App) buildMiddlewareArgs(ctx context.Context, eventType helpers.Incomin
 			Payload:           viewOutput, // Strongly typed payload (same as view)
 			Ack:               a.createViewAckFunction(event.Ack),
 		}
+		viewArgs.OpenView, viewArgs.PushView, viewArgs.UpdateView = a.createViewFunctions(baseArgs, parsed)
 		// Store the full args in context for wrapper functions
-		baseArgs.Context.Custom["middlewareArgs"] = viewArgs
-		return viewArgs, nil
+		return storeMiddlewareArgs(baseArgs.Context, viewArgs), nil
 	case helpers.IncomingEventTypeOptions:
 		options := types.OptionsRequest{}
 		if actionID, ok := parsed["action_id"].(string); ok {
@@ -1448,27 +2340,89 @@ func (a *App) buildMiddlewareArgs(ctx context.Context, eventType helpers.Incomin
 			Ack:               a.createOptionsAckFunction(event.Ack),
 		}
 		// Store the full args in context for wrapper functions
-		baseArgs.Context.Custom["middlewareArgs"] = optionsArgs
-		return optionsArgs, nil
+		return storeMiddlewareArgs(baseArgs.Context, optionsArgs), nil
 	default:
 		return baseArgs, nil
 	}
 }
 
+// storeMiddlewareArgs stores the full args in context for wrapper functions and returns them,
+// boxing args only once for both uses
+func storeMiddlewareArgs(context *types.Context, args interface{}) interface{} {
+	context.Custom["middlewareArgs"] = args
+	return args
+}
+
 // processGlobalMiddleware processes global middleware
 // Returns (shouldContinue, error) where shouldContinue indicates if listeners should be processed
 
-// processMatchingListeners processes listeners that match the event
-func (a *App) processMatchingListeners(middlewareArgs interface{}, eventType helpers.IncomingEventType) error {
-	var matchingListeners []*listenerEntry
+// listenerPatternMatches returns the submatches of a listener's RegExp message pattern, reusing
+// the result computed while matching the listener
+func (a *App) listenerPatternMatches(listener *listenerEntry, middlewareArgs interface{}) []string {
+	pattern, ok := listener.constraints.messagePattern.(*regexp.Regexp)
+	if !ok {
+		return nil
+	}
+	eventArgs, ok := middlewareArgs.(types.SlackEventMiddlewareArgs)
+	if !ok || eventArgs.Message == nil || eventArgs.Context == nil {
+		return nil
+	}
+	return eventArgs.Context.FindStringSubmatch(pattern, eventArgs.Message.Text)
+}
+
+// hasHandlersFor reports whether any user middleware or listener may run for an event of
+// eventType, judged from the parsed body before typed middleware args are built
+func (a *App) hasHandlersFor(eventType helpers.IncomingEventType, parsed map[string]interface{}) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.hasUserMiddleware || len(a.listeners) > 0 {
+		return true
+	}
+	return len(a.listenerIndex.candidates(eventType, parsedPrimaryKey(eventType, parsed))) > 0
+}
+
+// recoveredPanicError converts a recovered listener panic into an error according to the panic policy
+func (a *App) recoveredPanicError(recovered interface{}) error {
+	if a.panicPolicy == PanicPolicyHandler {
+		return a.panicHandler(recovered, debug.Stack())
+	}
+	return fmt.Errorf("listener panic: %v", recovered)
+}
+
+// processMatchingListeners processes listeners that match the event, recording their outcomes
+// in info when it is not nil
+func (a *App) processMatchingListeners(ctx context.Context, middlewareArgs interface{}, eventType helpers.IncomingEventType, info *ProcessEventInfo) error {
+	var matchingListeners []matchedListener
+
+	// A request re-invoking the app to run a lazy function only runs the listener it belongs to
+	lazyListener := -1
+	if appContext := a.extractBaseArgs(middlewareArgs).Context; appContext != nil {
+		if id := lazyListenerID(appContext.Headers); id != "" {
+			if lazyListener = lazyListenerIndex(id); lazyListener < 0 {
+				return nil
+			}
+		}
+	}
 
-	// Find listeners that match this event type and constraints
-	for _, listener := range a.listenerEntries {
+	// Find listeners that match this event type and constraints, checking only indexed candidates
+	for _, i := range a.listenerIndex.candidates(eventType, incomingPrimaryKey(middlewareArgs)) {
+		listener := a.listenerEntries[i]
+		if lazyListener >= 0 && (i != lazyListener || !listener.lazy) {
+			continue
+		}
 		if a.listenerMatchesEvent(listener, middlewareArgs, eventType) {
-			matchingListeners = append(matchingListeners, listener)
+			matchingListeners = append(matchingListeners, matchedListener{entry: listener, index: i, name: listener.String(), source: listener.source, matches: a.listenerPatternMatches(listener, middlewareArgs)})
 		}
 	}
 
+	if lazyListener >= 0 {
+		if len(matchingListeners) == 0 {
+			return nil
+		}
+		return a.runMatchingListeners(ctx, middlewareArgs, matchingListeners, info)
+	}
+
 	// Also check legacy listeners for backward compatibility
 	for _, listenerChain := range a.listeners {
 		if a.listenerMatches(listenerChain, middlewareArgs, eventType) {
@@ -1477,7 +2431,7 @@ func (a *App) processMatchingListeners(middlewareArgs interface{}, eventType hel
 				eventType:  eventType,
 				middleware: listenerChain,
 			}
-			matchingListeners = append(matchingListeners, legacyListener)
+			matchingListeners = append(matchingListeners, matchedListener{entry: legacyListener, index: -1, name: "legacy " + eventType.String()})
 		}
 	}
 
@@ -1488,23 +2442,47 @@ func (a *App) processMatchingListeners(middlewareArgs interface{}, eventType hel
 			eventType:  eventType,
 			middleware: []types.Middleware[types.AllMiddlewareArgs]{}, // Empty listener middleware
 		}
-		matchingListeners = append(matchingListeners, emptyListener)
+		matchingListeners = append(matchingListeners, matchedListener{entry: emptyListener, index: -1, name: "global middleware"})
 	}
 
+	return a.runMatchingListeners(ctx, middlewareArgs, matchingListeners, info)
+}
+
+// runMatchingListeners runs the listeners matching an event, concurrently if the app allows it,
+// and returns their errors combined in registration order
+func (a *App) runMatchingListeners(ctx context.Context, middlewareArgs interface{}, matchingListeners []matchedListener, info *ProcessEventInfo) error {
 	// Execute all matching listeners (including the empty one if no real listeners match)
-	var listenerErrors []error
-	for _, listener := range matchingListeners {
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					// Convert panic to error
-					listenerErrors = append(listenerErrors, fmt.Errorf("listener panic: %v", r))
-				}
+	errs := make([]error, len(matchingListeners))
+	if a.listenerConcurrency > 1 && len(matchingListeners) > 1 {
+		sem := make(chan struct{}, a.listenerConcurrency)
+		var wg sync.WaitGroup
+		for i, listener := range matchingListeners {
+			listenerArgs := withListenerContext(middlewareArgs)
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				errs[i] = a.runMatchedListener(ctx, listener, listenerArgs)
 			}()
-			if err := a.executeListenerChain(listener.middleware, middlewareArgs); err != nil {
-				listenerErrors = append(listenerErrors, err)
-			}
-		}()
+		}
+		wg.Wait()
+	} else {
+		for i, listener := range matchingListeners {
+			errs[i] = a.runMatchedListener(ctx, listener, middlewareArgs)
+		}
+	}
+
+	// Report errors in registration order, whichever order the listeners finished in
+	var listenerErrors []error
+	for i, err := range errs {
+		if info != nil {
+			info.Listeners = append(info.Listeners, ListenerOutcome{Listener: matchingListeners[i].name, Error: err})
+		}
+		if err != nil {
+			listener := matchingListeners[i]
+			listenerErrors = append(listenerErrors, bolterrors.NewListenerError(listener.name, listener.index, listener.source, err))
+		}
 	}
 
 	if len(listenerErrors) > 0 {
@@ -1514,38 +2492,105 @@ func (a *App) processMatchingListeners(middlewareArgs interface{}, eventType hel
 	return nil
 }
 
-// executeMiddlewareChain executes a middleware chain
+// runMatchedListener runs a matched listener's chain between the listener tracing hooks
+func (a *App) runMatchedListener(ctx context.Context, listener matchedListener, middlewareArgs interface{}) error {
+	hooks := a.currentTracingHooks()
+	ctx = hooks.startListener(ctx, listener.name)
+	err := a.runListenerChain(listener, middlewareArgs)
+	hooks.endListener(ctx, listener.name, err)
+	return err
+}
+
+// runListenerChain runs a matched listener's chain, converting a recovered panic into its
+// error according to the panic policy
+func (a *App) runListenerChain(listener matchedListener, middlewareArgs interface{}) (err error) {
+	if a.panicPolicy != PanicPolicyCrash {
+		defer func() {
+			if r := recover(); r != nil {
+				err = a.recoveredPanicError(r)
+			}
+		}()
+	}
+	// Expose the listener's pattern matches, computed during matching, to its middleware
+	if appContext := a.extractBaseArgs(middlewareArgs).Context; appContext != nil {
+		appContext.SetMatches(listener.matches)
+	}
+	return a.executeListenerChain(listener.entry.middleware, middlewareArgs)
+}
 
-// executeMiddlewareChainWithCompletion executes a middleware chain and tracks completion
-// Returns (completed, error) where completed indicates if the entire chain was executed
+// withListenerContext returns a copy of middlewareArgs with its own Context, so a listener run
+// concurrently with others does not share mutable state with them
+func withListenerContext(middlewareArgs interface{}) interface{} {
+	switch args := middlewareArgs.(type) {
+	case types.SlackEventMiddlewareArgs:
+		if args.Context != nil {
+			args.Context = args.Context.Clone()
+			return storeMiddlewareArgs(args.Context, args)
+		}
+	case types.SlackActionMiddlewareArgs:
+		if args.Context != nil {
+			args.Context = args.Context.Clone()
+			return storeMiddlewareArgs(args.Context, args)
+		}
+	case types.SlackCommandMiddlewareArgs:
+		if args.Context != nil {
+			args.Context = args.Context.Clone()
+			return storeMiddlewareArgs(args.Context, args)
+		}
+	case types.SlackShortcutMiddlewareArgs:
+		if args.Context != nil {
+			args.Context = args.Context.Clone()
+			return storeMiddlewareArgs(args.Context, args)
+		}
+	case types.SlackViewMiddlewareArgs:
+		if args.Context != nil {
+			args.Context = args.Context.Clone()
+			return storeMiddlewareArgs(args.Context, args)
+		}
+	case types.SlackOptionsMiddlewareArgs:
+		if args.Context != nil {
+			args.Context = args.Context.Clone()
+			return storeMiddlewareArgs(args.Context, args)
+		}
+	case types.AllMiddlewareArgs:
+		if args.Context != nil {
+			args.Context = args.Context.Clone()
+			return args
+		}
+	}
+	return middlewareArgs
+}
 
 // executeListenerChain executes a listener chain with proper argument conversion
-// First executes global middleware, then the listener-specific middleware
+// First executes global middleware, then the listener-specific middleware. Each middleware gets
+// its own Next, which runs the middleware after it at most once.
 func (a *App) executeListenerChain(chain []types.Middleware[types.AllMiddlewareArgs], middlewareArgs interface{}) error {
 	// Combine global middleware with listener middleware
 	fullChain := make([]types.Middleware[types.AllMiddlewareArgs], 0, len(a.middleware)+len(chain))
 	fullChain = append(fullChain, a.middleware...)
 	fullChain = append(fullChain, chain...)
 
-	index := 0
-
-	var next types.NextFn
-	next = func() error {
+	var run func(index int) error
+	run = func(index int) error {
 		if index >= len(fullChain) {
 			return nil
 		}
 
-		currentMiddleware := fullChain[index]
-		index++
-
+		called := false
 		// Convert middleware args to base args for execution
 		baseArgs := a.extractBaseArgs(middlewareArgs)
-		baseArgs.Next = next
+		baseArgs.Next = func() error {
+			if called {
+				return bolterrors.NewMultipleNextCallError()
+			}
+			called = true
+			return run(index + 1)
+		}
 
-		return currentMiddleware(baseArgs)
+		return fullChain[index](baseArgs)
 	}
 
-	return next()
+	return run(0)
 }
 
 // Helper methods for building specific middleware args
@@ -1563,17 +2608,61 @@ func (a *App) buildShortcutMiddlewareArgs(baseArgs types.AllMiddlewareArgs, even
 		Payload:           shortcut, // Strongly typed payload
 		Ack:               a.createAckFunction(event),
 	}
+	args.OpenView, _, _ = a.createViewFunctions(baseArgs, parsed)
 
-	// Add say function for message shortcuts
+	// Add say function for message shortcuts, replying in the thread of the selected message
 	if shortcutType, exists := parsed["type"]; exists {
 		if typeStr, ok := shortcutType.(string); ok && typeStr == "message_action" {
-			args.Say = &sayFn
+			threadSay := sayInThread(sayFn, messageShortcutThreadTS(parsed))
+			args.Say = &threadSay
 		}
 	}
 
 	return args, nil
 }
 
+// messageShortcutThreadTS returns the thread of the message a message shortcut was used on: the
+// thread it was posted in, or the thread it starts
+func messageShortcutThreadTS(parsed map[string]interface{}) string {
+	message, _ := parsed["message"].(map[string]interface{})
+	if threadTS, ok := message["thread_ts"].(string); ok && threadTS != "" {
+		return threadTS
+	}
+	if ts, ok := message["ts"].(string); ok && ts != "" {
+		return ts
+	}
+	messageTS, _ := parsed["message_ts"].(string)
+	return messageTS
+}
+
+// sayInThread returns say posting in the thread threadTS unless the message sets its own ThreadTS
+// or InChannel
+func sayInThread(say types.SayFn, threadTS string) types.SayFn {
+	if threadTS == "" {
+		return say
+	}
+	return func(message types.SayMessage) (*types.SayResponse, error) {
+		var args types.SayArguments
+		switch msg := message.(type) {
+		case types.SayString:
+			args = types.SayArguments{Text: string(msg)}
+		case types.SayArguments:
+			args = msg
+		case *types.SayArguments:
+			if msg == nil {
+				return say(message)
+			}
+			args = *msg
+		default:
+			return say(message)
+		}
+		if args.ThreadTS == "" && !args.InChannel {
+			args.ThreadTS = threadTS
+		}
+		return say(args)
+	}
+}
+
 // Utility functions
 func (a *App) extractResponseURL(parsed map[string]interface{}) string {
 	if responseURL, exists := parsed["response_url"]; exists {
@@ -1605,142 +2694,245 @@ func (a *App) extractBaseArgs(middlewareArgs interface{}) types.AllMiddlewareArg
 	}
 }
 
-// createSayFunction creates a say function for sending messages
-func (a *App) createSayFunction(client *slack.Client, context *types.Context) types.SayFn {
+// createSayFunction creates a say function for sending messages, called with the context of the
+// event being processed
+func (a *App) createSayFunction(ctx context.Context, client *slack.Client, context *types.Context) types.SayFn {
 	return func(message types.SayMessage) (*types.SayResponse, error) {
-		// Determine channel from context or message
-		var channelID string
+		var args types.SayArguments
 
-		// Try to get channel from message
 		switch msg := message.(type) {
 		case types.SayString:
-			// Simple text message - need channel from context
-			if context.Custom != nil {
-				if ch, exists := context.Custom["channel"]; exists {
-					if chStr, ok := ch.(string); ok {
-						channelID = chStr
-					}
-				}
-			}
-			if channelID == "" {
-				return &types.SayResponse{}, bolterrors.NewAppInitializationError("no channel context for say function")
-			}
-
-			_, _, err := client.PostMessage(channelID, slack.MsgOptionText(string(msg), false))
-			return &types.SayResponse{}, err
-
+			args = types.SayArguments{Text: string(msg)}
 		case types.SayArguments:
-			if msg.Channel != "" {
-				channelID = msg.Channel
+			args = msg
+		case *types.SayArguments:
+			if msg == nil {
+				return &types.SayResponse{}, bolterrors.NewAppInitializationError("unsupported message type for say function")
 			}
+			args = *msg
+		default:
+			return &types.SayResponse{}, bolterrors.NewAppInitializationError("unsupported message type for say function")
+		}
 
-			var options []slack.MsgOption
-			if msg.Text != "" {
-				options = append(options, slack.MsgOptionText(msg.Text, false))
-			}
-			if len(msg.Blocks) > 0 {
-				options = append(options, slack.MsgOptionBlocks(msg.Blocks...))
-			}
-			if len(msg.Attachments) > 0 {
-				options = append(options, slack.MsgOptionAttachments(msg.Attachments...))
-			}
-			if msg.ThreadTS != "" {
-				options = append(options, slack.MsgOptionTS(msg.ThreadTS))
-			}
-			if msg.Metadata != nil {
-				options = append(options, slack.MsgOptionMetadata(*msg.Metadata))
+		// Fall back to the channel of the incoming event
+		channelID := args.Channel
+		if channelID == "" && context.Custom != nil {
+			if ch, ok := context.Custom["channel"].(string); ok {
+				channelID = ch
 			}
+		}
+		if channelID == "" {
+			return &types.SayResponse{}, bolterrors.NewAppInitializationError("no channel context for say function")
+		}
 
-			_, _, err := client.PostMessage(channelID, options...)
-			return &types.SayResponse{}, err
+		metadata, err := sayMetadata(args.Metadata)
+		if err != nil {
+			return &types.SayResponse{Error: err.Error()}, err
+		}
 
-		case *types.SayArguments:
-			// Handle pointer to SayArguments
-			if msg.Channel != "" {
-				channelID = msg.Channel
-			}
+		respChannel, respTimestamp, err := client.PostMessageContext(ctx, channelID, buildSayOptions(args, metadata)...)
+		return newSayResponse(respChannel, respTimestamp, err), err
+	}
+}
 
-			var options []slack.MsgOption
-			if msg.Text != "" {
-				options = append(options, slack.MsgOptionText(msg.Text, false))
-			}
-			if len(msg.Blocks) > 0 {
-				options = append(options, slack.MsgOptionBlocks(msg.Blocks...))
-			}
-			if len(msg.Attachments) > 0 {
-				options = append(options, slack.MsgOptionAttachments(msg.Attachments...))
-			}
-			if msg.ThreadTS != "" {
-				options = append(options, slack.MsgOptionTS(msg.ThreadTS))
-			}
-			if msg.Metadata != nil {
-				options = append(options, slack.MsgOptionMetadata(*msg.Metadata))
-			}
+// sayMetadata converts the metadata of a say call into chat.postMessage metadata, nil if there is
+// none
+func sayMetadata(metadata types.SayMetadata) (*slack.SlackMetadata, error) {
+	switch metadata := metadata.(type) {
+	case nil:
+		return nil, nil
+	case *slack.SlackMetadata:
+		return metadata, nil
+	case slack.SlackMetadata:
+		return &metadata, nil
+	case types.SlackMetadataConverter:
+		slackMetadata, err := metadata.ToSlackMetadata()
+		if err != nil {
+			return nil, err
+		}
+		return &slackMetadata, nil
+	default:
+		return nil, fmt.Errorf("unsupported message metadata type %T", metadata)
+	}
+}
 
-			_, _, err := client.PostMessage(channelID, options...)
-			return &types.SayResponse{}, err
+// buildSayOptions converts say arguments into chat.postMessage options
+func buildSayOptions(args types.SayArguments, metadata *slack.SlackMetadata) []slack.MsgOption {
+	var options []slack.MsgOption
+	if args.Text != "" {
+		options = append(options, slack.MsgOptionText(args.Text, false))
+	}
+	if len(args.Blocks) > 0 {
+		options = append(options, slack.MsgOptionBlocks(args.Blocks...))
+	}
+	if len(args.Attachments) > 0 {
+		options = append(options, slack.MsgOptionAttachments(args.Attachments...))
+	}
+	if args.ThreadTS != "" {
+		options = append(options, slack.MsgOptionTS(args.ThreadTS))
+	}
+	if metadata != nil {
+		options = append(options, slack.MsgOptionMetadata(*metadata))
+	}
+	return options
+}
 
+// newSayResponse builds the say response from the chat.postMessage result
+func newSayResponse(channel, timestamp string, err error) *types.SayResponse {
+	if err != nil {
+		response := &types.SayResponse{Error: err.Error()}
+		var slackErr slack.SlackErrorResponse
+		if errors.As(err, &slackErr) {
+			response.Error = slackErr.Err
 		}
+		return response
+	}
 
-		return &types.SayResponse{}, bolterrors.NewAppInitializationError("unsupported message type for say function")
+	return &types.SayResponse{
+		OK:        true,
+		Channel:   channel,
+		Timestamp: timestamp,
 	}
 }
 
-// createRespondFunction creates a respond function for response URLs
-func (a *App) createRespondFunction(responseURL string) types.RespondFn {
-	return func(message types.RespondMessage) error {
-		var payload []byte
-		var err error
+// createContainerMessageFunctions creates the functions updating and deleting the message an
+// action was taken in
+func (a *App) createContainerMessageFunctions(baseArgs types.AllMiddlewareArgs, parsed map[string]interface{}) (types.UpdateMessageFn, types.DeleteMessageFn) {
+	channelID, messageTS, ephemeral := containerMessage(parsed)
 
-		switch msg := message.(type) {
-		case types.RespondString:
-			payload, err = json.Marshal(map[string]interface{}{
-				"text": string(msg),
-			})
-		case types.RespondArguments:
-			payload, err = json.Marshal(msg)
-		default:
-			payload, err = json.Marshal(message)
+	// check reports why the container message cannot be changed, if it cannot
+	check := func() error {
+		switch {
+		case ephemeral:
+			return bolterrors.NewContextMissingPropertyError("message_ts", "ephemeral messages cannot be changed with chat.update or chat.delete; use respond() instead")
+		case channelID == "" || messageTS == "":
+			return bolterrors.NewContextMissingPropertyError("message_ts", "the action was not taken in a message")
+		case baseArgs.Client == nil:
+			return bolterrors.NewContextMissingPropertyError("client", "changing the message of an action needs a client")
 		}
+		return nil
+	}
 
-		if err != nil {
+	updateMessage := func(message types.UpdateMessage) error {
+		if err := check(); err != nil {
 			return err
 		}
 
-		// Validate URL to prevent potential security issues
-		// Allow localhost/127.0.0.1 for testing, but require https://hooks.slack.com/ for production
-		if !strings.HasPrefix(responseURL, "https://hooks.slack.com/") &&
-			!strings.HasPrefix(responseURL, "http://127.0.0.1") &&
-			!strings.HasPrefix(responseURL, "http://localhost") {
-			return bolterrors.NewAppInitializationError("invalid response URL")
+		var args types.UpdateMessageArguments
+		switch msg := message.(type) {
+		case types.UpdateMessageString:
+			args = types.UpdateMessageArguments{Text: string(msg)}
+		case types.UpdateMessageArguments:
+			args = msg
+		default:
+			return bolterrors.NewAppInitializationError("unsupported message type for update message function")
 		}
 
-		// Use a client with timeout for security
-		client := &http.Client{
-			Timeout: 30 * time.Second,
+		var options []slack.MsgOption
+		if args.Text != "" {
+			options = append(options, slack.MsgOptionText(args.Text, false))
+		}
+		if len(args.Blocks) > 0 {
+			options = append(options, slack.MsgOptionBlocks(args.Blocks...))
+		}
+		if len(args.Attachments) > 0 {
+			options = append(options, slack.MsgOptionAttachments(args.Attachments...))
 		}
 
-		// Create context-aware request
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+		_, _, _, err := baseArgs.Client.UpdateMessageContext(baseArgs.API().Context(), channelID, messageTS, options...)
+		return err
+	}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewBuffer(payload))
-		if err != nil {
+	deleteMessage := func() error {
+		if err := check(); err != nil {
 			return err
 		}
-		req.Header.Set("Content-Type", "application/json")
+		_, _, err := baseArgs.Client.DeleteMessageContext(baseArgs.API().Context(), channelID, messageTS)
+		return err
+	}
 
-		resp, err := client.Do(req)
-		if err != nil {
-			return err
+	return updateMessage, deleteMessage
+}
+
+// createViewFunctions creates the functions opening and pushing views with the trigger_id of an
+// interaction, and updating the view it came from
+func (a *App) createViewFunctions(baseArgs types.AllMiddlewareArgs, parsed map[string]interface{}) (open, push, update types.ViewFn) {
+	triggerID, _ := parsed["trigger_id"].(string)
+	var viewID, hash string
+	if view, ok := parsed["view"].(map[string]interface{}); ok {
+		viewID, _ = view["id"].(string)
+		hash, _ = view["hash"].(string)
+	}
+
+	// check reports why method cannot be called, when value, the property it needs, is empty
+	check := func(method, property, value string) error {
+		switch {
+		case value == "":
+			return bolterrors.NewContextMissingPropertyError(property, fmt.Sprintf("%s needs a %s, which this request does not have", method, property))
+		case baseArgs.Client == nil:
+			return bolterrors.NewContextMissingPropertyError("client", method+" needs a client")
 		}
-		defer resp.Body.Close()
+		return nil
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			return bolterrors.NewAppInitializationError("failed to send response")
+	open = func(view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+		if err := check("views.open", "trigger_id", triggerID); err != nil {
+			return nil, err
+		}
+		return baseArgs.Client.OpenViewContext(baseArgs.API().Context(), triggerID, view)
+	}
+	push = func(view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+		if err := check("views.push", "trigger_id", triggerID); err != nil {
+			return nil, err
+		}
+		return baseArgs.Client.PushViewContext(baseArgs.API().Context(), triggerID, view)
+	}
+	update = func(view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+		if err := check("views.update", "view_id", viewID); err != nil {
+			return nil, err
 		}
+		return baseArgs.Client.UpdateViewContext(baseArgs.API().Context(), view, "", hash, viewID)
+	}
+	return open, push, update
+}
 
-		return nil
+// containerMessage returns the channel and timestamp of the message an action was taken in, from
+// the container of block actions or the channel and message_ts of attachment actions
+func containerMessage(parsed map[string]interface{}) (channelID, messageTS string, ephemeral bool) {
+	if container, ok := parsed["container"].(map[string]interface{}); ok {
+		channelID, _ = container["channel_id"].(string)
+		messageTS, _ = container["message_ts"].(string)
+		ephemeral, _ = container["is_ephemeral"].(bool)
+	}
+	if channelID == "" {
+		if channel, ok := parsed["channel"].(map[string]interface{}); ok {
+			channelID, _ = channel["id"].(string)
+		}
+	}
+	if messageTS == "" {
+		if message, ok := parsed["message"].(map[string]interface{}); ok {
+			messageTS, _ = message["ts"].(string)
+		}
+	}
+	if messageTS == "" {
+		messageTS, _ = parsed["message_ts"].(string)
+	}
+	return channelID, messageTS, ephemeral
+}
+
+// createRespondFunction creates a respond function for response URLs, posting with eventCtx when
+// called with a nil context
+func (a *App) createRespondFunction(eventCtx context.Context, responseURL string) types.RespondFn {
+	return func(ctx context.Context, message types.RespondMessage) (*types.RespondResponse, error) {
+		// Only post to allowed hosts so a forged response_url cannot redirect replies
+		if err := a.responseURLValidator.Validate(responseURL); err != nil {
+			return nil, err
+		}
+
+		if ctx == nil {
+			ctx = eventCtx
+		}
+		return helpers.PostResponseURL(ctx, a.httpClient, responseURL, message)
 	}
 }
 
@@ -1758,6 +2950,9 @@ func (a *App) createAckFunction(event types.ReceiverEvent) types.AckFn[interface
 // createCommandAckFunction creates an ack function for commands
 func (a *App) createCommandAckFunction(receiverAck func(response types.AckResponse) error) types.AckFn[types.CommandResponse] {
 	return func(response *types.CommandResponse) error {
+		if response == nil {
+			return receiverAck(nil)
+		}
 		ackResp := a.convertToAckResponse(response)
 		return receiverAck(ackResp)
 	}
@@ -1766,6 +2961,9 @@ func (a *App) createCommandAckFunction(receiverAck func(response types.AckRespon
 // createViewAckFunction creates an ack function for views
 func (a *App) createViewAckFunction(receiverAck func(response types.AckResponse) error) types.AckFn[types.ViewResponse] {
 	return func(response *types.ViewResponse) error {
+		if response == nil {
+			return receiverAck(nil)
+		}
 		ackResp := a.convertToAckResponse(response)
 		return receiverAck(ackResp)
 	}
@@ -1774,6 +2972,9 @@ func (a *App) createViewAckFunction(receiverAck func(response types.AckResponse)
 // createOptionsAckFunction creates an ack function for options
 func (a *App) createOptionsAckFunction(receiverAck func(response types.AckResponse) error) types.AckFn[types.OptionsResponse] {
 	return func(response *types.OptionsResponse) error {
+		if response == nil {
+			return receiverAck(nil)
+		}
 		ackResp := a.convertToAckResponse(response)
 		return receiverAck(ackResp)
 	}
@@ -1891,6 +3092,9 @@ func (a *App) matchesEventConstraints(listener *listenerEntry, middlewareArgs in
 			return false
 		}
 
+		if pattern, ok := listener.constraints.messagePattern.(*regexp.Regexp); ok && eventArgs.Context != nil {
+			return eventArgs.Context.FindStringSubmatch(pattern, eventArgs.Message.Text) != nil
+		}
 		return helpers.MatchesPattern(eventArgs.Message.Text, listener.constraints.messagePattern)
 	}
 
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// defaultAuditBufferSize is the number of records queued for the sink before new ones are dropped
+const defaultAuditBufferSize = 1024
+
+// AuditSink receives a record of every event the app processed, for compliance logging. Audit
+// is called from a single background goroutine, one record at a time; see pkg/audit for file
+// and webhook sinks.
+type AuditSink interface {
+	Audit(ctx context.Context, record AuditRecord) error
+}
+
+// AuditOptions enables auditing of processed events
+type AuditOptions struct {
+	// Sink receives the records; auditing is off when it is nil
+	Sink AuditSink
+	// BufferSize is the number of records queued while the sink is busy (default 1024). Records
+	// arriving while the queue is full are dropped and logged so event processing never waits
+	// on the sink.
+	BufferSize int
+}
+
+// AuditRecord summarizes a processed event
+type AuditRecord struct {
+	Time          time.Time `json:"time"`
+	CorrelationID string    `json:"correlation_id"`
+	// Type is the class of the request, such as "event", "command" or "action"; empty when the
+	// request could not be classified
+	Type string `json:"type,omitempty"`
+	// EventType is the Events API event type, such as "app_mention", for Type "event"
+	EventType    string `json:"event_type,omitempty"`
+	TeamID       string `json:"team_id,omitempty"`
+	EnterpriseID string `json:"enterprise_id,omitempty"`
+	UserID       string `json:"user_id,omitempty"`
+	RetryNum     int    `json:"retry_num,omitempty"`
+	// Handled reports whether middleware or listeners were registered for the event
+	Handled bool `json:"handled"`
+	// Listeners are the outcomes of the listeners that ran, in registration order
+	Listeners []AuditListenerOutcome `json:"listeners,omitempty"`
+	// Error and ErrorCode describe the error ProcessEvent returned, if any
+	Error     string               `json:"error,omitempty"`
+	ErrorCode bolterrors.ErrorCode `json:"error_code,omitempty"`
+	Duration  time.Duration        `json:"duration"`
+}
+
+// AuditListenerOutcome is the result of one listener run for an audited event
+type AuditListenerOutcome struct {
+	// Listener describes the listener, such as "event(type=app_mention)"
+	Listener string `json:"listener"`
+	// Error is the error the listener returned, empty if it succeeded
+	Error string `json:"error,omitempty"`
+}
+
+// auditor queues audit records and hands them to the sink in the background
+type auditor struct {
+	sink    AuditSink
+	logger  types.Logger
+	records chan AuditRecord
+	done    chan struct{}
+
+	// mu guards closed so no record is queued after the queue is closed
+	mu     sync.RWMutex
+	closed bool
+}
+
+// newAuditor starts the audit goroutine, or returns nil when auditing is off
+func newAuditor(options AuditOptions, logger types.Logger) *auditor {
+	if options.Sink == nil {
+		return nil
+	}
+	size := options.BufferSize
+	if size <= 0 {
+		size = defaultAuditBufferSize
+	}
+
+	a := &auditor{
+		sink:    options.Sink,
+		logger:  logger,
+		records: make(chan AuditRecord, size),
+		done:    make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// run sends queued records to the sink until the queue is closed
+func (a *auditor) run() {
+	defer close(a.done)
+	for record := range a.records {
+		if err := a.sink.Audit(context.Background(), record); err != nil {
+			a.logger.Warn("Audit sink failed", bolterrors.LogKeyError, err, bolterrors.LogKeyCorrelationID, record.CorrelationID)
+		}
+	}
+}
+
+// record queues a record for the event, dropping it if the queue is full
+func (a *auditor) record(ctx context.Context, event types.ReceiverEvent, info ProcessEventInfo, started time.Time, err error) {
+	if a == nil {
+		return
+	}
+
+	record := AuditRecord{
+		Time:          started,
+		CorrelationID: CorrelationIDFromContext(ctx),
+		Type:          info.Type,
+		EventType:     info.EventType,
+		TeamID:        info.TeamID,
+		EnterpriseID:  info.EnterpriseID,
+		UserID:        info.UserID,
+		RetryNum:      event.RetryNum,
+		Handled:       info.Handled,
+		Duration:      time.Since(started),
+	}
+	for _, listener := range info.Listeners {
+		outcome := AuditListenerOutcome{Listener: listener.Listener}
+		if listener.Error != nil {
+			outcome.Error = listener.Error.Error()
+		}
+		record.Listeners = append(record.Listeners, outcome)
+	}
+	if err != nil {
+		record.Error = err.Error()
+		record.ErrorCode = bolterrors.AsCodedError(err).Code()
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return
+	}
+	select {
+	case a.records <- record:
+	default:
+		a.logger.Warn("Audit queue full, dropped record", bolterrors.LogKeyCorrelationID, record.CorrelationID)
+	}
+}
+
+// close stops accepting records and waits until queued records are sent or ctx is done
+func (a *auditor) close(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.records)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// defaultAuthTestTTL is how long an auth.test result is reused for a token
+const defaultAuthTestTTL = 10 * time.Minute
+
+// authTestCache caches auth.test results per token, so token verification and bot identity
+// lookups call the API at most once per token and TTL. Concurrent lookups of the same token share
+// one call; failed calls are not cached.
+type authTestCache struct {
+	ttl       time.Duration
+	clientFor func(token string) *slack.Client
+
+	mu      sync.Mutex
+	entries map[string]*authTestEntry
+}
+
+// authTestEntry is the cached or in-flight auth.test call for one token
+type authTestEntry struct {
+	done    chan struct{}
+	result  *slack.AuthTestResponse
+	err     error
+	expires time.Time
+}
+
+// newAuthTestCache creates a cache calling auth.test with the clients returned by clientFor
+func newAuthTestCache(ttl time.Duration, clientFor func(token string) *slack.Client) *authTestCache {
+	if ttl <= 0 {
+		ttl = defaultAuthTestTTL
+	}
+	return &authTestCache{
+		ttl:       ttl,
+		clientFor: clientFor,
+		entries:   make(map[string]*authTestEntry),
+	}
+}
+
+// lookup returns the auth.test result for token, calling the API if no fresh result is cached
+func (c *authTestCache) lookup(ctx context.Context, token string) (*slack.AuthTestResponse, error) {
+	c.mu.Lock()
+	entry, exists := c.entries[token]
+	if exists {
+		select {
+		case <-entry.done:
+			if time.Now().After(entry.expires) {
+				exists = false
+			}
+		default:
+			// Another caller is running auth.test for this token; wait for its result below
+		}
+	}
+	if !exists {
+		entry = &authTestEntry{done: make(chan struct{})}
+		c.entries[token] = entry
+		c.mu.Unlock()
+
+		entry.result, entry.err = c.clientFor(token).AuthTestContext(ctx)
+		entry.expires = time.Now().Add(c.ttl)
+		if entry.err != nil {
+			c.mu.Lock()
+			if c.entries[token] == entry {
+				delete(c.entries, token)
+			}
+			c.mu.Unlock()
+		}
+		close(entry.done)
+		return entry.result, entry.err
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-entry.done:
+		return entry.result, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
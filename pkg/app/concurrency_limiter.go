@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+)
+
+// defaultConcurrencyQueueSize is the number of events that may wait for a slot when no queue size
+// is configured
+const defaultConcurrencyQueueSize = 100
+
+// OverflowPolicy decides what happens to an event arriving when AppOptions.MaxConcurrency events
+// are processed and the concurrency queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowPolicyBlock lets the event wait for a slot however many events already wait
+	OverflowPolicyBlock OverflowPolicy = iota
+	// OverflowPolicyReject fails the event with a retryable ConcurrencyLimitError, so receivers
+	// leave it for Slack to redeliver
+	OverflowPolicyReject
+	// OverflowPolicyDrop acknowledges the event without processing it
+	OverflowPolicyDrop
+)
+
+// ConcurrencyQueueOptions configures the events waiting for one of the AppOptions.MaxConcurrency
+// slots
+type ConcurrencyQueueOptions struct {
+	// Size is the number of events that may wait for a slot (default 100). It does not apply to
+	// OverflowPolicyBlock.
+	Size int
+	// OverflowPolicy handles events arriving when the queue is full (default OverflowPolicyBlock)
+	OverflowPolicy OverflowPolicy
+	// WaitTimeout bounds how long an event waits for a slot before it is rejected with a
+	// ConcurrencyLimitError; 0 waits until the request context is done
+	WaitTimeout time.Duration
+}
+
+// ConcurrencyStats is a snapshot of the events processed under AppOptions.MaxConcurrency
+type ConcurrencyStats struct {
+	// MaxConcurrency is the configured limit, 0 when events are not limited
+	MaxConcurrency int `json:"max_concurrency"`
+	// InFlight is the number of events holding a slot
+	InFlight int `json:"in_flight"`
+	// Queued is the number of events waiting for a slot
+	Queued int `json:"queued"`
+	// Dropped counts the events dropped by OverflowPolicyDrop since the app started
+	Dropped int64 `json:"dropped"`
+	// Rejected counts the events rejected with a ConcurrencyLimitError since the app started
+	Rejected int64 `json:"rejected"`
+}
+
+// errConcurrencyDropped is returned by concurrencyLimiter.acquire for events to acknowledge
+// without processing
+var errConcurrencyDropped = errors.New("event dropped by concurrency overflow policy")
+
+// concurrencyLimiter hands out the slots of the events processed at once across all teams
+type concurrencyLimiter struct {
+	slots       chan struct{}
+	queueSize   int64
+	policy      OverflowPolicy
+	waitTimeout time.Duration
+
+	queued   atomic.Int64
+	dropped  atomic.Int64
+	rejected atomic.Int64
+}
+
+// newConcurrencyLimiter creates a limiter of maxConcurrency slots, or returns nil if events are
+// not limited
+func newConcurrencyLimiter(maxConcurrency int, options ConcurrencyQueueOptions) *concurrencyLimiter {
+	if maxConcurrency <= 0 {
+		return nil
+	}
+	queueSize := options.Size
+	if queueSize <= 0 {
+		queueSize = defaultConcurrencyQueueSize
+	}
+	return &concurrencyLimiter{
+		slots:       make(chan struct{}, maxConcurrency),
+		queueSize:   int64(queueSize),
+		policy:      options.OverflowPolicy,
+		waitTimeout: options.WaitTimeout,
+	}
+}
+
+// acquire waits for a slot and returns the function releasing it. It returns
+// errConcurrencyDropped or a ConcurrencyLimitError for events the overflow policy turns away. All
+// events are accepted when the limiter is nil.
+func (l *concurrencyLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+
+	if queued := l.queued.Add(1); l.policy != OverflowPolicyBlock && queued > l.queueSize {
+		l.queued.Add(-1)
+		if l.policy == OverflowPolicyDrop {
+			l.dropped.Add(1)
+			return nil, errConcurrencyDropped
+		}
+		l.rejected.Add(1)
+		return nil, bolterrors.NewConcurrencyLimitError(nil)
+	}
+	defer l.queued.Add(-1)
+
+	var timeout <-chan time.Time
+	if l.waitTimeout > 0 {
+		timer := time.NewTimer(l.waitTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-timeout:
+		l.rejected.Add(1)
+		return nil, bolterrors.NewConcurrencyLimitError(nil)
+	case <-ctx.Done():
+		l.rejected.Add(1)
+		return nil, bolterrors.NewConcurrencyLimitError(ctx.Err())
+	}
+}
+
+// release frees a slot
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
+
+// stats returns a snapshot of the slots and queue
+func (l *concurrencyLimiter) stats() ConcurrencyStats {
+	if l == nil {
+		return ConcurrencyStats{}
+	}
+	return ConcurrencyStats{
+		MaxConcurrency: cap(l.slots),
+		InFlight:       len(l.slots),
+		Queued:         int(l.queued.Load()),
+		Dropped:        l.dropped.Load(),
+		Rejected:       l.rejected.Load(),
+	}
+}
+
+// ConcurrencyStats returns a snapshot of the events processed and waiting under
+// AppOptions.MaxConcurrency, e.g. to export the queue depth as a metric
+func (a *App) ConcurrencyStats() ConcurrencyStats {
+	return a.concurrencyLimiter.stats()
+}
@@ -0,0 +1,272 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Receivers a Config can select
+const (
+	ConfigReceiverHTTP       = "http"
+	ConfigReceiverSocketMode = "socket_mode"
+)
+
+// configEnvPattern matches ${NAME} and ${NAME:-default} references in config values
+var configEnvPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// configLogLevels maps the log_level values of a Config to log levels
+var configLogLevels = map[string]types.LogLevel{
+	"debug": types.LogLevelDebug,
+	"info":  types.LogLevelInfo,
+	"warn":  types.LogLevelWarn,
+	"error": types.LogLevelError,
+}
+
+// Config is the YAML or JSON file read by NewFromConfig. Any value may reference environment
+// variables as ${NAME}, or ${NAME:-default} to fall back to default when NAME is not set, so
+// secrets stay out of the file.
+type Config struct {
+	Token         string `json:"token,omitempty" yaml:"token,omitempty"`
+	AppToken      string `json:"app_token,omitempty" yaml:"app_token,omitempty"`
+	SigningSecret string `json:"signing_secret,omitempty" yaml:"signing_secret,omitempty"`
+	BotID         string `json:"bot_id,omitempty" yaml:"bot_id,omitempty"`
+	BotUserID     string `json:"bot_user_id,omitempty" yaml:"bot_user_id,omitempty"`
+
+	// The OAuth client serves installs with the default receiver, see AppOptions.ClientID; set
+	// an InstallationStore with ConfigOptions.Configure to authorize events with its installations
+	ClientID     string   `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty" yaml:"client_secret,omitempty"`
+	StateSecret  string   `json:"state_secret,omitempty" yaml:"state_secret,omitempty"`
+	RedirectURI  string   `json:"redirect_uri,omitempty" yaml:"redirect_uri,omitempty"`
+	Scopes       []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+
+	// Receiver is ConfigReceiverHTTP (default) or ConfigReceiverSocketMode
+	Receiver string `json:"receiver,omitempty" yaml:"receiver,omitempty"`
+	// Port is the port of the HTTP receiver (default 3000)
+	Port      int                      `json:"port,omitempty" yaml:"port,omitempty"`
+	Endpoints *types.ReceiverEndpoints `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	// CustomRoutes are served next to the Slack endpoints
+	CustomRoutes          []ConfigRoute `json:"custom_routes,omitempty" yaml:"custom_routes,omitempty"`
+	ProcessBeforeResponse bool          `json:"process_before_response,omitempty" yaml:"process_before_response,omitempty"`
+
+	// LogLevel is "debug", "info", "warn" or "error"
+	LogLevel                 string `json:"log_level,omitempty" yaml:"log_level,omitempty"`
+	DeveloperMode            bool   `json:"developer_mode,omitempty" yaml:"developer_mode,omitempty"`
+	TokenVerificationEnabled bool   `json:"token_verification_enabled,omitempty" yaml:"token_verification_enabled,omitempty"`
+	IgnoreSelf               *bool  `json:"ignore_self,omitempty" yaml:"ignore_self,omitempty"`
+}
+
+// ConfigRoute is a custom route of a Config, served either by a handler registered in
+// ConfigOptions.Handlers or with a static response
+type ConfigRoute struct {
+	Path string `json:"path" yaml:"path"`
+	// Method restricts the route to an HTTP method; any method is accepted when empty
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	// Handler names the handler in ConfigOptions.Handlers serving the route
+	Handler string `json:"handler,omitempty" yaml:"handler,omitempty"`
+	// Status, ContentType and Body are the static response of routes without a Handler
+	// (default 200 and text/plain)
+	Status      int    `json:"status,omitempty" yaml:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty" yaml:"content_type,omitempty"`
+	Body        string `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ConfigOptions supplies what a config file cannot hold
+type ConfigOptions struct {
+	// Handlers are the custom route handlers config files refer to by name
+	Handlers map[string]http.HandlerFunc
+	// Configure adjusts the options read from the file before the app is created, e.g. to set
+	// a Logger or an Authorize function
+	Configure func(options *AppOptions)
+}
+
+// NewFromConfig creates an app configured by the YAML or JSON file at path, so deployments can
+// be configured without code changes
+func NewFromConfig(path string, options ...ConfigOptions) (*App, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	var configOptions ConfigOptions
+	if len(options) > 0 {
+		configOptions = options[0]
+	}
+	appOptions, err := config.AppOptions(configOptions)
+	if err != nil {
+		return nil, err
+	}
+	return New(appOptions)
+}
+
+// LoadConfig reads the YAML or JSON file at path, replacing environment variable references.
+// Unknown keys are rejected so typos do not go unnoticed.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig parses a YAML or JSON config, replacing environment variable references
+func ParseConfig(data []byte) (*Config, error) {
+	// JSON is valid YAML, so both are parsed as YAML
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	var missing []string
+	interpolateConfigNode(&document, &missing)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("config references unset environment variables: %s", strings.Join(missing, ", "))
+	}
+
+	// Decode the interpolated document again to check its keys
+	interpolated, err := yaml.Marshal(&document)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(interpolated))
+	decoder.KnownFields(true)
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return config, nil
+}
+
+// interpolateConfigNode replaces environment variable references in the scalars of node,
+// recording the names of unset variables without a default in missing
+func interpolateConfigNode(node *yaml.Node, missing *[]string) {
+	if node.Kind == yaml.ScalarNode && strings.Contains(node.Value, "${") {
+		whole := configEnvPattern.FindString(node.Value) == node.Value
+		node.Value = configEnvPattern.ReplaceAllStringFunc(node.Value, func(reference string) string {
+			match := configEnvPattern.FindStringSubmatch(reference)
+			if value, ok := os.LookupEnv(match[1]); ok {
+				return value
+			}
+			if match[2] == "" && !slices.Contains(*missing, match[1]) {
+				*missing = append(*missing, match[1])
+			}
+			return match[3]
+		})
+		// A value made of a single reference takes the type of the variable, e.g. port: ${PORT}
+		if whole {
+			node.Tag = ""
+			node.Style = 0
+		}
+	}
+	for _, child := range node.Content {
+		interpolateConfigNode(child, missing)
+	}
+}
+
+// AppOptions returns the app options described by the config
+func (c *Config) AppOptions(options ConfigOptions) (AppOptions, error) {
+	appOptions := AppOptions{
+		Token:                    c.Token,
+		AppToken:                 c.AppToken,
+		SigningSecret:            c.SigningSecret,
+		BotID:                    c.BotID,
+		BotUserID:                c.BotUserID,
+		ClientID:                 c.ClientID,
+		ClientSecret:             c.ClientSecret,
+		StateSecret:              c.StateSecret,
+		RedirectURI:              c.RedirectURI,
+		Scopes:                   c.Scopes,
+		Port:                     c.Port,
+		Endpoints:                c.Endpoints,
+		ProcessBeforeResponse:    c.ProcessBeforeResponse,
+		DeveloperMode:            c.DeveloperMode,
+		TokenVerificationEnabled: c.TokenVerificationEnabled,
+		IgnoreSelf:               c.IgnoreSelf,
+	}
+
+	switch c.Receiver {
+	case "", ConfigReceiverHTTP:
+	case ConfigReceiverSocketMode:
+		appOptions.SocketMode = true
+	default:
+		return AppOptions{}, fmt.Errorf("unknown receiver %q, expected %q or %q", c.Receiver, ConfigReceiverHTTP, ConfigReceiverSocketMode)
+	}
+
+	if c.LogLevel != "" {
+		level, ok := configLogLevels[strings.ToLower(c.LogLevel)]
+		if !ok {
+			return AppOptions{}, fmt.Errorf("unknown log level %q", c.LogLevel)
+		}
+		appOptions.LogLevel = &level
+	}
+
+	for _, route := range c.CustomRoutes {
+		customRoute, err := route.customRoute(options.Handlers)
+		if err != nil {
+			return AppOptions{}, err
+		}
+		appOptions.CustomRoutes = append(appOptions.CustomRoutes, customRoute)
+	}
+
+	if options.Configure != nil {
+		options.Configure(&appOptions)
+	}
+	return appOptions, nil
+}
+
+// customRoute returns the receiver route of r
+func (r ConfigRoute) customRoute(handlers map[string]http.HandlerFunc) (types.CustomRoute, error) {
+	if r.Path == "" {
+		return types.CustomRoute{}, errors.New("custom route without a path")
+	}
+
+	handler := r.staticHandler()
+	if r.Handler != "" {
+		var ok bool
+		if handler, ok = handlers[r.Handler]; !ok {
+			return types.CustomRoute{}, fmt.Errorf("custom route %s refers to unknown handler %q", r.Path, r.Handler)
+		}
+	}
+	if r.Method != "" {
+		handler = methodHandler(strings.ToUpper(r.Method), handler)
+	}
+	return types.CustomRoute{Path: r.Path, Method: strings.ToUpper(r.Method), Handler: handler}, nil
+}
+
+// staticHandler answers with the static response of r
+func (r ConfigRoute) staticHandler() http.HandlerFunc {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	contentType := r.ContentType
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(r.Body))
+	}
+}
+
+// methodHandler rejects requests to handler with another method than method
+func methodHandler(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		handler(w, req)
+	}
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// eventContextCustomCapacity sizes new Context.Custom maps for the keys set while processing an
+// event (eventType, body, middlewareArgs, channel, matches) plus a few authorize or middleware values
+const eventContextCustomCapacity = 8
+
+// eventContextPool holds event contexts released after processing when AppOptions.ReuseEventContexts is set
+var eventContextPool = sync.Pool{
+	New: func() interface{} {
+		return newEventContext()
+	},
+}
+
+// newEventContext creates an empty event context
+func newEventContext() *types.Context {
+	return &types.Context{
+		Custom: make(types.StringIndexed, eventContextCustomCapacity),
+	}
+}
+
+// acquireEventContext returns an empty event context, reusing a released one if reuse is enabled
+func (a *App) acquireEventContext() *types.Context {
+	if !a.reuseEventContexts {
+		return newEventContext()
+	}
+	return eventContextPool.Get().(*types.Context)
+}
+
+// releaseEventContext clears context and returns it to the pool if reuse is enabled
+func (a *App) releaseEventContext(context *types.Context) {
+	if !a.reuseEventContexts || context == nil {
+		return
+	}
+	context.Reset()
+	eventContextPool.Put(context)
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"strings"
+
+	"github.com/slack-go/slack"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/logging"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// CorrelationIDHeaders are the request headers, in order of precedence, a correlation ID is
+// propagated from when ProcessEvent's context does not carry one
+var CorrelationIDHeaders = []string{"X-Correlation-Id", "X-Request-Id"}
+
+// maxCorrelationIDLength bounds propagated IDs, which end up in logs and User-Agent headers
+const maxCorrelationIDLength = 128
+
+// correlationIDKey is the context key of the event's correlation ID
+type correlationIDKey struct{}
+
+// eventLoggerKey is the context key of the logger tagged with the event's correlation ID
+type eventLoggerKey struct{}
+
+// WithCorrelationID returns a copy of ctx carrying id. ProcessEvent uses it as the event's
+// correlation ID instead of reading headers or generating one.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, or "" if there is none
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// withCorrelation returns ctx carrying the event's correlation ID and a logger tagged with it
+func (a *App) withCorrelation(ctx context.Context, event types.ReceiverEvent) context.Context {
+	id := CorrelationIDFromContext(ctx)
+	if id == "" {
+		id = correlationIDFromHeaders(event.Headers)
+	}
+	if id == "" {
+		id = rand.Text()
+	}
+
+	ctx = WithCorrelationID(ctx, id)
+	return context.WithValue(ctx, eventLoggerKey{}, logging.With(a.Logger, bolterrors.LogKeyCorrelationID, id))
+}
+
+// eventLogger returns the logger tagged with the correlation ID of the event ctx belongs to
+func (a *App) eventLogger(ctx context.Context) types.Logger {
+	if logger, ok := ctx.Value(eventLoggerKey{}).(types.Logger); ok {
+		return logger
+	}
+	return a.Logger
+}
+
+// correlationIDFromHeaders returns the first valid ID found in CorrelationIDHeaders, ignoring
+// header name case
+func correlationIDFromHeaders(headers map[string]string) string {
+	for _, name := range CorrelationIDHeaders {
+		for key, value := range headers {
+			if strings.EqualFold(key, name) && validCorrelationID(value) {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// validCorrelationID reports whether id is short and limited to characters safe in logs and headers
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// correlatedHTTPClient sends Slack API calls with a User-Agent naming the event's correlation ID
+type correlatedHTTPClient struct {
+	base      *http.Client
+	userAgent string
+}
+
+// Do sends req with the correlated User-Agent
+func (c *correlatedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.userAgent)
+	return c.base.Do(req)
+}
+
+// eventClient returns a copy of client whose Slack API calls carry id in their User-Agent. The
+// app's client options are applied again, so an HTTP client set in AppOptions.ClientOptions
+// takes precedence and its calls are not tagged.
+func (a *App) eventClient(client *slack.Client, id string) *slack.Client {
+	if client == nil || id == "" {
+		return client
+	}
+
+	eventClient := *client
+	slack.OptionHTTPClient(&correlatedHTTPClient{
+		base:      a.apiHTTPClient,
+		userAgent: "bolt-go (correlation_id=" + id + ")",
+	})(&eventClient)
+	for _, option := range a.userClientOptions {
+		option(&eventClient)
+	}
+	return &eventClient
+}
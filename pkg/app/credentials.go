@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Defaults of CredentialsOptions
+const (
+	defaultCredentialsRefreshInterval = 5 * time.Minute
+	defaultSigningSecretWindow        = 5 * time.Minute
+)
+
+// Credentials are the secrets of an app that can be rotated while it runs
+type Credentials struct {
+	SigningSecret string
+	// Token is the bot token of a single-workspace app
+	Token string
+	// ClientSecret is the OAuth client secret used by the receiver's installer
+	ClientSecret string
+}
+
+// SecretProvider supplies the credentials of an app, e.g. from a secrets manager. Empty fields
+// leave the corresponding credentials unchanged.
+type SecretProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsOptions configures the rotation of credentials while the app runs
+type CredentialsOptions struct {
+	// Provider fills in the credentials missing from AppOptions when the app is created, and is
+	// then read every RefreshInterval for rotated credentials
+	Provider SecretProvider
+	// RefreshInterval is how often Provider is read (default 5m)
+	RefreshInterval time.Duration
+	// SigningSecretWindow is how long requests signed with a replaced signing secret are still
+	// accepted, so requests Slack signed before the rotation are not rejected (default 5m)
+	SigningSecretWindow time.Duration
+}
+
+// withCredentials returns options with the credentials missing from it taken from credentials.
+// The token is only used when no authorize function is set.
+func (options AppOptions) withCredentials(credentials Credentials) AppOptions {
+	if options.SigningSecret == "" {
+		options.SigningSecret = credentials.SigningSecret
+	}
+	if options.Token == "" && options.Authorize == nil {
+		options.Token = credentials.Token
+	}
+	if options.ClientSecret == "" {
+		options.ClientSecret = credentials.ClientSecret
+	}
+	return options
+}
+
+// UpdateCredentials swaps the app's credentials without restarting it; empty fields are left
+// unchanged. A new bot token is verified first when TokenVerificationEnabled is set, and then
+// used for the events processed from now on and by app.Client. A new signing secret is used
+// by the receiver right away, while requests signed with the previous one are still accepted
+// for CredentialsOptions.SigningSecretWindow.
+func (a *App) UpdateCredentials(ctx context.Context, credentials Credentials) error {
+	// Verify the token before taking the lock, so events are not held up by auth.test
+	newToken := credentials.Token != "" && credentials.Token != a.botToken()
+	var identity *BotIdentity
+	if newToken {
+		if a.botToken() == "" {
+			return errors.New("cannot set a token on an app using an authorize function")
+		}
+		if a.tokenVerificationEnabled {
+			var err error
+			if identity, err = a.verifyToken(ctx, credentials.Token); err != nil {
+				return err
+			}
+		}
+	}
+
+	a.credentialsMu.Lock()
+	defer a.credentialsMu.Unlock()
+	current := a.credentials
+
+	var client *slack.Client
+	if newToken {
+		client = slack.New(credentials.Token, a.clientOptions...)
+		current.Token = credentials.Token
+		a.identity = identity
+	}
+
+	update := types.CredentialUpdate{SigningSecretWindow: a.signingSecretWindow}
+	if update.SigningSecretWindow <= 0 {
+		update.SigningSecretWindow = defaultSigningSecretWindow
+	}
+	if credentials.SigningSecret != "" && credentials.SigningSecret != current.SigningSecret {
+		update.SigningSecret = credentials.SigningSecret
+		current.SigningSecret = credentials.SigningSecret
+	}
+	if credentials.ClientSecret != "" && credentials.ClientSecret != current.ClientSecret {
+		update.ClientSecret = credentials.ClientSecret
+		current.ClientSecret = credentials.ClientSecret
+	}
+	if update.SigningSecret != "" || update.ClientSecret != "" {
+		if a.credentialUpdater != nil {
+			a.credentialUpdater.UpdateCredentials(update)
+		} else {
+			a.Logger.Warn("Receiver does not support credential updates, it keeps its secrets")
+		}
+	}
+
+	if client != nil {
+		a.Client = client
+	}
+	if current != a.credentials {
+		a.credentials = current
+		a.Logger.Info("Credentials updated",
+			"token", client != nil,
+			"signing_secret", update.SigningSecret != "",
+			"client_secret", update.ClientSecret != "")
+	}
+	return nil
+}
+
+// RefreshCredentials reads CredentialsOptions.Provider and applies the credentials it returns,
+// e.g. right after rotating them instead of waiting for the next refresh
+func (a *App) RefreshCredentials(ctx context.Context) error {
+	if a.secretProvider == nil {
+		return errors.New("no secret provider configured")
+	}
+	credentials, err := a.secretProvider.Credentials(ctx)
+	if err != nil {
+		return err
+	}
+	return a.UpdateCredentials(ctx, credentials)
+}
+
+// botToken returns the current bot token of a single-workspace app
+func (a *App) botToken() string {
+	a.credentialsMu.RLock()
+	defer a.credentialsMu.RUnlock()
+	return a.credentials.Token
+}
+
+// signingSecret returns the current signing secret of the app
+func (a *App) signingSecret() string {
+	a.credentialsMu.RLock()
+	defer a.credentialsMu.RUnlock()
+	return a.credentials.SigningSecret
+}
+
+// client returns app.Client, which is replaced when the bot token is rotated
+func (a *App) client() *slack.Client {
+	a.credentialsMu.RLock()
+	defer a.credentialsMu.RUnlock()
+	return a.Client
+}
+
+// credentialRefresher reads the secret provider periodically in the background
+type credentialRefresher struct {
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startCredentialRefresh starts refreshing the credentials every interval, or returns nil when
+// no secret provider is configured
+func (a *App) startCredentialRefresh(interval time.Duration) *credentialRefresher {
+	if a.secretProvider == nil {
+		return nil
+	}
+	if interval <= 0 {
+		interval = defaultCredentialsRefreshInterval
+	}
+
+	r := &credentialRefresher{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				if err := a.RefreshCredentials(ctx); err != nil {
+					a.Logger.Warn("Failed to refresh credentials", bolterrors.LogKeyError, err)
+				}
+				cancel()
+			}
+		}
+	}()
+	return r
+}
+
+// close stops refreshing and waits until a refresh in progress ends or ctx is done
+func (r *credentialRefresher) close(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	r.stopOnce.Do(func() { close(r.stop) })
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
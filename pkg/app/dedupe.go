@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// DefaultDedupeTTL is how long an event is remembered by default, covering Slack's Events API
+// retries, the last of which comes about five minutes after the first delivery
+const DefaultDedupeTTL = time.Hour
+
+// EventDeduper remembers the events the app processed, so an event delivered again, as a Slack
+// retry or to another replica of the app, is acknowledged without running listeners twice. Keys
+// are "event:<event_id>" for the Events API and "envelope:<envelope_id>" for other Socket Mode
+// requests; requests without either are never deduplicated. See pkg/dedupe for Redis.
+type EventDeduper interface {
+	// Seen records key and reports whether it was already recorded. Recording and checking must
+	// be atomic, so only one of several concurrent calls with the same key reports false.
+	Seen(ctx context.Context, key string) (bool, error)
+	// Forget removes key, so an event that failed with a retryable error is processed when
+	// Slack delivers it again
+	Forget(ctx context.Context, key string) error
+}
+
+// MemoryEventDeduper is an EventDeduper keeping keys in memory. It only deduplicates events
+// delivered to the same process; use a shared store such as Redis when running several replicas.
+type MemoryEventDeduper struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	expiresAt map[string]time.Time
+	// sweepAt is the number of keys at which expired keys are removed next
+	sweepAt int
+}
+
+// memoryDeduperMinSweep is the number of keys below which expired keys are left in place
+const memoryDeduperMinSweep = 1024
+
+var _ EventDeduper = (*MemoryEventDeduper)(nil)
+
+// NewMemoryEventDeduper returns a MemoryEventDeduper remembering keys for ttl (default
+// DefaultDedupeTTL)
+func NewMemoryEventDeduper(ttl time.Duration) *MemoryEventDeduper {
+	if ttl <= 0 {
+		ttl = DefaultDedupeTTL
+	}
+	return &MemoryEventDeduper{
+		ttl:       ttl,
+		expiresAt: make(map[string]time.Time),
+		sweepAt:   memoryDeduperMinSweep,
+	}
+}
+
+// Seen records key and reports whether it was recorded and had not expired yet
+func (d *MemoryEventDeduper) Seen(ctx context.Context, key string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, exists := d.expiresAt[key]; exists && now.Before(expiresAt) {
+		return true, nil
+	}
+	d.expiresAt[key] = now.Add(d.ttl)
+	if len(d.expiresAt) >= d.sweepAt {
+		for k, expiresAt := range d.expiresAt {
+			if !now.Before(expiresAt) {
+				delete(d.expiresAt, k)
+			}
+		}
+		d.sweepAt = max(2*len(d.expiresAt), memoryDeduperMinSweep)
+	}
+	return false, nil
+}
+
+// Forget removes key
+func (d *MemoryEventDeduper) Forget(ctx context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.expiresAt, key)
+	return nil
+}
+
+// dedupeKey returns the key event is deduplicated by, or "" if it has no stable ID
+func dedupeKey(event types.ReceiverEvent, jsonBody map[string]interface{}) string {
+	if eventID, ok := jsonBody["event_id"].(string); ok && eventID != "" {
+		return "event:" + eventID
+	}
+	for name, value := range event.Headers {
+		if strings.EqualFold(name, types.EnvelopeIDHeader) && value != "" {
+			return "envelope:" + value
+		}
+	}
+	return ""
+}
+
+// claimEvent records event with the deduper and reports whether it is a duplicate to drop. The
+// returned function, called with the result of processing, forgets the event if processing
+// failed with a retryable error. Deduper errors are logged and the event is processed.
+func (a *App) claimEvent(ctx context.Context, event types.ReceiverEvent, jsonBody map[string]interface{}) (bool, func(error)) {
+	settle := func(error) {}
+	if a.deduper == nil {
+		return false, settle
+	}
+	key := dedupeKey(event, jsonBody)
+	if key == "" {
+		return false, settle
+	}
+
+	logger := a.eventLogger(ctx)
+	duplicate, err := a.deduper.Seen(ctx, key)
+	if err != nil {
+		logger.Warn("Event deduplication failed, processing the event", bolterrors.LogKeyError, err, "dedupe_key", key)
+		return false, settle
+	}
+	if duplicate {
+		return true, settle
+	}
+	return false, func(err error) {
+		if !bolterrors.IsRetryable(err) {
+			return
+		}
+		if forgetErr := a.deduper.Forget(context.WithoutCancel(ctx), key); forgetErr != nil {
+			logger.Warn("Failed to forget deduplicated event", bolterrors.LogKeyError, forgetErr, "dedupe_key", key)
+		}
+	}
+}
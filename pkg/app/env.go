@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+)
+
+// Environment variables read by NewFromEnv
+const (
+	EnvBotToken      = "SLACK_BOT_TOKEN"
+	EnvAppToken      = "SLACK_APP_TOKEN"
+	EnvSigningSecret = "SLACK_SIGNING_SECRET"
+	EnvClientID      = "SLACK_CLIENT_ID"
+	EnvClientSecret  = "SLACK_CLIENT_SECRET"
+	EnvStateSecret   = "SLACK_STATE_SECRET"
+	EnvRedirectURI   = "SLACK_REDIRECT_URI"
+	// EnvScopes is a comma-separated list of bot scopes
+	EnvScopes = "SLACK_SCOPES"
+	// EnvLogLevel is "debug", "info", "warn" or "error"
+	EnvLogLevel = "SLACK_LOG_LEVEL"
+	// EnvPort is the port of the HTTP receiver
+	EnvPort = "PORT"
+)
+
+// NewFromEnv creates an app configured by the standard SLACK_* environment variables, on top of
+// the optional options; options that are already set take precedence over the environment.
+// The app uses Socket Mode when SLACK_APP_TOKEN is set and no receiver is given, and the HTTP
+// receiver otherwise. SLACK_BOT_TOKEN is neither read nor required when options authorize events
+// with Authorize, InstallationStore or a Credentials.Provider. All missing or invalid variables
+// are reported in a single error.
+func NewFromEnv(options ...AppOptions) (*App, error) {
+	var appOptions AppOptions
+	if len(options) > 0 {
+		appOptions = options[0]
+	}
+	appOptions, err := OptionsFromEnv(appOptions)
+	if err != nil {
+		return nil, err
+	}
+	return New(appOptions)
+}
+
+// OptionsFromEnv returns options with the fields left empty filled in from the standard SLACK_*
+// environment variables, see NewFromEnv
+func OptionsFromEnv(options AppOptions) (AppOptions, error) {
+	var missing, invalid []string
+	env := func(value *string, name string) {
+		if *value == "" {
+			*value = os.Getenv(name)
+		}
+	}
+
+	// The bot token only authorizes single-workspace apps; apps authorizing events otherwise
+	// neither read nor require it, since New rejects it alongside their authorization
+	singleWorkspace := options.Authorize == nil && options.InstallationStore == nil && options.Credentials.Provider == nil
+	if singleWorkspace {
+		env(&options.Token, EnvBotToken)
+	}
+	env(&options.AppToken, EnvAppToken)
+	env(&options.SigningSecret, EnvSigningSecret)
+	env(&options.ClientID, EnvClientID)
+	env(&options.ClientSecret, EnvClientSecret)
+	env(&options.StateSecret, EnvStateSecret)
+	env(&options.RedirectURI, EnvRedirectURI)
+
+	if scopes := os.Getenv(EnvScopes); len(options.Scopes) == 0 && scopes != "" {
+		for _, scope := range strings.Split(scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				options.Scopes = append(options.Scopes, scope)
+			}
+		}
+	}
+	if level := os.Getenv(EnvLogLevel); options.LogLevel == nil && level != "" {
+		if logLevel, ok := configLogLevels[strings.ToLower(level)]; ok {
+			options.LogLevel = &logLevel
+		} else {
+			invalid = append(invalid, fmt.Sprintf("%s=%q is not debug, info, warn or error", EnvLogLevel, level))
+		}
+	}
+	if port := os.Getenv(EnvPort); options.Port == 0 && port != "" {
+		if portNum, err := strconv.Atoi(port); err == nil && portNum > 0 && portNum < 65536 {
+			options.Port = portNum
+		} else {
+			invalid = append(invalid, fmt.Sprintf("%s=%q is not a port number", EnvPort, port))
+		}
+	}
+
+	// Select the receiver from the tokens available
+	if options.Receiver == nil {
+		if options.AppToken != "" {
+			options.SocketMode = true
+		}
+		if options.SocketMode && options.AppToken == "" {
+			missing = append(missing, EnvAppToken)
+		}
+		if !options.SocketMode && options.SigningSecret == "" && options.Credentials.Provider == nil {
+			missing = append(missing, EnvSigningSecret)
+		}
+	}
+	if options.Token == "" && singleWorkspace {
+		missing = append(missing, EnvBotToken)
+	}
+	if options.ClientID != "" && options.ClientSecret == "" && options.Credentials.Provider == nil {
+		missing = append(missing, EnvClientSecret)
+	}
+	if options.ClientSecret != "" && options.ClientID == "" {
+		missing = append(missing, EnvClientID)
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing environment variables: "+strings.Join(missing, ", "))
+	}
+	problems = append(problems, invalid...)
+	if len(problems) > 0 {
+		return options, bolterrors.NewAppInitializationError(strings.Join(problems, "; "))
+	}
+	return options, nil
+}
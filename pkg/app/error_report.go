@@ -0,0 +1,154 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// ErrorReporter sends the errors reaching the default error handler to an error-reporting or
+// alerting service; see pkg/errorreport for Sentry. ReportError is called on the event's
+// goroutine, so implementations should hand the report off rather than block on the network.
+// If the reporter also has a Close(ctx context.Context) error method, App.Stop calls it to flush
+// pending reports.
+type ErrorReporter interface {
+	ReportError(ctx context.Context, report ErrorReport)
+}
+
+// ErrorReport describes an unhandled error and the request it was raised for
+type ErrorReport struct {
+	Err  error
+	Code bolterrors.ErrorCode
+	// Listeners describe the listeners that failed, such as "event(type=app_mention)"
+	Listeners     []string
+	CorrelationID string
+	// Type is the class of the request, such as "event", "command" or "action"
+	Type string
+	// EventType is the Events API event type, such as "app_mention", for Type "event"
+	EventType    string
+	TeamID       string
+	EnterpriseID string
+	UserID       string
+	RetryNum     int
+	// Payload summarizes the request with identifying fields such as its callback_id, action_id
+	// or command, leaving out message text and tokens
+	Payload map[string]string
+}
+
+// errorReporterCloser is implemented by reporters flushing pending reports when the app stops
+type errorReporterCloser interface {
+	Close(ctx context.Context) error
+}
+
+// newErrorReport builds the report of err, raised while processing event
+func newErrorReport(ctx context.Context, err error, appContext *types.Context, event types.ReceiverEvent) ErrorReport {
+	parsed := helpers.ParseRequestBody(event.Body)
+	report := ErrorReport{
+		Err:           err,
+		Code:          bolterrors.AsCodedError(err).Code(),
+		CorrelationID: CorrelationIDFromContext(ctx),
+		RetryNum:      event.RetryNum,
+		Payload:       summarizePayload(parsed),
+	}
+
+	if incomingType := helpers.GetTypeAndConversationFromParsed(parsed).Type; incomingType != nil {
+		report.Type = incomingType.String()
+		if *incomingType == helpers.IncomingEventTypeEvent {
+			report.EventType = helpers.ExtractEventTypeFromParsed(parsed)
+		}
+	}
+	if appContext != nil {
+		report.TeamID = appContext.TeamID
+		report.EnterpriseID = appContext.EnterpriseID
+		report.UserID = appContext.UserID
+	}
+	if report.TeamID == "" {
+		if teamID := helpers.ExtractTeamIDFromParsed(parsed); teamID != nil {
+			report.TeamID = *teamID
+		}
+	}
+	if report.UserID == "" {
+		if userID := helpers.ExtractUserIDFromParsed(parsed); userID != nil {
+			report.UserID = *userID
+		}
+	}
+
+	var multipleErr *bolterrors.MultipleListenerError
+	var listenerErr *bolterrors.ListenerError
+	switch {
+	case errors.As(err, &multipleErr):
+		for _, failure := range multipleErr.Failures() {
+			report.Listeners = append(report.Listeners, failure.Listener)
+		}
+	case errors.As(err, &listenerErr):
+		report.Listeners = []string{listenerErr.Listener}
+	}
+	return report
+}
+
+// payloadSummaryFields are the request fields copied to ErrorReport.Payload, by path
+var payloadSummaryFields = []struct {
+	key  string
+	path []string
+}{
+	{"type", []string{"type"}},
+	{"api_app_id", []string{"api_app_id"}},
+	{"event_type", []string{"event", "type"}},
+	{"event_subtype", []string{"event", "subtype"}},
+	{"command", []string{"command"}},
+	{"callback_id", []string{"callback_id"}},
+	{"callback_id", []string{"view", "callback_id"}},
+	{"action_id", []string{"actions", "0", "action_id"}},
+	{"action_id", []string{"action_id"}},
+	{"block_id", []string{"actions", "0", "block_id"}},
+	{"block_id", []string{"block_id"}},
+	{"channel_id", []string{"channel_id"}},
+	{"channel_id", []string{"channel", "id"}},
+	{"channel_id", []string{"event", "channel"}},
+}
+
+// summarizePayload copies the identifying fields of a parsed request body
+func summarizePayload(parsed map[string]interface{}) map[string]string {
+	// Interactions arrive as a form with the JSON payload in one field
+	if payload, ok := parsed["payload"].(string); ok {
+		var decoded map[string]interface{}
+		if json.Unmarshal([]byte(payload), &decoded) == nil {
+			parsed = decoded
+		}
+	}
+
+	summary := make(map[string]string)
+	for _, field := range payloadSummaryFields {
+		if _, exists := summary[field.key]; exists {
+			continue
+		}
+		if value, ok := lookupPath(parsed, field.path).(string); ok && value != "" {
+			summary[field.key] = value
+		}
+	}
+	return summary
+}
+
+// lookupPath returns the value at path in a decoded JSON value, indexing arrays by number
+func lookupPath(value interface{}, path []string) interface{} {
+	for _, key := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			value = v[i]
+		default:
+			return nil
+		}
+	}
+	return value
+}
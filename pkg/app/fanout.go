@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// defaultFanOutBufferSize is the number of messages queued for the sink before new ones are dropped
+const defaultFanOutBufferSize = 1024
+
+// FanOutSink receives every verified request the app receives, so out-of-process workers can
+// consume Slack traffic from a message queue; see pkg/fanout for Kafka, NATS and SQS. Publish is
+// called from a single background goroutine, one message at a time.
+type FanOutSink interface {
+	Publish(ctx context.Context, message FanOutMessage) error
+}
+
+// FanOutOptions enables forwarding of incoming requests to a message queue
+type FanOutOptions struct {
+	// Sink receives the messages; fan-out is off when it is nil
+	Sink FanOutSink
+	// BufferSize is the number of messages queued while the sink is busy (default 1024).
+	// Messages arriving while the queue is full are dropped and logged so acknowledging Slack
+	// never waits on the sink.
+	BufferSize int
+}
+
+// FanOutMessage is an incoming request forwarded to a FanOutSink once it was acknowledged
+type FanOutMessage struct {
+	// ID is the correlation ID of the request
+	ID string `json:"id"`
+	// EventID is the Events API event_id, the same for every retry of an event
+	EventID    string    `json:"event_id,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+	// Type is the class of the request, such as "event", "command" or "action"; empty when the
+	// request could not be classified
+	Type string `json:"type,omitempty"`
+	// EventType is the Events API event type, such as "app_mention", for Type "event"
+	EventType    string `json:"event_type,omitempty"`
+	TeamID       string `json:"team_id,omitempty"`
+	EnterpriseID string `json:"enterprise_id,omitempty"`
+	RetryNum     int    `json:"retry_num,omitempty"`
+	RetryReason  string `json:"retry_reason,omitempty"`
+	// ContentType is the content type of Body, application/json or application/x-www-form-urlencoded
+	ContentType string `json:"content_type,omitempty"`
+	// Body is the request body, exactly as received
+	Body string `json:"body"`
+}
+
+// fanOut queues incoming requests and hands them to the sink in the background
+type fanOut struct {
+	sink     FanOutSink
+	logger   types.Logger
+	messages chan FanOutMessage
+	done     chan struct{}
+
+	// mu guards closed so no message is queued after the queue is closed
+	mu     sync.RWMutex
+	closed bool
+}
+
+// newFanOut starts the fan-out goroutine, or returns nil when fan-out is off
+func newFanOut(options FanOutOptions, logger types.Logger) *fanOut {
+	if options.Sink == nil {
+		return nil
+	}
+	size := options.BufferSize
+	if size <= 0 {
+		size = defaultFanOutBufferSize
+	}
+
+	f := &fanOut{
+		sink:     options.Sink,
+		logger:   logger,
+		messages: make(chan FanOutMessage, size),
+		done:     make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// run publishes queued messages until the queue is closed
+func (f *fanOut) run() {
+	defer close(f.done)
+	for message := range f.messages {
+		if err := f.sink.Publish(context.Background(), message); err != nil {
+			f.logger.Warn("Fan-out sink failed", bolterrors.LogKeyError, err, bolterrors.LogKeyCorrelationID, message.ID)
+		}
+	}
+}
+
+// forwardOnAck returns event with an Ack forwarding it once acknowledged, and the function to
+// call when processing ends, forwarding it if nothing acknowledged it. Events whose ack failed
+// are not forwarded, since Slack will retry them.
+func (f *fanOut) forwardOnAck(ctx context.Context, event types.ReceiverEvent) (types.ReceiverEvent, func()) {
+	if f == nil {
+		return event, func() {}
+	}
+
+	var acked atomic.Bool
+	var once sync.Once
+	received := time.Now()
+	forward := func() {
+		once.Do(func() { f.enqueue(newFanOutMessage(ctx, event, received)) })
+	}
+	if ack := event.Ack; ack != nil {
+		event.Ack = func(response types.AckResponse) error {
+			acked.Store(true)
+			err := ack(response)
+			if err == nil {
+				forward()
+			}
+			return err
+		}
+	}
+	return event, func() {
+		if !acked.Load() {
+			forward()
+		}
+	}
+}
+
+// enqueue queues message, dropping it if the queue is full
+func (f *fanOut) enqueue(message FanOutMessage) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.closed {
+		return
+	}
+	select {
+	case f.messages <- message:
+	default:
+		f.logger.Warn("Fan-out queue full, dropped message", bolterrors.LogKeyCorrelationID, message.ID)
+	}
+}
+
+// close stops accepting messages and waits until queued messages are published or ctx is done
+func (f *fanOut) close(ctx context.Context) error {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.messages)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newFanOutMessage describes event for the sink
+func newFanOutMessage(ctx context.Context, event types.ReceiverEvent, received time.Time) FanOutMessage {
+	parsed := helpers.ParseRequestBody(event.Body)
+	message := FanOutMessage{
+		ID:          CorrelationIDFromContext(ctx),
+		ReceivedAt:  received,
+		RetryNum:    event.RetryNum,
+		RetryReason: event.RetryReason,
+		ContentType: "application/x-www-form-urlencoded",
+		Body:        string(event.Body),
+	}
+	if len(event.Body) > 0 && event.Body[0] == '{' {
+		message.ContentType = "application/json"
+	}
+	if eventID, ok := parsed["event_id"].(string); ok {
+		message.EventID = eventID
+	}
+	if incomingType := helpers.GetTypeAndConversationFromParsed(parsed).Type; incomingType != nil {
+		message.Type = incomingType.String()
+		if *incomingType == helpers.IncomingEventTypeEvent {
+			message.EventType = helpers.ExtractEventTypeFromParsed(parsed)
+		}
+	}
+	if teamID := helpers.ExtractTeamIDFromParsed(parsed); teamID != nil {
+		message.TeamID = *teamID
+	}
+	if enterpriseID := helpers.ExtractEnterpriseIDFromParsed(parsed); enterpriseID != nil {
+		message.EnterpriseID = *enterpriseID
+	}
+	return message
+}
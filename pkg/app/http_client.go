@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+const (
+	// defaultHTTPClientTimeout bounds each response_url or webhook post
+	defaultHTTPClientTimeout = 30 * time.Second
+	// defaultHTTPMaxIdleConnsPerHost keeps enough connections to hooks.slack.com open for bursts of posts
+	defaultHTTPMaxIdleConnsPerHost = 16
+)
+
+// HTTPClientOptions configures the HTTP client shared by respond() and webhook posts when
+// AppOptions.HTTPClient is not set
+type HTTPClientOptions struct {
+	// Timeout bounds each request, including reading the response (default 30s)
+	Timeout time.Duration
+	// Proxy selects the proxy for each request (default http.ProxyFromEnvironment)
+	Proxy func(*http.Request) (*url.URL, error)
+	// MaxIdleConnsPerHost is the number of keep-alive connections kept per host (default 16)
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes keep-alive connections idle for longer than this (default 90s)
+	IdleConnTimeout time.Duration
+}
+
+// newHTTPClient creates a connection-pooled HTTP client from options
+func newHTTPClient(options HTTPClientOptions) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = defaultHTTPMaxIdleConnsPerHost
+	if options.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+	}
+	if options.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = options.IdleConnTimeout
+	}
+	if options.Proxy != nil {
+		transport.Proxy = options.Proxy
+	}
+
+	timeout := defaultHTTPClientTimeout
+	if options.Timeout > 0 {
+		timeout = options.Timeout
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
+
+// DefaultResponseURLHosts are the hosts respond() and SendWebhook post to when
+// AppOptions.ResponseURLHosts is empty
+var DefaultResponseURLHosts = helpers.DefaultResponseURLHosts
+
+// HTTPClient returns the HTTP client shared by respond() and webhook posts
+func (a *App) HTTPClient() *http.Client {
+	return a.httpClient
+}
+
+// SendWebhook posts message to an incoming webhook URL, such as the one stored with an
+// installation, using the app's shared HTTP client
+func (a *App) SendWebhook(ctx context.Context, webhookURL string, message types.RespondMessage) (*types.RespondResponse, error) {
+	return a.createRespondFunction(ctx, webhookURL)(ctx, message)
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/slack-go/slack"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+)
+
+// BotIdentity is who the bot token of a single-workspace app acts as, as reported by auth.test
+type BotIdentity struct {
+	BotID        string
+	BotUserID    string
+	TeamID       string
+	Team         string
+	EnterpriseID string
+	URL          string
+}
+
+// BotIdentity returns the identity of the bot token, verified when the app started or its token
+// was updated. It returns false unless TokenVerificationEnabled is set and the app has a token
+// that was verified.
+func (a *App) BotIdentity() (BotIdentity, bool) {
+	a.credentialsMu.RLock()
+	defer a.credentialsMu.RUnlock()
+	if a.identity == nil {
+		return BotIdentity{}, false
+	}
+	return *a.identity, true
+}
+
+// verifyBotToken verifies the bot token of a single-workspace app with auth.test and caches the
+// identity it reports, when TokenVerificationEnabled is set
+func (a *App) verifyBotToken(ctx context.Context) error {
+	token := a.botToken()
+	if !a.tokenVerificationEnabled || token == "" {
+		return nil
+	}
+
+	identity, err := a.verifyToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	a.credentialsMu.Lock()
+	defer a.credentialsMu.Unlock()
+	if a.credentials.Token == token {
+		a.identity = identity
+	}
+	return nil
+}
+
+// verifyToken runs auth.test for token and returns the identity of the bot it belongs to, or an
+// *errors.AppInitializationError describing why the token cannot be used
+func (a *App) verifyToken(ctx context.Context, token string) (*BotIdentity, error) {
+	result, err := a.AuthTest(ctx, token)
+	if err != nil {
+		return nil, tokenVerificationError(err)
+	}
+	if result.BotID == "" {
+		return nil, bolterrors.NewAppInitializationError("token verification failed: auth.test reports no bot_id, so the token is not a bot token; use the Bot User OAuth Token (xoxb-) of the app")
+	}
+
+	return &BotIdentity{
+		BotID:        result.BotID,
+		BotUserID:    result.UserID,
+		TeamID:       result.TeamID,
+		Team:         result.Team,
+		EnterpriseID: result.EnterpriseID,
+		URL:          result.URL,
+	}, nil
+}
+
+// tokenVerificationError describes why auth.test rejected a token
+func tokenVerificationError(err error) error {
+	message := fmt.Sprintf("token verification failed: %v", err)
+
+	var slackErr slack.SlackErrorResponse
+	if errors.As(err, &slackErr) {
+		switch slackErr.Err {
+		case "invalid_auth", "not_authed", "token_revoked", "token_expired", "account_inactive":
+			message += ": the token is invalid, expired or was revoked, e.g. because the app was uninstalled; reinstall the app and use its new token"
+		case "missing_scope", "not_allowed_token_type", "no_permission":
+			message += ": the token lacks the scopes or type the app needs; reinstall the app with its bot scopes and use its bot token"
+		}
+	}
+
+	return &bolterrors.AppInitializationError{
+		BaseError: bolterrors.NewBaseErrorWithOriginal(bolterrors.AppInitializationErrorCode, message, err),
+	}
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+)
+
+// AuthorizeWithInstallationStore returns an AuthorizeFunc resolving the tokens of each event from
+// the installations in store, as AppOptions.InstallationStore does. Events from an Enterprise Grid
+// org use the org-wide installation when there is one, so events arriving from any workspace of
+// the org share it; otherwise they use the installation of their workspace. The user token is the
+// one the acting user installed the app with, when they did.
+func AuthorizeWithInstallationStore(store oauth.InstallationStore) AuthorizeFunc {
+	return func(ctx context.Context, source AuthorizeSourceData, body interface{}) (*AuthorizeResult, error) {
+		query, installation, err := fetchSourceInstallation(ctx, store, source)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &AuthorizeResult{
+			BotToken:            installation.BotToken,
+			BotID:               installation.BotID,
+			BotUserID:           installation.BotUserID,
+			UserID:              source.UserID,
+			TeamID:              source.TeamID,
+			EnterpriseID:        source.EnterpriseID,
+			IsEnterpriseInstall: installation.IsEnterpriseInstall,
+			APIURL:              installation.APIURL,
+		}
+		if installation.Bot != nil {
+			if result.BotToken == "" {
+				result.BotToken = installation.Bot.AccessToken
+			}
+			if result.BotID == "" {
+				result.BotID = installation.Bot.ID
+			}
+			if result.BotUserID == "" {
+				result.BotUserID = installation.Bot.UserID
+			}
+		}
+		if result.TeamID == "" && installation.Team != nil {
+			result.TeamID = installation.Team.ID
+		}
+		if result.EnterpriseID == "" && installation.Enterprise != nil {
+			result.EnterpriseID = installation.Enterprise.ID
+		}
+
+		if source.UserID != "" {
+			query.UserID = source.UserID
+			userInstallation, err := store.FetchInstallation(ctx, query)
+			if err != nil && !errors.Is(err, oauth.ErrInstallationNotFound) {
+				return nil, err
+			}
+			if err == nil {
+				result.UserToken, result.UserScopes = installationUserToken(userInstallation, source.UserID)
+			}
+		}
+
+		if result.BotToken == "" && result.UserToken == "" {
+			return nil, errors.New("installation has neither a bot token nor a user token")
+		}
+		return result, nil
+	}
+}
+
+// fetchSourceInstallation fetches the installation events from source are authorized with and
+// returns the query that found it. The org-wide installation is preferred for events of an org;
+// the workspace installation is only used when the event is not from an org-wide install.
+func fetchSourceInstallation(ctx context.Context, store oauth.InstallationStore, source AuthorizeSourceData) (oauth.InstallationQuery, *oauth.Installation, error) {
+	if source.EnterpriseID != "" {
+		query := oauth.InstallationQuery{EnterpriseID: source.EnterpriseID, IsEnterpriseInstall: true}
+		installation, err := store.FetchInstallation(ctx, query)
+		if err == nil || !errors.Is(err, oauth.ErrInstallationNotFound) || source.IsEnterpriseInstall || source.TeamID == "" {
+			return query, installation, err
+		}
+	}
+
+	query := oauth.InstallationQuery{EnterpriseID: source.EnterpriseID, TeamID: source.TeamID}
+	installation, err := store.FetchInstallation(ctx, query)
+	return query, installation, err
+}
+
+// installationUserToken returns the token and scopes userID granted with installation, which
+// stores without per-user installations may return for another user
+func installationUserToken(installation *oauth.Installation, userID string) (string, []string) {
+	if installation.AuthedUser != nil && installation.AuthedUser.ID == userID && installation.AuthedUser.AccessToken != "" {
+		return installation.AuthedUser.AccessToken, installation.AuthedUser.Scopes()
+	}
+	if installation.User != nil && installation.User.ID == userID && installation.User.AccessToken != "" {
+		return installation.User.AccessToken, installation.UserScopes
+	}
+	return "", nil
+}
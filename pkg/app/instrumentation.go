@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Instrumentation observes receivers, event processing, authorization and outgoing HTTP calls,
+// for tracing and metrics integrations such as pkg/observability/otel. The Start methods return
+// the context to continue with and a function called once with the outcome.
+type Instrumentation interface {
+	// WrapReceiver returns the receiver the app uses in place of receiver
+	WrapReceiver(receiver types.Receiver) types.Receiver
+	// WrapHTTPClient returns the client used for Slack API calls and response_url posts in
+	// place of client. A nil client stands for http.DefaultClient.
+	WrapHTTPClient(client *http.Client) *http.Client
+	// StartProcessEvent is called when ProcessEvent starts
+	StartProcessEvent(ctx context.Context, event types.ReceiverEvent) (context.Context, func(info ProcessEventInfo, err error))
+	// StartAuthorize is called before the authorize function runs for an event
+	StartAuthorize(ctx context.Context, source AuthorizeSourceData) (context.Context, func(err error))
+}
+
+// ProcessEventInfo describes what ProcessEvent learned about an event, for Instrumentation
+type ProcessEventInfo struct {
+	// Type is the class of the incoming request, such as "event", "command" or "action"; empty
+	// when the request could not be classified
+	Type string
+	// EventType is the Events API event type, such as "app_mention", for Type "event"
+	EventType    string
+	TeamID       string
+	EnterpriseID string
+	UserID       string
+	// Handled reports whether middleware or listeners were registered for the event
+	Handled bool
+	// Listeners are the outcomes of the listeners that ran, in registration order
+	Listeners []ListenerOutcome
+}
+
+// ListenerOutcome is the result of running one listener for an event
+type ListenerOutcome struct {
+	// Listener describes the listener, such as "event(type=app_mention)"
+	Listener string
+	// Error is the error the listener returned, nil if it succeeded
+	Error error
+}
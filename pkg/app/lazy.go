@@ -0,0 +1,303 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// LazyListenerHeader is the request header naming the lazy function a request re-invoking the
+// app runs, see LazyListenerRequest.InvocationHeaders
+const LazyListenerHeader = "X-Slack-Bolt-Lazy-Function"
+
+// LazyListenerSignatureHeader is the request header authenticating a request re-invoking the app,
+// since Slack's signature of the request does not cover LazyListenerHeader
+const LazyListenerSignatureHeader = "X-Slack-Bolt-Lazy-Signature"
+
+// LazyListenerRequest is a lazy function of a listener to run after the request was acknowledged
+type LazyListenerRequest struct {
+	// ID identifies the lazy function among those of the app. It is the same in every process
+	// running the same app code.
+	ID string
+	// Body and Headers are those of the request the listener acknowledged
+	Body    []byte
+	Headers map[string]string
+	// Signature authenticates the request re-invoking the app to run the lazy function. It is
+	// signed with the app's signing secret, and empty when the app has none, in which case the
+	// app rejects re-invocations.
+	Signature string
+	// Run runs the lazy function in this process with the listener's args. ctx replaces the
+	// args' Ctx, which is done once the request was answered.
+	Run func(ctx context.Context) error
+}
+
+// InvocationHeaders returns the headers of a request re-invoking the app to run the lazy function,
+// e.g. from another Lambda invocation. The app acknowledges such requests at once and only runs
+// the lazy function for them, after global middleware and authorization. Requests naming a lazy
+// function without a valid Signature are rejected with a ReceiverAuthenticityError.
+func (r LazyListenerRequest) InvocationHeaders() map[string]string {
+	headers := maps.Clone(r.Headers)
+	if headers == nil {
+		headers = make(map[string]string, 2)
+	}
+	headers[LazyListenerHeader] = r.ID
+	if r.Signature != "" {
+		headers[LazyListenerSignatureHeader] = r.Signature
+	}
+	return headers
+}
+
+// LazyListenerRunner starts the lazy functions of listeners. Start must not wait for the function
+// to finish: it either runs request.Run in the background or hands the request to another process,
+// e.g. by asynchronously invoking the Lambda function with the body and InvocationHeaders.
+type LazyListenerRunner interface {
+	Start(ctx context.Context, request LazyListenerRequest) error
+}
+
+// CommandLazy adds a command listener for FaaS deployments that must acknowledge within three
+// seconds but run slow logic: ack runs first and acknowledges the command, then each lazy
+// function is started with the LazyListenerRunner, in a goroutine by default. Lazy functions get
+// the same args; they must not call Ack, as the command was already acknowledged.
+func (a *App) CommandLazy(command string, ack types.Middleware[types.SlackCommandMiddlewareArgs], lazy ...types.Middleware[types.SlackCommandMiddlewareArgs]) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	listener := &listenerEntry{
+		eventType: helpers.IncomingEventTypeCommand,
+		constraints: listenerConstraints{
+			command: command,
+		},
+	}
+	a.addLazyListener(listener, a.wrapCommandMiddleware(ack), wrapLazy(a.wrapCommandMiddleware, lazy))
+	return a
+}
+
+// ActionLazy adds an action listener whose lazy functions run after ack, see CommandLazy
+func (a *App) ActionLazy(constraints types.ActionConstraints, ack types.Middleware[types.SlackActionMiddlewareArgs], lazy ...types.Middleware[types.SlackActionMiddlewareArgs]) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	listener := &listenerEntry{
+		eventType: helpers.IncomingEventTypeAction,
+		constraints: listenerConstraints{
+			actionID:          constraints.ActionID,
+			blockID:           constraints.BlockID,
+			callbackID:        constraints.CallbackID,
+			actionType:        constraints.Type,
+			actionIDPattern:   constraints.ActionIDPattern,
+			blockIDPattern:    constraints.BlockIDPattern,
+			callbackIDPattern: constraints.CallbackIDPattern,
+		},
+	}
+	a.addLazyListener(listener, a.wrapActionMiddleware(ack), wrapLazy(a.wrapActionMiddleware, lazy))
+	return a
+}
+
+// ShortcutLazy adds a shortcut listener whose lazy functions run after ack, see CommandLazy
+func (a *App) ShortcutLazy(constraints types.ShortcutConstraints, ack types.Middleware[types.SlackShortcutMiddlewareArgs], lazy ...types.Middleware[types.SlackShortcutMiddlewareArgs]) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	listener := &listenerEntry{
+		eventType: helpers.IncomingEventTypeShortcut,
+		constraints: listenerConstraints{
+			callbackID:   constraints.CallbackID,
+			shortcutType: constraints.Type,
+		},
+	}
+	a.addLazyListener(listener, a.wrapShortcutMiddleware(ack), wrapLazy(a.wrapShortcutMiddleware, lazy))
+	return a
+}
+
+// ViewLazy adds a view listener whose lazy functions run after ack, see CommandLazy
+func (a *App) ViewLazy(constraints types.ViewConstraints, ack types.Middleware[types.SlackViewMiddlewareArgs], lazy ...types.Middleware[types.SlackViewMiddlewareArgs]) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	listener := &listenerEntry{
+		eventType: helpers.IncomingEventTypeViewAction,
+		constraints: listenerConstraints{
+			callbackID: constraints.CallbackID,
+			viewType:   constraints.Type,
+		},
+	}
+	a.addLazyListener(listener, a.wrapViewMiddleware(ack), wrapLazy(a.wrapViewMiddleware, lazy))
+	return a
+}
+
+// wrapLazy converts the lazy functions of a listener to base middleware with wrap
+func wrapLazy[Args any](wrap func(types.Middleware[Args]) types.Middleware[types.AllMiddlewareArgs], lazy []types.Middleware[Args]) []types.Middleware[types.AllMiddlewareArgs] {
+	wrapped := make([]types.Middleware[types.AllMiddlewareArgs], len(lazy))
+	for i, m := range lazy {
+		wrapped[i] = wrap(m)
+	}
+	return wrapped
+}
+
+// addLazyListener adds listener running ack and then starting lazy. Lazy functions are identified
+// by the index of their listener and their own, e.g. "3.0". Callers must hold a.mu.
+func (a *App) addLazyListener(listener *listenerEntry, ack types.Middleware[types.AllMiddlewareArgs], lazy []types.Middleware[types.AllMiddlewareArgs]) {
+	listener.lazy = true
+	listenerIndex := len(a.listenerEntries)
+	ids := make([]string, len(lazy))
+	for i := range lazy {
+		ids[i] = fmt.Sprintf("%d.%d", listenerIndex, i)
+	}
+
+	listener.middleware = []types.Middleware[types.AllMiddlewareArgs]{func(args types.AllMiddlewareArgs) error {
+		// A request re-invoking the app only runs the lazy function it names
+		if id := lazyListenerID(args.Context.Headers); id != "" {
+			for i, lazyID := range ids {
+				if lazyID == id {
+					return lazy[i](args)
+				}
+			}
+			return nil
+		}
+
+		if err := ack(args); err != nil {
+			return err
+		}
+		for i, fn := range lazy {
+			if err := a.startLazyListener(listener, listenerIndex, ids[i], fn, args); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}
+	a.addListener(listener)
+}
+
+// startLazyListener starts fn with the runner, giving it args that outlive the request
+func (a *App) startLazyListener(listener *listenerEntry, listenerIndex int, id string, fn types.Middleware[types.AllMiddlewareArgs], args types.AllMiddlewareArgs) error {
+	// The event's context is reset once the request was processed when event contexts are
+	// reused, so the lazy function gets a clone of it
+	lazyContext := args.Context.Clone()
+	request := LazyListenerRequest{
+		ID:        id,
+		Body:      args.Context.RawBody,
+		Headers:   args.Context.Headers,
+		Signature: a.lazyListenerSignature(id, args.Context.RawBody),
+		Run: func(ctx context.Context) error {
+			runArgs, err := a.lazyListenerArgs(ctx, listener.eventType, lazyContext)
+			if err != nil {
+				return err
+			}
+			return fn(runArgs)
+		},
+	}
+
+	ctx := args.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if a.lazyListenerRunner != nil {
+		return a.lazyListenerRunner.Start(ctx, request)
+	}
+
+	// Run in a goroutine, counted as in flight so a graceful shutdown waits for it
+	ctx = context.WithoutCancel(ctx)
+	a.inFlight.add()
+	go func() {
+		defer a.inFlight.done()
+		if err := a.runLazyListener(ctx, request); err != nil {
+			event := types.ReceiverEvent{Body: request.Body, Headers: request.Headers}
+			_ = a.handleError(ctx, bolterrors.NewListenerError(listener.String(), listenerIndex, listener.source, err), event, lazyContext)
+		}
+	}()
+	return nil
+}
+
+// lazyListenerArgs builds the args of a lazy function run with ctx from lazyContext, so its client,
+// say and respond are bound to ctx and lazyContext rather than to the request, which was
+// answered. The event was acknowledged, so its Ack returns a ReceiverMultipleAckError.
+func (a *App) lazyListenerArgs(ctx context.Context, eventType helpers.IncomingEventType, lazyContext *types.Context) (types.AllMiddlewareArgs, error) {
+	envelope, _ := parseRequestEnvelope(lazyContext.RawBody)
+	event := types.ReceiverEvent{
+		Body:    lazyContext.RawBody,
+		Headers: lazyContext.Headers,
+		Ack: func(types.AckResponse) error {
+			return bolterrors.NewReceiverMultipleAckError()
+		},
+	}
+	middlewareArgs, err := a.buildMiddlewareArgs(ctx, eventType, event, lazyContext, nil, envelope.parsed)
+	if err != nil {
+		return types.AllMiddlewareArgs{}, err
+	}
+	args := a.extractBaseArgs(middlewareArgs)
+	args.Next = func() error { return nil }
+	return args, nil
+}
+
+// runLazyListener runs a lazy function started in a goroutine, converting a recovered panic into
+// its error according to the panic policy
+func (a *App) runLazyListener(ctx context.Context, request LazyListenerRequest) (err error) {
+	if a.panicPolicy != PanicPolicyCrash {
+		defer func() {
+			if r := recover(); r != nil {
+				err = a.recoveredPanicError(r)
+			}
+		}()
+	}
+	return request.Run(ctx)
+}
+
+// lazyListenerID returns the lazy function named by headers, or "" for requests from Slack
+func lazyListenerID(headers map[string]string) string {
+	return lazyListenerHeader(headers, LazyListenerHeader)
+}
+
+// lazyListenerHeader returns the value of the header name, matched case-insensitively
+func lazyListenerHeader(headers map[string]string, name string) string {
+	for headerName, value := range headers {
+		if strings.EqualFold(headerName, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// lazyListenerSignature returns the signature of a request re-invoking the app to run lazy
+// function id for body, or "" if the app has no signing secret
+func (a *App) lazyListenerSignature(id string, body []byte) string {
+	secret := a.signingSecret()
+	if secret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("lazy:" + id + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyLazyListenerRequest returns an error unless headers sign the request re-invoking the app
+// to run lazy function id for body
+func (a *App) verifyLazyListenerRequest(id string, headers map[string]string, body []byte) error {
+	expected := a.lazyListenerSignature(id, body)
+	if expected == "" {
+		return bolterrors.NewReceiverAuthenticityError("lazy listener requests need the app's signing secret")
+	}
+	if !hmac.Equal([]byte(lazyListenerHeader(headers, LazyListenerSignatureHeader)), []byte(expected)) {
+		return bolterrors.NewReceiverAuthenticityError("invalid lazy listener request signature")
+	}
+	return nil
+}
+
+// lazyListenerIndex returns the index of the listener of lazy function id, or -1 if id is invalid
+func lazyListenerIndex(id string) int {
+	listener, _, found := strings.Cut(id, ".")
+	index, err := strconv.Atoi(listener)
+	if !found || err != nil {
+		return -1
+	}
+	return index
+}
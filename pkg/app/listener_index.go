@@ -0,0 +1,156 @@
+package app
+
+import (
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// listenerIndex narrows the listeners considered for an incoming event. Listeners are grouped by
+// incoming event class and then by their primary constraint (event type, command name, action_id
+// or callback_id); listeners without a plain primary constraint, e.g. RegExp patterns, fall back
+// to an unkeyed list. Each list holds registration indices in ascending order.
+type listenerIndex struct {
+	classes map[helpers.IncomingEventType]*listenerClassIndex
+}
+
+// listenerClassIndex holds the listeners registered for one incoming event class
+type listenerClassIndex struct {
+	byKey   map[string][]int
+	unkeyed []int
+}
+
+// newListenerIndex creates an empty listener index
+func newListenerIndex() *listenerIndex {
+	return &listenerIndex{
+		classes: make(map[helpers.IncomingEventType]*listenerClassIndex),
+	}
+}
+
+// add indexes listener under its registration index
+func (idx *listenerIndex) add(listener *listenerEntry, index int) {
+	class, exists := idx.classes[listener.eventType]
+	if !exists {
+		class = &listenerClassIndex{byKey: make(map[string][]int)}
+		idx.classes[listener.eventType] = class
+	}
+
+	if key := listenerPrimaryKey(listener); key != "" {
+		class.byKey[key] = append(class.byKey[key], index)
+	} else {
+		class.unkeyed = append(class.unkeyed, index)
+	}
+}
+
+// candidates returns, in registration order, the indices of listeners that may match an event
+// of eventType whose primary key is key
+func (idx *listenerIndex) candidates(eventType helpers.IncomingEventType, key string) []int {
+	class, exists := idx.classes[eventType]
+	if !exists {
+		return nil
+	}
+
+	keyed := class.byKey[key]
+	if key == "" || len(keyed) == 0 {
+		return class.unkeyed
+	}
+	if len(class.unkeyed) == 0 {
+		return keyed
+	}
+
+	// Merge the two ascending lists so listeners still run in registration order
+	merged := make([]int, 0, len(keyed)+len(class.unkeyed))
+	i, j := 0, 0
+	for i < len(keyed) && j < len(class.unkeyed) {
+		if keyed[i] < class.unkeyed[j] {
+			merged = append(merged, keyed[i])
+			i++
+		} else {
+			merged = append(merged, class.unkeyed[j])
+			j++
+		}
+	}
+	merged = append(merged, keyed[i:]...)
+	return append(merged, class.unkeyed[j:]...)
+}
+
+// listenerPrimaryKey returns the plain constraint a listener is indexed by, or "" if it has none
+func listenerPrimaryKey(listener *listenerEntry) string {
+	switch listener.eventType {
+	case helpers.IncomingEventTypeEvent:
+		return listener.constraints.eventType
+	case helpers.IncomingEventTypeCommand:
+		return listener.constraints.command
+	case helpers.IncomingEventTypeAction, helpers.IncomingEventTypeOptions:
+		return listener.constraints.actionID
+	case helpers.IncomingEventTypeShortcut, helpers.IncomingEventTypeViewAction:
+		return listener.constraints.callbackID
+	default:
+		return ""
+	}
+}
+
+// incomingPrimaryKey returns the value of the primary constraint carried by an incoming event,
+// or "" if it cannot be determined, in which case only unkeyed listeners are candidates
+func incomingPrimaryKey(middlewareArgs interface{}) string {
+	switch args := middlewareArgs.(type) {
+	case types.SlackEventMiddlewareArgs:
+		if args.Event != nil {
+			return args.Event.GetType()
+		}
+	case types.SlackCommandMiddlewareArgs:
+		return args.Command.Command
+	case types.SlackActionMiddlewareArgs:
+		if actionMap, err := helpers.ExtractRawDataFromSlackAction(args.Action); err == nil {
+			return stringField(actionMap, "action_id")
+		}
+	case types.SlackOptionsMiddlewareArgs:
+		if bodyMap, ok := args.Body.(map[string]interface{}); ok {
+			return stringField(bodyMap, "action_id")
+		}
+	case types.SlackShortcutMiddlewareArgs:
+		if bodyMap, err := helpers.ExtractRawDataFromSlackShortcut(args.Body); err == nil {
+			return stringField(bodyMap, "callback_id")
+		}
+	case types.SlackViewMiddlewareArgs:
+		if bodyMap, err := helpers.ExtractRawDataFromSlackView(args.Body); err == nil {
+			if viewMap, ok := bodyMap["view"].(map[string]interface{}); ok {
+				return stringField(viewMap, "callback_id")
+			}
+		}
+	}
+	return ""
+}
+
+// parsedPrimaryKey returns the same key as incomingPrimaryKey, read from the parsed request body
+// so listeners can be looked up before typed middleware args are built
+func parsedPrimaryKey(eventType helpers.IncomingEventType, parsed map[string]interface{}) string {
+	switch eventType {
+	case helpers.IncomingEventTypeEvent:
+		if eventMap, ok := parsed["event"].(map[string]interface{}); ok {
+			return stringField(eventMap, "type")
+		}
+	case helpers.IncomingEventTypeCommand:
+		return stringField(parsed, "command")
+	case helpers.IncomingEventTypeAction:
+		if actions, ok := parsed["actions"].([]interface{}); ok && len(actions) > 0 {
+			if actionMap, ok := actions[0].(map[string]interface{}); ok {
+				return stringField(actionMap, "action_id")
+			}
+		}
+	case helpers.IncomingEventTypeOptions:
+		return stringField(parsed, "action_id")
+	case helpers.IncomingEventTypeShortcut:
+		return stringField(parsed, "callback_id")
+	case helpers.IncomingEventTypeViewAction:
+		if viewMap, ok := parsed["view"].(map[string]interface{}); ok {
+			return stringField(viewMap, "callback_id")
+		}
+	}
+	return ""
+}
+
+// stringField returns m[key] if it is a string, or ""
+func stringField(m map[string]interface{}, key string) string {
+	value, _ := m[key].(string)
+	return value
+}
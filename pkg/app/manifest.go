@@ -0,0 +1,260 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+)
+
+// messageEvents are the event subscriptions delivering messages from every kind of conversation
+var messageEvents = []string{"message.channels", "message.groups", "message.im", "message.mpim"}
+
+// eventScopes are the bot scopes needed to receive common events
+var eventScopes = map[string][]string{
+	"app_mention":           {"app_mentions:read"},
+	"message.channels":      {"channels:history"},
+	"message.groups":        {"groups:history"},
+	"message.im":            {"im:history"},
+	"message.mpim":          {"mpim:history"},
+	"reaction_added":        {"reactions:read"},
+	"reaction_removed":      {"reactions:read"},
+	"member_joined_channel": {"channels:read", "groups:read"},
+	"member_left_channel":   {"channels:read", "groups:read"},
+	"channel_created":       {"channels:read"},
+	"channel_rename":        {"channels:read"},
+	"team_join":             {"users:read"},
+	"user_change":           {"users:read"},
+	"pin_added":             {"pins:read"},
+	"pin_removed":           {"pins:read"},
+	"star_added":            {"stars:read"},
+	"star_removed":          {"stars:read"},
+	"file_shared":           {"files:read"},
+	"file_created":          {"files:read"},
+	"emoji_changed":         {"emoji:read"},
+}
+
+// ManifestOptions supplies the app settings GenerateManifest cannot learn from the listeners
+type ManifestOptions struct {
+	// Name is the app's display name, also used for its bot user
+	Name string
+	// Description is the app's short description
+	Description string
+	// RequestURL receives events, commands and interactions; it is omitted in Socket Mode
+	RequestURL string
+	// RedirectURLs are the allowed OAuth redirect URLs (default AppOptions.RedirectURI)
+	RedirectURLs []string
+	// BotScopes are added to AppOptions.Scopes and the scopes the subscribed events need
+	BotScopes []string
+	// UserScopes are requested for user tokens
+	UserScopes []string
+	// CommandDescriptions describes slash commands by name, e.g. "/deploy" (default the command)
+	CommandDescriptions map[string]string
+	// ShortcutNames names shortcuts by callback ID (default the callback ID)
+	ShortcutNames map[string]string
+}
+
+// Manifest is a Slack app manifest, see https://api.slack.com/reference/manifests
+type Manifest struct {
+	DisplayInformation ManifestDisplayInformation `json:"display_information" yaml:"display_information"`
+	Features           ManifestFeatures           `json:"features" yaml:"features"`
+	OAuthConfig        ManifestOAuthConfig        `json:"oauth_config" yaml:"oauth_config"`
+	Settings           ManifestSettings           `json:"settings" yaml:"settings"`
+}
+
+// ManifestDisplayInformation is how the app appears in Slack
+type ManifestDisplayInformation struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// ManifestFeatures lists the app's bot user, shortcuts and slash commands
+type ManifestFeatures struct {
+	BotUser       *ManifestBotUser       `json:"bot_user,omitempty" yaml:"bot_user,omitempty"`
+	Shortcuts     []ManifestShortcut     `json:"shortcuts,omitempty" yaml:"shortcuts,omitempty"`
+	SlashCommands []ManifestSlashCommand `json:"slash_commands,omitempty" yaml:"slash_commands,omitempty"`
+}
+
+// ManifestBotUser is the app's bot user
+type ManifestBotUser struct {
+	DisplayName  string `json:"display_name" yaml:"display_name"`
+	AlwaysOnline bool   `json:"always_online" yaml:"always_online"`
+}
+
+// ManifestShortcut is a global or message shortcut
+type ManifestShortcut struct {
+	Name        string `json:"name" yaml:"name"`
+	Type        string `json:"type" yaml:"type"`
+	CallbackID  string `json:"callback_id" yaml:"callback_id"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// ManifestSlashCommand is a slash command
+type ManifestSlashCommand struct {
+	Command      string `json:"command" yaml:"command"`
+	URL          string `json:"url,omitempty" yaml:"url,omitempty"`
+	Description  string `json:"description" yaml:"description"`
+	ShouldEscape bool   `json:"should_escape" yaml:"should_escape"`
+}
+
+// ManifestOAuthConfig holds redirect URLs and requested scopes
+type ManifestOAuthConfig struct {
+	RedirectURLs []string       `json:"redirect_urls,omitempty" yaml:"redirect_urls,omitempty"`
+	Scopes       ManifestScopes `json:"scopes" yaml:"scopes"`
+}
+
+// ManifestScopes are the scopes requested for bot and user tokens
+type ManifestScopes struct {
+	Bot  []string `json:"bot,omitempty" yaml:"bot,omitempty"`
+	User []string `json:"user,omitempty" yaml:"user,omitempty"`
+}
+
+// ManifestSettings holds event subscriptions, interactivity and Socket Mode
+type ManifestSettings struct {
+	EventSubscriptions *ManifestEventSubscriptions `json:"event_subscriptions,omitempty" yaml:"event_subscriptions,omitempty"`
+	Interactivity      *ManifestInteractivity      `json:"interactivity,omitempty" yaml:"interactivity,omitempty"`
+	SocketModeEnabled  bool                        `json:"socket_mode_enabled" yaml:"socket_mode_enabled"`
+}
+
+// ManifestEventSubscriptions are the events delivered to the app
+type ManifestEventSubscriptions struct {
+	RequestURL string   `json:"request_url,omitempty" yaml:"request_url,omitempty"`
+	BotEvents  []string `json:"bot_events,omitempty" yaml:"bot_events,omitempty"`
+}
+
+// ManifestInteractivity enables actions, shortcuts, views and options
+type ManifestInteractivity struct {
+	IsEnabled             bool   `json:"is_enabled" yaml:"is_enabled"`
+	RequestURL            string `json:"request_url,omitempty" yaml:"request_url,omitempty"`
+	MessageMenuOptionsURL string `json:"message_menu_options_url,omitempty" yaml:"message_menu_options_url,omitempty"`
+}
+
+// JSON encodes the manifest as indented JSON
+func (m *Manifest) JSON() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// YAML encodes the manifest as YAML
+func (m *Manifest) YAML() ([]byte, error) {
+	return yaml.Marshal(m)
+}
+
+// GenerateManifest builds an app manifest from the registered event, message, command and
+// shortcut listeners and the configured scopes, so the app configuration can be kept in sync
+// with the code. Listeners registered with RegExp patterns cannot be listed and are left out.
+func (a *App) GenerateManifest(options ManifestOptions) (*Manifest, error) {
+	if options.Name == "" {
+		return nil, errors.New("manifest name is required")
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	requestURL := options.RequestURL
+	if a.socketMode {
+		requestURL = ""
+	}
+
+	manifest := &Manifest{
+		DisplayInformation: ManifestDisplayInformation{Name: options.Name, Description: options.Description},
+		Features:           ManifestFeatures{BotUser: &ManifestBotUser{DisplayName: options.Name}},
+		Settings:           ManifestSettings{SocketModeEnabled: a.socketMode},
+	}
+
+	var events []string
+	interactive, hasOptions := false, false
+	for _, listener := range a.listenerEntries {
+		c := listener.constraints
+		switch listener.eventType {
+		case helpers.IncomingEventTypeEvent:
+			if c.eventType == "message" {
+				events = append(events, messageEvents...)
+			} else if c.eventType != "" {
+				events = append(events, c.eventType)
+			}
+		case helpers.IncomingEventTypeCommand:
+			if c.command == "" || slices.ContainsFunc(manifest.Features.SlashCommands, func(command ManifestSlashCommand) bool {
+				return command.Command == c.command
+			}) {
+				continue
+			}
+			description := options.CommandDescriptions[c.command]
+			if description == "" {
+				description = c.command
+			}
+			manifest.Features.SlashCommands = append(manifest.Features.SlashCommands, ManifestSlashCommand{
+				Command:     c.command,
+				URL:         requestURL,
+				Description: description,
+			})
+		case helpers.IncomingEventTypeShortcut:
+			interactive = true
+			if c.callbackID == "" || slices.ContainsFunc(manifest.Features.Shortcuts, func(shortcut ManifestShortcut) bool {
+				return shortcut.CallbackID == c.callbackID
+			}) {
+				continue
+			}
+			shortcutType := "global"
+			if c.shortcutType == "message_action" {
+				shortcutType = "message"
+			}
+			name := options.ShortcutNames[c.callbackID]
+			if name == "" {
+				name = c.callbackID
+			}
+			manifest.Features.Shortcuts = append(manifest.Features.Shortcuts, ManifestShortcut{
+				Name:        name,
+				Type:        shortcutType,
+				CallbackID:  c.callbackID,
+				Description: name,
+			})
+		case helpers.IncomingEventTypeOptions:
+			interactive, hasOptions = true, true
+		default:
+			interactive = true
+		}
+	}
+
+	events = sortedUnique(events)
+	if len(events) > 0 {
+		manifest.Settings.EventSubscriptions = &ManifestEventSubscriptions{RequestURL: requestURL, BotEvents: events}
+	}
+	if interactive {
+		manifest.Settings.Interactivity = &ManifestInteractivity{IsEnabled: true, RequestURL: requestURL}
+		if hasOptions {
+			manifest.Settings.Interactivity.MessageMenuOptionsURL = requestURL
+		}
+	}
+
+	botScopes := append(slices.Clone(a.scopes), options.BotScopes...)
+	for _, event := range events {
+		botScopes = append(botScopes, eventScopes[event]...)
+	}
+	if len(manifest.Features.SlashCommands) > 0 || len(manifest.Features.Shortcuts) > 0 {
+		botScopes = append(botScopes, "commands")
+	}
+	manifest.OAuthConfig.Scopes = ManifestScopes{Bot: sortedUnique(botScopes), User: sortedUnique(options.UserScopes)}
+
+	manifest.OAuthConfig.RedirectURLs = options.RedirectURLs
+	if len(manifest.OAuthConfig.RedirectURLs) == 0 && a.redirectURI != "" {
+		manifest.OAuthConfig.RedirectURLs = []string{a.redirectURI}
+	}
+	return manifest, nil
+}
+
+// sortedUnique returns values sorted without duplicates or empty strings
+func sortedUnique(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return slices.Compact(result)
+}
@@ -0,0 +1,314 @@
+package app
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+const (
+	// defaultMetricsWindow is the period covered by the rolling metrics
+	defaultMetricsWindow = 5 * time.Minute
+	// metricsSlots is the number of slots the window is divided into; the oldest slot is dropped
+	// as time moves on
+	metricsSlots = 10
+)
+
+// latencyBuckets are the upper bounds of the latency histogram buckets; slower observations
+// fall into a final overflow bucket
+var latencyBuckets = [...]time.Duration{
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// MetricsOptions enables in-process processing metrics for quick production inspection
+type MetricsOptions struct {
+	// Enabled turns on recording of event processing latency, ack latency and listener errors
+	Enabled bool
+	// Window is the period covered by the rolling histograms and counts (default 5 minutes)
+	Window time.Duration
+	// ExpvarName publishes the metrics with expvar under this name, served by /debug/vars
+	ExpvarName string
+}
+
+// MetricsSnapshot is a point-in-time view of the processing metrics over the rolling window
+type MetricsSnapshot struct {
+	Window time.Duration `json:"window"`
+	// Processing is the time from ProcessEvent being called to all listeners returning
+	Processing LatencySnapshot `json:"processing"`
+	// Ack is the time from ProcessEvent being called to the event being acknowledged
+	Ack LatencySnapshot `json:"ack"`
+	// ListenerErrors counts events whose listeners returned an error within the window
+	ListenerErrors int64 `json:"listener_errors"`
+	// ListenerErrorsTotal counts events whose listeners returned an error since the app started
+	ListenerErrorsTotal int64 `json:"listener_errors_total"`
+}
+
+// LatencySnapshot summarizes a latency histogram. Percentiles are the upper bound of the bucket
+// they fall into.
+type LatencySnapshot struct {
+	Count int64         `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// MarshalJSON renders durations as strings such as "12ms" so the output is readable as is
+func (s LatencySnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Count int64  `json:"count"`
+		Mean  string `json:"mean"`
+		P50   string `json:"p50"`
+		P90   string `json:"p90"`
+		P99   string `json:"p99"`
+		Max   string `json:"max"`
+	}{s.Count, s.Mean.String(), s.P50.String(), s.P90.String(), s.P99.String(), s.Max.String()})
+}
+
+// MarshalJSON renders the window as a duration string
+func (s MetricsSnapshot) MarshalJSON() ([]byte, error) {
+	type snapshot MetricsSnapshot
+	return json.Marshal(struct {
+		Window string `json:"window"`
+		snapshot
+	}{s.Window.String(), snapshot(s)})
+}
+
+// latencyHistogram counts observations per latency bucket
+type latencyHistogram struct {
+	buckets [len(latencyBuckets) + 1]int64
+	count   int64
+	sum     time.Duration
+	max     time.Duration
+}
+
+// observe adds d to the histogram
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	h.buckets[i]++
+	h.count++
+	h.sum += d
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// merge adds the observations of other to the histogram
+func (h *latencyHistogram) merge(other *latencyHistogram) {
+	for i, n := range other.buckets {
+		h.buckets[i] += n
+	}
+	h.count += other.count
+	h.sum += other.sum
+	if other.max > h.max {
+		h.max = other.max
+	}
+}
+
+// snapshot summarizes the histogram
+func (h *latencyHistogram) snapshot() LatencySnapshot {
+	if h.count == 0 {
+		return LatencySnapshot{}
+	}
+	return LatencySnapshot{
+		Count: h.count,
+		Mean:  h.sum / time.Duration(h.count),
+		P50:   h.quantile(0.50),
+		P90:   h.quantile(0.90),
+		P99:   h.quantile(0.99),
+		Max:   h.max,
+	}
+}
+
+// quantile returns the upper bound of the bucket holding the q-th observation, or the maximum
+// for the overflow bucket
+func (h *latencyHistogram) quantile(q float64) time.Duration {
+	rank := int64(q * float64(h.count))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, n := range h.buckets {
+		seen += n
+		if seen >= rank {
+			if i < len(latencyBuckets) && latencyBuckets[i] < h.max {
+				return latencyBuckets[i]
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+// metricsSlot holds the observations of one slice of the rolling window
+type metricsSlot struct {
+	epoch          int64
+	processing     latencyHistogram
+	ack            latencyHistogram
+	listenerErrors int64
+}
+
+// processingMetrics records processing metrics in a ring of slots covering the rolling window
+type processingMetrics struct {
+	window    time.Duration
+	slotWidth time.Duration
+	now       func() time.Time
+
+	mu                  sync.Mutex
+	slots               [metricsSlots]metricsSlot
+	listenerErrorsTotal int64
+}
+
+// newProcessingMetrics creates a recorder from options, publishing it with expvar if requested,
+// or returns nil if metrics are disabled
+func newProcessingMetrics(options MetricsOptions) (*processingMetrics, error) {
+	if !options.Enabled {
+		return nil, nil
+	}
+
+	window := options.Window
+	if window <= 0 {
+		window = defaultMetricsWindow
+	}
+	m := &processingMetrics{
+		window:    window,
+		slotWidth: window / metricsSlots,
+		now:       time.Now,
+	}
+	if m.slotWidth <= 0 {
+		m.slotWidth = 1
+	}
+
+	if options.ExpvarName != "" {
+		if expvar.Get(options.ExpvarName) != nil {
+			return nil, bolterrors.NewAppInitializationError(fmt.Sprintf("expvar %q is already published", options.ExpvarName))
+		}
+		expvar.Publish(options.ExpvarName, expvar.Func(func() interface{} { return m.snapshot() }))
+	}
+	return m, nil
+}
+
+// slot returns the slot for now, clearing it if it holds observations from an earlier window.
+// The caller must hold m.mu.
+func (m *processingMetrics) slot(now time.Time) *metricsSlot {
+	epoch := now.UnixNano() / int64(m.slotWidth)
+	slot := &m.slots[epoch%metricsSlots]
+	if slot.epoch != epoch {
+		*slot = metricsSlot{epoch: epoch}
+	}
+	return slot
+}
+
+// start returns the time processing of an event started, or the zero time when metrics are disabled
+func (m *processingMetrics) start() time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+	return m.now()
+}
+
+// observeProcessing records the processing latency of an event started at started
+func (m *processingMetrics) observeProcessing(started time.Time) {
+	if m == nil {
+		return
+	}
+	now := m.now()
+	m.mu.Lock()
+	m.slot(now).processing.observe(now.Sub(started))
+	m.mu.Unlock()
+}
+
+// observeListenerError counts an event whose listeners returned an error
+func (m *processingMetrics) observeListenerError() {
+	if m == nil {
+		return
+	}
+	now := m.now()
+	m.mu.Lock()
+	m.slot(now).listenerErrors++
+	m.listenerErrorsTotal++
+	m.mu.Unlock()
+}
+
+// timeAck wraps ack so the first acknowledgement records the ack latency of an event started at started
+func (m *processingMetrics) timeAck(ack func(types.AckResponse) error, started time.Time) func(types.AckResponse) error {
+	if m == nil || ack == nil {
+		return ack
+	}
+	var acked atomic.Bool
+	return func(response types.AckResponse) error {
+		if acked.CompareAndSwap(false, true) {
+			now := m.now()
+			m.mu.Lock()
+			m.slot(now).ack.observe(now.Sub(started))
+			m.mu.Unlock()
+		}
+		return ack(response)
+	}
+}
+
+// snapshot merges the slots within the rolling window
+func (m *processingMetrics) snapshot() MetricsSnapshot {
+	if m == nil {
+		return MetricsSnapshot{}
+	}
+
+	oldest := m.now().UnixNano()/int64(m.slotWidth) - metricsSlots + 1
+	var processing, ack latencyHistogram
+	snapshot := MetricsSnapshot{Window: m.window}
+
+	m.mu.Lock()
+	for i := range m.slots {
+		slot := &m.slots[i]
+		if slot.epoch < oldest {
+			continue
+		}
+		processing.merge(&slot.processing)
+		ack.merge(&slot.ack)
+		snapshot.ListenerErrors += slot.listenerErrors
+	}
+	snapshot.ListenerErrorsTotal = m.listenerErrorsTotal
+	m.mu.Unlock()
+
+	snapshot.Processing = processing.snapshot()
+	snapshot.Ack = ack.snapshot()
+	return snapshot
+}
+
+// Metrics returns the processing metrics over the rolling window; it is empty unless
+// AppOptions.Metrics is enabled
+func (a *App) Metrics() MetricsSnapshot {
+	return a.metrics.snapshot()
+}
+
+// MetricsHandler serves Metrics as JSON, for mounting as a debug endpoint such as a receiver
+// custom route
+func (a *App) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(a.Metrics())
+	})
+}
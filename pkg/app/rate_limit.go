@@ -0,0 +1,383 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Defaults of RateLimitOptions
+const (
+	defaultRateLimitMaxRetries = 3
+	defaultRateLimitMaxWait    = 30 * time.Second
+	// defaultRetryAfter is used when a rate limited response has no valid Retry-After header
+	defaultRetryAfter = time.Second
+	// rateLimitWindow is the period Slack's tier limits are expressed in
+	rateLimitWindow = time.Minute
+)
+
+// DefaultRateLimitTierLimits are the calls per minute allowed for each of Slack's Web API rate
+// limit tiers, from https://api.slack.com/apis/rate-limits
+var DefaultRateLimitTierLimits = map[int]int{1: 1, 2: 20, 3: 50, 4: 100}
+
+// defaultMethodTiers are the tiers of commonly used Web API methods. Methods missing here, and
+// methods with special limits such as chat.postMessage, are only paced by Slack's 429 responses.
+var defaultMethodTiers = map[string]int{
+	"apps.connections.open":   1,
+	"bookmarks.add":           2,
+	"conversations.create":    2,
+	"conversations.invite":    2,
+	"conversations.list":      2,
+	"emoji.list":              2,
+	"pins.add":                2,
+	"reactions.remove":        2,
+	"search.messages":         2,
+	"usergroups.list":         2,
+	"users.list":              2,
+	"chat.delete":             3,
+	"chat.scheduleMessage":    3,
+	"chat.update":             3,
+	"conversations.history":   3,
+	"conversations.info":      3,
+	"conversations.join":      3,
+	"conversations.open":      3,
+	"conversations.replies":   3,
+	"files.list":              3,
+	"reactions.add":           3,
+	"reactions.get":           3,
+	"team.info":               3,
+	"users.conversations":     3,
+	"users.lookupByEmail":     3,
+	"users.profile.set":       3,
+	"chat.postEphemeral":      4,
+	"conversations.members":   4,
+	"files.info":              4,
+	"users.info":              4,
+	"users.profile.get":       4,
+	"views.open":              4,
+	"views.publish":           4,
+	"views.push":              4,
+	"views.update":            4,
+	"workflows.stepCompleted": 4,
+}
+
+// RateLimitOptions makes the Slack clients of the app, including args.Client, handle Web API
+// rate limits: calls are queued once the budget of their method's tier is spent, and calls
+// answered with HTTP 429 are retried after the Retry-After Slack asked for. A client set with
+// AppOptions.ClientOptions' slack.OptionHTTPClient replaces the rate limited one.
+type RateLimitOptions struct {
+	// Enabled turns rate limit handling on
+	Enabled bool
+	// MaxRetries is the number of times a rate limited call is retried (default 3); once spent,
+	// the call fails with *slack.RateLimitedError
+	MaxRetries int
+	// MaxWait is the longest a call waits, at once, for its budget or a Retry-After (default 30s).
+	// Calls that would wait longer are sent right away, failing with *slack.RateLimitedError if
+	// Slack still rate limits them, so listeners can ack before Slack's 3 second deadline.
+	MaxWait time.Duration
+	// TierLimits overrides the calls per minute of tiers in DefaultRateLimitTierLimits
+	TierLimits map[int]int
+	// MethodTiers sets or overrides the tier of Web API methods, such as {"admin.users.list": 2}
+	MethodTiers map[string]int
+}
+
+// RateLimitBudget is the remaining rate limit budget of a Web API method for a token
+type RateLimitBudget struct {
+	Method string
+	// Tier is the rate limit tier of Method, or 0 if it is not known
+	Tier int
+	// Limit is the number of calls per minute of Tier; 0 when calls are only paced by Slack's
+	// 429 responses
+	Limit int
+	// Remaining is the number of calls that can be made right away without exceeding Limit
+	Remaining int
+	// ResetAt is when the budget allows the next call, zero if one can be made right away
+	ResetAt time.Time
+	// RetryAfter is when Slack allows calls again after rate limiting the method, zero if it did not
+	RetryAfter time.Time
+}
+
+// rateLimitKey identifies the budget of a method for a token; Slack limits each app per
+// workspace and method
+type rateLimitKey struct {
+	token  string
+	method string
+}
+
+// rateLimitState holds the calls made in the last minute and the Retry-After of a budget
+type rateLimitState struct {
+	calls        []time.Time
+	blockedUntil time.Time
+}
+
+// rateLimiter paces Web API calls per token and method
+type rateLimiter struct {
+	maxRetries  int
+	maxWait     time.Duration
+	tierLimits  map[int]int
+	methodTiers map[string]int
+	logger      types.Logger
+
+	mu        sync.Mutex
+	states    map[rateLimitKey]*rateLimitState
+	lastSweep time.Time
+}
+
+// newRateLimiter creates a rate limiter from options, or returns nil if it is not enabled
+func newRateLimiter(options RateLimitOptions, logger types.Logger) *rateLimiter {
+	if !options.Enabled {
+		return nil
+	}
+
+	l := &rateLimiter{
+		maxRetries:  defaultRateLimitMaxRetries,
+		maxWait:     defaultRateLimitMaxWait,
+		tierLimits:  make(map[int]int),
+		methodTiers: make(map[string]int),
+		logger:      logger,
+		states:      make(map[rateLimitKey]*rateLimitState),
+		lastSweep:   time.Now(),
+	}
+	if options.MaxRetries > 0 {
+		l.maxRetries = options.MaxRetries
+	}
+	if options.MaxWait > 0 {
+		l.maxWait = options.MaxWait
+	}
+	for tier, limit := range DefaultRateLimitTierLimits {
+		l.tierLimits[tier] = limit
+	}
+	for tier, limit := range options.TierLimits {
+		l.tierLimits[tier] = limit
+	}
+	for method, tier := range defaultMethodTiers {
+		l.methodTiers[method] = tier
+	}
+	for method, tier := range options.MethodTiers {
+		l.methodTiers[method] = tier
+	}
+	return l
+}
+
+// wrap returns a copy of client whose Web API calls are rate limited
+func (l *rateLimiter) wrap(client *http.Client) *http.Client {
+	wrapped := *client
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	wrapped.Transport = &rateLimitTransport{limiter: l, next: next}
+	return &wrapped
+}
+
+// tier returns the tier of method and its calls per minute
+func (l *rateLimiter) tier(method string) (int, int) {
+	tier := l.methodTiers[method]
+	return tier, l.tierLimits[tier]
+}
+
+// wait blocks until key may make a call, or ctx is done, and records the call
+func (l *rateLimiter) wait(ctx context.Context, key rateLimitKey) error {
+	_, limit := l.tier(key.method)
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.sweep(now)
+		state := l.state(key, now)
+		var until time.Time
+		switch {
+		case now.Before(state.blockedUntil):
+			until = state.blockedUntil
+		case limit > 0 && len(state.calls) >= limit:
+			until = state.calls[0].Add(rateLimitWindow)
+		}
+		if until.IsZero() || until.Sub(now) > l.maxWait {
+			if limit > 0 {
+				state.calls = append(state.calls, now)
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(until.Sub(now))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// block holds back the calls of key for retryAfter
+func (l *rateLimiter) block(key rateLimitKey, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	state := l.state(key, now)
+	if until := now.Add(retryAfter); until.After(state.blockedUntil) {
+		state.blockedUntil = until
+	}
+}
+
+// budget returns the remaining budget of key
+func (l *rateLimiter) budget(key rateLimitKey) RateLimitBudget {
+	tier, limit := l.tier(key.method)
+	budget := RateLimitBudget{Method: key.method, Tier: tier, Limit: limit, Remaining: limit}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	state := l.state(key, now)
+	if now.Before(state.blockedUntil) {
+		budget.RetryAfter = state.blockedUntil
+		budget.Remaining = 0
+		budget.ResetAt = state.blockedUntil
+	}
+	if limit > 0 && budget.Remaining > 0 {
+		budget.Remaining = max(limit-len(state.calls), 0)
+		if budget.Remaining == 0 {
+			budget.ResetAt = state.calls[0].Add(rateLimitWindow)
+		}
+	}
+	return budget
+}
+
+// state returns the state of key with calls older than the window dropped; l.mu must be held
+func (l *rateLimiter) state(key rateLimitKey, now time.Time) *rateLimitState {
+	state, exists := l.states[key]
+	if !exists {
+		state = &rateLimitState{}
+		l.states[key] = state
+	}
+	state.prune(now)
+	return state
+}
+
+// sweep drops idle states once per window, so tokens of uninstalled workspaces do not pile up;
+// l.mu must be held
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitWindow {
+		return
+	}
+	l.lastSweep = now
+	for key, state := range l.states {
+		state.prune(now)
+		if len(state.calls) == 0 && !now.Before(state.blockedUntil) {
+			delete(l.states, key)
+		}
+	}
+}
+
+// prune drops calls older than the window
+func (s *rateLimitState) prune(now time.Time) {
+	cutoff := now.Add(-rateLimitWindow)
+	i := 0
+	for i < len(s.calls) && !s.calls[i].After(cutoff) {
+		i++
+	}
+	s.calls = s.calls[i:]
+}
+
+// rateLimitTransport queues and retries Web API calls according to the rate limiter
+type rateLimitTransport struct {
+	limiter *rateLimiter
+	next    http.RoundTripper
+}
+
+// RoundTrip sends req once its budget allows, retrying it while Slack answers with HTTP 429
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := slackAPIMethod(req.URL.Path)
+	if method == "" {
+		return t.next.RoundTrip(req)
+	}
+	key := rateLimitKey{token: requestToken(req), method: method}
+
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.wait(req.Context(), key); err != nil {
+			return nil, err
+		}
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		t.limiter.block(key, retryAfter)
+		replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if attempt >= t.limiter.maxRetries || retryAfter > t.limiter.maxWait || !replayable {
+			return resp, nil
+		}
+
+		t.limiter.logger.Debug("Slack API call rate limited, retrying", "method", method, "retry_after", retryAfter, "attempt", attempt+1)
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		_ = resp.Body.Close()
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// slackAPIMethod returns the Web API method of a request path such as /api/chat.postMessage,
+// or "" for other paths
+func slackAPIMethod(path string) string {
+	index := strings.LastIndex(path, "/api/")
+	if index < 0 {
+		return ""
+	}
+	return path[index+len("/api/"):]
+}
+
+// requestToken returns the token a Web API request is authorized with, from its Authorization
+// header or, as slack-go sends most methods, its form body
+func requestToken(req *http.Request) string {
+	if token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	if req.GetBody == nil || !strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	data, err := io.ReadAll(io.LimitReader(body, 1<<20))
+	if err != nil {
+		return ""
+	}
+	values, _ := url.ParseQuery(string(data))
+	return values.Get("token")
+}
+
+// parseRetryAfter parses a Retry-After header in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// RateLimitBudget returns the remaining rate limit budget of a Web API method, such as
+// "conversations.history", for token, e.g. args.Context.BotToken. Only Method is set when
+// RateLimitOptions is not enabled.
+func (a *App) RateLimitBudget(token, method string) RateLimitBudget {
+	if a.rateLimiter == nil {
+		return RateLimitBudget{Method: method}
+	}
+	return a.rateLimiter.budget(rateLimitKey{token: token, method: method})
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// EventRecorder persists the events ProcessEvent receives so they can be replayed while
+// developing listeners; see pkg/replay
+type EventRecorder interface {
+	Record(ctx context.Context, event types.ReceiverEvent) error
+}
+
+// replayKey is the context key marking an event as replayed
+type replayKey struct{}
+
+// WithReplay returns a copy of ctx marking the event processed with it as a replay, which is
+// not recorded again
+func WithReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayKey{}, true)
+}
+
+// IsReplay reports whether ctx belongs to a replayed event
+func IsReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
+
+// recordEvent passes event to the recorder unless there is none or the event is a replay.
+// Failing to record is logged without failing the event.
+func (a *App) recordEvent(ctx context.Context, event types.ReceiverEvent) {
+	if a.eventRecorder == nil || IsReplay(ctx) {
+		return
+	}
+	if err := a.eventRecorder.Record(ctx, event); err != nil {
+		a.eventLogger(ctx).Warn("Failed to record event", bolterrors.LogKeyError, err)
+	}
+}
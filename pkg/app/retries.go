@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// RetryOptions controls deliveries Slack retried, those with a ReceiverEvent.RetryNum above 0.
+// Retries are processed like first deliveries by default; listeners can tell them apart by
+// Context.RetryNum and Context.RetryReason.
+type RetryOptions struct {
+	// Skip acknowledges retried deliveries without running middleware or listeners, for apps
+	// whose listeners must not run twice for an event that was only slow to be acknowledged
+	Skip bool
+	// SkipReasons limits Skip to retries with these reasons, e.g. "http_timeout", so retries of
+	// deliveries that failed are still processed
+	SkipReasons []string
+	// ShouldSkip decides for each retried delivery whether to skip it, taking precedence over
+	// Skip and SkipReasons
+	ShouldSkip func(ctx context.Context, event types.ReceiverEvent) bool
+}
+
+// skips reports whether event is a retried delivery to acknowledge without processing it
+func (o RetryOptions) skips(ctx context.Context, event types.ReceiverEvent) bool {
+	if event.RetryNum <= 0 {
+		return false
+	}
+	if o.ShouldSkip != nil {
+		return o.ShouldSkip(ctx, event)
+	}
+	if !o.Skip {
+		return false
+	}
+	return len(o.SkipReasons) == 0 || slices.Contains(o.SkipReasons, event.RetryReason)
+}
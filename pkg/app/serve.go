@@ -0,0 +1,258 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ServeOptions configures App.Serve
+type ServeOptions struct {
+	// HealthAddr is the address of the health server, such as ":8081", answering liveness probes
+	// on /healthz and readiness probes on /readyz; empty disables it
+	HealthAddr string
+	// Signals stop the app (default SIGTERM and SIGINT)
+	Signals []os.Signal
+	// PreStopDelay is how long the app keeps serving after a stop signal while failing readiness
+	// probes, so Kubernetes removes the pod from its Service endpoints before the receiver stops
+	PreStopDelay time.Duration
+	// DrainTimeout bounds how long in-flight events may run once the receiver stops (default 20s).
+	// PreStopDelay and DrainTimeout together should fit in the pod's terminationGracePeriodSeconds.
+	DrainTimeout time.Duration
+	// ReadinessCheck reports whether the app can serve Slack. By default a Socket Mode receiver
+	// must be connected and the app's token, if any, must pass auth.test.
+	ReadinessCheck func(ctx context.Context) error
+	// ReadinessInterval is how often ReadinessCheck runs (default 30s)
+	ReadinessInterval time.Duration
+}
+
+// Defaults of ServeOptions
+const (
+	defaultDrainTimeout      = 20 * time.Second
+	defaultReadinessInterval = 30 * time.Second
+)
+
+// connectionReporter is implemented by receivers holding a connection to Slack, such as the
+// SocketModeReceiver
+type connectionReporter interface {
+	Connected() bool
+}
+
+// Serve runs the app until ctx is done or a stop signal arrives, then shuts it down gracefully
+// for rolling deployments: readiness probes start failing, the receiver keeps serving for
+// PreStopDelay, then it stops and in-flight events get up to DrainTimeout to finish before the
+// app is stopped. Until then the health server reports the app ready once ReadinessCheck passes.
+func (a *App) Serve(ctx context.Context, options ServeOptions) error {
+	if len(options.Signals) == 0 {
+		options.Signals = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	}
+	if options.DrainTimeout <= 0 {
+		options.DrainTimeout = defaultDrainTimeout
+	}
+	if options.ReadinessCheck == nil {
+		options.ReadinessCheck = a.checkSlackConnectivity
+	}
+	if options.ReadinessInterval <= 0 {
+		options.ReadinessInterval = defaultReadinessInterval
+	}
+
+	stopCtx, stopSignals := signal.NotifyContext(ctx, options.Signals...)
+	defer stopSignals()
+
+	health := &serveHealth{}
+	if options.HealthAddr != "" {
+		listener, err := net.Listen("tcp", options.HealthAddr)
+		if err != nil {
+			return fmt.Errorf("starting health server: %w", err)
+		}
+		server := &http.Server{Handler: health.handler(), ReadHeaderTimeout: 5 * time.Second}
+		go func() { _ = server.Serve(listener) }()
+		defer server.Close()
+	}
+
+	// The receiver outlives ctx: it is stopped below, once it has been taken out of rotation
+	runCtx, cancelRun := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelRun()
+	done := make(chan error, 1)
+	go func() { done <- a.Start(runCtx) }()
+
+	checkCtx, stopChecks := context.WithCancel(runCtx)
+	defer stopChecks()
+	go a.checkReadiness(checkCtx, health, options)
+
+	select {
+	case err := <-done:
+		return err
+	case <-stopCtx.Done():
+	}
+	stopChecks()
+	health.draining.Store(true)
+	a.Logger.Info("Stopping app", "pre_stop_delay", options.PreStopDelay, "drain_timeout", options.DrainTimeout)
+
+	if options.PreStopDelay > 0 {
+		timer := time.NewTimer(options.PreStopDelay)
+		select {
+		case <-timer.C:
+		case err := <-done:
+			timer.Stop()
+			return err
+		}
+	}
+
+	drainCtx, cancelDrain := context.WithTimeout(context.WithoutCancel(ctx), options.DrainTimeout)
+	defer cancelDrain()
+	err := a.receiver.Stop(drainCtx)
+	if inFlight, waitErr := a.inFlight.wait(drainCtx); waitErr != nil {
+		a.Logger.Warn("Stopping app before in-flight events finished", "in_flight", inFlight)
+	}
+	if stopErr := a.Stop(drainCtx); err == nil {
+		err = stopErr
+	}
+	cancelRun()
+	if startErr := <-done; err == nil && startErr != nil &&
+		!errors.Is(startErr, context.Canceled) && !errors.Is(startErr, http.ErrServerClosed) {
+		err = startErr
+	}
+	return err
+}
+
+// checkSlackConnectivity is the default ServeOptions.ReadinessCheck
+func (a *App) checkSlackConnectivity(ctx context.Context) error {
+	if receiver, ok := a.receiver.(connectionReporter); ok && !receiver.Connected() {
+		return errors.New("not connected to Slack")
+	}
+	token := a.botToken()
+	if token == "" {
+		return nil
+	}
+	// Call auth.test directly rather than through the cache, which would hide an outage for its TTL
+	_, err := a.getOrCreateClient(token).AuthTestContext(ctx)
+	return err
+}
+
+// checkReadiness runs the readiness check every interval until ctx is done
+func (a *App) checkReadiness(ctx context.Context, health *serveHealth, options ServeOptions) {
+	ticker := time.NewTicker(options.ReadinessInterval)
+	defer ticker.Stop()
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, options.ReadinessInterval)
+		err := options.ReadinessCheck(checkCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		switch wasReady := health.ready.Swap(err == nil); {
+		case err == nil && !wasReady:
+			a.Logger.Info("App is ready")
+		case err != nil && wasReady:
+			a.Logger.Warn("App is not ready", "error", err)
+		}
+		health.setReason(err)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// serveHealth is the state reported by the health server
+type serveHealth struct {
+	ready    atomic.Bool
+	draining atomic.Bool
+
+	mu     sync.Mutex
+	reason string
+}
+
+// setReason records why the last readiness check failed
+func (h *serveHealth) setReason(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reason = ""
+	if err != nil {
+		h.reason = err.Error()
+	}
+}
+
+// handler serves the liveness and readiness probes
+func (h *serveHealth) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case h.draining.Load():
+			http.Error(w, "stopping", http.StatusServiceUnavailable)
+		case !h.ready.Load():
+			h.mu.Lock()
+			reason := h.reason
+			h.mu.Unlock()
+			if reason == "" {
+				reason = "starting"
+			}
+			http.Error(w, reason, http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	})
+	return mux
+}
+
+// inFlightEvents counts the events being processed, so they can be drained before stopping
+type inFlightEvents struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{}
+}
+
+// add records an event being processed until done is called
+func (e *inFlightEvents) add() {
+	e.mu.Lock()
+	e.count++
+	e.mu.Unlock()
+}
+
+// done records the end of an event's processing
+func (e *inFlightEvents) done() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.count--
+	if e.count == 0 && e.idle != nil {
+		close(e.idle)
+		e.idle = nil
+	}
+}
+
+// wait waits until no event is being processed, or returns the number still in flight when ctx is done
+func (e *inFlightEvents) wait(ctx context.Context) (int, error) {
+	e.mu.Lock()
+	if e.count == 0 {
+		e.mu.Unlock()
+		return 0, nil
+	}
+	if e.idle == nil {
+		e.idle = make(chan struct{})
+	}
+	idle := e.idle
+	e.mu.Unlock()
+
+	select {
+	case <-idle:
+		return 0, nil
+	case <-ctx.Done():
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return e.count, ctx.Err()
+	}
+}
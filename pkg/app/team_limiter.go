@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+)
+
+// TeamConcurrencyOptions caps the number of events of a single workspace or Enterprise Grid
+// organization processed at once, so one noisy team cannot starve the others
+type TeamConcurrencyOptions struct {
+	// MaxInFlight is the maximum number of events per team processed concurrently; 0 disables the limit
+	MaxInFlight int
+	// WaitTimeout bounds how long an event waits for a free slot before it is rejected with a
+	// retryable TeamConcurrencyLimitError; 0 waits until the request context is done
+	WaitTimeout time.Duration
+}
+
+// teamLimiter hands out per-team slots for in-flight events
+type teamLimiter struct {
+	maxInFlight int
+	waitTimeout time.Duration
+
+	mu    sync.Mutex
+	teams map[string]*teamSlots
+}
+
+// teamSlots is the semaphore of one team, removed once no event holds or waits for a slot
+type teamSlots struct {
+	sem  chan struct{}
+	refs int
+}
+
+// newTeamLimiter creates a limiter from options, or returns nil if no limit is configured
+func newTeamLimiter(options TeamConcurrencyOptions) *teamLimiter {
+	if options.MaxInFlight <= 0 {
+		return nil
+	}
+	return &teamLimiter{
+		maxInFlight: options.MaxInFlight,
+		waitTimeout: options.WaitTimeout,
+		teams:       make(map[string]*teamSlots),
+	}
+}
+
+// acquire waits for a slot for teamID and returns the function releasing it. Events without a
+// team, and all events when the limiter is nil, are not limited.
+func (l *teamLimiter) acquire(ctx context.Context, teamID string) (func(), error) {
+	if l == nil || teamID == "" {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	slots, exists := l.teams[teamID]
+	if !exists {
+		slots = &teamSlots{sem: make(chan struct{}, l.maxInFlight)}
+		l.teams[teamID] = slots
+	}
+	slots.refs++
+	l.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if l.waitTimeout > 0 {
+		timer := time.NewTimer(l.waitTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case slots.sem <- struct{}{}:
+		return func() {
+			<-slots.sem
+			l.unref(teamID, slots)
+		}, nil
+	case <-timeout:
+		l.unref(teamID, slots)
+		return nil, bolterrors.NewTeamConcurrencyLimitError(teamID, nil)
+	case <-ctx.Done():
+		l.unref(teamID, slots)
+		return nil, bolterrors.NewTeamConcurrencyLimitError(teamID, ctx.Err())
+	}
+}
+
+// unref drops a reference to a team's slots, forgetting the team once nothing references it
+func (l *teamLimiter) unref(teamID string, slots *teamSlots) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots.refs--
+	if slots.refs == 0 {
+		delete(l.teams, teamID)
+	}
+}
+
+// teamLimitKey returns the key events are limited by: the organization for enterprise installs,
+// otherwise the workspace
+func teamLimitKey(source AuthorizeSourceData) string {
+	if source.IsEnterpriseInstall && source.EnterpriseID != "" {
+		return source.EnterpriseID
+	}
+	if source.TeamID != "" {
+		return source.TeamID
+	}
+	return source.EnterpriseID
+}
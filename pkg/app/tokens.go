@@ -0,0 +1,53 @@
+package app
+
+import (
+	"strings"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+)
+
+// Token prefixes Slack issues
+const (
+	botTokenPrefix  = "xoxb-"
+	userTokenPrefix = "xoxp-"
+	appTokenPrefix  = "xapp-"
+)
+
+// validateTokens checks that the tokens in options are of the kind their options take and fit the
+// receiver options, so mistakes fail New rather than the first Slack API call or Socket Mode
+// connection. Every problem found is reported in one AppInitializationError.
+func validateTokens(options AppOptions) error {
+	var problems []string
+
+	switch {
+	case options.Token == "" || strings.HasPrefix(options.Token, botTokenPrefix):
+	case strings.HasPrefix(options.Token, appTokenPrefix):
+		problems = append(problems, "Token is an app-level token (xapp-); pass it as AppToken, and the bot token (xoxb-) as Token")
+	case strings.HasPrefix(options.Token, userTokenPrefix):
+		problems = append(problems, "Token is a user token (xoxp-); pass the bot token (xoxb-) as Token, and return user tokens as AuthorizeResult.UserToken from an Authorize function")
+	default:
+		problems = append(problems, "Token is not a bot token; bot tokens start with xoxb-")
+	}
+
+	switch {
+	case options.AppToken == "" || strings.HasPrefix(options.AppToken, appTokenPrefix):
+	case strings.HasPrefix(options.AppToken, botTokenPrefix):
+		problems = append(problems, "AppToken is a bot token (xoxb-); pass it as Token, and the app-level token (xapp-) as AppToken")
+	default:
+		problems = append(problems, "AppToken is not an app-level token; app-level tokens start with xapp- and are generated under Basic Information in the app settings")
+	}
+
+	if options.Receiver == nil {
+		if options.SocketMode && options.AppToken == "" {
+			problems = append(problems, "app token required for socket mode; set AppToken to an app-level token (xapp-) with the connections:write scope")
+		}
+		if !options.SocketMode && options.AppToken != "" {
+			problems = append(problems, "AppToken is only used by Socket Mode; set SocketMode to receive events over Socket Mode, or remove AppToken to receive them over HTTP")
+		}
+	}
+
+	if len(problems) > 0 {
+		return bolterrors.NewAppInitializationError(strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// EventStartHook is called when the app starts processing an event. The context it returns is
+// used to process the event and passed to the EventEndHook, so a span started by the hook can be
+// carried to its end; return ctx when there is nothing to add.
+type EventStartHook func(ctx context.Context, event types.ReceiverEvent) context.Context
+
+// EventEndHook is called when the app finished processing an event, with what it learned about
+// the event and the error it returns to the receiver
+type EventEndHook func(ctx context.Context, event types.ReceiverEvent, info ProcessEventInfo, err error)
+
+// ListenerStartHook is called before a listener runs, with a description of the listener such as
+// "event(type=app_mention)". The context it returns is passed to the ListenerEndHook. Listener
+// hooks may be called concurrently when AppOptions.ListenerConcurrency is above 1.
+type ListenerStartHook func(ctx context.Context, listener string) context.Context
+
+// ListenerEndHook is called after a listener ran, with the error it returned or its recovered panic
+type ListenerEndHook func(ctx context.Context, listener string, err error)
+
+// tracingHooks are the hooks registered with OnEventStart, OnEventEnd, OnListenerStart and
+// OnListenerEnd. Start hooks run in registration order and end hooks in reverse order, so hooks
+// nest like the spans they open.
+type tracingHooks struct {
+	eventStart    []EventStartHook
+	eventEnd      []EventEndHook
+	listenerStart []ListenerStartHook
+	listenerEnd   []ListenerEndHook
+}
+
+// OnEventStart registers a hook called when the app starts processing an event, to integrate
+// tracing and APM SDKs without an Instrumentation
+func (a *App) OnEventStart(hook EventStartHook) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tracing.eventStart = append(a.tracing.eventStart, hook)
+	return a
+}
+
+// OnEventEnd registers a hook called when the app finished processing an event
+func (a *App) OnEventEnd(hook EventEndHook) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tracing.eventEnd = append(a.tracing.eventEnd, hook)
+	return a
+}
+
+// OnListenerStart registers a hook called before each listener runs
+func (a *App) OnListenerStart(hook ListenerStartHook) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tracing.listenerStart = append(a.tracing.listenerStart, hook)
+	return a
+}
+
+// OnListenerEnd registers a hook called after each listener ran
+func (a *App) OnListenerEnd(hook ListenerEndHook) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tracing.listenerEnd = append(a.tracing.listenerEnd, hook)
+	return a
+}
+
+// currentTracingHooks returns the registered hooks; the slices are only ever appended to, so
+// the copy stays valid while hooks are registered concurrently
+func (a *App) currentTracingHooks() tracingHooks {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.tracing
+}
+
+// hasEventHooks reports whether event hooks are registered
+func (h tracingHooks) hasEventHooks() bool {
+	return len(h.eventStart) > 0 || len(h.eventEnd) > 0
+}
+
+// startEvent runs the event start hooks
+func (h tracingHooks) startEvent(ctx context.Context, event types.ReceiverEvent) context.Context {
+	for _, hook := range h.eventStart {
+		if next := hook(ctx, event); next != nil {
+			ctx = next
+		}
+	}
+	return ctx
+}
+
+// endEvent runs the event end hooks
+func (h tracingHooks) endEvent(ctx context.Context, event types.ReceiverEvent, info ProcessEventInfo, err error) {
+	for i := len(h.eventEnd) - 1; i >= 0; i-- {
+		h.eventEnd[i](ctx, event, info, err)
+	}
+}
+
+// startListener runs the listener start hooks
+func (h tracingHooks) startListener(ctx context.Context, listener string) context.Context {
+	for _, hook := range h.listenerStart {
+		if next := hook(ctx, listener); next != nil {
+			ctx = next
+		}
+	}
+	return ctx
+}
+
+// endListener runs the listener end hooks
+func (h tracingHooks) endListener(ctx context.Context, listener string, err error) {
+	for i := len(h.listenerEnd) - 1; i >= 0; i-- {
+		h.listenerEnd[i](ctx, listener, err)
+	}
+}
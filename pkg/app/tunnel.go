@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// tunnelTarget is implemented by receivers serving Slack requests on a local port, such as
+// the HTTPReceiver
+type tunnelTarget interface {
+	Port() int
+	Endpoints() types.ReceiverEndpoints
+}
+
+// startTunnel starts the configured tunnel to the receiver and logs the request URLs to
+// configure in Slack
+func (a *App) startTunnel(ctx context.Context) error {
+	if a.tunnelProvider == nil {
+		return nil
+	}
+	target, ok := a.receiver.(tunnelTarget)
+	if !ok {
+		a.Logger.Warn("Tunnel is only supported with HTTP receivers, not starting it")
+		return nil
+	}
+
+	t, err := a.tunnelProvider.Start(ctx, fmt.Sprintf("localhost:%d", target.Port()))
+	if err != nil {
+		return fmt.Errorf("starting tunnel: %w", err)
+	}
+	a.mu.Lock()
+	a.tunnel = t
+	a.mu.Unlock()
+
+	base := strings.TrimSuffix(t.URL(), "/")
+	endpoints := target.Endpoints()
+	a.Logger.Info("Tunnel started",
+		"url", base,
+		"events_url", base+endpoints.Events,
+		"interactivity_url", base+endpoints.Interactive,
+		"commands_url", base+endpoints.Commands,
+		"options_url", base+endpoints.Options,
+	)
+	return nil
+}
+
+// closeTunnel stops the tunnel started with the app, if any
+func (a *App) closeTunnel() error {
+	a.mu.Lock()
+	t := a.tunnel
+	a.tunnel = nil
+	a.mu.Unlock()
+	if t == nil {
+		return nil
+	}
+	return t.Close()
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// TypedEventArgs are the arguments of listeners added with EventTyped: the event arguments with
+// the event decoded into T
+type TypedEventArgs[T types.TypedEvent] struct {
+	types.SlackEventMiddlewareArgs
+	// Event is the event decoded into T, shadowing SlackEventMiddlewareArgs.Event
+	Event T
+}
+
+// EventTyped adds a listener for the event type T decodes, such as types.AppMentionEvent, passing
+// it the event decoded into T. Go methods cannot have type parameters, so unlike Event it is a
+// function taking the app. Events that do not decode into T fail with the decoding error instead
+// of running the listener.
+func EventTyped[T types.TypedEvent](a *App, middleware ...types.Middleware[TypedEventArgs[T]]) *App {
+	var zero T
+	listeners := make([]types.Middleware[types.SlackEventMiddlewareArgs], 0, len(middleware))
+	for _, m := range middleware {
+		listeners = append(listeners, func(args types.SlackEventMiddlewareArgs) error {
+			event, err := helpers.DecodeEvent[T](args.Event)
+			if err != nil {
+				return err
+			}
+			return m(TypedEventArgs[T]{SlackEventMiddlewareArgs: args, Event: event})
+		})
+	}
+	return a.Event(zero.EventType(), listeners...)
+}
@@ -0,0 +1,287 @@
+// Package apphome keeps the Home tabs of an app's users up to date. A Manager remembers the view
+// it last published to each user, skips publishing views that did not change, coalesces
+// frequent updates into batches and recovers from hash conflicts, which Slack reports when the
+// tab was published elsewhere in the meantime.
+package apphome
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Defaults of Options
+const (
+	defaultBatchInterval = time.Second
+	defaultConcurrency   = 4
+)
+
+// lockShards is the number of locks the users are spread over, so publishes to one user are
+// serialized without keeping a lock per user
+const lockShards = 32
+
+// hashConflictError is the error Slack returns when the hash sent with a view is outdated
+const hashConflictError = "hash_conflict"
+
+// Publisher publishes Home tab views; *slack.Client implements it
+type Publisher interface {
+	PublishViewContext(ctx context.Context, req slack.PublishViewContextRequest) (*slack.ViewResponse, error)
+}
+
+// RenderFunc builds the current Home tab of a user
+type RenderFunc func(ctx context.Context, teamID, userID string) (slack.HomeTabViewRequest, error)
+
+// Options configures a Manager
+type Options struct {
+	// Store keeps the state of the published views (default an in-memory store)
+	Store Store
+	// Render rebuilds the Home tab of a user after a hash conflict, since the view being
+	// published may be based on outdated data. Without it the view is republished as is.
+	Render RenderFunc
+	// BatchInterval is how long Schedule collects updates before publishing them (default 1s)
+	BatchInterval time.Duration
+	// Concurrency is the number of views a batch publishes at once (default 4)
+	Concurrency int
+	Logger      types.Logger
+}
+
+// Manager publishes the Home tabs of users, see the package documentation
+type Manager struct {
+	store         Store
+	render        RenderFunc
+	batchInterval time.Duration
+	concurrency   int
+	logger        types.Logger
+	locks         [lockShards]sync.Mutex
+
+	// pendingMu guards the views scheduled for the next batch
+	pendingMu sync.Mutex
+	pending   map[string]scheduledView
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// scheduledView is a view waiting for the next batch
+type scheduledView struct {
+	client Publisher
+	teamID string
+	userID string
+	view   slack.HomeTabViewRequest
+}
+
+// NewManager creates a manager and starts publishing scheduled views in the background until it
+// is closed
+func NewManager(options Options) *Manager {
+	m := &Manager{
+		store:         options.Store,
+		render:        options.Render,
+		batchInterval: options.BatchInterval,
+		concurrency:   options.Concurrency,
+		logger:        options.Logger,
+		pending:       make(map[string]scheduledView),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	if m.store == nil {
+		m.store = NewMemoryStore()
+	}
+	if m.concurrency <= 0 {
+		m.concurrency = defaultConcurrency
+	}
+	if m.logger == nil {
+		m.logger = slog.Default()
+	}
+	if m.batchInterval <= 0 {
+		m.batchInterval = defaultBatchInterval
+	}
+
+	go m.run()
+	return m
+}
+
+// Publish publishes view as the Home tab of the user right away, unless it is the view last
+// published to them. It reports whether the view was published.
+func (m *Manager) Publish(ctx context.Context, client Publisher, teamID, userID string, view slack.HomeTabViewRequest) (bool, error) {
+	key := stateKey(teamID, userID)
+	lock := m.lock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if view.Type == "" {
+		view.Type = slack.VTHomeTab
+	}
+	digest, err := viewDigest(view)
+	if err != nil {
+		return false, err
+	}
+	state, err := m.store.Get(key)
+	if err != nil {
+		return false, err
+	}
+	if state != nil && state.Digest == digest {
+		return false, nil
+	}
+
+	request := slack.PublishViewContextRequest{UserID: userID, View: view}
+	if state != nil && state.Hash != "" {
+		hash := state.Hash
+		request.Hash = &hash
+	}
+	response, err := client.PublishViewContext(ctx, request)
+	if isHashConflict(err) {
+		// The tab was published elsewhere since: rebuild it from current data and publish it
+		// without a hash, so the latest content wins
+		m.logger.Debug("Home tab hash conflict, republishing", bolterrors.LogKeyTeamID, teamID, "user_id", userID)
+		if m.render != nil {
+			if request.View, err = m.render(ctx, teamID, userID); err != nil {
+				return false, err
+			}
+			if request.View.Type == "" {
+				request.View.Type = slack.VTHomeTab
+			}
+			if digest, err = viewDigest(request.View); err != nil {
+				return false, err
+			}
+		}
+		request.Hash = nil
+		response, err = client.PublishViewContext(ctx, request)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, m.store.Set(key, ViewState{
+		ViewID:      response.ID,
+		Hash:        response.Hash,
+		Digest:      digest,
+		PublishedAt: time.Now(),
+	})
+}
+
+// Schedule publishes view as the Home tab of the user with the next batch. A view scheduled for
+// the same user before the batch is sent is replaced, so bursts of updates cost one call.
+// Errors are logged.
+func (m *Manager) Schedule(client Publisher, teamID, userID string, view slack.HomeTabViewRequest) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	m.pending[stateKey(teamID, userID)] = scheduledView{client: client, teamID: teamID, userID: userID, view: view}
+}
+
+// Flush publishes the scheduled views now and returns their errors
+func (m *Manager) Flush(ctx context.Context) error {
+	m.pendingMu.Lock()
+	batch := m.pending
+	m.pending = make(map[string]scheduledView)
+	m.pendingMu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	var (
+		wg     sync.WaitGroup
+		errsMu sync.Mutex
+		errs   []error
+	)
+	slots := make(chan struct{}, m.concurrency)
+	for _, scheduled := range batch {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if _, err := m.Publish(ctx, scheduled.client, scheduled.teamID, scheduled.userID, scheduled.view); err != nil {
+				m.logger.Warn("Failed to publish Home tab", bolterrors.LogKeyError, err, bolterrors.LogKeyTeamID, scheduled.teamID, "user_id", scheduled.userID)
+				errsMu.Lock()
+				errs = append(errs, err)
+				errsMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// State returns the state of the view last published to the user, or nil when there is none
+func (m *Manager) State(teamID, userID string) (*ViewState, error) {
+	return m.store.Get(stateKey(teamID, userID))
+}
+
+// Forget drops the state of the user, so the next view is published whatever it contains, e.g.
+// after the user uninstalled the app
+func (m *Manager) Forget(teamID, userID string) error {
+	key := stateKey(teamID, userID)
+	m.pendingMu.Lock()
+	delete(m.pending, key)
+	m.pendingMu.Unlock()
+	return m.store.Delete(key)
+}
+
+// Close publishes the scheduled views and stops the background batches
+func (m *Manager) Close(ctx context.Context) error {
+	m.closeOnce.Do(func() { close(m.stop) })
+	select {
+	case <-m.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return m.Flush(ctx)
+}
+
+// run publishes the scheduled views every batch interval until the manager is closed
+func (m *Manager) run() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.batchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			// Errors are logged by Flush
+			_ = m.Flush(context.Background())
+		}
+	}
+}
+
+// lock returns the lock serializing the publishes of key
+func (m *Manager) lock(key string) *sync.Mutex {
+	hash := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+	return &m.locks[hash%lockShards]
+}
+
+// stateKey returns the store key of a user
+func stateKey(teamID, userID string) string {
+	return teamID + ":" + userID
+}
+
+// viewDigest identifies the content of view
+func viewDigest(view slack.HomeTabViewRequest) (string, error) {
+	encoded, err := json.Marshal(view)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isHashConflict reports whether err is Slack's hash_conflict error
+func isHashConflict(err error) bool {
+	var slackErr slack.SlackErrorResponse
+	return errors.As(err, &slackErr) && slackErr.Err == hashConflictError
+}
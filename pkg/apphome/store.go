@@ -0,0 +1,68 @@
+package apphome
+
+import (
+	"sync"
+	"time"
+)
+
+// ViewState is what a Manager remembers about the Home tab it last published to a user
+type ViewState struct {
+	// ViewID is the ID Slack gave the view
+	ViewID string `json:"view_id"`
+	// Hash is Slack's hash of the view, sent with the next publish so concurrent updates are
+	// detected
+	Hash string `json:"hash"`
+	// Digest identifies the content published, to skip republishing unchanged views
+	Digest string `json:"digest"`
+	// PublishedAt is when the view was published
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Store persists the ViewState of each user, keyed by team and user. Apps running several
+// instances share a store so each instance knows the views the others published.
+type Store interface {
+	// Get returns the state stored for key, or nil when there is none
+	Get(key string) (*ViewState, error)
+	// Set stores the state of key
+	Set(key string, state ViewState) error
+	// Delete removes the state of key
+	Delete(key string) error
+}
+
+// MemoryStore is the default in-memory implementation of Store
+type MemoryStore struct {
+	mu     sync.RWMutex
+	states map[string]ViewState
+}
+
+// NewMemoryStore creates a new in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]ViewState)}
+}
+
+// Get returns the state stored for key, or nil when there is none
+func (s *MemoryStore) Get(key string) (*ViewState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, ok := s.states[key]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// Set stores the state of key
+func (s *MemoryStore) Set(key string, state ViewState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[key] = state
+	return nil
+}
+
+// Delete removes the state of key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, key)
+	return nil
+}
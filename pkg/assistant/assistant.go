@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/Asafrose/bolt-go/pkg/errors"
 	"github.com/Asafrose/bolt-go/pkg/helpers"
@@ -32,8 +33,12 @@ type AssistantThreadContextStore interface {
 	Save(ctx context.Context, context *AssistantThreadContext) error
 }
 
-// DefaultThreadContextStore provides a default implementation
+// threadContextEventType is the metadata event type of messages carrying a thread context
+const threadContextEventType = "assistant_thread_context"
+
+// DefaultThreadContextStore keeps thread contexts in memory. It is safe for concurrent use.
 type DefaultThreadContextStore struct {
+	mu       sync.RWMutex
 	contexts map[string]*AssistantThreadContext
 	context  map[string]interface{} // Current context like JavaScript implementation
 }
@@ -49,7 +54,10 @@ func NewDefaultThreadContextStore() *DefaultThreadContextStore {
 // Get retrieves a thread context
 func (s *DefaultThreadContextStore) Get(ctx context.Context, channelID, threadTS string) (*AssistantThreadContext, error) {
 	key := channelID + ":" + threadTS
-	if context, exists := s.contexts[key]; exists {
+	s.mu.RLock()
+	context, exists := s.contexts[key]
+	s.mu.RUnlock()
+	if exists {
 		return context, nil
 	}
 
@@ -64,15 +72,20 @@ func (s *DefaultThreadContextStore) Get(ctx context.Context, channelID, threadTS
 // Save stores a thread context
 func (s *DefaultThreadContextStore) Save(ctx context.Context, context *AssistantThreadContext) error {
 	key := context.ChannelID + ":" + context.ThreadTS
+	s.mu.Lock()
 	s.contexts[key] = context
+	s.mu.Unlock()
 	return nil
 }
 
 // GetWithArgs retrieves a thread context using middleware args (like JavaScript implementation)
 func (s *DefaultThreadContextStore) GetWithArgs(args AllAssistantMiddlewareArgs) (map[string]interface{}, error) {
 	// If context is already saved to instance, return it
-	if channelID, exists := s.context["channel_id"]; exists && channelID != nil {
-		return s.context, nil
+	s.mu.RLock()
+	instanceContext := s.context
+	s.mu.RUnlock()
+	if channelID, exists := instanceContext["channel_id"]; exists && channelID != nil {
+		return instanceContext, nil
 	}
 
 	// Check if we have a Slack client
@@ -96,7 +109,10 @@ func (s *DefaultThreadContextStore) GetWithArgs(args AllAssistantMiddlewareArgs)
 func (s *DefaultThreadContextStore) GetWithArgsAndChannel(args AllAssistantMiddlewareArgs, channelID, threadTS string) (map[string]interface{}, error) {
 	// Check if context is already saved in memory
 	key := channelID + ":" + threadTS
-	if context, exists := s.contexts[key]; exists {
+	s.mu.RLock()
+	context, exists := s.contexts[key]
+	s.mu.RUnlock()
+	if exists {
 		return context.Context, nil
 	}
 
@@ -115,9 +131,9 @@ func (s *DefaultThreadContextStore) SaveWithArgs(args AllAssistantMiddlewareArgs
 			Context:   threadContext,
 		}
 		// Save to instance context and memory
+		s.mu.Lock()
 		s.context = threadContext
-		key := channelID + ":" + threadTS
-		s.contexts[key] = contextObj
+		s.mu.Unlock()
 		return s.Save(context.Background(), contextObj)
 	}
 
@@ -133,24 +149,27 @@ func (s *DefaultThreadContextStore) SaveWithArgs(args AllAssistantMiddlewareArgs
 	_ = client
 
 	// Save to instance
+	s.mu.Lock()
 	s.context = threadContext
-	key := channelID + ":" + threadTS
-	s.contexts[key] = &AssistantThreadContext{
+	s.mu.Unlock()
+	return s.Save(context.Background(), &AssistantThreadContext{
 		ChannelID: channelID,
 		ThreadTS:  threadTS,
 		Context:   threadContext,
-	}
-
-	return nil
+	})
 }
 
 // SetInstanceContext sets the context for the instance (helper for testing)
 func (s *DefaultThreadContextStore) SetInstanceContext(context map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.context = context
 }
 
 // GetInstanceContext gets the context from the instance (helper for testing)
 func (s *DefaultThreadContextStore) GetInstanceContext() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.context
 }
 
@@ -169,7 +188,8 @@ type SetTitleFn func(title string) error
 
 // SetSuggestedPromptsArguments represents arguments for setting suggested prompts
 type SetSuggestedPromptsArguments struct {
-	Prompts []string `json:"prompts"`
+	// Prompts are shown as buttons; clicking one sends its Message in the thread
+	Prompts []AssistantPrompt `json:"prompts"`
 }
 
 // AssistantPrompt represents a suggested prompt
@@ -245,14 +265,25 @@ func NewAssistant(config AssistantConfig) (*Assistant, error) {
 		threadContextStore = NewDefaultThreadContextStore()
 	}
 
+	// Like bolt-js, keep the stored context up to date when no handler is given
+	threadContextChanged := config.ThreadContextChanged
+	if len(threadContextChanged) == 0 {
+		threadContextChanged = []AssistantThreadContextChangedMiddleware{saveThreadContext}
+	}
+
 	return &Assistant{
 		threadContextStore:             threadContextStore,
 		threadStartedMiddleware:        config.ThreadStarted,
-		threadContextChangedMiddleware: config.ThreadContextChanged,
+		threadContextChangedMiddleware: threadContextChanged,
 		userMessageMiddleware:          config.UserMessage,
 	}, nil
 }
 
+// saveThreadContext is the default ThreadContextChanged handler, saving the thread's new context
+func saveThreadContext(args AssistantThreadContextChangedMiddlewareArgs) error {
+	return args.SaveThreadContext()
+}
+
 // GetMiddleware returns the middleware function for the app
 func (a *Assistant) GetMiddleware() types.Middleware[types.AllMiddlewareArgs] {
 	return func(args types.AllMiddlewareArgs) error {
@@ -349,7 +380,7 @@ func (a *Assistant) processThreadStarted(args types.AllMiddlewareArgs, eventArgs
 	// Extract channel and thread info
 	channelID, threadTS := a.extractChannelAndThread(eventArgs.Event)
 
-	utilityArgs := a.createUtilityArgs(args, channelID, threadTS)
+	utilityArgs := a.createUtilityArgs(args, eventArgs, channelID, threadTS)
 
 	middlewareArgs := AssistantThreadStartedMiddlewareArgs{
 		AllMiddlewareArgs:    args,
@@ -373,7 +404,7 @@ func (a *Assistant) processThreadContextChanged(args types.AllMiddlewareArgs, ev
 	// Extract channel and thread info
 	channelID, threadTS := a.extractChannelAndThread(eventArgs.Event)
 
-	utilityArgs := a.createUtilityArgs(args, channelID, threadTS)
+	utilityArgs := a.createUtilityArgs(args, eventArgs, channelID, threadTS)
 
 	middlewareArgs := AssistantThreadContextChangedMiddlewareArgs{
 		AllMiddlewareArgs:    args,
@@ -397,7 +428,7 @@ func (a *Assistant) processUserMessage(args types.AllMiddlewareArgs, eventArgs t
 	// Extract channel and thread info
 	channelID, threadTS := a.extractChannelAndThread(eventArgs.Event)
 
-	utilityArgs := a.createUtilityArgs(args, channelID, threadTS)
+	utilityArgs := a.createUtilityArgs(args, eventArgs, channelID, threadTS)
 
 	middlewareArgs := AssistantUserMessageMiddlewareArgs{
 		AllMiddlewareArgs:    args,
@@ -418,70 +449,174 @@ func (a *Assistant) processUserMessage(args types.AllMiddlewareArgs, eventArgs t
 }
 
 // createUtilityArgs creates utility arguments for assistant middleware
-func (a *Assistant) createUtilityArgs(args types.AllMiddlewareArgs, channelID, threadTS string) AssistantUtilityArgs {
-	var currentContext *AssistantThreadContext
+func (a *Assistant) createUtilityArgs(args types.AllMiddlewareArgs, eventArgs types.SlackEventMiddlewareArgs, channelID, threadTS string) AssistantUtilityArgs {
+	utilities := threadContextUtilities(a.threadContextStore, args, eventMapOf(eventArgs.Event), channelID, threadTS)
+	setters := threadSetters(args, channelID, threadTS)
+
+	utilities.Say = threadSay(eventArgs.Say, utilities.GetThreadContext, channelID, threadTS)
+	utilities.SetStatus = setters.SetStatus
+	utilities.SetSuggestedPrompts = setters.SetSuggestedPrompts
+	utilities.SetTitle = setters.SetTitle
+	return utilities
+}
+
+// threadContextUtilities returns GetThreadContext and SaveThreadContext utilities for the thread
+// at channelID and threadTS. Thread events carry the thread's current context, which they return
+// and save; the context of other events is loaded from store.
+func threadContextUtilities(store AssistantThreadContextStore, args types.AllMiddlewareArgs, event map[string]interface{}, channelID, threadTS string) AssistantUtilityArgs {
+	var current *AssistantThreadContext
+	if assistantThread, ok := event["assistant_thread"].(map[string]interface{}); ok {
+		if threadContext, ok := assistantThread["context"].(map[string]interface{}); ok {
+			current = &AssistantThreadContext{ChannelID: channelID, ThreadTS: threadTS, Context: threadContext}
+		}
+	}
 
 	return AssistantUtilityArgs{
 		GetThreadContext: func() (*AssistantThreadContext, error) {
-			if currentContext == nil {
-				ctx := context.Background() // Would use proper context
-				var err error
-				currentContext, err = a.threadContextStore.Get(ctx, channelID, threadTS)
+			if current == nil {
+				loaded, err := store.Get(args.API().Context(), channelID, threadTS)
 				if err != nil {
 					return nil, err
 				}
+				current = loaded
 			}
-			return currentContext, nil
+			return current, nil
 		},
 		SaveThreadContext: func() error {
-			if currentContext != nil {
-				ctx := context.Background() // Would use proper context
-				return a.threadContextStore.Save(ctx, currentContext)
+			if current == nil {
+				return nil
 			}
-			return nil
-		},
-		Say: func(message types.SayMessage) (*types.SayResponse, error) {
-			// This would use the actual say function from the context
-			return &types.SayResponse{}, nil
+			return store.Save(args.API().Context(), current)
 		},
+	}
+}
+
+// threadSay returns a say function posting in the thread at channelID and threadTS with say. Like
+// bolt-js, messages without metadata carry the thread context, so it can be recovered from them.
+func threadSay(say types.SayFn, getThreadContext GetThreadContextUtilFn, channelID, threadTS string) types.SayFn {
+	return func(message types.SayMessage) (*types.SayResponse, error) {
+		if say == nil {
+			return &types.SayResponse{}, errors.NewContextMissingPropertyError("say", "Assistant say needs a bot token to post in the thread")
+		}
+
+		var sayArgs types.SayArguments
+		switch msg := message.(type) {
+		case types.SayString:
+			sayArgs = types.SayArguments{Text: string(msg)}
+		case types.SayArguments:
+			sayArgs = msg
+		case *types.SayArguments:
+			if msg == nil {
+				return say(message)
+			}
+			sayArgs = *msg
+		default:
+			return say(message)
+		}
+
+		if sayArgs.Channel == "" {
+			sayArgs.Channel = channelID
+		}
+		if sayArgs.ThreadTS == "" {
+			sayArgs.ThreadTS = threadTS
+		}
+		if sayArgs.Metadata == nil {
+			threadContext, err := getThreadContext()
+			if err != nil {
+				return &types.SayResponse{}, err
+			}
+			if len(threadContext.Context) > 0 {
+				sayArgs.Metadata = types.MessageMetadata[map[string]interface{}]{
+					EventType:    threadContextEventType,
+					EventPayload: threadContext.Context,
+				}
+			}
+		}
+		return say(sayArgs)
+	}
+}
+
+// threadSetters returns SetStatus, SetSuggestedPrompts and SetTitle utilities calling the
+// assistant.threads API for the thread at channelID and threadTS
+func threadSetters(args types.AllMiddlewareArgs, channelID, threadTS string) AssistantUtilityArgs {
+	// check returns an error when the utilities cannot be called for the thread
+	check := func() error {
+		if channelID == "" || threadTS == "" {
+			return errors.NewAssistantMissingPropertyError("Assistant utilities need the channel_id and thread_ts of the event's thread")
+		}
+		if args.Client == nil {
+			return errors.NewContextMissingPropertyError("client", "Assistant utilities need a client to call the assistant.threads API")
+		}
+		return nil
+	}
+
+	return AssistantUtilityArgs{
 		SetStatus: func(status string) error {
-			// This would call the Slack API to set thread status
-			return nil
+			if err := check(); err != nil {
+				return err
+			}
+			return args.Client.SetAssistantThreadsStatusContext(args.API().Context(), slack.AssistantThreadsSetStatusParameters{
+				ChannelID: channelID,
+				ThreadTS:  threadTS,
+				Status:    status,
+			})
 		},
-		SetSuggestedPrompts: func(args SetSuggestedPromptsArguments) error {
-			// This would call the Slack API to set suggested prompts
-			return nil
+		SetSuggestedPrompts: func(promptArgs SetSuggestedPromptsArguments) error {
+			if err := check(); err != nil {
+				return err
+			}
+			params := slack.AssistantThreadsSetSuggestedPromptsParameters{
+				ChannelID: channelID,
+				ThreadTS:  threadTS,
+			}
+			for _, prompt := range promptArgs.Prompts {
+				params.AddPrompt(prompt.Title, prompt.Message)
+			}
+			return args.Client.SetAssistantThreadsSuggestedPromptsContext(args.API().Context(), params)
 		},
 		SetTitle: func(title string) error {
-			// This would call the Slack API to set thread title
-			return nil
+			if err := check(); err != nil {
+				return err
+			}
+			return args.Client.SetAssistantThreadsTitleContext(args.API().Context(), slack.AssistantThreadsSetTitleParameters{
+				ChannelID: channelID,
+				ThreadTS:  threadTS,
+				Title:     title,
+			})
 		},
 	}
 }
 
 // extractChannelAndThread extracts channel ID and thread timestamp from event data
 func (a *Assistant) extractChannelAndThread(event interface{}) (string, string) {
-	if eventMap, ok := event.(map[string]interface{}); ok {
-		var channelID, threadTS string
-
-		// Extract channel ID
-		if channel, exists := eventMap["channel"]; exists {
-			if channelStr, ok := channel.(string); ok {
-				channelID = channelStr
-			}
-		}
-
-		// Extract thread timestamp
-		if thread, exists := eventMap["thread_ts"]; exists {
-			if threadStr, ok := thread.(string); ok {
-				threadTS = threadStr
-			}
-		}
+	return threadOf(eventMapOf(event))
+}
 
-		return channelID, threadTS
+// eventMapOf returns the raw fields of an event
+func eventMapOf(event interface{}) map[string]interface{} {
+	switch event := event.(type) {
+	case *helpers.GenericSlackEvent:
+		return event.RawData
+	case map[string]interface{}:
+		return event
 	}
+	return nil
+}
 
-	return "", ""
+// threadOf returns the channel ID and thread timestamp of the assistant thread of an event: the
+// assistant_thread of thread events, or the thread a user message was posted in
+func threadOf(eventMap map[string]interface{}) (channelID, threadTS string) {
+	if assistantThread, ok := eventMap["assistant_thread"].(map[string]interface{}); ok {
+		channelID, _ = assistantThread["channel_id"].(string)
+		threadTS, _ = assistantThread["thread_ts"].(string)
+	}
+	if channelID == "" {
+		channelID, _ = eventMap["channel"].(string)
+	}
+	if threadTS == "" {
+		threadTS, _ = eventMap["thread_ts"].(string)
+	}
+	return channelID, threadTS
 }
 
 // isAssistantMessage checks if a message event is in an assistant thread
@@ -639,7 +774,8 @@ func ExtractThreadInfo(payload map[string]interface{}) (channelID, threadTS stri
 	return channelID, threadTS, context
 }
 
-// EnrichAssistantArgs enriches the middleware args with assistant utilities
+// EnrichAssistantArgs enriches the middleware args with assistant utilities bound to the thread of
+// the event. Say posts in the thread with the Say of args, failing when args has none.
 func EnrichAssistantArgs(store AssistantThreadContextStore, args AllAssistantMiddlewareArgs) AllAssistantMiddlewareArgs {
 	// Remove next from args to prevent continuation of middleware chain
 	enrichedArgs := AllAssistantMiddlewareArgs{
@@ -647,41 +783,30 @@ func EnrichAssistantArgs(store AssistantThreadContextStore, args AllAssistantMid
 			Context: args.Context,
 			Client:  args.Client,
 			Logger:  args.Logger,
+			Ctx:     args.Ctx,
 			// Next is deliberately omitted
 		},
 	}
 
-	// Add utility functions
-	enrichedArgs.GetThreadContext = func() (*AssistantThreadContext, error) {
-		// This would extract channel and thread from the current event context
-		return store.Get(context.Background(), "default_channel", "default_thread")
-	}
-
-	enrichedArgs.SaveThreadContext = func() error {
-		// This would save the current context
-		return nil
-	}
-
-	enrichedArgs.Say = func(message types.SayMessage) (*types.SayResponse, error) {
-		// This would use the client to post a message
-		// In a real implementation, this would use the actual client and channel/thread from context
-		return &types.SayResponse{}, nil
+	// Bind the utilities to the thread of the event being processed
+	var event map[string]interface{}
+	var channelID, threadTS string
+	if args.Context != nil {
+		if event, _ = helpers.ParseRequestBody(args.Context.RawBody)["event"].(map[string]interface{}); event != nil {
+			channelID, threadTS = threadOf(event)
+		}
 	}
 
-	enrichedArgs.SetStatus = func(status string) error {
-		// This would call the Slack API to set thread status
-		return nil
-	}
+	utilities := threadContextUtilities(store, args.AllMiddlewareArgs, event, channelID, threadTS)
+	enrichedArgs.GetThreadContext = utilities.GetThreadContext
+	enrichedArgs.SaveThreadContext = utilities.SaveThreadContext
 
-	enrichedArgs.SetSuggestedPrompts = func(args SetSuggestedPromptsArguments) error {
-		// This would call the Slack API to set suggested prompts
-		return nil
-	}
+	enrichedArgs.Say = threadSay(args.Say, utilities.GetThreadContext, channelID, threadTS)
 
-	enrichedArgs.SetTitle = func(title string) error {
-		// This would call the Slack API to set thread title
-		return nil
-	}
+	setters := threadSetters(args.AllMiddlewareArgs, channelID, threadTS)
+	enrichedArgs.SetStatus = setters.SetStatus
+	enrichedArgs.SetSuggestedPrompts = setters.SetSuggestedPrompts
+	enrichedArgs.SetTitle = setters.SetTitle
 
 	return enrichedArgs
 }
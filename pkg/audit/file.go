@@ -0,0 +1,49 @@
+// Package audit provides AuditSink implementations for AppOptions.Audit
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+)
+
+// FileSink appends audit records to a file as JSON lines
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+var _ app.AuditSink = (*FileSink)(nil)
+
+// NewFileSink opens path for appending, creating it readable only by its owner if needed
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Audit writes record as one line of JSON
+func (s *FileSink) Audit(ctx context.Context, record app.AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(line)
+	return err
+}
+
+// Close closes the file. Stop the app first so no record is written afterwards.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
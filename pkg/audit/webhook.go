@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+)
+
+// defaultWebhookTimeout bounds each webhook post when no HTTP client is given
+const defaultWebhookTimeout = 10 * time.Second
+
+// WebhookSinkOptions configures a WebhookSink
+type WebhookSinkOptions struct {
+	// URL receives each record as a JSON POST body
+	URL string
+	// Headers are added to every request, e.g. for authentication
+	Headers map[string]string
+	// Client sends the requests (default a client with a 10 second timeout)
+	Client *http.Client
+}
+
+// WebhookSink posts each audit record as JSON to a URL
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+var _ app.AuditSink = (*WebhookSink)(nil)
+
+// NewWebhookSink creates a WebhookSink from options
+func NewWebhookSink(options WebhookSinkOptions) (*WebhookSink, error) {
+	if options.URL == "" {
+		return nil, errors.New("audit webhook URL is required")
+	}
+	client := options.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	return &WebhookSink{url: options.URL, headers: options.Headers, client: client}, nil
+}
+
+// Audit posts record, failing unless the webhook answers with a 2xx status
+func (s *WebhookSink) Audit(ctx context.Context, record app.AuditRecord) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("audit webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
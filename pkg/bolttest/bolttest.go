@@ -0,0 +1,211 @@
+// Package bolttest helps unit-test bolt-go listeners. A Harness runs an app behind a fake
+// receiver, sends it payloads built by the functions in this package, records acks, and
+// captures the Slack API calls and response_url posts listeners make:
+//
+//	h := bolttest.New(t, bolt.AppOptions{})
+//	h.App.Command("/echo", func(args bolt.SlackCommandMiddlewareArgs) error {
+//		return args.Ack(&types.CommandResponse{Text: args.Command.Text})
+//	})
+//	h.Send(bolttest.Command("/echo", "hi")).AssertAcked(t)
+package bolttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/slack-go/slack"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Identifiers used by the payload builders and the default app options
+const (
+	TeamID        = "T0000TEST"
+	UserID        = "U0000TEST"
+	ChannelID     = "C0000TEST"
+	BotUserID     = "U0000BOT"
+	BotID         = "B0000BOT"
+	BotToken      = "xoxb-bolttest" //nolint:gosec
+	SigningSecret = "bolttest-signing-secret"
+)
+
+// errReceiverNotInitialized is returned when sending through a Receiver no app was created with
+var errReceiverNotInitialized = errors.New("bolttest: receiver not initialized")
+
+// Receiver is a receiver that does not listen for requests; Harness.Send delivers events instead
+type Receiver struct {
+	mu  sync.Mutex
+	app types.App
+}
+
+var _ types.Receiver = (*Receiver)(nil)
+
+// Init stores the app events are sent to
+func (r *Receiver) Init(app types.App) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.app = app
+	return nil
+}
+
+// Start does nothing
+func (r *Receiver) Start(ctx context.Context) error { return nil }
+
+// Stop does nothing
+func (r *Receiver) Stop(ctx context.Context) error { return nil }
+
+// Send passes payload to the app's ProcessEvent, recording its acks
+func (r *Receiver) Send(ctx context.Context, payload Payload) *Result {
+	r.mu.Lock()
+	target := r.app
+	r.mu.Unlock()
+
+	result := &Result{}
+	if target == nil {
+		result.Err = errReceiverNotInitialized
+		return result
+	}
+	result.Err = target.ProcessEvent(ctx, types.ReceiverEvent{
+		Body:        payload.Body,
+		Headers:     payload.Headers,
+		RetryNum:    payload.RetryNum,
+		RetryReason: payload.RetryReason,
+		Ack: func(response types.AckResponse) error {
+			result.mu.Lock()
+			defer result.mu.Unlock()
+			result.acks = append(result.acks, response)
+			return nil
+		},
+	})
+	return result
+}
+
+// Harness is an app wired to a fake receiver and a fake Slack server
+type Harness struct {
+	App      *app.App
+	Receiver *Receiver
+	Slack    *Slack
+}
+
+// New creates an app from options for the duration of the test. Unless set in options, the app
+// uses BotToken with BotID and BotUserID, SigningSecret, and a Receiver. Its Slack API calls
+// and response_url posts go to the fake Slack server.
+func New(t testing.TB, options app.AppOptions) *Harness {
+	t.Helper()
+
+	fake := NewSlack(t)
+	receiver := &Receiver{}
+	if options.Token == "" && options.Authorize == nil {
+		options.Token = BotToken
+		if options.BotID == "" {
+			options.BotID = BotID
+		}
+		if options.BotUserID == "" {
+			options.BotUserID = BotUserID
+		}
+	}
+	if options.SigningSecret == "" {
+		options.SigningSecret = SigningSecret
+	}
+	if options.Receiver == nil && !options.SocketMode {
+		options.Receiver = receiver
+	}
+	options.ClientOptions = append(options.ClientOptions, slack.OptionHTTPClient(fake.Client()))
+	if options.HTTPClient == nil {
+		options.HTTPClient = fake.Client()
+	}
+
+	application, err := app.New(options)
+	if err != nil {
+		t.Fatalf("bolttest: creating app: %v", err)
+	}
+	return &Harness{App: application, Receiver: receiver, Slack: fake}
+}
+
+// Send delivers payload to the app and returns what happened
+func (h *Harness) Send(payload Payload) *Result {
+	return h.Receiver.Send(context.Background(), payload)
+}
+
+// Result records how the app handled a payload
+type Result struct {
+	// Err is the error ProcessEvent returned
+	Err error
+
+	mu   sync.Mutex
+	acks []types.AckResponse
+}
+
+// Acks returns the responses passed to ack, in order
+func (r *Result) Acks() []types.AckResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]types.AckResponse(nil), r.acks...)
+}
+
+// AssertNoError fails the test if ProcessEvent returned an error
+func (r *Result) AssertNoError(t testing.TB) *Result {
+	t.Helper()
+	if r.Err != nil {
+		t.Errorf("bolttest: expected no error, got %v", r.Err)
+	}
+	return r
+}
+
+// AssertError fails the test unless ProcessEvent returned an error
+func (r *Result) AssertError(t testing.TB) *Result {
+	t.Helper()
+	if r.Err == nil {
+		t.Error("bolttest: expected an error")
+	}
+	return r
+}
+
+// AssertAcked fails the test unless the payload was acknowledged exactly once
+func (r *Result) AssertAcked(t testing.TB) *Result {
+	t.Helper()
+	if acks := r.Acks(); len(acks) != 1 {
+		t.Errorf("bolttest: expected 1 ack, got %d", len(acks))
+	}
+	return r
+}
+
+// AssertNotAcked fails the test if the payload was acknowledged
+func (r *Result) AssertNotAcked(t testing.TB) *Result {
+	t.Helper()
+	if acks := r.Acks(); len(acks) != 0 {
+		t.Errorf("bolttest: expected no ack, got %d", len(acks))
+	}
+	return r
+}
+
+// AckJSON returns the first ack response encoded as Slack would receive it and decoded into a
+// map, or nil for an empty ack. The test fails if the payload was not acknowledged.
+func (r *Result) AckJSON(t testing.TB) map[string]any {
+	t.Helper()
+	acks := r.Acks()
+	if len(acks) == 0 {
+		t.Fatal("bolttest: payload was not acknowledged")
+	}
+
+	switch response := acks[0].(type) {
+	case nil, types.AckVoid:
+		return nil
+	case types.AckString:
+		return map[string]any{"text": string(response)}
+	default:
+		data, err := json.Marshal(response)
+		if err != nil {
+			t.Fatalf("bolttest: encoding ack: %v", err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("bolttest: decoding ack: %v", err)
+		}
+		return decoded
+	}
+}
@@ -0,0 +1,28 @@
+package bolttest
+
+import (
+	"testing"
+
+	"github.com/Asafrose/bolt-go/pkg/capture"
+)
+
+// FromFixture returns the payload of a captured fixture
+func FromFixture(fixture *capture.Fixture) Payload {
+	return Payload{
+		Body:        []byte(fixture.Body),
+		Headers:     fixture.Headers,
+		RetryNum:    fixture.RetryNum,
+		RetryReason: fixture.RetryReason,
+	}
+}
+
+// LoadFixture returns the payload of the fixture captured at path, failing the test if it cannot
+// be read
+func LoadFixture(t testing.TB, path string) Payload {
+	t.Helper()
+	fixture, err := capture.Load(path)
+	if err != nil {
+		t.Fatalf("bolttest: loading fixture: %v", err)
+	}
+	return FromFixture(fixture)
+}
@@ -0,0 +1,194 @@
+package bolttest
+
+import (
+	"encoding/json"
+	"maps"
+	"net/url"
+)
+
+// Payload is a request body as a receiver passes it to the app
+type Payload struct {
+	Body        []byte
+	Headers     map[string]string
+	RetryNum    int
+	RetryReason string
+}
+
+// jsonPayload encodes body as a JSON payload, as the Events API sends and receivers pass
+// interactions on after decoding their payload form field
+func jsonPayload(body map[string]any) Payload {
+	data, _ := json.Marshal(body)
+	return Payload{Body: data, Headers: map[string]string{"Content-Type": "application/json"}}
+}
+
+// formPayload encodes values as a form payload, as slash commands and interactions are sent
+func formPayload(values url.Values) Payload {
+	return Payload{
+		Body:    []byte(values.Encode()),
+		Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+	}
+}
+
+// team and user are the team and user objects of interaction payloads
+var (
+	team = map[string]any{"id": TeamID, "domain": "bolttest"}
+	user = map[string]any{"id": UserID, "username": "bolttest", "team_id": TeamID}
+)
+
+// Event builds an Events API payload for an event of eventType. fields are added to the event,
+// overriding its defaults: the user and channel IDs and a timestamp.
+func Event(eventType string, fields map[string]any) Payload {
+	event := map[string]any{
+		"type":     eventType,
+		"user":     UserID,
+		"channel":  ChannelID,
+		"ts":       "1700000000.000100",
+		"event_ts": "1700000000.000100",
+	}
+	maps.Copy(event, fields)
+
+	return jsonPayload(map[string]any{
+		"token":        "bolttest-verification-token",
+		"team_id":      TeamID,
+		"api_app_id":   "A0000TEST",
+		"type":         "event_callback",
+		"event_id":     "Ev0000TEST",
+		"event_time":   1700000000,
+		"authed_users": []string{BotUserID},
+		"event":        event,
+	})
+}
+
+// Message builds a message event with text, posted by UserID in ChannelID
+func Message(text string) Payload {
+	return Event("message", map[string]any{"text": text, "channel_type": "channel"})
+}
+
+// AppMention builds an app_mention event with text mentioning the bot user
+func AppMention(text string) Payload {
+	return Event("app_mention", map[string]any{"text": "<@" + BotUserID + "> " + text})
+}
+
+// Command builds a slash command invocation of command, such as "/deploy", with text
+func Command(command, text string) Payload {
+	return formPayload(url.Values{
+		"token":        {"bolttest-verification-token"},
+		"command":      {command},
+		"text":         {text},
+		"team_id":      {TeamID},
+		"team_domain":  {"bolttest"},
+		"channel_id":   {ChannelID},
+		"channel_name": {"general"},
+		"user_id":      {UserID},
+		"user_name":    {"bolttest"},
+		"api_app_id":   {"A0000TEST"},
+		"response_url": {ResponseURL},
+		"trigger_id":   {"1.0.bolttest"},
+	})
+}
+
+// BlockAction builds a block_actions interaction for a button with actionID and value
+func BlockAction(actionID, value string) Payload {
+	return jsonPayload(map[string]any{
+		"type":         "block_actions",
+		"team":         team,
+		"user":         user,
+		"api_app_id":   "A0000TEST",
+		"trigger_id":   "1.0.bolttest",
+		"response_url": ResponseURL,
+		"channel":      map[string]any{"id": ChannelID, "name": "general"},
+		"container":    map[string]any{"type": "message", "channel_id": ChannelID, "message_ts": "1700000000.000100"},
+		"message":      map[string]any{"type": "message", "ts": "1700000000.000100", "text": ""},
+		"actions": []map[string]any{{
+			"type":      "button",
+			"action_id": actionID,
+			"block_id":  "bolttest_block",
+			"value":     value,
+			"action_ts": "1700000000.000200",
+		}},
+	})
+}
+
+// BlockSuggestion builds a block_suggestion request for the options of an external select
+// with actionID, as typed so far in value
+func BlockSuggestion(actionID, value string) Payload {
+	return jsonPayload(map[string]any{
+		"type":       "block_suggestion",
+		"team":       team,
+		"user":       user,
+		"api_app_id": "A0000TEST",
+		"action_id":  actionID,
+		"block_id":   "bolttest_block",
+		"value":      value,
+	})
+}
+
+// GlobalShortcut builds an invocation of the global shortcut with callbackID
+func GlobalShortcut(callbackID string) Payload {
+	return jsonPayload(map[string]any{
+		"type":        "shortcut",
+		"team":        team,
+		"user":        user,
+		"callback_id": callbackID,
+		"trigger_id":  "1.0.bolttest",
+		"action_ts":   "1700000000.000200",
+	})
+}
+
+// MessageShortcut builds an invocation of the message shortcut with callbackID on a message
+// with text in ChannelID
+func MessageShortcut(callbackID, text string) Payload {
+	return jsonPayload(map[string]any{
+		"type":         "message_action",
+		"team":         team,
+		"user":         user,
+		"callback_id":  callbackID,
+		"trigger_id":   "1.0.bolttest",
+		"action_ts":    "1700000000.000200",
+		"response_url": ResponseURL,
+		"channel":      map[string]any{"id": ChannelID, "name": "general"},
+		"message":      map[string]any{"type": "message", "user": UserID, "ts": "1700000000.000100", "text": text},
+		"message_ts":   "1700000000.000100",
+	})
+}
+
+// ViewSubmission builds the submission of a modal with callbackID. values are the view's state
+// values, keyed by block ID and then action ID, e.g.
+// {"title_block": {"title": {"type": "plain_text_input", "value": "Hello"}}}.
+func ViewSubmission(callbackID string, values map[string]any) Payload {
+	return jsonPayload(map[string]any{
+		"type":       "view_submission",
+		"team":       team,
+		"user":       user,
+		"api_app_id": "A0000TEST",
+		"trigger_id": "1.0.bolttest",
+		"view":       view(callbackID, values),
+	})
+}
+
+// ViewClosed builds the closing of a modal with callbackID that notifies the app on close
+func ViewClosed(callbackID string) Payload {
+	return jsonPayload(map[string]any{
+		"type":       "view_closed",
+		"team":       team,
+		"user":       user,
+		"api_app_id": "A0000TEST",
+		"view":       view(callbackID, nil),
+		"is_cleared": false,
+	})
+}
+
+// view builds a modal view with callbackID and state values
+func view(callbackID string, values map[string]any) map[string]any {
+	if values == nil {
+		values = map[string]any{}
+	}
+	return map[string]any{
+		"id":          "V0000TEST",
+		"type":        "modal",
+		"team_id":     TeamID,
+		"callback_id": callbackID,
+		"state":       map[string]any{"values": values},
+		"hash":        "bolttest-hash",
+	}
+}
@@ -0,0 +1,319 @@
+package bolttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// ResponseURL is the response_url of the payloads built by this package
+const ResponseURL = "https://hooks.slack.com/commands/" + TeamID + "/1/bolttest"
+
+// Call is a request captured by the fake Slack server
+type Call struct {
+	// Method is the Slack API method, such as "chat.postMessage", or empty for response_url posts
+	Method string
+	// URL is the URL the request was sent to before being redirected to the fake server, or
+	// empty for requests sent to APIURL
+	URL string
+	// Token is the token the request was authorized with
+	Token string
+	// Params are the request's form or JSON parameters; JSON values are kept as decoded
+	Params map[string]any
+}
+
+// Text returns the "text" parameter of the call
+func (c Call) Text() string {
+	text, _ := c.Params["text"].(string)
+	return text
+}
+
+// Slack is a fake Slack server that records every Slack API call and response_url post. API
+// calls succeed with responses shaped like Slack's for common methods, such as chat.postMessage,
+// views.open and functions.completeSuccess, and {"ok": true} for others, unless a response is
+// set with Handle or HandleFunc.
+type Slack struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	calls     []Call
+	responses map[string]func(Call) any
+	messages  int
+}
+
+// NewSlack starts a fake Slack server for the duration of the test
+func NewSlack(t testing.TB) *Slack {
+	s := &Slack{responses: make(map[string]func(Call) any)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// Client returns an HTTP client sending every request to the fake server, whatever its host
+func (s *Slack) Client() *http.Client {
+	target, _ := url.Parse(s.server.URL)
+	base := s.server.Client().Transport
+	return &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		redirected := req.Clone(req.Context())
+		redirected.Header.Set("X-Bolttest-Original-Url", req.URL.String())
+		redirected.URL.Scheme = target.Scheme
+		redirected.URL.Host = target.Host
+		redirected.Host = target.Host
+		return base.RoundTrip(redirected)
+	})}
+}
+
+// APIURL returns the fake server's Web API URL, for slack.OptionAPIURL
+func (s *Slack) APIURL() string {
+	return s.server.URL + "/api/"
+}
+
+// APIClient returns a Slack API client authorized with BotToken whose calls go to the fake server
+func (s *Slack) APIClient(options ...slack.Option) *slack.Client {
+	return slack.New(BotToken, append([]slack.Option{slack.OptionHTTPClient(s.Client())}, options...)...)
+}
+
+// Handle sets the JSON response of an API method, such as
+// map[string]any{"ok": false, "error": "channel_not_found"}
+func (s *Slack) Handle(method string, response any) {
+	s.HandleFunc(method, func(Call) any { return response })
+}
+
+// HandleFunc sets a function building the JSON response of an API method from the call
+func (s *Slack) HandleFunc(method string, handler func(call Call) any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[method] = handler
+}
+
+// Calls returns the recorded calls of an API method, or the response_url posts for ""
+func (s *Slack) Calls(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []Call
+	for _, call := range s.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Said returns the messages posted with chat.postMessage, as listeners' say does
+func (s *Slack) Said() []Call {
+	return s.Calls("chat.postMessage")
+}
+
+// Responded returns the messages posted to response URLs, as listeners' respond does
+func (s *Slack) Responded() []Call {
+	return s.Calls("")
+}
+
+// AssertSaid fails the test unless a message with text was posted with chat.postMessage
+func (s *Slack) AssertSaid(t testing.TB, text string) {
+	t.Helper()
+	assertTextCall(t, "said", s.Said(), text)
+}
+
+// AssertResponded fails the test unless a message with text was posted to a response URL
+func (s *Slack) AssertResponded(t testing.TB, text string) {
+	t.Helper()
+	assertTextCall(t, "responded", s.Responded(), text)
+}
+
+// AssertCalled fails the test unless the API method was called
+func (s *Slack) AssertCalled(t testing.TB, method string) {
+	t.Helper()
+	if len(s.Calls(method)) == 0 {
+		t.Errorf("bolttest: expected a call to %s", method)
+	}
+}
+
+// AssertCalledWith fails the test unless the API method was called with all of params. Form
+// parameters are strings; JSON parameters compare as decoded, e.g. numbers as float64.
+func (s *Slack) AssertCalledWith(t testing.TB, method string, params map[string]any) {
+	t.Helper()
+	calls := s.Calls(method)
+	for _, call := range calls {
+		if hasParams(call, params) {
+			return
+		}
+	}
+	received := make([]map[string]any, 0, len(calls))
+	for _, call := range calls {
+		received = append(received, call.Params)
+	}
+	t.Errorf("bolttest: expected a call to %s with %v, got %v", method, params, received)
+}
+
+// AssertNotCalled fails the test if the API method was called
+func (s *Slack) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	if calls := s.Calls(method); len(calls) != 0 {
+		t.Errorf("bolttest: expected no call to %s, got %d", method, len(calls))
+	}
+}
+
+// hasParams reports whether call was made with all of params
+func hasParams(call Call, params map[string]any) bool {
+	for key, want := range params {
+		got, ok := call.Params[key]
+		if !ok || !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// assertTextCall fails the test unless one of calls has text
+func assertTextCall(t testing.TB, verb string, calls []Call, text string) {
+	t.Helper()
+	var texts []string
+	for _, call := range calls {
+		if call.Text() == text {
+			return
+		}
+		texts = append(texts, call.Text())
+	}
+	t.Errorf("bolttest: expected to have %s %q, got %q", verb, text, texts)
+}
+
+// serve records a request and answers it
+func (s *Slack) serve(w http.ResponseWriter, r *http.Request) {
+	call := Call{
+		URL:    r.Header.Get("X-Bolttest-Original-Url"),
+		Token:  strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+		Params: requestParams(r),
+	}
+	if method, ok := strings.CutPrefix(r.URL.Path, "/api/"); ok {
+		call.Method = method
+	}
+	if token, ok := call.Params["token"].(string); ok && call.Token == "" {
+		call.Token = token
+	}
+
+	if call.Method == "" {
+		s.mu.Lock()
+		s.calls = append(s.calls, call)
+		s.mu.Unlock()
+		_, _ = w.Write([]byte("ok"))
+		return
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+	handler, ok := s.responses[call.Method]
+	s.mu.Unlock()
+
+	var response any
+	if ok {
+		response = handler(call)
+	} else {
+		response = s.defaultResponse(call)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// defaultResponse answers the common API methods the way Slack does
+func (s *Slack) defaultResponse(call Call) map[string]any {
+	switch call.Method {
+	case "chat.postMessage", "chat.scheduleMessage", "chat.update", "chat.delete", "chat.meMessage":
+		channel, _ := call.Params["channel"].(string)
+		ts, _ := call.Params["ts"].(string)
+		if ts == "" {
+			ts = s.nextTS()
+		}
+		response := map[string]any{"ok": true, "channel": channel, "ts": ts}
+		if call.Method == "chat.postMessage" || call.Method == "chat.update" {
+			response["message"] = map[string]any{"type": "message", "text": call.Text(), "user": BotUserID, "bot_id": BotID, "ts": ts}
+		}
+		if call.Method == "chat.scheduleMessage" {
+			response["scheduled_message_id"] = "Q0000TEST"
+			response["post_at"] = call.Params["post_at"]
+		}
+		return response
+	case "chat.postEphemeral":
+		return map[string]any{"ok": true, "message_ts": s.nextTS()}
+	case "views.open", "views.push", "views.update", "views.publish":
+		return map[string]any{"ok": true, "view": responseView(call)}
+	case "auth.test":
+		return map[string]any{
+			"ok":      true,
+			"url":     "https://bolttest.slack.com/",
+			"team":    "bolttest",
+			"user":    "bolttest",
+			"team_id": TeamID,
+			"user_id": BotUserID,
+			"bot_id":  BotID,
+		}
+	default:
+		return map[string]any{"ok": true}
+	}
+}
+
+// nextTS returns a new message timestamp
+func (s *Slack) nextTS() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages++
+	return fmt.Sprintf("1700000000.%06d", s.messages)
+}
+
+// responseView echoes the view of a views.* call with the fields Slack adds
+func responseView(call Call) map[string]any {
+	view := map[string]any{}
+	switch v := call.Params["view"].(type) {
+	case map[string]any:
+		view = maps.Clone(v)
+	case string:
+		_ = json.Unmarshal([]byte(v), &view)
+	}
+	id, _ := call.Params["view_id"].(string)
+	if id == "" {
+		id = "V0000TEST"
+	}
+	view["id"] = id
+	view["team_id"] = TeamID
+	view["hash"] = "bolttest-hash"
+	if _, ok := view["state"]; !ok {
+		view["state"] = map[string]any{"values": map[string]any{}}
+	}
+	return view
+}
+
+// requestParams decodes a form or JSON request body
+func requestParams(r *http.Request) map[string]any {
+	params := make(map[string]any)
+	body, err := io.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		return params
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		_ = json.Unmarshal(body, &params)
+		return params
+	}
+	values, _ := url.ParseQuery(string(body))
+	for key := range values {
+		params[key] = values.Get(key)
+	}
+	return params
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
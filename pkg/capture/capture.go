@@ -0,0 +1,250 @@
+// Package capture saves the events receivers pass to the app as normalized fixtures, so real
+// traffic can be turned into golden-file regression tests. Pass a Writer as the Capture option
+// of a receiver:
+//
+//	writer, err := capture.NewWriter("testdata/fixtures")
+//	receiver := receivers.NewHTTPReceiver(types.HTTPReceiverOptions{..., Capture: writer})
+//
+// and load the fixtures in tests with bolttest.LoadFixture.
+//
+// Fixtures keep only the headers that affect processing, have interaction payloads decoded
+// from their form field into JSON bodies, and have tokens and response URLs redacted.
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Redacted replaces secrets in fixtures
+const Redacted = "REDACTED"
+
+// RedactedResponseURL replaces response URLs in fixtures
+const RedactedResponseURL = "https://hooks.slack.com/redacted"
+
+// fixtureExtension is the file extension of fixtures
+const fixtureExtension = ".json"
+
+// keptHeaders are the headers fixtures keep; signatures, timestamps and proxy headers are dropped
+var keptHeaders = []string{"Content-Type", "X-Slack-Retry-Num", "X-Slack-Retry-Reason"}
+
+// tokenPattern matches Slack tokens wherever they appear in a payload
+var tokenPattern = regexp.MustCompile(`\b(xox[a-z]|xapp)-[A-Za-z0-9-]+`)
+
+// Fixture is a normalized ReceiverEvent
+type Fixture struct {
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        string            `json:"body"`
+	RetryNum    int               `json:"retry_num,omitempty"`
+	RetryReason string            `json:"retry_reason,omitempty"`
+}
+
+// Event returns the fixture as a ReceiverEvent without an ack function
+func (f *Fixture) Event() types.ReceiverEvent {
+	return types.ReceiverEvent{
+		Body:        []byte(f.Body),
+		Headers:     f.Headers,
+		RetryNum:    f.RetryNum,
+		RetryReason: f.RetryReason,
+	}
+}
+
+// Normalize builds the fixture of event: headers other than the content type and retry headers
+// are dropped, form bodies carrying an interaction payload are replaced by the payload as JSON,
+// JSON bodies are re-encoded with sorted keys, and tokens and response URLs are redacted
+func Normalize(event types.ReceiverEvent) *Fixture {
+	fixture := &Fixture{
+		Headers:     make(map[string]string),
+		RetryNum:    event.RetryNum,
+		RetryReason: event.RetryReason,
+	}
+	for key, value := range event.Headers {
+		key = http.CanonicalHeaderKey(key)
+		for _, kept := range keptHeaders {
+			if key == kept {
+				fixture.Headers[key] = value
+			}
+		}
+	}
+
+	body := event.Body
+	if values, ok := formBody(fixture.Headers["Content-Type"], body); ok {
+		if payload := values.Get("payload"); payload != "" {
+			body = []byte(payload)
+			fixture.Headers["Content-Type"] = "application/json"
+		} else {
+			for key := range values {
+				values.Set(key, redactString(key, values.Get(key)))
+			}
+			fixture.Body = values.Encode()
+			return fixture
+		}
+	}
+
+	var decoded any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		fixture.Body = tokenPattern.ReplaceAllString(string(body), Redacted)
+		return fixture
+	}
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(redact("", decoded))
+	fixture.Body = strings.TrimSuffix(encoded.String(), "\n")
+	return fixture
+}
+
+// formBody parses body when it is form encoded
+func formBody(contentType string, body []byte) (url.Values, bool) {
+	if !strings.HasPrefix(strings.ToLower(contentType), "application/x-www-form-urlencoded") {
+		return nil, false
+	}
+	values, err := url.ParseQuery(string(body))
+	return values, err == nil
+}
+
+// redact replaces the secrets in a decoded JSON value found under key
+func redact(key string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = redact(k, child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = redact(key, child)
+		}
+		return v
+	case string:
+		return redactString(key, v)
+	default:
+		return v
+	}
+}
+
+// redactString replaces a string value that is a secret or contains tokens
+func redactString(key, value string) string {
+	switch {
+	case value == "":
+		return value
+	case key == "token" || strings.HasSuffix(key, "_token"):
+		return Redacted
+	case key == "response_url":
+		return RedactedResponseURL
+	default:
+		return tokenPattern.ReplaceAllString(value, Redacted)
+	}
+}
+
+// Writer writes each captured event to its own fixture file in a directory
+type Writer struct {
+	dir string
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+var _ types.EventCapturer = (*Writer)(nil)
+
+// NewWriter creates a Writer writing to dir, creating it if needed
+func NewWriter(dir string) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Writer{dir: dir}, nil
+}
+
+// Capture writes the fixture of event to a file named after the time it arrived and its kind,
+// such as "app_mention" or "block_actions", so listing the directory gives the events in order
+func (w *Writer) Capture(ctx context.Context, event types.ReceiverEvent) error {
+	fixture := Normalize(event)
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	now := time.Now().UTC()
+	if !now.After(w.last) {
+		now = w.last.Add(time.Nanosecond)
+	}
+	w.last = now
+	w.mu.Unlock()
+
+	name := now.Format("20060102T150405.000000000Z")
+	if kind := fixture.Kind(); kind != "" {
+		name += "-" + kind
+	}
+	return os.WriteFile(filepath.Join(w.dir, name+fixtureExtension), data, 0o600)
+}
+
+// kindPattern matches the characters kept in fixture kinds
+var kindPattern = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// Kind describes the fixture's event: the event type for Events API payloads, the command for
+// slash commands, or the interaction type
+func (f *Fixture) Kind() string {
+	var kind string
+	if values, ok := formBody(f.Headers["Content-Type"], []byte(f.Body)); ok {
+		kind = values.Get("command")
+	} else {
+		var body struct {
+			Type  string `json:"type"`
+			Event struct {
+				Type string `json:"type"`
+			} `json:"event"`
+		}
+		if json.Unmarshal([]byte(f.Body), &body) == nil {
+			kind = body.Event.Type
+			if kind == "" {
+				kind = body.Type
+			}
+		}
+	}
+	return strings.Trim(kindPattern.ReplaceAllString(kind, "_"), "_")
+}
+
+// Load reads the fixture at path
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// Paths returns the fixture files in dir in the order they were captured
+func Paths(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), fixtureExtension) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
@@ -16,10 +16,19 @@ type ConversationStore interface {
 	Delete(conversationID string) error
 }
 
+// memoryStoreShards is the number of independently locked maps a MemoryStore spreads
+// conversations over, so concurrent conversations rarely contend for the same lock
+const memoryStoreShards = 32
+
 // MemoryStore is the default in-memory implementation of ConversationStore
 // This should not be used in situations where there is more than one instance
 // of the app running because state will not be shared amongst the processes.
 type MemoryStore struct {
+	shards [memoryStoreShards]memoryStoreShard
+}
+
+// memoryStoreShard holds the conversations whose IDs hash to it
+type memoryStoreShard struct {
 	mu    sync.RWMutex
 	state map[string]*conversationEntry
 }
@@ -31,17 +40,30 @@ type conversationEntry struct {
 
 // NewMemoryStore creates a new in-memory conversation store
 func NewMemoryStore() *MemoryStore {
-	return &MemoryStore{
-		state: make(map[string]*conversationEntry),
+	s := &MemoryStore{}
+	for i := range s.shards {
+		s.shards[i].state = make(map[string]*conversationEntry)
+	}
+	return s
+}
+
+// shard returns the shard for conversationID, chosen by its FNV-1a hash
+func (s *MemoryStore) shard(conversationID string) *memoryStoreShard {
+	hash := uint32(2166136261)
+	for i := 0; i < len(conversationID); i++ {
+		hash ^= uint32(conversationID[i])
+		hash *= 16777619
 	}
+	return &s.shards[hash%memoryStoreShards]
 }
 
 // Set stores conversation state with optional expiration
 func (s *MemoryStore) Set(conversationID string, value any, expiresAt *time.Time) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	shard := s.shard(conversationID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
 
-	s.state[conversationID] = &conversationEntry{
+	shard.state[conversationID] = &conversationEntry{
 		Value:     value,
 		ExpiresAt: expiresAt,
 	}
@@ -51,18 +73,23 @@ func (s *MemoryStore) Set(conversationID string, value any, expiresAt *time.Time
 
 // Get retrieves conversation state
 func (s *MemoryStore) Get(conversationID string) (any, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	shard := s.shard(conversationID)
+	shard.mu.RLock()
+	entry, exists := shard.state[conversationID]
+	shard.mu.RUnlock()
 
-	entry, exists := s.state[conversationID]
 	if !exists {
 		return nil, errors.New("conversation not found")
 	}
 
 	// Check if expired
 	if entry.ExpiresAt != nil && time.Now().After(*entry.ExpiresAt) {
-		// Clean up expired entry
-		delete(s.state, conversationID)
+		// Clean up expired entry, unless it was replaced in the meantime
+		shard.mu.Lock()
+		if shard.state[conversationID] == entry {
+			delete(shard.state, conversationID)
+		}
+		shard.mu.Unlock()
 		return nil, errors.New("conversation expired")
 	}
 
@@ -71,22 +98,25 @@ func (s *MemoryStore) Get(conversationID string) (any, error) {
 
 // Delete removes conversation state
 func (s *MemoryStore) Delete(conversationID string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	shard := s.shard(conversationID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
 
-	delete(s.state, conversationID)
+	delete(shard.state, conversationID)
 	return nil
 }
 
 // CleanupExpired removes all expired entries
 func (s *MemoryStore) CleanupExpired() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	now := time.Now()
-	for id, entry := range s.state {
-		if entry.ExpiresAt != nil && now.After(*entry.ExpiresAt) {
-			delete(s.state, id)
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mu.Lock()
+		for id, entry := range shard.state {
+			if entry.ExpiresAt != nil && now.After(*entry.ExpiresAt) {
+				delete(shard.state, id)
+			}
 		}
+		shard.mu.Unlock()
 	}
 }
@@ -0,0 +1,71 @@
+// Package dedupe provides EventDeduper implementations for AppOptions.Deduper backed by shared
+// stores, so several replicas of an app process each event once.
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultRedisPrefix prefixes keys when no prefix is configured
+const defaultRedisPrefix = "bolt:dedupe:"
+
+// RedisClient runs Redis commands; *redis.Client, *redis.ClusterClient and *redis.Ring implement it
+type RedisClient interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// RedisDeduperOptions configures a RedisDeduper
+type RedisDeduperOptions struct {
+	// Client runs the commands
+	Client RedisClient
+	// Prefix prefixes every key (default "bolt:dedupe:"), e.g. to share a database between apps
+	Prefix string
+	// TTL is how long an event is remembered (default app.DefaultDedupeTTL)
+	TTL time.Duration
+}
+
+// RedisDeduper remembers processed events in Redis with SET NX, so the first replica to see an
+// event processes it and the others drop it
+type RedisDeduper struct {
+	client RedisClient
+	prefix string
+	ttl    time.Duration
+}
+
+var _ app.EventDeduper = (*RedisDeduper)(nil)
+
+// NewRedisDeduper creates a RedisDeduper from options
+func NewRedisDeduper(options RedisDeduperOptions) (*RedisDeduper, error) {
+	if options.Client == nil {
+		return nil, errors.New("redis client is required")
+	}
+	prefix := options.Prefix
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+	ttl := options.TTL
+	if ttl <= 0 {
+		ttl = app.DefaultDedupeTTL
+	}
+	return &RedisDeduper{client: options.Client, prefix: prefix, ttl: ttl}, nil
+}
+
+// Seen sets key unless it exists and reports whether it existed
+func (d *RedisDeduper) Seen(ctx context.Context, key string) (bool, error) {
+	set, err := d.client.SetNX(ctx, d.prefix+key, time.Now().Unix(), d.ttl).Result()
+	if err != nil {
+		return false, err
+	}
+	return !set, nil
+}
+
+// Forget deletes key
+func (d *RedisDeduper) Forget(ctx context.Context, key string) error {
+	return d.client.Del(ctx, d.prefix+key).Err()
+}
@@ -0,0 +1,328 @@
+// Package errorreport provides ErrorReporter implementations for AppOptions.ErrorReporter
+package errorreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Defaults of SentryOptions
+const (
+	defaultSentryTimeout    = 10 * time.Second
+	defaultSentryBufferSize = 100
+)
+
+// maxExceptionChain bounds the number of wrapped errors sent as chained exceptions
+const maxExceptionChain = 10
+
+// SentryOptions configures a SentryReporter
+type SentryOptions struct {
+	// DSN is the project's client key, such as "https://public@o0.ingest.sentry.io/42"
+	DSN string
+	// Environment, Release and ServerName are set on every event when not empty
+	Environment string
+	Release     string
+	ServerName  string
+	// Tags are added to every event, alongside tags describing the failed request
+	Tags map[string]string
+	// Client sends the events (default a client with a 10 second timeout)
+	Client *http.Client
+	// BufferSize is the number of reports queued while events are being sent (default 100).
+	// Reports arriving while the queue is full are dropped and logged.
+	BufferSize int
+	// Logger logs reports that could not be sent (default slog.Default())
+	Logger types.Logger
+}
+
+// SentryReporter sends error reports to Sentry as events, in the background
+type SentryReporter struct {
+	endpoint    string
+	auth        string
+	dsn         string
+	environment string
+	release     string
+	serverName  string
+	tags        map[string]string
+	client      *http.Client
+	logger      types.Logger
+
+	events chan sentryEvent
+	done   chan struct{}
+	// mu guards closed so no event is queued after the queue is closed
+	mu     sync.RWMutex
+	closed bool
+}
+
+var _ app.ErrorReporter = (*SentryReporter)(nil)
+
+// NewSentryReporter creates a SentryReporter from options and starts sending its events
+func NewSentryReporter(options SentryOptions) (*SentryReporter, error) {
+	endpoint, key, err := parseDSN(options.DSN)
+	if err != nil {
+		return nil, err
+	}
+	client := options.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultSentryTimeout}
+	}
+	size := options.BufferSize
+	if size <= 0 {
+		size = defaultSentryBufferSize
+	}
+	logger := options.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	r := &SentryReporter{
+		endpoint:    endpoint,
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_key=%s, sentry_client=bolt-go", key),
+		dsn:         options.DSN,
+		environment: options.Environment,
+		release:     options.Release,
+		serverName:  options.ServerName,
+		tags:        options.Tags,
+		client:      client,
+		logger:      logger,
+		events:      make(chan sentryEvent, size),
+		done:        make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+// parseDSN returns the envelope endpoint and public key of a Sentry DSN
+func parseDSN(dsn string) (string, string, error) {
+	if dsn == "" {
+		return "", "", errors.New("sentry DSN is required")
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid sentry DSN: %w", err)
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	if u.Scheme == "" || u.Host == "" || u.User == nil || u.User.Username() == "" || slash < 0 || path[slash+1:] == "" {
+		return "", "", errors.New("invalid sentry DSN: expected scheme://key@host/project_id")
+	}
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, path[:slash], path[slash+1:])
+	return endpoint, u.User.Username(), nil
+}
+
+// ReportError queues report to be sent as a Sentry event
+func (r *SentryReporter) ReportError(ctx context.Context, report app.ErrorReport) {
+	event := r.newEvent(report)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.events <- event:
+	default:
+		r.logger.Warn("Sentry queue full, dropped error report", "correlation_id", report.CorrelationID)
+	}
+}
+
+// Close stops accepting reports and waits until queued events are sent or ctx is done
+func (r *SentryReporter) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run sends queued events until the queue is closed
+func (r *SentryReporter) run() {
+	defer close(r.done)
+	for event := range r.events {
+		if err := r.send(event); err != nil {
+			r.logger.Warn("Failed to send error report to Sentry", "error", err, "event_id", event.EventID)
+		}
+	}
+}
+
+// send posts event in an envelope
+func (r *SentryReporter) send(event sentryEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	header, err := json.Marshal(map[string]string{
+		"event_id": event.EventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+		"dsn":      r.dsn,
+	})
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	body.Write(header)
+	fmt.Fprintf(&body, "\n{\"type\":\"event\",\"length\":%d}\n", len(payload))
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sentry returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sentryEvent is the subset of the Sentry event payload sent for error reports
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger"`
+	Transaction string            `json:"transaction,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Exception   sentryExceptions  `json:"exception"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	User        *sentryUser       `json:"user,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+// newEvent converts report to a Sentry event
+func (r *SentryReporter) newEvent(report app.ErrorReport) sentryEvent {
+	event := sentryEvent{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       "error",
+		Logger:      "bolt",
+		Environment: r.environment,
+		Release:     r.release,
+		ServerName:  r.serverName,
+		Exception:   sentryExceptions{Values: exceptionChain(report.Err)},
+		Tags:        make(map[string]string),
+		Extra:       map[string]any{"retry_num": report.RetryNum},
+	}
+
+	// Group the issues by the listener that failed, or else by the kind of request
+	switch {
+	case len(report.Listeners) > 0:
+		event.Transaction = report.Listeners[0]
+	case report.EventType != "":
+		event.Transaction = report.Type + "(type=" + report.EventType + ")"
+	default:
+		event.Transaction = report.Type
+	}
+
+	for key, value := range r.tags {
+		event.Tags[key] = value
+	}
+	for key, value := range map[string]string{
+		"error_code":     string(report.Code),
+		"type":           report.Type,
+		"event_type":     report.EventType,
+		"team_id":        report.TeamID,
+		"enterprise_id":  report.EnterpriseID,
+		"correlation_id": report.CorrelationID,
+		"listener":       strings.Join(report.Listeners, ","),
+	} {
+		if value != "" {
+			event.Tags[key] = value
+		}
+	}
+	if report.UserID != "" {
+		event.User = &sentryUser{ID: report.UserID}
+	}
+	if len(report.Payload) > 0 {
+		event.Extra["payload"] = report.Payload
+	}
+	if len(report.Listeners) > 1 {
+		event.Extra["listeners"] = report.Listeners
+	}
+	return event
+}
+
+// exceptionChain describes err and the errors it wraps, innermost first as Sentry expects
+func exceptionChain(err error) []sentryException {
+	var chain []sentryException
+	for err != nil && len(chain) < maxExceptionChain {
+		chain = append(chain, sentryException{
+			Type:  strings.TrimPrefix(fmt.Sprintf("%T", err), "*"),
+			Value: err.Error(),
+		})
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() error }:
+			err = wrapped.Unwrap()
+		case interface{ Unwrap() []error }:
+			if errs := wrapped.Unwrap(); len(errs) > 0 {
+				err = errs[0]
+			} else {
+				err = nil
+			}
+		default:
+			err = nil
+		}
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// newEventID returns a random 32 character hex event ID
+func newEventID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // ErrorCode represents error codes used throughout the framework
@@ -27,6 +28,7 @@ const (
 	ReceiverInconsistentStateError ErrorCode = "slack_bolt_receiver_inconsistent_state_error"
 
 	MultipleListenerErrorCode ErrorCode = "slack_bolt_multiple_listener_error"
+	MultipleNextCallErrorCode ErrorCode = "slack_bolt_multiple_next_call_error"
 
 	HTTPReceiverDeferredRequestErrorCode ErrorCode = "slack_bolt_http_receiver_deferred_request_error"
 
@@ -34,13 +36,24 @@ const (
 
 	EventProcessingError ErrorCode = "slack_bolt_event_processing_error"
 
+	TeamConcurrencyLimitErrorCode ErrorCode = "slack_bolt_team_concurrency_limit_error"
+	ConcurrencyLimitErrorCode     ErrorCode = "slack_bolt_concurrency_limit_error"
+
+	UserTokenErrorCode ErrorCode = "slack_bolt_user_token_error"
+
 	WorkflowStepInitializationErrorCode ErrorCode = "slack_bolt_workflow_step_initialization_error"
 
 	CustomFunctionInitializationErrorCode  ErrorCode = "slack_bolt_custom_function_initialization_error"
 	CustomFunctionCompleteSuccessErrorCode ErrorCode = "slack_bolt_custom_function_complete_success_error"
 	CustomFunctionCompleteFailErrorCode    ErrorCode = "slack_bolt_custom_function_complete_fail_error"
+	FunctionParameterErrorCode             ErrorCode = "slack_bolt_function_parameter_error"
 )
 
+// Error allows error codes to be used as sentinel errors with errors.Is
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
 // CodedError represents an error with a specific error code
 type CodedError interface {
 	error
@@ -73,6 +86,17 @@ func (e BaseError) Originals() []error {
 	return e.originals
 }
 
+// Unwrap returns the original error, if any
+func (e BaseError) Unwrap() error {
+	return e.original
+}
+
+// Is reports whether the target is the error code of this error
+func (e BaseError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == e.code
+}
+
 // NewBaseError creates a new BaseError
 func NewBaseError(code ErrorCode, message string) *BaseError {
 	return &BaseError{
@@ -105,6 +129,72 @@ func AsCodedError(err error) CodedError {
 	return NewUnknownError(err)
 }
 
+// RetryableError is implemented by errors that tell receivers whether Slack should redeliver the request.
+// Receivers withhold the ack for retryable errors and acknowledge (and log) non-retryable ones.
+type RetryableError interface {
+	error
+	Retryable() bool
+}
+
+// retryClassifiedError marks a wrapped error as retryable or non-retryable
+type retryClassifiedError struct {
+	err       error
+	retryable bool
+}
+
+func (e *retryClassifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryClassifiedError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryClassifiedError) Retryable() bool {
+	return e.retryable
+}
+
+// NewRetryableError marks err as retryable so the receiver does not acknowledge the request
+func NewRetryableError(err error) error {
+	return &retryClassifiedError{err: err, retryable: true}
+}
+
+// NewNonRetryableError marks err as non-retryable so the receiver acknowledges the request
+func NewNonRetryableError(err error) error {
+	return &retryClassifiedError{err: err, retryable: false}
+}
+
+// IsRetryable reports whether err or any error it wraps is marked as retryable
+func IsRetryable(err error) bool {
+	return anyRetryClassification(err, true)
+}
+
+// IsNonRetryable reports whether err is marked as non-retryable and wraps no retryable errors
+func IsNonRetryable(err error) bool {
+	return !IsRetryable(err) && anyRetryClassification(err, false)
+}
+
+// anyRetryClassification walks the error tree looking for a RetryableError with the given classification
+func anyRetryClassification(err error, retryable bool) bool {
+	if err == nil {
+		return false
+	}
+	if classified, ok := err.(RetryableError); ok && classified.Retryable() == retryable {
+		return true
+	}
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		return anyRetryClassification(wrapped.Unwrap(), retryable)
+	case interface{ Unwrap() []error }:
+		for _, e := range wrapped.Unwrap() {
+			if anyRetryClassification(e, retryable) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Specific error types
 
 // AppInitializationErrorType represents an app initialization error
@@ -146,6 +236,17 @@ func NewAssistantMissingPropertyError(message string) *AssistantMissingPropertyE
 // AuthorizationErrorType represents an authorization error
 type AuthorizationError struct {
 	*BaseError
+	// Source identifies the installation that failed to authorize, if known
+	Source *AuthorizationSource
+}
+
+// AuthorizationSource mirrors the authorize source data of the request that failed to authorize
+type AuthorizationSource struct {
+	TeamID              string `json:"team_id,omitempty"`
+	EnterpriseID        string `json:"enterprise_id,omitempty"`
+	UserID              string `json:"user_id,omitempty"`
+	ConversationID      string `json:"conversation_id,omitempty"`
+	IsEnterpriseInstall bool   `json:"is_enterprise_install"`
 }
 
 // NewAuthorizationError creates a new AuthorizationError
@@ -155,6 +256,16 @@ func NewAuthorizationError(message string, original error) *AuthorizationError {
 	}
 }
 
+// NewAuthorizationErrorWithSource creates a new AuthorizationError for the installation described by source
+func NewAuthorizationErrorWithSource(message string, original error, source AuthorizationSource) *AuthorizationError {
+	message = fmt.Sprintf("%s (team_id=%s, enterprise_id=%s, user_id=%s, conversation_id=%s, is_enterprise_install=%t)",
+		message, source.TeamID, source.EnterpriseID, source.UserID, source.ConversationID, source.IsEnterpriseInstall)
+	return &AuthorizationError{
+		BaseError: NewBaseErrorWithOriginal(AuthorizationErrorCode, message, original),
+		Source:    &source,
+	}
+}
+
 // ContextMissingPropertyErrorType represents a context missing property error
 type ContextMissingPropertyError struct {
 	*BaseError
@@ -193,6 +304,18 @@ func NewReceiverMultipleAckError() *ReceiverMultipleAckError {
 	}
 }
 
+// MultipleNextCallError is returned when a middleware calls its `next` function more than once
+type MultipleNextCallError struct {
+	*BaseError
+}
+
+// NewMultipleNextCallError creates a new MultipleNextCallError
+func NewMultipleNextCallError() *MultipleNextCallError {
+	return &MultipleNextCallError{
+		BaseError: NewBaseError(MultipleNextCallErrorCode, "A middleware's `next` function was called multiple times."),
+	}
+}
+
 // ReceiverAuthenticityError represents a receiver authenticity error
 type ReceiverAuthenticityError struct {
 	*BaseError
@@ -229,6 +352,13 @@ type MultipleListenerError struct {
 // NewMultipleListenerError creates a new MultipleListenerError
 func NewMultipleListenerError(originals []error) *MultipleListenerError {
 	message := fmt.Sprintf("Multiple errors occurred while handling several listeners. %d errors occurred.", len(originals))
+	if len(originals) > 0 {
+		details := make([]string, 0, len(originals))
+		for _, original := range originals {
+			details = append(details, original.Error())
+		}
+		message = fmt.Sprintf("%s %s", message, strings.Join(details, "; "))
+	}
 	return &MultipleListenerError{
 		BaseError: &BaseError{
 			code:      MultipleListenerErrorCode,
@@ -238,6 +368,56 @@ func NewMultipleListenerError(originals []error) *MultipleListenerError {
 	}
 }
 
+// Unwrap returns the individual listener errors so errors.Is and errors.As can inspect each of them
+func (e *MultipleListenerError) Unwrap() []error {
+	return e.originals
+}
+
+// Failures returns the listener errors that identify which listener failed
+func (e *MultipleListenerError) Failures() []*ListenerError {
+	failures := make([]*ListenerError, 0, len(e.originals))
+	for _, original := range e.originals {
+		var listenerErr *ListenerError
+		if errors.As(original, &listenerErr) {
+			failures = append(failures, listenerErr)
+		}
+	}
+	return failures
+}
+
+// ListenerError represents an error returned by a single listener
+type ListenerError struct {
+	// Listener identifies the listener that failed, e.g. "event(type=app_mention)"
+	Listener string
+	// Index is the position of the listener in registration order, or -1 when no listener matched
+	Index int
+	// Source is the file:line where the listener was registered, if known
+	Source string
+	Err    error
+}
+
+// NewListenerError creates a new ListenerError
+func NewListenerError(listener string, index int, source string, err error) *ListenerError {
+	return &ListenerError{
+		Listener: listener,
+		Index:    index,
+		Source:   source,
+		Err:      err,
+	}
+}
+
+func (e *ListenerError) Error() string {
+	if e.Source != "" {
+		return fmt.Sprintf("listener %s registered at %s failed: %v", e.Listener, e.Source, e.Err)
+	}
+	return fmt.Sprintf("listener %s failed: %v", e.Listener, e.Err)
+}
+
+// Unwrap returns the error returned by the listener
+func (e *ListenerError) Unwrap() error {
+	return e.Err
+}
+
 // WorkflowStepInitializationError represents a workflow step initialization error
 // Deprecated: Workflow steps from apps are no longer supported
 type WorkflowStepInitializationError struct {
@@ -288,6 +468,78 @@ func NewCustomFunctionInitializationError(message string) *CustomFunctionInitial
 	}
 }
 
+// FunctionParameterError is returned when the inputs or outputs of a custom function do not match
+// the parameters it declares
+type FunctionParameterError struct {
+	*BaseError
+	// Problems describe each parameter that is missing or has the wrong type
+	Problems []string
+}
+
+// NewFunctionParameterError creates a new FunctionParameterError
+func NewFunctionParameterError(message string, problems []string) *FunctionParameterError {
+	return &FunctionParameterError{
+		BaseError: NewBaseError(FunctionParameterErrorCode, message),
+		Problems:  problems,
+	}
+}
+
+// TeamConcurrencyLimitError is returned when an event is rejected because its team already has the
+// maximum number of events in flight. It is retryable so Slack redelivers the event later.
+type TeamConcurrencyLimitError struct {
+	*BaseError
+	// TeamID is the team or enterprise whose limit was reached
+	TeamID string
+}
+
+// NewTeamConcurrencyLimitError creates a new TeamConcurrencyLimitError
+func NewTeamConcurrencyLimitError(teamID string, original error) *TeamConcurrencyLimitError {
+	return &TeamConcurrencyLimitError{
+		BaseError: NewBaseErrorWithOriginal(TeamConcurrencyLimitErrorCode, fmt.Sprintf("too many events in flight for team %s", teamID), original),
+		TeamID:    teamID,
+	}
+}
+
+// Retryable reports that the event may be redelivered once the team has capacity again
+func (e *TeamConcurrencyLimitError) Retryable() bool {
+	return true
+}
+
+// ConcurrencyLimitError is returned when an event is rejected because the app already processes
+// AppOptions.MaxConcurrency events and no more may wait. It is retryable so Slack redelivers the
+// event later.
+type ConcurrencyLimitError struct {
+	*BaseError
+}
+
+// NewConcurrencyLimitError creates a new ConcurrencyLimitError
+func NewConcurrencyLimitError(original error) *ConcurrencyLimitError {
+	return &ConcurrencyLimitError{
+		BaseError: NewBaseErrorWithOriginal(ConcurrencyLimitErrorCode, "too many events in flight", original),
+	}
+}
+
+// Retryable reports that the event may be redelivered once the app has capacity again
+func (e *ConcurrencyLimitError) Retryable() bool {
+	return true
+}
+
+// UserTokenError is returned when a listener asks to act as the user but the event was authorized
+// without a user token, or with one lacking scopes the listener needs
+type UserTokenError struct {
+	*BaseError
+	// MissingScopes are the scopes the listener needs that the user token was not granted
+	MissingScopes []string
+}
+
+// NewUserTokenError creates a new UserTokenError
+func NewUserTokenError(message string, missingScopes []string) *UserTokenError {
+	return &UserTokenError{
+		BaseError:     NewBaseError(UserTokenErrorCode, message),
+		MissingScopes: missingScopes,
+	}
+}
+
 // UnknownError represents an unknown error that wraps another error
 type UnknownError struct {
 	*BaseError
@@ -299,3 +551,39 @@ func NewUnknownError(original error) *UnknownError {
 		BaseError: NewBaseErrorWithOriginal(UnknownErrorCode, original.Error(), original),
 	}
 }
+
+// Structured log keys used when logging errors, kept stable so log pipelines can aggregate on them
+const (
+	LogKeyError     = "error"
+	LogKeyErrorCode = "error_code"
+	LogKeyEventType = "event_type"
+	LogKeyTeamID    = "team_id"
+	LogKeyListener  = "listener"
+	LogKeyRetryNum  = "retry_num"
+	// LogKeyCorrelationID identifies the event being processed across log lines and Slack API calls
+	LogKeyCorrelationID = "correlation_id"
+)
+
+// LogAttrs returns slog key-value pairs describing err: the error, its error_code and,
+// for listener failures, the listener that failed
+func LogAttrs(err error) []any {
+	attrs := []any{LogKeyError, err, LogKeyErrorCode, string(AsCodedError(err).Code())}
+
+	var listeners []string
+	var multipleErr *MultipleListenerError
+	if errors.As(err, &multipleErr) {
+		for _, failure := range multipleErr.Failures() {
+			listeners = append(listeners, failure.Listener)
+		}
+	} else {
+		var listenerErr *ListenerError
+		if errors.As(err, &listenerErr) {
+			listeners = append(listeners, listenerErr.Listener)
+		}
+	}
+	if len(listeners) > 0 {
+		attrs = append(attrs, LogKeyListener, strings.Join(listeners, ","))
+	}
+
+	return attrs
+}
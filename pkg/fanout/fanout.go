@@ -0,0 +1,50 @@
+// Package fanout holds the message encoding shared by the FanOutSink implementations for
+// AppOptions.FanOut in its kafka, nats and sqs subpackages, which publish incoming requests to
+// Kafka, NATS or Amazon SQS. Each message body is the FanOutMessage encoded as JSON; its type,
+// event type, team and correlation ID are also set as message headers or attributes so consumers
+// can route messages without decoding them.
+package fanout
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+)
+
+// Header names set on published messages
+const (
+	HeaderID           = "bolt-id"
+	HeaderEventID      = "bolt-event-id"
+	HeaderType         = "bolt-type"
+	HeaderEventType    = "bolt-event-type"
+	HeaderTeamID       = "bolt-team-id"
+	HeaderEnterpriseID = "bolt-enterprise-id"
+	HeaderRetryNum     = "bolt-retry-num"
+)
+
+// Encode returns the body of the message published for message
+func Encode(message app.FanOutMessage) ([]byte, error) {
+	return json.Marshal(message)
+}
+
+// Headers returns the non-empty headers describing message
+func Headers(message app.FanOutMessage) map[string]string {
+	values := map[string]string{
+		HeaderID:           message.ID,
+		HeaderEventID:      message.EventID,
+		HeaderType:         message.Type,
+		HeaderEventType:    message.EventType,
+		HeaderTeamID:       message.TeamID,
+		HeaderEnterpriseID: message.EnterpriseID,
+	}
+	if message.RetryNum > 0 {
+		values[HeaderRetryNum] = strconv.Itoa(message.RetryNum)
+	}
+	for key, value := range values {
+		if value == "" {
+			delete(values, key)
+		}
+	}
+	return values
+}
@@ -0,0 +1,54 @@
+// Package kafka provides a FanOutSink publishing incoming requests to Kafka
+package kafka
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	"github.com/Asafrose/bolt-go/pkg/fanout"
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// Writer writes messages to Kafka; *kafka.Writer implements it
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// SinkOptions configures a Sink
+type SinkOptions struct {
+	// Writer writes the messages
+	Writer Writer
+	// Topic is set on every message; leave it empty when the writer has its own topic
+	Topic string
+}
+
+// Sink publishes requests to Kafka, keyed by team ID so the requests of a workspace keep their
+// order within a partition
+type Sink struct {
+	writer Writer
+	topic  string
+}
+
+var _ app.FanOutSink = (*Sink)(nil)
+
+// NewSink creates a Sink from options
+func NewSink(options SinkOptions) (*Sink, error) {
+	if options.Writer == nil {
+		return nil, errors.New("kafka writer is required")
+	}
+	return &Sink{writer: options.Writer, topic: options.Topic}, nil
+}
+
+// Publish writes message to Kafka
+func (s *Sink) Publish(ctx context.Context, message app.FanOutMessage) error {
+	value, err := fanout.Encode(message)
+	if err != nil {
+		return err
+	}
+	msg := kafkago.Message{Topic: s.topic, Key: []byte(message.TeamID), Value: value}
+	for key, value := range fanout.Headers(message) {
+		msg.Headers = append(msg.Headers, kafkago.Header{Key: key, Value: []byte(value)})
+	}
+	return s.writer.WriteMessages(ctx, msg)
+}
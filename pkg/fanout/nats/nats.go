@@ -0,0 +1,86 @@
+// Package nats provides a FanOutSink publishing incoming requests to NATS
+package nats
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	"github.com/Asafrose/bolt-go/pkg/fanout"
+	natsgo "github.com/nats-io/nats.go"
+)
+
+// defaultSubject is the subject prefix when none is configured
+const defaultSubject = "slack"
+
+// Publisher publishes NATS messages; *nats.Conn implements it
+type Publisher interface {
+	PublishMsg(msg *natsgo.Msg) error
+}
+
+// SinkOptions configures a Sink
+type SinkOptions struct {
+	// Conn publishes the messages
+	Conn Publisher
+	// Subject prefixes the subject of every message (default "slack"). Messages are published
+	// on "<Subject>.<type>", or "<Subject>.event.<event type>" for Events API requests, so
+	// workers can subscribe to "slack.event.app_mention", "slack.command" or "slack.>".
+	Subject string
+}
+
+// Sink publishes requests to NATS
+type Sink struct {
+	conn    Publisher
+	subject string
+}
+
+var _ app.FanOutSink = (*Sink)(nil)
+
+// NewSink creates a Sink from options
+func NewSink(options SinkOptions) (*Sink, error) {
+	if options.Conn == nil {
+		return nil, errors.New("nats connection is required")
+	}
+	subject := options.Subject
+	if subject == "" {
+		subject = defaultSubject
+	}
+	return &Sink{conn: options.Conn, subject: subject}, nil
+}
+
+// Publish publishes message on its subject
+func (s *Sink) Publish(ctx context.Context, message app.FanOutMessage) error {
+	data, err := fanout.Encode(message)
+	if err != nil {
+		return err
+	}
+	msg := natsgo.NewMsg(s.Subject(message))
+	msg.Data = data
+	for key, value := range fanout.Headers(message) {
+		msg.Header.Set(key, value)
+	}
+	return s.conn.PublishMsg(msg)
+}
+
+// Subject returns the subject message is published on
+func (s *Sink) Subject(message app.FanOutMessage) string {
+	subject := s.subject + "." + subjectToken(message.Type)
+	if message.EventType != "" {
+		subject += "." + subjectToken(message.EventType)
+	}
+	return subject
+}
+
+// subjectToken makes value safe to use as a subject token
+func subjectToken(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	token := []byte(value)
+	for i, c := range token {
+		if c == '.' || c == '*' || c == '>' || c <= ' ' {
+			token[i] = '_'
+		}
+	}
+	return string(token)
+}
@@ -0,0 +1,88 @@
+// Package sqs provides a FanOutSink publishing incoming requests to an Amazon SQS queue
+package sqs
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	"github.com/Asafrose/bolt-go/pkg/fanout"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// defaultMessageGroup groups the messages of requests without a team on FIFO queues
+const defaultMessageGroup = "slack"
+
+// Client sends SQS messages; *sqs.Client implements it
+type Client interface {
+	SendMessage(ctx context.Context, params *awssqs.SendMessageInput, optFns ...func(*awssqs.Options)) (*awssqs.SendMessageOutput, error)
+}
+
+// SinkOptions configures a Sink
+type SinkOptions struct {
+	// Client sends the messages
+	Client Client
+	// QueueURL is the URL of the queue. On FIFO queues, whose name ends with ".fifo", messages
+	// are grouped by team and deduplicated by event_id, so retries of an event are dropped.
+	QueueURL string
+}
+
+// Sink publishes requests to an Amazon SQS queue
+type Sink struct {
+	client   Client
+	queueURL string
+	fifo     bool
+}
+
+var _ app.FanOutSink = (*Sink)(nil)
+
+// NewSink creates a Sink from options
+func NewSink(options SinkOptions) (*Sink, error) {
+	if options.Client == nil {
+		return nil, errors.New("sqs client is required")
+	}
+	if options.QueueURL == "" {
+		return nil, errors.New("sqs queue URL is required")
+	}
+	return &Sink{
+		client:   options.Client,
+		queueURL: options.QueueURL,
+		fifo:     strings.HasSuffix(options.QueueURL, ".fifo"),
+	}, nil
+}
+
+// Publish sends message to the queue
+func (s *Sink) Publish(ctx context.Context, message app.FanOutMessage) error {
+	body, err := fanout.Encode(message)
+	if err != nil {
+		return err
+	}
+	input := &awssqs.SendMessageInput{
+		QueueUrl:          aws.String(s.queueURL),
+		MessageBody:       aws.String(string(body)),
+		MessageAttributes: make(map[string]types.MessageAttributeValue),
+	}
+	for key, value := range fanout.Headers(message) {
+		input.MessageAttributes[key] = types.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	if s.fifo {
+		group := message.TeamID
+		if group == "" {
+			group = defaultMessageGroup
+		}
+		dedup := message.EventID
+		if dedup == "" {
+			dedup = message.ID
+		}
+		input.MessageGroupId = aws.String(group)
+		input.MessageDeduplicationId = aws.String(dedup)
+	}
+	_, err = s.client.SendMessage(ctx, input)
+	return err
+}
@@ -1,6 +1,7 @@
 package functions
 
 import (
+	stdcontext "context"
 	"errors"
 	"fmt"
 
@@ -10,9 +11,7 @@ import (
 )
 
 // CustomFunctionOptions represents options for custom functions
-type CustomFunctionOptions struct {
-	AutoAcknowledge bool `json:"auto_acknowledge"`
-}
+type CustomFunctionOptions = types.CustomFunctionOptions
 
 // CustomFunction represents a custom function with middleware chain
 type CustomFunction struct {
@@ -129,24 +128,24 @@ func CreateFunctionComplete(context map[string]interface{}, client *slack.Client
 	}
 
 	return func(outputs map[string]interface{}) error {
-		// If outputs is nil, use empty map
-		if outputs == nil {
-			outputs = make(map[string]interface{})
-		}
+		return CompleteSuccess(stdcontext.Background(), client, functionExecutionIDStr, outputs)
+	}
+}
 
-		// Convert map[string]interface{} to map[string]string for the API
-		stringOutputs := make(map[string]string)
-		for key, value := range outputs {
-			if strValue, ok := value.(string); ok {
-				stringOutputs[key] = strValue
-			} else {
-				stringOutputs[key] = fmt.Sprintf("%v", value)
-			}
+// CompleteSuccess completes the function execution functionExecutionID with outputs by calling
+// functions.completeSuccess
+func CompleteSuccess(ctx stdcontext.Context, client *slack.Client, functionExecutionID string, outputs map[string]interface{}) error {
+	// Convert map[string]interface{} to map[string]string for the API
+	stringOutputs := make(map[string]string, len(outputs))
+	for key, value := range outputs {
+		if strValue, ok := value.(string); ok {
+			stringOutputs[key] = strValue
+		} else {
+			stringOutputs[key] = fmt.Sprintf("%v", value)
 		}
-
-		// Call Slack API to complete the function
-		return client.FunctionCompleteSuccess(functionExecutionIDStr, slack.FunctionCompleteSuccessRequestOptionOutput(stringOutputs))
 	}
+
+	return client.FunctionCompleteSuccessContext(ctx, functionExecutionID, slack.FunctionCompleteSuccessRequestOptionOutput(stringOutputs))
 }
 
 // CreateFunctionFail creates a function failure handler
@@ -163,7 +162,12 @@ func CreateFunctionFail(context map[string]interface{}, client *slack.Client) ty
 	}
 
 	return func(errorMsg string) error {
-		// Call Slack API to fail the function
-		return client.FunctionCompleteError(functionExecutionIDStr, errorMsg)
+		return CompleteError(stdcontext.Background(), client, functionExecutionIDStr, errorMsg)
 	}
 }
+
+// CompleteError fails the function execution functionExecutionID with message by calling
+// functions.completeError
+func CompleteError(ctx stdcontext.Context, client *slack.Client, functionExecutionID, message string) error {
+	return client.FunctionCompleteErrorContext(ctx, functionExecutionID, message)
+}
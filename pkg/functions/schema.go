@@ -0,0 +1,241 @@
+package functions
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Parameter types of custom function inputs and outputs
+const (
+	TypeString           = "string"
+	TypeInteger          = "integer"
+	TypeNumber           = "number"
+	TypeBoolean          = "boolean"
+	TypeArray            = "array"
+	TypeObject           = "object"
+	TypeUserID           = "slack#/types/user_id"
+	TypeChannelID        = "slack#/types/channel_id"
+	TypeUsergroupID      = "slack#/types/usergroup_id"
+	TypeTimestamp        = "slack#/types/timestamp"
+	TypeDate             = "slack#/types/date"
+	TypeMessageTS        = "slack#/types/message_ts"
+	TypeRichText         = "slack#/types/rich_text"
+	TypeInteractivity    = "slack#/types/interactivity"
+	TypeMessageContext   = "slack#/types/message_context"
+	TypeMessageLink      = "slack#/types/message_link"
+	TypeExpandedRichText = "slack#/types/expanded_rich_text"
+)
+
+// Parameter declares an input or output parameter of a custom function
+type Parameter struct {
+	// Name is the key of the parameter in inputs and outputs
+	Name        string
+	Type        string
+	Title       string
+	Description string
+	// Hint is shown in Workflow Builder while the parameter is being filled in
+	Hint     string
+	Required bool
+	// Items is the type of the elements of TypeArray parameters
+	Items *Parameter
+}
+
+// Schema declares the parameters of a custom function
+type Schema struct {
+	Title       string
+	Description string
+	Inputs      []Parameter
+	Outputs     []Parameter
+}
+
+// ManifestFunction is a custom function as declared in the functions section of the app manifest
+type ManifestFunction struct {
+	Title            string             `json:"title"`
+	Description      string             `json:"description,omitempty"`
+	InputParameters  ManifestParameters `json:"input_parameters"`
+	OutputParameters ManifestParameters `json:"output_parameters"`
+}
+
+// ManifestParameters are the input or output parameters of a ManifestFunction
+type ManifestParameters struct {
+	Properties map[string]ManifestProperty `json:"properties"`
+	Required   []string                    `json:"required,omitempty"`
+}
+
+// ManifestProperty is a parameter of a ManifestFunction
+type ManifestProperty struct {
+	Type        string            `json:"type"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Hint        string            `json:"hint,omitempty"`
+	Items       *ManifestProperty `json:"items,omitempty"`
+}
+
+// Manifest returns the function as declared in the functions section of the app manifest, keyed
+// by its callback ID
+func (s Schema) Manifest() ManifestFunction {
+	return ManifestFunction{
+		Title:            s.Title,
+		Description:      s.Description,
+		InputParameters:  manifestParameters(s.Inputs),
+		OutputParameters: manifestParameters(s.Outputs),
+	}
+}
+
+func manifestParameters(parameters []Parameter) ManifestParameters {
+	result := ManifestParameters{Properties: make(map[string]ManifestProperty, len(parameters))}
+	for _, parameter := range parameters {
+		result.Properties[parameter.Name] = manifestProperty(parameter)
+		if parameter.Required {
+			result.Required = append(result.Required, parameter.Name)
+		}
+	}
+	return result
+}
+
+func manifestProperty(parameter Parameter) ManifestProperty {
+	property := ManifestProperty{
+		Type:        parameter.Type,
+		Title:       parameter.Title,
+		Description: parameter.Description,
+		Hint:        parameter.Hint,
+	}
+	if parameter.Items != nil {
+		items := manifestProperty(*parameter.Items)
+		property.Items = &items
+	}
+	return property
+}
+
+// ValidateInputs returns a *errors.FunctionParameterError when inputs miss required parameters
+// or hold values of the wrong type
+func (s Schema) ValidateInputs(inputs map[string]interface{}) error {
+	return validateParameters("inputs", s.Inputs, inputs)
+}
+
+// ValidateOutputs returns a *errors.FunctionParameterError when outputs miss required parameters
+// or hold values of the wrong type
+func (s Schema) ValidateOutputs(outputs map[string]interface{}) error {
+	return validateParameters("outputs", s.Outputs, outputs)
+}
+
+// validateParameters checks values against parameters, the inputs or outputs named by kind
+func validateParameters(kind string, parameters []Parameter, values map[string]interface{}) error {
+	var problems []string
+	for _, parameter := range parameters {
+		value, exists := values[parameter.Name]
+		if !exists || value == nil {
+			if parameter.Required {
+				problems = append(problems, fmt.Sprintf("%s is required", parameter.Name))
+			}
+			continue
+		}
+		if problem := checkType(parameter, value); problem != "" {
+			problems = append(problems, fmt.Sprintf("%s %s", parameter.Name, problem))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.NewFunctionParameterError("invalid function "+kind+": "+strings.Join(problems, "; "), problems)
+	}
+	return nil
+}
+
+// checkType describes how value, decoded from JSON, does not match the type of parameter, or
+// returns ""
+func checkType(parameter Parameter, value interface{}) string {
+	switch parameter.Type {
+	case TypeInteger, TypeTimestamp:
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			return "must be an integer"
+		}
+	case TypeNumber:
+		if _, ok := value.(float64); !ok {
+			return "must be a number"
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	case TypeArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			return "must be an array"
+		}
+		if parameter.Items != nil {
+			for i, item := range items {
+				if problem := checkType(*parameter.Items, item); problem != "" {
+					return fmt.Sprintf("item %d %s", i, problem)
+				}
+			}
+		}
+	case TypeObject, TypeRichText, TypeExpandedRichText, TypeInteractivity, TypeMessageContext:
+		if _, ok := value.(map[string]interface{}); !ok {
+			return "must be an object"
+		}
+	default:
+		// Strings and the Slack types identifying users, channels, dates and messages
+		if _, ok := value.(string); !ok {
+			return "must be a string"
+		}
+	}
+	return ""
+}
+
+// DecodeInputs validates inputs against schema and decodes them into a T, whose fields are mapped
+// to parameter names with json tags
+func DecodeInputs[T any](schema Schema, inputs map[string]interface{}) (T, error) {
+	var result T
+	if err := schema.ValidateInputs(inputs); err != nil {
+		return result, err
+	}
+
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		message := "invalid function inputs: " + err.Error()
+		return result, errors.NewFunctionParameterError(message, []string{err.Error()})
+	}
+	return result, nil
+}
+
+// BindInputs decodes the inputs of the function execution args were built for into a T, as
+// DecodeInputs does. When they are invalid, it fails the execution with args.Fail, reporting the
+// problems to the workflow, and returns the error.
+func BindInputs[T any](args types.SlackCustomFunctionMiddlewareArgs, schema Schema) (T, error) {
+	var inputs map[string]interface{}
+	if args.Context != nil {
+		inputs = args.Context.FunctionInputs
+	}
+
+	result, err := DecodeInputs[T](schema, inputs)
+	if err != nil && args.Fail != nil {
+		if failErr := args.Fail(err.Error()); failErr != nil {
+			return result, fmt.Errorf("%w (failing the function: %v)", err, failErr)
+		}
+	}
+	return result, err
+}
+
+// EncodeOutputs encodes v, whose fields are mapped to parameter names with json tags, into
+// function outputs for args.Complete and validates them against schema
+func EncodeOutputs(schema Schema, v any) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var outputs map[string]interface{}
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return nil, err
+	}
+	if err := schema.ValidateOutputs(outputs); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+)
+
+// defaultFileInfoConcurrency is the number of files.info lookups FetchFileInfos runs at once by default
+const defaultFileInfoConcurrency = 4
+
+// FileIDsFromEvent returns the IDs of the files referenced by a file event such as file_shared or
+// file_created, read from its file_id and file.id fields
+func FileIDsFromEvent(event types.SlackEvent) []string {
+	var raw map[string]interface{}
+	if generic, ok := event.(*GenericSlackEvent); ok {
+		raw = generic.RawData
+	} else if extracted, err := ExtractRawDataFromSlackEvent(event); err == nil {
+		raw = extracted
+	}
+
+	var fileIDs []string
+	if fileID, ok := raw["file_id"].(string); ok && fileID != "" {
+		fileIDs = append(fileIDs, fileID)
+	}
+	if file, ok := raw["file"].(map[string]interface{}); ok {
+		if fileID, ok := file["id"].(string); ok && fileID != "" && (len(fileIDs) == 0 || fileIDs[0] != fileID) {
+			fileIDs = append(fileIDs, fileID)
+		}
+	}
+	return fileIDs
+}
+
+// FetchFileInfos looks up fileIDs with files.info, skipping duplicate IDs and running up to
+// concurrency lookups at once (default 4). Files that were found are returned even if other
+// lookups failed; the error joins the failures.
+func FetchFileInfos(ctx context.Context, client *slack.Client, fileIDs []string, concurrency int) (map[string]*slack.File, error) {
+	if concurrency <= 0 {
+		concurrency = defaultFileInfoConcurrency
+	}
+
+	files := make(map[string]*slack.File, len(fileIDs))
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+	seen := make(map[string]bool, len(fileIDs))
+
+	for _, fileID := range fileIDs {
+		if fileID == "" || seen[fileID] {
+			continue
+		}
+		seen[fileID] = true
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			file, _, _, err := client.GetFileInfoContext(ctx, fileID, 0, 0)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("files.info %s: %w", fileID, err))
+				return
+			}
+			files[fileID] = file
+		}()
+	}
+	wg.Wait()
+
+	return files, errors.Join(errs...)
+}
+
+// OpenFile streams the private download of file through client without loading it into memory.
+// The caller must close the returned reader; closing it early aborts the download.
+func OpenFile(ctx context.Context, client *slack.Client, file *slack.File) (io.ReadCloser, error) {
+	if file == nil {
+		return nil, errors.New("file is nil")
+	}
+
+	downloadURL := file.URLPrivateDownload
+	if downloadURL == "" {
+		downloadURL = file.URLPrivate
+	}
+	if downloadURL == "" {
+		return nil, fmt.Errorf("file %s has no private download URL", file.ID)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(client.GetFileContext(ctx, downloadURL, writer))
+	}()
+	return reader, nil
+}
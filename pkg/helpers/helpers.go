@@ -1,12 +1,14 @@
 package helpers
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -28,6 +30,26 @@ const (
 	IncomingEventTypeShortcut
 )
 
+// String returns the name of the incoming event type
+func (t IncomingEventType) String() string {
+	switch t {
+	case IncomingEventTypeEvent:
+		return "event"
+	case IncomingEventTypeAction:
+		return "action"
+	case IncomingEventTypeCommand:
+		return "command"
+	case IncomingEventTypeOptions:
+		return "options"
+	case IncomingEventTypeViewAction:
+		return "view"
+	case IncomingEventTypeShortcut:
+		return "shortcut"
+	default:
+		return "unknown"
+	}
+}
+
 // EventTypeAndConversation holds event type and conversation info
 type EventTypeAndConversation struct {
 	Type           *IncomingEventType `json:"type,omitempty"`
@@ -61,8 +83,11 @@ func ParseRequestBody(body []byte) map[string]interface{} {
 
 // GetTypeAndConversation determines the type and conversation ID from a request body
 func GetTypeAndConversation(body []byte) EventTypeAndConversation {
-	parsed := ParseRequestBody(body)
+	return GetTypeAndConversationFromParsed(ParseRequestBody(body))
+}
 
+// GetTypeAndConversationFromParsed determines the type and conversation ID from a body already parsed with ParseRequestBody
+func GetTypeAndConversationFromParsed(parsed map[string]interface{}) EventTypeAndConversation {
 	// Check for event
 	if event, exists := parsed["event"]; exists {
 		eventType := IncomingEventTypeEvent
@@ -240,7 +265,11 @@ func IsBodyWithTypeEnterpriseInstall(body []byte) bool {
 	if err := json.Unmarshal(body, &parsed); err != nil {
 		return false
 	}
+	return IsParsedBodyWithTypeEnterpriseInstall(parsed)
+}
 
+// IsParsedBodyWithTypeEnterpriseInstall checks if an already parsed JSON body indicates enterprise install
+func IsParsedBodyWithTypeEnterpriseInstall(parsed map[string]interface{}) bool {
 	if isEnterpriseInstall, exists := parsed["is_enterprise_install"]; exists {
 		// Handle boolean values
 		if isEnterprise, ok := isEnterpriseInstall.(bool); ok {
@@ -272,7 +301,11 @@ func ExtractEventType(body []byte) string {
 	if err := json.Unmarshal(body, &parsed); err != nil {
 		return ""
 	}
+	return ExtractEventTypeFromParsed(parsed)
+}
 
+// ExtractEventTypeFromParsed extracts the event type from an already parsed JSON body
+func ExtractEventTypeFromParsed(parsed map[string]interface{}) string {
 	if event, exists := parsed["event"]; exists {
 		if eventMap, ok := event.(map[string]interface{}); ok {
 			if eventType, exists := eventMap["type"]; exists {
@@ -295,12 +328,35 @@ func CreateSayFunction(client interface{}, channelID string) types.SayFn {
 	}
 }
 
-// CreateRespondFunction creates a respond function for a response URL
+// CreateRespondFunction creates a respond function posting to responseURL with
+// http.DefaultClient, once it is checked against DefaultResponseURLHosts
 func CreateRespondFunction(responseURL string) types.RespondFn {
-	return func(message types.RespondMessage) error {
-		// Implementation will depend on HTTP client for response URL
-		// For now, return nil
-		return nil
+	validator := NewResponseURLValidator(nil)
+	return func(ctx context.Context, message types.RespondMessage) (*types.RespondResponse, error) {
+		// Only post to allowed hosts so a forged response_url cannot redirect replies
+		if err := validator.Validate(responseURL); err != nil {
+			return nil, err
+		}
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return PostResponseURL(ctx, http.DefaultClient, responseURL, message)
+	}
+}
+
+// NormalizePattern prepares a string or RegExp pattern for repeated matching: a RegExp passed by
+// value is converted to a pointer so its compiled state is shared, and a *string is dereferenced.
+func NormalizePattern(pattern interface{}) interface{} {
+	switch p := pattern.(type) {
+	case regexp.Regexp:
+		return &p
+	case *string:
+		if p == nil {
+			return ""
+		}
+		return *p
+	default:
+		return pattern
 	}
 }
 
@@ -333,7 +389,11 @@ func ExtractTeamID(body []byte) *string {
 	if err := json.Unmarshal(body, &parsed); err != nil {
 		return nil
 	}
+	return ExtractTeamIDFromParsed(parsed)
+}
 
+// ExtractTeamIDFromParsed extracts team ID from an already parsed JSON body
+func ExtractTeamIDFromParsed(parsed map[string]interface{}) *string {
 	if teamID, exists := parsed["team_id"]; exists {
 		if teamIDStr, ok := teamID.(string); ok {
 			return &teamIDStr
@@ -359,7 +419,11 @@ func ExtractEnterpriseID(body []byte) *string {
 	if err := json.Unmarshal(body, &parsed); err != nil {
 		return nil
 	}
+	return ExtractEnterpriseIDFromParsed(parsed)
+}
 
+// ExtractEnterpriseIDFromParsed extracts enterprise ID from an already parsed JSON body
+func ExtractEnterpriseIDFromParsed(parsed map[string]interface{}) *string {
 	if enterpriseID, exists := parsed["enterprise_id"]; exists {
 		if enterpriseIDStr, ok := enterpriseID.(string); ok && enterpriseIDStr != "" {
 			return &enterpriseIDStr
@@ -385,7 +449,11 @@ func ExtractUserID(body []byte) *string {
 	if err := json.Unmarshal(body, &parsed); err != nil {
 		return nil
 	}
+	return ExtractUserIDFromParsed(parsed)
+}
 
+// ExtractUserIDFromParsed extracts user ID from an already parsed JSON body
+func ExtractUserIDFromParsed(parsed map[string]interface{}) *string {
 	// Check direct user_id field
 	if userID, exists := parsed["user_id"]; exists {
 		if userIDStr, ok := userID.(string); ok {
@@ -456,16 +524,7 @@ func VerifySlackSignature(signingSecret, signature, timestamp string, body []byt
 		return errors.New("timestamp is in the future")
 	}
 
-	// Create the signature base string
-	baseString := fmt.Sprintf("v0:%s:%s", timestamp, string(body))
-
-	// Create HMAC-SHA256 hash
-	mac := hmac.New(sha256.New, []byte(signingSecret))
-	mac.Write([]byte(baseString))
-	expectedSignature := "v0=" + hex.EncodeToString(mac.Sum(nil))
-
-	// Compare signatures
-	if !hmac.Equal([]byte(signature), []byte(expectedSignature)) {
+	if !NewSignatureVerifier(signingSecret).Verify(signature, timestamp, body) {
 		return errors.New("signature mismatch")
 	}
 
@@ -311,6 +311,29 @@ func ParseSlackView(data map[string]interface{}) (types.SlackView, error) {
 	return nil, errors.New("unknown view type")
 }
 
+// ParseActionView returns the view an action was taken in, when its container is a modal or App
+// Home view, or nil otherwise
+func ParseActionView(parsed map[string]interface{}) (*slack.View, error) {
+	container, _ := parsed["container"].(map[string]interface{})
+	if containerType, _ := container["type"].(string); containerType != "view" {
+		return nil, nil
+	}
+	viewData, exists := parsed["view"]
+	if !exists {
+		return nil, nil
+	}
+
+	jsonBytes, err := json.Marshal(viewData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal view: %w", err)
+	}
+	var view slack.View
+	if err := json.Unmarshal(jsonBytes, &view); err != nil {
+		return nil, fmt.Errorf("failed to parse view: %w", err)
+	}
+	return &view, nil
+}
+
 // ParseViewOutput converts raw JSON view data to ViewOutput for processed view data
 func ParseViewOutput(data interface{}) (types.ViewOutput, error) {
 	output := types.ViewOutput{
@@ -441,3 +464,50 @@ func ExtractRawDataFromSlackEvent(event types.SlackEvent) (map[string]interface{
 
 	return rawData, nil
 }
+
+// DecodeEvent decodes event into T, such as one of the typed event structs of the types package
+func DecodeEvent[T any](event types.SlackEvent) (T, error) {
+	var decoded T
+	if event == nil {
+		return decoded, errors.New("event is nil")
+	}
+
+	var data interface{} = event
+	if genericEvent, ok := event.(*GenericSlackEvent); ok {
+		data = genericEvent.RawData
+	}
+
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return decoded, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := json.Unmarshal(jsonBytes, &decoded); err != nil {
+		return decoded, fmt.Errorf("failed to parse %s event: %w", event.GetType(), err)
+	}
+	return decoded, nil
+}
+
+// ParseMessageMetadataEvent decodes a message_metadata_* event into a MessageMetadataEvent
+// whose metadata payload is unmarshaled into T
+func ParseMessageMetadataEvent[T any](event types.SlackEvent) (*types.MessageMetadataEvent[T], error) {
+	if event == nil {
+		return nil, errors.New("event is nil")
+	}
+
+	var data interface{} = event
+	if genericEvent, ok := event.(*GenericSlackEvent); ok {
+		data = genericEvent.RawData
+	}
+
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	metadataEvent := &types.MessageMetadataEvent[T]{}
+	if err := json.Unmarshal(jsonBytes, metadataEvent); err != nil {
+		return nil, fmt.Errorf("failed to parse message metadata event: %w", err)
+	}
+
+	return metadataEvent, nil
+}
@@ -0,0 +1,148 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// DefaultResponseURLHosts are the hosts CreateRespondFunction posts to, and the default of
+// AppOptions.ResponseURLHosts
+var DefaultResponseURLHosts = []string{"hooks.slack.com"}
+
+// ResponseURLValidator checks response_url and webhook URLs against the allowed hosts
+type ResponseURLValidator struct {
+	// hosts are lower-cased host names, or host:port pairs when a port was given
+	hosts map[string]bool
+	// wildcards are lower-cased ".example.com" suffixes from "*.example.com" entries
+	wildcards []string
+}
+
+// NewResponseURLValidator creates a validator allowing hosts, or DefaultResponseURLHosts when
+// hosts is empty. Entries are host names, host:port pairs or "*.example.com" subdomain wildcards.
+func NewResponseURLValidator(hosts []string) *ResponseURLValidator {
+	if len(hosts) == 0 {
+		hosts = DefaultResponseURLHosts
+	}
+
+	validator := &ResponseURLValidator{hosts: make(map[string]bool, len(hosts))}
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		switch {
+		case host == "":
+		case strings.HasPrefix(host, "*."):
+			validator.wildcards = append(validator.wildcards, host[1:])
+		default:
+			validator.hosts[host] = true
+		}
+	}
+	return validator
+}
+
+// Validate returns an error unless rawURL is an https URL on an allowed host. Plain http is only
+// accepted for loopback hosts, so local test servers keep working.
+func (v *ResponseURLValidator) Validate(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return bolterrors.NewAppInitializationError(fmt.Sprintf("invalid response URL: %v", err))
+	}
+
+	hostname := strings.ToLower(parsed.Hostname())
+	if hostname == "" || parsed.User != nil {
+		return bolterrors.NewAppInitializationError("invalid response URL")
+	}
+
+	switch parsed.Scheme {
+	case "http":
+		if isLoopbackHost(hostname) {
+			return nil
+		}
+	case "https":
+		if isLoopbackHost(hostname) || v.allows(hostname, strings.ToLower(parsed.Host)) {
+			return nil
+		}
+	}
+	return bolterrors.NewAppInitializationError(fmt.Sprintf("invalid response URL: host %q is not allowed", parsed.Host))
+}
+
+// allows reports whether hostname, or host including its port, is in the allowed list
+func (v *ResponseURLValidator) allows(hostname, host string) bool {
+	if v.hosts[hostname] || v.hosts[host] {
+		return true
+	}
+	for _, suffix := range v.wildcards {
+		if strings.HasSuffix(hostname, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLoopbackHost reports whether hostname is localhost or a loopback IP address
+func isLoopbackHost(hostname string) bool {
+	if hostname == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
+}
+
+// PostResponseURL posts message to responseURL with client. A RespondString is sent as the text of
+// a message; other messages are encoded as JSON. responseURL is not validated.
+func PostResponseURL(ctx context.Context, client *http.Client, responseURL string, message types.RespondMessage) (*types.RespondResponse, error) {
+	var payload []byte
+	var err error
+
+	switch msg := message.(type) {
+	case types.RespondString:
+		payload, err = json.Marshal(map[string]interface{}{
+			"text": string(msg),
+		})
+	case types.RespondArguments:
+		payload, err = json.Marshal(msg)
+	default:
+		payload, err = json.Marshal(message)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// response_url replies are tiny ("ok" or a short error), so cap what we buffer
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.RespondResponse{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return result, bolterrors.NewAppInitializationError(fmt.Sprintf("failed to send response: status %d", resp.StatusCode))
+	}
+
+	return result, nil
+}
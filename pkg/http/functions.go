@@ -2,13 +2,14 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 
-	"github.com/Asafrose/bolt-go/pkg/errors"
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
 )
 
 // ExtractRetryNumFromHTTPRequest extracts the retry number from the X-Slack-Retry-Num header
@@ -134,15 +135,13 @@ func BuildUrlVerificationResponse(w http.ResponseWriter, body interface{}) {
 
 // DefaultDispatchErrorHandler handles dispatch errors
 func DefaultDispatchErrorHandler(args DispatchErrorHandlerArgs) {
-	if codedErr, ok := args.Error.(*errors.ReceiverMultipleAckError); ok {
-		_ = codedErr // Use the error
+	if errors.Is(args.Error, bolterrors.ReceiverMultipleAckErrorCode) {
 		args.Logger.Error("Multiple ack error occurred")
 		BuildNoBodyResponse(args.Response, http.StatusInternalServerError)
 		return
 	}
 
-	if codedErr, ok := args.Error.(*errors.HTTPReceiverDeferredRequestError); ok {
-		_ = codedErr // Use the error
+	if errors.Is(args.Error, bolterrors.HTTPReceiverDeferredRequestErrorCode) {
 		args.Logger.Info(fmt.Sprintf("Unhandled HTTP request (%s) made to %s", args.Request.Method, args.Request.URL.Path))
 		BuildNoBodyResponse(args.Response, http.StatusNotFound)
 		return
@@ -155,16 +154,14 @@ func DefaultDispatchErrorHandler(args DispatchErrorHandlerArgs) {
 
 // DefaultProcessEventErrorHandler handles process event errors
 func DefaultProcessEventErrorHandler(args ProcessEventErrorHandlerArgs) bool {
-	if codedErr, ok := args.Error.(*errors.ReceiverMultipleAckError); ok {
-		_ = codedErr // Use the error
+	if errors.Is(args.Error, bolterrors.ReceiverMultipleAckErrorCode) {
 		args.Logger.Error("Multiple ack error occurred after ack() called in a listener")
 		args.Logger.Debug(fmt.Sprintf("Error details: %v, storedResponse: %v", args.Error, args.StoredResponse))
 		BuildNoBodyResponse(args.Response, http.StatusInternalServerError)
 		return false
 	}
 
-	if codedErr, ok := args.Error.(*errors.AuthorizationError); ok {
-		_ = codedErr // Use the error
+	if errors.Is(args.Error, bolterrors.AuthorizationErrorCode) {
 		BuildNoBodyResponse(args.Response, http.StatusUnauthorized)
 		return true
 	}
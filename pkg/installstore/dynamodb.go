@@ -0,0 +1,291 @@
+package installstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// defaultDynamoDBPartitionKey and defaultDynamoDBSortKey name the key attributes of the table
+	// when no names are configured
+	defaultDynamoDBPartitionKey = "pk"
+	defaultDynamoDBSortKey      = "sk"
+	// defaultDynamoDBTTLAttribute names the attribute holding the expiry time of items when no
+	// name is configured
+	defaultDynamoDBTTLAttribute = "ttl"
+
+	// dynamoDBPartitionPrefix prefixes the partition keys of installations, so the table can hold
+	// other items as well
+	dynamoDBPartitionPrefix = "INSTALLATION#"
+	// dynamoDBLatestSortKey is the sort key of the latest installation of a workspace or
+	// organization; dynamoDBUserSortPrefix prefixes the sort keys of the latest of each user
+	dynamoDBLatestSortKey  = "LATEST"
+	dynamoDBUserSortPrefix = "USER#"
+
+	// Attributes of installation items besides their keys
+	dynamoDBInstallationAttribute = "installation"
+	dynamoDBInstalledAtAttribute  = "installed_at"
+)
+
+// DynamoDBClient runs DynamoDB operations; *dynamodb.Client implements it
+type DynamoDBClient interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+var _ DynamoDBClient = (*dynamodb.Client)(nil)
+
+// DynamoDBInstallationStoreOptions configures a DynamoDBInstallationStore
+type DynamoDBInstallationStoreOptions struct {
+	// Client runs the operations
+	Client DynamoDBClient
+	// TableName is the table storing installations. Its partition and sort keys must be strings.
+	TableName string
+	// PartitionKey and SortKey name the key attributes of the table (default "pk" and "sk")
+	PartitionKey string
+	SortKey      string
+	// TTL expires installations not stored again for this long; zero keeps them until deleted.
+	// Enable Time to Live on TTLAttribute for DynamoDB to remove expired items.
+	TTL time.Duration
+	// TTLAttribute names the attribute holding the expiry time, in Unix seconds (default "ttl")
+	TTLAttribute string
+}
+
+// DynamoDBInstallationStore stores installations in a single DynamoDB table, e.g. for apps running
+// on AWS Lambda, with the same semantics as RedisInstallationStore. Each workspace, or organization
+// for org-wide installs, is a partition keyed by its enterprise and team IDs, holding its latest
+// installation and the latest installation of each user who installed the app there.
+//
+// Writes are conditional: an installation never replaces one stored at a later time, so a slow
+// OAuth callback cannot overwrite a newer installation of the same workspace.
+type DynamoDBInstallationStore struct {
+	client       DynamoDBClient
+	table        string
+	partitionKey string
+	sortKey      string
+	ttl          time.Duration
+	ttlAttribute string
+}
+
+var _ oauth.InstallationStore = (*DynamoDBInstallationStore)(nil)
+
+// NewDynamoDBInstallationStore creates a DynamoDBInstallationStore from options
+func NewDynamoDBInstallationStore(options DynamoDBInstallationStoreOptions) (*DynamoDBInstallationStore, error) {
+	if options.Client == nil {
+		return nil, errors.New("dynamodb client is required")
+	}
+	if options.TableName == "" {
+		return nil, errors.New("dynamodb table name is required")
+	}
+	store := &DynamoDBInstallationStore{
+		client:       options.Client,
+		table:        options.TableName,
+		partitionKey: options.PartitionKey,
+		sortKey:      options.SortKey,
+		ttl:          options.TTL,
+		ttlAttribute: options.TTLAttribute,
+	}
+	if store.partitionKey == "" {
+		store.partitionKey = defaultDynamoDBPartitionKey
+	}
+	if store.sortKey == "" {
+		store.sortKey = defaultDynamoDBSortKey
+	}
+	if store.ttlAttribute == "" {
+		store.ttlAttribute = defaultDynamoDBTTLAttribute
+	}
+	return store, nil
+}
+
+// StoreInstallation stores installation as the latest of its workspace or organization, and as
+// the latest of the user who installed it, if any, unless a later installation was stored
+func (s *DynamoDBInstallationStore) StoreInstallation(ctx context.Context, installation *oauth.Installation) error {
+	if installation == nil {
+		return errors.New("installation cannot be nil")
+	}
+	var enterpriseID, teamID string
+	if installation.Enterprise != nil {
+		enterpriseID = installation.Enterprise.ID
+	}
+	if installation.Team != nil && !installation.IsEnterpriseInstall {
+		teamID = installation.Team.ID
+	}
+	if enterpriseID == "" && teamID == "" {
+		return errors.New("installation has neither a team nor an enterprise")
+	}
+
+	data, err := json.Marshal(installation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal installation: %w", err)
+	}
+	now := time.Now()
+	partition := dynamoDBPartition(enterpriseID, teamID)
+	if err := s.put(ctx, partition, dynamoDBLatestSortKey, data, now); err != nil {
+		return err
+	}
+	if userID := installerID(installation); userID != "" {
+		return s.put(ctx, partition, dynamoDBUserSortPrefix+userID, data, now)
+	}
+	return nil
+}
+
+// put writes an installation item unless the stored one was installed after installedAt
+func (s *DynamoDBInstallationStore) put(ctx context.Context, partition, sort string, data []byte, installedAt time.Time) error {
+	item := map[string]types.AttributeValue{
+		s.partitionKey:                &types.AttributeValueMemberS{Value: partition},
+		s.sortKey:                     &types.AttributeValueMemberS{Value: sort},
+		dynamoDBInstallationAttribute: &types.AttributeValueMemberS{Value: string(data)},
+		dynamoDBInstalledAtAttribute:  numberAttribute(installedAt.UnixNano()),
+	}
+	if s.ttl > 0 {
+		item[s.ttlAttribute] = numberAttribute(installedAt.Add(s.ttl).Unix())
+	}
+
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(#installed_at) OR #installed_at <= :installed_at"),
+		ExpressionAttributeNames: map[string]string{
+			"#installed_at": dynamoDBInstalledAtAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":installed_at": item[dynamoDBInstalledAtAttribute],
+		},
+	})
+	// A later installation was stored meanwhile, which is the one to keep
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return nil
+	}
+	return err
+}
+
+// FetchInstallation returns the latest installation of the user of query when it has a UserID, or
+// else of its workspace, or of its organization when IsEnterpriseInstall is set. It returns an
+// error wrapping ErrInstallationNotFound when there is none, or when it expired but was not
+// removed by DynamoDB yet.
+func (s *DynamoDBInstallationStore) FetchInstallation(ctx context.Context, query oauth.InstallationQuery) (*oauth.Installation, error) {
+	sort := dynamoDBLatestSortKey
+	if query.UserID != "" {
+		sort = dynamoDBUserSortPrefix + query.UserID
+	}
+
+	output, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.table),
+		Key:            s.key(dynamoDBQueryPartition(query), sort),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := output.Item[dynamoDBInstallationAttribute].(*types.AttributeValueMemberS)
+	if !ok || s.expired(output.Item) {
+		return nil, fmt.Errorf("%w for query: %+v", ErrInstallationNotFound, query)
+	}
+
+	installation := &oauth.Installation{}
+	if err := json.Unmarshal([]byte(data.Value), installation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal installation: %w", err)
+	}
+	return installation, nil
+}
+
+// DeleteInstallation deletes the installation of the user of query when it has a UserID, or else
+// every installation of its workspace or organization, including those of its users
+func (s *DynamoDBInstallationStore) DeleteInstallation(ctx context.Context, query oauth.InstallationQuery) error {
+	partition := dynamoDBQueryPartition(query)
+	if query.UserID != "" {
+		return s.delete(ctx, partition, dynamoDBUserSortPrefix+query.UserID)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                aws.String(s.table),
+		KeyConditionExpression:   aws.String("#pk = :pk"),
+		ProjectionExpression:     aws.String("#sk"),
+		ExpressionAttributeNames: map[string]string{"#pk": s.partitionKey, "#sk": s.sortKey},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: partition},
+		},
+	}
+	for {
+		output, err := s.client.Query(ctx, input)
+		if err != nil {
+			return err
+		}
+		for _, item := range output.Items {
+			sort, ok := item[s.sortKey].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			if err := s.delete(ctx, partition, sort.Value); err != nil {
+				return err
+			}
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// delete deletes an installation item
+func (s *DynamoDBInstallationStore) delete(ctx context.Context, partition, sort string) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.table),
+		Key:       s.key(partition, sort),
+	})
+	return err
+}
+
+// key returns the primary key of an installation item
+func (s *DynamoDBInstallationStore) key(partition, sort string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		s.partitionKey: &types.AttributeValueMemberS{Value: partition},
+		s.sortKey:      &types.AttributeValueMemberS{Value: sort},
+	}
+}
+
+// expired reports whether item has a TTL in the past
+func (s *DynamoDBInstallationStore) expired(item map[string]types.AttributeValue) bool {
+	ttl, ok := item[s.ttlAttribute].(*types.AttributeValueMemberN)
+	if !ok {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(ttl.Value, 10, 64)
+	return err == nil && expiresAt <= time.Now().Unix()
+}
+
+// dynamoDBQueryPartition returns the partition key of the installation query asks for
+func dynamoDBQueryPartition(query oauth.InstallationQuery) string {
+	if query.IsEnterpriseInstall {
+		return dynamoDBPartition(query.EnterpriseID, "")
+	}
+	return dynamoDBPartition(query.EnterpriseID, query.TeamID)
+}
+
+// dynamoDBPartition returns the partition key of the installations of a workspace or organization
+func dynamoDBPartition(enterpriseID, teamID string) string {
+	if enterpriseID == "" {
+		enterpriseID = noID
+	}
+	if teamID == "" {
+		teamID = noID
+	}
+	return dynamoDBPartitionPrefix + enterpriseID + "#" + teamID
+}
+
+// numberAttribute returns a DynamoDB number attribute of n
+func numberAttribute(n int64) *types.AttributeValueMemberN {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(n, 10)}
+}
@@ -0,0 +1,211 @@
+package installstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+)
+
+const (
+	// defaultFileStoreDir is the directory, under the home directory, of file installation stores
+	// created without a base directory, like the one of bolt-js
+	defaultFileStoreDir = ".bolt-go-oauth-store"
+	// fileStoreLatest is the file of the latest installation of a workspace or organization
+	fileStoreLatest = "app-latest"
+)
+
+// FileInstallationStore stores installations as JSON files, like the FileInstallationStore of
+// bolt-js: a directory per workspace, or per organization for org-wide installs, holding its
+// latest installation and the latest installation of each user who installed the app there.
+// Directories are only accessible by the owner and files only readable by them, as they contain
+// tokens. It suits single-node apps and local development; the files are not shared by replicas.
+type FileInstallationStore struct {
+	baseDir string
+	mutex   sync.RWMutex
+}
+
+var _ oauth.InstallationStore = (*FileInstallationStore)(nil)
+
+// NewFileInstallationStore creates a FileInstallationStore writing under baseDir, which is
+// created when needed. An empty baseDir stands for ".bolt-go-oauth-store" in the home directory.
+func NewFileInstallationStore(baseDir string) *FileInstallationStore {
+	if baseDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		baseDir = filepath.Join(home, defaultFileStoreDir)
+	}
+	return &FileInstallationStore{baseDir: baseDir}
+}
+
+// StoreInstallation stores installation as the latest of its workspace or organization, and as
+// the latest of the user who installed it, if any
+func (f *FileInstallationStore) StoreInstallation(ctx context.Context, installation *oauth.Installation) error {
+	if installation == nil {
+		return errors.New("installation cannot be nil")
+	}
+	var enterpriseID, teamID string
+	if installation.Enterprise != nil {
+		enterpriseID = installation.Enterprise.ID
+	}
+	if installation.Team != nil && !installation.IsEnterpriseInstall {
+		teamID = installation.Team.ID
+	}
+	if enterpriseID == "" && teamID == "" {
+		return errors.New("installation has neither a team nor an enterprise")
+	}
+	userID := installerID(installation)
+	dir, err := f.workspaceDir(enterpriseID, teamID)
+	if err != nil {
+		return err
+	}
+	if err := validFileStoreID(userID); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(installation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal installation: %w", err)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create installation directory: %w", err)
+	}
+	if err := writeFileAtomically(filepath.Join(dir, fileStoreLatest), data); err != nil {
+		return err
+	}
+	if userID != "" {
+		return writeFileAtomically(filepath.Join(dir, userFileName(userID)), data)
+	}
+	return nil
+}
+
+// FetchInstallation returns the latest installation of the user of query when it has a UserID, or
+// else of its workspace, or of its organization when IsEnterpriseInstall is set. It returns an
+// error wrapping ErrInstallationNotFound when there is none.
+func (f *FileInstallationStore) FetchInstallation(ctx context.Context, query oauth.InstallationQuery) (*oauth.Installation, error) {
+	dir, err := f.queryDir(query)
+	if err != nil {
+		return nil, err
+	}
+	if err := validFileStoreID(query.UserID); err != nil {
+		return nil, err
+	}
+	name := fileStoreLatest
+	if query.UserID != "" {
+		name = userFileName(query.UserID)
+	}
+
+	f.mutex.RLock()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	f.mutex.RUnlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for query: %+v", ErrInstallationNotFound, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	installation := &oauth.Installation{}
+	if err := json.Unmarshal(data, installation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal installation: %w", err)
+	}
+	return installation, nil
+}
+
+// DeleteInstallation deletes the installation of the user of query when it has a UserID, or else
+// every installation of its workspace or organization, including those of its users
+func (f *FileInstallationStore) DeleteInstallation(ctx context.Context, query oauth.InstallationQuery) error {
+	dir, err := f.queryDir(query)
+	if err != nil {
+		return err
+	}
+	if err := validFileStoreID(query.UserID); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if query.UserID == "" {
+		return os.RemoveAll(dir)
+	}
+	if err := os.Remove(filepath.Join(dir, userFileName(query.UserID))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// queryDir returns the directory of the installations query asks for
+func (f *FileInstallationStore) queryDir(query oauth.InstallationQuery) (string, error) {
+	if query.IsEnterpriseInstall {
+		return f.workspaceDir(query.EnterpriseID, "")
+	}
+	return f.workspaceDir(query.EnterpriseID, query.TeamID)
+}
+
+// workspaceDir returns the directory of the installations of a workspace or organization
+func (f *FileInstallationStore) workspaceDir(enterpriseID, teamID string) (string, error) {
+	if err := validFileStoreID(enterpriseID); err != nil {
+		return "", err
+	}
+	if err := validFileStoreID(teamID); err != nil {
+		return "", err
+	}
+	if enterpriseID == "" {
+		enterpriseID = noID
+	}
+	if teamID == "" {
+		teamID = noID
+	}
+	return filepath.Join(f.baseDir, enterpriseID+"-"+teamID), nil
+}
+
+// userFileName returns the file of the latest installation of a user
+func userFileName(userID string) string {
+	return "user-" + userID + "-latest"
+}
+
+// validFileStoreID rejects IDs that could name files outside of the store. Slack IDs only have
+// letters and digits.
+func validFileStoreID(id string) error {
+	for _, r := range id {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return fmt.Errorf("invalid ID for the file installation store: %q", id)
+		}
+	}
+	return nil
+}
+
+// writeFileAtomically replaces the file at path with data, readable by its owner only, so readers
+// never see a partially written installation
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write installation: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	// CreateTemp creates the file with mode 0600
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write installation: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write installation: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write installation: %w", err)
+	}
+	return nil
+}
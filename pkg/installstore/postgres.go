@@ -0,0 +1,143 @@
+package installstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+)
+
+// PostgresInstallationSchema creates the table of PostgresInstallationStore. Migrate runs it; apps
+// managing their schema with a migration tool can copy it instead. Installations are stored whole
+// as JSON, keyed by their enterprise, team and installing user, with "" for none.
+const PostgresInstallationSchema = `CREATE TABLE IF NOT EXISTS slack_installations (
+	enterprise_id TEXT NOT NULL DEFAULT '',
+	team_id TEXT NOT NULL DEFAULT '',
+	user_id TEXT NOT NULL DEFAULT '',
+	is_enterprise_install BOOLEAN NOT NULL DEFAULT FALSE,
+	installation JSONB NOT NULL,
+	installed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (enterprise_id, team_id, user_id)
+)`
+
+const (
+	postgresUpsertInstallation = `INSERT INTO slack_installations (enterprise_id, team_id, user_id, is_enterprise_install, installation, installed_at)
+VALUES ($1, $2, $3, $4, $5, now())
+ON CONFLICT (enterprise_id, team_id, user_id) DO UPDATE SET
+	is_enterprise_install = EXCLUDED.is_enterprise_install,
+	installation = EXCLUDED.installation,
+	installed_at = EXCLUDED.installed_at`
+	postgresSelectInstallation  = `SELECT installation FROM slack_installations WHERE enterprise_id = $1 AND team_id = $2 AND user_id = $3`
+	postgresDeleteInstallation  = `DELETE FROM slack_installations WHERE enterprise_id = $1 AND team_id = $2 AND user_id = $3`
+	postgresDeleteInstallations = `DELETE FROM slack_installations WHERE enterprise_id = $1 AND team_id = $2`
+)
+
+// PostgresInstallationStore stores installations in PostgreSQL with the same semantics as
+// RedisInstallationStore: the latest installation of each workspace, or of each organization for
+// org-wide installs, and the latest installation of each user who installed the app there. It
+// works with any database/sql driver for PostgreSQL, such as pgx or lib/pq.
+type PostgresInstallationStore struct {
+	db *sql.DB
+}
+
+var _ oauth.InstallationStore = (*PostgresInstallationStore)(nil)
+
+// NewPostgresInstallationStore creates a PostgresInstallationStore using db. Call Migrate once
+// to create its table.
+func NewPostgresInstallationStore(db *sql.DB) *PostgresInstallationStore {
+	return &PostgresInstallationStore{db: db}
+}
+
+// Migrate creates the table of the store unless it exists, see PostgresInstallationSchema
+func (s *PostgresInstallationStore) Migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, PostgresInstallationSchema); err != nil {
+		return fmt.Errorf("failed to create the installations table: %w", err)
+	}
+	return nil
+}
+
+// StoreInstallation stores installation as the latest of its workspace or organization, and as
+// the latest of the user who installed it, if any
+func (s *PostgresInstallationStore) StoreInstallation(ctx context.Context, installation *oauth.Installation) error {
+	if installation == nil {
+		return errors.New("installation cannot be nil")
+	}
+	var enterpriseID, teamID string
+	if installation.Enterprise != nil {
+		enterpriseID = installation.Enterprise.ID
+	}
+	if installation.Team != nil && !installation.IsEnterpriseInstall {
+		teamID = installation.Team.ID
+	}
+	if enterpriseID == "" && teamID == "" {
+		return errors.New("installation has neither a team nor an enterprise")
+	}
+
+	data, err := json.Marshal(installation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal installation: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// JSON is passed as text, which every driver converts to JSONB
+	if _, err := tx.ExecContext(ctx, postgresUpsertInstallation, enterpriseID, teamID, "", installation.IsEnterpriseInstall, string(data)); err != nil {
+		return err
+	}
+	if userID := installerID(installation); userID != "" {
+		if _, err := tx.ExecContext(ctx, postgresUpsertInstallation, enterpriseID, teamID, userID, installation.IsEnterpriseInstall, string(data)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// FetchInstallation returns the latest installation of the user of query when it has a UserID, or
+// else of its workspace, identified by both its enterprise and team IDs on Enterprise Grid, or of
+// its organization when IsEnterpriseInstall is set. It returns an error wrapping
+// ErrInstallationNotFound when there is none.
+func (s *PostgresInstallationStore) FetchInstallation(ctx context.Context, query oauth.InstallationQuery) (*oauth.Installation, error) {
+	enterpriseID, teamID := postgresQueryKey(query)
+
+	var data []byte
+	err := s.db.QueryRowContext(ctx, postgresSelectInstallation, enterpriseID, teamID, query.UserID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w for query: %+v", ErrInstallationNotFound, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	installation := &oauth.Installation{}
+	if err := json.Unmarshal(data, installation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal installation: %w", err)
+	}
+	return installation, nil
+}
+
+// DeleteInstallation deletes the installation of the user of query when it has a UserID, or else
+// every installation of its workspace or organization, including those of its users
+func (s *PostgresInstallationStore) DeleteInstallation(ctx context.Context, query oauth.InstallationQuery) error {
+	enterpriseID, teamID := postgresQueryKey(query)
+	if query.UserID != "" {
+		_, err := s.db.ExecContext(ctx, postgresDeleteInstallation, enterpriseID, teamID, query.UserID)
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, postgresDeleteInstallations, enterpriseID, teamID)
+	return err
+}
+
+// postgresQueryKey returns the enterprise and team IDs of the installation query asks for
+func postgresQueryKey(query oauth.InstallationQuery) (enterpriseID, teamID string) {
+	if query.IsEnterpriseInstall {
+		return query.EnterpriseID, ""
+	}
+	return query.EnterpriseID, query.TeamID
+}
@@ -0,0 +1,200 @@
+// Package installstore provides oauth.InstallationStore and oauth.StateStore implementations
+// persisting installations across restarts: in Redis, PostgreSQL or DynamoDB, where every replica
+// of an app sees them, or in files for single-node apps.
+package installstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultRedisPrefix prefixes keys when no prefix is configured
+const defaultRedisPrefix = "bolt:installation:"
+
+// noID stands for the enterprise or team of keys of installations without one
+const noID = "none"
+
+// ErrInstallationNotFound is wrapped by the errors of FetchInstallation for queries matching no
+// installation
+var ErrInstallationNotFound = oauth.ErrInstallationNotFound
+
+// RedisClient runs Redis commands; *redis.Client, *redis.ClusterClient and *redis.Ring implement it
+type RedisClient interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	SRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	SMembers(ctx context.Context, key string) *redis.StringSliceCmd
+	Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+}
+
+var _ RedisClient = (*redis.Client)(nil)
+
+// RedisInstallationStoreOptions configures a RedisInstallationStore
+type RedisInstallationStoreOptions struct {
+	// Client runs the commands
+	Client RedisClient
+	// Prefix prefixes every key (default "bolt:installation:"), e.g. to share a database between apps
+	Prefix string
+	// TTL expires installations not stored again for this long; zero keeps them until deleted
+	TTL time.Duration
+}
+
+// RedisInstallationStore stores installations as JSON in Redis, like bolt-js installation stores:
+// the latest installation of each workspace, or of each organization for org-wide installs, and
+// the latest installation of each user who installed the app there.
+type RedisInstallationStore struct {
+	client RedisClient
+	prefix string
+	ttl    time.Duration
+}
+
+var _ oauth.InstallationStore = (*RedisInstallationStore)(nil)
+
+// NewRedisInstallationStore creates a RedisInstallationStore from options
+func NewRedisInstallationStore(options RedisInstallationStoreOptions) (*RedisInstallationStore, error) {
+	if options.Client == nil {
+		return nil, errors.New("redis client is required")
+	}
+	prefix := options.Prefix
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+	return &RedisInstallationStore{client: options.Client, prefix: prefix, ttl: options.TTL}, nil
+}
+
+// StoreInstallation stores installation as the latest of its workspace or organization, and as
+// the latest of the user who installed it, if any
+func (s *RedisInstallationStore) StoreInstallation(ctx context.Context, installation *oauth.Installation) error {
+	if installation == nil {
+		return errors.New("installation cannot be nil")
+	}
+	var enterpriseID, teamID string
+	if installation.Enterprise != nil {
+		enterpriseID = installation.Enterprise.ID
+	}
+	if installation.Team != nil && !installation.IsEnterpriseInstall {
+		teamID = installation.Team.ID
+	}
+	if enterpriseID == "" && teamID == "" {
+		return errors.New("installation has neither a team nor an enterprise")
+	}
+
+	data, err := json.Marshal(installation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal installation: %w", err)
+	}
+	base := s.baseKey(enterpriseID, teamID)
+	if err := s.client.Set(ctx, base, data, s.ttl).Err(); err != nil {
+		return err
+	}
+
+	userID := installerID(installation)
+	if userID == "" {
+		return nil
+	}
+	if err := s.client.Set(ctx, userKey(base, userID), data, s.ttl).Err(); err != nil {
+		return err
+	}
+	// Track the users of the workspace, so deleting it deletes theirs
+	if err := s.client.SAdd(ctx, usersKey(base), userID).Err(); err != nil {
+		return err
+	}
+	if s.ttl > 0 {
+		return s.client.Expire(ctx, usersKey(base), s.ttl).Err()
+	}
+	return nil
+}
+
+// FetchInstallation returns the latest installation of the user of query when it has a UserID, or
+// else of its workspace, or of its organization when IsEnterpriseInstall is set. It returns an
+// error wrapping ErrInstallationNotFound when there is none.
+func (s *RedisInstallationStore) FetchInstallation(ctx context.Context, query oauth.InstallationQuery) (*oauth.Installation, error) {
+	key := s.queryKey(query)
+	if query.UserID != "" {
+		key = userKey(key, query.UserID)
+	}
+
+	data, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w for query: %+v", ErrInstallationNotFound, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	installation := &oauth.Installation{}
+	if err := json.Unmarshal(data, installation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal installation: %w", err)
+	}
+	return installation, nil
+}
+
+// DeleteInstallation deletes the installation of the user of query when it has a UserID, or else
+// every installation of its workspace or organization, including those of its users
+func (s *RedisInstallationStore) DeleteInstallation(ctx context.Context, query oauth.InstallationQuery) error {
+	base := s.queryKey(query)
+	if query.UserID != "" {
+		if err := s.client.Del(ctx, userKey(base, query.UserID)).Err(); err != nil {
+			return err
+		}
+		return s.client.SRem(ctx, usersKey(base), query.UserID).Err()
+	}
+
+	users, err := s.client.SMembers(ctx, usersKey(base)).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{base, usersKey(base)}
+	for _, userID := range users {
+		keys = append(keys, userKey(base, userID))
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+// queryKey returns the key of the installation query asks for
+func (s *RedisInstallationStore) queryKey(query oauth.InstallationQuery) string {
+	if query.IsEnterpriseInstall {
+		return s.baseKey(query.EnterpriseID, "")
+	}
+	return s.baseKey(query.EnterpriseID, query.TeamID)
+}
+
+// baseKey returns the key of the latest installation of a workspace or organization
+func (s *RedisInstallationStore) baseKey(enterpriseID, teamID string) string {
+	if enterpriseID == "" {
+		enterpriseID = noID
+	}
+	if teamID == "" {
+		teamID = noID
+	}
+	return s.prefix + enterpriseID + ":" + teamID
+}
+
+// userKey returns the key of the latest installation of a user in the workspace of base
+func userKey(base, userID string) string {
+	return base + ":user:" + userID
+}
+
+// usersKey returns the key of the set of users with an installation in the workspace of base
+func usersKey(base string) string {
+	return base + ":users"
+}
+
+// installerID returns the ID of the user who installed installation, if any
+func installerID(installation *oauth.Installation) string {
+	if installation.AuthedUser != nil && installation.AuthedUser.ID != "" {
+		return installation.AuthedUser.ID
+	}
+	if installation.User != nil {
+		return installation.User.ID
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package installstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// defaultRedisStatePrefix prefixes state keys when no prefix is configured
+	defaultRedisStatePrefix = "bolt:oauth-state:"
+	// defaultRedisStateExpiration is how long states are valid when no expiration is configured
+	defaultRedisStateExpiration = 10 * time.Minute
+)
+
+// RedisStateClient runs the Redis commands of RedisStateStore; *redis.Client,
+// *redis.ClusterClient and *redis.Ring implement it
+type RedisStateClient interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	GetDel(ctx context.Context, key string) *redis.StringCmd
+}
+
+var _ RedisStateClient = (*redis.Client)(nil)
+
+// RedisStateStoreOptions configures a RedisStateStore
+type RedisStateStoreOptions struct {
+	// Client runs the commands
+	Client RedisStateClient
+	// Prefix prefixes every key (default "bolt:oauth-state:")
+	Prefix string
+	// Expiration is how long installers have to complete the installation (default 10 minutes)
+	Expiration time.Duration
+}
+
+// RedisStateStore keeps the install options of each state parameter in Redis, so any instance of
+// an app can verify the redirect of an installation started on another. States are random and
+// can only be verified once.
+type RedisStateStore struct {
+	client     RedisStateClient
+	prefix     string
+	expiration time.Duration
+}
+
+var _ oauth.StateStore = (*RedisStateStore)(nil)
+
+// NewRedisStateStore creates a RedisStateStore from options
+func NewRedisStateStore(options RedisStateStoreOptions) (*RedisStateStore, error) {
+	if options.Client == nil {
+		return nil, errors.New("redis client is required")
+	}
+	store := &RedisStateStore{client: options.Client, prefix: options.Prefix, expiration: options.Expiration}
+	if store.prefix == "" {
+		store.prefix = defaultRedisStatePrefix
+	}
+	if store.expiration <= 0 {
+		store.expiration = defaultRedisStateExpiration
+	}
+	return store, nil
+}
+
+// GenerateStateParam stores installOptions under a new random state
+func (s *RedisStateStore) GenerateStateParam(ctx context.Context, installOptions *oauth.InstallURLOptions) (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate random state: %w", err)
+	}
+	state := hex.EncodeToString(bytes)
+
+	if installOptions == nil {
+		installOptions = &oauth.InstallURLOptions{}
+	}
+	data, err := json.Marshal(installOptions)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal install options: %w", err)
+	}
+	if err := s.client.Set(ctx, s.prefix+state, data, s.expiration).Err(); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+// VerifyStateParam returns the install options of state and deletes it, so a redirect cannot be
+// replayed
+func (s *RedisStateStore) VerifyStateParam(ctx context.Context, state string) (*oauth.InstallURLOptions, error) {
+	data, err := s.client.GetDel(ctx, s.prefix+state).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, errors.New("invalid or expired state parameter")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	installOptions := &oauth.InstallURLOptions{}
+	if err := json.Unmarshal(data, installOptions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal install options: %w", err)
+	}
+	return installOptions, nil
+}
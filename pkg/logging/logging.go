@@ -0,0 +1,80 @@
+// Package logging defines the Logger interface used throughout bolt-go. *slog.Logger implements
+// it and is the default; the zap and zerolog subpackages adapt those loggers to it.
+package logging
+
+import "log/slog"
+
+// Logger logs a message with slog-style arguments: alternating keys and values, or slog.Attr
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// badKey is the key given to a value without one, matching slog
+const badKey = "!BADKEY"
+
+// Fields calls field for each key and value in slog-style args. slog.Attr arguments are
+// expanded, group attributes use dotted keys, and values without a key get "!BADKEY" as slog does.
+func Fields(args []any, field func(key string, value any)) {
+	for len(args) > 0 {
+		switch key := args[0].(type) {
+		case slog.Attr:
+			attrFields("", key, field)
+			args = args[1:]
+		case string:
+			if len(args) == 1 {
+				field(badKey, key)
+				return
+			}
+			field(key, args[1])
+			args = args[2:]
+		default:
+			field(badKey, key)
+			args = args[1:]
+		}
+	}
+}
+
+// attrFields calls field for attr, flattening groups under prefix
+func attrFields(prefix string, attr slog.Attr, field func(key string, value any)) {
+	key := attr.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+
+	value := attr.Value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		field(key, value.Any())
+		return
+	}
+	for _, member := range value.Group() {
+		attrFields(key, member, field)
+	}
+}
+
+// With returns a Logger that adds args to every message logged through logger. A *slog.Logger
+// is extended with its own With method.
+func With(logger Logger, args ...any) Logger {
+	if l, ok := logger.(*slog.Logger); ok {
+		return l.With(args...)
+	}
+	return &withLogger{logger: logger, args: args}
+}
+
+// withLogger prepends args to the arguments of every message
+type withLogger struct {
+	logger Logger
+	args   []any
+}
+
+func (l *withLogger) Debug(msg string, args ...any) { l.logger.Debug(msg, l.with(args)...) }
+func (l *withLogger) Info(msg string, args ...any)  { l.logger.Info(msg, l.with(args)...) }
+func (l *withLogger) Warn(msg string, args ...any)  { l.logger.Warn(msg, l.with(args)...) }
+func (l *withLogger) Error(msg string, args ...any) { l.logger.Error(msg, l.with(args)...) }
+
+// with returns l.args followed by args without modifying l.args
+func (l *withLogger) with(args []any) []any {
+	return append(l.args[:len(l.args):len(l.args)], args...)
+}
@@ -0,0 +1,54 @@
+// Package zap adapts a zap logger to the bolt-go logging.Logger interface
+package zap
+
+import (
+	"github.com/Asafrose/bolt-go/pkg/logging"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger logs bolt-go messages to a *zap.Logger, turning slog-style arguments into zap fields
+type Logger struct {
+	logger *uberzap.Logger
+}
+
+var _ logging.Logger = (*Logger)(nil)
+
+// New creates a Logger writing to logger. Caller information points at the bolt-go code that logged.
+func New(logger *uberzap.Logger) *Logger {
+	return &Logger{logger: logger.WithOptions(uberzap.AddCallerSkip(2))}
+}
+
+// Debug logs msg at debug level
+func (l *Logger) Debug(msg string, args ...any) {
+	l.log(zapcore.DebugLevel, msg, args)
+}
+
+// Info logs msg at info level
+func (l *Logger) Info(msg string, args ...any) {
+	l.log(zapcore.InfoLevel, msg, args)
+}
+
+// Warn logs msg at warn level
+func (l *Logger) Warn(msg string, args ...any) {
+	l.log(zapcore.WarnLevel, msg, args)
+}
+
+// Error logs msg at error level
+func (l *Logger) Error(msg string, args ...any) {
+	l.log(zapcore.ErrorLevel, msg, args)
+}
+
+// log writes msg if level is enabled, only then converting args to fields
+func (l *Logger) log(level zapcore.Level, msg string, args []any) {
+	entry := l.logger.Check(level, msg)
+	if entry == nil {
+		return
+	}
+
+	fields := make([]zapcore.Field, 0, len(args)/2)
+	logging.Fields(args, func(key string, value any) {
+		fields = append(fields, uberzap.Any(key, value))
+	})
+	entry.Write(fields...)
+}
@@ -0,0 +1,55 @@
+// Package zerolog adapts a zerolog logger to the bolt-go logging.Logger interface
+package zerolog
+
+import (
+	"github.com/Asafrose/bolt-go/pkg/logging"
+	rszerolog "github.com/rs/zerolog"
+)
+
+// Logger logs bolt-go messages to a zerolog.Logger, turning slog-style arguments into fields
+type Logger struct {
+	logger rszerolog.Logger
+}
+
+var _ logging.Logger = (*Logger)(nil)
+
+// New creates a Logger writing to logger
+func New(logger rszerolog.Logger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Debug logs msg at debug level
+func (l *Logger) Debug(msg string, args ...any) {
+	write(l.logger.Debug(), msg, args)
+}
+
+// Info logs msg at info level
+func (l *Logger) Info(msg string, args ...any) {
+	write(l.logger.Info(), msg, args)
+}
+
+// Warn logs msg at warn level
+func (l *Logger) Warn(msg string, args ...any) {
+	write(l.logger.Warn(), msg, args)
+}
+
+// Error logs msg at error level
+func (l *Logger) Error(msg string, args ...any) {
+	write(l.logger.Error(), msg, args)
+}
+
+// write adds args to event and sends it; zerolog returns a nil event for disabled levels
+func write(event *rszerolog.Event, msg string, args []any) {
+	if event == nil {
+		return
+	}
+
+	logging.Fields(args, func(key string, value any) {
+		if err, ok := value.(error); ok {
+			event.AnErr(key, err)
+			return
+		}
+		event.Interface(key, value)
+	})
+	event.Msg(msg)
+}
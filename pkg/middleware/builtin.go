@@ -84,6 +84,7 @@ func OnlyViewActions(args types.AllMiddlewareArgs) error {
 
 // MatchEventType creates middleware that matches specific event types (string or RegExp)
 func MatchEventType(pattern interface{}) types.Middleware[types.AllMiddlewareArgs] {
+	pattern = helpers.NormalizePattern(pattern)
 	return func(args types.AllMiddlewareArgs) error {
 		// Only process event middleware args
 		if middlewareArgs, exists := args.Context.Custom["middlewareArgs"]; exists {
@@ -103,23 +104,13 @@ func MatchEventType(pattern interface{}) types.Middleware[types.AllMiddlewareArg
 					if actualEventType, exists := eventMap["type"]; exists {
 						if typeStr, ok := actualEventType.(string); ok {
 							// Match using pattern (string or RegExp)
-							if helpers.MatchesPattern(typeStr, pattern) {
-								// For RegExp patterns, store matches in context
-								if regexPattern, ok := pattern.(*regexp.Regexp); ok {
-									if matches := regexPattern.FindStringSubmatch(typeStr); matches != nil {
-										if args.Context.Custom == nil {
-											args.Context.Custom = make(map[string]interface{})
-										}
-										args.Context.Custom["matches"] = matches
-									}
-								} else if regexPattern, ok := pattern.(regexp.Regexp); ok {
-									if matches := regexPattern.FindStringSubmatch(typeStr); matches != nil {
-										if args.Context.Custom == nil {
-											args.Context.Custom = make(map[string]interface{})
-										}
-										args.Context.Custom["matches"] = matches
-									}
+							// For RegExp patterns, store matches in context
+							if regexPattern, ok := pattern.(*regexp.Regexp); ok {
+								if matches := args.Context.FindStringSubmatch(regexPattern, typeStr); matches != nil {
+									args.Context.SetMatches(matches)
+									return args.Next()
 								}
+							} else if helpers.MatchesPattern(typeStr, pattern) {
 								return args.Next()
 							}
 						}
@@ -164,6 +155,7 @@ func MatchConstraints(constraints types.ActionConstraints) types.Middleware[type
 
 // MatchMessage creates middleware that matches message patterns
 func MatchMessage(pattern interface{}) types.Middleware[types.AllMiddlewareArgs] {
+	pattern = helpers.NormalizePattern(pattern)
 	return func(args types.AllMiddlewareArgs) error {
 		// Only process message events
 		if middlewareArgs, exists := args.Context.Custom["middlewareArgs"]; exists {
@@ -171,25 +163,13 @@ func MatchMessage(pattern interface{}) types.Middleware[types.AllMiddlewareArgs]
 				if eventArgs.Message != nil && eventArgs.Message.Text != "" {
 					text := eventArgs.Message.Text
 
-					// Match using pattern (string or RegExp)
-					if helpers.MatchesPattern(text, pattern) {
-						// For RegExp patterns, store matches in context
-						if regexPattern, ok := pattern.(*regexp.Regexp); ok {
-							if matches := regexPattern.FindStringSubmatch(text); matches != nil {
-								if args.Context.Custom == nil {
-									args.Context.Custom = make(map[string]interface{})
-								}
-								args.Context.Custom["matches"] = matches
-							}
-						} else if regexPattern, ok := pattern.(regexp.Regexp); ok {
-							if matches := regexPattern.FindStringSubmatch(text); matches != nil {
-								if args.Context.Custom == nil {
-									args.Context.Custom = make(map[string]interface{})
-								}
-								args.Context.Custom["matches"] = matches
-							}
+					// Match using pattern (string or RegExp); RegExp results are shared with listener matching
+					if regexPattern, ok := pattern.(*regexp.Regexp); ok {
+						if matches := args.Context.FindStringSubmatch(regexPattern, text); matches != nil {
+							args.Context.SetMatches(matches)
+							return args.Next()
 						}
-
+					} else if helpers.MatchesPattern(text, pattern) {
 						return args.Next()
 					}
 				}
@@ -418,6 +398,7 @@ func CreateMiddlewareChain(middlewares ...types.Middleware[types.AllMiddlewareAr
 				Logger:  args.Logger,
 				Client:  args.Client,
 				Next:    next,
+				Ctx:     args.Ctx,
 			}
 
 			return currentMiddleware(newArgs)
@@ -427,6 +408,19 @@ func CreateMiddlewareChain(middlewares ...types.Middleware[types.AllMiddlewareAr
 	}
 }
 
+// Listener adapts middleware written for all requests, such as the built-ins of this package, to
+// a listener's middleware, e.g.
+//
+//	app.Message("deploy", middleware.Listener[types.SlackEventMiddlewareArgs](middleware.Subtype("bot_message")), handler)
+//
+// The adapted middleware gets the listener's Next, so it continues or short-circuits the
+// listener's chain like any other listener middleware.
+func Listener[Args types.ListenerArgs](m types.Middleware[types.AllMiddlewareArgs]) types.Middleware[Args] {
+	return func(args Args) error {
+		return m(args.BaseArgs())
+	}
+}
+
 // ProcessMessageEvent processes message events for pattern matching
 func ProcessMessageEvent(body []byte, pattern interface{}) bool {
 	var parsed map[string]interface{}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Subcommand is a subcommand of a slash command dispatched by Subcommands
+type Subcommand struct {
+	// Description is shown next to the subcommand in the help
+	Description string
+	// Usage describes the arguments of the subcommand in the help, e.g. "<title> [due date]"
+	Usage string
+	// Handler runs the subcommand with the text following its name. It acknowledges the command
+	// as any command listener does.
+	Handler func(args types.SlackCommandMiddlewareArgs, arguments string) error
+}
+
+// Subcommands creates a command listener dispatching on the first word of the command text, e.g.
+// "add" in "/todo add Buy milk", to the subcommand of that name. "help" and an empty text are
+// answered with the list of subcommands, and unknown subcommands with suggestions of the closest
+// names and the list. Names are matched ignoring case.
+func Subcommands(subcommands map[string]Subcommand) types.Middleware[types.SlackCommandMiddlewareArgs] {
+	byName := make(map[string]Subcommand, len(subcommands))
+	names := make([]string, 0, len(subcommands))
+	for name, subcommand := range subcommands {
+		name = strings.ToLower(name)
+		byName[name] = subcommand
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return func(args types.SlackCommandMiddlewareArgs) error {
+		name, arguments, _ := strings.Cut(strings.TrimSpace(args.Command.Text), " ")
+		name = strings.ToLower(name)
+
+		if subcommand, ok := byName[name]; ok && subcommand.Handler != nil {
+			return subcommand.Handler(args, strings.TrimSpace(arguments))
+		}
+
+		help := subcommandHelp(args.Command.Command, names, byName)
+		if name != "" && name != "help" {
+			help = unknownSubcommand(args.Command.Command, name, names) + "\n\n" + help
+		}
+		return args.Ack(&types.CommandResponse{Text: help, ResponseType: types.ResponseTypeEphemeral})
+	}
+}
+
+// subcommandHelp lists the subcommands of command
+func subcommandHelp(command string, names []string, subcommands map[string]Subcommand) string {
+	var help strings.Builder
+	fmt.Fprintf(&help, "*Usage:* `%s <subcommand>`\n", command)
+	for _, name := range names {
+		subcommand := subcommands[name]
+		usage := strings.TrimSpace(command + " " + name + " " + subcommand.Usage)
+		fmt.Fprintf(&help, "\n• `%s`", usage)
+		if subcommand.Description != "" {
+			help.WriteString(" – " + subcommand.Description)
+		}
+	}
+	fmt.Fprintf(&help, "\n• `%s help` – Show this message", command)
+	return help.String()
+}
+
+// unknownSubcommand reports that name is not a subcommand of command, suggesting the names it
+// most likely meant: those it is a prefix of, or else those within two edits of it
+func unknownSubcommand(command, name string, names []string) string {
+	var suggestions []string
+	for _, candidate := range names {
+		if strings.HasPrefix(candidate, name) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	if len(suggestions) == 0 {
+		for _, candidate := range names {
+			if editDistance(name, candidate) <= 2 {
+				suggestions = append(suggestions, candidate)
+			}
+		}
+	}
+
+	message := fmt.Sprintf("Unknown subcommand `%s`.", name)
+	if len(suggestions) > 0 {
+		quoted := make([]string, len(suggestions))
+		for i, suggestion := range suggestions {
+			quoted[i] = fmt.Sprintf("`%s %s`", command, suggestion)
+		}
+		message += " Did you mean " + strings.Join(quoted, " or ") + "?"
+	}
+	return message
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
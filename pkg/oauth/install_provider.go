@@ -8,19 +8,23 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/slack-go/slack"
+
+	"github.com/Asafrose/bolt-go/pkg/logging"
 )
 
 // InstallProvider handles Slack OAuth installation flow
 type InstallProvider struct {
 	clientID                     string
+	clientSecretMu               sync.RWMutex
 	clientSecret                 string
 	stateSecret                  string
 	installationStore            InstallationStore
 	authVersion                  string // v1 or v2
-	logger                       *slog.Logger
+	logger                       logging.Logger
 	stateStore                   StateStore
 	stateVerification            bool
 	legacyStateVerification      bool
@@ -28,6 +32,7 @@ type InstallProvider struct {
 	stateCookieExpirationSeconds int
 	directInstall                bool
 	renderHtmlForInstallPath     func(*InstallURLOptions, *http.Request) string
+	renderInstallPage            func(string, *http.Request) string
 	authorizationURL             string
 }
 
@@ -81,13 +86,14 @@ func NewInstallProvider(options InstallProviderOptions) (*InstallProvider, error
 	if options.RenderHtmlForInstallPath != nil {
 		provider.renderHtmlForInstallPath = options.RenderHtmlForInstallPath
 	}
+	provider.renderInstallPage = options.RenderInstallPage
 	if options.AuthorizationURL != "" {
 		provider.authorizationURL = options.AuthorizationURL
 	}
 
 	// Set logger
 	if options.Logger != nil {
-		if logger, ok := options.Logger.(*slog.Logger); ok {
+		if logger, ok := options.Logger.(logging.Logger); ok {
 			provider.logger = logger
 		}
 	}
@@ -100,7 +106,8 @@ func NewInstallProvider(options InstallProviderOptions) (*InstallProvider, error
 		provider.stateStore = options.StateStore
 	} else if provider.stateVerification {
 		if options.StateSecret != "" {
-			provider.stateStore = NewEncryptedStateStore(options.StateSecret)
+			expiration := time.Duration(provider.stateCookieExpirationSeconds) * time.Second
+			provider.stateStore = NewJWTStateStore(options.StateSecret, expiration)
 		} else {
 			provider.stateStore = NewClearStateStore()
 		}
@@ -172,7 +179,9 @@ func (p *InstallProvider) HandleInstallPath(req *http.Request, res http.Response
 
 	// Render HTML page
 	var html string
-	if p.renderHtmlForInstallPath != nil {
+	if p.renderInstallPage != nil {
+		html = p.renderInstallPage(installURL, req)
+	} else if p.renderHtmlForInstallPath != nil {
 		html = p.renderHtmlForInstallPath(installURLOptions, req)
 	} else {
 		html = p.defaultInstallPageHTML(installURL)
@@ -213,7 +222,15 @@ func (p *InstallProvider) HandleCallback(req *http.Request, res http.ResponseWri
 
 	// Verify state parameter
 	var verifiedOptions *InstallURLOptions
-	if p.stateVerification && state != "" {
+	if p.stateVerification && state == "" {
+		// A redirect without state did not come from an install URL of this app
+		authErr := errors.New("state verification failed: missing state parameter")
+		if callbackOptions != nil && callbackOptions.Failure != nil {
+			callbackOptions.Failure(authErr, nil, req, res)
+			return nil
+		}
+		return authErr
+	} else if p.stateVerification {
 		var err error
 		verifiedOptions, err = p.stateStore.VerifyStateParam(ctx, state)
 		if err != nil {
@@ -265,6 +282,21 @@ func (p *InstallProvider) HandleCallback(req *http.Request, res http.ResponseWri
 	return nil
 }
 
+// UpdateClientSecret replaces the client secret used to exchange authorization codes, e.g. after
+// it was rotated in the app's settings
+func (p *InstallProvider) UpdateClientSecret(clientSecret string) {
+	p.clientSecretMu.Lock()
+	defer p.clientSecretMu.Unlock()
+	p.clientSecret = clientSecret
+}
+
+// currentClientSecret returns the client secret used to exchange authorization codes
+func (p *InstallProvider) currentClientSecret() string {
+	p.clientSecretMu.RLock()
+	defer p.clientSecretMu.RUnlock()
+	return p.clientSecret
+}
+
 // exchangeCodeForToken exchanges an authorization code for access tokens
 func (p *InstallProvider) exchangeCodeForToken(ctx context.Context, code string, installOptions *InstallURLOptions) (*Installation, error) {
 	var redirectURI string
@@ -274,17 +306,18 @@ func (p *InstallProvider) exchangeCodeForToken(ctx context.Context, code string,
 
 	// Use slack SDK for OAuth token exchange
 	httpClient := &http.Client{Timeout: 30 * time.Second}
+	clientSecret := p.currentClientSecret()
 
 	if p.authVersion == "v1" {
 		// Use OAuth v1 API
-		resp, err := slack.GetOAuthResponseContext(ctx, httpClient, p.clientID, p.clientSecret, code, redirectURI)
+		resp, err := slack.GetOAuthResponseContext(ctx, httpClient, p.clientID, clientSecret, code, redirectURI)
 		if err != nil {
 			return nil, fmt.Errorf("OAuth v1 token exchange failed: %w", err)
 		}
 		return p.convertOAuthV1Response(resp, installOptions), nil
 	} else {
 		// Use OAuth v2 API
-		resp, err := slack.GetOAuthV2ResponseContext(ctx, httpClient, p.clientID, p.clientSecret, code, redirectURI)
+		resp, err := slack.GetOAuthV2ResponseContext(ctx, httpClient, p.clientID, clientSecret, code, redirectURI)
 		if err != nil {
 			return nil, fmt.Errorf("OAuth v2 token exchange failed: %w", err)
 		}
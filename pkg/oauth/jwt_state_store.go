@@ -0,0 +1,95 @@
+package oauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultStateExpiration is how long state parameters are valid unless configured otherwise
+const defaultStateExpiration = 10 * time.Minute
+
+// jwtHeader is the encoded header of the state tokens, which are always signed with HS256
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// jwtStateClaims are the claims of a state token
+type jwtStateClaims struct {
+	InstallOptions *InstallURLOptions `json:"install_options,omitempty"`
+	IssuedAt       int64              `json:"iat"`
+	ExpiresAt      int64              `json:"exp"`
+}
+
+// JWTStateStore is a stateless StateStore, like the default state store of bolt-js: the state
+// parameter is a JWT signed with HMAC-SHA256 carrying the install options, so any instance
+// sharing the secret can verify it. The install options can be read by the installer but not
+// changed; use EncryptedStateStore to hide them.
+type JWTStateStore struct {
+	secret     []byte
+	expiration time.Duration
+}
+
+var _ StateStore = (*JWTStateStore)(nil)
+
+// NewJWTStateStore creates a JWTStateStore signing with secret whose states are valid for
+// expiration, or 10 minutes when it is zero
+func NewJWTStateStore(secret string, expiration time.Duration) *JWTStateStore {
+	if expiration <= 0 {
+		expiration = defaultStateExpiration
+	}
+	return &JWTStateStore{secret: []byte(secret), expiration: expiration}
+}
+
+// GenerateStateParam returns a signed token carrying installOptions
+func (j *JWTStateStore) GenerateStateParam(ctx context.Context, installOptions *InstallURLOptions) (string, error) {
+	now := time.Now()
+	claims, err := json.Marshal(jwtStateClaims{
+		InstallOptions: installOptions,
+		IssuedAt:       now.Unix(),
+		ExpiresAt:      now.Add(j.expiration).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal install options: %w", err)
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return unsigned + "." + j.sign(unsigned), nil
+}
+
+// VerifyStateParam checks the signature and expiry of state and returns its install options
+func (j *JWTStateStore) VerifyStateParam(ctx context.Context, state string) (*InstallURLOptions, error) {
+	parts := strings.Split(state, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, errors.New("invalid state parameter format")
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(j.sign(parts[0]+"."+parts[1]))) {
+		return nil, errors.New("invalid state parameter signature")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid state parameter format: %w", err)
+	}
+	var claims jwtStateClaims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, fmt.Errorf("invalid state parameter claims: %w", err)
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, errors.New("state parameter has expired")
+	}
+	if claims.InstallOptions == nil {
+		claims.InstallOptions = &InstallURLOptions{}
+	}
+	return claims.InstallOptions, nil
+}
+
+// sign returns the encoded HMAC-SHA256 signature of unsigned
+func (j *JWTStateStore) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
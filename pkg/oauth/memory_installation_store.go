@@ -46,7 +46,7 @@ func (m *MemoryInstallationStore) FetchInstallation(ctx context.Context, query I
 	key := m.generateKeyFromQuery(query)
 	installation, exists := m.installations[key]
 	if !exists {
-		return nil, fmt.Errorf("installation not found for query: %+v", query)
+		return nil, fmt.Errorf("%w for query: %+v", ErrInstallationNotFound, query)
 	}
 
 	return installation, nil
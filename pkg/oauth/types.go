@@ -2,7 +2,9 @@ package oauth
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -13,7 +15,14 @@ type InstallationStore interface {
 	DeleteInstallation(ctx context.Context, installQuery InstallationQuery) error
 }
 
-// StateStore interface for managing OAuth state
+// ErrInstallationNotFound is wrapped by the errors of FetchInstallation for queries matching no
+// installation
+var ErrInstallationNotFound = errors.New("installation not found")
+
+// StateStore generates the state parameters of install URLs and verifies those Slack redirects
+// installers back with, protecting the OAuth flow from forged redirects. JWTStateStore is the
+// default when a state secret is set; apps running several instances without a shared secret can
+// keep states in Redis with installstore.RedisStateStore.
 type StateStore interface {
 	GenerateStateParam(ctx context.Context, installOptions *InstallURLOptions) (string, error)
 	VerifyStateParam(ctx context.Context, state string) (*InstallURLOptions, error)
@@ -40,6 +49,9 @@ type Installation struct {
 	BotScopes           []string               `json:"bot_scopes,omitempty"`
 	UserScopes          []string               `json:"user_scopes,omitempty"`
 	Metadata            map[string]interface{} `json:"metadata,omitempty"`
+	// APIURL is the Web API URL of the installation when it is not the default one, e.g. for
+	// GovSlack, for authorize functions to return as AuthorizeResult.APIURL
+	APIURL string `json:"api_url,omitempty"`
 }
 
 // Team represents a Slack team/workspace
@@ -97,6 +109,14 @@ type AuthedUser struct {
 	TokenType    string     `json:"token_type,omitempty"`
 }
 
+// Scopes returns the scopes granted to the user token, for AuthorizeResult.UserScopes
+func (u *AuthedUser) Scopes() []string {
+	if u == nil || u.Scope == "" {
+		return nil
+	}
+	return strings.Split(u.Scope, ",")
+}
+
 // InstallationQuery represents a query for retrieving installations
 type InstallationQuery struct {
 	TeamID              string `json:"team_id,omitempty"`
@@ -143,6 +163,9 @@ type InstallProviderOptions struct {
 	DirectInstall                *bool                                          `json:"direct_install,omitempty"`
 	RenderHtmlForInstallPath     func(*InstallURLOptions, *http.Request) string `json:"-"`
 	AuthorizationURL             string                                         `json:"authorization_url,omitempty"`
+	// RenderInstallPage returns the HTML of the install page linking to installURL, e.g. a
+	// branded "Add to Slack" page; it replaces RenderHtmlForInstallPath
+	RenderInstallPage func(installURL string, req *http.Request) string `json:"-"`
 }
 
 // OAuthV2Response represents the response from OAuth v2 access endpoint
@@ -0,0 +1,80 @@
+package otel
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// slackAPIPathPrefix is the path prefix of Slack Web API methods
+const slackAPIPathPrefix = "/api/"
+
+// transport wraps an http.RoundTripper in client spans and duration metrics
+type transport struct {
+	base            http.RoundTripper
+	instrumentation *Instrumentation
+}
+
+// WrapHTTPClient returns a copy of client, or of http.DefaultClient when client is nil, whose
+// requests are traced as client spans and measured. Slack API calls are named after their
+// method; other requests, such as response_url posts, only record the host since their URLs
+// carry secrets.
+func (i *Instrumentation) WrapHTTPClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	wrapped := *client
+	wrapped.Transport = i.WrapTransport(client.Transport)
+	return &wrapped
+}
+
+// WrapTransport wraps base, or http.DefaultTransport when base is nil, like WrapHTTPClient
+func (i *Instrumentation) WrapTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, instrumentation: i}
+}
+
+// RoundTrip sends req inside a client span
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := time.Now()
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.ServerAddress(req.URL.Hostname()),
+	}
+	name := "HTTP " + req.Method
+	if method, ok := strings.CutPrefix(req.URL.Path, slackAPIPathPrefix); ok && method != "" {
+		name = "slack.api " + method
+		attrs = append(attrs, APIMethodKey.String(method))
+	}
+
+	ctx, span := t.instrumentation.tracer.Start(req.Context(), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	var result []attribute.KeyValue
+	if err != nil {
+		result = append(result, semconv.ErrorTypeKey.String("transport"))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		result = append(result, semconv.HTTPResponseStatusCode(resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			result = append(result, semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	span.SetAttributes(result...)
+	t.instrumentation.clientDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(append(attrs, result...)...))
+	return resp, err
+}
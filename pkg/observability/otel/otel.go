@@ -0,0 +1,203 @@
+// Package otel instruments bolt-go apps with OpenTelemetry traces and metrics.
+//
+// Pass an Instrumentation as AppOptions.Instrumentation to trace and measure requests received,
+// ProcessEvent, authorization, Slack API calls and response_url posts:
+//
+//	instrumentation, err := otel.New(otel.Options{})
+//	app, err := bolt.New(bolt.AppOptions{Token: token, SigningSecret: secret, Instrumentation: instrumentation})
+//
+// Following OpenTelemetry's guidance for libraries, the package only depends on the API and
+// records to the global tracer and meter providers unless others are given. Exporters, sampling
+// and resource attributes are configured by the SDK the application installs, which reads the
+// standard OTEL_* environment variables (OTEL_SERVICE_NAME, OTEL_EXPORTER_OTLP_ENDPOINT,
+// OTEL_TRACES_SAMPLER, ...). OTEL_SDK_DISABLED=true turns the instrumentation off.
+package otel
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter
+const ScopeName = "github.com/Asafrose/bolt-go/pkg/observability/otel"
+
+// Attribute keys describing Slack requests
+const (
+	// RequestTypeKey is the class of the incoming request, such as "event", "command" or "action"
+	RequestTypeKey = attribute.Key("slack.request.type")
+	// EventTypeKey is the Events API event type, such as "app_mention"
+	EventTypeKey = attribute.Key("slack.event.type")
+	// TeamIDKey is the workspace the request belongs to
+	TeamIDKey = attribute.Key("slack.team.id")
+	// EnterpriseIDKey is the Enterprise Grid organization the request belongs to
+	EnterpriseIDKey = attribute.Key("slack.enterprise.id")
+	// HandledKey reports whether any middleware or listener was registered for the request
+	HandledKey = attribute.Key("slack.handled")
+	// RetryNumKey is the delivery attempt Slack reported for the request
+	RetryNumKey = attribute.Key("slack.retry.num")
+	// ReceiverKey is the Go type of the receiver that accepted the request
+	ReceiverKey = attribute.Key("slack.receiver")
+	// CorrelationIDKey is the correlation ID bolt-go assigned to the event
+	CorrelationIDKey = attribute.Key("slack.correlation.id")
+	// APIMethodKey is the Slack Web API method called, such as "chat.postMessage"
+	APIMethodKey = attribute.Key("slack.api.method")
+)
+
+// durationBuckets are the histogram bucket boundaries, in seconds, for all recorded durations
+var durationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Options configures the OpenTelemetry instrumentation
+type Options struct {
+	// TracerProvider creates the tracer (default the global provider)
+	TracerProvider trace.TracerProvider
+	// MeterProvider creates the meter (default the global provider)
+	MeterProvider metric.MeterProvider
+}
+
+// Instrumentation implements app.Instrumentation with OpenTelemetry spans and metrics
+type Instrumentation struct {
+	tracer trace.Tracer
+
+	requests          metric.Int64Counter
+	eventDuration     metric.Float64Histogram
+	ackDuration       metric.Float64Histogram
+	authorizeDuration metric.Float64Histogram
+	clientDuration    metric.Float64Histogram
+}
+
+var _ app.Instrumentation = (*Instrumentation)(nil)
+
+// New creates the instrumentation and its instruments
+func New(options Options) (*Instrumentation, error) {
+	tracerProvider := options.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otelapi.GetTracerProvider()
+	}
+	meterProvider := options.MeterProvider
+	if meterProvider == nil {
+		meterProvider = otelapi.GetMeterProvider()
+	}
+	if sdkDisabled() {
+		tracerProvider = tracenoop.NewTracerProvider()
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+
+	meter := meterProvider.Meter(ScopeName)
+	i := &Instrumentation{tracer: tracerProvider.Tracer(ScopeName)}
+
+	var err error
+	if i.requests, err = meter.Int64Counter("bolt.receiver.requests",
+		metric.WithDescription("Requests from Slack accepted by a receiver"),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	if i.eventDuration, err = newDurationHistogram(meter, "bolt.event.duration", "Duration of ProcessEvent"); err != nil {
+		return nil, err
+	}
+	if i.ackDuration, err = newDurationHistogram(meter, "bolt.ack.duration", "Time from receiving a request to acknowledging it"); err != nil {
+		return nil, err
+	}
+	if i.authorizeDuration, err = newDurationHistogram(meter, "bolt.authorize.duration", "Duration of the authorize function"); err != nil {
+		return nil, err
+	}
+	if i.clientDuration, err = newDurationHistogram(meter, "bolt.http.client.duration", "Duration of Slack API calls and response_url posts"); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// newDurationHistogram creates a histogram of durations in seconds
+func newDurationHistogram(meter metric.Meter, name, description string) (metric.Float64Histogram, error) {
+	return meter.Float64Histogram(name,
+		metric.WithDescription(description),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...))
+}
+
+// sdkDisabled reports whether OTEL_SDK_DISABLED turns telemetry off
+func sdkDisabled() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("OTEL_SDK_DISABLED")), "true")
+}
+
+// StartProcessEvent starts the slack.process_event span
+func (i *Instrumentation) StartProcessEvent(ctx context.Context, event types.ReceiverEvent) (context.Context, func(info app.ProcessEventInfo, err error)) {
+	started := time.Now()
+	ctx, span := i.tracer.Start(ctx, "slack.process_event", trace.WithAttributes(
+		RetryNumKey.Int(event.RetryNum),
+		CorrelationIDKey.String(app.CorrelationIDFromContext(ctx))))
+
+	return ctx, func(info app.ProcessEventInfo, err error) {
+		attrs := make([]attribute.KeyValue, 0, 5)
+		if info.Type != "" {
+			attrs = append(attrs, RequestTypeKey.String(info.Type))
+		}
+		if info.EventType != "" {
+			attrs = append(attrs, EventTypeKey.String(info.EventType))
+		}
+		attrs = append(attrs, HandledKey.Bool(info.Handled))
+		if err != nil {
+			attrs = append(attrs, errorType(err))
+		}
+		i.eventDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(attrs...))
+
+		// Team IDs are unbounded, so they go on the span but not the metric
+		if info.TeamID != "" {
+			attrs = append(attrs, TeamIDKey.String(info.TeamID))
+		}
+		if info.EnterpriseID != "" {
+			attrs = append(attrs, EnterpriseIDKey.String(info.EnterpriseID))
+		}
+		span.SetAttributes(attrs...)
+		endSpan(span, err)
+	}
+}
+
+// StartAuthorize starts the slack.authorize span
+func (i *Instrumentation) StartAuthorize(ctx context.Context, source app.AuthorizeSourceData) (context.Context, func(err error)) {
+	started := time.Now()
+	ctx, span := i.tracer.Start(ctx, "slack.authorize")
+	if source.TeamID != "" {
+		span.SetAttributes(TeamIDKey.String(source.TeamID))
+	}
+	if source.EnterpriseID != "" {
+		span.SetAttributes(EnterpriseIDKey.String(source.EnterpriseID))
+	}
+
+	return ctx, func(err error) {
+		var attrs []attribute.KeyValue
+		if err != nil {
+			attrs = append(attrs, errorType(err))
+		}
+		i.authorizeDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(attrs...))
+		span.SetAttributes(attrs...)
+		endSpan(span, err)
+	}
+}
+
+// errorType describes err by its bolt error code
+func errorType(err error) attribute.KeyValue {
+	return semconv.ErrorTypeKey.String(string(bolterrors.AsCodedError(err).Code()))
+}
+
+// endSpan records err on span, if any, and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
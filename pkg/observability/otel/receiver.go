@@ -0,0 +1,69 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// receiver wraps a receiver so every request it hands to the app is traced and counted
+type receiver struct {
+	types.Receiver
+	instrumentation *Instrumentation
+	name            attribute.KeyValue
+}
+
+// instrumentedApp is the app as seen by a wrapped receiver
+type instrumentedApp struct {
+	app      types.App
+	receiver *receiver
+}
+
+// WrapReceiver wraps receiver in a slack.receive server span per request, counting requests and
+// recording the time until each request is acknowledged
+func (i *Instrumentation) WrapReceiver(r types.Receiver) types.Receiver {
+	if r == nil {
+		return nil
+	}
+	return &receiver{
+		Receiver:        r,
+		instrumentation: i,
+		name:            ReceiverKey.String(fmt.Sprintf("%T", r)),
+	}
+}
+
+// Init initializes the wrapped receiver with the instrumented app
+func (r *receiver) Init(app types.App) error {
+	return r.Receiver.Init(&instrumentedApp{app: app, receiver: r})
+}
+
+// ProcessEvent traces event and passes it on to the app
+func (a *instrumentedApp) ProcessEvent(ctx context.Context, event types.ReceiverEvent) error {
+	i := a.receiver.instrumentation
+	started := time.Now()
+	ctx, span := i.tracer.Start(ctx, "slack.receive",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(a.receiver.name, RetryNumKey.Int(event.RetryNum)))
+	i.requests.Add(ctx, 1, metric.WithAttributes(a.receiver.name))
+
+	if ack := event.Ack; ack != nil {
+		var acked atomic.Bool
+		event.Ack = func(response types.AckResponse) error {
+			if acked.CompareAndSwap(false, true) {
+				i.ackDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(a.receiver.name))
+				span.AddEvent("ack")
+			}
+			return ack(response)
+		}
+	}
+
+	err := a.app.ProcessEvent(ctx, event)
+	endSpan(span, err)
+	return err
+}
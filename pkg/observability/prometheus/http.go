@@ -0,0 +1,94 @@
+package prometheus
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slackAPIPathPrefix is the path prefix of Slack Web API methods
+const slackAPIPathPrefix = "/api/"
+
+// transport wraps an http.RoundTripper counting and measuring Slack API calls
+type transport struct {
+	base            http.RoundTripper
+	instrumentation *Instrumentation
+}
+
+// WrapHTTPClient returns a copy of client, or of http.DefaultClient when client is nil, whose
+// Slack API calls are counted and measured. Other requests, such as response_url posts, are
+// sent as they are.
+func (i *Instrumentation) WrapHTTPClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	wrapped := *client
+	wrapped.Transport = i.WrapTransport(client.Transport)
+	return &wrapped
+}
+
+// WrapTransport wraps base, or http.DefaultTransport when base is nil, like WrapHTTPClient
+func (i *Instrumentation) WrapTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, instrumentation: i}
+}
+
+// RoundTrip sends req, recording it if it calls a Slack API method. Calls fail with "transport"
+// when no response arrived, the status code for HTTP errors, or the error Slack reported in the
+// body of a response with "ok": false, such as "channel_not_found".
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method, ok := strings.CutPrefix(req.URL.Path, slackAPIPathPrefix)
+	if !ok || method == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	i := t.instrumentation
+	started := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	i.apiCalls.WithLabelValues(method).Inc()
+	i.apiDuration.WithLabelValues(method).Observe(time.Since(started).Seconds())
+
+	switch {
+	case err != nil:
+		i.apiErrors.WithLabelValues(method, "transport").Inc()
+	case resp.StatusCode >= 400:
+		i.apiErrors.WithLabelValues(method, strconv.Itoa(resp.StatusCode)).Inc()
+	default:
+		if slackErr := readSlackError(resp); slackErr != "" {
+			i.apiErrors.WithLabelValues(method, slackErr).Inc()
+		}
+	}
+	return resp, err
+}
+
+// readSlackError returns the error of a JSON Web API response with "ok": false, leaving the body
+// readable for the caller
+func readSlackError(resp *http.Response) string {
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &result) != nil || result.OK {
+		return ""
+	}
+	if result.Error == "" {
+		return "unknown"
+	}
+	return result.Error
+}
@@ -0,0 +1,211 @@
+// Package prometheus exposes Prometheus metrics for bolt-go apps.
+//
+// Pass an Instrumentation as AppOptions.Instrumentation to count requests and measure event
+// processing, acknowledgement, authorization and Slack API calls, then mount its handler, e.g. as
+// a custom route of the HTTP receiver:
+//
+//	metrics, err := prometheus.New(prometheus.Options{})
+//	app, err := bolt.New(bolt.AppOptions{
+//		Token:           token,
+//		SigningSecret:   secret,
+//		Instrumentation: metrics,
+//		CustomRoutes:    []types.CustomRoute{metrics.Route("/metrics")},
+//	})
+//	metrics.ObserveListeners(app)
+//
+// Metrics are only labelled with bounded values, such as request types, registered listeners and
+// Slack API methods; team IDs are left out.
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// DefaultNamespace prefixes the metric names unless Options.Namespace is set
+const DefaultNamespace = "bolt"
+
+// durationBuckets are the histogram bucket boundaries, in seconds, for all recorded durations
+var durationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Options configures the Prometheus metrics
+type Options struct {
+	// Namespace prefixes the metric names (default "bolt")
+	Namespace string
+	// Registerer registers the metrics (default prometheus.DefaultRegisterer)
+	Registerer prometheus.Registerer
+	// Gatherer is served by Handler (default prometheus.DefaultGatherer)
+	Gatherer prometheus.Gatherer
+}
+
+// Instrumentation implements app.Instrumentation with Prometheus metrics
+type Instrumentation struct {
+	namespace  string
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+
+	requests          *prometheus.CounterVec
+	ackDuration       *prometheus.HistogramVec
+	events            *prometheus.CounterVec
+	eventDuration     *prometheus.HistogramVec
+	authorizeDuration *prometheus.HistogramVec
+	listenerDuration  *prometheus.HistogramVec
+	apiCalls          *prometheus.CounterVec
+	apiErrors         *prometheus.CounterVec
+	apiDuration       *prometheus.HistogramVec
+	socketConnected   prometheus.Gauge
+	socketReconnects  prometheus.Counter
+	socketDisconnects *prometheus.CounterVec
+}
+
+var _ app.Instrumentation = (*Instrumentation)(nil)
+
+// New creates the metrics and registers them
+func New(options Options) (*Instrumentation, error) {
+	namespace := options.Namespace
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	registerer := options.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	gatherer := options.Gatherer
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+
+	counter := func(name, help string, labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help}, labels)
+	}
+	histogram := func(name, help string, labels ...string) *prometheus.HistogramVec {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: namespace, Name: name, Help: help, Buckets: durationBuckets}, labels)
+	}
+
+	i := &Instrumentation{
+		namespace:         namespace,
+		registerer:        registerer,
+		gatherer:          gatherer,
+		requests:          counter("requests_total", "Requests from Slack accepted by a receiver", "receiver"),
+		ackDuration:       histogram("ack_duration_seconds", "Time from receiving a request to acknowledging it", "receiver"),
+		events:            counter("events_total", "Events processed, by request type, event type and outcome", "type", "event_type", "handled", "error"),
+		eventDuration:     histogram("event_duration_seconds", "Duration of ProcessEvent", "type", "event_type"),
+		authorizeDuration: histogram("authorize_duration_seconds", "Duration of the authorize function", "error"),
+		listenerDuration:  histogram("listener_duration_seconds", "Duration of listeners, by listener and outcome", "listener", "error"),
+		apiCalls:          counter("slack_api_calls_total", "Slack API calls", "method"),
+		apiErrors:         counter("slack_api_errors_total", "Slack API calls that failed, by method and error", "method", "error"),
+		apiDuration:       histogram("slack_api_duration_seconds", "Duration of Slack API calls", "method"),
+		socketConnected: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "socket_mode_connected", Help: "Whether the Socket Mode receiver is connected",
+		}),
+		socketReconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "socket_mode_reconnects_total", Help: "Socket Mode reconnections after a connection ended",
+		}),
+		socketDisconnects: counter("socket_mode_disconnects_total", "Socket Mode connections that ended, by the reason Slack gave", "reason"),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		i.requests, i.ackDuration, i.events, i.eventDuration, i.authorizeDuration, i.listenerDuration,
+		i.apiCalls, i.apiErrors, i.apiDuration, i.socketConnected, i.socketReconnects, i.socketDisconnects,
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+// Handler serves the metrics of the Gatherer in the Prometheus exposition format
+func (i *Instrumentation) Handler() http.Handler {
+	return promhttp.HandlerFor(i.gatherer, promhttp.HandlerOpts{})
+}
+
+// Route returns a receiver custom route serving Handler on path, such as "/metrics"
+func (i *Instrumentation) Route(path string) types.CustomRoute {
+	return types.CustomRoute{Path: path, Method: http.MethodGet, Handler: i.Handler().ServeHTTP}
+}
+
+// StartProcessEvent measures ProcessEvent and counts the event once it was processed
+func (i *Instrumentation) StartProcessEvent(ctx context.Context, event types.ReceiverEvent) (context.Context, func(info app.ProcessEventInfo, err error)) {
+	started := time.Now()
+	return ctx, func(info app.ProcessEventInfo, err error) {
+		i.eventDuration.WithLabelValues(info.Type, info.EventType).Observe(time.Since(started).Seconds())
+		handled := "false"
+		if info.Handled {
+			handled = "true"
+		}
+		i.events.WithLabelValues(info.Type, info.EventType, handled, errorCode(err)).Inc()
+	}
+}
+
+// StartAuthorize measures the authorize function
+func (i *Instrumentation) StartAuthorize(ctx context.Context, source app.AuthorizeSourceData) (context.Context, func(err error)) {
+	started := time.Now()
+	return ctx, func(err error) {
+		i.authorizeDuration.WithLabelValues(errorCode(err)).Observe(time.Since(started).Seconds())
+	}
+}
+
+// listenerStartedKey is the context key of the time a listener started
+type listenerStartedKey struct{}
+
+// ObserveListeners registers listener hooks with a measuring the duration and outcome of each
+// listener. Listeners are labelled by their description, such as "command(command=/deploy)".
+func (i *Instrumentation) ObserveListeners(a *app.App) {
+	a.OnListenerStart(func(ctx context.Context, listener string) context.Context {
+		return context.WithValue(ctx, listenerStartedKey{}, time.Now())
+	})
+	a.OnListenerEnd(func(ctx context.Context, listener string, err error) {
+		started, ok := ctx.Value(listenerStartedKey{}).(time.Time)
+		if !ok {
+			return
+		}
+		i.listenerDuration.WithLabelValues(listener, errorCode(err)).Observe(time.Since(started).Seconds())
+	})
+}
+
+// ObserveConcurrency registers gauges of the events a processes and queues under
+// AppOptions.MaxConcurrency, and counters of the events its overflow policy dropped or rejected
+func (i *Instrumentation) ObserveConcurrency(a *app.App) error {
+	gauge := func(name, help string, value func(stats app.ConcurrencyStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Namespace: i.namespace, Name: name, Help: help}, func() float64 {
+			return value(a.ConcurrencyStats())
+		})
+	}
+	counter := func(name, help string, value func(stats app.ConcurrencyStats) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Namespace: i.namespace, Name: name, Help: help}, func() float64 {
+			return value(a.ConcurrencyStats())
+		})
+	}
+	for _, collector := range []prometheus.Collector{
+		gauge("events_in_flight", "Events holding one of the MaxConcurrency slots",
+			func(stats app.ConcurrencyStats) float64 { return float64(stats.InFlight) }),
+		gauge("events_queued", "Events waiting for one of the MaxConcurrency slots",
+			func(stats app.ConcurrencyStats) float64 { return float64(stats.Queued) }),
+		counter("events_dropped_total", "Events dropped by the concurrency overflow policy",
+			func(stats app.ConcurrencyStats) float64 { return float64(stats.Dropped) }),
+		counter("events_rejected_total", "Events rejected for lack of a concurrency slot",
+			func(stats app.ConcurrencyStats) float64 { return float64(stats.Rejected) }),
+	} {
+		if err := i.registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorCode describes err by its bolt error code, or "" for no error
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	return string(bolterrors.AsCodedError(err).Code())
+}
@@ -0,0 +1,84 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// receiver wraps a receiver so every request it hands to the app is counted
+type receiver struct {
+	types.Receiver
+	instrumentation *Instrumentation
+	name            string
+}
+
+// instrumentedApp is the app as seen by a wrapped receiver
+type instrumentedApp struct {
+	app      types.App
+	receiver *receiver
+}
+
+// WrapReceiver wraps receiver so requests are counted and the time until each request is
+// acknowledged is recorded
+func (i *Instrumentation) WrapReceiver(r types.Receiver) types.Receiver {
+	if r == nil {
+		return nil
+	}
+	return &receiver{
+		Receiver:        r,
+		instrumentation: i,
+		name:            fmt.Sprintf("%T", r),
+	}
+}
+
+// Init initializes the wrapped receiver with the instrumented app
+func (r *receiver) Init(app types.App) error {
+	return r.Receiver.Init(&instrumentedApp{app: app, receiver: r})
+}
+
+// ProcessEvent counts event and passes it on to the app
+func (a *instrumentedApp) ProcessEvent(ctx context.Context, event types.ReceiverEvent) error {
+	i := a.receiver.instrumentation
+	started := time.Now()
+	i.requests.WithLabelValues(a.receiver.name).Inc()
+
+	if ack := event.Ack; ack != nil {
+		var acked atomic.Bool
+		event.Ack = func(response types.AckResponse) error {
+			if acked.CompareAndSwap(false, true) {
+				i.ackDuration.WithLabelValues(a.receiver.name).Observe(time.Since(started).Seconds())
+			}
+			return ack(response)
+		}
+	}
+	return a.app.ProcessEvent(ctx, event)
+}
+
+// InstrumentSocketMode adds hooks to options recording the connection state, reconnections and
+// disconnections of a Socket Mode receiver created with them. Hooks already set still run.
+func (i *Instrumentation) InstrumentSocketMode(options *types.SocketModeReceiverOptions) {
+	onConnected, onDisconnect, onReconnect := options.OnConnected, options.OnDisconnect, options.OnReconnect
+	options.OnConnected = func(hello types.SocketModeHello) {
+		i.socketConnected.Set(1)
+		if onConnected != nil {
+			onConnected(hello)
+		}
+	}
+	options.OnDisconnect = func(reason string) {
+		i.socketConnected.Set(0)
+		i.socketDisconnects.WithLabelValues(reason).Inc()
+		if onDisconnect != nil {
+			onDisconnect(reason)
+		}
+	}
+	options.OnReconnect = func() {
+		i.socketReconnects.Inc()
+		if onReconnect != nil {
+			onReconnect()
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Package options builds responses for Options listeners serving external selects, keeping them
+// within the limits Slack enforces on option lists
+package options
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// Limits Slack enforces on the options of external selects
+const (
+	MaxOptions      = 100
+	MaxOptionGroups = 100
+	MaxTextLength   = 75
+	MaxValueLength  = 150
+)
+
+// Option returns an option with text and value. text is escaped as plain text and truncated to
+// MaxTextLength characters with an ellipsis, and value is truncated to MaxValueLength.
+func Option(text, value string) types.Option {
+	return types.Option{
+		Text:  plainText(text),
+		Value: truncateValue(value),
+	}
+}
+
+// OptionWithDescription returns an option as Option does, with a description shown below its text
+func OptionWithDescription(text, value, description string) types.Option {
+	option := Option(text, value)
+	option.Description = plainText(description)
+	return option
+}
+
+// Group returns an option group labeled with label, escaped and truncated as option texts are.
+// Options past MaxOptions are dropped.
+func Group(label string, options ...types.Option) types.OptionGroup {
+	options = options[:min(len(options), MaxOptions)]
+	group := types.OptionGroup{
+		Label:   plainText(label),
+		Options: make([]*slack.OptionBlockObject, len(options)),
+	}
+	for i, option := range options {
+		group.Options[i] = &option
+	}
+	return group
+}
+
+// Response returns the response listing options, dropping those past MaxOptions
+func Response(options ...types.Option) types.OptionsResponse {
+	return types.OptionsResponse{Options: options[:min(len(options), MaxOptions)]}
+}
+
+// GroupedResponse returns the response listing groups. Empty groups are dropped, as Slack
+// rejects them, and so are groups past MaxOptionGroups.
+func GroupedResponse(groups ...types.OptionGroup) types.OptionsResponse {
+	result := make([]types.OptionGroup, 0, len(groups))
+	for _, group := range groups {
+		if len(group.Options) == 0 {
+			continue
+		}
+		group.Options = group.Options[:min(len(group.Options), MaxOptions)]
+		result = append(result, group)
+		if len(result) == MaxOptionGroups {
+			break
+		}
+	}
+	return types.OptionsResponse{OptionGroups: result}
+}
+
+// Filter returns the options whose text matches query, the value typed in the select, best
+// matches first: texts starting with query, then texts with a word starting with it, then texts
+// containing it, then texts containing its characters in order. Matching ignores case, and an
+// empty query matches every option.
+func Filter(query string, options []types.Option) []types.Option {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return options
+	}
+
+	type match struct {
+		option types.Option
+		rank   int
+	}
+	var matches []match
+	for _, option := range options {
+		if rank, ok := matchRank(query, strings.ToLower(unescape(option.Text))); ok {
+			matches = append(matches, match{option, rank})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].rank < matches[j].rank })
+
+	result := make([]types.Option, len(matches))
+	for i, m := range matches {
+		result[i] = m.option
+	}
+	return result
+}
+
+// FilterGroups filters the options of each group as Filter does, dropping groups left empty
+func FilterGroups(query string, groups []types.OptionGroup) []types.OptionGroup {
+	result := make([]types.OptionGroup, 0, len(groups))
+	for _, group := range groups {
+		options := make([]types.Option, 0, len(group.Options))
+		for _, option := range group.Options {
+			if option != nil {
+				options = append(options, *option)
+			}
+		}
+		if filtered := Filter(query, options); len(filtered) > 0 {
+			result = append(result, Group(unescape(group.Label), filtered...))
+		}
+	}
+	return result
+}
+
+// matchRank ranks how well text matches query, lower being better
+func matchRank(query, text string) (int, bool) {
+	switch {
+	case strings.HasPrefix(text, query):
+		return 0, true
+	case strings.Contains(text, " "+query):
+		return 1, true
+	case strings.Contains(text, query):
+		return 2, true
+	}
+
+	// Fall back to the characters of query appearing in order
+	remaining := query
+	for _, r := range text {
+		next, size := utf8.DecodeRuneInString(remaining)
+		if r == next {
+			remaining = remaining[size:]
+			if remaining == "" {
+				return 3, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// plainText returns text as a plain_text object, escaped and truncated to MaxTextLength
+func plainText(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.PlainTextType, escape(truncate(text, MaxTextLength)), false, false)
+}
+
+var (
+	escaper   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	unescaper = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">")
+)
+
+// escape escapes the characters Slack reserves for control sequences
+func escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// unescape reverses escape for text taken from an object built by this package
+func unescape(text *slack.TextBlockObject) string {
+	if text == nil {
+		return ""
+	}
+	return unescaper.Replace(text.Text)
+}
+
+// truncate shortens s to limit characters, ending it with an ellipsis when it was cut
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit-1]) + "…"
+}
+
+// truncateValue cuts value to MaxValueLength characters. Values are returned to the app as they
+// are, so unlike texts they do not get an ellipsis.
+func truncateValue(value string) string {
+	if utf8.RuneCountInString(value) <= MaxValueLength {
+		return value
+	}
+	return string([]rune(value)[:MaxValueLength])
+}
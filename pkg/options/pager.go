@@ -0,0 +1,134 @@
+package options
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// loadMorePrefix starts the values of the options loading the next page
+const loadMorePrefix = "bolt_load_more:"
+
+// DefaultPageSize is the number of options of a page when Pager.PageSize is unset
+const DefaultPageSize = 50
+
+// Fetch returns up to limit options of a data source matching query, starting at cursor, "" for
+// the first page, and the cursor of the next page, "" after the last one
+type Fetch func(ctx context.Context, query, cursor string, limit int) (options []types.Option, next string, err error)
+
+// Pager serves the options of an external select from a data source too large to list at once. A
+// page ends with a "load more" option carrying the cursor of the next page, and once it is
+// selected, the next options request of the select serves that page. Selections are read from the
+// state of the view the select is in, so pages past the first are only served in modals and App
+// Home; selects in messages always get the first page.
+type Pager struct {
+	Fetch Fetch
+	// PageSize is the number of options of a page, at most MaxOptions-1 to leave room for the load
+	// more option. Defaults to DefaultPageSize.
+	PageSize int
+	// LoadMoreText is the text of the option loading the next page. Defaults to "Load more…".
+	LoadMoreText string
+}
+
+// loadMore is the state carried by the value of a load more option
+type loadMore struct {
+	Cursor string `json:"c"`
+	Query  string `json:"q,omitempty"`
+}
+
+// Page returns the page of options the options request args were built for asks for: the page
+// after the load more option selected in the select, if the query did not change since, or else
+// the first page
+func (p Pager) Page(args types.SlackOptionsMiddlewareArgs) (types.OptionsResponse, error) {
+	query := args.Options.Value
+	var cursor string
+	if state, ok := selectedLoadMore(args); ok && state.Query == query {
+		cursor = state.Cursor
+	}
+
+	limit := p.PageSize
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxOptions-1)
+
+	page, next, err := p.Fetch(args.API().Context(), query, cursor, limit)
+	if err != nil {
+		return types.OptionsResponse{}, err
+	}
+	page = page[:min(len(page), limit)]
+
+	if next != "" {
+		option, err := p.loadMoreOption(loadMore{Cursor: next, Query: query})
+		if err != nil {
+			return types.OptionsResponse{}, err
+		}
+		page = append(page, option)
+	}
+	return Response(page...), nil
+}
+
+// Respond acknowledges the options request args were built for with Page. When fetching the
+// page fails, it acknowledges with no options, so the select does not wait for a timeout, and
+// returns the error.
+func (p Pager) Respond(args types.SlackOptionsMiddlewareArgs) error {
+	response, err := p.Page(args)
+	if ackErr := args.Ack(&response); ackErr != nil {
+		return ackErr
+	}
+	return err
+}
+
+// IsLoadMore reports whether value is the value of a load more option of a Pager, which action
+// and view submission listeners should not treat as a choice
+func IsLoadMore(value string) bool {
+	return strings.HasPrefix(value, loadMorePrefix)
+}
+
+// loadMoreOption returns the option loading the page state points to
+func (p Pager) loadMoreOption(state loadMore) (types.Option, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return types.Option{}, err
+	}
+	value := loadMorePrefix + base64.RawURLEncoding.EncodeToString(data)
+	if len(value) > MaxValueLength {
+		return types.Option{}, fmt.Errorf("options: the cursor and query of the next page take %d characters encoded, more than the %d an option value holds", len(value), MaxValueLength)
+	}
+
+	text := p.LoadMoreText
+	if text == "" {
+		text = "Load more…"
+	}
+	return Option(text, value), nil
+}
+
+// selectedLoadMore returns the state of the load more option selected in the select the options
+// request args were built for asks options for, if one is
+func selectedLoadMore(args types.SlackOptionsMiddlewareArgs) (loadMore, bool) {
+	body, _ := args.Body.(map[string]interface{})
+	view, _ := body["view"].(map[string]interface{})
+	state, _ := view["state"].(map[string]interface{})
+	values, _ := state["values"].(map[string]interface{})
+	block, _ := values[args.Options.BlockID].(map[string]interface{})
+	element, _ := block[args.Options.ActionID].(map[string]interface{})
+	selected, _ := element["selected_option"].(map[string]interface{})
+	value, _ := selected["value"].(string)
+	if !IsLoadMore(value) {
+		return loadMore{}, false
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, loadMorePrefix))
+	if err != nil {
+		return loadMore{}, false
+	}
+	var result loadMore
+	if err := json.Unmarshal(data, &result); err != nil {
+		return loadMore{}, false
+	}
+	return result, true
+}
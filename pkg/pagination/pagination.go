@@ -0,0 +1,139 @@
+// Package pagination iterates over the pages of cursor-paginated Slack Web API methods with
+// range-over-func loops, following the cursors and waiting out rate limits:
+//
+//	for page, err := range args.Pages().ConversationsHistoryIter(ctx, params) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// An iterator yields each page with a nil error, or a single error after which it stops.
+package pagination
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// defaultMaxRetries is how many times a rate limited page is retried by default
+const defaultMaxRetries = 5
+
+// Options configures the iterators of a Client
+type Options struct {
+	// MaxRetries is how many times a rate limited page is requested again after the delay Slack
+	// asks for (default 5); a negative value returns rate limit errors right away
+	MaxRetries int
+}
+
+// Client provides iterators over the list methods of a Slack client
+type Client struct {
+	client     *slack.Client
+	maxRetries int
+}
+
+// New returns the iterators of client
+func New(client *slack.Client, options ...Options) *Client {
+	c := &Client{client: client, maxRetries: defaultMaxRetries}
+	if len(options) > 0 && options[0].MaxRetries != 0 {
+		c.maxRetries = max(options[0].MaxRetries, 0)
+	}
+	return c
+}
+
+// ConversationsHistoryIter iterates over the pages of conversations.history, starting at
+// params.Cursor
+func (c *Client) ConversationsHistoryIter(ctx context.Context, params slack.GetConversationHistoryParameters) iter.Seq2[*slack.GetConversationHistoryResponse, error] {
+	return func(yield func(*slack.GetConversationHistoryResponse, error) bool) {
+		for {
+			page, err := retry(ctx, c.maxRetries, func() (*slack.GetConversationHistoryResponse, error) {
+				return c.client.GetConversationHistoryContext(ctx, &params)
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+			if !page.HasMore || page.ResponseMetaData.NextCursor == "" {
+				return
+			}
+			params.Cursor = page.ResponseMetaData.NextCursor
+		}
+	}
+}
+
+// ConversationsListIter iterates over the pages of conversations.list, starting at params.Cursor
+func (c *Client) ConversationsListIter(ctx context.Context, params slack.GetConversationsParameters) iter.Seq2[[]slack.Channel, error] {
+	type page struct {
+		channels   []slack.Channel
+		nextCursor string
+	}
+	return func(yield func([]slack.Channel, error) bool) {
+		for {
+			current, err := retry(ctx, c.maxRetries, func() (page, error) {
+				channels, nextCursor, err := c.client.GetConversationsContext(ctx, &params)
+				return page{channels: channels, nextCursor: nextCursor}, err
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(current.channels, nil) {
+				return
+			}
+			if current.nextCursor == "" {
+				return
+			}
+			params.Cursor = current.nextCursor
+		}
+	}
+}
+
+// UsersListIter iterates over the pages of users.list
+func (c *Client) UsersListIter(ctx context.Context, options ...slack.GetUsersOption) iter.Seq2[[]slack.User, error] {
+	return func(yield func([]slack.User, error) bool) {
+		pages := c.client.GetUsersPaginated(options...)
+		for {
+			next, err := retry(ctx, c.maxRetries, func() (slack.UserPagination, error) {
+				return pages.Next(ctx)
+			})
+			if pages.Done(err) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(next.Users, nil) {
+				return
+			}
+			pages = next
+		}
+	}
+}
+
+// retry calls call until it is not rate limited, waiting the delay Slack asks for between
+// attempts, at most maxRetries times
+func retry[T any](ctx context.Context, maxRetries int, call func() (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := call()
+		var rateLimited *slack.RateLimitedError
+		if !errors.As(err, &rateLimited) || attempt >= maxRetries {
+			return result, err
+		}
+
+		timer := time.NewTimer(rateLimited.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
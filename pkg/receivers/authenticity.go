@@ -0,0 +1,12 @@
+package receivers
+
+import (
+	"context"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// DefaultAuthenticityErrorHandler logs requests that failed signature verification
+func DefaultAuthenticityErrorHandler(ctx context.Context, args types.ReceiverAuthenticityErrorHandlerArgs) {
+	args.Logger.Error("Signature verification failed", "error", args.Error)
+}
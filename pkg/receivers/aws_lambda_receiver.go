@@ -2,21 +2,18 @@ package receivers
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"log/slog"
+	"net/http"
 	"net/url"
 	"os"
-	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	boltErrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
 	"github.com/Asafrose/bolt-go/pkg/types"
 )
 
@@ -64,11 +61,15 @@ type AwsHandler func(event AwsEvent, context interface{}, callback AwsCallback)
 // AwsLambdaReceiver handles AWS Lambda requests from Slack
 type AwsLambdaReceiver struct {
 	signingSecret                 string
-	logger                        *slog.Logger
+	signatureVerifier             *helpers.RotatingSignatureVerifier
+	signatureTolerance            time.Duration
+	logger                        types.Logger
 	processBeforeResponse         bool
 	signatureVerification         bool
 	unhandledRequestTimeoutMillis int
 	customProperties              map[string]interface{}
+	authenticityErrorHandler      types.ReceiverAuthenticityErrorHandler
+	capture                       types.EventCapturer
 
 	app types.App
 }
@@ -83,10 +84,21 @@ func NewAwsLambdaReceiver(options types.AwsLambdaReceiverOptions) *AwsLambdaRece
 
 	receiver := &AwsLambdaReceiver{
 		signingSecret:                 options.SigningSecret,
+		signatureVerifier:             helpers.NewRotatingSignatureVerifier(options.SigningSecret),
+		signatureTolerance:            defaultSignatureTolerance,
 		processBeforeResponse:         options.ProcessBeforeResponse,
 		unhandledRequestTimeoutMillis: 3001, // default
 		signatureVerification:         signatureVerification,
 		customProperties:              options.CustomProperties,
+		authenticityErrorHandler:      options.AuthenticityErrorHandler,
+		capture:                       options.Capture,
+	}
+
+	if receiver.authenticityErrorHandler == nil {
+		receiver.authenticityErrorHandler = DefaultAuthenticityErrorHandler
+	}
+	if options.SignatureTolerance > 0 {
+		receiver.signatureTolerance = options.SignatureTolerance
 	}
 
 	if options.Logger != nil {
@@ -111,6 +123,13 @@ func (r *AwsLambdaReceiver) Init(app types.App) error {
 	return nil
 }
 
+// UpdateCredentials rotates the signing secret requests are verified with
+func (r *AwsLambdaReceiver) UpdateCredentials(update types.CredentialUpdate) {
+	if update.SigningSecret != "" {
+		r.signatureVerifier.Rotate(update.SigningSecret, update.SigningSecretWindow)
+	}
+}
+
 // Start starts the receiver (no-op for Lambda)
 func (r *AwsLambdaReceiver) Start(ctx context.Context) error {
 	if r.app == nil {
@@ -179,15 +198,23 @@ func (r *AwsLambdaReceiver) HandleLambdaEvent(ctx context.Context, event APIGate
 		bodyBytes = []byte(event.Body)
 	}
 
-	// Handle URL verification
-	if r.isURLVerification(bodyBytes) {
-		return r.handleURLVerification(bodyBytes)
+	// Answer Slack's endpoint checks before verifying and processing the request
+	switch check, challenge := detectEndpointCheck(bodyBytes); check {
+	case endpointCheckURLVerification:
+		return r.handleURLVerification(challenge)
+	case endpointCheckSSL:
+		return r.createSuccessResponse(), nil
 	}
 
 	// Verify signature if enabled
 	if r.signatureVerification {
 		if err := r.verifySignature(event.Headers, bodyBytes); err != nil {
-			r.logger.Error("Signature verification failed", "error", err)
+			r.authenticityErrorHandler(ctx, types.ReceiverAuthenticityErrorHandlerArgs{
+				Error:   err,
+				Logger:  r.logger,
+				Body:    bodyBytes,
+				Headers: event.Headers,
+			})
 			return r.createErrorResponse(401, "Unauthorized"), nil
 		}
 	}
@@ -205,21 +232,29 @@ func (r *AwsLambdaReceiver) HandleLambdaEvent(ctx context.Context, event APIGate
 	}
 
 	// Create receiver event
+	retryNum, retryReason := retryMetadata(headers)
 	receiverEvent := types.ReceiverEvent{
-		Body:    bodyBytes,
-		Headers: headers,
+		Body:        bodyBytes,
+		Headers:     headers,
+		RetryNum:    retryNum,
+		RetryReason: retryReason,
 		Ack: func(response types.AckResponse) error {
 			// For Lambda, ack is handled by returning the response
 			return nil
 		},
 	}
 
+	captureEvent(ctx, r.capture, r.logger, receiverEvent)
+
 	// Process the event
 	if r.processBeforeResponse {
 		// Process synchronously
 		err := r.app.ProcessEvent(ctx, receiverEvent)
 		if err != nil {
-			r.logger.Error("Error processing event", "error", err)
+			r.logger.Error("Error processing event", append(boltErrors.LogAttrs(err), boltErrors.LogKeyRetryNum, receiverEvent.RetryNum)...)
+			if boltErrors.IsNonRetryable(err) {
+				return r.createSuccessResponse(), nil
+			}
 			return r.createErrorResponse(500, "Internal server error"), nil
 		}
 		return r.createSuccessResponse(), nil
@@ -228,104 +263,36 @@ func (r *AwsLambdaReceiver) HandleLambdaEvent(ctx context.Context, event APIGate
 		go func() {
 			err := r.app.ProcessEvent(context.Background(), receiverEvent)
 			if err != nil {
-				r.logger.Error("Error processing event asynchronously", "error", err)
+				r.logger.Error("Error processing event asynchronously", append(boltErrors.LogAttrs(err), boltErrors.LogKeyRetryNum, receiverEvent.RetryNum)...)
 			}
 		}()
 		return r.createSuccessResponse(), nil
 	}
 }
 
-// isURLVerification checks if the request is a URL verification request
-func (r *AwsLambdaReceiver) isURLVerification(body []byte) bool {
-	var parsed map[string]interface{}
-	if err := json.Unmarshal(body, &parsed); err != nil {
-		return false
-	}
-
-	eventType, exists := parsed["type"]
-	if !exists {
-		return false
-	}
-
-	return eventType == "url_verification"
-}
-
-// handleURLVerification handles URL verification requests
-func (r *AwsLambdaReceiver) handleURLVerification(body []byte) (APIGatewayProxyResponse, error) {
-	var parsed map[string]interface{}
-	if err := json.Unmarshal(body, &parsed); err != nil {
-		return r.createErrorResponse(400, "Invalid JSON"), nil
-	}
-
-	challenge, exists := parsed["challenge"]
-	if !exists {
+// handleURLVerification answers a URL verification challenge
+func (r *AwsLambdaReceiver) handleURLVerification(challenge string) (APIGatewayProxyResponse, error) {
+	if challenge == "" {
 		return r.createErrorResponse(400, "Missing challenge"), nil
 	}
 
-	challengeStr, ok := challenge.(string)
-	if !ok {
-		return r.createErrorResponse(400, "Invalid challenge type"), nil
-	}
-
 	return APIGatewayProxyResponse{
 		StatusCode: 200,
 		Headers: map[string]string{
 			"Content-Type": "text/plain",
 		},
-		Body: challengeStr,
+		Body: challenge,
 	}, nil
 }
 
-// verifySignature verifies the Slack request signature
+// verifySignature verifies the Slack request signature, rejecting timestamps further than the
+// signature tolerance from the current time
 func (r *AwsLambdaReceiver) verifySignature(headers map[string]string, body []byte) error {
-	// Get signature and timestamp from headers
-	signature := ""
-	timestamp := ""
-
+	header := make(http.Header, len(headers))
 	for k, v := range headers {
-		switch strings.ToLower(k) {
-		case "x-slack-signature":
-			signature = v
-		case "x-slack-request-timestamp":
-			timestamp = v
-		}
-	}
-
-	if signature == "" || timestamp == "" {
-		return errors.New("missing signature or timestamp headers")
-	}
-
-	// Parse timestamp
-	ts, err := strconv.ParseInt(timestamp, 10, 64)
-	if err != nil {
-		return errors.New("invalid timestamp format")
-	}
-
-	// Check if request is too old (5 minutes)
-	fiveMinutesAgo := time.Now().Unix() - 300
-	if ts < fiveMinutesAgo {
-		return errors.New("request timestamp too old")
-	}
-
-	// Parse signature
-	parts := strings.Split(signature, "=")
-	if len(parts) != 2 {
-		return errors.New("invalid signature format")
-	}
-	version := parts[0]
-	hash := parts[1]
-
-	// Create HMAC
-	h := hmac.New(sha256.New, []byte(r.signingSecret))
-	h.Write([]byte(fmt.Sprintf("%s:%s:%s", version, timestamp, body)))
-	expectedHash := hex.EncodeToString(h.Sum(nil))
-
-	// Compare hashes using constant time comparison
-	if !hmac.Equal([]byte(hash), []byte(expectedHash)) {
-		return errors.New("signature verification failed")
+		header.Set(k, v)
 	}
-
-	return nil
+	return verifySignatureHeaders(r.signatureVerifier, r.signatureTolerance, header, body)
 }
 
 // parseFormData parses form-encoded data and converts it to JSON
@@ -419,43 +386,35 @@ func (r *AwsLambdaReceiver) ToHandler() AwsHandler {
 
 		rawBody := r.getRawBody(awsEvent)
 
-		// Parse request body
-		body := r.parseRequestBody(rawBody, r.getHeaderValue(awsEvent.Headers, "Content-Type"))
-
-		// Handle SSL check (for Slash Commands)
-		if r.isSSLCheck(body) {
+		// Handle SSL check (for Slash Commands) before parsing the body
+		check, challenge := detectEndpointCheck([]byte(rawBody))
+		if check == endpointCheckSSL {
 			return AwsResponse{StatusCode: 200, Body: ""}, nil
 		}
 
 		// Handle signature verification
 		if r.signatureVerification {
-			signature := r.getHeaderValue(awsEvent.Headers, "X-Slack-Signature")
-			tsStr := r.getHeaderValue(awsEvent.Headers, "X-Slack-Request-Timestamp")
-			ts, err := strconv.ParseInt(tsStr, 10, 64)
-			if err != nil {
-				return AwsResponse{StatusCode: 401, Body: ""}, nil
-			}
-
-			if !r.isValidRequestSignature(rawBody, signature, ts) {
+			if err := r.verifySignature(awsEvent.Headers, []byte(rawBody)); err != nil {
+				r.reportAuthenticityError(err, rawBody, awsEvent.Headers)
 				return AwsResponse{StatusCode: 401, Body: ""}, nil
 			}
 		}
 
 		// Handle URL verification (Events API)
-		if r.isURLVerificationFromMap(body) {
-			if challenge, ok := body["challenge"].(string); ok {
-				response := map[string]string{"challenge": challenge}
-				responseBody, _ := json.Marshal(response)
-				return AwsResponse{
-					StatusCode: 200,
-					Headers:    map[string]string{"Content-Type": "application/json"},
-					Body:       string(responseBody),
-				}, nil
-			}
+		if check == endpointCheckURLVerification && challenge != "" {
+			responseBody, _ := json.Marshal(map[string]string{"challenge": challenge})
+			return AwsResponse{
+				StatusCode: 200,
+				Headers:    map[string]string{"Content-Type": "application/json"},
+				Body:       string(responseBody),
+			}, nil
 		}
 
+		// Parse request body
+		body := r.parseRequestBody(rawBody, r.getHeaderValue(awsEvent.Headers, "Content-Type"))
+
 		// Process the event through the app
-		isAcknowledged := false
+		var isAcknowledged atomic.Bool
 
 		// Convert the parsed body back to JSON bytes for the ReceiverEvent
 		bodyBytes, err := json.Marshal(body)
@@ -464,24 +423,31 @@ func (r *AwsLambdaReceiver) ToHandler() AwsHandler {
 			return AwsResponse{StatusCode: 500, Body: "Internal Server Error"}, nil
 		}
 
+		retryNum, retryReason := retryMetadata(awsEvent.Headers)
 		receiverEvent := types.ReceiverEvent{
-			Body:    bodyBytes,
-			Headers: awsEvent.Headers,
+			Body:        bodyBytes,
+			Headers:     awsEvent.Headers,
+			RetryNum:    retryNum,
+			RetryReason: retryReason,
 			Ack: func(response types.AckResponse) error {
-				isAcknowledged = true
+				isAcknowledged.Store(true)
 				return nil
 			},
 		}
 
 		// Process the event
 		ctx := context.Background()
+		captureEvent(ctx, r.capture, r.logger, receiverEvent)
 		if err := r.app.ProcessEvent(ctx, receiverEvent); err != nil {
-			r.logger.Error("Error processing event", "error", err)
+			r.logger.Error("Error processing event", append(boltErrors.LogAttrs(err), boltErrors.LogKeyRetryNum, receiverEvent.RetryNum)...)
+			if boltErrors.IsNonRetryable(err) {
+				return AwsResponse{StatusCode: 200, Body: ""}, nil
+			}
 			return AwsResponse{StatusCode: 500, Body: "Internal Server Error"}, nil
 		}
 
 		// Return appropriate response
-		if isAcknowledged {
+		if isAcknowledged.Load() {
 			return AwsResponse{StatusCode: 200, Body: ""}, nil
 		} else {
 			return AwsResponse{StatusCode: 404, Body: "Not Found"}, nil
@@ -489,6 +455,16 @@ func (r *AwsLambdaReceiver) ToHandler() AwsHandler {
 	}
 }
 
+// reportAuthenticityError passes a signature verification failure to the authenticity error handler
+func (r *AwsLambdaReceiver) reportAuthenticityError(err error, rawBody string, headers map[string]string) {
+	r.authenticityErrorHandler(context.Background(), types.ReceiverAuthenticityErrorHandlerArgs{
+		Error:   err,
+		Logger:  r.logger,
+		Body:    []byte(rawBody),
+		Headers: headers,
+	})
+}
+
 // getRawBody extracts the raw body from AWS event
 func (r *AwsLambdaReceiver) getRawBody(awsEvent AwsEvent) string {
 	if awsEvent.IsBase64Encoded {
@@ -547,41 +523,3 @@ func (r *AwsLambdaReceiver) getHeaderValue(headers map[string]string, key string
 	}
 	return ""
 }
-
-// isSSLCheck checks if this is an SSL check request
-func (r *AwsLambdaReceiver) isSSLCheck(body map[string]interface{}) bool {
-	if sslCheck, exists := body["ssl_check"]; exists {
-		return sslCheck != nil
-	}
-	return false
-}
-
-// isURLVerificationFromMap checks if this is a URL verification request from parsed body
-func (r *AwsLambdaReceiver) isURLVerificationFromMap(body map[string]interface{}) bool {
-	if eventType, exists := body["type"]; exists {
-		if typeStr, ok := eventType.(string); ok {
-			return typeStr == "url_verification"
-		}
-	}
-	return false
-}
-
-// isValidRequestSignature validates the request signature
-func (r *AwsLambdaReceiver) isValidRequestSignature(rawBody, signature string, timestamp int64) bool {
-	// Check if timestamp is too old (more than 5 minutes)
-	now := time.Now().Unix()
-	if now-timestamp > 300 {
-		return false
-	}
-
-	// Create the signature base string
-	baseString := fmt.Sprintf("v0:%d:%s", timestamp, rawBody)
-
-	// Calculate the expected signature
-	mac := hmac.New(sha256.New, []byte(r.signingSecret))
-	mac.Write([]byte(baseString))
-	expectedSignature := "v0=" + hex.EncodeToString(mac.Sum(nil))
-
-	// Compare signatures (constant time comparison)
-	return hmac.Equal([]byte(signature), []byte(expectedSignature))
-}
@@ -0,0 +1,17 @@
+package receivers
+
+import (
+	"context"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// captureEvent passes event to capturer, if any, logging instead of failing when it cannot
+func captureEvent(ctx context.Context, capturer types.EventCapturer, logger types.Logger, event types.ReceiverEvent) {
+	if capturer == nil {
+		return
+	}
+	if err := capturer.Capture(ctx, event); err != nil {
+		logger.Warn("Failed to capture event", "error", err)
+	}
+}
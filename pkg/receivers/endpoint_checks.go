@@ -0,0 +1,76 @@
+package receivers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+)
+
+// endpointCheck identifies the requests Slack sends to check a request URL, which receivers
+// answer directly without authorizing or running the app
+type endpointCheck int
+
+const (
+	endpointCheckNone endpointCheck = iota
+	// endpointCheckSSL is a form-encoded ssl_check=1 request sent for slash command URLs
+	endpointCheckSSL
+	// endpointCheckURLVerification is the Events API url_verification challenge
+	endpointCheckURLVerification
+)
+
+var (
+	sslCheckField         = []byte("ssl_check=")
+	urlVerificationMarker = []byte(`"url_verification"`)
+)
+
+// detectEndpointCheck reports whether body is an endpoint check and returns the challenge of a
+// url_verification request. Other requests cost a single scan of the body, so regular events
+// reach the app without an extra parse.
+func detectEndpointCheck(body []byte) (endpointCheck, string) {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if len(trimmed) == 0 {
+		return endpointCheckNone, ""
+	}
+
+	if trimmed[0] != '{' {
+		// Form-encoded bodies: commands, interactivity payloads and ssl_check
+		if !bytes.Contains(body, sslCheckField) {
+			return endpointCheckNone, ""
+		}
+		values, err := url.ParseQuery(string(body))
+		if err != nil || values.Get("ssl_check") == "" {
+			return endpointCheckNone, ""
+		}
+		return endpointCheckSSL, ""
+	}
+
+	if !bytes.Contains(trimmed, urlVerificationMarker) {
+		return endpointCheckNone, ""
+	}
+	var verification struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+	}
+	if err := json.Unmarshal(trimmed, &verification); err != nil || verification.Type != "url_verification" {
+		return endpointCheckNone, ""
+	}
+	return endpointCheckURLVerification, verification.Challenge
+}
+
+// HandleURLVerification answers the url_verification challenge Slack sends when the Request URL
+// of the Events API is set. When body is such a challenge, it returns the JSON response to send
+// with status 200 and true; custom receivers should answer it before processing the request, as
+// the built-in receivers do. It returns false for every other request, including challenges
+// without a challenge value. The challenge is read from the body, not the query string, where
+// Slack never sends it.
+func HandleURLVerification(body []byte) ([]byte, bool) {
+	check, challenge := detectEndpointCheck(body)
+	if check != endpointCheckURLVerification || challenge == "" {
+		return nil, false
+	}
+	response, err := json.Marshal(map[string]string{"challenge": challenge})
+	if err != nil {
+		return nil, false
+	}
+	return response, true
+}
@@ -2,20 +2,20 @@ package receivers
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
 	"github.com/Asafrose/bolt-go/pkg/oauth"
 	"github.com/Asafrose/bolt-go/pkg/types"
 )
@@ -23,20 +23,29 @@ import (
 // HTTPReceiver handles HTTP requests from Slack
 type HTTPReceiver struct {
 	signingSecret                 string
+	signatureVerifier             *helpers.RotatingSignatureVerifier
+	signatureTolerance            time.Duration
 	endpoints                     *types.ReceiverEndpoints
 	port                          int
 	customRoutes                  []types.CustomRoute
-	logger                        *slog.Logger
+	logger                        types.Logger
 	processBeforeResponse         bool
 	signatureVerification         bool
 	unhandledRequestTimeoutMillis int
 	customProperties              map[string]interface{}
+	authenticityErrorHandler      types.ReceiverAuthenticityErrorHandler
+	errorStatusCodes              types.HTTPErrorStatusCodes
+	capture                       types.EventCapturer
+	mirror                        *mirror
+	endpointMiddleware            map[string][]func(http.Handler) http.Handler
 
 	// OAuth support
 	installer              *oauth.InstallProvider
 	installPath            string
 	installRedirectURIPath string
 	stateVerification      bool
+	callbackOptions        *oauth.CallbackOptions
+	installURLOptions      oauth.InstallURLOptions
 
 	server *http.Server
 	app    types.App
@@ -46,6 +55,8 @@ type HTTPReceiver struct {
 func NewHTTPReceiver(options types.HTTPReceiverOptions) *HTTPReceiver {
 	receiver := &HTTPReceiver{
 		signingSecret:                 options.SigningSecret,
+		signatureVerifier:             helpers.NewRotatingSignatureVerifier(options.SigningSecret),
+		signatureTolerance:            defaultSignatureTolerance,
 		endpoints:                     options.Endpoints,
 		port:                          3000, // default port
 		customRoutes:                  options.CustomRoutes,
@@ -55,6 +66,23 @@ func NewHTTPReceiver(options types.HTTPReceiverOptions) *HTTPReceiver {
 		signatureVerification:         true, // default to true
 		customProperties:              options.CustomProperties,
 		stateVerification:             true, // default to true
+		authenticityErrorHandler:      options.AuthenticityErrorHandler,
+		capture:                       options.Capture,
+	}
+
+	if options.Port > 0 {
+		receiver.port = options.Port
+	}
+	if options.SignatureTolerance > 0 {
+		receiver.signatureTolerance = options.SignatureTolerance
+	}
+
+	if receiver.authenticityErrorHandler == nil {
+		receiver.authenticityErrorHandler = DefaultAuthenticityErrorHandler
+	}
+
+	if options.ErrorStatusCodes != nil {
+		receiver.errorStatusCodes = *options.ErrorStatusCodes
 	}
 
 	// Set default logger if none provided
@@ -68,6 +96,7 @@ func NewHTTPReceiver(options types.HTTPReceiverOptions) *HTTPReceiver {
 			receiver.logger = slog.Default()
 		}
 	}
+	receiver.mirror = newMirror(options.Mirror, receiver.logger)
 
 	// Initialize OAuth if configuration is provided
 	if options.ClientID != "" && options.ClientSecret != "" {
@@ -85,6 +114,7 @@ func NewHTTPReceiver(options types.HTTPReceiverOptions) *HTTPReceiver {
 
 		// Set installer options if provided
 		if options.InstallerOptions != nil {
+			installProviderOptions.StateStore = options.InstallerOptions.StateStore
 			installProviderOptions.StateVerification = options.InstallerOptions.StateVerification
 			installProviderOptions.LegacyStateVerification = options.InstallerOptions.LegacyStateVerification
 			installProviderOptions.StateCookieName = options.InstallerOptions.StateCookieName
@@ -92,6 +122,8 @@ func NewHTTPReceiver(options types.HTTPReceiverOptions) *HTTPReceiver {
 			installProviderOptions.AuthVersion = options.InstallerOptions.AuthVersion
 			installProviderOptions.DirectInstall = options.InstallerOptions.DirectInstall
 			installProviderOptions.AuthorizationURL = options.InstallerOptions.AuthorizationURL
+			installProviderOptions.RenderHtmlForInstallPath = options.InstallerOptions.RenderHtmlForInstallPath
+			installProviderOptions.RenderInstallPage = options.InstallerOptions.RenderInstallPage
 
 			// Set paths
 			receiver.installPath = options.InstallerOptions.InstallPath
@@ -111,6 +143,9 @@ func NewHTTPReceiver(options types.HTTPReceiverOptions) *HTTPReceiver {
 			receiver.installRedirectURIPath = "/slack/oauth_redirect"
 		}
 
+		receiver.callbackOptions = installerCallbackOptions(options.InstallerOptions, receiver.logger)
+		receiver.installURLOptions = installURLOptions(options.Scopes, options.RedirectURI, options.InstallerOptions)
+
 		// Create install provider
 		var err error
 		receiver.installer, err = oauth.NewInstallProvider(installProviderOptions)
@@ -126,24 +161,64 @@ func NewHTTPReceiver(options types.HTTPReceiverOptions) *HTTPReceiver {
 		receiver.unhandledRequestTimeoutMillis = 3001
 	}
 
-	if receiver.endpoints == nil {
-		receiver.endpoints = &types.ReceiverEndpoints{
-			Events:      "/slack/events",
-			Interactive: "/slack/events",
-			Commands:    "/slack/events",
-			Options:     "/slack/events",
+	endpoints := defaultEndpoints(options.Endpoints)
+	receiver.endpoints = &endpoints
+
+	receiver.endpointMiddleware = options.EndpointMiddleware
+	for path := range receiver.endpointMiddleware {
+		if !slices.Contains(endpoints.Paths(), path) {
+			receiver.logger.Warn("Endpoint middleware configured for a path that is not an endpoint", "path", path)
 		}
 	}
 
 	return receiver
 }
 
+// defaultEndpoints returns endpoints with empty paths defaulted to the events path, and that one
+// to /slack/events
+func defaultEndpoints(endpoints *types.ReceiverEndpoints) types.ReceiverEndpoints {
+	var result types.ReceiverEndpoints
+	if endpoints != nil {
+		result = *endpoints
+	}
+	if result.Events == "" {
+		result.Events = "/slack/events"
+	}
+	for _, path := range []*string{&result.Interactive, &result.Commands, &result.Options} {
+		if *path == "" {
+			*path = result.Events
+		}
+	}
+	return result
+}
+
 // Init initializes the receiver with the app
 func (r *HTTPReceiver) Init(app types.App) error {
 	r.app = app
 	return nil
 }
 
+// UpdateCredentials rotates the signing secret requests are verified with and the client secret
+// of the installer
+func (r *HTTPReceiver) UpdateCredentials(update types.CredentialUpdate) {
+	if update.SigningSecret != "" {
+		r.signatureVerifier.Rotate(update.SigningSecret, update.SigningSecretWindow)
+	}
+	if update.ClientSecret != "" && r.installer != nil {
+		r.installer.UpdateClientSecret(update.ClientSecret)
+	}
+}
+
+// Port returns the port the receiver listens on
+func (r *HTTPReceiver) Port() int {
+	return r.port
+}
+
+// Endpoints returns the paths Slack requests are served on
+func (r *HTTPReceiver) Endpoints() types.ReceiverEndpoints {
+	return *r.endpoints
+}
+
 // Start starts the HTTP server
 func (r *HTTPReceiver) Start(ctx context.Context) error {
 	// Check if context is already cancelled
@@ -152,27 +227,8 @@ func (r *HTTPReceiver) Start(ctx context.Context) error {
 	}
 
 	mux := http.NewServeMux()
-
-	// Add default endpoints (avoid duplicates)
-	registeredPaths := make(map[string]bool)
-
-	endpoints := []string{r.endpoints.Events, r.endpoints.Interactive, r.endpoints.Commands, r.endpoints.Options}
-	for _, endpoint := range endpoints {
-		if endpoint != "" && !registeredPaths[endpoint] {
-			mux.HandleFunc(endpoint, r.handleSlackEvent)
-			registeredPaths[endpoint] = true
-		}
-	}
-
-	// Add OAuth routes if installer is configured
-	if r.installer != nil {
-		mux.HandleFunc(r.installPath, r.handleInstallPath)
-		mux.HandleFunc(r.installRedirectURIPath, r.handleInstallRedirect)
-	}
-
-	// Add custom routes
-	for _, route := range r.customRoutes {
-		mux.HandleFunc(route.Path, route.Handler)
+	for path, handler := range r.routes(true) {
+		mux.HandleFunc(path, handler)
 	}
 
 	r.server = &http.Server{
@@ -197,12 +253,53 @@ func (r *HTTPReceiver) Start(ctx context.Context) error {
 	return err
 }
 
+// routes returns the handlers of the receiver by path; the Slack endpoints are left out when
+// withEndpoints is false
+func (r *HTTPReceiver) routes(withEndpoints bool) map[string]http.HandlerFunc {
+	routes := make(map[string]http.HandlerFunc)
+	if withEndpoints {
+		for _, endpoint := range r.endpoints.Paths() {
+			routes[endpoint] = r.endpointHandler(endpoint)
+		}
+	}
+
+	// Add OAuth routes if installer is configured
+	if r.installer != nil {
+		routes[r.installPath] = r.handleInstallPath
+		routes[r.installRedirectURIPath] = r.handleInstallRedirect
+	}
+
+	for _, route := range r.customRoutes {
+		routes[route.Path] = route.Handler
+	}
+	return routes
+}
+
+// endpointHandler returns the handler of the Slack endpoint served on path, wrapped in its
+// endpoint middleware
+func (r *HTTPReceiver) endpointHandler(path string) http.HandlerFunc {
+	middleware := r.endpointMiddleware[path]
+	if len(middleware) == 0 {
+		return r.handleSlackEvent
+	}
+	var handler http.Handler = http.HandlerFunc(r.handleSlackEvent)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler.ServeHTTP
+}
+
 // Stop stops the HTTP server
 func (r *HTTPReceiver) Stop(ctx context.Context) error {
 	if r.server == nil {
-		return nil
+		return r.mirror.close(ctx)
 	}
-	return r.server.Shutdown(ctx)
+	err := r.server.Shutdown(ctx)
+	// Send the requests mirrored before the server stopped
+	if mirrorErr := r.mirror.close(ctx); err == nil {
+		err = mirrorErr
+	}
+	return err
 }
 
 // handleSlackEvent handles incoming Slack events
@@ -214,44 +311,84 @@ func (r *HTTPReceiver) handleSlackEvent(w http.ResponseWriter, req *http.Request
 		return
 	}
 	defer req.Body.Close()
+	headers := requestHeaders(req)
 
 	// Verify the request signature if enabled
 	if r.signatureVerification {
 		if err := r.verifySlackRequest(req, body); err != nil {
-			http.Error(w, "Invalid request signature", http.StatusUnauthorized)
+			r.rejectSlackRequest(w, req, err, body, headers)
 			return
 		}
 	}
+	r.serveSlackRequest(w, req, body, headers)
+}
 
-	// Handle URL verification
-	if strings.Contains(string(body), `"type":"url_verification"`) {
-		r.handleURLVerification(w, body)
-		return
-	}
-
-	// Handle SSL check
-	if strings.Contains(string(body), `"ssl_check"`) {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	// Create receiver event
+// requestHeaders returns the first value of each header of req
+func requestHeaders(req *http.Request) map[string]string {
 	headers := make(map[string]string)
 	for key, values := range req.Header {
 		if len(values) > 0 {
 			headers[key] = values[0]
 		}
 	}
+	return headers
+}
+
+// retryMetadata returns the delivery attempt and retry reason Slack reports in the
+// X-Slack-Retry-Num and X-Slack-Retry-Reason headers, 0 and "" for first deliveries. Header names
+// are matched ignoring case.
+func retryMetadata(headers map[string]string) (int, string) {
+	var num int
+	var reason string
+	for name, value := range headers {
+		switch {
+		case strings.EqualFold(name, "X-Slack-Retry-Num"):
+			num, _ = strconv.Atoi(value)
+		case strings.EqualFold(name, "X-Slack-Retry-Reason"):
+			reason = value
+		}
+	}
+	return num, reason
+}
 
-	ackCalled := false
-	event := types.ReceiverEvent{
+// rejectSlackRequest answers a request that failed signature verification with err
+func (r *HTTPReceiver) rejectSlackRequest(w http.ResponseWriter, req *http.Request, err error, body []byte, headers map[string]string) {
+	r.authenticityErrorHandler(req.Context(), types.ReceiverAuthenticityErrorHandlerArgs{
+		Error:   err,
+		Logger:  r.logger,
 		Body:    body,
 		Headers: headers,
+	})
+	r.writeErrorStatus(w, err)
+}
+
+// serveSlackRequest processes a verified request from Slack
+func (r *HTTPReceiver) serveSlackRequest(w http.ResponseWriter, req *http.Request, body []byte, headers map[string]string) {
+	// Answer Slack's endpoint checks here, without authorizing or running the app
+	switch check, _ := detectEndpointCheck(body); check {
+	case endpointCheckURLVerification:
+		r.handleURLVerification(w, body)
+		return
+	case endpointCheckSSL:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	r.mirror.mirror(req, body)
+
+	// Create receiver event
+	// Listeners run concurrently under AppOptions.ListenerConcurrency may ack at the same time
+	var ackCalled atomic.Bool
+	retryNum, retryReason := retryMetadata(headers)
+	event := types.ReceiverEvent{
+		Body:        body,
+		Headers:     headers,
+		RetryNum:    retryNum,
+		RetryReason: retryReason,
 		Ack: func(response types.AckResponse) error {
-			if ackCalled {
+			if !ackCalled.CompareAndSwap(false, true) {
 				return errors.NewReceiverMultipleAckError()
 			}
-			ackCalled = true
 			// Handle response body based on type
 			if response == nil {
 				w.WriteHeader(http.StatusOK)
@@ -285,15 +422,17 @@ func (r *HTTPReceiver) handleSlackEvent(w http.ResponseWriter, req *http.Request
 
 	// Process the event
 	ctx := req.Context()
+	captureEvent(ctx, r.capture, r.logger, event)
 	if err := r.app.ProcessEvent(ctx, event); err != nil {
-		if !ackCalled {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		r.logger.Error("Failed to process event", append(errors.LogAttrs(err), errors.LogKeyRetryNum, event.RetryNum, "retryable", errors.IsRetryable(err))...)
+		if ackCalled.CompareAndSwap(false, true) {
+			r.writeErrorStatus(w, err)
 		}
 		return
 	}
 
 	// Auto-ack if not already acknowledged and processBeforeResponse is false
-	if !ackCalled && !r.processBeforeResponse {
+	if !ackCalled.Load() && !r.processBeforeResponse {
 		if err := event.Ack(nil); err != nil {
 			// Log error but don't fail the request
 			_ = err
@@ -301,66 +440,33 @@ func (r *HTTPReceiver) handleSlackEvent(w http.ResponseWriter, req *http.Request
 	}
 }
 
-// verifySlackRequest verifies the Slack request signature
-func (r *HTTPReceiver) verifySlackRequest(req *http.Request, body []byte) error {
-	timestamp := req.Header.Get("X-Slack-Request-Timestamp")
-	signature := req.Header.Get("X-Slack-Signature")
-
-	if timestamp == "" || signature == "" {
-		return errors.NewReceiverAuthenticityError("Missing required headers")
-	}
-
-	// Parse timestamp
-	ts, err := strconv.ParseInt(timestamp, 10, 64)
-	if err != nil {
-		return errors.NewReceiverAuthenticityError("Invalid timestamp")
-	}
-
-	// Check if request is too old (more than 5 minutes)
-	if time.Now().Unix()-ts > 300 {
-		return errors.NewReceiverAuthenticityError("Request timestamp too old")
-	}
-
-	// Create the signature base string
-	baseString := fmt.Sprintf("v0:%s:%s", timestamp, string(body))
-
-	// Create HMAC
-	mac := hmac.New(sha256.New, []byte(r.signingSecret))
-	mac.Write([]byte(baseString))
-	expectedSignature := "v0=" + hex.EncodeToString(mac.Sum(nil))
-
-	// Compare signatures
-	if !hmac.Equal([]byte(signature), []byte(expectedSignature)) {
-		return errors.NewReceiverAuthenticityError("Invalid signature")
+// writeErrorStatus responds with the status code configured for the class of err
+func (r *HTTPReceiver) writeErrorStatus(w http.ResponseWriter, err error) {
+	status := r.errorStatusCodes.StatusCode(err)
+	if status >= 200 && status < 300 {
+		// Acknowledge without a body so Slack does not treat it as a message
+		w.WriteHeader(status)
+		return
 	}
+	http.Error(w, http.StatusText(status), status)
+}
 
-	return nil
+// verifySlackRequest verifies the Slack request signature
+func (r *HTTPReceiver) verifySlackRequest(req *http.Request, body []byte) error {
+	return verifySignatureHeaders(r.signatureVerifier, r.signatureTolerance, req.Header, body)
 }
 
-// handleURLVerification handles Slack URL verification
+// handleURLVerification answers a Slack URL verification challenge
 func (r *HTTPReceiver) handleURLVerification(w http.ResponseWriter, body []byte) {
-	// Parse the challenge from the body
-	bodyStr := string(body)
-
-	// Simple JSON parsing for challenge
-	challengeStart := strings.Index(bodyStr, `"challenge":"`)
-	if challengeStart == -1 {
+	responseBody, ok := HandleURLVerification(body)
+	if !ok {
 		http.Error(w, "No challenge found", http.StatusBadRequest)
 		return
 	}
 
-	challengeStart += len(`"challenge":"`)
-	challengeEnd := strings.Index(bodyStr[challengeStart:], `"`)
-	if challengeEnd == -1 {
-		http.Error(w, "Invalid challenge format", http.StatusBadRequest)
-		return
-	}
-
-	challenge := bodyStr[challengeStart : challengeStart+challengeEnd]
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte(fmt.Sprintf(`{"challenge":"%s"}`, challenge))); err != nil {
+	if _, err := w.Write(responseBody); err != nil {
 		// Error already sent to client, just log it
 		_ = err
 	}
@@ -373,18 +479,14 @@ func (r *HTTPReceiver) handleInstallPath(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	// Create install URL options
-	installURLOptions := &oauth.InstallURLOptions{
-		Scopes:      []string{}, // Could be configured from receiver options
-		UserScopes:  []string{}, // Could be configured from receiver options
-		RedirectURI: "",         // Could be configured from receiver options
-	}
+	// Copy the install URL options, so concurrent requests do not share them
+	installURLOptions := r.installURLOptions
 
 	// Create install path options
 	installPathOptions := &oauth.InstallPathOptions{}
 
 	// Handle the install path request
-	if err := r.installer.HandleInstallPath(req, w, installPathOptions, installURLOptions); err != nil {
+	if err := r.installer.HandleInstallPath(req, w, installPathOptions, &installURLOptions); err != nil {
 		if r.logger != nil {
 			r.logger.Error("Failed to handle install path request", "error", err)
 		}
@@ -399,65 +501,12 @@ func (r *HTTPReceiver) handleInstallRedirect(w http.ResponseWriter, req *http.Re
 		return
 	}
 
-	// Create callback options with default success/failure handlers
-	callbackOptions := &oauth.CallbackOptions{
-		Success: func(installation *oauth.Installation, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
-			res.Header().Set("Content-Type", "text/html")
-			res.WriteHeader(http.StatusOK)
-			if _, err := res.Write([]byte(`
-<!DOCTYPE html>
-<html>
-<head>
-    <title>Installation Successful</title>
-    <style>
-        body { font-family: Arial, sans-serif; text-align: center; margin: 50px; }
-        .success { color: #2eb886; }
-    </style>
-</head>
-<body>
-    <h1 class="success">✅ Installation Successful!</h1>
-    <p>Your Slack app has been successfully installed.</p>
-    <p>You can now close this window and return to Slack.</p>
-</body>
-</html>`)); err != nil {
-				// Error already sent to client, just log it
-				_ = err
-			}
-		},
-		Failure: func(err error, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
-			if r.logger != nil {
-				r.logger.Error("OAuth installation failed", "error", err)
-			}
-			res.Header().Set("Content-Type", "text/html")
-			res.WriteHeader(http.StatusBadRequest)
-			if _, writeErr := res.Write([]byte(fmt.Sprintf(`
-<!DOCTYPE html>
-<html>
-<head>
-    <title>Installation Failed</title>
-    <style>
-        body { font-family: Arial, sans-serif; text-align: center; margin: 50px; }
-        .error { color: #e01e5a; }
-    </style>
-</head>
-<body>
-    <h1 class="error">❌ Installation Failed</h1>
-    <p>There was an error installing the Slack app:</p>
-    <p><code>%s</code></p>
-    <p>Please try again or contact support.</p>
-</body>
-</html>`, err.Error()))); writeErr != nil {
-				// Error already sent to client, just log it
-				_ = writeErr
-			}
-		},
-	}
-
-	// Create install URL options (these might be retrieved from state)
-	installURLOptions := &oauth.InstallURLOptions{}
+	// The install URL options are retrieved from the state, or are the receiver's when state
+	// verification is off
+	installURLOptions := r.installURLOptions
 
 	// Handle the callback request
-	if err := r.installer.HandleCallback(req, w, callbackOptions, installURLOptions); err != nil {
+	if err := r.installer.HandleCallback(req, w, r.callbackOptions, &installURLOptions); err != nil {
 		if r.logger != nil {
 			r.logger.Error("Failed to handle OAuth callback", "error", err)
 		}
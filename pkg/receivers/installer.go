@@ -0,0 +1,116 @@
+package receivers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// installURLOptions returns the options of the install URLs the receivers generate: the bot
+// scopes and redirect URI of the receiver, and the user scopes and metadata of options
+func installURLOptions(scopes []string, redirectURI string, options *types.InstallerOptions) oauth.InstallURLOptions {
+	urlOptions := oauth.InstallURLOptions{Scopes: scopes, RedirectURI: redirectURI}
+	if options != nil {
+		urlOptions.UserScopes = options.UserScopes
+		urlOptions.Metadata = options.Metadata
+	}
+	return urlOptions
+}
+
+// installerCallbackOptions returns how the receivers answer OAuth redirects: with the hooks of
+// options when set, or else with the default pages, logging failures with logger
+func installerCallbackOptions(options *types.InstallerOptions, logger types.Logger) *oauth.CallbackOptions {
+	if options == nil {
+		options = &types.InstallerOptions{}
+	}
+	callbackOptions := &oauth.CallbackOptions{
+		Success: func(installation *oauth.Installation, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
+			writeInstallPage(res, http.StatusOK, installSuccessPage)
+		},
+		Failure: func(err error, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
+			if logger != nil {
+				logger.Error("OAuth installation failed", "error", err)
+			}
+			writeInstallPage(res, http.StatusBadRequest, fmt.Sprintf(installFailurePage, html.EscapeString(err.Error())))
+		},
+	}
+	if options.CallbackOptions != nil {
+		if options.CallbackOptions.Success != nil {
+			callbackOptions.Success = options.CallbackOptions.Success
+		}
+		if options.CallbackOptions.Failure != nil {
+			callbackOptions.Failure = options.CallbackOptions.Failure
+		}
+	}
+
+	switch {
+	case options.CallbackSuccess != nil:
+		callbackOptions.Success = func(installation *oauth.Installation, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
+			options.CallbackSuccess(res, req, installation)
+		}
+	case options.SuccessRedirectURL != "":
+		callbackOptions.Success = func(installation *oauth.Installation, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
+			http.Redirect(res, req, options.SuccessRedirectURL, http.StatusFound)
+		}
+	}
+	if options.CallbackFailure != nil {
+		callbackOptions.Failure = func(err error, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
+			if logger != nil {
+				logger.Error("OAuth installation failed", "error", err)
+			}
+			options.CallbackFailure(res, req, err)
+		}
+	}
+	return callbackOptions
+}
+
+// writeInstallPage answers an OAuth redirect with page
+func writeInstallPage(res http.ResponseWriter, status int, page string) {
+	res.Header().Set("Content-Type", "text/html")
+	res.WriteHeader(status)
+	if _, err := res.Write([]byte(page)); err != nil {
+		// Error already sent to client, just log it
+		_ = err
+	}
+}
+
+// installSuccessPage is the default page shown once the app was installed
+const installSuccessPage = `
+<!DOCTYPE html>
+<html>
+<head>
+    <title>Installation Successful</title>
+    <style>
+        body { font-family: Arial, sans-serif; text-align: center; margin: 50px; }
+        .success { color: #2eb886; }
+    </style>
+</head>
+<body>
+    <h1 class="success">✅ Installation Successful!</h1>
+    <p>Your Slack app has been successfully installed.</p>
+    <p>You can now close this window and return to Slack.</p>
+</body>
+</html>`
+
+// installFailurePage is the default page shown when the installation failed, formatted with the
+// escaped error
+const installFailurePage = `
+<!DOCTYPE html>
+<html>
+<head>
+    <title>Installation Failed</title>
+    <style>
+        body { font-family: Arial, sans-serif; text-align: center; margin: 50px; }
+        .error { color: #e01e5a; }
+    </style>
+</head>
+<body>
+    <h1 class="error">❌ Installation Failed</h1>
+    <p>There was an error installing the Slack app:</p>
+    <p><code>%s</code></p>
+    <p>Please try again or contact support.</p>
+</body>
+</html>`
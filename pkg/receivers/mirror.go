@@ -0,0 +1,179 @@
+package receivers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// MirroredHeader is set on mirrored requests, so targets can tell them from Slack's and, for
+// instance, skip side effects such as posting messages
+const MirroredHeader = "X-Bolt-Mirrored"
+
+// Defaults of MirrorOptions
+const (
+	defaultMirrorTimeout    = 10 * time.Second
+	defaultMirrorBufferSize = 256
+)
+
+// mirrorSkippedHeaders are request headers not copied to mirrored requests
+var mirrorSkippedHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Connection":        true,
+	"Keep-Alive":        true,
+	"Transfer-Encoding": true,
+	"Accept-Encoding":   true,
+}
+
+// mirrorRequest is a verified request waiting to be mirrored
+type mirrorRequest struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// mirror sends copies of verified requests to the targets, each from its own queue so a slow
+// target does not hold back the others
+type mirror struct {
+	client  *http.Client
+	logger  types.Logger
+	targets []*mirrorTargetQueue
+	wg      sync.WaitGroup
+
+	// mu guards closed so no request is queued after the queues are closed
+	mu     sync.RWMutex
+	closed bool
+}
+
+// mirrorTargetQueue is the queue of one target
+type mirrorTargetQueue struct {
+	target   types.MirrorTarget
+	requests chan mirrorRequest
+}
+
+// newMirror starts the mirroring goroutines, or returns nil when no target is configured
+func newMirror(options *types.MirrorOptions, logger types.Logger) *mirror {
+	if options == nil || len(options.Targets) == 0 {
+		return nil
+	}
+	client := options.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultMirrorTimeout}
+	}
+	size := options.BufferSize
+	if size <= 0 {
+		size = defaultMirrorBufferSize
+	}
+
+	m := &mirror{client: client, logger: logger}
+	for _, target := range options.Targets {
+		queue := &mirrorTargetQueue{target: target, requests: make(chan mirrorRequest, size)}
+		m.targets = append(m.targets, queue)
+		m.wg.Add(1)
+		go m.run(queue)
+	}
+	return m
+}
+
+// mirror queues a copy of a verified request for every target, dropping it for targets whose
+// queue is full
+func (m *mirror) mirror(req *http.Request, body []byte) {
+	if m == nil {
+		return
+	}
+	request := mirrorRequest{path: req.URL.Path, header: req.Header.Clone(), body: body}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return
+	}
+	for _, queue := range m.targets {
+		select {
+		case queue.requests <- request:
+		default:
+			m.logger.Warn("Mirror queue full, dropped request", "target", queue.target.URL)
+		}
+	}
+}
+
+// run sends the requests queued for a target until its queue is closed
+func (m *mirror) run(queue *mirrorTargetQueue) {
+	defer m.wg.Done()
+	for request := range queue.requests {
+		if err := m.send(queue.target, request); err != nil {
+			m.logger.Warn("Failed to mirror request", "target", queue.target.URL, "error", err)
+		}
+	}
+}
+
+// send posts request to target, re-signing it if the target has its own signing secret
+func (m *mirror) send(target types.MirrorTarget, request mirrorRequest) error {
+	url := strings.TrimSuffix(target.URL, "/") + request.path
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(request.body))
+	if err != nil {
+		return err
+	}
+	for key, values := range request.header {
+		if !mirrorSkippedHeaders[http.CanonicalHeaderKey(key)] {
+			req.Header[key] = values
+		}
+	}
+	if target.SigningSecret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		req.Header.Set("X-Slack-Signature", helpers.GenerateSlackSignature(target.SigningSecret, "v0:"+timestamp+":"+string(request.body)))
+	}
+	req.Header.Set(MirroredHeader, "true")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("mirror target returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// close stops accepting requests and waits until queued requests are sent or ctx is done
+func (m *mirror) close(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		for _, queue := range m.targets {
+			close(queue.requests)
+		}
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,287 @@
+package receivers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// MultiAppReceiver serves several Slack apps, each with its own signing secret, from one HTTP
+// server. Each app is created with the receiver returned by Host as its AppOptions.Receiver,
+// and is reached either under its own path prefix or, on the shared endpoints, by the signing
+// secret that verifies the request.
+type MultiAppReceiver struct {
+	port         int
+	endpoints    types.ReceiverEndpoints
+	customRoutes []types.CustomRoute
+	logger       types.Logger
+
+	mu     sync.Mutex
+	apps   []*HostedApp
+	server *http.Server
+	done   chan struct{}
+}
+
+// HostedApp is the receiver of one app served by a MultiAppReceiver
+type HostedApp struct {
+	name     string
+	prefix   string
+	receiver *HTTPReceiver
+	parent   *MultiAppReceiver
+}
+
+// NewMultiAppReceiver creates a receiver serving several apps from one HTTP server
+func NewMultiAppReceiver(options types.MultiAppReceiverOptions) *MultiAppReceiver {
+	receiver := &MultiAppReceiver{
+		port:         3000, // default port
+		customRoutes: options.CustomRoutes,
+		logger:       options.Logger,
+	}
+	if options.Port > 0 {
+		receiver.port = options.Port
+	}
+	receiver.endpoints = defaultEndpoints(options.Endpoints)
+
+	// Set default logger if none provided
+	if receiver.logger == nil {
+		if options.LogLevel != nil {
+			handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+				Level: options.LogLevel.ToSlogLevel(),
+			})
+			receiver.logger = slog.New(handler)
+		} else {
+			receiver.logger = slog.Default()
+		}
+	}
+	return receiver
+}
+
+// Host adds an app to the receiver and returns the receiver to create it with. Apps must be
+// hosted before the receiver starts.
+func (r *MultiAppReceiver) Host(options types.HostedAppOptions) *HostedApp {
+	if options.Logger == nil {
+		options.Logger = r.logger
+	}
+	app := &HostedApp{
+		name:     options.Name,
+		prefix:   strings.TrimSuffix(options.PathPrefix, "/"),
+		receiver: NewHTTPReceiver(options.HTTPReceiverOptions),
+		parent:   r,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apps = append(r.apps, app)
+	return app
+}
+
+// Port returns the port the receiver listens on
+func (r *MultiAppReceiver) Port() int {
+	return r.port
+}
+
+// Handler returns the handler serving the hosted apps, for mounting them on a server of your own
+// instead of starting the receiver
+func (r *MultiAppReceiver) Handler() (http.Handler, error) {
+	r.mu.Lock()
+	apps := append([]*HostedApp(nil), r.apps...)
+	r.mu.Unlock()
+
+	mux := http.NewServeMux()
+	owners := make(map[string]string)
+	handle := func(path, owner string, handler http.HandlerFunc) error {
+		if other, ok := owners[path]; ok {
+			return fmt.Errorf("path %s of %s is already served by %s", path, owner, other)
+		}
+		owners[path] = owner
+		mux.HandleFunc(path, handler)
+		return nil
+	}
+
+	var shared []*HostedApp
+	for _, app := range apps {
+		if app.prefix == "" {
+			shared = append(shared, app)
+		}
+		for path, handler := range app.receiver.routes(app.prefix != "") {
+			if err := handle(app.prefix+path, app.String(), handler); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(shared) > 0 {
+		sharedHandler := r.sharedEventHandler(shared)
+		for _, endpoint := range r.endpoints.Paths() {
+			if err := handle(endpoint, "the shared endpoints", sharedHandler); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, route := range r.customRoutes {
+		if err := handle(route.Path, "a custom route", route.Handler); err != nil {
+			return nil, err
+		}
+	}
+	return mux, nil
+}
+
+// sharedEventHandler routes requests on the shared endpoints to the app whose signing secret
+// verifies them. Apps without signature verification only get the requests no other app verifies.
+func (r *MultiAppReceiver) sharedEventHandler(apps []*HostedApp) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		defer req.Body.Close()
+		headers := requestHeaders(req)
+
+		var unverified *HostedApp
+		var verifyErr error
+		for _, app := range apps {
+			if !app.receiver.signatureVerification {
+				if unverified == nil {
+					unverified = app
+				}
+				continue
+			}
+			if verifyErr = app.receiver.verifySlackRequest(req, body); verifyErr == nil {
+				app.receiver.serveSlackRequest(w, req, body, headers)
+				return
+			}
+		}
+		if unverified != nil {
+			unverified.receiver.serveSlackRequest(w, req, body, headers)
+			return
+		}
+		apps[0].receiver.rejectSlackRequest(w, req, verifyErr, body, headers)
+	}
+}
+
+// Start serves the hosted apps until ctx is done or the receiver is stopped. When the server is
+// already running, e.g. because another hosted app started it, Start waits until it stops.
+func (r *MultiAppReceiver) Start(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	r.mu.Lock()
+	if r.server != nil {
+		done := r.done
+		r.mu.Unlock()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	r.mu.Unlock()
+
+	handler, err := r.Handler()
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", r.port),
+		Handler:           handler,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	done := make(chan struct{})
+
+	r.mu.Lock()
+	if r.server != nil {
+		// Another hosted app started the server meanwhile
+		r.mu.Unlock()
+		return r.Start(ctx)
+	}
+	r.server, r.done = server, done
+	r.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = r.Stop(context.Background())
+		case <-done:
+		}
+	}()
+
+	err = server.ListenAndServe()
+	r.mu.Lock()
+	r.server = nil
+	close(done)
+	r.mu.Unlock()
+
+	// If the server was shut down due to context cancellation, return context error
+	if err == http.ErrServerClosed && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// Stop stops the server of all hosted apps
+func (r *MultiAppReceiver) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	server := r.server
+	apps := append([]*HostedApp(nil), r.apps...)
+	r.mu.Unlock()
+
+	var err error
+	if server != nil {
+		err = server.Shutdown(ctx)
+	}
+	for _, app := range apps {
+		if mirrorErr := app.receiver.mirror.close(ctx); err == nil {
+			err = mirrorErr
+		}
+	}
+	return err
+}
+
+// Name returns the name the app was hosted with
+func (h *HostedApp) Name() string {
+	return h.name
+}
+
+// String describes the app in errors
+func (h *HostedApp) String() string {
+	if h.name != "" {
+		return "app " + h.name
+	}
+	if h.prefix != "" {
+		return "app at " + h.prefix
+	}
+	return "unnamed app"
+}
+
+// Init initializes the receiver with the app
+func (h *HostedApp) Init(app types.App) error {
+	return h.receiver.Init(app)
+}
+
+// Start starts the shared server unless it is running already, see MultiAppReceiver.Start
+func (h *HostedApp) Start(ctx context.Context) error {
+	return h.parent.Start(ctx)
+}
+
+// Stop stops mirroring the app's requests. The shared server keeps serving the other apps; it
+// is stopped by MultiAppReceiver.Stop.
+func (h *HostedApp) Stop(ctx context.Context) error {
+	return h.receiver.mirror.close(ctx)
+}
+
+// UpdateCredentials rotates the app's signing secret and client secret
+func (h *HostedApp) UpdateCredentials(update types.CredentialUpdate) {
+	h.receiver.UpdateCredentials(update)
+}
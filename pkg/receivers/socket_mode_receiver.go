@@ -7,10 +7,13 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
 	"github.com/Asafrose/bolt-go/pkg/oauth"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/slack-go/slack"
@@ -20,7 +23,7 @@ import (
 // SocketModeReceiver handles Socket Mode connections from Slack using the official socketmode client
 type SocketModeReceiver struct {
 	appToken                  string
-	logger                    *slog.Logger
+	logger                    types.Logger
 	client                    *socketmode.Client
 	customProperties          map[string]interface{}
 	customPropertiesExtractor func(map[string]interface{}) map[string]interface{}
@@ -33,6 +36,34 @@ type SocketModeReceiver struct {
 	installPath            string
 	installRedirectURIPath string
 	stateVerification      bool
+	callbackOptions        *oauth.CallbackOptions
+	installURLOptions      oauth.InstallURLOptions
+
+	// Envelope processing; workers is nil when envelopes are processed one at a time
+	workerPoolSize      int
+	workerQueueSize     int
+	overloadPolicy      types.OverloadPolicy
+	eventTypePriorities map[string]int
+	onEnvelopeDropped   func(eventType string)
+	workers             *workerPool
+
+	capture types.EventCapturer
+
+	connected atomic.Bool
+
+	// Connection lifecycle hooks
+	onConnecting func()
+	onConnected  func(hello types.SocketModeHello)
+	onDisconnect func(reason string)
+	onReconnect  func()
+	// disconnectReasons is nil unless onDisconnect is set
+	disconnectReasons *disconnectReasons
+
+	// Reconnect policy
+	maxReconnectAttempts int
+	reconnectBackoff     types.ReconnectBackoff
+	onReconnecting       func(attempt int, delay time.Duration, err error)
+	attempt              connectionAttempt
 
 	app    types.App
 	ctx    context.Context
@@ -43,10 +74,21 @@ type SocketModeReceiver struct {
 // NewSocketModeReceiver creates a new Socket Mode receiver
 func NewSocketModeReceiver(options types.SocketModeReceiverOptions) *SocketModeReceiver {
 	// Create slack API client
-	slackClient := slack.New(options.BotToken, slack.OptionAppLevelToken(options.AppToken))
+	slackOptions := []slack.Option{slack.OptionAppLevelToken(options.AppToken)}
+	if options.APIURL != "" {
+		slackOptions = append(slackOptions, slack.OptionAPIURL(options.APIURL))
+	}
+	slackClient := slack.New(options.BotToken, slackOptions...)
 
 	// Create socketmode client options
-	socketmodeOptions := []socketmode.Option{}
+	var socketmodeOptions []socketmode.Option
+	var reasons *disconnectReasons
+	if options.OnDisconnect != nil {
+		// Placed first, so client options setting their own log take precedence
+		reasons = &disconnectReasons{}
+		socketmodeOptions = append(socketmodeOptions, socketmode.OptionDebug(true), socketmode.OptionLog(reasons))
+	}
+	socketmodeOptions = append(socketmodeOptions, options.ClientOptions...)
 
 	// Add ping interval if specified
 	if options.PingTimeout > 0 {
@@ -66,6 +108,23 @@ func NewSocketModeReceiver(options types.SocketModeReceiverOptions) *SocketModeR
 		customRoutes:              options.CustomRoutes,
 		stateVerification:         true, // default to true
 		httpServerPort:            3000, // default port
+		workerPoolSize:            options.WorkerPoolSize,
+		workerQueueSize:           options.WorkerQueueSize,
+		overloadPolicy:            options.OverloadPolicy,
+		eventTypePriorities:       options.EventTypePriorities,
+		onEnvelopeDropped:         options.OnEnvelopeDropped,
+		capture:                   options.Capture,
+		onConnecting:              options.OnConnecting,
+		onConnected:               options.OnConnected,
+		onDisconnect:              options.OnDisconnect,
+		onReconnect:               options.OnReconnect,
+		disconnectReasons:         reasons,
+		maxReconnectAttempts:      options.MaxReconnectAttempts,
+		reconnectBackoff:          options.ReconnectBackoff,
+		onReconnecting:            options.OnReconnecting,
+	}
+	if receiver.reconnectBackoff == nil {
+		receiver.reconnectBackoff = defaultReconnectBackoff
 	}
 
 	// Initialize OAuth if configuration is provided
@@ -84,6 +143,7 @@ func NewSocketModeReceiver(options types.SocketModeReceiverOptions) *SocketModeR
 
 		// Set installer options if provided
 		if options.InstallerOptions != nil {
+			installProviderOptions.StateStore = options.InstallerOptions.StateStore
 			installProviderOptions.StateVerification = options.InstallerOptions.StateVerification
 			installProviderOptions.LegacyStateVerification = options.InstallerOptions.LegacyStateVerification
 			installProviderOptions.StateCookieName = options.InstallerOptions.StateCookieName
@@ -91,6 +151,8 @@ func NewSocketModeReceiver(options types.SocketModeReceiverOptions) *SocketModeR
 			installProviderOptions.AuthVersion = options.InstallerOptions.AuthVersion
 			installProviderOptions.DirectInstall = options.InstallerOptions.DirectInstall
 			installProviderOptions.AuthorizationURL = options.InstallerOptions.AuthorizationURL
+			installProviderOptions.RenderHtmlForInstallPath = options.InstallerOptions.RenderHtmlForInstallPath
+			installProviderOptions.RenderInstallPage = options.InstallerOptions.RenderInstallPage
 
 			// Set paths
 			receiver.installPath = options.InstallerOptions.InstallPath
@@ -115,6 +177,9 @@ func NewSocketModeReceiver(options types.SocketModeReceiverOptions) *SocketModeR
 			receiver.installRedirectURIPath = "/slack/oauth_redirect"
 		}
 
+		receiver.callbackOptions = installerCallbackOptions(options.InstallerOptions, receiver.logger)
+		receiver.installURLOptions = installURLOptions(options.Scopes, options.RedirectURI, options.InstallerOptions)
+
 		// Create install provider
 		var err error
 		receiver.installer, err = oauth.NewInstallProvider(installProviderOptions)
@@ -147,7 +212,16 @@ func (r *SocketModeReceiver) Init(app types.App) error {
 	return nil
 }
 
-// Start starts the Socket Mode connection
+// UpdateCredentials rotates the client secret of the installer; Socket Mode requests are not
+// signed, so the signing secret is ignored
+func (r *SocketModeReceiver) UpdateCredentials(update types.CredentialUpdate) {
+	if update.ClientSecret != "" && r.installer != nil {
+		r.installer.UpdateClientSecret(update.ClientSecret)
+	}
+}
+
+// Start serves the Socket Mode connection until ctx is done or the receiver is stopped, returning
+// the error of a connection that failed for good, such as one opened with a revoked app token
 func (r *SocketModeReceiver) Start(ctx context.Context) error {
 	r.ctx, r.cancel = context.WithCancel(ctx)
 
@@ -158,15 +232,24 @@ func (r *SocketModeReceiver) Start(ctx context.Context) error {
 		}
 	}
 
+	// Start the envelope workers before any envelope can arrive
+	if r.workerPoolSize > 0 {
+		r.workers = newWorkerPool(r.workerPoolSize, r.workerQueueSize, r.overloadPolicy, r.envelopeDropped)
+		r.workers.start(r.ctx)
+	}
+
 	// Set up event handling
 	r.setupEventHandlers()
 
-	// Start the socketmode client
+	// Run the socketmode client. It only returns before the receiver is stopped on errors it
+	// cannot recover from by reconnecting, such as a revoked app token, which stop the receiver.
+	var runErr error
 	r.wg.Add(1)
 	go func() {
 		defer r.wg.Done()
-		if err := r.client.RunContext(r.ctx); err != nil {
-			r.logger.Error("Socket mode client error", "error", err)
+		if err := r.runConnection(); err != nil {
+			runErr = err
+			r.cancel()
 		}
 	}()
 
@@ -176,8 +259,11 @@ func (r *SocketModeReceiver) Start(ctx context.Context) error {
 	// Clean up
 	r.cleanup()
 	r.wg.Wait()
+	if r.workers != nil {
+		r.workers.wait()
+	}
 
-	return nil
+	return runErr
 }
 
 // Stop stops the Socket Mode connection
@@ -188,28 +274,77 @@ func (r *SocketModeReceiver) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Connected reports whether the receiver holds an open Socket Mode connection to Slack
+func (r *SocketModeReceiver) Connected() bool {
+	return r.connected.Load()
+}
+
 // setupEventHandlers configures event handlers for the socketmode client
 func (r *SocketModeReceiver) setupEventHandlers() {
 	// Handle all socketmode events
 	go func() {
+		// open reports whether a connection is open, and ended whether one ended since the last
+		// connection attempt
+		var open, ended bool
 		for evt := range r.client.Events {
 			switch evt.Type {
 			case socketmode.EventTypeConnecting:
+				r.connected.Store(false)
+				if open {
+					// The socketmode client reconnects on disconnect messages without emitting them
+					open = false
+					ended = true
+					r.disconnected(r.disconnectReasons.take())
+				}
+				if ended {
+					ended = false
+					if r.onReconnect != nil {
+						r.onReconnect()
+					}
+				}
 				r.logger.Info("Connecting to Slack with Socket Mode")
+				if r.onConnecting != nil {
+					r.onConnecting()
+				}
 			case socketmode.EventTypeConnectionError:
+				r.connected.Store(false)
 				r.logger.Error("Connection failed", "error", evt.Data)
+				r.attempt.failed(connectionError(evt.Data))
 			case socketmode.EventTypeConnected:
+				open = true
+				r.attempt.opened()
+				r.connected.Store(true)
 				r.logger.Info("Connected to Slack with Socket Mode")
 			case socketmode.EventTypeEventsAPI:
-				r.handleEventsAPI(evt)
+				r.dispatch(evt, r.handleEventsAPI)
 			case socketmode.EventTypeInteractive:
-				r.handleInteractive(evt)
+				r.dispatch(evt, r.handleInteractive)
 			case socketmode.EventTypeSlashCommand:
-				r.handleSlashCommand(evt)
+				r.dispatch(evt, r.handleSlashCommand)
+			case socketmode.EventTypeErrorBadMessage:
+				r.handleBadMessage(evt)
 			case socketmode.EventTypeHello:
 				r.logger.Info("Received hello message from Slack")
+				if r.onConnected != nil && evt.Request != nil {
+					r.onConnected(types.SocketModeHello{
+						AppID:                     evt.Request.ConnectionInfo.AppID,
+						NumConnections:            evt.Request.NumConnections,
+						Host:                      evt.Request.DebugInfo.Host,
+						ApproximateConnectionTime: evt.Request.DebugInfo.ApproximateConnectionTime,
+					})
+				}
 			case socketmode.EventTypeDisconnect:
-				r.logger.Info("Received disconnect message from Slack")
+				r.connected.Store(false)
+				var reason string
+				if evt.Request != nil {
+					reason = evt.Request.Reason
+				}
+				r.logger.Info("Received disconnect message from Slack", "reason", reason)
+				if open {
+					open = false
+					ended = true
+					r.disconnected(reason)
+				}
 			default:
 				r.logger.Warn("Received unknown event type", "type", evt.Type)
 			}
@@ -217,23 +352,159 @@ func (r *SocketModeReceiver) setupEventHandlers() {
 	}()
 }
 
+// disconnectReasons collects the reason of the disconnect messages of Slack from the debug log
+// of the socketmode client, the only place it surfaces them
+type disconnectReasons struct {
+	mu     sync.Mutex
+	reason string
+}
+
+// Output receives a line of the socketmode client's debug log
+func (d *disconnectReasons) Output(_ int, line string) error {
+	message, ok := strings.CutPrefix(line, "Received WebSocket message: ")
+	if !ok {
+		return nil
+	}
+	var disconnect struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(message), &disconnect); err == nil && disconnect.Type == string(socketmode.RequestTypeDisconnect) {
+		d.mu.Lock()
+		d.reason = disconnect.Reason
+		d.mu.Unlock()
+	}
+	return nil
+}
+
+// take returns and forgets the reason of the last disconnect message, "" if there was none
+func (d *disconnectReasons) take() string {
+	if d == nil {
+		return ""
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	reason := d.reason
+	d.reason = ""
+	return reason
+}
+
+// disconnected runs the OnDisconnect hook for a connection that ended for reason
+func (r *SocketModeReceiver) disconnected(reason string) {
+	if r.onDisconnect != nil {
+		r.onDisconnect(reason)
+	}
+}
+
+// dispatch runs handler for evt directly, or queues it on the worker pool when one is configured.
+// Events API envelopes that cannot carry a response payload are acknowledged as soon as they are
+// queued; the pool never sheds them and runs them even when the receiver stops.
+func (r *SocketModeReceiver) dispatch(evt socketmode.Event, handler func(socketmode.Event, bool)) {
+	if r.workers == nil {
+		handler(evt, false)
+		return
+	}
+
+	ackOnQueue := evt.Request != nil && !evt.Request.AcceptsResponsePayload && evt.Type == socketmode.EventTypeEventsAPI
+	eventType := envelopeEventType(evt)
+	job := &workerJob{
+		eventType: eventType,
+		priority:  r.eventTypePriorities[eventType],
+		acked:     ackOnQueue,
+		run:       func() { handler(evt, ackOnQueue) },
+	}
+	if !r.workers.submit(job) {
+		// Shed or stopped envelopes were not acknowledged yet, so Slack redelivers them
+		return
+	}
+	if ackOnQueue {
+		r.client.Ack(*evt.Request)
+	}
+}
+
+// QueueStats returns a snapshot of the envelope queue, or zero stats when no worker pool is configured
+func (r *SocketModeReceiver) QueueStats() types.ReceiverQueueStats {
+	if r.workers == nil {
+		return types.ReceiverQueueStats{DroppedByEventType: map[string]int64{}}
+	}
+	return r.workers.stats()
+}
+
+// envelopeDropped logs and reports an envelope shed under overload
+func (r *SocketModeReceiver) envelopeDropped(eventType string) {
+	r.logger.Warn("Envelope queue full, dropped envelope", errors.LogKeyEventType, eventType, "overload_policy", r.overloadPolicy)
+	if r.onEnvelopeDropped != nil {
+		r.onEnvelopeDropped(eventType)
+	}
+}
+
+// envelopeEventType returns the event type used to prioritize evt, e.g. "app_mention" or "block_actions"
+func envelopeEventType(evt socketmode.Event) string {
+	if evt.Request == nil {
+		return string(evt.Type)
+	}
+	if eventType := helpers.ExtractEventType(evt.Request.Payload); eventType != "" {
+		return eventType
+	}
+	var payload struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(evt.Request.Payload, &payload); err == nil && payload.Type != "" {
+		return payload.Type
+	}
+	return evt.Request.Type
+}
+
 // handleEventsAPI handles Events API messages
-func (r *SocketModeReceiver) handleEventsAPI(evt socketmode.Event) {
-	r.processEvent(evt)
+func (r *SocketModeReceiver) handleEventsAPI(evt socketmode.Event, acked bool) {
+	r.processEvent(evt, acked)
 }
 
 // handleInteractive handles interactive messages
-func (r *SocketModeReceiver) handleInteractive(evt socketmode.Event) {
-	r.processEvent(evt)
+func (r *SocketModeReceiver) handleInteractive(evt socketmode.Event, acked bool) {
+	r.processEvent(evt, acked)
 }
 
 // handleSlashCommand handles slash command messages
-func (r *SocketModeReceiver) handleSlashCommand(evt socketmode.Event) {
-	r.processEvent(evt)
+func (r *SocketModeReceiver) handleSlashCommand(evt socketmode.Event, acked bool) {
+	r.processEvent(evt, acked)
 }
 
-// processEvent processes an event through the app
-func (r *SocketModeReceiver) processEvent(evt socketmode.Event) {
+// handleBadMessage dispatches envelopes the socketmode client failed to decode into its own
+// types, e.g. slash commands missing a field it requires. The app only needs the raw payload, so
+// such envelopes are processed like the others rather than dropped unacknowledged.
+func (r *SocketModeReceiver) handleBadMessage(evt socketmode.Event) {
+	bad, ok := evt.Data.(*socketmode.ErrorBadMessage)
+	if !ok {
+		r.logger.Warn("Received malformed Socket Mode message")
+		return
+	}
+
+	var req socketmode.Request
+	if err := json.Unmarshal(bad.Message, &req); err != nil || req.EnvelopeID == "" {
+		r.logger.Warn("Received malformed Socket Mode message", "error", bad.Cause)
+		return
+	}
+	evt.Request = &req
+
+	switch req.Type {
+	case socketmode.RequestTypeEventsAPI:
+		evt.Type = socketmode.EventTypeEventsAPI
+		r.dispatch(evt, r.handleEventsAPI)
+	case socketmode.RequestTypeInteractive:
+		evt.Type = socketmode.EventTypeInteractive
+		r.dispatch(evt, r.handleInteractive)
+	case socketmode.RequestTypeSlashCommands:
+		evt.Type = socketmode.EventTypeSlashCommand
+		r.dispatch(evt, r.handleSlashCommand)
+	default:
+		r.logger.Warn("Received malformed Socket Mode message", "type", req.Type, "error", bad.Cause)
+	}
+}
+
+// processEvent processes an event through the app. acked reports whether the envelope was
+// already acknowledged when it was queued, in which case the listener's ack is not sent again.
+func (r *SocketModeReceiver) processEvent(evt socketmode.Event, acked bool) {
 	// The request is directly available in the event
 	req := evt.Request
 	if req == nil {
@@ -252,19 +523,33 @@ func (r *SocketModeReceiver) processEvent(evt socketmode.Event) {
 	headers := map[string]string{
 		"Content-Type": "application/json",
 	}
+	// The envelope ID is the event's correlation ID, and identifies it for deduplication
+	if req.EnvelopeID != "" {
+		headers["X-Request-Id"] = req.EnvelopeID
+		headers[types.EnvelopeIDHeader] = req.EnvelopeID
+	}
 
-	ackCalled := false
+	// Listeners run concurrently under AppOptions.ListenerConcurrency may ack at the same time
+	var ackCalled atomic.Bool
 	event := types.ReceiverEvent{
-		Body:    payloadBytes,
-		Headers: headers,
+		Body:        payloadBytes,
+		Headers:     headers,
+		RetryNum:    req.RetryAttempt,
+		RetryReason: req.RetryReason,
 		Ack: func(response types.AckResponse) error {
-			if ackCalled {
+			if !ackCalled.CompareAndSwap(false, true) {
 				return errors.NewReceiverMultipleAckError()
 			}
-			ackCalled = true
+			if acked {
+				return nil
+			}
 
 			// Send acknowledgment back to Slack using the official client
-			r.client.Ack(*req, response)
+			if payload := socketModeAckPayload(req, response); payload != nil {
+				r.client.Ack(*req, payload)
+			} else {
+				r.client.Ack(*req)
+			}
 			return nil
 		},
 	}
@@ -285,10 +570,21 @@ func (r *SocketModeReceiver) processEvent(evt socketmode.Event) {
 		_ = customProps
 	}
 
-	// Process the event
-	if err := r.app.ProcessEvent(r.ctx, event); err != nil {
-		r.logger.Error("Failed to process event", "error", err)
-		if !ackCalled {
+	// Process the event. Envelopes acknowledged when queued are processed to completion even when
+	// the receiver stops, since Slack will not redeliver them.
+	ctx := r.ctx
+	if acked {
+		ctx = context.WithoutCancel(ctx)
+	}
+	captureEvent(ctx, r.capture, r.logger, event)
+	if err := r.app.ProcessEvent(ctx, event); err != nil {
+		if errors.IsRetryable(err) && !acked {
+			// Leave the envelope unacknowledged so Slack redelivers it
+			r.logger.Error("Failed to process event, withholding ack so Slack retries", append(errors.LogAttrs(err), errors.LogKeyRetryNum, event.RetryNum)...)
+			return
+		}
+		r.logger.Error("Failed to process event", append(errors.LogAttrs(err), errors.LogKeyRetryNum, event.RetryNum)...)
+		if !ackCalled.Load() {
 			if ackErr := event.Ack(nil); ackErr != nil {
 				// Log error but don't fail the request
 				r.logger.Error("Failed to ack event", "error", ackErr)
@@ -298,13 +594,33 @@ func (r *SocketModeReceiver) processEvent(evt socketmode.Event) {
 	}
 
 	// Auto-acknowledge if not already done
-	if !ackCalled {
+	if !ackCalled.Load() {
 		if err := event.Ack(nil); err != nil {
 			r.logger.Error("Failed to auto-ack event", "error", err)
 		}
 	}
 }
 
+// socketModeAckPayload returns the payload acknowledging req with response, or nil to acknowledge
+// it without one. Only interactive and slash command envelopes accept a payload, which, unlike an
+// HTTP response body, must be a JSON object: a string response is sent as the text of a message.
+func socketModeAckPayload(req *socketmode.Request, response types.AckResponse) interface{} {
+	if !req.AcceptsResponsePayload {
+		return nil
+	}
+	switch resp := response.(type) {
+	case nil, types.AckVoid:
+		return nil
+	case types.AckString:
+		if resp == "" {
+			return nil
+		}
+		return map[string]string{"text": string(resp)}
+	default:
+		return resp
+	}
+}
+
 // startHTTPServer starts the HTTP server for OAuth and custom routes
 func (r *SocketModeReceiver) startHTTPServer() error {
 	mux := http.NewServeMux()
@@ -343,18 +659,14 @@ func (r *SocketModeReceiver) handleInstallPath(w http.ResponseWriter, req *http.
 		return
 	}
 
-	// Create install URL options
-	installURLOptions := &oauth.InstallURLOptions{
-		Scopes:      []string{}, // Could be configured from receiver options
-		UserScopes:  []string{}, // Could be configured from receiver options
-		RedirectURI: "",         // Could be configured from receiver options
-	}
+	// Copy the install URL options, so concurrent requests do not share them
+	installURLOptions := r.installURLOptions
 
 	// Create install path options
 	installPathOptions := &oauth.InstallPathOptions{}
 
 	// Handle the install path request
-	if err := r.installer.HandleInstallPath(req, w, installPathOptions, installURLOptions); err != nil {
+	if err := r.installer.HandleInstallPath(req, w, installPathOptions, &installURLOptions); err != nil {
 		r.logger.Error("Failed to handle install path request", "error", err)
 		http.Error(w, "Failed to handle install request", http.StatusInternalServerError)
 	}
@@ -367,63 +679,12 @@ func (r *SocketModeReceiver) handleInstallRedirect(w http.ResponseWriter, req *h
 		return
 	}
 
-	// Create callback options with default success/failure handlers
-	callbackOptions := &oauth.CallbackOptions{
-		Success: func(installation *oauth.Installation, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
-			res.Header().Set("Content-Type", "text/html")
-			res.WriteHeader(http.StatusOK)
-			if _, err := res.Write([]byte(`
-<!DOCTYPE html>
-<html>
-<head>
-    <title>Installation Successful</title>
-    <style>
-        body { font-family: Arial, sans-serif; text-align: center; margin: 50px; }
-        .success { color: #2eb886; }
-    </style>
-</head>
-<body>
-    <h1 class="success">✅ Installation Successful!</h1>
-    <p>Your Slack app has been successfully installed.</p>
-    <p>You can now close this window and return to Slack.</p>
-</body>
-</html>`)); err != nil {
-				// Error already sent to client, just log it
-				_ = err
-			}
-		},
-		Failure: func(err error, installOptions *oauth.InstallURLOptions, req *http.Request, res http.ResponseWriter) {
-			r.logger.Error("OAuth installation failed", "error", err)
-			res.Header().Set("Content-Type", "text/html")
-			res.WriteHeader(http.StatusBadRequest)
-			if _, writeErr := res.Write([]byte(fmt.Sprintf(`
-<!DOCTYPE html>
-<html>
-<head>
-    <title>Installation Failed</title>
-    <style>
-        body { font-family: Arial, sans-serif; text-align: center; margin: 50px; }
-        .error { color: #e01e5a; }
-    </style>
-</head>
-<body>
-    <h1 class="error">❌ Installation Failed</h1>
-    <p>There was an error installing the Slack app:</p>
-    <p><code>%s</code></p>
-    <p>Please try again or contact support.</p>
-</body>
-</html>`, err.Error()))); writeErr != nil {
-				// Error already sent to client, just log it
-				_ = writeErr
-			}
-		},
-	}
-
-	// Create install URL options (these might be retrieved from state)
-	installURLOptions := &oauth.InstallURLOptions{}
+	// The install URL options are retrieved from the state, or are the receiver's when state
+	// verification is off
+	installURLOptions := r.installURLOptions
 
 	// Handle the callback request
-	if err := r.installer.HandleCallback(req, w, callbackOptions, installURLOptions); err != nil {
+	if err := r.installer.HandleCallback(req, w, r.callbackOptions, &installURLOptions); err != nil {
 		r.logger.Error("Failed to handle OAuth callback", "error", err)
 		// Error handling is done by the callback options
 	}
@@ -431,6 +692,8 @@ func (r *SocketModeReceiver) handleInstallRedirect(w http.ResponseWriter, req *h
 
 // cleanup closes the socketmode client and HTTP server
 func (r *SocketModeReceiver) cleanup() {
+	r.connected.Store(false)
+
 	// The socketmode client will be closed when the context is cancelled
 	// No need to explicitly close it here
 
@@ -0,0 +1,145 @@
+package receivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+)
+
+// ExponentialBackoff returns a ReconnectBackoff waiting initial after the first failed attempt and
+// twice as long after each following one, up to maxDelay
+func ExponentialBackoff(initial, maxDelay time.Duration) types.ReconnectBackoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		return min(delay, maxDelay)
+	}
+}
+
+// ConstantBackoff returns a ReconnectBackoff always waiting delay
+func ConstantBackoff(delay time.Duration) types.ReconnectBackoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// defaultReconnectBackoff matches the backoff of the socketmode client
+var defaultReconnectBackoff = ExponentialBackoff(100*time.Millisecond, 5*time.Minute)
+
+// connectionAttempt is the run of the socketmode client in progress. The client retries failed
+// connections on its own schedule, so the receiver stops it at the first failure and applies its
+// own policy before running it again.
+type connectionAttempt struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	// connected reports whether a connection was opened during the run
+	connected bool
+	// err is the error of the failed attempt that stopped the run
+	err error
+	// retryAfter is how long Slack asked to wait before the next attempt, 0 if it did not
+	retryAfter time.Duration
+}
+
+// start begins a new run stopped by cancel
+func (a *connectionAttempt) start(cancel context.CancelFunc) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cancel = cancel
+	a.connected = false
+	a.err = nil
+	a.retryAfter = 0
+}
+
+// opened records that a connection was opened
+func (a *connectionAttempt) opened() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.connected = true
+}
+
+// failed stops the run after an attempt failed with err, Slack asking to wait retryAfter before
+// the next one
+func (a *connectionAttempt) failed(retryAfter time.Duration, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil || a.cancel == nil {
+		return
+	}
+	a.err = err
+	a.retryAfter = retryAfter
+	a.cancel()
+}
+
+// result returns whether a connection was opened during the run, how long Slack asked to wait
+// before the next attempt, and the error that stopped the run, nil if no attempt failed
+func (a *connectionAttempt) result() (bool, time.Duration, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.connected, a.retryAfter, a.err
+}
+
+// runConnection runs the socketmode client until the receiver is stopped, waiting out
+// ReconnectBackoff after failed attempts to open a connection, or the Retry-After of rate limited
+// ones when it is longer. It returns the error of a connection
+// that failed for good: one rejected for its app token, or MaxReconnectAttempts failed attempts.
+func (r *SocketModeReceiver) runConnection() error {
+	failures := 0
+	for {
+		ctx, cancel := context.WithCancel(r.ctx)
+		r.attempt.start(cancel)
+		err := r.client.RunContext(ctx)
+		cancel()
+		if r.ctx.Err() != nil {
+			return nil
+		}
+
+		connected, retryAfter, attemptErr := r.attempt.result()
+		if attemptErr == nil {
+			r.logger.Error("Socket mode client error", "error", err)
+			return fmt.Errorf("socket mode connection failed: %w", err)
+		}
+		if connected {
+			failures = 0
+		}
+		failures++
+		if r.maxReconnectAttempts > 0 && failures >= r.maxReconnectAttempts {
+			r.logger.Error("Giving up connecting to Slack with Socket Mode", "attempts", failures, "error", attemptErr)
+			return fmt.Errorf("socket mode connection failed after %d attempts: %w", failures, attemptErr)
+		}
+
+		delay := max(r.reconnectBackoff(failures), retryAfter)
+		r.logger.Warn("Reconnecting to Slack with Socket Mode", "attempt", failures, "delay", delay, "error", attemptErr)
+		if r.onReconnecting != nil {
+			r.onReconnecting(failures, delay, attemptErr)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-r.ctx.Done():
+			timer.Stop()
+			return nil
+		}
+	}
+}
+
+// connectionError returns the Retry-After of a connection_error event of the socketmode client,
+// 0 unless the attempt was rate limited, and its error. The Backoff of other events is the
+// client's own schedule, which ReconnectBackoff replaces.
+func connectionError(data interface{}) (time.Duration, error) {
+	event, ok := data.(*slack.ConnectionErrorEvent)
+	if !ok || event.ErrorObj == nil {
+		return 0, errors.New("connection failed")
+	}
+	var rateLimited *slack.RateLimitedError
+	if errors.As(event.ErrorObj, &rateLimited) {
+		return event.Backoff, event.ErrorObj
+	}
+	return 0, event.ErrorObj
+}
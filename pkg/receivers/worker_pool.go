@@ -0,0 +1,203 @@
+package receivers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// defaultWorkerQueueSize is the number of queued jobs allowed when a worker pool is configured without a queue size
+const defaultWorkerQueueSize = 100
+
+// workerJob is a unit of work queued on a workerPool
+type workerJob struct {
+	eventType string
+	priority  int
+	// acked reports the job is acknowledged once queued, so Slack will not redeliver it; such
+	// jobs are never shed and are still run when the pool closes
+	acked bool
+	run   func()
+}
+
+// workerPool runs jobs on a fixed number of goroutines fed by a bounded queue.
+// When the queue is full, the overload policy decides whether to wait or which job to shed.
+type workerPool struct {
+	size      int
+	queueSize int
+	policy    types.OverloadPolicy
+	onDrop    func(eventType string)
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    []*workerJob
+	closed   bool
+	wg       sync.WaitGroup
+
+	processed          int64
+	dropped            int64
+	droppedByEventType map[string]int64
+}
+
+// newWorkerPool creates a worker pool with size workers and room for queueSize pending jobs
+func newWorkerPool(size, queueSize int, policy types.OverloadPolicy, onDrop func(eventType string)) *workerPool {
+	if queueSize <= 0 {
+		queueSize = defaultWorkerQueueSize
+	}
+	p := &workerPool{
+		size:               size,
+		queueSize:          queueSize,
+		policy:             policy,
+		onDrop:             onDrop,
+		droppedByEventType: make(map[string]int64),
+	}
+	p.notEmpty = sync.NewCond(&p.mu)
+	p.notFull = sync.NewCond(&p.mu)
+	return p
+}
+
+// start launches the workers; they exit once ctx is cancelled
+func (p *workerPool) start(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+		p.notEmpty.Broadcast()
+		p.notFull.Broadcast()
+	}()
+
+	for i := 0; i < p.size; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for {
+				job := p.next()
+				if job == nil {
+					return
+				}
+				job.run()
+			}
+		}()
+	}
+}
+
+// next blocks until a job is available and returns it, or returns nil once the pool is closed
+// and drained. A closed pool still hands out its acknowledged jobs; the others are left for Slack
+// to redeliver.
+func (p *workerPool) next() *workerJob {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.queue) == 0 && !p.closed {
+		p.notEmpty.Wait()
+	}
+	for len(p.queue) > 0 {
+		job := p.queue[0]
+		p.queue = p.queue[1:]
+		if p.closed && !job.acked {
+			continue
+		}
+		p.processed++
+		p.notFull.Signal()
+		return job
+	}
+	return nil
+}
+
+// submit queues job according to the overload policy. It returns false if job was shed
+// or the pool was closed before it could be queued.
+func (p *workerPool) submit(job *workerJob) bool {
+	p.mu.Lock()
+
+	var shed *workerJob
+	for len(p.queue) >= p.queueSize && !p.closed && shed == nil {
+		switch p.policy {
+		case types.OverloadPolicyShedNewest:
+			shed = job
+		case types.OverloadPolicyShedOldest:
+			shed = p.removeOldest(job)
+		case types.OverloadPolicyShedByPriority:
+			shed = p.removeLowestPriority(job)
+		default:
+			p.notFull.Wait()
+		}
+	}
+
+	if p.closed {
+		p.mu.Unlock()
+		return false
+	}
+
+	if shed != nil {
+		p.dropped++
+		p.droppedByEventType[shed.eventType]++
+	}
+	queued := shed != job
+	if queued {
+		p.queue = append(p.queue, job)
+		p.notEmpty.Signal()
+	}
+	p.mu.Unlock()
+
+	if shed != nil && p.onDrop != nil {
+		p.onDrop(shed.eventType)
+	}
+	return queued
+}
+
+// removeOldest removes and returns the oldest queued job that was not acknowledged, or returns
+// incoming if every queued job was
+func (p *workerPool) removeOldest(incoming *workerJob) *workerJob {
+	for i, queued := range p.queue {
+		if !queued.acked {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			return queued
+		}
+	}
+	return incoming
+}
+
+// removeLowestPriority removes and returns the oldest unacknowledged queued job with the lowest
+// priority, or returns incoming if no such job has a lower priority than it
+func (p *workerPool) removeLowestPriority(incoming *workerJob) *workerJob {
+	lowest := -1
+	for i, queued := range p.queue {
+		if queued.acked {
+			continue
+		}
+		if queued.priority < incoming.priority && (lowest == -1 || queued.priority < p.queue[lowest].priority) {
+			lowest = i
+		}
+	}
+	if lowest == -1 {
+		return incoming
+	}
+
+	shed := p.queue[lowest]
+	p.queue = append(p.queue[:lowest], p.queue[lowest+1:]...)
+	return shed
+}
+
+// stats returns a snapshot of the queue and drop counters
+func (p *workerPool) stats() types.ReceiverQueueStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	droppedByEventType := make(map[string]int64, len(p.droppedByEventType))
+	for eventType, count := range p.droppedByEventType {
+		droppedByEventType[eventType] = count
+	}
+	return types.ReceiverQueueStats{
+		Queued:             len(p.queue),
+		Processed:          p.processed,
+		Dropped:            p.dropped,
+		DroppedByEventType: droppedByEventType,
+	}
+}
+
+// wait blocks until all workers have exited
+func (p *workerPool) wait() {
+	p.wg.Wait()
+}
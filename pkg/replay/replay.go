@@ -0,0 +1,176 @@
+// Package replay records incoming Slack events to disk and replays them through an app, so
+// listeners can be iterated on without triggering the events in Slack again.
+//
+// Record events while developing by passing a Recorder as AppOptions.EventRecorder together
+// with DeveloperMode:
+//
+//	recorder, err := replay.NewRecorder("recordings")
+//	app, err := bolt.New(bolt.AppOptions{..., DeveloperMode: true, EventRecorder: recorder})
+//
+// and replay them later, after changing the listeners:
+//
+//	results, err := replay.Dir(ctx, app, "recordings")
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/app"
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// recordingExtension is the file extension of recordings
+const recordingExtension = ".json"
+
+// Recording is an incoming event as it was passed to ProcessEvent
+type Recording struct {
+	Time          time.Time         `json:"time"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	// Body is the raw request body, JSON or form encoded
+	Body        string `json:"body"`
+	RetryNum    int    `json:"retry_num,omitempty"`
+	RetryReason string `json:"retry_reason,omitempty"`
+}
+
+// Result is the outcome of replaying a recording
+type Result struct {
+	// Path is the file the recording was loaded from, if any
+	Path string
+	// Acked reports whether a listener acknowledged the event, with Response
+	Acked    bool
+	Response types.AckResponse
+	// Err is the error ProcessEvent returned
+	Err error
+}
+
+// Recorder writes each event to its own JSON file in a directory
+type Recorder struct {
+	dir string
+}
+
+var _ app.EventRecorder = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder writing to dir, creating it if needed
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir}, nil
+}
+
+// Record writes event to a file named after the time it arrived and its correlation ID, so
+// listing the directory gives the events in arrival order
+func (r *Recorder) Record(ctx context.Context, event types.ReceiverEvent) error {
+	recording := Recording{
+		Time:          time.Now().UTC(),
+		CorrelationID: app.CorrelationIDFromContext(ctx),
+		Headers:       event.Headers,
+		Body:          string(event.Body),
+		RetryNum:      event.RetryNum,
+		RetryReason:   event.RetryReason,
+	}
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	name := recording.Time.Format("20060102T150405.000000000Z")
+	if recording.CorrelationID != "" {
+		name += "-" + recording.CorrelationID
+	}
+	return os.WriteFile(filepath.Join(r.dir, name+recordingExtension), data, 0o600)
+}
+
+// Load reads the recording at path
+func Load(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var recording Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+	}
+	return &recording, nil
+}
+
+// Event passes recording to target's ProcessEvent as a replay, which the app does not record again
+func Event(ctx context.Context, target types.App, recording *Recording) Result {
+	var mu sync.Mutex
+	var result Result
+	event := types.ReceiverEvent{
+		Body:        []byte(recording.Body),
+		Headers:     recording.Headers,
+		RetryNum:    recording.RetryNum,
+		RetryReason: recording.RetryReason,
+		Ack: func(response types.AckResponse) error {
+			mu.Lock()
+			defer mu.Unlock()
+			result.Acked = true
+			result.Response = response
+			return nil
+		},
+	}
+	if recording.CorrelationID != "" {
+		ctx = app.WithCorrelationID(ctx, recording.CorrelationID)
+	}
+
+	err := target.ProcessEvent(app.WithReplay(ctx), event)
+	mu.Lock()
+	defer mu.Unlock()
+	result.Err = err
+	return result
+}
+
+// File replays the recording at path through target
+func File(ctx context.Context, target types.App, path string) (Result, error) {
+	recording, err := Load(path)
+	if err != nil {
+		return Result{Path: path}, err
+	}
+	result := Event(ctx, target, recording)
+	result.Path = path
+	return result, nil
+}
+
+// Dir replays every recording in dir through target in the order they were recorded. Errors
+// returned by ProcessEvent are reported in the results; the error is for recordings that could
+// not be read, or ctx being done.
+func Dir(ctx context.Context, target types.App, dir string) ([]Result, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), recordingExtension) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+
+	results := make([]Result, 0, len(paths))
+	var errs []error
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		result, err := File(ctx, target, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,381 @@
+// Package socketmodesim emulates the Slack Socket Mode server, so Socket Mode apps can be
+// integration-tested and demoed without connecting to Slack. The Server answers
+// apps.connections.open with its own WebSocket URL, greets connections with a hello message,
+// pings them, delivers envelopes and collects their acks, and can ask clients to reconnect:
+//
+//	sim := socketmodesim.New(socketmodesim.Options{})
+//	defer sim.Close()
+//	receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{
+//		AppToken: "xapp-sim",
+//		APIURL:   sim.APIURL(),
+//	})
+//	...
+//	ack, err := sim.SendEvent(ctx, eventCallback)
+package socketmodesim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Envelope types delivered by Slack over Socket Mode
+const (
+	EnvelopeEventsAPI     = "events_api"
+	EnvelopeInteractive   = "interactive"
+	EnvelopeSlashCommands = "slash_commands"
+)
+
+// Disconnect reasons sent by Slack
+const (
+	// DisconnectRefreshRequested asks the client to reconnect, as Slack does every few hours
+	DisconnectRefreshRequested = "refresh_requested"
+	// DisconnectWarning announces the connection will be refreshed soon
+	DisconnectWarning = "warning"
+	// DisconnectLinkDisabled is sent when Socket Mode is turned off for the app
+	DisconnectLinkDisabled = "link_disabled"
+)
+
+// ErrNotConnected is returned when sending an envelope while no client is connected
+var ErrNotConnected = errors.New("socketmodesim: no client connected")
+
+// Options configures a Server
+type Options struct {
+	// AppToken is the app-level token apps.connections.open accepts; any token is accepted when empty
+	AppToken string
+	// AppID is reported in hello messages (default "A0000SIM")
+	AppID string
+	// PingInterval is how often connections are pinged (default 5s). Clients reconnect when they
+	// are not pinged within their own ping interval, 30s by default.
+	PingInterval time.Duration
+	// APIHandler answers Web API calls other than apps.connections.open; by default they succeed
+	// with {"ok": true}
+	APIHandler http.Handler
+}
+
+// Envelope is a Socket Mode request sent to the client
+type Envelope struct {
+	Type string
+	// Payload is encoded as JSON, unless it is already a json.RawMessage
+	Payload                any
+	AcceptsResponsePayload bool
+	RetryAttempt           int
+	RetryReason            string
+}
+
+// Ack is the client's acknowledgement of an envelope
+type Ack struct {
+	EnvelopeID string          `json:"envelope_id"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// Server is a local Socket Mode server
+type Server struct {
+	options Options
+	server  *httptest.Server
+
+	mu       sync.Mutex
+	conns    []*connection
+	opened   int
+	envelope int
+	pending  map[string]chan Ack
+	changed  chan struct{}
+}
+
+// connection is a client WebSocket connection
+type connection struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+	done chan struct{}
+}
+
+// write sends a JSON message on the connection
+func (c *connection) write(message any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(message)
+}
+
+// New starts a Server on a local port
+func New(options Options) *Server {
+	if options.AppID == "" {
+		options.AppID = "A0000SIM"
+	}
+	if options.PingInterval <= 0 {
+		options.PingInterval = 5 * time.Second
+	}
+
+	s := &Server{
+		options: options,
+		pending: make(map[string]chan Ack),
+		changed: make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/apps.connections.open", s.handleOpen)
+	mux.HandleFunc("/api/", s.handleAPI)
+	mux.HandleFunc("/link", s.handleLink)
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// APIURL returns the Web API URL clients open connections with, for
+// SocketModeReceiverOptions.APIURL or slack.OptionAPIURL
+func (s *Server) APIURL() string {
+	return s.server.URL + "/api/"
+}
+
+// Close closes every connection and stops the server
+func (s *Server) Close() {
+	s.Drop()
+	s.server.Close()
+}
+
+// Connected returns the number of open connections
+func (s *Server) Connected() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Opened returns the number of connections opened since the server started, including reconnections
+func (s *Server) Opened() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.opened
+}
+
+// WaitForConnections waits until n connections have been opened since the server started
+func (s *Server) WaitForConnections(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		opened, changed := s.opened, s.changed
+		connected := len(s.conns)
+		s.mu.Unlock()
+		if opened >= n && connected > 0 {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Send delivers an envelope on the most recent connection and waits for its ack
+func (s *Server) Send(ctx context.Context, envelope Envelope) (Ack, error) {
+	payload, ok := envelope.Payload.(json.RawMessage)
+	if !ok {
+		var err error
+		if payload, err = json.Marshal(envelope.Payload); err != nil {
+			return Ack{}, err
+		}
+	}
+
+	s.mu.Lock()
+	if len(s.conns) == 0 {
+		s.mu.Unlock()
+		return Ack{}, ErrNotConnected
+	}
+	conn := s.conns[len(s.conns)-1]
+	s.envelope++
+	id := fmt.Sprintf("sim-envelope-%d", s.envelope)
+	acked := make(chan Ack, 1)
+	s.pending[id] = acked
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+	}()
+
+	if err := conn.write(map[string]any{
+		"envelope_id":              id,
+		"type":                     envelope.Type,
+		"payload":                  payload,
+		"accepts_response_payload": envelope.AcceptsResponsePayload,
+		"retry_attempt":            envelope.RetryAttempt,
+		"retry_reason":             envelope.RetryReason,
+	}); err != nil {
+		return Ack{}, err
+	}
+
+	select {
+	case ack := <-acked:
+		return ack, nil
+	case <-conn.done:
+		return Ack{}, ErrNotConnected
+	case <-ctx.Done():
+		return Ack{}, ctx.Err()
+	}
+}
+
+// SendEvent delivers an Events API payload, such as an event_callback, and waits for its ack
+func (s *Server) SendEvent(ctx context.Context, payload any) (Ack, error) {
+	return s.Send(ctx, Envelope{Type: EnvelopeEventsAPI, Payload: payload})
+}
+
+// SendInteractive delivers an interaction payload, such as block_actions, and waits for its ack
+func (s *Server) SendInteractive(ctx context.Context, payload any) (Ack, error) {
+	return s.Send(ctx, Envelope{Type: EnvelopeInteractive, Payload: payload, AcceptsResponsePayload: true})
+}
+
+// SendSlashCommand delivers a slash command payload and waits for its ack
+func (s *Server) SendSlashCommand(ctx context.Context, payload any) (Ack, error) {
+	return s.Send(ctx, Envelope{Type: EnvelopeSlashCommands, Payload: payload, AcceptsResponsePayload: true})
+}
+
+// Disconnect sends a disconnect message with reason to every connection, as Slack does before
+// refreshing a connection. Clients are expected to close the connection and open a new one.
+func (s *Server) Disconnect(reason string) error {
+	s.mu.Lock()
+	conns := append([]*connection(nil), s.conns...)
+	s.mu.Unlock()
+
+	var errs []error
+	for _, conn := range conns {
+		errs = append(errs, conn.write(map[string]any{
+			"type":       "disconnect",
+			"reason":     reason,
+			"debug_info": map[string]any{"host": "socketmodesim"},
+		}))
+	}
+	return errors.Join(errs...)
+}
+
+// Drop closes every connection without a disconnect message, as when the network fails
+func (s *Server) Drop() {
+	s.mu.Lock()
+	conns := append([]*connection(nil), s.conns...)
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.conn.Close()
+	}
+}
+
+// handleOpen answers apps.connections.open with the WebSocket URL of the server
+func (s *Server) handleOpen(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	switch {
+	case token == "":
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "not_authed"})
+	case s.options.AppToken != "" && token != s.options.AppToken:
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_auth"})
+	default:
+		url := "ws" + strings.TrimPrefix(s.server.URL, "http") + "/link"
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "url": url})
+	}
+}
+
+// handleAPI answers the other Web API methods
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	if s.options.APIHandler != nil {
+		s.options.APIHandler.ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"ok":true}`))
+}
+
+// upgrader accepts WebSocket connections from any origin
+var upgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+// handleLink serves a Socket Mode WebSocket connection
+func (s *Server) handleLink(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn := &connection{conn: ws, done: make(chan struct{})}
+
+	s.mu.Lock()
+	s.conns = append(s.conns, conn)
+	s.opened++
+	numConnections := len(s.conns)
+	s.mu.Unlock()
+
+	defer s.remove(conn)
+
+	if err := conn.write(map[string]any{
+		"type":            "hello",
+		"num_connections": numConnections,
+		"debug_info":      map[string]any{"host": "socketmodesim"},
+		"connection_info": map[string]any{"app_id": s.options.AppID},
+	}); err != nil {
+		return
+	}
+	s.notify()
+
+	go s.ping(conn)
+	for {
+		_, message, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var ack Ack
+		if json.Unmarshal(message, &ack) != nil || ack.EnvelopeID == "" {
+			continue
+		}
+		s.mu.Lock()
+		acked, ok := s.pending[ack.EnvelopeID]
+		s.mu.Unlock()
+		if ok {
+			select {
+			case acked <- ack:
+			default:
+			}
+		}
+	}
+}
+
+// ping pings conn until it is closed
+func (s *Server) ping(conn *connection) {
+	ticker := time.NewTicker(s.options.PingInterval)
+	defer ticker.Stop()
+	for {
+		if err := conn.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-conn.done:
+			return
+		}
+	}
+}
+
+// remove forgets a closed connection
+func (s *Server) remove(conn *connection) {
+	_ = conn.conn.Close()
+	close(conn.done)
+
+	s.mu.Lock()
+	for i, c := range s.conns {
+		if c == conn {
+			s.conns = append(s.conns[:i], s.conns[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// notify wakes up WaitForConnections
+func (s *Server) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
@@ -0,0 +1,161 @@
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Ngrok starts tunnels with the ngrok agent, which must be installed and authenticated, see
+// https://ngrok.com/download
+type Ngrok struct {
+	// Path is the ngrok executable (default "ngrok", looked up in PATH)
+	Path string
+	// AuthToken overrides the agent's configured authtoken
+	AuthToken string
+	// URL requests a reserved domain or URL, such as "my-app.ngrok.app"
+	URL string
+	// Args are passed to "ngrok http" after the other options
+	Args []string
+	// StartTimeout is how long to wait for the tunnel to come up (default 30s)
+	StartTimeout time.Duration
+}
+
+var _ Provider = (*Ngrok)(nil)
+
+// ngrokLog is a line of the agent's JSON log
+type ngrokLog struct {
+	Msg string `json:"msg"`
+	URL string `json:"url"`
+	Err string `json:"err"`
+	Lvl string `json:"lvl"`
+}
+
+// Start runs "ngrok http addr" and waits for the agent to report the tunnel's public URL
+func (n *Ngrok) Start(ctx context.Context, addr string) (Tunnel, error) {
+	path := n.Path
+	if path == "" {
+		path = "ngrok"
+	}
+	args := []string{"http", addr, "--log", "stdout", "--log-format", "json"}
+	if n.AuthToken != "" {
+		args = append(args, "--authtoken", n.AuthToken)
+	}
+	if n.URL != "" {
+		args = append(args, "--url", n.URL)
+	}
+	args = append(args, n.Args...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := exec.CommandContext(ctx, path, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("starting ngrok: %w", err)
+	}
+
+	t := &ngrokTunnel{cmd: cmd, cancel: cancel, exited: make(chan struct{})}
+	started := make(chan string, 1)
+	failures := make(chan string, 1)
+	go func() {
+		// Wait closes stdout, so the log must be read to the end first
+		t.readLog(stdout, started, failures)
+		t.waitErr = cmd.Wait()
+		close(t.exited)
+	}()
+
+	timeout := n.StartTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var lastErr string
+	for {
+		select {
+		case url := <-started:
+			t.url = url
+			return t, nil
+		case failure := <-failures:
+			lastErr = failure
+		case <-t.exited:
+			select {
+			case failure := <-failures:
+				lastErr = failure
+			default:
+			}
+			if lastErr == "" {
+				lastErr = fmt.Sprint(t.waitErr)
+			}
+			return nil, fmt.Errorf("ngrok exited before starting the tunnel: %s", lastErr)
+		case <-timer.C:
+			_ = t.Close()
+			return nil, errors.New("timed out waiting for ngrok to start the tunnel")
+		case <-ctx.Done():
+			_ = t.Close()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// ngrokTunnel is a tunnel run by an ngrok agent process
+type ngrokTunnel struct {
+	url     string
+	cmd     *exec.Cmd
+	cancel  context.CancelFunc
+	exited  chan struct{}
+	waitErr error
+
+	closeOnce sync.Once
+}
+
+// URL returns the tunnel's public URL
+func (t *ngrokTunnel) URL() string {
+	return t.url
+}
+
+// Close stops the ngrok agent
+func (t *ngrokTunnel) Close() error {
+	t.closeOnce.Do(func() {
+		t.cancel()
+		<-t.exited
+	})
+	return nil
+}
+
+// readLog reports the tunnel URL and errors from the agent's log, then drains it
+func (t *ngrokTunnel) readLog(stdout io.Reader, started, failures chan<- string) {
+	scanner := bufio.NewScanner(stdout)
+	reported := false
+	for scanner.Scan() {
+		var line ngrokLog
+		if json.Unmarshal(scanner.Bytes(), &line) != nil {
+			continue
+		}
+		switch {
+		case !reported && line.Msg == "started tunnel" && line.URL != "":
+			reported = true
+			started <- line.URL
+		case line.Lvl == "crit" || line.Lvl == "eror":
+			msg := line.Err
+			if msg == "" {
+				msg = line.Msg
+			}
+			select {
+			case failures <- msg:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Package tunnel exposes a local HTTP receiver on a public URL while developing, so Slack can
+// deliver events to it. Pass a Provider as AppOptions.Tunnel together with DeveloperMode; the app
+// starts the tunnel when it starts and logs the public request URLs to configure in Slack.
+package tunnel
+
+import "context"
+
+// Provider starts tunnels
+type Provider interface {
+	// Start exposes addr, such as "localhost:3000", on a public URL until the tunnel is closed
+	// or ctx is done
+	Start(ctx context.Context, addr string) (Tunnel, error)
+}
+
+// Tunnel is a running tunnel
+type Tunnel interface {
+	// URL is the public URL forwarding to the local address, e.g. "https://abc.ngrok.app"
+	URL() string
+	// Close stops the tunnel
+	Close() error
+}
@@ -96,8 +96,57 @@ type SlackActionMiddlewareArgs struct {
 	Respond RespondFn          `json:"-"`
 	Ack     AckFn[interface{}] `json:"-"`
 	Say     SayFn              `json:"-"` // Optional, only for actions with channel context
+	// UpdateMessage and DeleteMessage change the message containing the action with chat.update
+	// and chat.delete, for when respond() cannot, e.g. after the response_url expired
+	UpdateMessage UpdateMessageFn `json:"-"`
+	DeleteMessage DeleteMessageFn `json:"-"`
+	// View is the modal or App Home view the action was taken in, with the current state of its
+	// inputs, or nil when the action was not taken in a view
+	View *slack.View `json:"view,omitempty"`
+	// OpenView and PushView open or push a view with the trigger_id of the action, and UpdateView
+	// updates the view the action was taken in, guarded by its hash
+	OpenView   ViewFn `json:"-"`
+	PushView   ViewFn `json:"-"`
+	UpdateView ViewFn `json:"-"`
 }
 
+// ViewStateValue returns the current value of the input with blockID and actionID in the view the
+// action was taken in, so listeners can read other inputs before the view is submitted
+func (a SlackActionMiddlewareArgs) ViewStateValue(blockID, actionID string) (slack.BlockAction, bool) {
+	if a.View == nil || a.View.State == nil {
+		return slack.BlockAction{}, false
+	}
+	value, ok := a.View.State.Values[blockID][actionID]
+	return value, ok
+}
+
+// UpdateMessageArguments represents arguments for the update message function. Blocks and
+// Attachments replace those of the message when set.
+type UpdateMessageArguments struct {
+	Text        string             `json:"text,omitempty"`
+	Blocks      []slack.Block      `json:"blocks,omitempty"`
+	Attachments []slack.Attachment `json:"attachments,omitempty"`
+}
+
+// UpdateMessage represents the union type for UpdateMessageFn parameter: string | UpdateMessageArguments
+type UpdateMessage interface {
+	isUpdateMessage()
+}
+
+// String message implementation
+type UpdateMessageString string
+
+func (u UpdateMessageString) isUpdateMessage() {}
+
+// UpdateMessageArguments message implementation
+func (u UpdateMessageArguments) isUpdateMessage() {}
+
+// UpdateMessageFn represents a function to update the message containing an action
+type UpdateMessageFn func(message UpdateMessage) error
+
+// DeleteMessageFn represents a function to delete the message containing an action
+type DeleteMessageFn func() error
+
 // DialogValidation represents validation errors for dialog submissions
 type DialogValidation struct {
 	Errors []DialogFieldError `json:"errors"`
@@ -0,0 +1,249 @@
+package types
+
+import (
+	"context"
+	"errors"
+
+	"github.com/slack-go/slack"
+
+	"github.com/Asafrose/bolt-go/pkg/pagination"
+)
+
+// ErrNoToken is returned by API methods when the event was authorized without a token they can use
+var ErrNoToken = errors.New("no token available for this call")
+
+// API calls Slack Web API methods with the context of the event being processed, so calls are
+// canceled with it and carry its trace, and with the token the event was authorized with. Most
+// methods use the bot token and fall back to the user token; methods only available to users,
+// such as search, prefer the user token. Bot and User give access to the other methods.
+type API struct {
+	ctx  context.Context
+	bot  *slack.Client
+	user *slack.Client
+}
+
+// NewAPI returns an API calling methods with ctx and the bot or user client; either may be nil
+// when the event was not authorized with that kind of token
+func NewAPI(ctx context.Context, bot, user *slack.Client) *API {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &API{ctx: ctx, bot: bot, user: user}
+}
+
+// Context returns the context of the event calls are made with
+func (a *API) Context() context.Context {
+	return a.ctx
+}
+
+// Bot returns the client using the bot token, or nil when there is none
+func (a *API) Bot() *slack.Client {
+	return a.bot
+}
+
+// User returns the client using the user token, or nil when there is none
+func (a *API) User() *slack.Client {
+	return a.user
+}
+
+// Pages returns iterators over the pages of list methods called with the bot token, or the user
+// token when there is no bot token
+func (a *API) Pages() *pagination.Client {
+	return pagination.New(a.botFirst())
+}
+
+// botFirst returns the bot client, or the user client when there is none
+func (a *API) botFirst() *slack.Client {
+	if a.bot != nil {
+		return a.bot
+	}
+	return a.user
+}
+
+// userFirst returns the user client, or the bot client when there is none
+func (a *API) userFirst() *slack.Client {
+	if a.user != nil {
+		return a.user
+	}
+	return a.bot
+}
+
+// PostMessage calls chat.postMessage and returns the channel and timestamp of the message
+func (a *API) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	client := a.botFirst()
+	if client == nil {
+		return "", "", ErrNoToken
+	}
+	return client.PostMessageContext(a.ctx, channelID, options...)
+}
+
+// PostEphemeral calls chat.postEphemeral and returns the timestamp of the message
+func (a *API) PostEphemeral(channelID, userID string, options ...slack.MsgOption) (string, error) {
+	client := a.botFirst()
+	if client == nil {
+		return "", ErrNoToken
+	}
+	return client.PostEphemeralContext(a.ctx, channelID, userID, options...)
+}
+
+// UpdateMessage calls chat.update and returns the channel, timestamp and text of the message
+func (a *API) UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	client := a.botFirst()
+	if client == nil {
+		return "", "", "", ErrNoToken
+	}
+	return client.UpdateMessageContext(a.ctx, channelID, timestamp, options...)
+}
+
+// DeleteMessage calls chat.delete and returns the channel and timestamp of the message
+func (a *API) DeleteMessage(channelID, timestamp string) (string, string, error) {
+	client := a.botFirst()
+	if client == nil {
+		return "", "", ErrNoToken
+	}
+	return client.DeleteMessageContext(a.ctx, channelID, timestamp)
+}
+
+// ScheduleMessage calls chat.scheduleMessage and returns the channel and scheduled message ID
+func (a *API) ScheduleMessage(channelID, postAt string, options ...slack.MsgOption) (string, string, error) {
+	client := a.botFirst()
+	if client == nil {
+		return "", "", ErrNoToken
+	}
+	return client.ScheduleMessageContext(a.ctx, channelID, postAt, options...)
+}
+
+// GetPermalink calls chat.getPermalink
+func (a *API) GetPermalink(params *slack.PermalinkParameters) (string, error) {
+	client := a.botFirst()
+	if client == nil {
+		return "", ErrNoToken
+	}
+	return client.GetPermalinkContext(a.ctx, params)
+}
+
+// AddReaction calls reactions.add
+func (a *API) AddReaction(name string, item slack.ItemRef) error {
+	client := a.botFirst()
+	if client == nil {
+		return ErrNoToken
+	}
+	return client.AddReactionContext(a.ctx, name, item)
+}
+
+// RemoveReaction calls reactions.remove
+func (a *API) RemoveReaction(name string, item slack.ItemRef) error {
+	client := a.botFirst()
+	if client == nil {
+		return ErrNoToken
+	}
+	return client.RemoveReactionContext(a.ctx, name, item)
+}
+
+// OpenView calls views.open
+func (a *API) OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.OpenViewContext(a.ctx, triggerID, view)
+}
+
+// PushView calls views.push
+func (a *API) PushView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.PushViewContext(a.ctx, triggerID, view)
+}
+
+// UpdateView calls views.update
+func (a *API) UpdateView(view slack.ModalViewRequest, externalID, hash, viewID string) (*slack.ViewResponse, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.UpdateViewContext(a.ctx, view, externalID, hash, viewID)
+}
+
+// PublishView calls views.publish
+func (a *API) PublishView(request slack.PublishViewContextRequest) (*slack.ViewResponse, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.PublishViewContext(a.ctx, request)
+}
+
+// GetConversationInfo calls conversations.info
+func (a *API) GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.GetConversationInfoContext(a.ctx, input)
+}
+
+// GetConversationHistory calls conversations.history; see Pages to follow its cursors
+func (a *API) GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.GetConversationHistoryContext(a.ctx, params)
+}
+
+// GetConversationReplies calls conversations.replies
+func (a *API) GetConversationReplies(params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, false, "", ErrNoToken
+	}
+	return client.GetConversationRepliesContext(a.ctx, params)
+}
+
+// OpenConversation calls conversations.open
+func (a *API) OpenConversation(params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, false, false, ErrNoToken
+	}
+	return client.OpenConversationContext(a.ctx, params)
+}
+
+// GetUserInfo calls users.info
+func (a *API) GetUserInfo(userID string) (*slack.User, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.GetUserInfoContext(a.ctx, userID)
+}
+
+// UploadFile uploads a file with files.getUploadURLExternal and files.completeUploadExternal
+func (a *API) UploadFile(params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	client := a.botFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.UploadFileV2Context(a.ctx, params)
+}
+
+// SearchMessages calls search.messages, which requires a user token
+func (a *API) SearchMessages(query string, params slack.SearchParameters) (*slack.SearchMessages, error) {
+	client := a.userFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.SearchMessagesContext(a.ctx, query, params)
+}
+
+// SearchFiles calls search.files, which requires a user token
+func (a *API) SearchFiles(query string, params slack.SearchParameters) (*slack.SearchFiles, error) {
+	client := a.userFirst()
+	if client == nil {
+		return nil, ErrNoToken
+	}
+	return client.SearchFilesContext(a.ctx, query, params)
+}
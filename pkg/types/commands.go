@@ -35,3 +35,6 @@ type CommandResponse struct {
 	Blocks       []slack.Block      `json:"blocks,omitempty"`
 	Attachments  []slack.Attachment `json:"attachments,omitempty"`
 }
+
+// CommandResponse is sent as the JSON body of the acknowledgement
+func (r CommandResponse) isAckResponse() {}
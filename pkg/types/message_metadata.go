@@ -0,0 +1,62 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/slack-go/slack"
+)
+
+// SayMetadata represents message metadata that can be attached to a say call: a
+// slack.SlackMetadata or *slack.SlackMetadata, or a SlackMetadataConverter such as MessageMetadata
+type SayMetadata interface{}
+
+// SlackMetadataConverter is message metadata converting itself into the shape expected by
+// chat.postMessage
+type SlackMetadataConverter interface {
+	ToSlackMetadata() (slack.SlackMetadata, error)
+}
+
+// MessageMetadata represents message metadata with a strongly typed payload
+type MessageMetadata[T any] struct {
+	EventType    string `json:"event_type"`
+	EventPayload T      `json:"event_payload"`
+}
+
+// ToSlackMetadata converts the metadata into the shape expected by chat.postMessage
+func (m MessageMetadata[T]) ToSlackMetadata() (slack.SlackMetadata, error) {
+	payloadBytes, err := json.Marshal(m.EventPayload)
+	if err != nil {
+		return slack.SlackMetadata{}, fmt.Errorf("failed to marshal metadata payload: %w", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return slack.SlackMetadata{}, fmt.Errorf("metadata payload must be a JSON object: %w", err)
+	}
+
+	return slack.SlackMetadata{
+		EventType:    m.EventType,
+		EventPayload: payload,
+	}, nil
+}
+
+// MessageMetadataEvent represents a message_metadata_posted, message_metadata_updated
+// or message_metadata_deleted event with a strongly typed payload
+type MessageMetadataEvent[T any] struct {
+	Type             string              `json:"type"`
+	AppID            string              `json:"app_id,omitempty"`
+	BotID            string              `json:"bot_id,omitempty"`
+	UserID           string              `json:"user_id,omitempty"`
+	TeamID           string              `json:"team_id,omitempty"`
+	ChannelID        string              `json:"channel_id"`
+	MessageTS        string              `json:"message_ts"`
+	EventTS          string              `json:"event_ts"`
+	DeletedTS        string              `json:"deleted_ts,omitempty"`
+	Metadata         *MessageMetadata[T] `json:"metadata,omitempty"`
+	PreviousMetadata *MessageMetadata[T] `json:"previous_metadata,omitempty"`
+}
+
+func (e *MessageMetadataEvent[T]) GetType() string {
+	return e.Type
+}
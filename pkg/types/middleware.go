@@ -1,10 +1,19 @@
 package types
 
 import (
-	"log/slog"
+	"context"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/slack-go/slack"
+
+	"github.com/Asafrose/bolt-go/pkg/admin"
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/pagination"
 )
 
 // StringIndexed represents a map with string keys and any values
@@ -19,6 +28,8 @@ type Context struct {
 	BotToken string `json:"bot_token,omitempty"`
 	// A user token, which starts with `xoxp-`
 	UserToken string `json:"user_token,omitempty"`
+	// Scopes granted to the user token, if the authorize function reported them
+	UserScopes []string `json:"user_scopes,omitempty"`
 	// This app's bot ID in the installed workspace
 	BotID string `json:"bot_id,omitempty"`
 	// This app's bot user ID in the installed workspace
@@ -31,6 +42,10 @@ type Context struct {
 	EnterpriseID string `json:"enterprise_id,omitempty"`
 	// Is the app installed at an Enterprise level?
 	IsEnterpriseInstall bool `json:"is_enterprise_install"`
+	// Web API URL of the installation, when the authorize function set one
+	APIURL string `json:"api_url,omitempty"`
+	// Client options of the installation, when the authorize function set them
+	ClientOptions []slack.Option `json:"-"`
 	// A JIT and function-specific token
 	FunctionBotAccessToken string `json:"function_bot_access_token,omitempty"`
 	// Function execution ID associated with the event
@@ -41,6 +56,14 @@ type Context struct {
 	RetryNum int `json:"retry_num,omitempty"`
 	// Retry reason of an Events API request
 	RetryReason string `json:"retry_reason,omitempty"`
+	// Raw body of the incoming request, exactly as received
+	RawBody []byte `json:"-"`
+	// Headers of the incoming request
+	Headers map[string]string `json:"-"`
+	// Correlation ID of the event, also attached to log lines and Slack API calls made for it
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// Submatches of the RegExp pattern that matched the event for the running listener, if any
+	Matches []string `json:"matches,omitempty"`
 
 	// Conversation context fields
 	Conversation       any                  `json:"conversation,omitempty"`
@@ -48,17 +71,212 @@ type Context struct {
 
 	// Custom properties
 	Custom StringIndexed `json:"custom,omitempty"`
+
+	// regexpMatches caches RegExp results computed while processing this request
+	regexpMatches map[regexpMatchKey][]string
+}
+
+// regexpMatchKey identifies a RegExp run against a piece of text
+type regexpMatchKey struct {
+	pattern *regexp.Regexp
+	text    string
+}
+
+// FindStringSubmatch returns pattern.FindStringSubmatch(text), reusing the result when the same
+// pattern was already run against text while processing this request
+func (c *Context) FindStringSubmatch(pattern *regexp.Regexp, text string) []string {
+	key := regexpMatchKey{pattern: pattern, text: text}
+	if matches, exists := c.regexpMatches[key]; exists {
+		return matches
+	}
+
+	matches := pattern.FindStringSubmatch(text)
+	if c.regexpMatches == nil {
+		c.regexpMatches = make(map[regexpMatchKey][]string)
+	}
+	c.regexpMatches[key] = matches
+	return matches
+}
+
+// SetMatches records the submatches of the pattern that matched the event in Matches and Custom["matches"]
+func (c *Context) SetMatches(matches []string) {
+	c.Matches = matches
+	if matches == nil {
+		return
+	}
+	if c.Custom == nil {
+		c.Custom = make(StringIndexed)
+	}
+	c.Custom["matches"] = matches
+}
+
+// Reset clears c so it can be reused for another request, keeping the storage of its Custom map
+func (c *Context) Reset() {
+	custom := c.Custom
+	clear(custom)
+	regexpMatches := c.regexpMatches
+	clear(regexpMatches)
+	*c = Context{Custom: custom, regexpMatches: regexpMatches}
 }
 
-// NextFn represents the next function in middleware chain
+// Clone returns a copy of c with its own Custom map and RegExp match cache, so the copy can be
+// used concurrently with c
+func (c *Context) Clone() *Context {
+	clone := *c
+	clone.Custom = maps.Clone(c.Custom)
+	clone.regexpMatches = maps.Clone(c.regexpMatches)
+	return &clone
+}
+
+// NextFn runs the rest of the middleware chain and returns its error. Global middleware runs
+// first, then the listener's middleware in the order they were passed, the last one being the
+// handler. A middleware that returns without calling Next short-circuits the chain: the
+// middleware after it do not run and, unless it returns an error, the event is handled without
+// error. Code after Next runs once the rest of the chain returned. Next runs the rest of the
+// chain at most once; later calls return a MultipleNextCallError.
 type NextFn func() error
 
 // AllMiddlewareArgs contains common arguments for all middleware
 type AllMiddlewareArgs struct {
 	Context *Context      `json:"context"`
-	Logger  *slog.Logger  `json:"logger"`
+	Logger  Logger        `json:"logger"`
 	Client  *slack.Client `json:"client"`
 	Next    NextFn        `json:"-"`
+	// Ctx is the context the receiver processes the event with, carrying its correlation ID. It
+	// is done when the receiver gives up on the event, e.g. when the HTTP request is closed or a
+	// Lambda invocation reaches its deadline, so listeners should pass it to the calls they make.
+	Ctx context.Context `json:"-"`
+
+	// api and adminOptions are set by the App for the event being processed
+	api          *API
+	adminOptions admin.Options
+}
+
+// BaseArgs returns the args common to all middleware. Listener args embed AllMiddlewareArgs, so
+// they all implement ListenerArgs through it.
+func (a AllMiddlewareArgs) BaseArgs() AllMiddlewareArgs {
+	return a
+}
+
+// ListenerArgs is implemented by the args of every kind of listener
+type ListenerArgs interface {
+	BaseArgs() AllMiddlewareArgs
+}
+
+// RawBody returns the raw body of the incoming request, exactly as received
+func (a AllMiddlewareArgs) RawBody() []byte {
+	if a.Context == nil {
+		return nil
+	}
+	return a.Context.RawBody
+}
+
+// Headers returns the headers of the incoming request
+func (a AllMiddlewareArgs) Headers() map[string]string {
+	if a.Context == nil {
+		return nil
+	}
+	return a.Context.Headers
+}
+
+// API returns the Slack Web API methods called with the event's context and the bot or user
+// token the event was authorized with. Outside of event processing it uses Client.
+func (a AllMiddlewareArgs) API() *API {
+	if a.api != nil {
+		return a.api
+	}
+	return NewAPI(a.ctx(), a.Client, nil)
+}
+
+// ctx returns Ctx, or the background context for args built outside of event processing
+func (a AllMiddlewareArgs) ctx() context.Context {
+	if a.Ctx != nil {
+		return a.Ctx
+	}
+	return context.Background()
+}
+
+// UserClient returns a client acting as the user who installed the app, for methods such as
+// reminders.add or search.messages. It returns a *errors.UserTokenError when the event was
+// authorized without a user token, or when scopes were granted to it and some of the given ones
+// are missing.
+func (a AllMiddlewareArgs) UserClient(scopes ...string) (*slack.Client, error) {
+	user := a.API().User()
+	if user == nil {
+		return nil, bolterrors.NewUserTokenError("no user token for this event: install the app with user scopes and return UserToken from authorize", nil)
+	}
+
+	if err := a.checkUserScopes(scopes); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Admin returns a client for the admin.* methods of the Enterprise Grid organization the app was
+// installed on, calling them with the user token of the org-wide install the event came from. It
+// returns a *errors.UserTokenError when the event did not come from an org-wide install, when
+// there is no user token, or when scopes were granted to it and some of the given ones are
+// missing.
+func (a AllMiddlewareArgs) Admin(scopes ...string) (*admin.Client, error) {
+	if a.Context == nil || !a.Context.IsEnterpriseInstall {
+		return nil, bolterrors.NewUserTokenError("admin methods need the app installed on the organization: the event did not come from an org-wide install", nil)
+	}
+	if a.Context.UserToken == "" {
+		return nil, bolterrors.NewUserTokenError("no user token for this org-wide install: install the app with admin user scopes as an org admin and return UserToken from authorize", nil)
+	}
+	if err := a.checkUserScopes(scopes); err != nil {
+		return nil, err
+	}
+	return admin.New(a.Context.UserToken, a.adminOptions), nil
+}
+
+// checkUserScopes returns an error listing the scopes not granted to the user token, when the
+// authorize function reported the granted ones
+func (a AllMiddlewareArgs) checkUserScopes(scopes []string) error {
+	var granted []string
+	if a.Context != nil {
+		granted = a.Context.UserScopes
+	}
+	if len(granted) == 0 {
+		return nil
+	}
+	var missing []string
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return bolterrors.NewUserTokenError("user token is missing scopes: "+strings.Join(missing, ", "), missing)
+	}
+	return nil
+}
+
+// WithAdminOptions returns a copy of a whose Admin method creates clients with options
+func (a AllMiddlewareArgs) WithAdminOptions(options admin.Options) AllMiddlewareArgs {
+	a.adminOptions = options
+	return a
+}
+
+// HTTPClient returns the HTTP client the App sends the Web API calls slack-go does not cover
+// with, such as admin.* and workflows.* methods, or http.DefaultClient outside of event processing
+func (a AllMiddlewareArgs) HTTPClient() *http.Client {
+	if a.adminOptions.HTTPClient != nil {
+		return a.adminOptions.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// WithAPI returns a copy of a whose API method returns api
+func (a AllMiddlewareArgs) WithAPI(api *API) AllMiddlewareArgs {
+	a.api = api
+	return a
+}
+
+// Pages returns iterators over the pages of Web API list methods called with Client, such as
+// conversations.history, following cursors and waiting out rate limits
+func (a AllMiddlewareArgs) Pages() *pagination.Client {
+	return pagination.New(a.Client)
 }
 
 // Middleware represents a middleware function
@@ -66,12 +284,15 @@ type Middleware[Args any] func(args Args) error
 
 // SayArguments represents arguments for the say function
 type SayArguments struct {
-	Channel     string               `json:"channel,omitempty"`
-	Text        string               `json:"text,omitempty"`
-	Blocks      []slack.Block        `json:"blocks,omitempty"`
-	Attachments []slack.Attachment   `json:"attachments,omitempty"`
-	ThreadTS    string               `json:"thread_ts,omitempty"`
-	Metadata    *slack.SlackMetadata `json:"metadata,omitempty"`
+	Channel     string             `json:"channel,omitempty"`
+	Text        string             `json:"text,omitempty"`
+	Blocks      []slack.Block      `json:"blocks,omitempty"`
+	Attachments []slack.Attachment `json:"attachments,omitempty"`
+	ThreadTS    string             `json:"thread_ts,omitempty"`
+	Metadata    SayMetadata        `json:"metadata,omitempty"`
+	// InChannel posts at the top level of the channel when say defaults to a thread, as it does
+	// for message shortcuts
+	InChannel bool `json:"-"`
 	// Add other ChatPostMessageArguments fields as needed
 }
 
@@ -88,11 +309,15 @@ func (s SayString) isSayMessage() {}
 // SayArguments message implementation
 func (s SayArguments) isSayMessage() {}
 
-// SayResponse represents the response from a say operation
+// SayResponse represents the response from a say operation (chat.postMessage)
 type SayResponse struct {
-	*slack.Channel
-	*slack.Message
+	OK bool `json:"ok"`
+	// Channel the message was posted to
+	Channel string `json:"channel,omitempty"`
+	// Timestamp of the posted message, usable as a thread_ts for follow-ups
 	Timestamp string `json:"ts,omitempty"`
+	// Error code returned by the Slack API (e.g. "channel_not_found"), if any
+	Error string `json:"error,omitempty"`
 }
 
 // SayFn represents a function to send a message
@@ -121,8 +346,16 @@ func (r RespondString) isRespondMessage() {}
 // RespondArguments message implementation
 func (r RespondArguments) isRespondMessage() {}
 
+// RespondResponse represents the result of posting to a response_url
+type RespondResponse struct {
+	// HTTP status code returned by the response_url endpoint
+	StatusCode int `json:"status_code"`
+	// Raw response body returned by the response_url endpoint
+	Body []byte `json:"body,omitempty"`
+}
+
 // RespondFn represents a function to respond to an interaction
-type RespondFn func(message RespondMessage) error
+type RespondFn func(ctx context.Context, message RespondMessage) (*RespondResponse, error)
 
 // Additional AckResponse implementations for middleware-specific types
 func (s SayArguments) isAckResponse()     {} // For commands: string | SayArguments
@@ -43,6 +43,9 @@ type OptionsResponse struct {
 	OptionGroups []OptionGroup `json:"option_groups,omitempty"`
 }
 
+// OptionsResponse is sent as the JSON body of the acknowledgement
+func (r OptionsResponse) isAckResponse() {}
+
 // Option is an alias for the slack SDK's OptionBlockObject
 // This provides built-in validation and proper JSON marshaling
 type Option = slack.OptionBlockObject
@@ -2,14 +2,23 @@ package types
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/slack-go/slack/socketmode"
 
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/logging"
 	"github.com/Asafrose/bolt-go/pkg/oauth"
 )
 
+// Logger is the logging interface used by the app, receivers and listeners. *slog.Logger
+// implements it; see the logging/zap and logging/zerolog packages for other loggers.
+type Logger = logging.Logger
+
 // LogLevel represents logging levels
 type LogLevel int
 
@@ -67,6 +76,43 @@ type ReceiverEvent struct {
 	RetryReason string                           `json:"retry_reason,omitempty"`
 }
 
+// EnvelopeIDHeader carries the envelope ID of requests received over Socket Mode
+const EnvelopeIDHeader = "X-Slack-Envelope-Id"
+
+// ReceiverAuthenticityErrorHandlerArgs describes a request that failed signature verification
+type ReceiverAuthenticityErrorHandlerArgs struct {
+	// Error is a *errors.ReceiverAuthenticityError describing why verification failed
+	Error   error
+	Logger  Logger
+	Body    []byte
+	Headers map[string]string
+}
+
+// ReceiverAuthenticityErrorHandler is called when a receiver rejects a request that failed
+// signature verification, e.g. to alert on possibly spoofed requests
+type ReceiverAuthenticityErrorHandler func(ctx context.Context, args ReceiverAuthenticityErrorHandlerArgs)
+
+// EventCapturer receives each event a receiver is about to pass to the app, e.g. to save it as
+// a test fixture. Errors are logged and do not affect processing.
+type EventCapturer interface {
+	Capture(ctx context.Context, event ReceiverEvent) error
+}
+
+// CredentialUpdate carries rotated credentials to a receiver; empty fields are left unchanged
+type CredentialUpdate struct {
+	SigningSecret string
+	// SigningSecretWindow is how long requests signed with the replaced signing secret are still accepted
+	SigningSecretWindow time.Duration
+	// ClientSecret is the OAuth client secret used by the receiver's installer
+	ClientSecret string
+}
+
+// CredentialUpdater is implemented by receivers whose credentials can be rotated at runtime,
+// see App.UpdateCredentials
+type CredentialUpdater interface {
+	UpdateCredentials(update CredentialUpdate)
+}
+
 // App represents the main app interface that receivers need
 type App interface {
 	ProcessEvent(ctx context.Context, event ReceiverEvent) error
@@ -75,13 +121,33 @@ type App interface {
 // HTTPReceiverOptions represents options for HTTP receiver
 type HTTPReceiverOptions struct {
 	SigningSecret                 string             `json:"signing_secret"`
-	Logger                        *slog.Logger       `json:"logger,omitempty"`
+	Logger                        Logger             `json:"logger,omitempty"`
 	LogLevel                      *LogLevel          `json:"log_level,omitempty"`
 	Endpoints                     *ReceiverEndpoints `json:"endpoints,omitempty"`
 	ProcessBeforeResponse         bool               `json:"process_before_response"`
 	UnhandledRequestHandler       http.HandlerFunc   `json:"-"`
 	UnhandledRequestTimeoutMillis int                `json:"unhandled_request_timeout_millis"`
 	CustomRoutes                  []CustomRoute      `json:"custom_routes,omitempty"`
+	// Port is the port the server listens on (default 3000)
+	Port int `json:"port,omitempty"`
+	// AuthenticityErrorHandler is called for requests that fail signature verification, e.g. to log
+	// or alert on them. They are answered with the ErrorStatusCodes status of authenticity errors
+	// and never reach the app.
+	AuthenticityErrorHandler ReceiverAuthenticityErrorHandler `json:"-"`
+	// SignatureTolerance is how far the X-Slack-Request-Timestamp of a request may be from the
+	// current time, in either direction, for its signature to be accepted, so captured requests
+	// cannot be replayed later (default 5 minutes)
+	SignatureTolerance time.Duration `json:"signature_tolerance,omitempty"`
+	// ErrorStatusCodes overrides the status codes returned for failed requests
+	ErrorStatusCodes *HTTPErrorStatusCodes `json:"error_status_codes,omitempty"`
+	// Capture receives a copy of every verified event, see the capture package
+	Capture EventCapturer `json:"-"`
+	// Mirror sends a copy of every verified request to downstream endpoints, e.g. for shadow testing
+	Mirror *MirrorOptions `json:"-"`
+	// EndpointMiddleware wraps the handlers of the endpoints, keyed by path, with net/http
+	// middleware such as rate limiting for the commands endpoint. The first middleware of a path
+	// runs first. It does not apply to the shared endpoints of a MultiAppReceiver.
+	EndpointMiddleware map[string][]func(http.Handler) http.Handler `json:"-"`
 	// Custom properties
 	CustomProperties map[string]interface{} `json:"custom_properties,omitempty"`
 
@@ -95,7 +161,98 @@ type HTTPReceiverOptions struct {
 	InstallerOptions  *InstallerOptions       `json:"installer_options,omitempty"`
 }
 
-// ReceiverEndpoints represents custom endpoints for receivers
+// MultiAppReceiverOptions represents options for a receiver serving several Slack apps from one
+// HTTP server
+type MultiAppReceiverOptions struct {
+	// Port is the port the server listens on (default 3000)
+	Port     int       `json:"port,omitempty"`
+	Logger   Logger    `json:"logger,omitempty"`
+	LogLevel *LogLevel `json:"log_level,omitempty"`
+	// Endpoints are the paths shared by the apps hosted without a PathPrefix. Requests on them
+	// are routed to the app whose signing secret verifies them (default /slack/events).
+	Endpoints *ReceiverEndpoints `json:"endpoints,omitempty"`
+	// CustomRoutes are served next to the apps' endpoints
+	CustomRoutes []CustomRoute `json:"custom_routes,omitempty"`
+}
+
+// HostedAppOptions represents options for one app served by a multi-app receiver
+type HostedAppOptions struct {
+	// Name identifies the app in logs
+	Name string `json:"name"`
+	// PathPrefix serves the app's endpoints, install paths and custom routes under the prefix,
+	// e.g. "/acme" serves "/acme/slack/events". Apps without a prefix share the receiver's
+	// endpoints.
+	PathPrefix string `json:"path_prefix,omitempty"`
+	// HTTPReceiverOptions configures the app's signing secret, endpoints and OAuth installer;
+	// Port is ignored
+	HTTPReceiverOptions
+}
+
+// MirrorOptions mirrors verified requests to downstream HTTP endpoints, such as a new version of
+// an app shadow-tested against production traffic. Requests are sent in the background after
+// they were verified, and the responses of the targets are discarded.
+type MirrorOptions struct {
+	// Targets receive a copy of every verified request
+	Targets []MirrorTarget `json:"targets"`
+	// Client sends the requests (default a client with a 10 second timeout)
+	Client *http.Client `json:"-"`
+	// BufferSize is the number of requests queued per target while it is busy (default 256).
+	// Requests arriving while the queue is full are dropped and logged, so a slow target never
+	// delays the responses to Slack.
+	BufferSize int `json:"buffer_size,omitempty"`
+}
+
+// MirrorTarget is a downstream endpoint receiving mirrored requests
+type MirrorTarget struct {
+	// URL is the base URL of the target; the path each request was received on is appended to
+	// it, so "https://shadow.example.com" receives requests to /slack/events on
+	// "https://shadow.example.com/slack/events"
+	URL string `json:"url"`
+	// SigningSecret re-signs requests for a target verifying them with another app's signing
+	// secret. When empty, Slack's original signature and timestamp are passed through.
+	SigningSecret string `json:"-"`
+}
+
+// HTTPErrorStatusCodes maps classes of bolt errors to the HTTP status codes returned by the HTTP receiver.
+// Zero values fall back to the defaults. A 2xx code acknowledges the request so Slack does not retry it.
+type HTTPErrorStatusCodes struct {
+	// Authenticity is used for requests that fail signature verification (default 401)
+	Authenticity int `json:"authenticity,omitempty"`
+	// Authorization is used when the authorize function fails (default 401)
+	Authorization int `json:"authorization,omitempty"`
+	// ProcessingFailure is used for any other error returned before the request was acknowledged (default 500)
+	ProcessingFailure int `json:"processing_failure,omitempty"`
+	// Retryable is used for errors marked with errors.NewRetryableError (default 500).
+	// Errors marked with errors.NewNonRetryableError are always acknowledged with 200.
+	Retryable int `json:"retryable,omitempty"`
+}
+
+// StatusCode returns the status code configured for the class of err
+func (c HTTPErrorStatusCodes) StatusCode(err error) int {
+	switch {
+	case errors.Is(err, bolterrors.ReceiverAuthenticityErrorCode):
+		return statusCodeOrDefault(c.Authenticity, http.StatusUnauthorized)
+	case bolterrors.IsRetryable(err):
+		return statusCodeOrDefault(c.Retryable, http.StatusInternalServerError)
+	case bolterrors.IsNonRetryable(err):
+		return http.StatusOK
+	case errors.Is(err, bolterrors.AuthorizationErrorCode):
+		return statusCodeOrDefault(c.Authorization, http.StatusUnauthorized)
+	default:
+		return statusCodeOrDefault(c.ProcessingFailure, http.StatusInternalServerError)
+	}
+}
+
+func statusCodeOrDefault(code, defaultCode int) int {
+	if code == 0 {
+		return defaultCode
+	}
+	return code
+}
+
+// ReceiverEndpoints represents custom endpoints for receivers. Each payload type may be served on
+// its own path, matching the Request URLs configured for the app; empty paths default to Events,
+// or /slack/events when Events is empty too.
 type ReceiverEndpoints struct {
 	Events      string `json:"events"`
 	Interactive string `json:"interactive"`
@@ -103,6 +260,17 @@ type ReceiverEndpoints struct {
 	Options     string `json:"options"`
 }
 
+// Paths returns the distinct non-empty paths of the endpoints
+func (e ReceiverEndpoints) Paths() []string {
+	var paths []string
+	for _, path := range []string{e.Events, e.Interactive, e.Commands, e.Options} {
+		if path != "" && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
 // CustomRoute represents a custom route
 type CustomRoute struct {
 	Path    string           `json:"path"`
@@ -128,19 +296,73 @@ type InstallerOptions struct {
 	Metadata                     map[string]interface{}                               `json:"metadata,omitempty"`
 	UserScopes                   []string                                             `json:"user_scopes,omitempty"`
 	AuthorizationURL             string                                               `json:"authorization_url,omitempty"`
+	// RenderInstallPage returns the HTML of the install page linking to installURL, e.g. a
+	// branded "Add to Slack" page; it replaces RenderHtmlForInstallPath
+	RenderInstallPage func(installURL string, req *http.Request) string `json:"-"`
+	// CallbackSuccess answers the OAuth redirect once the installation was stored, e.g. with a
+	// branded page; it replaces SuccessRedirectURL and CallbackOptions.Success
+	CallbackSuccess func(w http.ResponseWriter, req *http.Request, installation *oauth.Installation) `json:"-"`
+	// CallbackFailure answers the OAuth redirect when the installation failed, after the error was
+	// logged; it replaces CallbackOptions.Failure
+	CallbackFailure func(w http.ResponseWriter, req *http.Request, err error) `json:"-"`
+	// SuccessRedirectURL is where installers are redirected once the installation was stored,
+	// instead of the default success page
+	SuccessRedirectURL string `json:"success_redirect_url,omitempty"`
 }
 
 // SocketModeReceiverOptions represents options for Socket Mode receiver
 type SocketModeReceiverOptions struct {
 	AppToken                  string                                              `json:"app_token"`
 	BotToken                  string                                              `json:"bot_token"`
-	Logger                    *slog.Logger                                        `json:"logger,omitempty"`
+	Logger                    Logger                                              `json:"logger,omitempty"`
 	LogLevel                  *LogLevel                                           `json:"log_level,omitempty"`
 	PingTimeout               int                                                 `json:"ping_timeout,omitempty"`
 	ClientOptions             []socketmode.Option                                 `json:"client_options,omitempty"`
 	CustomProperties          map[string]interface{}                              `json:"custom_properties,omitempty"`
 	CustomPropertiesExtractor func(map[string]interface{}) map[string]interface{} `json:"-"`
 	CustomRoutes              []CustomRoute                                       `json:"custom_routes,omitempty"`
+	// WorkerPoolSize is the number of goroutines processing envelopes; 0 processes envelopes one at a time
+	WorkerPoolSize int `json:"worker_pool_size,omitempty"`
+	// WorkerQueueSize is the number of envelopes that may wait for a worker (default 100).
+	// Events API envelopes are acknowledged as soon as they are queued, so they are never shed and
+	// are still processed when the receiver stops.
+	WorkerQueueSize int `json:"worker_queue_size,omitempty"`
+	// OverloadPolicy decides what happens when the worker queue is full (default OverloadPolicyBlock)
+	OverloadPolicy OverloadPolicy `json:"overload_policy,omitempty"`
+	// EventTypePriorities ranks event types for OverloadPolicyShedByPriority; higher values are kept longer.
+	// Unlisted event types have priority 0.
+	EventTypePriorities map[string]int `json:"event_type_priorities,omitempty"`
+	// OnEnvelopeDropped is called with the event type of each envelope shed from the queue
+	OnEnvelopeDropped func(eventType string) `json:"-"`
+	// Capture receives a copy of every event, see the capture package
+	Capture EventCapturer `json:"-"`
+	// APIURL overrides the Slack Web API URL used to open connections, e.g. to connect to a
+	// local simulator from the socketmodesim package
+	APIURL string `json:"api_url,omitempty"`
+	// OnConnecting is called before each attempt to open a connection. The connection hooks run
+	// on the goroutine reading envelopes, so they should return quickly.
+	OnConnecting func() `json:"-"`
+	// OnConnected is called with the hello message Slack greets each new connection with
+	OnConnected func(hello SocketModeHello) `json:"-"`
+	// OnDisconnect is called when a connection ends, with the reason Slack gave, e.g.
+	// "refresh_requested" or "link_disabled", or "" when the connection was lost without one. The
+	// reason is read from the debug log of the socketmode client, so it is "" when ClientOptions
+	// set their own socketmode.OptionLog.
+	OnDisconnect func(reason string) `json:"-"`
+	// OnReconnect is called once the receiver starts opening a new connection after one ended
+	OnReconnect func() `json:"-"`
+	// OnReconnecting is called when an attempt to open a connection failed, with the number of
+	// consecutive failed attempts, the delay before the next one and the error, e.g. to alert on a
+	// connection that keeps failing
+	OnReconnecting func(attempt int, delay time.Duration, err error) `json:"-"`
+	// MaxReconnectAttempts is the number of consecutive failed attempts to open a connection after
+	// which Start gives up and returns the last error; 0 retries until the receiver is stopped
+	MaxReconnectAttempts int `json:"max_reconnect_attempts,omitempty"`
+	// ReconnectBackoff returns the delay before the next attempt to open a connection after
+	// attempt failed (default receivers.ExponentialBackoff(100*time.Millisecond, 5*time.Minute)).
+	// Rate limited attempts wait at least the Retry-After of Slack. A new connection is opened right
+	// away when one ends.
+	ReconnectBackoff ReconnectBackoff `json:"-"`
 
 	// OAuth configuration
 	ClientID          string                  `json:"client_id,omitempty"`
@@ -152,12 +374,58 @@ type SocketModeReceiverOptions struct {
 	InstallerOptions  *InstallerOptions       `json:"installer_options,omitempty"`
 }
 
+// ReconnectBackoff returns how long to wait after the attempt-th consecutive failed attempt to
+// open a connection, counted from 1
+type ReconnectBackoff func(attempt int) time.Duration
+
+// SocketModeHello is the hello message Slack greets a Socket Mode connection with
+type SocketModeHello struct {
+	AppID string `json:"app_id"`
+	// NumConnections is the number of connections the app has open, including this one
+	NumConnections int `json:"num_connections"`
+	// Host is the Slack host serving the connection
+	Host string `json:"host"`
+	// ApproximateConnectionTime is the number of seconds after which Slack refreshes the connection
+	ApproximateConnectionTime int `json:"approximate_connection_time"`
+}
+
+// OverloadPolicy controls what a receiver does with new envelopes when its queue is full
+type OverloadPolicy int
+
+const (
+	// OverloadPolicyBlock waits for room in the queue; envelopes left unacknowledged are retried by Slack
+	OverloadPolicyBlock OverloadPolicy = iota
+	// OverloadPolicyShedNewest drops the incoming envelope
+	OverloadPolicyShedNewest
+	// OverloadPolicyShedOldest drops the unacknowledged envelope that has waited longest, or the
+	// incoming envelope if every queued envelope was acknowledged
+	OverloadPolicyShedOldest
+	// OverloadPolicyShedByPriority drops the oldest unacknowledged envelope with the lowest event
+	// type priority, or the incoming envelope if nothing unacknowledged ranks below it
+	OverloadPolicyShedByPriority
+)
+
+// ReceiverQueueStats is a snapshot of a receiver's envelope queue
+type ReceiverQueueStats struct {
+	Queued             int              `json:"queued"`
+	Processed          int64            `json:"processed"`
+	Dropped            int64            `json:"dropped"`
+	DroppedByEventType map[string]int64 `json:"dropped_by_event_type"`
+}
+
 // AwsLambdaReceiverOptions represents options for AWS Lambda receiver
 type AwsLambdaReceiverOptions struct {
 	SigningSecret         string                 `json:"signing_secret"`
-	Logger                *slog.Logger           `json:"logger,omitempty"`
+	Logger                Logger                 `json:"logger,omitempty"`
 	LogLevel              *LogLevel              `json:"log_level,omitempty"`
 	ProcessBeforeResponse bool                   `json:"process_before_response"`
 	SignatureVerification *bool                  `json:"signature_verification,omitempty"`
 	CustomProperties      map[string]interface{} `json:"custom_properties,omitempty"`
+	// AuthenticityErrorHandler is called for requests that fail signature verification
+	AuthenticityErrorHandler ReceiverAuthenticityErrorHandler `json:"-"`
+	// SignatureTolerance is how far the X-Slack-Request-Timestamp of a request may be from the
+	// current time, in either direction, for its signature to be accepted (default 5 minutes)
+	SignatureTolerance time.Duration `json:"signature_tolerance,omitempty"`
+	// Capture receives a copy of every verified event, see the capture package
+	Capture EventCapturer `json:"-"`
 }
@@ -67,4 +67,6 @@ type SlackShortcutMiddlewareArgs struct {
 	Payload  SlackShortcut      `json:"payload"`  // Strongly typed payload
 	Ack      AckFn[interface{}] `json:"-"`
 	Say      *SayFn             `json:"-"` // Optional, only for message shortcuts
+	// OpenView opens a view with the trigger_id of the shortcut
+	OpenView ViewFn `json:"-"`
 }
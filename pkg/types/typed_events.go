@@ -0,0 +1,241 @@
+package types
+
+import "github.com/slack-go/slack"
+
+// TypedEvent is a Slack event decoded into its own struct, such as AppMentionEvent, for listeners
+// registered with app.EventTyped. EventType names the event type the struct decodes and is
+// called on the zero value.
+type TypedEvent interface {
+	SlackEvent
+	EventType() SlackEventType
+}
+
+// AppMentionEvent is an app_mention event, sent when a message mentions the app
+type AppMentionEvent struct {
+	Type     string       `json:"type"`
+	User     string       `json:"user"`
+	BotID    string       `json:"bot_id,omitempty"`
+	Text     string       `json:"text"`
+	Blocks   slack.Blocks `json:"blocks,omitempty"`
+	Channel  string       `json:"channel"`
+	TS       string       `json:"ts"`
+	ThreadTS string       `json:"thread_ts,omitempty"`
+	EventTS  string       `json:"event_ts"`
+}
+
+func (e AppMentionEvent) GetType() string         { return e.Type }
+func (AppMentionEvent) EventType() SlackEventType { return EventTypeAppMention }
+
+// AppHomeOpenedEvent is an app_home_opened event, sent when a user opens a tab of the App Home
+type AppHomeOpenedEvent struct {
+	Type    string `json:"type"`
+	User    string `json:"user"`
+	Channel string `json:"channel"`
+	// Tab is "home" or "messages"
+	Tab string `json:"tab"`
+	// View is the view last published to the user's Home tab, if any
+	View    *slack.View `json:"view,omitempty"`
+	EventTS string      `json:"event_ts"`
+}
+
+func (e AppHomeOpenedEvent) GetType() string         { return e.Type }
+func (AppHomeOpenedEvent) EventType() SlackEventType { return EventTypeAppHomeOpened }
+
+// AppUninstalledEvent is an app_uninstalled event, sent when the app is uninstalled from a workspace
+type AppUninstalledEvent struct {
+	Type    string `json:"type"`
+	EventTS string `json:"event_ts,omitempty"`
+}
+
+func (e AppUninstalledEvent) GetType() string         { return e.Type }
+func (AppUninstalledEvent) EventType() SlackEventType { return EventTypeAppUninstalled }
+
+// ReactionItem is the item a reaction was added to or removed from
+type ReactionItem struct {
+	// Type is "message", "file" or "file_comment"
+	Type        string `json:"type"`
+	Channel     string `json:"channel,omitempty"`
+	TS          string `json:"ts,omitempty"`
+	File        string `json:"file,omitempty"`
+	FileComment string `json:"file_comment,omitempty"`
+}
+
+// ReactionAddedEvent is a reaction_added event
+type ReactionAddedEvent struct {
+	Type     string `json:"type"`
+	User     string `json:"user"`
+	Reaction string `json:"reaction"`
+	// ItemUser is the user who created the item
+	ItemUser string       `json:"item_user,omitempty"`
+	Item     ReactionItem `json:"item"`
+	EventTS  string       `json:"event_ts"`
+}
+
+func (e ReactionAddedEvent) GetType() string         { return e.Type }
+func (ReactionAddedEvent) EventType() SlackEventType { return EventTypeReactionAdded }
+
+// ReactionRemovedEvent is a reaction_removed event
+type ReactionRemovedEvent struct {
+	Type     string `json:"type"`
+	User     string `json:"user"`
+	Reaction string `json:"reaction"`
+	// ItemUser is the user who created the item
+	ItemUser string       `json:"item_user,omitempty"`
+	Item     ReactionItem `json:"item"`
+	EventTS  string       `json:"event_ts"`
+}
+
+func (e ReactionRemovedEvent) GetType() string         { return e.Type }
+func (ReactionRemovedEvent) EventType() SlackEventType { return EventTypeReactionRemoved }
+
+// MemberJoinedChannelEvent is a member_joined_channel event
+type MemberJoinedChannelEvent struct {
+	Type    string `json:"type"`
+	User    string `json:"user"`
+	Channel string `json:"channel"`
+	// ChannelType is "C" for public channels and "G" for private ones
+	ChannelType string `json:"channel_type"`
+	Team        string `json:"team"`
+	// Inviter is the user who added the member, empty when they joined themselves
+	Inviter string `json:"inviter,omitempty"`
+	EventTS string `json:"event_ts"`
+}
+
+func (e MemberJoinedChannelEvent) GetType() string         { return e.Type }
+func (MemberJoinedChannelEvent) EventType() SlackEventType { return EventTypeMemberJoinedChannel }
+
+// MemberLeftChannelEvent is a member_left_channel event
+type MemberLeftChannelEvent struct {
+	Type    string `json:"type"`
+	User    string `json:"user"`
+	Channel string `json:"channel"`
+	// ChannelType is "C" for public channels and "G" for private ones
+	ChannelType string `json:"channel_type"`
+	Team        string `json:"team"`
+	EventTS     string `json:"event_ts"`
+}
+
+func (e MemberLeftChannelEvent) GetType() string         { return e.Type }
+func (MemberLeftChannelEvent) EventType() SlackEventType { return EventTypeMemberLeftChannel }
+
+// ChannelInfo is the channel of channel_created and channel_rename events
+type ChannelInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Created int64  `json:"created,omitempty"`
+	Creator string `json:"creator,omitempty"`
+}
+
+// ChannelCreatedEvent is a channel_created event
+type ChannelCreatedEvent struct {
+	Type    string      `json:"type"`
+	Channel ChannelInfo `json:"channel"`
+	EventTS string      `json:"event_ts,omitempty"`
+}
+
+func (e ChannelCreatedEvent) GetType() string         { return e.Type }
+func (ChannelCreatedEvent) EventType() SlackEventType { return EventTypeChannelCreated }
+
+// ChannelRenameEvent is a channel_rename event; Channel.Name is the new name
+type ChannelRenameEvent struct {
+	Type    string      `json:"type"`
+	Channel ChannelInfo `json:"channel"`
+	EventTS string      `json:"event_ts,omitempty"`
+}
+
+func (e ChannelRenameEvent) GetType() string         { return e.Type }
+func (ChannelRenameEvent) EventType() SlackEventType { return EventTypeChannelRename }
+
+// ChannelArchiveEvent is a channel_archive event
+type ChannelArchiveEvent struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+	User    string `json:"user"`
+	EventTS string `json:"event_ts,omitempty"`
+}
+
+func (e ChannelArchiveEvent) GetType() string         { return e.Type }
+func (ChannelArchiveEvent) EventType() SlackEventType { return EventTypeChannelArchive }
+
+// ChannelUnarchiveEvent is a channel_unarchive event
+type ChannelUnarchiveEvent struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+	User    string `json:"user"`
+	EventTS string `json:"event_ts,omitempty"`
+}
+
+func (e ChannelUnarchiveEvent) GetType() string         { return e.Type }
+func (ChannelUnarchiveEvent) EventType() SlackEventType { return EventTypeChannelUnarchive }
+
+// TeamJoinEvent is a team_join event, sent when a user joins the workspace
+type TeamJoinEvent struct {
+	Type    string     `json:"type"`
+	User    slack.User `json:"user"`
+	EventTS string     `json:"event_ts,omitempty"`
+}
+
+func (e TeamJoinEvent) GetType() string         { return e.Type }
+func (TeamJoinEvent) EventType() SlackEventType { return EventTypeTeamJoin }
+
+// UserChangeEvent is a user_change event, sent when a user's profile or settings change
+type UserChangeEvent struct {
+	Type    string     `json:"type"`
+	User    slack.User `json:"user"`
+	EventTS string     `json:"event_ts,omitempty"`
+}
+
+func (e UserChangeEvent) GetType() string         { return e.Type }
+func (UserChangeEvent) EventType() SlackEventType { return EventTypeUserChange }
+
+// SharedLink is a link of a link_shared event
+type SharedLink struct {
+	Domain string `json:"domain"`
+	URL    string `json:"url"`
+}
+
+// LinkSharedEvent is a link_shared event, sent when a message contains a link to a domain the app
+// unfurls
+type LinkSharedEvent struct {
+	Type      string       `json:"type"`
+	User      string       `json:"user"`
+	Channel   string       `json:"channel"`
+	MessageTS string       `json:"message_ts"`
+	ThreadTS  string       `json:"thread_ts,omitempty"`
+	Links     []SharedLink `json:"links"`
+	// UnfurlID and Source identify links shared in the composer, before the message is sent
+	UnfurlID string `json:"unfurl_id,omitempty"`
+	Source   string `json:"source,omitempty"`
+	EventTS  string `json:"event_ts"`
+}
+
+func (e LinkSharedEvent) GetType() string         { return e.Type }
+func (LinkSharedEvent) EventType() SlackEventType { return EventTypeLinkShared }
+
+// FileSharedEvent is a file_shared event
+type FileSharedEvent struct {
+	Type      string `json:"type"`
+	FileID    string `json:"file_id"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id,omitempty"`
+	EventTS   string `json:"event_ts"`
+}
+
+func (e FileSharedEvent) GetType() string         { return e.Type }
+func (FileSharedEvent) EventType() SlackEventType { return EventTypeFileShared }
+
+// RevokedTokens are the user IDs whose tokens a tokens_revoked event revoked, by token kind
+type RevokedTokens struct {
+	OAuth []string `json:"oauth,omitempty"`
+	Bot   []string `json:"bot,omitempty"`
+}
+
+// TokensRevokedEvent is a tokens_revoked event
+type TokensRevokedEvent struct {
+	Type    string        `json:"type"`
+	Tokens  RevokedTokens `json:"tokens"`
+	EventTS string        `json:"event_ts,omitempty"`
+}
+
+func (e TokensRevokedEvent) GetType() string         { return e.Type }
+func (TokensRevokedEvent) EventType() SlackEventType { return EventTypeTokensRevoked }
@@ -75,11 +75,23 @@ type SlackViewMiddlewareArgs struct {
 	Body    SlackView           `json:"body"`    // Strongly typed view action
 	Payload ViewOutput          `json:"payload"` // Strongly typed payload (same as view)
 	Ack     AckFn[ViewResponse] `json:"-"`
+	// OpenView and PushView open or push a view with the trigger_id of the submission, and
+	// UpdateView updates the submitted view, guarded by its hash
+	OpenView   ViewFn `json:"-"`
+	PushView   ViewFn `json:"-"`
+	UpdateView ViewFn `json:"-"`
 }
 
+// ViewFn represents a function calling views.open, views.push or views.update with the
+// trigger_id or view of the interaction it was created for
+type ViewFn func(view slack.ModalViewRequest) (*slack.ViewResponse, error)
+
 // ViewResponse represents a response to a view submission
 type ViewResponse struct {
 	ResponseAction string                  `json:"response_action,omitempty"` // "clear", "update", "push", "errors"
 	View           *slack.ModalViewRequest `json:"view,omitempty"`
 	Errors         map[string]string       `json:"errors,omitempty"`
 }
+
+// ViewResponse is sent as the JSON body of the acknowledgement
+func (r ViewResponse) isAckResponse() {}
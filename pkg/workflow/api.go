@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/Asafrose/bolt-go/pkg/types"
+)
+
+// stepClient calls the Web API methods of Steps from Apps, which slack-go does not cover
+type stepClient struct {
+	ctx        context.Context
+	token      string
+	httpClient *http.Client
+	apiURL     string
+}
+
+// newStepClient returns a stepClient calling methods with the bot token of the event args
+// were built for
+func newStepClient(args types.AllMiddlewareArgs) *stepClient {
+	c := &stepClient{
+		ctx:        args.API().Context(),
+		httpClient: args.HTTPClient(),
+		apiURL:     slack.APIURL,
+	}
+	if args.Context != nil {
+		c.token = args.Context.BotToken
+	}
+	return c
+}
+
+// call posts params as JSON to method and returns the error Slack answered with, if any
+func (c *stepClient) call(method string, params map[string]interface{}) error {
+	if c.token == "" {
+		return fmt.Errorf("%s: %w", method, types.ErrNoToken)
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, c.apiURL+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64)
+		if err != nil {
+			return err
+		}
+		return &slack.RateLimitedError{RetryAfter: time.Duration(retryAfter) * time.Second}
+	case resp.StatusCode != http.StatusOK:
+		return slack.StatusCodeError{Code: resp.StatusCode, Status: resp.Status}
+	}
+
+	var result slack.SlackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%s: decoding response: %w", method, err)
+	}
+	if !result.Ok {
+		return slack.SlackErrorResponse{Err: result.Error, ResponseMetadata: result.ResponseMetadata}
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package workflow
 
 import (
 	"encoding/json"
+	"sync"
 
 	"github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/slack-go/slack"
 )
@@ -67,26 +69,40 @@ type WorkflowStepSaveMiddleware func(args WorkflowStepSaveMiddlewareArgs) error
 type WorkflowStepExecuteMiddleware func(args WorkflowStepExecuteMiddlewareArgs) error
 
 // Middleware argument types
+
+// WorkflowStepEditMiddlewareArgs are passed to Edit middleware when a user adds or edits the step
+// in Workflow Builder. Step is the payload's workflow_step object and Body the whole payload, as
+// decoded JSON objects. The edit is acknowledged before the middleware runs, so Ack is optional;
+// call Configure to open the step's configuration modal.
 type WorkflowStepEditMiddlewareArgs struct {
 	types.AllMiddlewareArgs
-	Step      interface{}     `json:"step"`
-	Body      interface{}     `json:"body"`
-	Configure StepConfigureFn `json:"-"`
-	Update    StepUpdateFn    `json:"-"`
-	Complete  StepCompleteFn  `json:"-"`
-	Fail      StepFailFn      `json:"-"`
+	Step      interface{}              `json:"step"`
+	Body      interface{}              `json:"body"`
+	Ack       types.AckFn[interface{}] `json:"-"`
+	Configure StepConfigureFn          `json:"-"`
+	Update    StepUpdateFn             `json:"-"`
+	Complete  StepCompleteFn           `json:"-"`
+	Fail      StepFailFn               `json:"-"`
 }
 
+// WorkflowStepSaveMiddlewareArgs are passed to Save middleware when the configuration modal is
+// submitted. Step, Body and View are decoded JSON objects. Call Ack, with errors to show in the
+// modal if the input is invalid, then Update with the step's inputs and outputs. Submissions the
+// middleware does not acknowledge are acknowledged once it returns.
 type WorkflowStepSaveMiddlewareArgs struct {
 	types.AllMiddlewareArgs
-	Step     interface{}    `json:"step"`
-	Body     interface{}    `json:"body"`
-	View     interface{}    `json:"view"`
-	Update   StepUpdateFn   `json:"-"`
-	Complete StepCompleteFn `json:"-"`
-	Fail     StepFailFn     `json:"-"`
+	Step     interface{}                     `json:"step"`
+	Body     interface{}                     `json:"body"`
+	View     interface{}                     `json:"view"`
+	Ack      types.AckFn[types.ViewResponse] `json:"-"`
+	Update   StepUpdateFn                    `json:"-"`
+	Complete StepCompleteFn                  `json:"-"`
+	Fail     StepFailFn                      `json:"-"`
 }
 
+// WorkflowStepExecuteMiddlewareArgs are passed to Execute middleware when a workflow runs the
+// step. Step, Body and Event are decoded JSON objects. The event is acknowledged before the
+// middleware runs; call Complete or Fail once the step's work is done.
 type WorkflowStepExecuteMiddlewareArgs struct {
 	types.AllMiddlewareArgs
 	Step     interface{}    `json:"step"`
@@ -141,106 +157,194 @@ func (ws *WorkflowStep) GetMiddleware() types.Middleware[types.AllMiddlewareArgs
 	}
 }
 
-// processEvent processes workflow step events
+// processEvent runs the step's middleware for its edit, save and execute requests, and passes
+// other requests on
 func (ws *WorkflowStep) processEvent(args types.AllMiddlewareArgs) error {
-	// This would need access to the event body to determine event type
-	eventType := ws.extractEventType(args)
+	body := requestBody(args)
 
-	switch eventType {
+	switch ws.extractEventType(body) {
 	case "workflow_step_edit":
-		return ws.processEdit(args)
+		return ws.processEdit(args, body)
 	case "view_submission":
-		if ws.isStepSave(args) {
-			return ws.processSave(args)
-		}
-		return args.Next()
+		return ws.processSave(args, body)
 	case "workflow_step_execute":
-		return ws.processExecute(args)
+		return ws.processExecute(args, body)
 	default:
-		// Not a workflow step event, continue
+		// Not a request for this workflow step, continue
 		return args.Next()
 	}
 }
 
-// extractEventType extracts the event type from middleware args
-func (ws *WorkflowStep) extractEventType(args types.AllMiddlewareArgs) string {
-	// This would need to extract from the actual event body
-	// For now, return empty string
+// requestBody returns the decoded body of the request args were built for
+func requestBody(args types.AllMiddlewareArgs) map[string]interface{} {
+	if args.Context == nil {
+		return nil
+	}
+	return helpers.ParseRequestBody(args.Context.RawBody)
+}
+
+// extractEventType returns the kind of step request body is, or "" when it is not one for this
+// workflow step
+func (ws *WorkflowStep) extractEventType(body map[string]interface{}) string {
+	switch bodyType, _ := body["type"].(string); bodyType {
+	case "workflow_step_edit":
+		if callbackID, _ := body["callback_id"].(string); callbackID == ws.callbackID {
+			return bodyType
+		}
+	case "view_submission":
+		if ws.isStepSave(body) {
+			return bodyType
+		}
+	case "event_callback":
+		event, _ := body["event"].(map[string]interface{})
+		eventType, _ := event["type"].(string)
+		if callbackID, _ := event["callback_id"].(string); eventType == "workflow_step_execute" && callbackID == ws.callbackID {
+			return eventType
+		}
+	}
 	return ""
 }
 
-// isStepSave checks if this is a step save event
-func (ws *WorkflowStep) isStepSave(args types.AllMiddlewareArgs) bool {
-	// This would check if the view submission is for this workflow step
-	return false
+// isStepSave reports whether body is the submission of this workflow step's configuration modal
+func (ws *WorkflowStep) isStepSave(body map[string]interface{}) bool {
+	view, _ := body["view"].(map[string]interface{})
+	viewType, _ := view["type"].(string)
+	callbackID, _ := view["callback_id"].(string)
+	return viewType == "workflow_step" && callbackID == ws.callbackID
+}
+
+// storedArgs returns the typed middleware args the App stored in the context of args
+func storedArgs(args types.AllMiddlewareArgs) interface{} {
+	if args.Context == nil {
+		return nil
+	}
+	return args.Context.Custom["middlewareArgs"]
 }
 
 // processEdit processes workflow step edit events
-func (ws *WorkflowStep) processEdit(args types.AllMiddlewareArgs) error {
-	stepUtilities := ws.createStepUtilities(args)
+func (ws *WorkflowStep) processEdit(args types.AllMiddlewareArgs, body map[string]interface{}) error {
+	step, _ := body["workflow_step"].(map[string]interface{})
+	stepUtilities := ws.createStepUtilities(args, body, step)
+
+	var ack types.AckFn[interface{}] = func(*interface{}) error { return nil }
+	if actionArgs, ok := storedArgs(args).(types.SlackActionMiddlewareArgs); ok && actionArgs.Ack != nil {
+		ack = actionArgs.Ack
+	}
+	ack, _ = onceAck(ack)
+	if err := ack(nil); err != nil {
+		return err
+	}
 
 	middlewareArgs := WorkflowStepEditMiddlewareArgs{
 		AllMiddlewareArgs: args,
-		Step:              nil, // Would be populated from actual event
-		Body:              nil, // Would be populated from actual body
+		Step:              step,
+		Body:              body,
+		Ack:               ack,
 		Configure:         stepUtilities.Configure,
 		Update:            stepUtilities.Update,
 		Complete:          stepUtilities.Complete,
 		Fail:              stepUtilities.Fail,
 	}
 
-	for _, middleware := range ws.editMiddleware {
-		if err := middleware(middlewareArgs); err != nil {
-			return err
-		}
-	}
-
-	return args.Next()
+	return runStepMiddleware(ws.editMiddleware, func(next types.NextFn) WorkflowStepEditMiddlewareArgs {
+		middlewareArgs.Next = next
+		return middlewareArgs
+	})
 }
 
 // processSave processes workflow step save events
-func (ws *WorkflowStep) processSave(args types.AllMiddlewareArgs) error {
-	stepUtilities := ws.createStepUtilities(args)
+func (ws *WorkflowStep) processSave(args types.AllMiddlewareArgs, body map[string]interface{}) error {
+	step, _ := body["workflow_step"].(map[string]interface{})
+	stepUtilities := ws.createStepUtilities(args, body, step)
+
+	var ack types.AckFn[types.ViewResponse] = func(*types.ViewResponse) error { return nil }
+	if viewArgs, ok := storedArgs(args).(types.SlackViewMiddlewareArgs); ok && viewArgs.Ack != nil {
+		ack = viewArgs.Ack
+	}
+	ack, acked := onceAck(ack)
 
 	middlewareArgs := WorkflowStepSaveMiddlewareArgs{
 		AllMiddlewareArgs: args,
-		Step:              nil, // Would be populated from actual event
-		Body:              nil, // Would be populated from actual body
-		View:              nil, // Would be populated from actual view
+		Step:              step,
+		Body:              body,
+		View:              body["view"],
+		Ack:               ack,
 		Update:            stepUtilities.Update,
 		Complete:          stepUtilities.Complete,
 		Fail:              stepUtilities.Fail,
 	}
 
-	for _, middleware := range ws.saveMiddleware {
-		if err := middleware(middlewareArgs); err != nil {
-			return err
-		}
+	if err := runStepMiddleware(ws.saveMiddleware, func(next types.NextFn) WorkflowStepSaveMiddlewareArgs {
+		middlewareArgs.Next = next
+		return middlewareArgs
+	}); err != nil {
+		return err
 	}
-
-	return args.Next()
+	if !acked() {
+		return ack(nil)
+	}
+	return nil
 }
 
 // processExecute processes workflow step execute events
-func (ws *WorkflowStep) processExecute(args types.AllMiddlewareArgs) error {
-	stepUtilities := ws.createStepUtilities(args)
+func (ws *WorkflowStep) processExecute(args types.AllMiddlewareArgs, body map[string]interface{}) error {
+	event, _ := body["event"].(map[string]interface{})
+	step, _ := event["workflow_step"].(map[string]interface{})
+	stepUtilities := ws.createStepUtilities(args, body, step)
+
+	if eventArgs, ok := storedArgs(args).(types.SlackEventMiddlewareArgs); ok && eventArgs.Ack != nil {
+		if err := eventArgs.Ack(nil); err != nil {
+			return err
+		}
+	}
 
 	middlewareArgs := WorkflowStepExecuteMiddlewareArgs{
 		AllMiddlewareArgs: args,
-		Step:              nil, // Would be populated from actual event
-		Body:              nil, // Would be populated from actual body
-		Event:             nil, // Would be populated from actual event
+		Step:              step,
+		Body:              body,
+		Event:             event,
 		Complete:          stepUtilities.Complete,
 		Fail:              stepUtilities.Fail,
 	}
 
-	for _, middleware := range ws.executeMiddleware {
-		if err := middleware(middlewareArgs); err != nil {
-			return err
+	return runStepMiddleware(ws.executeMiddleware, func(next types.NextFn) WorkflowStepExecuteMiddlewareArgs {
+		middlewareArgs.Next = next
+		return middlewareArgs
+	})
+}
+
+// onceAck returns ack made idempotent, so that a step and its middleware can both ack a request,
+// and a function reporting whether it was called
+func onceAck[Response any](ack types.AckFn[Response]) (types.AckFn[Response], func() bool) {
+	var mu sync.Mutex
+	acked := false
+	once := func(response *Response) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if acked {
+			return nil
 		}
+		acked = true
+		return ack(response)
 	}
+	return once, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return acked
+	}
+}
 
-	return args.Next()
+// runStepMiddleware runs middleware as a chain, each one continuing to the next with args.Next().
+// Step requests end with the step's middleware: Next of the last one does nothing.
+func runStepMiddleware[M ~func(Args) error, Args any](middleware []M, withNext func(next types.NextFn) Args) error {
+	var run func(i int) error
+	run = func(i int) error {
+		if i >= len(middleware) {
+			return nil
+		}
+		return middleware[i](withNext(func() error { return run(i + 1) }))
+	}
+	return run(0)
 }
 
 // StepUtilities contains utility functions for workflow steps
@@ -251,24 +355,66 @@ type StepUtilities struct {
 	Fail      StepFailFn
 }
 
-// createStepUtilities creates utility functions for workflow step middleware
-func (ws *WorkflowStep) createStepUtilities(args types.AllMiddlewareArgs) StepUtilities {
+// createStepUtilities creates the utility functions for a step request: Configure opens the
+// configuration modal with the request's trigger_id, Update saves the step being edited and
+// Complete and Fail end the step execution
+func (ws *WorkflowStep) createStepUtilities(args types.AllMiddlewareArgs, body, step map[string]interface{}) StepUtilities {
+	client := newStepClient(args)
+	triggerID, _ := body["trigger_id"].(string)
+	editID, _ := step["workflow_step_edit_id"].(string)
+	executeID, _ := step["workflow_step_execute_id"].(string)
+
 	return StepUtilities{
 		Configure: func(configArgs StepConfigureArguments) error {
-			// This would call the Slack API to configure the step
-			return nil
+			view := map[string]interface{}{
+				"type":        "workflow_step",
+				"callback_id": ws.callbackID,
+				"blocks":      configArgs.Blocks,
+			}
+			if configArgs.Blocks == nil {
+				view["blocks"] = []slack.Block{}
+			}
+			if configArgs.PrivateMetadata != nil {
+				view["private_metadata"] = *configArgs.PrivateMetadata
+			}
+			if configArgs.SubmitDisabled != nil {
+				view["submit_disabled"] = *configArgs.SubmitDisabled
+			}
+			if configArgs.ExternalID != nil {
+				view["external_id"] = *configArgs.ExternalID
+			}
+			return client.call("views.open", map[string]interface{}{"trigger_id": triggerID, "view": view})
 		},
 		Update: func(updateArgs *StepUpdateArguments) error {
-			// This would call the Slack API to update the step
-			return nil
+			params := map[string]interface{}{"workflow_step_edit_id": editID}
+			if updateArgs != nil {
+				if updateArgs.Inputs != nil {
+					params["inputs"] = updateArgs.Inputs
+				}
+				if updateArgs.Outputs != nil {
+					params["outputs"] = updateArgs.Outputs
+				}
+				if updateArgs.StepName != nil {
+					params["step_name"] = *updateArgs.StepName
+				}
+				if updateArgs.StepImageURL != nil {
+					params["step_image_url"] = *updateArgs.StepImageURL
+				}
+			}
+			return client.call("workflows.updateStep", params)
 		},
 		Complete: func(completeArgs *StepCompleteArguments) error {
-			// This would call the Slack API to complete the step
-			return nil
+			params := map[string]interface{}{"workflow_step_execute_id": executeID}
+			if completeArgs != nil && completeArgs.Outputs != nil {
+				params["outputs"] = completeArgs.Outputs
+			}
+			return client.call("workflows.stepCompleted", params)
 		},
 		Fail: func(failArgs StepFailArguments) error {
-			// This would call the Slack API to fail the step
-			return nil
+			return client.call("workflows.stepFailed", map[string]interface{}{
+				"workflow_step_execute_id": executeID,
+				"error":                    failArgs.Error,
+			})
 		},
 	}
 }
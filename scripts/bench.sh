@@ -0,0 +1,38 @@
+#!/usr/bin/env bash
+# Compares the benchmarks of the working tree against a base git ref.
+#
+# Usage: scripts/bench.sh [base-ref]
+#
+# Environment:
+#   BENCH  benchmark regexp passed to -bench (default ".")
+#   COUNT  runs per benchmark, for benchstat's statistics (default 6)
+#
+# Results are written to bench_output.txt (working tree) and bench_base.txt (base ref) and
+# compared with benchstat when it is installed.
+set -euo pipefail
+
+base_ref="${1:-HEAD}"
+bench="${BENCH:-.}"
+count="${COUNT:-6}"
+root="$(git rev-parse --show-toplevel)"
+
+run_benchmarks() {
+	(cd "$1" && go test ./test -run '^$' -bench "$bench" -benchmem -count "$count")
+}
+
+worktree="$(mktemp -d)"
+trap 'git -C "$root" worktree remove --force "$worktree" >/dev/null 2>&1 || true' EXIT
+git -C "$root" worktree add --detach "$worktree" "$base_ref" >/dev/null
+
+echo "Running benchmarks at $base_ref..."
+run_benchmarks "$worktree" >"$root/bench_base.txt"
+
+echo "Running benchmarks on the working tree..."
+run_benchmarks "$root" >"$root/bench_output.txt"
+
+if command -v benchstat >/dev/null 2>&1; then
+	benchstat "$root/bench_base.txt" "$root/bench_output.txt"
+else
+	echo "benchstat not found; install it with: go install golang.org/x/perf/cmd/benchstat@latest"
+	echo "Results: bench_base.txt (base) and bench_output.txt (working tree)"
+fi
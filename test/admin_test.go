@@ -0,0 +1,172 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/admin"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orgMention is an app_mention delivered to an app installed on an Enterprise Grid organization
+func orgMention(t *testing.T) bolttest.Payload {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"type":                  "event_callback",
+		"team_id":               "T123456",
+		"enterprise_id":         "E123456",
+		"is_enterprise_install": true,
+		"event_id":              "Ev123",
+		"authorizations":        []any{map[string]any{"enterprise_id": "E123456", "team_id": nil, "user_id": "U123456", "is_bot": true, "is_enterprise_install": true}},
+		"event":                 map[string]any{"type": "app_mention", "text": "audit", "user": "U1", "channel": "C1", "ts": "1.2"},
+	})
+	require.NoError(t, err)
+	return bolttest.Payload{Body: body, Headers: map[string]string{"Content-Type": "application/json"}}
+}
+
+func TestAdminAPI(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should call admin methods with the user token of an org-wide install", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return &bolt.AuthorizeResult{BotToken: "xoxb-org", UserToken: "xoxp-admin", UserScopes: []string{"admin.users:read"}, EnterpriseID: "E123456"}, nil
+			},
+		})
+		h.Slack.HandleFunc("admin.users.list", cursorPages("users", map[string][]any{
+			"":   {map[string]any{"id": "W1", "email": "a@example.com", "is_admin": true}},
+			"c2": {map[string]any{"id": "W2", "is_active": true}},
+		}, map[string]string{"": "c2"}))
+
+		var users []admin.User
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			client, err := args.Admin("admin.users:read")
+			if err != nil {
+				return err
+			}
+			for page, err := range client.UsersListIter(ctx, admin.UsersListParams{Limit: 1}) {
+				if err != nil {
+					return err
+				}
+				users = append(users, page...)
+			}
+			return nil
+		})
+		h.Send(orgMention(t)).AssertNoError(t)
+
+		require.Len(t, users, 2)
+		assert.Equal(t, "a@example.com", users[0].Email)
+		assert.True(t, users[0].IsAdmin)
+		assert.Equal(t, "W2", users[1].ID)
+		calls := h.Slack.Calls("admin.users.list")
+		require.Len(t, calls, 2)
+		assert.Equal(t, "xoxp-admin", calls[0].Token)
+		assert.Equal(t, "1", calls[0].Params["limit"])
+		assert.Equal(t, "c2", calls[1].Params["cursor"])
+	})
+
+	t.Run("should refuse events not from an org-wide install or without needed scopes", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return &bolt.AuthorizeResult{BotToken: "xoxb-org", UserToken: "xoxp-admin", UserScopes: []string{"admin.users:read"}}, nil
+			},
+		})
+
+		var errs []error
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err := args.Admin("admin.apps:read")
+			errs = append(errs, err)
+			return nil
+		})
+		h.Send(bolttest.AppMention("audit")).AssertNoError(t)
+		h.Send(orgMention(t)).AssertNoError(t)
+
+		require.Len(t, errs, 2)
+		assert.ErrorIs(t, errs[0], bolt.UserTokenErrorCode)
+		assert.Contains(t, errs[0].Error(), "org-wide install")
+		var tokenErr *bolt.UserTokenError
+		require.ErrorAs(t, errs[1], &tokenErr)
+		assert.Equal(t, []string{"admin.apps:read"}, tokenErr.MissingScopes)
+	})
+
+	t.Run("should search conversations and handle app requests", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		fake.Handle("admin.conversations.search", map[string]any{
+			"ok":            true,
+			"conversations": []any{map[string]any{"id": "C1", "name": "general", "member_count": 42, "is_general": true}},
+			"next_cursor":   "",
+		})
+		fake.Handle("admin.apps.requests.list", map[string]any{
+			"ok": true,
+			"app_requests": []any{map[string]any{
+				"id":     "Ar1",
+				"app":    map[string]any{"id": "A1", "name": "Polls"},
+				"user":   map[string]any{"id": "W1", "email": "a@example.com"},
+				"team":   map[string]any{"id": "T1"},
+				"scopes": []any{map[string]any{"name": "chat:write", "token_type": "bot"}},
+			}},
+			"response_metadata": map[string]any{"next_cursor": ""},
+		})
+		client := admin.New("xoxp-admin", admin.Options{HTTPClient: fake.Client()})
+
+		conversations, cursor, err := client.ConversationsSearch(ctx, admin.ConversationsSearchParams{
+			Query:              "gen",
+			TeamIDs:            []string{"T1", "T2"},
+			SearchChannelTypes: []string{"exclude_archived"},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, cursor)
+		require.Len(t, conversations, 1)
+		assert.Equal(t, 42, conversations[0].MemberCount)
+		search := fake.Calls("admin.conversations.search")
+		require.Len(t, search, 1)
+		assert.Equal(t, "T1,T2", search[0].Params["team_ids"])
+		assert.Equal(t, "exclude_archived", search[0].Params["search_channel_types"])
+
+		var requests []admin.AppRequest
+		for page, err := range client.AppsRequestsListIter(ctx, admin.AppsRequestsListParams{TeamID: "T1"}) {
+			require.NoError(t, err)
+			requests = append(requests, page...)
+		}
+		require.Len(t, requests, 1)
+		assert.Equal(t, "Polls", requests[0].App.Name)
+		assert.Equal(t, "chat:write", requests[0].Scopes[0].Name)
+
+		require.NoError(t, client.AppsApprove(ctx, admin.AppDecision{RequestID: requests[0].ID, TeamID: "T1"}))
+		approve := fake.Calls("admin.apps.approve")
+		require.Len(t, approve, 1)
+		assert.Equal(t, "Ar1", approve[0].Params["request_id"])
+		assert.Equal(t, "xoxp-admin", approve[0].Token)
+	})
+
+	t.Run("should return Slack and rate limit errors like slack-go", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		fake.Handle("admin.apps.restrict", map[string]any{"ok": false, "error": "not_an_admin"})
+		client := admin.New("xoxp-user", admin.Options{HTTPClient: fake.Client()})
+
+		err := client.AppsRestrict(ctx, admin.AppDecision{AppID: "A1"})
+		var slackErr slack.SlackErrorResponse
+		require.ErrorAs(t, err, &slackErr)
+		assert.Equal(t, "not_an_admin", slackErr.Err)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		limited := admin.New("xoxp-user", admin.Options{APIURL: server.URL + "/"})
+		_, _, err = limited.UsersList(ctx, admin.UsersListParams{})
+		var rateLimited *slack.RateLimitedError
+		require.ErrorAs(t, err, &rateLimited)
+		assert.Equal(t, 7*time.Second, rateLimited.RetryAfter)
+	})
+}
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authorizeTokens returns an authorize function granting botToken and userToken
+func authorizeTokens(botToken, userToken string) func(context.Context, bolt.AuthorizeSourceData, interface{}) (*bolt.AuthorizeResult, error) {
+	return func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+		return &bolt.AuthorizeResult{
+			BotToken:  botToken,
+			BotID:     "B123456",
+			BotUserID: "U123456",
+			TeamID:    "T123456",
+			UserToken: userToken,
+		}, nil
+	}
+}
+
+func TestListenerAPI(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should call methods with the bot token and search with the user token", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("xoxb-bot", "xoxp-user")})
+		h.Slack.Handle("search.messages", map[string]any{"ok": true, "messages": map[string]any{"matches": []any{}}})
+
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			api := args.API()
+			if _, _, err := api.PostMessage("C123", slack.MsgOptionText("hi", false)); err != nil {
+				return err
+			}
+			_, err := api.SearchMessages("hello", slack.NewSearchParameters())
+			return err
+		})
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		posts := h.Slack.Calls("chat.postMessage")
+		require.Len(t, posts, 1)
+		assert.Equal(t, "xoxb-bot", posts[0].Token)
+		searches := h.Slack.Calls("search.messages")
+		require.Len(t, searches, 1)
+		assert.Equal(t, "xoxp-user", searches[0].Token)
+	})
+
+	t.Run("should fall back to the token the event was authorized with", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("", "xoxp-user")})
+
+		var botMissing bool
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			botMissing = args.API().Bot() == nil
+			return args.API().AddReaction("wave", slack.NewRefToMessage("C123", "1.0"))
+		})
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		assert.True(t, botMissing)
+		calls := h.Slack.Calls("reactions.add")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "xoxp-user", calls[0].Token)
+	})
+
+	t.Run("should use the context of the event", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+
+		type key struct{}
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "traced"))
+		cancel()
+		var value any
+		var err error
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			value = args.API().Context().Value(key{})
+			_, _, err = args.API().PostMessage("C123", slack.MsgOptionText("hi", false))
+			return nil
+		})
+		h.Receiver.Send(ctx, bolttest.AppMention("hello")).AssertNoError(t)
+
+		assert.Equal(t, "traced", value)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, h.Slack.Calls("chat.postMessage"))
+	})
+
+	t.Run("should return an error without a client", func(t *testing.T) {
+		api := bolt.NewAPI(context.Background(), nil, nil)
+		_, err := api.SearchMessages("hello", slack.NewSearchParameters())
+		assert.ErrorIs(t, err, bolt.ErrNoToken)
+	})
+}
+
+func TestInstallationAPIURL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should call the API URL and client options of the installation", func(t *testing.T) {
+		var optionsApplied atomic.Int32
+		h := bolttest.New(t, bolt.AppOptions{
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return &bolt.AuthorizeResult{
+					BotToken:      "xoxb-gov",
+					UserToken:     "xoxp-gov",
+					UserScopes:    []string{"search:read"},
+					TeamID:        "T123456",
+					APIURL:        "https://slack-gov.com/api/",
+					ClientOptions: []slack.Option{func(*slack.Client) { optionsApplied.Add(1) }},
+				}, nil
+			},
+		})
+
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			assert.Equal(t, "https://slack-gov.com/api/", args.Context.APIURL)
+			if _, err := args.Say(types.SayString("hello")); err != nil {
+				return err
+			}
+			client, err := args.UserClient("search:read")
+			if err != nil {
+				return err
+			}
+			_, err = client.AuthTest()
+			return err
+		})
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+		h.Send(bolttest.AppMention("again")).AssertNoError(t)
+
+		said := h.Slack.Said()
+		require.Len(t, said, 2)
+		assert.Equal(t, "https://slack-gov.com/api/chat.postMessage", said[0].URL)
+		authTests := h.Slack.Calls("auth.test")
+		require.Len(t, authTests, 2)
+		assert.Equal(t, "https://slack-gov.com/api/auth.test", authTests[0].URL)
+		assert.Equal(t, "xoxp-gov", authTests[0].Token)
+		assert.EqualValues(t, 2, optionsApplied.Load(), "options should apply once to each pooled client")
+	})
+
+	t.Run("should keep the app's API URL without one", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err := args.Say(types.SayString("hello"))
+			return err
+		})
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		said := h.Slack.Said()
+		require.Len(t, said, 1)
+		assert.Equal(t, slack.APIURL+"chat.postMessage", said[0].URL)
+	})
+}
+
+func TestListenerUserClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should act as the user when the token has the needed scopes", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return &bolt.AuthorizeResult{BotToken: "xoxb-bot", UserToken: "xoxp-user", UserScopes: []string{"reminders:write", "search:read"}, TeamID: "T123456"}, nil
+			},
+		})
+		h.Slack.Handle("reminders.add", map[string]any{"ok": true, "reminder": map[string]any{"id": "Rm1"}})
+
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			client, err := args.UserClient("reminders:write")
+			if err != nil {
+				return err
+			}
+			_, err = client.AddUserReminder("U1", "stand up", "in 5 minutes")
+			return err
+		})
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		calls := h.Slack.Calls("reminders.add")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "xoxp-user", calls[0].Token)
+	})
+
+	t.Run("should report missing scopes", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return &bolt.AuthorizeResult{BotToken: "xoxb-bot", UserToken: "xoxp-user", UserScopes: []string{"search:read"}, TeamID: "T123456"}, nil
+			},
+		})
+
+		var err error
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err = args.UserClient("search:read", "reminders:write", "chat:write")
+			return nil
+		})
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		require.ErrorIs(t, err, bolt.UserTokenErrorCode)
+		var tokenErr *bolt.UserTokenError
+		require.ErrorAs(t, err, &tokenErr)
+		assert.Equal(t, []string{"reminders:write", "chat:write"}, tokenErr.MissingScopes)
+		assert.Contains(t, err.Error(), "reminders:write, chat:write")
+	})
+
+	t.Run("should explain when there is no user token", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+
+		var err error
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err = args.UserClient()
+			return nil
+		})
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		require.ErrorIs(t, err, bolt.UserTokenErrorCode)
+		assert.Contains(t, err.Error(), "no user token")
+	})
+}
@@ -2,7 +2,11 @@ package test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Asafrose/bolt-go"
 	"github.com/Asafrose/bolt-go/pkg/app"
@@ -239,6 +243,33 @@ func TestAppConstructorValidation(t *testing.T) {
 		assert.Contains(t, err.Error(), "app token")
 	})
 
+	t.Run("should explain swapped tokens", func(t *testing.T) {
+		_, err := bolt.New(bolt.AppOptions{
+			Token:      fakeAppToken,
+			AppToken:   fakeToken,
+			SocketMode: true,
+		})
+		require.ErrorIs(t, err, bolt.AppInitializationErrorCode)
+		assert.Contains(t, err.Error(), "Token is an app-level token (xapp-); pass it as AppToken")
+		assert.Contains(t, err.Error(), "AppToken is a bot token (xoxb-); pass it as Token")
+	})
+
+	t.Run("should reject tokens of the wrong kind", func(t *testing.T) {
+		_, err := bolt.New(bolt.AppOptions{Token: "xoxp-user", SigningSecret: fakeSigningSecret})
+		require.ErrorIs(t, err, bolt.AppInitializationErrorCode)
+		assert.Contains(t, err.Error(), "Token is a user token (xoxp-)")
+
+		_, err = bolt.New(bolt.AppOptions{Token: "not-a-token", SigningSecret: fakeSigningSecret})
+		require.ErrorIs(t, err, bolt.AppInitializationErrorCode)
+		assert.Contains(t, err.Error(), "bot tokens start with xoxb-")
+	})
+
+	t.Run("should reject an app token without socket mode", func(t *testing.T) {
+		_, err := bolt.New(bolt.AppOptions{Token: fakeToken, AppToken: fakeAppToken, SigningSecret: fakeSigningSecret})
+		require.ErrorIs(t, err, bolt.AppInitializationErrorCode)
+		assert.Contains(t, err.Error(), "AppToken is only used by Socket Mode")
+	})
+
 	t.Run("should validate mutual exclusion of token and authorize", func(t *testing.T) {
 		authorizeFn := func(ctx context.Context, source app.AuthorizeSourceData, body interface{}) (*app.AuthorizeResult, error) {
 			return &app.AuthorizeResult{BotToken: fakeToken}, nil
@@ -383,14 +414,190 @@ func TestBasicAppConstructorMissing(t *testing.T) {
 
 	t.Run("with auth.test failure", func(t *testing.T) {
 		t.Run("should not perform auth.test API call if tokenVerificationEnabled is false", func(t *testing.T) {
-			// This test would require mocking the Slack API client
-			// For now, we test that the app can be created with token verification disabled
-			t.Skip("TokenVerificationEnabled field and auth.test mocking not implemented yet")
+			server, calls := newAuthTestServer(t, false)
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:               fakeToken,
+				SigningSecret:       fakeSigningSecret,
+				DeferInitialization: true,
+				ClientOptions:       []slack.Option{slack.OptionAPIURL(server.URL + "/")},
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, app.Init(context.Background()))
+			assert.Equal(t, int32(0), calls.Load())
 		})
 
 		t.Run("should fail in await App#init()", func(t *testing.T) {
-			// This test would require mocking a failing auth.test API call
-			t.Skip("Requires mocking Slack API auth.test failure - not implemented yet")
+			server, calls := newAuthTestServer(t, false)
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:                    fakeToken,
+				SigningSecret:            fakeSigningSecret,
+				DeferInitialization:      true,
+				TokenVerificationEnabled: true,
+				ClientOptions:            []slack.Option{slack.OptionAPIURL(server.URL + "/")},
+			})
+			require.NoError(t, err)
+
+			err = app.Init(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "token verification failed")
+			assert.Equal(t, int32(1), calls.Load())
+		})
+	})
+
+	t.Run("with startup token verification", func(t *testing.T) {
+		t.Run("should fail Start before starting the receiver when the token was revoked", func(t *testing.T) {
+			server := newAuthTestServerResponding(t, `{"ok":false,"error":"token_revoked"}`)
+			receiver := &FakeReceiver{}
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:                    fakeToken,
+				SigningSecret:            fakeSigningSecret,
+				Receiver:                 receiver,
+				TokenVerificationEnabled: true,
+				ClientOptions:            []slack.Option{slack.OptionAPIURL(server.URL + "/")},
+			})
+			require.NoError(t, err)
+
+			err = app.Start(context.Background())
+			require.ErrorIs(t, err, bolt.AppInitializationErrorCode)
+			assert.Contains(t, err.Error(), "token_revoked")
+			assert.Contains(t, err.Error(), "reinstall the app")
+			var slackErr slack.SlackErrorResponse
+			assert.ErrorAs(t, err, &slackErr)
+			assert.False(t, receiver.started)
+		})
+
+		t.Run("should reject a token that is not a bot token", func(t *testing.T) {
+			server := newAuthTestServerResponding(t, `{"ok":true,"team_id":"T_AUTH_TEST","user_id":"U_HUMAN"}`)
+			receiver := &FakeReceiver{}
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:                    fakeToken,
+				SigningSecret:            fakeSigningSecret,
+				Receiver:                 receiver,
+				TokenVerificationEnabled: true,
+				ClientOptions:            []slack.Option{slack.OptionAPIURL(server.URL + "/")},
+			})
+			require.NoError(t, err)
+
+			err = app.Start(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "not a bot token")
+			assert.False(t, receiver.started)
+		})
+
+		t.Run("should cache the bot identity for listeners", func(t *testing.T) {
+			server, calls := newAuthTestServer(t, true)
+			receiver := &FakeReceiver{}
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:                    fakeToken,
+				SigningSecret:            fakeSigningSecret,
+				Receiver:                 receiver,
+				TokenVerificationEnabled: true,
+				ClientOptions:            []slack.Option{slack.OptionAPIURL(server.URL + "/")},
+			})
+			require.NoError(t, err)
+
+			_, verified := app.BotIdentity()
+			assert.False(t, verified)
+			require.NoError(t, app.Start(context.Background()))
+			assert.True(t, receiver.started)
+
+			identity, verified := app.BotIdentity()
+			require.True(t, verified)
+			assert.Equal(t, bolt.BotIdentity{BotID: "B_AUTH_TEST", BotUserID: "U_AUTH_TEST", TeamID: "T_AUTH_TEST"}, identity)
+
+			var botUserID string
+			app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+				botUserID = args.Context.BotUserID
+				return nil
+			})
+			require.NoError(t, app.ProcessEvent(context.Background(), types.ReceiverEvent{
+				Body:    createAppMentionEventBody(),
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Ack:     func(response types.AckResponse) error { return nil },
+			}))
+			assert.Equal(t, "U_AUTH_TEST", botUserID)
+			assert.Equal(t, int32(1), calls.Load())
+		})
+	})
+
+	t.Run("with auth.test caching", func(t *testing.T) {
+		t.Run("should resolve the bot identity with a single auth.test call", func(t *testing.T) {
+			server, calls := newAuthTestServer(t, true)
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:                    fakeToken,
+				SigningSecret:            fakeSigningSecret,
+				DeferInitialization:      true,
+				TokenVerificationEnabled: true,
+				ClientOptions:            []slack.Option{slack.OptionAPIURL(server.URL + "/")},
+			})
+			require.NoError(t, err)
+			require.NoError(t, app.Init(context.Background()))
+
+			var botIDs, botUserIDs []string
+			app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+				botIDs = append(botIDs, args.Context.BotID)
+				botUserIDs = append(botUserIDs, args.Context.BotUserID)
+				return nil
+			})
+
+			for i := 0; i < 3; i++ {
+				require.NoError(t, app.ProcessEvent(context.Background(), types.ReceiverEvent{
+					Body:    createAppMentionEventBody(),
+					Headers: map[string]string{"Content-Type": "application/json"},
+					Ack:     func(response types.AckResponse) error { return nil },
+				}))
+			}
+
+			assert.Equal(t, []string{"B_AUTH_TEST", "B_AUTH_TEST", "B_AUTH_TEST"}, botIDs)
+			assert.Equal(t, []string{"U_AUTH_TEST", "U_AUTH_TEST", "U_AUTH_TEST"}, botUserIDs)
+			assert.Equal(t, int32(1), calls.Load(), "init and every authorize should share one auth.test call")
+		})
+
+		t.Run("should call auth.test again after the TTL expires", func(t *testing.T) {
+			server, calls := newAuthTestServer(t, true)
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:         fakeToken,
+				SigningSecret: fakeSigningSecret,
+				AuthTestTTL:   time.Millisecond,
+				ClientOptions: []slack.Option{slack.OptionAPIURL(server.URL + "/")},
+			})
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			_, err = app.AuthTest(ctx, fakeToken)
+			require.NoError(t, err)
+			time.Sleep(5 * time.Millisecond)
+			identity, err := app.AuthTest(ctx, fakeToken)
+			require.NoError(t, err)
+
+			assert.Equal(t, "B_AUTH_TEST", identity.BotID)
+			assert.Equal(t, int32(2), calls.Load())
+		})
+
+		t.Run("should not cache failed auth.test calls", func(t *testing.T) {
+			server, calls := newAuthTestServer(t, false)
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:         fakeToken,
+				SigningSecret: fakeSigningSecret,
+				ClientOptions: []slack.Option{slack.OptionAPIURL(server.URL + "/")},
+			})
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			_, err = app.AuthTest(ctx, fakeToken)
+			require.Error(t, err)
+			_, err = app.AuthTest(ctx, fakeToken)
+			require.Error(t, err)
+			assert.Equal(t, int32(2), calls.Load())
 		})
 	})
 
@@ -465,3 +672,34 @@ func TestAppConstructorWebClientOptions(t *testing.T) {
 		assert.NotNil(t, app, "App should be initialized with client options")
 	})
 }
+
+// newAuthTestServer serves auth.test, succeeding with a fixed bot identity when ok is true,
+// and counts the calls it receives
+// newAuthTestServerResponding serves auth.test with response
+func newAuthTestServerResponding(t *testing.T, response string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newAuthTestServer(t *testing.T, ok bool) (*httptest.Server, *atomic.Int32) {
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth.test" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if !ok {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"team_id":"T_AUTH_TEST","user_id":"U_AUTH_TEST","bot_id":"B_AUTH_TEST"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, calls
+}
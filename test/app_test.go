@@ -1,8 +1,16 @@
 package test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Asafrose/bolt-go"
 	"github.com/Asafrose/bolt-go/pkg/types"
@@ -253,3 +261,389 @@ func TestAppIgnoreSelf(t *testing.T) {
 
 // Helper function
 // stringPtr helper is defined in helpers_test.go
+
+func TestWebClientPool(t *testing.T) {
+	t.Parallel()
+	t.Run("should reuse clients per token", func(t *testing.T) {
+		pool := bolt.NewWebClientPool()
+
+		first := pool.GetOrCreate("xoxb-1")
+		assert.Same(t, first, pool.GetOrCreate("xoxb-1"))
+		assert.NotSame(t, first, pool.GetOrCreate("xoxb-2"))
+
+		stats := pool.Stats()
+		assert.Equal(t, 2, stats.Size)
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(2), stats.Misses)
+	})
+
+	t.Run("should pool clients per token and API URL", func(t *testing.T) {
+		pool := bolt.NewWebClientPool()
+
+		first := pool.GetOrCreateForAPIURL("xoxb-1", "https://slack-gov.com/api/")
+		assert.Same(t, first, pool.GetOrCreateForAPIURL("xoxb-1", "https://slack-gov.com/api/"))
+		assert.NotSame(t, first, pool.GetOrCreate("xoxb-1"))
+		assert.Equal(t, 2, pool.Stats().Size)
+	})
+
+	t.Run("should evict the least recently used client when full", func(t *testing.T) {
+		pool := bolt.NewWebClientPoolWithOptions(bolt.WebClientPoolOptions{MaxSize: 2})
+
+		first := pool.GetOrCreate("xoxb-1")
+		pool.GetOrCreate("xoxb-2")
+		assert.Same(t, first, pool.GetOrCreate("xoxb-1"))
+		pool.GetOrCreate("xoxb-3") // evicts xoxb-2
+
+		stats := pool.Stats()
+		assert.Equal(t, 2, stats.Size)
+		assert.Equal(t, int64(1), stats.Evictions)
+		assert.Same(t, first, pool.GetOrCreate("xoxb-1"), "recently used client should survive eviction")
+	})
+
+	t.Run("should expire idle clients", func(t *testing.T) {
+		pool := bolt.NewWebClientPoolWithOptions(bolt.WebClientPoolOptions{IdleTimeout: 10 * time.Millisecond})
+
+		first := pool.GetOrCreate("xoxb-1")
+		time.Sleep(20 * time.Millisecond)
+
+		stats := pool.Stats()
+		assert.Equal(t, 0, stats.Size)
+		assert.Equal(t, int64(1), stats.Expirations)
+		assert.NotSame(t, first, pool.GetOrCreate("xoxb-1"))
+	})
+
+	t.Run("should bound the app's client pool", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:             fakeToken,
+			SigningSecret:     fakeSigningSecret,
+			ClientPoolOptions: bolt.WebClientPoolOptions{MaxSize: 10},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, app.ClientPoolStats().Size)
+	})
+}
+
+func TestTeamConcurrencyLimits(t *testing.T) {
+	t.Parallel()
+	teamEvent := func(teamID, text string) types.ReceiverEvent {
+		body, _ := json.Marshal(map[string]interface{}{
+			"type":    "event_callback",
+			"team_id": teamID,
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"text":    text,
+				"channel": "C123456",
+			},
+		})
+		return types.ReceiverEvent{
+			Body:    body,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		}
+	}
+
+	t.Run("should reject events of a team at its limit without blocking other teams", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			TeamConcurrency: bolt.TeamConcurrencyOptions{
+				MaxInFlight: 1,
+				WaitTimeout: 20 * time.Millisecond,
+			},
+		})
+		require.NoError(t, err)
+
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+		// Hold the first event in flight until the test releases it
+		app.Use(func(args bolt.AllMiddlewareArgs) error {
+			if bytes.Contains(args.Context.RawBody, []byte("slow")) {
+				close(entered)
+				<-release
+			}
+			return args.Next()
+		})
+
+		firstDone := make(chan error, 1)
+		go func() { firstDone <- app.ProcessEvent(context.Background(), teamEvent("T1", "slow")) }()
+		<-entered
+
+		err = app.ProcessEvent(context.Background(), teamEvent("T1", "fast"))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, bolt.TeamConcurrencyLimitErrorCode))
+		assert.True(t, bolt.IsRetryable(err), "Rejected events should be redelivered by Slack")
+
+		var limitErr *bolt.TeamConcurrencyLimitError
+		require.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, "T1", limitErr.TeamID)
+
+		require.NoError(t, app.ProcessEvent(context.Background(), teamEvent("T2", "fast")), "Other teams should not be limited")
+
+		close(release)
+		require.NoError(t, <-firstDone)
+		require.NoError(t, app.ProcessEvent(context.Background(), teamEvent("T1", "fast")), "Slot should be released after processing")
+	})
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	t.Parallel()
+	teamEvent := func(teamID, text string, acked *bool) types.ReceiverEvent {
+		body, _ := json.Marshal(map[string]interface{}{
+			"type":    "event_callback",
+			"team_id": teamID,
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"text":    text,
+				"channel": "C123456",
+			},
+		})
+		return types.ReceiverEvent{
+			Body:    body,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack: func(response types.AckResponse) error {
+				if acked != nil {
+					*acked = true
+				}
+				return nil
+			},
+		}
+	}
+	// newHeldApp creates an app whose listeners count the events they process and hold events
+	// containing "slow" until release is closed
+	newHeldApp := func(t *testing.T, queue bolt.ConcurrencyQueueOptions, processed *atomic.Int32, release chan struct{}) *bolt.App {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:            fakeToken,
+			SigningSecret:    fakeSigningSecret,
+			MaxConcurrency:   1,
+			ConcurrencyQueue: queue,
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			if bytes.Contains(args.Context.RawBody, []byte("slow")) {
+				<-release
+			}
+			processed.Add(1)
+			return nil
+		})
+		return app
+	}
+	// fill holds one event in flight and queues another, returning their results
+	fill := func(t *testing.T, app *bolt.App) chan error {
+		results := make(chan error, 2)
+		go func() { results <- app.ProcessEvent(context.Background(), teamEvent("T1", "slow", nil)) }()
+		require.Eventually(t, func() bool { return app.ConcurrencyStats().InFlight == 1 }, time.Second, time.Millisecond)
+		go func() { results <- app.ProcessEvent(context.Background(), teamEvent("T2", "queued", nil)) }()
+		require.Eventually(t, func() bool { return app.ConcurrencyStats().Queued == 1 }, time.Second, time.Millisecond)
+		return results
+	}
+
+	t.Run("should reject events of any team once the queue is full", func(t *testing.T) {
+		var processed atomic.Int32
+		release := make(chan struct{})
+		app := newHeldApp(t, bolt.ConcurrencyQueueOptions{Size: 1, OverflowPolicy: bolt.OverflowPolicyReject}, &processed, release)
+		results := fill(t, app)
+
+		err := app.ProcessEvent(context.Background(), teamEvent("T3", "rejected", nil))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, bolt.ConcurrencyLimitErrorCode))
+		assert.True(t, bolt.IsRetryable(err), "Rejected events should be redelivered by Slack")
+		assert.Equal(t, bolt.ConcurrencyStats{MaxConcurrency: 1, InFlight: 1, Queued: 1, Rejected: 1}, app.ConcurrencyStats())
+
+		close(release)
+		require.NoError(t, <-results)
+		require.NoError(t, <-results)
+		assert.Equal(t, int32(2), processed.Load())
+		assert.Equal(t, bolt.ConcurrencyStats{MaxConcurrency: 1, Rejected: 1}, app.ConcurrencyStats())
+	})
+
+	t.Run("should acknowledge and drop events once the queue is full", func(t *testing.T) {
+		var processed atomic.Int32
+		release := make(chan struct{})
+		app := newHeldApp(t, bolt.ConcurrencyQueueOptions{Size: 1, OverflowPolicy: bolt.OverflowPolicyDrop}, &processed, release)
+		results := fill(t, app)
+
+		acked := false
+		require.NoError(t, app.ProcessEvent(context.Background(), teamEvent("T3", "dropped", &acked)))
+		assert.True(t, acked, "Dropped events should be acknowledged so Slack does not redeliver them")
+		assert.Equal(t, int64(1), app.ConcurrencyStats().Dropped)
+
+		close(release)
+		require.NoError(t, <-results)
+		require.NoError(t, <-results)
+		assert.Equal(t, int32(2), processed.Load())
+	})
+
+	t.Run("should let events wait beyond the queue size when blocking", func(t *testing.T) {
+		var processed atomic.Int32
+		release := make(chan struct{})
+		app := newHeldApp(t, bolt.ConcurrencyQueueOptions{Size: 1}, &processed, release)
+		results := fill(t, app)
+
+		go func() { results <- app.ProcessEvent(context.Background(), teamEvent("T3", "blocked", nil)) }()
+		require.Eventually(t, func() bool { return app.ConcurrencyStats().Queued == 2 }, time.Second, time.Millisecond)
+
+		close(release)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, <-results)
+		}
+		assert.Equal(t, int32(3), processed.Load())
+	})
+
+	t.Run("should reject events waiting longer than the wait timeout", func(t *testing.T) {
+		var processed atomic.Int32
+		release := make(chan struct{})
+		defer close(release)
+		app := newHeldApp(t, bolt.ConcurrencyQueueOptions{WaitTimeout: 20 * time.Millisecond}, &processed, release)
+		go func() { _ = app.ProcessEvent(context.Background(), teamEvent("T1", "slow", nil)) }()
+		require.Eventually(t, func() bool { return app.ConcurrencyStats().InFlight == 1 }, time.Second, time.Millisecond)
+
+		err := app.ProcessEvent(context.Background(), teamEvent("T2", "timed out", nil))
+		var limitErr *bolt.ConcurrencyLimitError
+		assert.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, int64(1), app.ConcurrencyStats().Rejected)
+	})
+
+	t.Run("should not hold a slot while waiting for the team limit", func(t *testing.T) {
+		release := make(chan struct{})
+		app, err := bolt.New(bolt.AppOptions{
+			Token:            fakeToken,
+			SigningSecret:    fakeSigningSecret,
+			MaxConcurrency:   2,
+			TeamConcurrency:  bolt.TeamConcurrencyOptions{MaxInFlight: 1},
+			ConcurrencyQueue: bolt.ConcurrencyQueueOptions{OverflowPolicy: bolt.OverflowPolicyReject},
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			if bytes.Contains(args.Context.RawBody, []byte("slow")) {
+				<-release
+			}
+			return nil
+		})
+
+		noisy := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() { noisy <- app.ProcessEvent(context.Background(), teamEvent("T1", "slow", nil)) }()
+		}
+		require.Eventually(t, func() bool { return app.ConcurrencyStats().InFlight == 1 }, time.Second, time.Millisecond)
+
+		require.NoError(t, app.ProcessEvent(context.Background(), teamEvent("T2", "fast", nil)), "A noisy team should not starve the others")
+		close(release)
+		require.NoError(t, <-noisy)
+		require.NoError(t, <-noisy)
+	})
+
+	t.Run("should report zero stats without a limit", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+		assert.Equal(t, bolt.ConcurrencyStats{}, app.ConcurrencyStats())
+	})
+}
+
+func TestProcessingMetrics(t *testing.T) {
+	t.Parallel()
+	mentionEvent := func(acked *bool) types.ReceiverEvent {
+		return types.ReceiverEvent{
+			Body:    createAppMentionEventBody(),
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack: func(response types.AckResponse) error {
+				*acked = true
+				return nil
+			},
+		}
+	}
+
+	t.Run("should record processing latency, ack latency and listener errors", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Metrics:       bolt.MetricsOptions{Enabled: true, ExpvarName: "bolt_test_processing_metrics"},
+		})
+		require.NoError(t, err)
+
+		fail := false
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			time.Sleep(2 * time.Millisecond)
+			if fail {
+				return errors.New("listener failed")
+			}
+			return nil
+		})
+
+		for i := 0; i < 3; i++ {
+			var acked bool
+			require.NoError(t, app.ProcessEvent(context.Background(), mentionEvent(&acked)))
+			assert.True(t, acked, "Ack should still reach the receiver")
+		}
+		fail = true
+		var acked bool
+		require.Error(t, app.ProcessEvent(context.Background(), mentionEvent(&acked)))
+
+		metrics := app.Metrics()
+		assert.Equal(t, 5*time.Minute, metrics.Window)
+		assert.Equal(t, int64(4), metrics.Processing.Count)
+		assert.Equal(t, int64(4), metrics.Ack.Count)
+		assert.GreaterOrEqual(t, metrics.Processing.P50, 2*time.Millisecond)
+		assert.GreaterOrEqual(t, metrics.Processing.Max, metrics.Processing.Mean)
+		assert.LessOrEqual(t, metrics.Ack.Max, metrics.Processing.Max, "Events are acked before the listener finishes")
+		assert.Equal(t, int64(1), metrics.ListenerErrors)
+		assert.Equal(t, int64(1), metrics.ListenerErrorsTotal)
+
+		published := expvar.Get("bolt_test_processing_metrics")
+		require.NotNil(t, published, "Metrics should be published with expvar")
+		var fromExpvar map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(published.String()), &fromExpvar))
+		assert.Equal(t, "5m0s", fromExpvar["window"])
+		assert.Equal(t, float64(1), fromExpvar["listener_errors_total"])
+		assert.Equal(t, float64(4), fromExpvar["processing"].(map[string]interface{})["count"])
+
+		recorder := httptest.NewRecorder()
+		app.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/bolt", nil))
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.JSONEq(t, published.String(), recorder.Body.String())
+	})
+
+	t.Run("should not record unhandled events", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Metrics:       bolt.MetricsOptions{Enabled: true},
+		})
+		require.NoError(t, err)
+
+		var acked bool
+		require.NoError(t, app.ProcessEvent(context.Background(), mentionEvent(&acked)))
+		assert.Equal(t, int64(0), app.Metrics().Processing.Count)
+	})
+
+	t.Run("should return empty metrics when disabled", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return args.Ack(nil) })
+
+		var acked bool
+		require.NoError(t, app.ProcessEvent(context.Background(), mentionEvent(&acked)))
+		assert.Equal(t, bolt.MetricsSnapshot{}, app.Metrics())
+	})
+
+	t.Run("should fail when the expvar name is already published", func(t *testing.T) {
+		options := bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Metrics:       bolt.MetricsOptions{Enabled: true, ExpvarName: "bolt_test_duplicate_metrics"},
+		}
+		_, err := bolt.New(options)
+		require.NoError(t, err)
+		_, err = bolt.New(options)
+		require.Error(t, err)
+	})
+}
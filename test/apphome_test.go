@@ -0,0 +1,200 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/apphome"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// homeView returns a Home tab showing text
+func homeView(text string) slack.HomeTabViewRequest {
+	return slack.HomeTabViewRequest{
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+		}},
+	}
+}
+
+// publishedText returns the text of the Home tab published by call
+func publishedText(call bolttest.Call) string {
+	view, _ := call.Params["view"].(map[string]any)
+	blocks, _ := view["blocks"].([]any)
+	if len(blocks) == 0 {
+		return ""
+	}
+	block, _ := blocks[0].(map[string]any)
+	text, _ := block["text"].(map[string]any)
+	value, _ := text["text"].(string)
+	return value
+}
+
+func newHomeManager(t *testing.T, options apphome.Options) *apphome.Manager {
+	t.Helper()
+	manager := apphome.NewManager(options)
+	t.Cleanup(func() { _ = manager.Close(context.Background()) })
+	return manager
+}
+
+func TestAppHomeManager(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should skip publishing unchanged views", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		client := fake.APIClient()
+		manager := newHomeManager(t, apphome.Options{})
+
+		published, err := manager.Publish(ctx, client, "T1", "U1", homeView("hello"))
+		require.NoError(t, err)
+		assert.True(t, published)
+
+		published, err = manager.Publish(ctx, client, "T1", "U1", homeView("hello"))
+		require.NoError(t, err)
+		assert.False(t, published)
+
+		published, err = manager.Publish(ctx, client, "T1", "U1", homeView("updated"))
+		require.NoError(t, err)
+		assert.True(t, published)
+
+		calls := fake.Calls("views.publish")
+		require.Len(t, calls, 2)
+		assert.Equal(t, "U1", calls[0].Params["user_id"])
+		assert.Equal(t, "home", calls[0].Params["view"].(map[string]any)["type"])
+		assert.Nil(t, calls[0].Params["hash"])
+		assert.Equal(t, "bolttest-hash", calls[1].Params["hash"])
+
+		state, err := manager.State("T1", "U1")
+		require.NoError(t, err)
+		require.NotNil(t, state)
+		assert.Equal(t, "V0000TEST", state.ViewID)
+		assert.Equal(t, "bolttest-hash", state.Hash)
+	})
+
+	t.Run("should republish current content after a hash conflict", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		publishes := 0
+		fake.HandleFunc("views.publish", func(call bolttest.Call) any {
+			publishes++
+			if call.Params["hash"] == "stale" {
+				return map[string]any{"ok": false, "error": "hash_conflict"}
+			}
+			return map[string]any{"ok": true, "view": map[string]any{"id": "V1", "hash": fmt.Sprintf("hash-%d", publishes)}}
+		})
+		store := apphome.NewMemoryStore()
+		require.NoError(t, store.Set("T1:U1", apphome.ViewState{Hash: "stale", Digest: "old"}))
+		manager := newHomeManager(t, apphome.Options{
+			Store: store,
+			Render: func(ctx context.Context, teamID, userID string) (slack.HomeTabViewRequest, error) {
+				return homeView("rendered for " + userID), nil
+			},
+		})
+
+		published, err := manager.Publish(ctx, fake.APIClient(), "T1", "U1", homeView("outdated"))
+		require.NoError(t, err)
+		assert.True(t, published)
+
+		calls := fake.Calls("views.publish")
+		require.Len(t, calls, 2)
+		assert.Equal(t, "stale", calls[0].Params["hash"])
+		assert.Nil(t, calls[1].Params["hash"])
+		assert.Equal(t, "rendered for U1", publishedText(calls[1]))
+
+		state, err := manager.State("T1", "U1")
+		require.NoError(t, err)
+		assert.Equal(t, "hash-2", state.Hash)
+
+		// The rendered view is now the one published
+		published, err = manager.Publish(ctx, fake.APIClient(), "T1", "U1", homeView("rendered for U1"))
+		require.NoError(t, err)
+		assert.False(t, published)
+	})
+
+	t.Run("should return errors other than hash conflicts", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		fake.Handle("views.publish", map[string]any{"ok": false, "error": "not_enabled"})
+		manager := newHomeManager(t, apphome.Options{})
+
+		published, err := manager.Publish(ctx, fake.APIClient(), "T1", "U1", homeView("hello"))
+		assert.False(t, published)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not_enabled")
+		assert.Len(t, fake.Calls("views.publish"), 1)
+	})
+
+	t.Run("should coalesce scheduled updates into one publish per user", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		client := fake.APIClient()
+		manager := newHomeManager(t, apphome.Options{BatchInterval: time.Hour})
+
+		for i := range 5 {
+			manager.Schedule(client, "T1", "U1", homeView(fmt.Sprintf("update %d", i)))
+		}
+		manager.Schedule(client, "T1", "U2", homeView("other user"))
+		require.NoError(t, manager.Flush(ctx))
+
+		calls := fake.Calls("views.publish")
+		require.Len(t, calls, 2)
+		texts := map[any]string{}
+		for _, call := range calls {
+			texts[call.Params["user_id"]] = publishedText(call)
+		}
+		assert.Equal(t, map[any]string{"U1": "update 4", "U2": "other user"}, texts)
+	})
+
+	t.Run("should publish scheduled views in the background and on close", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		client := fake.APIClient()
+		manager := apphome.NewManager(apphome.Options{BatchInterval: 10 * time.Millisecond})
+
+		manager.Schedule(client, "T1", "U1", homeView("background"))
+		assert.Eventually(t, func() bool {
+			return len(fake.Calls("views.publish")) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		closing := apphome.NewManager(apphome.Options{BatchInterval: time.Hour})
+		closing.Schedule(client, "T1", "U2", homeView("on close"))
+		require.NoError(t, closing.Close(ctx))
+		require.NoError(t, manager.Close(ctx))
+		assert.Len(t, fake.Calls("views.publish"), 2)
+	})
+
+	t.Run("should serialize concurrent publishes to a user", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		client := fake.APIClient()
+		manager := newHomeManager(t, apphome.Options{})
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := manager.Publish(ctx, client, "T1", "U1", homeView("same"))
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, fake.Calls("views.publish"), 1)
+	})
+
+	t.Run("should publish any view after forgetting a user", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		client := fake.APIClient()
+		manager := newHomeManager(t, apphome.Options{})
+
+		_, err := manager.Publish(ctx, client, "T1", "U1", homeView("hello"))
+		require.NoError(t, err)
+		require.NoError(t, manager.Forget("T1", "U1"))
+
+		published, err := manager.Publish(ctx, client, "T1", "U1", homeView("hello"))
+		require.NoError(t, err)
+		assert.True(t, published)
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/Asafrose/bolt-go/pkg/assistant"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/errors"
 	"github.com/Asafrose/bolt-go/pkg/helpers"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/slack-go/slack"
@@ -536,6 +538,14 @@ func TestAssistantComprehensive(t *testing.T) {
 	})
 
 	t.Run("assistant args/utilities", func(t *testing.T) {
+		// recordSay returns a say function recording the arguments of the message it posts
+		recordSay := func(said *types.SayArguments) types.SayFn {
+			return func(message types.SayMessage) (*types.SayResponse, error) {
+				*said = message.(types.SayArguments)
+				return &types.SayResponse{OK: true}, nil
+			}
+		}
+
 		t.Run("say should call chat.postMessage", func(t *testing.T) {
 			botToken := "test"
 			args := assistant.AllAssistantMiddlewareArgs{
@@ -546,6 +556,9 @@ func TestAssistantComprehensive(t *testing.T) {
 				},
 			}
 
+			var said types.SayArguments
+			args.Say = recordSay(&said)
+
 			store := assistant.NewDefaultThreadContextStore()
 			enrichedArgs := assistant.EnrichAssistantArgs(store, args)
 
@@ -553,6 +566,7 @@ func TestAssistantComprehensive(t *testing.T) {
 			assert.NotNil(t, enrichedArgs.Say)
 			_, err := enrichedArgs.Say(types.SayString("Hello world"))
 			require.NoError(t, err)
+			assert.Equal(t, "Hello world", said.Text)
 		})
 
 		t.Run("say should be called with message_metadata that includes thread context", func(t *testing.T) {
@@ -565,6 +579,9 @@ func TestAssistantComprehensive(t *testing.T) {
 				},
 			}
 
+			var said types.SayArguments
+			args.Say = recordSay(&said)
+
 			store := assistant.NewDefaultThreadContextStore()
 			enrichedArgs := assistant.EnrichAssistantArgs(store, args)
 
@@ -579,6 +596,11 @@ func TestAssistantComprehensive(t *testing.T) {
 				},
 			})
 			require.NoError(t, err)
+			assert.Equal(t, "Hello", said.Text)
+			assert.Equal(t, &slack.SlackMetadata{
+				EventType:    "assistant_thread_context",
+				EventPayload: map[string]interface{}{"key": "value"},
+			}, said.Metadata)
 		})
 
 		t.Run("say should be called with message_metadata that supplements thread context", func(t *testing.T) {
@@ -602,6 +624,8 @@ func TestAssistantComprehensive(t *testing.T) {
 			})
 			require.NoError(t, err)
 
+			var said types.SayArguments
+			args.Say = recordSay(&said)
 			enrichedArgs := assistant.EnrichAssistantArgs(store, args)
 
 			_, err = enrichedArgs.Say(&types.SayArguments{
@@ -614,6 +638,10 @@ func TestAssistantComprehensive(t *testing.T) {
 				},
 			})
 			require.NoError(t, err)
+			assert.Equal(t, &slack.SlackMetadata{
+				EventType:    "assistant_thread_context",
+				EventPayload: map[string]interface{}{"new": "data"},
+			}, said.Metadata)
 		})
 
 		t.Run("say should get context from store if no thread context is included in event", func(t *testing.T) {
@@ -634,58 +662,104 @@ func TestAssistantComprehensive(t *testing.T) {
 			assert.NotNil(t, context)
 		})
 
-		t.Run("setStatus should call assistant.threads.setStatus", func(t *testing.T) {
-			botToken := "test"
-			args := assistant.AllAssistantMiddlewareArgs{
+		// threadArgs returns args for a user message in thread 1700000000.000100 of D123, calling
+		// the fake Slack
+		threadArgs := func(slackAPI *bolttest.Slack) assistant.AllAssistantMiddlewareArgs {
+			return assistant.AllAssistantMiddlewareArgs{
 				AllMiddlewareArgs: types.AllMiddlewareArgs{
-					Context: &types.Context{BotToken: botToken},
-					Client:  &slack.Client{},
-					Logger:  slog.Default(),
+					Context: &types.Context{
+						BotToken: "test",
+						RawBody:  []byte(`{"type":"event_callback","event":{"type":"message","channel":"D123","thread_ts":"1700000000.000100","channel_type":"im"}}`),
+					},
+					Client: slackAPI.APIClient(),
+					Logger: slog.Default(),
 				},
 			}
+		}
 
+		t.Run("setStatus should call assistant.threads.setStatus", func(t *testing.T) {
+			slackAPI := bolttest.NewSlack(t)
 			store := assistant.NewDefaultThreadContextStore()
-			enrichedArgs := assistant.EnrichAssistantArgs(store, args)
+			enrichedArgs := assistant.EnrichAssistantArgs(store, threadArgs(slackAPI))
 
 			err := enrichedArgs.SetStatus("in_progress")
 			require.NoError(t, err)
+			slackAPI.AssertCalledWith(t, "assistant.threads.setStatus", map[string]any{
+				"channel_id": "D123",
+				"thread_ts":  "1700000000.000100",
+				"status":     "in_progress",
+			})
 		})
 
 		t.Run("setSuggestedPrompts should call assistant.threads.setSuggestedPrompts", func(t *testing.T) {
-			botToken := "test"
-			args := assistant.AllAssistantMiddlewareArgs{
-				AllMiddlewareArgs: types.AllMiddlewareArgs{
-					Context: &types.Context{BotToken: botToken},
-					Client:  &slack.Client{},
-					Logger:  slog.Default(),
-				},
-			}
-
+			slackAPI := bolttest.NewSlack(t)
 			store := assistant.NewDefaultThreadContextStore()
-			enrichedArgs := assistant.EnrichAssistantArgs(store, args)
+			enrichedArgs := assistant.EnrichAssistantArgs(store, threadArgs(slackAPI))
 
-			prompts := []string{"What can you help with?", "Show me examples"}
+			prompts := []assistant.AssistantPrompt{
+				{Title: "Help", Message: "What can you help with?"},
+				{Title: "Examples", Message: "Show me examples"},
+			}
 			err := enrichedArgs.SetSuggestedPrompts(assistant.SetSuggestedPromptsArguments{
 				Prompts: prompts,
 			})
 			require.NoError(t, err)
+
+			calls := slackAPI.Calls("assistant.threads.setSuggestedPrompts")
+			require.Len(t, calls, 1)
+			assert.Equal(t, "D123", calls[0].Params["channel_id"])
+			assert.Equal(t, "1700000000.000100", calls[0].Params["thread_ts"])
+			assert.Contains(t, calls[0].Params["prompts"], "Show me examples")
 		})
 
 		t.Run("setTitle should call assistant.threads.setTitle", func(t *testing.T) {
-			botToken := "test"
-			args := assistant.AllAssistantMiddlewareArgs{
-				AllMiddlewareArgs: types.AllMiddlewareArgs{
-					Context: &types.Context{BotToken: botToken},
-					Client:  &slack.Client{},
-					Logger:  slog.Default(),
-				},
-			}
-
+			slackAPI := bolttest.NewSlack(t)
 			store := assistant.NewDefaultThreadContextStore()
-			enrichedArgs := assistant.EnrichAssistantArgs(store, args)
+			enrichedArgs := assistant.EnrichAssistantArgs(store, threadArgs(slackAPI))
 
 			err := enrichedArgs.SetTitle("My Assistant Thread")
 			require.NoError(t, err)
+			slackAPI.AssertCalledWith(t, "assistant.threads.setTitle", map[string]any{
+				"channel_id": "D123",
+				"thread_ts":  "1700000000.000100",
+				"title":      "My Assistant Thread",
+			})
+		})
+
+		t.Run("say should post in the thread with the say of the args", func(t *testing.T) {
+			slackAPI := bolttest.NewSlack(t)
+			args := threadArgs(slackAPI)
+			var said types.SayArguments
+			args.Say = func(message types.SayMessage) (*types.SayResponse, error) {
+				said = message.(types.SayArguments)
+				return &types.SayResponse{Channel: said.Channel}, nil
+			}
+			enrichedArgs := assistant.EnrichAssistantArgs(assistant.NewDefaultThreadContextStore(), args)
+
+			response, err := enrichedArgs.Say(types.SayString("Hello"))
+			require.NoError(t, err)
+			assert.Equal(t, "D123", response.Channel)
+			assert.Equal(t, "Hello", said.Text)
+			assert.Equal(t, "D123", said.Channel)
+			assert.Equal(t, "1700000000.000100", said.ThreadTS)
+		})
+
+		t.Run("say should fail without the say of the args", func(t *testing.T) {
+			enrichedArgs := assistant.EnrichAssistantArgs(assistant.NewDefaultThreadContextStore(), threadArgs(bolttest.NewSlack(t)))
+
+			_, err := enrichedArgs.Say(types.SayString("Hello"))
+			require.ErrorIs(t, err, errors.ContextMissingPropertyErrorCode)
+		})
+
+		t.Run("utilities should fail without a thread", func(t *testing.T) {
+			slackAPI := bolttest.NewSlack(t)
+			args := threadArgs(slackAPI)
+			args.Context.RawBody = nil
+			enrichedArgs := assistant.EnrichAssistantArgs(assistant.NewDefaultThreadContextStore(), args)
+
+			err := enrichedArgs.SetStatus("in_progress")
+			require.ErrorIs(t, err, errors.AssistantMissingPropertyErrorCode)
+			slackAPI.AssertNotCalled(t, "assistant.threads.setStatus")
 		})
 	})
 
@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/assistant"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -227,3 +230,133 @@ func TestAssistantUtilities(t *testing.T) {
 		assert.NotNil(t, assistant)
 	})
 }
+
+func TestAssistantThreadUtilities(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should bind status, prompts and title to the thread that started", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		a, err := bolt.NewAssistant(bolt.AssistantConfig{
+			ThreadStarted: []bolt.AssistantThreadStartedMiddleware{func(args bolt.AssistantThreadStartedMiddlewareArgs) error {
+				if err := args.SetStatus("is thinking..."); err != nil {
+					return err
+				}
+				if err := args.SetTitle("Trip planning"); err != nil {
+					return err
+				}
+				return args.SetSuggestedPrompts(assistant.SetSuggestedPromptsArguments{
+					Prompts: []assistant.AssistantPrompt{{Title: "Flights", Message: "Find me a flight"}},
+				})
+			}},
+			UserMessage: []bolt.AssistantUserMessageMiddleware{func(args bolt.AssistantUserMessageMiddlewareArgs) error {
+				return nil
+			}},
+		})
+		require.NoError(t, err)
+		h.App.Assistant(a)
+
+		h.Send(bolttest.Event("assistant_thread_started", map[string]any{
+			"assistant_thread": map[string]any{"user_id": bolttest.UserID, "channel_id": "D123", "thread_ts": "1700000000.000200"},
+		})).AssertNoError(t)
+
+		thread := map[string]any{"channel_id": "D123", "thread_ts": "1700000000.000200"}
+		h.Slack.AssertCalledWith(t, "assistant.threads.setStatus", map[string]any{"channel_id": "D123", "thread_ts": "1700000000.000200", "status": "is thinking..."})
+		h.Slack.AssertCalledWith(t, "assistant.threads.setTitle", map[string]any{"channel_id": "D123", "thread_ts": "1700000000.000200", "title": "Trip planning"})
+		h.Slack.AssertCalledWith(t, "assistant.threads.setSuggestedPrompts", thread)
+	})
+}
+
+func TestAssistantThreadContext(t *testing.T) {
+	t.Parallel()
+
+	thread := map[string]any{"user_id": bolttest.UserID, "channel_id": "D123", "thread_ts": "1700000000.000200"}
+	threadWithContext := map[string]any{
+		"user_id":    bolttest.UserID,
+		"channel_id": "D123",
+		"thread_ts":  "1700000000.000200",
+		"context":    map[string]any{"channel_id": "C456", "team_id": bolttest.TeamID},
+	}
+	userMessage := bolttest.Event("message", map[string]any{
+		"text":         "plan my trip",
+		"channel":      "D123",
+		"channel_type": "im",
+		"thread_ts":    "1700000000.000200",
+	})
+
+	t.Run("should say in the thread with its context as metadata", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		a, err := bolt.NewAssistant(bolt.AssistantConfig{
+			ThreadStarted: []bolt.AssistantThreadStartedMiddleware{func(args bolt.AssistantThreadStartedMiddlewareArgs) error {
+				threadContext, err := args.GetThreadContext()
+				if err != nil {
+					return err
+				}
+				assert.Equal(t, "C456", threadContext.Context["channel_id"])
+				_, err = args.Say(types.SayString("How can I help?"))
+				return err
+			}},
+			UserMessage: []bolt.AssistantUserMessageMiddleware{func(args bolt.AssistantUserMessageMiddlewareArgs) error {
+				return nil
+			}},
+		})
+		require.NoError(t, err)
+		h.App.Assistant(a)
+
+		h.Send(bolttest.Event("assistant_thread_started", map[string]any{"assistant_thread": threadWithContext})).AssertNoError(t)
+
+		said := h.Slack.Said()
+		require.Len(t, said, 1)
+		assert.Equal(t, "How can I help?", said[0].Text())
+		assert.Equal(t, "D123", said[0].Params["channel"])
+		assert.Equal(t, "1700000000.000200", said[0].Params["thread_ts"])
+		assert.Contains(t, said[0].Params["metadata"], `"event_type":"assistant_thread_context"`)
+		assert.Contains(t, said[0].Params["metadata"], `"channel_id":"C456"`)
+	})
+
+	t.Run("should save changed contexts for later messages by default", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var messageContext *bolt.AssistantThreadContext
+		a, err := bolt.NewAssistant(bolt.AssistantConfig{
+			ThreadStarted: []bolt.AssistantThreadStartedMiddleware{func(args bolt.AssistantThreadStartedMiddlewareArgs) error {
+				return nil
+			}},
+			UserMessage: []bolt.AssistantUserMessageMiddleware{func(args bolt.AssistantUserMessageMiddlewareArgs) error {
+				var err error
+				messageContext, err = args.GetThreadContext()
+				return err
+			}},
+		})
+		require.NoError(t, err)
+		h.App.Assistant(a)
+
+		h.Send(bolttest.Event("assistant_thread_started", map[string]any{"assistant_thread": thread})).AssertNoError(t)
+		h.Send(bolttest.Event("assistant_thread_context_changed", map[string]any{"assistant_thread": threadWithContext})).AssertNoError(t)
+		h.Send(userMessage).AssertNoError(t)
+
+		require.NotNil(t, messageContext)
+		assert.Equal(t, "C456", messageContext.Context["channel_id"])
+	})
+
+	t.Run("should reply to user messages in their thread", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		a, err := bolt.NewAssistant(bolt.AssistantConfig{
+			ThreadStarted: []bolt.AssistantThreadStartedMiddleware{func(args bolt.AssistantThreadStartedMiddlewareArgs) error {
+				return nil
+			}},
+			UserMessage: []bolt.AssistantUserMessageMiddleware{func(args bolt.AssistantUserMessageMiddlewareArgs) error {
+				_, err := args.Say(types.SayString("Where to?"))
+				return err
+			}},
+		})
+		require.NoError(t, err)
+		h.App.Assistant(a)
+
+		h.Send(userMessage).AssertNoError(t)
+
+		said := h.Slack.Said()
+		require.Len(t, said, 1)
+		assert.Equal(t, "D123", said[0].Params["channel"])
+		assert.Equal(t, "1700000000.000200", said[0].Params["thread_ts"])
+		assert.NotContains(t, said[0].Params, "metadata")
+	})
+}
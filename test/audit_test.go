@@ -0,0 +1,151 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingAuditSink holds every record until release is closed
+type blockingAuditSink struct {
+	release chan struct{}
+}
+
+func (s *blockingAuditSink) Audit(ctx context.Context, record bolt.AuditRecord) error {
+	<-s.release
+	return nil
+}
+
+// newAuditedApp creates an app auditing to sink with a succeeding and a failing app_mention listener
+func newAuditedApp(t *testing.T, options bolt.AuditOptions, logger bolt.Logger) *bolt.App {
+	receiver := &FakeReceiver{}
+	app, err := bolt.New(bolt.AppOptions{
+		Token:         fakeToken,
+		SigningSecret: fakeSigningSecret,
+		Receiver:      receiver,
+		Audit:         options,
+		Logger:        logger,
+	})
+	require.NoError(t, err)
+
+	app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+	app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return errors.New("listener error") })
+	return app
+}
+
+func TestAuditSink(t *testing.T) {
+	t.Parallel()
+	t.Run("should append a record of each processed event to a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		sink, err := audit.NewFileSink(path)
+		require.NoError(t, err)
+		app := newAuditedApp(t, bolt.AuditOptions{Sink: sink}, nil)
+
+		err = processMention(context.Background(), app, map[string]string{"X-Request-Id": "req-1"})
+		require.Error(t, err)
+		require.NoError(t, app.Stop(context.Background()))
+		require.NoError(t, sink.Close())
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		require.True(t, scanner.Scan())
+
+		var record bolt.AuditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, "req-1", record.CorrelationID)
+		assert.Equal(t, "event", record.Type)
+		assert.Equal(t, "app_mention", record.EventType)
+		assert.Equal(t, "T123456", record.TeamID)
+		assert.Equal(t, "U123456", record.UserID)
+		assert.True(t, record.Handled)
+		assert.Equal(t, bolt.MultipleListenerErrorCode, record.ErrorCode)
+		require.Len(t, record.Listeners, 2)
+		assert.Empty(t, record.Listeners[0].Error)
+		assert.Equal(t, "listener error", record.Listeners[1].Error)
+		assert.False(t, scanner.Scan())
+	})
+
+	t.Run("should post records to a webhook", func(t *testing.T) {
+		bodies := make(chan []byte, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			body, _ := io.ReadAll(r.Body)
+			bodies <- body
+		}))
+		defer server.Close()
+
+		sink, err := audit.NewWebhookSink(audit.WebhookSinkOptions{
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "Bearer secret"},
+		})
+		require.NoError(t, err)
+		app := newAuditedApp(t, bolt.AuditOptions{Sink: sink}, nil)
+
+		_ = processMention(context.Background(), app, map[string]string{"X-Request-Id": "req-2"})
+
+		select {
+		case body := <-bodies:
+			var record bolt.AuditRecord
+			require.NoError(t, json.Unmarshal(body, &record))
+			assert.Equal(t, "req-2", record.CorrelationID)
+			assert.Len(t, record.Listeners, 2)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "webhook was not called")
+		}
+		require.NoError(t, app.Stop(context.Background()))
+	})
+
+	t.Run("should report webhook failures", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		sink, err := audit.NewWebhookSink(audit.WebhookSinkOptions{URL: server.URL})
+		require.NoError(t, err)
+		assert.EqualError(t, sink.Audit(context.Background(), bolt.AuditRecord{}), "audit webhook returned status 500")
+
+		_, err = audit.NewWebhookSink(audit.WebhookSinkOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should drop records instead of blocking event processing when the sink is slow", func(t *testing.T) {
+		var logs bytes.Buffer
+		sink := &blockingAuditSink{release: make(chan struct{})}
+		app := newAuditedApp(t, bolt.AuditOptions{Sink: sink, BufferSize: 1}, slog.New(slog.NewJSONHandler(&logs, nil)))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range 5 {
+				_ = processMention(context.Background(), app, map[string]string{})
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "event processing blocked on the audit sink")
+		}
+
+		close(sink.release)
+		require.NoError(t, app.Stop(context.Background()))
+		assert.Contains(t, logs.String(), "Audit queue full, dropped record")
+	})
+}
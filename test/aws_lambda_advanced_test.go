@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"testing"
@@ -511,6 +512,48 @@ func TestAwsLambdaAdvanced(t *testing.T) {
 		assert.Equal(t, 401, response.StatusCode, "Should return 401 for invalid signature")
 	})
 
+	t.Run("should pass signature verification failures to the authenticity error handler", func(t *testing.T) {
+		var handledArgs []types.ReceiverAuthenticityErrorHandlerArgs
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{
+			SigningSecret: fakeSigningSecret,
+			AuthenticityErrorHandler: func(ctx context.Context, args types.ReceiverAuthenticityErrorHandlerArgs) {
+				handledArgs = append(handledArgs, args)
+			},
+		})
+
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+
+		err = receiver.Init(app)
+		require.NoError(t, err)
+
+		eventBody := `{"type":"event_callback","event":{"type":"app_mention"}}`
+		awsEvent := createDummyAWSEvent(eventBody, time.Now().Unix(), "wrong-secret")
+
+		response, err := receiver.ToHandler()(awsEvent, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 401, response.StatusCode)
+
+		proxyResponse, err := receiver.HandleLambdaEvent(context.Background(), receivers.APIGatewayProxyEvent{
+			HTTPMethod: "POST",
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       eventBody,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 401, proxyResponse.StatusCode)
+
+		require.Len(t, handledArgs, 2)
+		for _, args := range handledArgs {
+			assert.True(t, errors.Is(args.Error, bolt.ReceiverAuthenticityErrorCode))
+			assert.Equal(t, eventBody, string(args.Body))
+			assert.NotNil(t, args.Logger)
+		}
+		assert.Equal(t, awsEvent.Headers["X-Slack-Signature"], handledArgs[0].Headers["X-Slack-Signature"])
+	})
+
 	t.Run("should detect too old request timestamp", func(t *testing.T) {
 		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{
 			SigningSecret: fakeSigningSecret,
@@ -538,6 +581,38 @@ func TestAwsLambdaAdvanced(t *testing.T) {
 		assert.Equal(t, 401, response.StatusCode, "Should return 401 for old timestamp")
 	})
 
+	t.Run("should apply the signature tolerance to past and future timestamps", func(t *testing.T) {
+		var rejected []string
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{
+			SigningSecret:      fakeSigningSecret,
+			SignatureTolerance: 15 * time.Minute,
+			AuthenticityErrorHandler: func(ctx context.Context, args types.ReceiverAuthenticityErrorHandlerArgs) {
+				rejected = append(rejected, args.Error.Error())
+			},
+		})
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+		require.NoError(t, receiver.Init(app))
+		handler := receiver.ToHandler()
+
+		eventBody := `{"type":"url_verification","challenge":"test_challenge","token":"test_token"}`
+		now := time.Now().Unix()
+		for _, timestamp := range []int64{now - 600, now + 600} {
+			response, err := handler(createDummyAWSEvent(eventBody, timestamp, fakeSigningSecret), nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 200, response.StatusCode, "should accept timestamps within the tolerance")
+		}
+		for _, timestamp := range []int64{now - 1200, now + 1200} {
+			response, err := handler(createDummyAWSEvent(eventBody, timestamp, fakeSigningSecret), nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 401, response.StatusCode, "should reject timestamps outside the tolerance")
+		}
+
+		require.Len(t, rejected, 2)
+		assert.Contains(t, rejected[0], "Request timestamp too old")
+		assert.Contains(t, rejected[1], "Request timestamp is in the future")
+	})
+
 	t.Run("should handle SSL check requests", func(t *testing.T) {
 		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{
 			SigningSecret: fakeSigningSecret,
@@ -732,6 +807,82 @@ func TestAwsLambdaAdvanced(t *testing.T) {
 		// The actual logging behavior would need to be tested with a custom logger
 		// For now, we're just ensuring the request completes successfully with 404
 	})
+
+	t.Run("should answer endpoint checks without authorizing or running the app", func(t *testing.T) {
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{
+			SigningSecret: fakeSigningSecret,
+		})
+
+		authorizeCalls := 0
+		app, err := bolt.New(bolt.AppOptions{
+			SigningSecret: fakeSigningSecret,
+			Receiver:      receiver,
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				authorizeCalls++
+				return &bolt.AuthorizeResult{BotToken: fakeToken}, nil
+			},
+		})
+		require.NoError(t, err)
+		app.Use(func(args bolt.AllMiddlewareArgs) error {
+			t.Error("Endpoint checks should not reach middleware")
+			return args.Next()
+		})
+
+		handler := receiver.ToHandler()
+		timestamp := time.Now().Unix()
+
+		verification := `{"token":"test_token","challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P","type":"url_verification"}`
+		response, err := handler(createDummyAWSEvent(verification, timestamp, fakeSigningSecret), nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, response.StatusCode)
+		assert.JSONEq(t, `{"challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"}`, response.Body)
+
+		sslCheck := createDummyAWSEvent("ssl_check=1&token=test_token", timestamp, fakeSigningSecret)
+		sslCheck.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+		response, err = handler(sslCheck, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 200, response.StatusCode)
+
+		proxyResponse, err := receiver.HandleLambdaEvent(context.Background(), receivers.APIGatewayProxyEvent{
+			HTTPMethod: "POST",
+			Headers:    map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			Body:       "ssl_check=1&token=test_token",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 200, proxyResponse.StatusCode)
+
+		assert.Equal(t, 0, authorizeCalls)
+	})
+
+	t.Run("should not treat events mentioning endpoint checks as endpoint checks", func(t *testing.T) {
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{
+			SigningSecret: fakeSigningSecret,
+		})
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Receiver:      receiver,
+		})
+		require.NoError(t, err)
+
+		handled := 0
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			handled++
+			return args.Ack(nil)
+		})
+
+		handler := receiver.ToHandler()
+		bodies := []string{
+			`{"type":"event_callback","team_id":"T123456","event":{"type":"app_mention","user":"U123456","text":"hi","channel":"C123456","topic":"url_verification"}}`,
+			`{"type":"event_callback","team_id":"T123456","event":{"type":"app_mention","user":"U123456","text":"try ssl_check=1","channel":"C123456"}}`,
+		}
+		for _, body := range bodies {
+			response, err := handler(createDummyAWSEvent(body, time.Now().Unix(), fakeSigningSecret), nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 200, response.StatusCode)
+		}
+		assert.Equal(t, 2, handled, "Both events should reach the listener")
+	})
 }
 
 // Helper function to create a dummy AWS event with valid signature
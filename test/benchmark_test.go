@@ -0,0 +1,240 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// benchmarkPayload is an incoming request type exercised by the ProcessEvent benchmarks
+type benchmarkPayload struct {
+	name string
+	body []byte
+	// register adds a listener for key, the value of the payload's primary constraint
+	register func(app *bolt.App, key string)
+}
+
+// benchmarkPayloads covers each incoming event class; the matching listener is registered for "target"
+var benchmarkPayloads = []benchmarkPayload{
+	{
+		name: "event",
+		body: createAppMentionEventBody(),
+		register: func(app *bolt.App, key string) {
+			app.Event(types.SlackEventType(eventTypeFor(key, "app_mention")), func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+		},
+	},
+	{
+		name: "message",
+		body: createMessageEventBody(),
+		register: func(app *bolt.App, key string) {
+			app.Message(messagePatternFor(key, "hello"), func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+		},
+	},
+	{
+		name: "command",
+		body: createSlashCommandBody("/target", "hello"),
+		register: func(app *bolt.App, key string) {
+			app.Command("/"+key, func(args bolt.SlackCommandMiddlewareArgs) error { return args.Ack(nil) })
+		},
+	},
+	{
+		name: "action",
+		body: createBlockActionBody("target", "block"),
+		register: func(app *bolt.App, key string) {
+			app.Action(types.ActionConstraints{ActionID: key}, func(args bolt.SlackActionMiddlewareArgs) error { return args.Ack(nil) })
+		},
+	},
+	{
+		name: "shortcut",
+		body: createGlobalShortcutBody("target"),
+		register: func(app *bolt.App, key string) {
+			app.Shortcut(types.ShortcutConstraints{CallbackID: key}, func(args bolt.SlackShortcutMiddlewareArgs) error { return args.Ack(nil) })
+		},
+	},
+	{
+		name: "view",
+		body: createViewSubmissionBody("target"),
+		register: func(app *bolt.App, key string) {
+			app.View(types.ViewConstraints{CallbackID: key}, func(args bolt.SlackViewMiddlewareArgs) error { return args.Ack(nil) })
+		},
+	},
+	{
+		name: "options",
+		body: createOptionsRequestBody("target", "block"),
+		register: func(app *bolt.App, key string) {
+			app.Options(types.OptionsConstraints{ActionID: key}, func(args bolt.SlackOptionsMiddlewareArgs) error { return args.Ack(nil) })
+		},
+	},
+}
+
+// benchmarkLogger discards logs so they do not distort measurements
+var benchmarkLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// benchmarkListenerCounts are the numbers of listeners registered per payload type
+var benchmarkListenerCounts = []int{1, 10, 100}
+
+// eventTypeFor maps the matching key to the event type of the payload and other keys to unrelated event types
+func eventTypeFor(key, matching string) string {
+	if key == "target" {
+		return matching
+	}
+	return "custom_event_" + key
+}
+
+// messagePatternFor maps the matching key to a pattern found in the message and other keys to absent patterns
+func messagePatternFor(key, matching string) string {
+	if key == "target" {
+		return matching
+	}
+	return "absent " + key
+}
+
+// newBenchmarkApp creates an app with listeners-1 non-matching listeners followed by the matching one
+func newBenchmarkApp(tb testing.TB, payload benchmarkPayload, listeners int, options bolt.AppOptions) *bolt.App {
+	options.Token = fakeToken
+	options.SigningSecret = fakeSigningSecret
+	options.Logger = benchmarkLogger
+	app, err := bolt.New(options)
+	require.NoError(tb, err)
+
+	for i := 0; i < listeners-1; i++ {
+		payload.register(app, fmt.Sprintf("other_%d", i))
+	}
+	payload.register(app, "target")
+	return app
+}
+
+// benchmarkReceiverEvent wraps body in a receiver event that is acknowledged without doing any work
+func benchmarkReceiverEvent(body []byte) types.ReceiverEvent {
+	return types.ReceiverEvent{
+		Body:    body,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Ack:     func(response types.AckResponse) error { return nil },
+	}
+}
+
+// benchmarkProcessEvent runs ProcessEvent for event b.N times
+func benchmarkProcessEvent(b *testing.B, app *bolt.App, event types.ReceiverEvent) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := app.ProcessEvent(ctx, event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProcessEventPayloads(b *testing.B) {
+	for _, payload := range benchmarkPayloads {
+		for _, listeners := range benchmarkListenerCounts {
+			b.Run(fmt.Sprintf("%s/listeners=%d", payload.name, listeners), func(b *testing.B) {
+				app := newBenchmarkApp(b, payload, listeners, bolt.AppOptions{})
+				benchmarkProcessEvent(b, app, benchmarkReceiverEvent(payload.body))
+			})
+		}
+	}
+}
+
+func BenchmarkProcessEventUnhandled(b *testing.B) {
+	for _, listeners := range benchmarkListenerCounts {
+		b.Run(fmt.Sprintf("listeners=%d", listeners), func(b *testing.B) {
+			app, err := bolt.New(bolt.AppOptions{
+				Token:         fakeToken,
+				SigningSecret: fakeSigningSecret,
+				Logger:        benchmarkLogger,
+			})
+			require.NoError(b, err)
+			for i := 0; i < listeners; i++ {
+				app.Event(types.SlackEventType(fmt.Sprintf("custom_event_%d", i)), func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+			}
+
+			benchmarkProcessEvent(b, app, benchmarkReceiverEvent(createAppMentionEventBody()))
+		})
+	}
+}
+
+func BenchmarkReceivers(b *testing.B) {
+	b.Run("aws_lambda", func(b *testing.B) {
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{
+			SigningSecret: fakeSigningSecret,
+		})
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Receiver:      receiver,
+			Logger:        benchmarkLogger,
+		})
+		require.NoError(b, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return args.Ack(nil) })
+
+		handler := receiver.ToHandler()
+		awsEvent := createDummyAWSEvent(string(createAppMentionEventBody()), time.Now().Unix(), fakeSigningSecret)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			response, err := handler(awsEvent, nil, nil)
+			if err != nil || response.StatusCode != 200 {
+				b.Fatalf("unexpected response %d: %v", response.StatusCode, err)
+			}
+		}
+	})
+}
+
+// processEventAllocationBudgets are upper bounds on allocations per ProcessEvent with 100
+// listeners registered, leaving headroom over the measured values so only real regressions fail
+var processEventAllocationBudgets = map[string]float64{
+	"event":    260,
+	"message":  430,
+	"command":  160,
+	"action":   360,
+	"shortcut": 260,
+	"view":     600,
+	"options":  270,
+}
+
+func TestProcessEventPerformanceRegression(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation budgets in short mode")
+	}
+
+	for _, payload := range benchmarkPayloads {
+		t.Run(fmt.Sprintf("should stay within the allocation budget for %s payloads", payload.name), func(t *testing.T) {
+			app := newBenchmarkApp(t, payload, 100, bolt.AppOptions{})
+			event := benchmarkReceiverEvent(payload.body)
+
+			allocs := testing.AllocsPerRun(50, func() {
+				_ = app.ProcessEvent(context.Background(), event)
+			})
+			assert.LessOrEqual(t, allocs, processEventAllocationBudgets[payload.name])
+		})
+	}
+
+	t.Run("should not allocate per listener for unhandled events", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Logger:        benchmarkLogger,
+		})
+		require.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			app.Event(types.SlackEventType(fmt.Sprintf("custom_event_%d", i)), func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+		}
+		event := benchmarkReceiverEvent(createAppMentionEventBody())
+
+		allocs := testing.AllocsPerRun(50, func() {
+			_ = app.ProcessEvent(context.Background(), event)
+		})
+		assert.Less(t, allocs, float64(100))
+	})
+}
@@ -0,0 +1,162 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBolttestHarness(t *testing.T) {
+	t.Parallel()
+	t.Run("should capture say calls of event and message listeners", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err := args.Say(types.SayString("hi there"))
+			return err
+		})
+		h.App.Message("ping", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err := args.Say(types.SayString("pong"))
+			return err
+		})
+
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+		h.Send(bolttest.Message("ping")).AssertNoError(t)
+
+		h.Slack.AssertSaid(t, "hi there")
+		h.Slack.AssertSaid(t, "pong")
+		said := h.Slack.Said()
+		require.Len(t, said, 2)
+		assert.Equal(t, bolttest.ChannelID, said[0].Params["channel"])
+		assert.Equal(t, "https://slack.com/api/chat.postMessage", said[0].URL)
+	})
+
+	t.Run("should record command acks and respond calls", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Command("/echo", func(args bolt.SlackCommandMiddlewareArgs) error {
+			if err := args.Ack(&types.CommandResponse{Text: "echoing"}); err != nil {
+				return err
+			}
+			_, err := args.Respond(t.Context(), &types.RespondArguments{Text: args.Command.Text})
+			return err
+		})
+
+		result := h.Send(bolttest.Command("/echo", "hi")).AssertNoError(t).AssertAcked(t)
+
+		assert.Equal(t, "echoing", result.AckJSON(t)["text"])
+		h.Slack.AssertResponded(t, "hi")
+		assert.Equal(t, bolttest.ResponseURL, h.Slack.Responded()[0].URL)
+	})
+
+	t.Run("should route interactions to their listeners", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var called []string
+		h.App.Action(bolt.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			called = append(called, "action")
+			return args.Ack(nil)
+		})
+		h.App.Options(bolt.OptionsConstraints{ActionID: "pick"}, func(args bolt.SlackOptionsMiddlewareArgs) error {
+			called = append(called, "options")
+			return args.Ack(&types.OptionsResponse{})
+		})
+		h.App.Shortcut(bolt.ShortcutConstraints{CallbackID: "open"}, func(args bolt.SlackShortcutMiddlewareArgs) error {
+			called = append(called, "shortcut")
+			return args.Ack(nil)
+		})
+		h.App.View(bolt.ViewConstraints{CallbackID: "form", Type: "view_submission"}, func(args bolt.SlackViewMiddlewareArgs) error {
+			called = append(called, "view "+args.View.Values["title_block"]["title"].(map[string]interface{})["value"].(string))
+			return args.Ack(&types.ViewResponse{ResponseAction: "clear"})
+		})
+		h.App.View(bolt.ViewConstraints{CallbackID: "form", Type: "view_closed"}, func(args bolt.SlackViewMiddlewareArgs) error {
+			called = append(called, "closed")
+			return args.Ack(nil)
+		})
+
+		h.Send(bolttest.BlockAction("approve", "yes")).AssertNoError(t).AssertAcked(t)
+		h.Send(bolttest.BlockSuggestion("pick", "a")).AssertNoError(t).AssertAcked(t)
+		h.Send(bolttest.GlobalShortcut("open")).AssertNoError(t).AssertAcked(t)
+		h.Send(bolttest.MessageShortcut("open", "some message")).AssertNoError(t).AssertAcked(t)
+		submission := h.Send(bolttest.ViewSubmission("form", map[string]any{
+			"title_block": map[string]any{"title": map[string]any{"type": "plain_text_input", "value": "Hello"}},
+		})).AssertNoError(t).AssertAcked(t)
+		h.Send(bolttest.ViewClosed("form")).AssertNoError(t).AssertAcked(t)
+
+		assert.Equal(t, []string{"action", "options", "shortcut", "shortcut", "view Hello", "closed"}, called)
+		assert.Equal(t, "clear", submission.AckJSON(t)["response_action"])
+	})
+
+	t.Run("should return API responses set on the fake Slack server", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.Slack.Handle("chat.postMessage", map[string]any{"ok": false, "error": "channel_not_found"})
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err := args.Say(types.SayString("hi"))
+			return err
+		})
+
+		result := h.Send(bolttest.AppMention("hello")).AssertError(t)
+		assert.Contains(t, result.Err.Error(), "channel_not_found")
+		h.Slack.AssertCalled(t, "chat.postMessage")
+	})
+}
+
+func TestBolttestSlackServer(t *testing.T) {
+	t.Parallel()
+	t.Run("should answer common Web API methods like Slack", func(t *testing.T) {
+		api := bolttest.NewSlack(t)
+		client := api.APIClient()
+
+		channel, ts, err := client.PostMessage(bolttest.ChannelID, slack.MsgOptionText("first", false))
+		require.NoError(t, err)
+		assert.Equal(t, bolttest.ChannelID, channel)
+		_, nextTS, err := client.PostMessage(bolttest.ChannelID, slack.MsgOptionText("second", false))
+		require.NoError(t, err)
+		assert.NotEqual(t, ts, nextTS)
+
+		view, err := client.OpenView("1.0.bolttest", slack.ModalViewRequest{
+			Type:       slack.VTModal,
+			CallbackID: "form",
+			Title:      slack.NewTextBlockObject(slack.PlainTextType, "Form", false, false),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "V0000TEST", view.ID)
+		assert.Equal(t, "form", view.CallbackID)
+
+		auth, err := client.AuthTest()
+		require.NoError(t, err)
+		assert.Equal(t, bolttest.TeamID, auth.TeamID)
+		assert.Equal(t, bolttest.BotUserID, auth.UserID)
+
+		require.NoError(t, client.FunctionCompleteSuccess("Fx0000TEST"))
+
+		api.AssertCalledWith(t, "chat.postMessage", map[string]any{"channel": bolttest.ChannelID, "text": "second"})
+		api.AssertCalledWith(t, "views.open", map[string]any{"trigger_id": "1.0.bolttest"})
+		api.AssertCalledWith(t, "functions.completeSuccess", map[string]any{"function_execution_id": "Fx0000TEST"})
+		api.AssertNotCalled(t, "views.update")
+		assert.Equal(t, bolttest.BotToken, api.Calls("views.open")[0].Token)
+	})
+
+	t.Run("should answer with responses built by HandleFunc", func(t *testing.T) {
+		api := bolttest.NewSlack(t)
+		api.HandleFunc("users.info", func(call bolttest.Call) any {
+			return map[string]any{"ok": true, "user": map[string]any{"id": call.Params["user"], "name": "someone"}}
+		})
+
+		user, err := api.APIClient().GetUserInfo("U0000SOME")
+		require.NoError(t, err)
+		assert.Equal(t, "U0000SOME", user.ID)
+		assert.Equal(t, "someone", user.Name)
+	})
+
+	t.Run("should point API clients at the server with its API URL", func(t *testing.T) {
+		api := bolttest.NewSlack(t)
+		client := slack.New("xoxb-other", slack.OptionAPIURL(api.APIURL()))
+
+		_, err := client.AuthTest()
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-other", api.Calls("auth.test")[0].Token)
+	})
+}
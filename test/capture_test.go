@@ -0,0 +1,112 @@
+package test
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/capture"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+	t.Run("should strip secrets and volatile headers from events", func(t *testing.T) {
+		fixture := capture.Normalize(types.ReceiverEvent{
+			Body: []byte(`{"token":"verification","team_id":"T1","authorizations":[{"user_id":"U1"}],` +
+				`"event":{"type":"app_mention","text":"<@U1> use xoxb-123-abc","bot_access_token":"xoxb-456"},"event_time":1700000000}`),
+			Headers: map[string]string{
+				"content-type":              "application/json",
+				"X-Slack-Signature":         "v0=abc",
+				"X-Slack-Request-Timestamp": "1700000000",
+				"X-Slack-Retry-Num":         "1",
+				"Authorization":             "Bearer secret",
+			},
+			RetryNum: 1,
+		})
+
+		assert.Equal(t, map[string]string{"Content-Type": "application/json", "X-Slack-Retry-Num": "1"}, fixture.Headers)
+		assert.Equal(t, 1, fixture.RetryNum)
+		assert.JSONEq(t, `{"token":"REDACTED","team_id":"T1","authorizations":[{"user_id":"U1"}],`+
+			`"event":{"type":"app_mention","text":"<@U1> use REDACTED","bot_access_token":"REDACTED"},"event_time":1700000000}`, fixture.Body)
+		assert.Equal(t, "app_mention", fixture.Kind())
+	})
+
+	t.Run("should decode interaction payloads into JSON bodies", func(t *testing.T) {
+		payload := `{"type":"block_actions","token":"verification","response_url":"https://hooks.slack.com/actions/T1/1/secret"}`
+		fixture := capture.Normalize(types.ReceiverEvent{
+			Body:    []byte(url.Values{"payload": {payload}}.Encode()),
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		})
+
+		assert.Equal(t, "application/json", fixture.Headers["Content-Type"])
+		assert.JSONEq(t, `{"type":"block_actions","token":"REDACTED","response_url":"`+capture.RedactedResponseURL+`"}`, fixture.Body)
+		assert.Equal(t, "block_actions", fixture.Kind())
+	})
+
+	t.Run("should redact slash command form fields", func(t *testing.T) {
+		fixture := capture.Normalize(types.ReceiverEvent{
+			Body: []byte(url.Values{
+				"token":        {"verification"},
+				"command":      {"/deploy"},
+				"text":         {"now"},
+				"response_url": {"https://hooks.slack.com/commands/T1/1/secret"},
+			}.Encode()),
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		})
+
+		values, err := url.ParseQuery(fixture.Body)
+		require.NoError(t, err)
+		assert.Equal(t, capture.Redacted, values.Get("token"))
+		assert.Equal(t, capture.RedactedResponseURL, values.Get("response_url"))
+		assert.Equal(t, "now", values.Get("text"))
+		assert.Equal(t, "deploy", fixture.Kind())
+	})
+
+	t.Run("should write fixtures from a receiver that replay through bolttest", func(t *testing.T) {
+		dir := t.TempDir()
+		writer, err := capture.NewWriter(dir)
+		require.NoError(t, err)
+
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{
+			SigningSecret: fakeSigningSecret,
+			Capture:       writer,
+		})
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Receiver:      receiver,
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+
+		body := string(bolttest.AppMention("hello").Body)
+		_, err = receiver.ToHandler()(createDummyAWSEvent(body, time.Now().Unix(), fakeSigningSecret), nil, nil)
+		require.NoError(t, err)
+
+		paths, err := capture.Paths(dir)
+		require.NoError(t, err)
+		require.Len(t, paths, 1)
+		assert.Contains(t, filepath.Base(paths[0]), "-app_mention.json")
+
+		data, err := os.ReadFile(paths[0])
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "bolttest-verification-token")
+		assert.NotContains(t, string(data), "X-Slack-Signature")
+
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err := args.Say(types.SayString("replayed " + args.Event.GetType()))
+			return err
+		})
+		h.Send(bolttest.LoadFixture(t, paths[0])).AssertNoError(t)
+		h.Slack.AssertSaid(t, "replayed app_mention")
+	})
+}
@@ -0,0 +1,257 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfig writes content to a config file named name in a temporary directory
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// setConfigEnv sets an environment variable for the duration of the test. t.Setenv cannot be
+// used by parallel tests, so each test uses its own variable names.
+func setConfigEnv(t *testing.T, name, value string) {
+	t.Helper()
+	require.NoError(t, os.Setenv(name, value))
+	t.Cleanup(func() { _ = os.Unsetenv(name) })
+}
+
+// serveRoute serves a request to the custom route of options at path
+func serveRoute(t *testing.T, options bolt.AppOptions, method, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	for _, route := range options.CustomRoutes {
+		if route.Path == path {
+			recorder := httptest.NewRecorder()
+			route.Handler(recorder, httptest.NewRequest(method, path, nil))
+			return recorder
+		}
+	}
+	require.Failf(t, "missing custom route", "no custom route for %s", path)
+	return nil
+}
+
+func TestConfigFiles(t *testing.T) {
+	t.Parallel()
+	t.Run("should load options from a YAML file", func(t *testing.T) {
+		config, err := bolt.LoadConfig(writeConfig(t, "app.yaml", `
+token: xoxb-yaml
+signing_secret: yaml-secret
+client_id: "1234.5678"
+scopes: [chat:write, commands]
+port: 8080
+endpoints:
+  events: /slack/events
+  commands: /slack/commands
+log_level: debug
+ignore_self: false
+`))
+		require.NoError(t, err)
+
+		options, err := config.AppOptions(bolt.ConfigOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-yaml", options.Token)
+		assert.Equal(t, "yaml-secret", options.SigningSecret)
+		assert.Equal(t, "1234.5678", options.ClientID)
+		assert.Equal(t, []string{"chat:write", "commands"}, options.Scopes)
+		assert.Equal(t, 8080, options.Port)
+		assert.Equal(t, "/slack/commands", options.Endpoints.Commands)
+		require.NotNil(t, options.LogLevel)
+		assert.Equal(t, types.LogLevelDebug, *options.LogLevel)
+		require.NotNil(t, options.IgnoreSelf)
+		assert.False(t, *options.IgnoreSelf)
+		assert.False(t, options.SocketMode)
+	})
+
+	t.Run("should load options from a JSON file", func(t *testing.T) {
+		config, err := bolt.LoadConfig(writeConfig(t, "app.json", `{
+			"token": "xoxb-json",
+			"app_token": "xapp-json",
+			"receiver": "socket_mode",
+			"developer_mode": true
+		}`))
+		require.NoError(t, err)
+
+		options, err := config.AppOptions(bolt.ConfigOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-json", options.Token)
+		assert.Equal(t, "xapp-json", options.AppToken)
+		assert.True(t, options.SocketMode)
+		assert.True(t, options.DeveloperMode)
+	})
+
+	t.Run("should replace environment variables", func(t *testing.T) {
+		setConfigEnv(t, "BOLT_CONFIG_TEST_TOKEN", "xoxb-from-env")
+		setConfigEnv(t, "BOLT_CONFIG_TEST_PORT", "9090")
+		setConfigEnv(t, "BOLT_CONFIG_TEST_TEAM", "T123")
+
+		config, err := bolt.ParseConfig([]byte(`
+token: ${BOLT_CONFIG_TEST_TOKEN}
+signing_secret: ${BOLT_CONFIG_TEST_UNSET_SECRET:-fallback-secret}
+port: ${BOLT_CONFIG_TEST_PORT}
+redirect_uri: https://example.com/${BOLT_CONFIG_TEST_TEAM}/oauth
+`))
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-from-env", config.Token)
+		assert.Equal(t, "fallback-secret", config.SigningSecret)
+		assert.Equal(t, 9090, config.Port)
+		assert.Equal(t, "https://example.com/T123/oauth", config.RedirectURI)
+	})
+
+	t.Run("should keep numeric environment variables as strings in string fields", func(t *testing.T) {
+		setConfigEnv(t, "BOLT_CONFIG_TEST_NUMERIC_SECRET", "0123")
+
+		config, err := bolt.ParseConfig([]byte("signing_secret: ${BOLT_CONFIG_TEST_NUMERIC_SECRET}\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "0123", config.SigningSecret)
+	})
+
+	t.Run("should report unset environment variables", func(t *testing.T) {
+		_, err := bolt.ParseConfig([]byte(`
+token: ${BOLT_CONFIG_TEST_MISSING_TOKEN}
+signing_secret: ${BOLT_CONFIG_TEST_MISSING_SECRET}
+`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BOLT_CONFIG_TEST_MISSING_TOKEN, BOLT_CONFIG_TEST_MISSING_SECRET")
+	})
+
+	t.Run("should reject unknown keys", func(t *testing.T) {
+		_, err := bolt.ParseConfig([]byte("tokn: xoxb-typo\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tokn")
+	})
+
+	t.Run("should reject unknown receivers and log levels", func(t *testing.T) {
+		_, err := (&bolt.Config{Receiver: "carrier_pigeon"}).AppOptions(bolt.ConfigOptions{})
+		assert.Error(t, err)
+
+		_, err = (&bolt.Config{LogLevel: "loud"}).AppOptions(bolt.ConfigOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should serve custom routes with named handlers or static responses", func(t *testing.T) {
+		config, err := bolt.ParseConfig([]byte(`
+custom_routes:
+  - path: /health
+    method: get
+    body: ok
+  - path: /teapot
+    status: 418
+    content_type: application/json
+    body: '{"short":"stout"}'
+  - path: /status
+    handler: status
+`))
+		require.NoError(t, err)
+
+		options, err := config.AppOptions(bolt.ConfigOptions{
+			Handlers: map[string]http.HandlerFunc{
+				"status": func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("all good")) },
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, options.CustomRoutes, 3)
+
+		health := serveRoute(t, options, http.MethodGet, "/health")
+		assert.Equal(t, http.StatusOK, health.Code)
+		assert.Equal(t, "ok", health.Body.String())
+		assert.Equal(t, http.StatusMethodNotAllowed, serveRoute(t, options, http.MethodPost, "/health").Code)
+
+		teapot := serveRoute(t, options, http.MethodPost, "/teapot")
+		assert.Equal(t, http.StatusTeapot, teapot.Code)
+		assert.Equal(t, "application/json", teapot.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"short":"stout"}`, teapot.Body.String())
+
+		assert.Equal(t, "all good", serveRoute(t, options, http.MethodGet, "/status").Body.String())
+
+		_, err = config.AppOptions(bolt.ConfigOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should create an app serving the configured port and routes", func(t *testing.T) {
+		_, port, err := net.SplitHostPort(freeAddr(t))
+		require.NoError(t, err)
+		setConfigEnv(t, "BOLT_CONFIG_TEST_APP_PORT", port)
+
+		configured := false
+		app, err := bolt.NewFromConfig(writeConfig(t, "app.yaml", `
+token: xoxb-test
+signing_secret: secret
+port: ${BOLT_CONFIG_TEST_APP_PORT}
+custom_routes:
+  - path: /health
+    body: ok
+`), bolt.ConfigOptions{
+			Configure: func(options *bolt.AppOptions) { configured = true },
+		})
+		require.NoError(t, err)
+		assert.True(t, configured)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = app.Start(ctx) }()
+		t.Cleanup(func() { _ = app.Stop(context.Background()) })
+
+		var body string
+		require.Eventually(t, func() bool {
+			resp, err := http.Get("http://127.0.0.1:" + port + "/health")
+			if err != nil {
+				return false
+			}
+			defer resp.Body.Close()
+			data, _ := io.ReadAll(resp.Body)
+			body = string(data)
+			return resp.StatusCode == http.StatusOK
+		}, 5*time.Second, 20*time.Millisecond)
+		assert.Equal(t, "ok", body)
+	})
+	t.Run("should serve OAuth installs with the configured client", func(t *testing.T) {
+		_, port, err := net.SplitHostPort(freeAddr(t))
+		require.NoError(t, err)
+		setConfigEnv(t, "BOLT_CONFIG_TEST_OAUTH_PORT", port)
+
+		app, err := bolt.NewFromConfig(writeConfig(t, "app.yaml", `
+signing_secret: secret
+client_id: "1234.5678"
+client_secret: client-secret
+state_secret: state-secret
+redirect_uri: https://acme.example.com/slack/oauth_redirect
+scopes: [chat:write, commands]
+port: ${BOLT_CONFIG_TEST_OAUTH_PORT}
+`), bolt.ConfigOptions{
+			Configure: func(options *bolt.AppOptions) {
+				options.InstallationStore = oauth.NewMemoryInstallationStore()
+			},
+		})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = app.Start(ctx) }()
+		t.Cleanup(func() { _ = app.Stop(context.Background()) })
+
+		page := serveInstallPage(t, "http://127.0.0.1:"+port)
+		assert.Contains(t, page, "client_id=1234.5678")
+		assert.Contains(t, page, "scope=chat%3Awrite%2Ccommands")
+		assert.Contains(t, page, "redirect_uri=https%3A%2F%2Facme.example.com%2Fslack%2Foauth_redirect")
+		// Signed with the state secret, the state is a JWT
+		assert.Regexp(t, `state=[\w-]+\.[\w-]+\.[\w-]+`, page)
+	})
+}
@@ -3,6 +3,8 @@ package test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -159,6 +161,80 @@ func TestMemoryStore(t *testing.T) {
 		assert.Equal(t, "user", retrievedState.UserName)
 		assert.True(t, retrievedState.Count >= 0 && retrievedState.Count < 10)
 	})
+
+	t.Run("should keep many concurrent conversations separate", func(t *testing.T) {
+		store := conversation.NewMemoryStore()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 200; i++ {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				conversationID := fmt.Sprintf("C%06d", index)
+				assert.NoError(t, store.Set(conversationID, index, nil))
+				value, err := store.Get(conversationID)
+				assert.NoError(t, err)
+				assert.Equal(t, index, value)
+				if index%2 == 0 {
+					assert.NoError(t, store.Delete(conversationID))
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < 200; i++ {
+			value, err := store.Get(fmt.Sprintf("C%06d", i))
+			if i%2 == 0 {
+				assert.Error(t, err)
+				continue
+			}
+			require.NoError(t, err)
+			assert.Equal(t, i, value)
+		}
+	})
+
+	t.Run("should clean up expired conversations in every shard", func(t *testing.T) {
+		store := conversation.NewMemoryStore()
+		expired := time.Now().Add(-time.Minute)
+		future := time.Now().Add(time.Hour)
+
+		for i := 0; i < 100; i++ {
+			require.NoError(t, store.Set(fmt.Sprintf("expired_%d", i), i, &expired))
+			require.NoError(t, store.Set(fmt.Sprintf("active_%d", i), i, &future))
+		}
+		store.CleanupExpired()
+
+		for i := 0; i < 100; i++ {
+			_, err := store.Get(fmt.Sprintf("expired_%d", i))
+			assert.EqualError(t, err, "conversation not found")
+			_, err = store.Get(fmt.Sprintf("active_%d", i))
+			assert.NoError(t, err)
+		}
+	})
+}
+
+func BenchmarkMemoryStoreParallel(b *testing.B) {
+	store := conversation.NewMemoryStore()
+	conversationIDs := make([]string, 1024)
+	for i := range conversationIDs {
+		conversationIDs[i] = fmt.Sprintf("C%06d", i)
+		_ = store.Set(conversationIDs[i], i, nil)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			conversationID := conversationIDs[i%len(conversationIDs)]
+			if i%4 == 0 {
+				_ = store.Set(conversationID, i, nil)
+			} else {
+				_, _ = store.Get(conversationID)
+			}
+			i++
+		}
+	})
 }
 
 func TestConversationMiddleware(t *testing.T) {
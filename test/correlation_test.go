@@ -0,0 +1,134 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// processMention runs an app_mention event with headers through app
+func processMention(ctx context.Context, app *bolt.App, headers map[string]string) error {
+	headers["Content-Type"] = "application/json"
+	return app.ProcessEvent(ctx, types.ReceiverEvent{
+		Body:    createAppMentionEventBody(),
+		Headers: headers,
+		Ack:     func(response types.AckResponse) error { return nil },
+	})
+}
+
+func TestCorrelationID(t *testing.T) {
+	t.Parallel()
+	t.Run("should propagate the request ID header to listeners, logs and error handlers", func(t *testing.T) {
+		var logs bytes.Buffer
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Logger:        slog.New(slog.NewJSONHandler(&logs, nil)),
+		})
+		require.NoError(t, err)
+
+		var listenerID string
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			listenerID = args.Context.CorrelationID
+			args.Logger.Info("handling mention")
+			return errors.New("listener error")
+		})
+		var handlerContextID, handlerCtxID string
+		app.ExtendedError(func(ctx context.Context, err error, logger bolt.Logger, body interface{}, context *bolt.Context, event bolt.ReceiverEvent) error {
+			handlerContextID = context.CorrelationID
+			handlerCtxID = bolt.CorrelationIDFromContext(ctx)
+			logger.Error("handled", "error", err)
+			return nil
+		})
+
+		require.NoError(t, processMention(context.Background(), app, map[string]string{"x-request-id": "req-123"}))
+
+		assert.Equal(t, "req-123", listenerID)
+		assert.Equal(t, "req-123", handlerContextID)
+		assert.Equal(t, "req-123", handlerCtxID)
+		lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+		require.Len(t, lines, 2)
+		for _, line := range lines {
+			var record map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			assert.Equal(t, "req-123", record["correlation_id"], record["msg"])
+		}
+	})
+
+	t.Run("should prefer the context ID, then X-Correlation-Id", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+
+		var ids []string
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			ids = append(ids, args.Context.CorrelationID)
+			return nil
+		})
+
+		headers := func() map[string]string {
+			return map[string]string{"X-Request-Id": "request", "X-Correlation-Id": "correlation"}
+		}
+		require.NoError(t, processMention(bolt.WithCorrelationID(context.Background(), "from-context"), app, headers()))
+		require.NoError(t, processMention(context.Background(), app, headers()))
+
+		assert.Equal(t, []string{"from-context", "correlation"}, ids)
+	})
+
+	t.Run("should generate a distinct ID when the header is missing or invalid", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+
+		var ids []string
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			ids = append(ids, args.Context.CorrelationID)
+			return nil
+		})
+
+		require.NoError(t, processMention(context.Background(), app, map[string]string{}))
+		require.NoError(t, processMention(context.Background(), app, map[string]string{"X-Request-Id": "bad id\r\nX-Injected: 1"}))
+
+		require.Len(t, ids, 2)
+		for _, id := range ids {
+			assert.NotEmpty(t, id)
+			assert.NotContains(t, id, " ")
+		}
+		assert.NotEqual(t, ids[0], ids[1])
+	})
+
+	t.Run("should send the ID in the User-Agent of Slack API calls", func(t *testing.T) {
+		userAgents := make(chan string, 1)
+		slackAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgents <- r.UserAgent()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true,"user_id":"U123456","team_id":"T123456"}`))
+		}))
+		defer slackAPI.Close()
+
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			ClientOptions: []slack.Option{slack.OptionAPIURL(slackAPI.URL + "/api/")},
+		})
+		require.NoError(t, err)
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err := args.Client.AuthTest()
+			return err
+		})
+
+		require.NoError(t, processMention(context.Background(), app, map[string]string{"X-Request-Id": "req-456"}))
+		assert.Equal(t, "bolt-go (correlation_id=req-456)", <-userAgents)
+	})
+}
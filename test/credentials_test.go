@@ -0,0 +1,138 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretProvider returns the credentials it was last set to
+type fakeSecretProvider struct {
+	mu          sync.Mutex
+	credentials bolt.Credentials
+	err         error
+}
+
+func (p *fakeSecretProvider) Credentials(ctx context.Context) (bolt.Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.credentials, p.err
+}
+
+func (p *fakeSecretProvider) set(credentials bolt.Credentials) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.credentials = credentials
+}
+
+// newLambdaApp creates an app behind a Lambda receiver verifying requests with signingSecret
+func newLambdaApp(t *testing.T, signingSecret string, credentials bolt.CredentialsOptions) (*bolt.App, receivers.AwsHandler) {
+	t.Helper()
+	receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{SigningSecret: signingSecret})
+	app, err := bolt.New(bolt.AppOptions{
+		Token:         fakeToken,
+		SigningSecret: signingSecret,
+		Receiver:      receiver,
+		Credentials:   credentials,
+	})
+	require.NoError(t, err)
+	app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+	return app, receiver.ToHandler()
+}
+
+// lambdaAccepts reports whether handler accepts a mention signed with signingSecret
+func lambdaAccepts(t *testing.T, handler receivers.AwsHandler, signingSecret string) bool {
+	t.Helper()
+	body := string(bolttest.AppMention("hello").Body)
+	response, err := handler(createDummyAWSEvent(body, time.Now().Unix(), signingSecret), nil, nil)
+	require.NoError(t, err)
+	return response.StatusCode != 401
+}
+
+func TestCredentialRotation(t *testing.T) {
+	t.Parallel()
+	t.Run("should fill in missing credentials from the secret provider", func(t *testing.T) {
+		provider := &fakeSecretProvider{credentials: bolt.Credentials{SigningSecret: fakeSigningSecret, Token: fakeToken}}
+		_, err := bolt.New(bolt.AppOptions{Credentials: bolt.CredentialsOptions{Provider: provider}})
+		require.NoError(t, err)
+
+		provider.err = errors.New("vault sealed")
+		_, err = bolt.New(bolt.AppOptions{Credentials: bolt.CredentialsOptions{Provider: provider}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "vault sealed")
+	})
+
+	t.Run("should use a rotated token for listeners and app.Client", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, err := args.Say(types.SayString("hi"))
+			return err
+		})
+
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+		require.NoError(t, h.App.UpdateCredentials(context.Background(), bolt.Credentials{Token: "xoxb-rotated"}))
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		said := h.Slack.Said()
+		require.Len(t, said, 2)
+		assert.Equal(t, bolttest.BotToken, said[0].Token)
+		assert.Equal(t, "xoxb-rotated", said[1].Token)
+
+		_, err := h.App.Client.AuthTest()
+		require.NoError(t, err)
+		calls := h.Slack.Calls("auth.test")
+		assert.Equal(t, "xoxb-rotated", calls[len(calls)-1].Token)
+	})
+
+	t.Run("should not set a token on apps using an authorize function", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return &bolt.AuthorizeResult{BotToken: bolttest.BotToken}, nil
+			},
+		})
+		assert.Error(t, h.App.UpdateCredentials(context.Background(), bolt.Credentials{Token: "xoxb-rotated"}))
+	})
+
+	t.Run("should accept the previous signing secret during the rotation window", func(t *testing.T) {
+		app, handler := newLambdaApp(t, fakeSigningSecret, bolt.CredentialsOptions{SigningSecretWindow: 200 * time.Millisecond})
+		require.NoError(t, app.UpdateCredentials(context.Background(), bolt.Credentials{SigningSecret: "rotated-secret"}))
+
+		assert.True(t, lambdaAccepts(t, handler, "rotated-secret"))
+		assert.True(t, lambdaAccepts(t, handler, fakeSigningSecret))
+		assert.False(t, lambdaAccepts(t, handler, "unknown-secret"))
+
+		time.Sleep(250 * time.Millisecond)
+		assert.False(t, lambdaAccepts(t, handler, fakeSigningSecret))
+		assert.True(t, lambdaAccepts(t, handler, "rotated-secret"))
+	})
+
+	t.Run("should refresh credentials from the secret provider until the app stops", func(t *testing.T) {
+		provider := &fakeSecretProvider{}
+		app, handler := newLambdaApp(t, fakeSigningSecret, bolt.CredentialsOptions{
+			Provider:        provider,
+			RefreshInterval: 10 * time.Millisecond,
+		})
+
+		provider.set(bolt.Credentials{SigningSecret: "rotated-secret"})
+		assert.Eventually(t, func() bool {
+			return lambdaAccepts(t, handler, "rotated-secret")
+		}, 5*time.Second, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, app.Stop(ctx))
+
+		provider.set(bolt.Credentials{SigningSecret: "late-secret"})
+		time.Sleep(50 * time.Millisecond)
+		assert.False(t, lambdaAccepts(t, handler, "late-secret"))
+	})
+}
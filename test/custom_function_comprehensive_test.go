@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
 	"github.com/Asafrose/bolt-go/pkg/functions"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/slack-go/slack"
@@ -131,8 +132,10 @@ func TestCustomFunctionValidate(t *testing.T) {
 func TestCustomFunctionUtilityFunctions(t *testing.T) {
 	t.Parallel()
 	t.Run("complete should call functions.completeSuccess", func(t *testing.T) {
-		// Create a mock client
-		client := slack.New("fake-token")
+		// Create a client calling a fake Slack server that rejects the token
+		api := bolttest.NewSlack(t)
+		api.Handle("functions.completeSuccess", map[string]any{"ok": false, "error": "invalid_auth"})
+		client := api.APIClient()
 
 		// Test the createFunctionComplete factory function
 		context := map[string]interface{}{
@@ -143,10 +146,11 @@ func TestCustomFunctionUtilityFunctions(t *testing.T) {
 		complete := functions.CreateFunctionComplete(context, client)
 		assert.NotNil(t, complete)
 
-		// Call complete - this will now make a real API call and should get invalid_auth
+		// Call complete - the fake server answers with invalid_auth
 		err := complete(map[string]interface{}{})
 		require.Error(t, err, "Complete function should error with invalid auth")
 		assert.Contains(t, err.Error(), "invalid_auth", "Should get invalid_auth error from Slack API")
+		api.AssertCalledWith(t, "functions.completeSuccess", map[string]any{"function_execution_id": "Fx1234"})
 	})
 
 	t.Run("should throw if no functionExecutionId present on context", func(t *testing.T) {
@@ -174,8 +178,10 @@ func TestCustomFunctionUtilityFunctions(t *testing.T) {
 	})
 
 	t.Run("fail should call functions.completeError", func(t *testing.T) {
-		// Create a mock client
-		client := slack.New("fake-token")
+		// Create a client calling a fake Slack server that rejects the token
+		api := bolttest.NewSlack(t)
+		api.Handle("functions.completeError", map[string]any{"ok": false, "error": "invalid_auth"})
+		client := api.APIClient()
 
 		// Test the createFunctionFail factory function
 		context := map[string]interface{}{
@@ -186,10 +192,11 @@ func TestCustomFunctionUtilityFunctions(t *testing.T) {
 		fail := functions.CreateFunctionFail(context, client)
 		assert.NotNil(t, fail)
 
-		// Call fail - this will now make a real API call and should get invalid_auth
+		// Call fail - the fake server answers with invalid_auth
 		err := fail("boom")
 		require.Error(t, err, "Fail function should error with invalid auth")
 		assert.Contains(t, err.Error(), "invalid_auth", "Should get invalid_auth error from Slack API")
+		api.AssertCalledWith(t, "functions.completeError", map[string]any{"function_execution_id": "Fx1234", "error": "boom"})
 	})
 
 	t.Run("should throw if no functionExecutionId present on context for fail", func(t *testing.T) {
@@ -160,7 +160,7 @@ func TestCustomFunctionRouting(t *testing.T) {
 		options := bolt.CustomFunctionOptions{AutoAcknowledge: false}
 
 		// Register function handler with auto-acknowledge disabled
-		app.Function("my_id", options, func(args bolt.SlackCustomFunctionMiddlewareArgs) error {
+		app.FunctionWithOptions("my_id", options, func(args bolt.SlackCustomFunctionMiddlewareArgs) error {
 			handlerCalled = true
 			return args.Next()
 		})
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/dedupe"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedisClient implements SET NX and DEL over a map
+type fakeRedisClient struct {
+	mu   sync.Mutex
+	keys map[string]time.Duration
+}
+
+func (c *fakeRedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.keys[key]; exists {
+		return redis.NewBoolResult(false, nil)
+	}
+	c.keys[key] = expiration
+	return redis.NewBoolResult(true, nil)
+}
+
+func (c *fakeRedisClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.keys, key)
+	}
+	return redis.NewIntResult(int64(len(keys)), nil)
+}
+
+// failingDeduper fails every call
+type failingDeduper struct{}
+
+func (failingDeduper) Seen(ctx context.Context, key string) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func (failingDeduper) Forget(ctx context.Context, key string) error {
+	return errors.New("store unavailable")
+}
+
+func TestEventDeduplication(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should run listeners once and acknowledge redeliveries", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Deduper: bolt.NewMemoryEventDeduper(0)})
+		var runs atomic.Int32
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			runs.Add(1)
+			return nil
+		})
+
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+		retry := bolttest.AppMention("hello")
+		retry.RetryNum = 1
+		h.Send(retry).AssertNoError(t).AssertAcked(t)
+
+		assert.EqualValues(t, 1, runs.Load())
+	})
+
+	t.Run("should deduplicate socket mode requests by envelope ID", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Deduper: bolt.NewMemoryEventDeduper(time.Minute)})
+		var runs atomic.Int32
+		h.App.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error {
+			runs.Add(1)
+			return args.Ack(nil)
+		})
+
+		command := func(envelopeID string) bolttest.Payload {
+			payload := bolttest.Command("/deploy", "now")
+			payload.Headers[types.EnvelopeIDHeader] = envelopeID
+			return payload
+		}
+		h.Send(command("env-1")).AssertNoError(t)
+		h.Send(command("env-1")).AssertNoError(t).AssertAcked(t)
+		h.Send(command("env-2")).AssertNoError(t)
+		h.Send(bolttest.Command("/deploy", "now")).AssertNoError(t)
+
+		assert.EqualValues(t, 3, runs.Load())
+	})
+
+	t.Run("should process an event again after a retryable failure", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Deduper: bolt.NewMemoryEventDeduper(0)})
+		var runs atomic.Int32
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			if runs.Add(1) == 1 {
+				return bolt.NewRetryableError(errors.New("database down"))
+			}
+			return nil
+		})
+
+		h.Send(bolttest.AppMention("hello")).AssertError(t)
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		assert.EqualValues(t, 2, runs.Load())
+	})
+
+	t.Run("should process events when the deduper fails", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Deduper: failingDeduper{}})
+		var runs atomic.Int32
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			runs.Add(1)
+			return nil
+		})
+
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		assert.EqualValues(t, 2, runs.Load())
+	})
+
+	t.Run("should forget keys once their TTL passes", func(t *testing.T) {
+		ctx := context.Background()
+		deduper := bolt.NewMemoryEventDeduper(20 * time.Millisecond)
+
+		seen, err := deduper.Seen(ctx, "event:Ev1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+		seen, err = deduper.Seen(ctx, "event:Ev1")
+		require.NoError(t, err)
+		assert.True(t, seen)
+
+		time.Sleep(30 * time.Millisecond)
+		seen, err = deduper.Seen(ctx, "event:Ev1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+	})
+
+	t.Run("should share processed events between apps through Redis", func(t *testing.T) {
+		client := &fakeRedisClient{keys: map[string]time.Duration{}}
+		deduper, err := dedupe.NewRedisDeduper(dedupe.RedisDeduperOptions{Client: client, TTL: 10 * time.Minute})
+		require.NoError(t, err)
+
+		var runs atomic.Int32
+		replicas := make([]*bolttest.Harness, 2)
+		for i := range replicas {
+			replicas[i] = bolttest.New(t, bolt.AppOptions{Deduper: deduper})
+			replicas[i].App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+				runs.Add(1)
+				return nil
+			})
+		}
+		replicas[0].Send(bolttest.AppMention("hello")).AssertNoError(t)
+		replicas[1].Send(bolttest.AppMention("hello")).AssertNoError(t).AssertAcked(t)
+
+		assert.EqualValues(t, 1, runs.Load())
+		assert.Equal(t, map[string]time.Duration{"bolt:dedupe:event:Ev0000TEST": 10 * time.Minute}, client.keys)
+
+		require.NoError(t, deduper.Forget(context.Background(), "event:Ev0000TEST"))
+		assert.Empty(t, client.keys)
+	})
+
+	t.Run("should require a Redis client", func(t *testing.T) {
+		_, err := dedupe.NewRedisDeduper(dedupe.RedisDeduperOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setSlackEnv sets the environment variables read by NewFromEnv, clearing the others
+func setSlackEnv(t *testing.T, values map[string]string) {
+	t.Helper()
+	for _, name := range []string{
+		bolt.EnvBotToken, bolt.EnvAppToken, bolt.EnvSigningSecret, bolt.EnvClientID, bolt.EnvClientSecret,
+		bolt.EnvStateSecret, bolt.EnvRedirectURI, bolt.EnvScopes, bolt.EnvLogLevel, bolt.EnvPort,
+	} {
+		t.Setenv(name, values[name])
+	}
+}
+
+// Not parallel, since it sets the process environment
+func TestNewFromEnv(t *testing.T) {
+	t.Run("should create an HTTP app from the environment", func(t *testing.T) {
+		setSlackEnv(t, map[string]string{
+			bolt.EnvBotToken:      fakeToken,
+			bolt.EnvSigningSecret: fakeSigningSecret,
+			bolt.EnvScopes:        "chat:write, commands,",
+			bolt.EnvLogLevel:      "WARN",
+			bolt.EnvPort:          "8080",
+		})
+
+		options, err := bolt.OptionsFromEnv(bolt.AppOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, fakeToken, options.Token)
+		assert.Equal(t, fakeSigningSecret, options.SigningSecret)
+		assert.Equal(t, []string{"chat:write", "commands"}, options.Scopes)
+		require.NotNil(t, options.LogLevel)
+		assert.Equal(t, types.LogLevelWarn, *options.LogLevel)
+		assert.Equal(t, 8080, options.Port)
+		assert.False(t, options.SocketMode)
+
+		app, err := bolt.NewFromEnv()
+		require.NoError(t, err)
+		assert.NotNil(t, app)
+	})
+
+	t.Run("should select Socket Mode when an app token is set", func(t *testing.T) {
+		setSlackEnv(t, map[string]string{
+			bolt.EnvBotToken: fakeToken,
+			bolt.EnvAppToken: fakeAppToken,
+		})
+
+		options, err := bolt.OptionsFromEnv(bolt.AppOptions{})
+		require.NoError(t, err)
+		assert.True(t, options.SocketMode)
+		assert.Equal(t, fakeAppToken, options.AppToken)
+
+		_, err = bolt.NewFromEnv()
+		require.NoError(t, err)
+	})
+
+	t.Run("should prefer options over the environment", func(t *testing.T) {
+		setSlackEnv(t, map[string]string{
+			bolt.EnvBotToken:      "xoxb-from-env",
+			bolt.EnvSigningSecret: "secret-from-env",
+			bolt.EnvPort:          "8080",
+		})
+
+		options, err := bolt.OptionsFromEnv(bolt.AppOptions{Token: fakeToken, Port: 9090})
+		require.NoError(t, err)
+		assert.Equal(t, fakeToken, options.Token)
+		assert.Equal(t, "secret-from-env", options.SigningSecret)
+		assert.Equal(t, 9090, options.Port)
+	})
+
+	t.Run("should report all missing and invalid variables at once", func(t *testing.T) {
+		setSlackEnv(t, map[string]string{
+			bolt.EnvClientID: "1234.5678",
+			bolt.EnvLogLevel: "loud",
+			bolt.EnvPort:     "http",
+		})
+
+		_, err := bolt.NewFromEnv()
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, bolt.AppInitializationErrorCode))
+		assert.Contains(t, err.Error(), "missing environment variables: SLACK_SIGNING_SECRET, SLACK_BOT_TOKEN, SLACK_CLIENT_SECRET")
+		assert.Contains(t, err.Error(), `SLACK_LOG_LEVEL="loud"`)
+		assert.Contains(t, err.Error(), `PORT="http"`)
+	})
+
+	t.Run("should not require what an authorize function or receiver provides", func(t *testing.T) {
+		setSlackEnv(t, nil)
+
+		_, err := bolt.OptionsFromEnv(bolt.AppOptions{
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return &bolt.AuthorizeResult{}, nil
+			},
+			Receiver: receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{SigningSecret: fakeSigningSecret}),
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should serve OAuth installs with the client of the environment", func(t *testing.T) {
+		_, port, err := net.SplitHostPort(freeAddr(t))
+		require.NoError(t, err)
+		setSlackEnv(t, map[string]string{
+			bolt.EnvSigningSecret: fakeSigningSecret,
+			bolt.EnvClientID:      "1234.5678",
+			bolt.EnvClientSecret:  "client-secret",
+			bolt.EnvRedirectURI:   "https://acme.example.com/slack/oauth_redirect",
+			bolt.EnvScopes:        "chat:write,commands",
+			bolt.EnvPort:          port,
+		})
+
+		app, err := bolt.NewFromEnv(bolt.AppOptions{InstallationStore: oauth.NewMemoryInstallationStore()})
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = app.Start(ctx) }()
+		t.Cleanup(func() { _ = app.Stop(context.Background()) })
+
+		page := serveInstallPage(t, "http://127.0.0.1:"+port)
+		assert.Contains(t, page, "client_id=1234.5678")
+		assert.Contains(t, page, "scope=chat%3Awrite%2Ccommands")
+		assert.Contains(t, page, "redirect_uri=https%3A%2F%2Facme.example.com%2Fslack%2Foauth_redirect")
+	})
+
+	t.Run("should not read the bot token for an app with an authorize function", func(t *testing.T) {
+		setSlackEnv(t, map[string]string{
+			bolt.EnvBotToken:      fakeToken,
+			bolt.EnvSigningSecret: fakeSigningSecret,
+		})
+
+		options, err := bolt.OptionsFromEnv(bolt.AppOptions{
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return &bolt.AuthorizeResult{BotToken: fakeToken}, nil
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, options.Token)
+
+		_, err = bolt.NewFromEnv(options)
+		require.NoError(t, err)
+	})
+
+	t.Run("should not read or require the bot token for an app with an installation store", func(t *testing.T) {
+		for name, env := range map[string]map[string]string{
+			"set":     {bolt.EnvBotToken: fakeToken, bolt.EnvSigningSecret: fakeSigningSecret},
+			"missing": {bolt.EnvSigningSecret: fakeSigningSecret},
+		} {
+			t.Run(name, func(t *testing.T) {
+				setSlackEnv(t, env)
+
+				app, err := bolt.NewFromEnv(bolt.AppOptions{InstallationStore: oauth.NewMemoryInstallationStore()})
+				require.NoError(t, err)
+				assert.NotNil(t, app)
+			})
+		}
+	})
+}
+
+// serveInstallPage returns the install page of the app serving baseURL once it is up
+func serveInstallPage(t *testing.T, baseURL string) string {
+	t.Helper()
+	var body string
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(baseURL + "/slack/install")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		body = string(data)
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 20*time.Millisecond)
+	return body
+}
@@ -1,12 +1,15 @@
 package test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -107,6 +110,270 @@ func TestListenerErrorHandling(t *testing.T) {
 		assert.True(t, listener1Called || listener2Called, "At least one listener should be called")
 		require.Error(t, err, "Should return error from listeners")
 	})
+
+	t.Run("should expose individual listener failures", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+
+		errFirst := errors.New("listener 1 error")
+		errSecond := errors.New("listener 2 error")
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			return errFirst
+		})
+		app.Event("message", func(args bolt.SlackEventMiddlewareArgs) error {
+			return nil
+		})
+		app.Message("hello", func(args bolt.SlackEventMiddlewareArgs) error {
+			return errSecond
+		})
+
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type": "event_callback",
+			"event": map[string]interface{}{
+				"type":    "message",
+				"user":    "U123456",
+				"text":    "hello world",
+				"channel": "C123456",
+			},
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    bodyBytes,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack: func(response types.AckResponse) error {
+				return nil
+			},
+		})
+		require.Error(t, err)
+
+		var multipleErr *bolt.MultipleListenerError
+		require.True(t, errors.As(err, &multipleErr))
+		assert.True(t, errors.Is(err, errSecond))
+		assert.False(t, errors.Is(err, errFirst))
+
+		failures := multipleErr.Failures()
+		require.Len(t, failures, 1)
+		assert.Equal(t, 2, failures[0].Index)
+		assert.Equal(t, "event(type=message, pattern=hello)", failures[0].Listener)
+		assert.Equal(t, errSecond, failures[0].Err)
+		assert.Contains(t, failures[0].Source, "error_handling_test.go:")
+		assert.Contains(t, err.Error(), failures[0].Source)
+		assert.Contains(t, err.Error(), "listener 2 error")
+	})
+}
+
+func TestErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	newApp := func(t *testing.T, listenerErr error) *bolt.App {
+		t.Helper()
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			return listenerErr
+		})
+		return app
+	}
+
+	process := func(app *bolt.App) error {
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type":    "event_callback",
+			"team_id": "T123456",
+			"event":   map[string]interface{}{"type": "app_mention", "user": "U123456"},
+		})
+		return app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body: bodyBytes,
+			Ack:  func(response types.AckResponse) error { return nil },
+		})
+	}
+
+	t.Run("should receive unhandled listener errors", func(t *testing.T) {
+		listenerErr := errors.New("listener error")
+		app := newApp(t, listenerErr)
+
+		var handledErr error
+		app.Error(func(err error) error {
+			handledErr = err
+			return nil
+		})
+
+		require.NoError(t, process(app), "Handled errors should not be returned to the receiver")
+		assert.True(t, errors.Is(handledErr, listenerErr))
+		assert.True(t, errors.Is(handledErr, bolt.MultipleListenerErrorCode))
+	})
+
+	t.Run("should pass the returned error on to the receiver", func(t *testing.T) {
+		app := newApp(t, errors.New("listener error"))
+
+		reported := errors.New("reported")
+		app.Error(func(err error) error {
+			return reported
+		})
+
+		assert.Equal(t, reported, process(app))
+	})
+
+	t.Run("should replace a previously registered extended error handler", func(t *testing.T) {
+		app := newApp(t, errors.New("listener error"))
+
+		extendedCalled := false
+		app.ExtendedError(func(ctx context.Context, err error, logger bolt.Logger, body interface{}, context *bolt.Context, event bolt.ReceiverEvent) error {
+			extendedCalled = true
+			return nil
+		})
+		handlerCalled := false
+		app.Error(func(err error) error {
+			handlerCalled = true
+			return nil
+		})
+
+		require.NoError(t, process(app))
+		assert.True(t, handlerCalled)
+		assert.False(t, extendedCalled)
+	})
+}
+
+func TestExtendedErrorHandler(t *testing.T) {
+	t.Parallel()
+	t.Run("should receive the originating receiver event", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+
+		listenerErr := errors.New("listener error")
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			return listenerErr
+		})
+
+		var handledErr error
+		var handledContext *bolt.Context
+		var handledEvent bolt.ReceiverEvent
+		app.ExtendedError(func(ctx context.Context, err error, logger bolt.Logger, body interface{}, context *bolt.Context, event bolt.ReceiverEvent) error {
+			handledErr = err
+			handledContext = context
+			handledEvent = event
+			return nil
+		})
+
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type":    "event_callback",
+			"team_id": "T123456",
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"text":    "<@U987654> hello",
+				"channel": "C123456",
+			},
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:        bodyBytes,
+			Headers:     map[string]string{"Content-Type": "application/json"},
+			RetryNum:    2,
+			RetryReason: "http_timeout",
+			Ack: func(response types.AckResponse) error {
+				return nil
+			},
+		})
+
+		require.NoError(t, err, "Handled errors should not be returned to the receiver")
+		assert.True(t, errors.Is(handledErr, listenerErr))
+		require.NotNil(t, handledContext)
+		assert.Equal(t, "T123456", handledContext.TeamID)
+		assert.Equal(t, bodyBytes, handledEvent.Body)
+		assert.Equal(t, "application/json", handledEvent.Headers["Content-Type"])
+		assert.Equal(t, 2, handledEvent.RetryNum)
+		assert.Equal(t, "http_timeout", handledEvent.RetryReason)
+	})
+
+	t.Run("should receive authorization errors with the receiver event", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			SigningSecret: fakeSigningSecret,
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return nil, errors.New("no installation")
+			},
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+
+		var handledEvent bolt.ReceiverEvent
+		app.ExtendedError(func(ctx context.Context, err error, logger bolt.Logger, body interface{}, context *bolt.Context, event bolt.ReceiverEvent) error {
+			handledEvent = event
+			return err
+		})
+
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type":  "event_callback",
+			"event": map[string]interface{}{"type": "app_mention"},
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    bodyBytes,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack: func(response types.AckResponse) error {
+				return nil
+			},
+		})
+
+		assert.True(t, errors.Is(err, bolt.AuthorizationErrorCode))
+		assert.Equal(t, bodyBytes, handledEvent.Body)
+	})
+}
+
+func TestUnhandledErrorLogging(t *testing.T) {
+	t.Parallel()
+	t.Run("should log unhandled errors with structured fields", func(t *testing.T) {
+		var logs bytes.Buffer
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Logger:        slog.New(slog.NewJSONHandler(&logs, nil)),
+		})
+		require.NoError(t, err)
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			return errors.New("listener error")
+		})
+
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type":    "event_callback",
+			"team_id": "T123456",
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"channel": "C123456",
+			},
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:     bodyBytes,
+			Headers:  map[string]string{"Content-Type": "application/json"},
+			RetryNum: 1,
+			Ack: func(response types.AckResponse) error {
+				return nil
+			},
+		})
+		require.Error(t, err)
+
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+		assert.Equal(t, "Unhandled error", record["msg"])
+		assert.Equal(t, string(bolt.MultipleListenerErrorCode), record["error_code"])
+		assert.Equal(t, "app_mention", record["event_type"])
+		assert.Equal(t, "T123456", record["team_id"])
+		assert.Equal(t, "event(type=app_mention)", record["listener"])
+		assert.Equal(t, float64(1), record["retry_num"])
+		assert.Contains(t, record["error"], "listener error")
+	})
 }
 
 func TestMiddlewareErrorHandling(t *testing.T) {
@@ -260,6 +527,46 @@ func TestAuthorizationErrorHandling(t *testing.T) {
 		assert.False(t, listenerCalled, "Listener should not be called when authorization fails")
 	})
 
+	t.Run("should include the authorize source data on authorization errors", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			SigningSecret: fakeSigningSecret,
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return nil, errors.New("installation not found")
+			},
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type":          "event_callback",
+			"team_id":       "T123456",
+			"enterprise_id": "E123456",
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"channel": "C123456",
+			},
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    bodyBytes,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack: func(response types.AckResponse) error {
+				return nil
+			},
+		})
+
+		var authErr *bolterrors.AuthorizationError
+		require.True(t, errors.As(err, &authErr))
+		require.NotNil(t, authErr.Source)
+		assert.Equal(t, "T123456", authErr.Source.TeamID)
+		assert.Equal(t, "E123456", authErr.Source.EnterpriseID)
+		assert.Equal(t, "U123456", authErr.Source.UserID)
+		assert.Equal(t, "C123456", authErr.Source.ConversationID)
+		assert.Contains(t, err.Error(), "team_id=T123456")
+		assert.ErrorContains(t, errors.Unwrap(err), "installation not found")
+	})
+
 	t.Run("should handle missing authorization", func(t *testing.T) {
 		// Create app without token or authorization function
 		_, err := bolt.New(bolt.AppOptions{
@@ -692,4 +999,94 @@ func TestPanicRecovery(t *testing.T) {
 		assert.False(t, listenerCalled, "Listener should not be called when middleware panics")
 		_ = err
 	})
+
+	mentionEvent := func() types.ReceiverEvent {
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type": "event_callback",
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"channel": "C123456",
+			},
+		})
+		return types.ReceiverEvent{
+			Body:    bodyBytes,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack: func(response types.AckResponse) error {
+				return nil
+			},
+		}
+	}
+
+	t.Run("should re-panic with the crash panic policy", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			PanicPolicy:   bolt.PanicPolicyCrash,
+		})
+		require.NoError(t, err)
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			panic("listener panic")
+		})
+
+		assert.PanicsWithValue(t, "listener panic", func() {
+			_ = app.ProcessEvent(context.Background(), mentionEvent())
+		})
+	})
+
+	t.Run("should pass panics to the panic handler with the goroutine stack", func(t *testing.T) {
+		var recovered interface{}
+		var stack []byte
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			PanicPolicy:   bolt.PanicPolicyHandler,
+			PanicHandler: func(r interface{}, s []byte) error {
+				recovered = r
+				stack = s
+				return errors.New("handled panic")
+			},
+		})
+		require.NoError(t, err)
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			panic("listener panic")
+		})
+
+		err = app.ProcessEvent(context.Background(), mentionEvent())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "handled panic")
+		assert.Equal(t, "listener panic", recovered)
+		assert.Contains(t, string(stack), "error_handling_test.go")
+	})
+
+	t.Run("should swallow panics when the panic handler returns nil", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			PanicPolicy:   bolt.PanicPolicyHandler,
+			PanicHandler: func(r interface{}, s []byte) error {
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			panic("listener panic")
+		})
+
+		assert.NoError(t, app.ProcessEvent(context.Background(), mentionEvent()))
+	})
+
+	t.Run("should require a panic handler for the handler panic policy", func(t *testing.T) {
+		_, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			PanicPolicy:   bolt.PanicPolicyHandler,
+		})
+
+		assert.True(t, errors.Is(err, bolt.AppInitializationErrorCode))
+	})
 }
@@ -0,0 +1,191 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/errorreport"
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingErrorReporter keeps the reports it receives
+type recordingErrorReporter struct {
+	mu      sync.Mutex
+	reports []bolt.ErrorReport
+}
+
+func (r *recordingErrorReporter) ReportError(ctx context.Context, report bolt.ErrorReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+func (r *recordingErrorReporter) Reports() []bolt.ErrorReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]bolt.ErrorReport(nil), r.reports...)
+}
+
+// sentryRequest is an envelope received by the fake Sentry server
+type sentryRequest struct {
+	Path  string
+	Auth  string
+	Event map[string]any
+}
+
+// fakeSentry starts a server collecting Sentry envelopes and returns its DSN
+func fakeSentry(t *testing.T) (string, <-chan sentryRequest) {
+	t.Helper()
+	requests := make(chan sentryRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 1<<20), 1<<20)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		request := sentryRequest{Path: r.URL.Path, Auth: r.Header.Get("X-Sentry-Auth")}
+		if len(lines) == 3 && strings.Contains(lines[1], `"type":"event"`) {
+			_ = json.Unmarshal([]byte(lines[2]), &request.Event)
+		}
+		requests <- request
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return strings.Replace(server.URL, "http://", "http://publickey@", 1) + "/42", requests
+}
+
+func TestErrorReporter(t *testing.T) {
+	t.Parallel()
+	t.Run("should report errors reaching the default error handler with a payload summary", func(t *testing.T) {
+		reporter := &recordingErrorReporter{}
+		h := bolttest.New(t, bolt.AppOptions{ErrorReporter: reporter})
+		h.App.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error {
+			return errors.New("deploy failed")
+		})
+
+		h.Send(bolttest.Command("/deploy", "secret release notes"))
+
+		reports := reporter.Reports()
+		require.Len(t, reports, 1)
+		report := reports[0]
+		assert.ErrorContains(t, report.Err, "deploy failed")
+		assert.Equal(t, bolterrors.MultipleListenerErrorCode, report.Code)
+		assert.Equal(t, []string{"command(command=/deploy)"}, report.Listeners)
+		assert.Equal(t, "command", report.Type)
+		assert.Equal(t, bolttest.TeamID, report.TeamID)
+		assert.Equal(t, bolttest.UserID, report.UserID)
+		assert.NotEmpty(t, report.CorrelationID)
+		assert.Equal(t, map[string]string{
+			"api_app_id": "A0000TEST",
+			"command":    "/deploy",
+			"channel_id": bolttest.ChannelID,
+		}, report.Payload)
+	})
+
+	t.Run("should summarize interactions", func(t *testing.T) {
+		reporter := &recordingErrorReporter{}
+		h := bolttest.New(t, bolt.AppOptions{ErrorReporter: reporter})
+		h.App.Action(bolt.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			return errors.New("approval failed")
+		})
+
+		h.Send(bolttest.BlockAction("approve", "yes"))
+
+		reports := reporter.Reports()
+		require.Len(t, reports, 1)
+		assert.Equal(t, "action", reports[0].Type)
+		assert.Equal(t, "approve", reports[0].Payload["action_id"])
+		assert.Equal(t, "bolttest_block", reports[0].Payload["block_id"])
+		assert.Equal(t, "block_actions", reports[0].Payload["type"])
+	})
+
+	t.Run("should not report errors handled by a custom error handler", func(t *testing.T) {
+		reporter := &recordingErrorReporter{}
+		h := bolttest.New(t, bolt.AppOptions{ErrorReporter: reporter})
+		h.App.ExtendedError(func(ctx context.Context, err error, logger bolt.Logger, body interface{}, context *bolt.Context, event bolt.ReceiverEvent) error {
+			return nil
+		})
+		h.App.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error {
+			return errors.New("deploy failed")
+		})
+
+		h.Send(bolttest.Command("/deploy", ""))
+		assert.Empty(t, reporter.Reports())
+	})
+
+	t.Run("should send reports to Sentry and flush them when the app stops", func(t *testing.T) {
+		dsn, requests := fakeSentry(t)
+		reporter, err := errorreport.NewSentryReporter(errorreport.SentryOptions{
+			DSN:         dsn,
+			Environment: "test",
+			Tags:        map[string]string{"service": "bolt"},
+		})
+		require.NoError(t, err)
+
+		h := bolttest.New(t, bolt.AppOptions{ErrorReporter: reporter})
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			return fmt.Errorf("posting reply: %w", errors.New("channel_not_found"))
+		})
+		h.Send(bolttest.AppMention("hello"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, h.App.Stop(ctx))
+
+		var request sentryRequest
+		select {
+		case request = <-requests:
+		default:
+			t.Fatal("no event was sent to Sentry")
+		}
+		assert.Equal(t, "/api/42/envelope/", request.Path)
+		assert.Contains(t, request.Auth, "sentry_key=publickey")
+		require.NotNil(t, request.Event)
+
+		event := request.Event
+		assert.Len(t, event["event_id"], 32)
+		assert.Equal(t, "error", event["level"])
+		assert.Equal(t, "test", event["environment"])
+		assert.Equal(t, "event(type=app_mention)", event["transaction"])
+		assert.Equal(t, map[string]any{"id": bolttest.UserID}, event["user"])
+
+		tags := event["tags"].(map[string]any)
+		assert.Equal(t, "bolt", tags["service"])
+		assert.Equal(t, "app_mention", tags["event_type"])
+		assert.Equal(t, bolttest.TeamID, tags["team_id"])
+		assert.Equal(t, string(bolterrors.MultipleListenerErrorCode), tags["error_code"])
+
+		values := event["exception"].(map[string]any)["values"].([]any)
+		require.NotEmpty(t, values)
+		innermost := values[0].(map[string]any)
+		assert.Equal(t, "errors.errorString", innermost["type"])
+		assert.Equal(t, "channel_not_found", innermost["value"])
+		outermost := values[len(values)-1].(map[string]any)
+		assert.Equal(t, "errors.MultipleListenerError", outermost["type"])
+
+		payload := event["extra"].(map[string]any)["payload"].(map[string]any)
+		assert.Equal(t, "app_mention", payload["event_type"])
+		assert.NotContains(t, payload, "text")
+	})
+
+	t.Run("should reject invalid DSNs", func(t *testing.T) {
+		for _, dsn := range []string{"", "not a url", "https://o0.ingest.sentry.io/42", "https://key@o0.ingest.sentry.io/"} {
+			_, err := errorreport.NewSentryReporter(errorreport.SentryOptions{DSN: dsn})
+			assert.Error(t, err, dsn)
+		}
+	})
+}
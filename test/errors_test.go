@@ -2,6 +2,7 @@ package test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
@@ -43,4 +44,76 @@ func TestErrors(t *testing.T) {
 		// Should be the same instance
 		assert.Equal(t, originalError, passedError, "Coded errors should pass through unchanged")
 	})
+
+	t.Run("matches error codes with errors.Is", func(t *testing.T) {
+		err := fmt.Errorf("processing failed: %w", bolterrors.NewAuthorizationError("auth failed", errors.New("no installation")))
+
+		assert.True(t, errors.Is(err, bolterrors.AuthorizationErrorCode))
+		assert.False(t, errors.Is(err, bolterrors.ReceiverAuthenticityErrorCode))
+		assert.True(t, errors.Is(bolterrors.NewReceiverAuthenticityError("bad signature"), bolterrors.ReceiverAuthenticityErrorCode))
+		assert.True(t, errors.Is(bolterrors.NewMultipleListenerError(nil), bolterrors.MultipleListenerErrorCode))
+	})
+
+	t.Run("unwraps to the original error", func(t *testing.T) {
+		original := errors.New("no installation")
+		err := fmt.Errorf("processing failed: %w", bolterrors.NewAuthorizationError("auth failed", original))
+
+		assert.True(t, errors.Is(err, original))
+		assert.Equal(t, original, errors.Unwrap(bolterrors.NewUnknownError(original)))
+
+		var authErr *bolterrors.AuthorizationError
+		assert.True(t, errors.As(err, &authErr))
+		assert.Equal(t, original, authErr.Original())
+
+		var missingErr *bolterrors.ContextMissingPropertyError
+		assert.True(t, errors.As(fmt.Errorf("wrapped: %w", bolterrors.NewContextMissingPropertyError("foo", "can't find foo")), &missingErr))
+		assert.Equal(t, "foo", missingErr.MissingProperty)
+	})
+
+	t.Run("classifies retryable and non-retryable errors", func(t *testing.T) {
+		original := errors.New("database unavailable")
+		retryable := bolterrors.NewRetryableError(original)
+		nonRetryable := bolterrors.NewNonRetryableError(errors.New("bad input"))
+
+		assert.True(t, bolterrors.IsRetryable(retryable))
+		assert.False(t, bolterrors.IsNonRetryable(retryable))
+		assert.True(t, errors.Is(retryable, original))
+
+		assert.False(t, bolterrors.IsRetryable(nonRetryable))
+		assert.True(t, bolterrors.IsNonRetryable(nonRetryable))
+
+		assert.False(t, bolterrors.IsRetryable(original))
+		assert.False(t, bolterrors.IsNonRetryable(original))
+		assert.False(t, bolterrors.IsRetryable(nil))
+
+		// A single retryable listener failure makes the whole event retryable
+		multiple := bolterrors.NewMultipleListenerError([]error{
+			bolterrors.NewListenerError("event(type=message)", 0, "", nonRetryable),
+			bolterrors.NewListenerError("event(type=message)", 1, "", fmt.Errorf("wrapped: %w", retryable)),
+		})
+		assert.True(t, bolterrors.IsRetryable(multiple))
+		assert.False(t, bolterrors.IsNonRetryable(multiple))
+
+		assert.True(t, bolterrors.IsNonRetryable(bolterrors.NewMultipleListenerError([]error{nonRetryable})))
+	})
+
+	t.Run("returns structured log attributes", func(t *testing.T) {
+		plain := errors.New("boom")
+		assert.Equal(t, []any{
+			bolterrors.LogKeyError, plain,
+			bolterrors.LogKeyErrorCode, string(bolterrors.UnknownErrorCode),
+		}, bolterrors.LogAttrs(plain))
+
+		authErr := bolterrors.NewAuthorizationError("Failed to authorize", plain)
+		assert.Equal(t, string(bolterrors.AuthorizationErrorCode), bolterrors.LogAttrs(authErr)[3])
+
+		multiple := bolterrors.NewMultipleListenerError([]error{
+			bolterrors.NewListenerError("event(type=message)", 0, "", plain),
+			bolterrors.NewListenerError("action(action_id=approve)", 1, "", plain),
+		})
+		attrs := bolterrors.LogAttrs(multiple)
+		assert.Equal(t, string(bolterrors.MultipleListenerErrorCode), attrs[3])
+		assert.Equal(t, bolterrors.LogKeyListener, attrs[4])
+		assert.Equal(t, "event(type=message),action(action_id=approve)", attrs[5])
+	})
 }
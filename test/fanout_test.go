@@ -0,0 +1,195 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/fanout"
+	fanoutkafka "github.com/Asafrose/bolt-go/pkg/fanout/kafka"
+	fanoutnats "github.com/Asafrose/bolt-go/pkg/fanout/nats"
+	fanoutsqs "github.com/Asafrose/bolt-go/pkg/fanout/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// channelFanOutSink hands published messages to a channel
+type channelFanOutSink chan bolt.FanOutMessage
+
+func (s channelFanOutSink) Publish(ctx context.Context, message bolt.FanOutMessage) error {
+	s <- message
+	return nil
+}
+
+// nextFanOutMessage waits for the next message published to sink
+func nextFanOutMessage(t *testing.T, sink channelFanOutSink) bolt.FanOutMessage {
+	t.Helper()
+	select {
+	case message := <-sink:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message was published")
+		return bolt.FanOutMessage{}
+	}
+}
+
+type fakeKafkaWriter struct{ messages []kafka.Message }
+
+func (w *fakeKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+type fakeNATSConn struct{ messages []*nats.Msg }
+
+func (c *fakeNATSConn) PublishMsg(msg *nats.Msg) error {
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+type fakeSQSClient struct{ inputs []*sqs.SendMessageInput }
+
+func (c *fakeSQSClient) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	c.inputs = append(c.inputs, params)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+// fanOutMessage is a forwarded app_mention
+var fanOutMessage = bolt.FanOutMessage{
+	ID:          "req-1",
+	EventID:     "Ev0000TEST",
+	Type:        "event",
+	EventType:   "app_mention",
+	TeamID:      bolttest.TeamID,
+	ContentType: "application/json",
+	Body:        `{"type":"event_callback"}`,
+}
+
+func TestFanOut(t *testing.T) {
+	t.Parallel()
+	t.Run("should forward every request with its classification", func(t *testing.T) {
+		sink := make(channelFanOutSink, 10)
+		h := bolttest.New(t, bolt.AppOptions{FanOut: bolt.FanOutOptions{Sink: sink}})
+
+		// Requests are forwarded whether or not a listener handles them
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+		message := nextFanOutMessage(t, sink)
+		assert.NotEmpty(t, message.ID)
+		assert.Equal(t, "Ev0000TEST", message.EventID)
+		assert.Equal(t, "event", message.Type)
+		assert.Equal(t, "app_mention", message.EventType)
+		assert.Equal(t, bolttest.TeamID, message.TeamID)
+		assert.Equal(t, "application/json", message.ContentType)
+		assert.JSONEq(t, string(bolttest.AppMention("hello").Body), message.Body)
+
+		h.Send(bolttest.Command("/deploy", "now")).AssertNoError(t)
+		message = nextFanOutMessage(t, sink)
+		assert.Equal(t, "command", message.Type)
+		assert.Equal(t, "application/x-www-form-urlencoded", message.ContentType)
+		assert.Contains(t, message.Body, "command=%2Fdeploy")
+	})
+
+	t.Run("should forward requests once acknowledged without waiting for listeners", func(t *testing.T) {
+		sink := make(channelFanOutSink, 10)
+		h := bolttest.New(t, bolt.AppOptions{FanOut: bolt.FanOutOptions{Sink: sink}})
+		release := make(chan struct{})
+		h.App.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			<-release
+			return nil
+		})
+
+		done := make(chan *bolttest.Result, 1)
+		go func() { done <- h.Send(bolttest.Command("/deploy", "")) }()
+
+		message := nextFanOutMessage(t, sink)
+		assert.Equal(t, "command", message.Type)
+		close(release)
+		(<-done).AssertNoError(t).AssertAcked(t)
+
+		select {
+		case message := <-sink:
+			t.Fatalf("request was forwarded twice: %+v", message)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("should publish queued messages when the app stops", func(t *testing.T) {
+		sink := make(channelFanOutSink, 10)
+		h := bolttest.New(t, bolt.AppOptions{FanOut: bolt.FanOutOptions{Sink: sink}})
+		h.Send(bolttest.AppMention("hello"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, h.App.Stop(ctx))
+		assert.Len(t, sink, 1)
+
+		// Requests received after the app stopped are not forwarded
+		h.Send(bolttest.AppMention("late"))
+		assert.Len(t, sink, 1)
+	})
+
+	t.Run("should publish to Kafka keyed by team", func(t *testing.T) {
+		writer := &fakeKafkaWriter{}
+		sink, err := fanoutkafka.NewSink(fanoutkafka.SinkOptions{Writer: writer, Topic: "slack-events"})
+		require.NoError(t, err)
+		require.NoError(t, sink.Publish(context.Background(), fanOutMessage))
+
+		require.Len(t, writer.messages, 1)
+		msg := writer.messages[0]
+		assert.Equal(t, "slack-events", msg.Topic)
+		assert.Equal(t, bolttest.TeamID, string(msg.Key))
+		assert.Contains(t, msg.Headers, kafka.Header{Key: fanout.HeaderEventType, Value: []byte("app_mention")})
+
+		var decoded bolt.FanOutMessage
+		require.NoError(t, json.Unmarshal(msg.Value, &decoded))
+		assert.Equal(t, fanOutMessage, decoded)
+	})
+
+	t.Run("should publish to NATS on a subject per request type", func(t *testing.T) {
+		conn := &fakeNATSConn{}
+		sink, err := fanoutnats.NewSink(fanoutnats.SinkOptions{Conn: conn})
+		require.NoError(t, err)
+		require.NoError(t, sink.Publish(context.Background(), fanOutMessage))
+
+		require.Len(t, conn.messages, 1)
+		assert.Equal(t, "slack.event.app_mention", conn.messages[0].Subject)
+		assert.Equal(t, "req-1", conn.messages[0].Header.Get(fanout.HeaderID))
+		assert.Equal(t, "slack.command", sink.Subject(bolt.FanOutMessage{Type: "command"}))
+		assert.Equal(t, "slack.unknown", sink.Subject(bolt.FanOutMessage{}))
+	})
+
+	t.Run("should send to SQS, deduplicating events on FIFO queues", func(t *testing.T) {
+		client := &fakeSQSClient{}
+		standard, err := fanoutsqs.NewSink(fanoutsqs.SinkOptions{Client: client, QueueURL: "https://sqs.us-east-1.amazonaws.com/123/slack"})
+		require.NoError(t, err)
+		fifo, err := fanoutsqs.NewSink(fanoutsqs.SinkOptions{Client: client, QueueURL: "https://sqs.us-east-1.amazonaws.com/123/slack.fifo"})
+		require.NoError(t, err)
+
+		require.NoError(t, standard.Publish(context.Background(), fanOutMessage))
+		require.NoError(t, fifo.Publish(context.Background(), fanOutMessage))
+
+		require.Len(t, client.inputs, 2)
+		assert.Nil(t, client.inputs[0].MessageGroupId)
+		assert.Equal(t, "app_mention", *client.inputs[0].MessageAttributes[fanout.HeaderEventType].StringValue)
+		assert.Equal(t, bolttest.TeamID, *client.inputs[1].MessageGroupId)
+		assert.Equal(t, "Ev0000TEST", *client.inputs[1].MessageDeduplicationId)
+	})
+
+	t.Run("should require the client of each sink", func(t *testing.T) {
+		_, err := fanoutkafka.NewSink(fanoutkafka.SinkOptions{})
+		assert.Error(t, err)
+		_, err = fanoutnats.NewSink(fanoutnats.SinkOptions{})
+		assert.Error(t, err)
+		_, err = fanoutsqs.NewSink(fanoutsqs.SinkOptions{Client: &fakeSQSClient{}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Asafrose/bolt-go/pkg/installstore"
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileInstallationStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	workspaceInstall := func(userID, botToken string) *oauth.Installation {
+		return &oauth.Installation{
+			Team:       &oauth.Team{ID: "T123", Name: "Acme"},
+			AuthedUser: &oauth.AuthedUser{ID: userID, AccessToken: "xoxp-" + userID},
+			BotToken:   botToken,
+		}
+	}
+
+	t.Run("should write the latest workspace and user installations", func(t *testing.T) {
+		baseDir := filepath.Join(t.TempDir(), "installations")
+		store := installstore.NewFileInstallationStore(baseDir)
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U1", "xoxb-first")))
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U2", "xoxb-second")))
+
+		assert.FileExists(t, filepath.Join(baseDir, "none-T123", "app-latest"))
+		assert.FileExists(t, filepath.Join(baseDir, "none-T123", "user-U1-latest"))
+
+		latest, err := store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123"})
+		require.NoError(t, err)
+		assert.Equal(t, workspaceInstall("U2", "xoxb-second"), latest)
+
+		user, err := installstore.NewFileInstallationStore(baseDir).FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123", UserID: "U1"})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-first", user.BotToken)
+	})
+
+	t.Run("should keep installations private to the owner", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("file modes are not enforced on Windows")
+		}
+		baseDir := filepath.Join(t.TempDir(), "installations")
+		store := installstore.NewFileInstallationStore(baseDir)
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U1", "xoxb-first")))
+
+		for path, mode := range map[string]os.FileMode{
+			baseDir:                             0o700,
+			filepath.Join(baseDir, "none-T123"): 0o700,
+			filepath.Join(baseDir, "none-T123", "app-latest"): 0o600,
+		} {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, mode, info.Mode().Perm(), path)
+		}
+	})
+
+	t.Run("should key org-wide installs by enterprise", func(t *testing.T) {
+		store := installstore.NewFileInstallationStore(t.TempDir())
+		require.NoError(t, store.StoreInstallation(ctx, &oauth.Installation{
+			Enterprise:          &oauth.Enterprise{ID: "E123"},
+			IsEnterpriseInstall: true,
+			BotToken:            "xoxb-org",
+		}))
+
+		installation, err := store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E123", TeamID: "T999", IsEnterpriseInstall: true})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-org", installation.BotToken)
+
+		_, err = store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E123", TeamID: "T999"})
+		assert.ErrorIs(t, err, oauth.ErrInstallationNotFound)
+	})
+
+	t.Run("should delete users or whole workspaces", func(t *testing.T) {
+		baseDir := t.TempDir()
+		store := installstore.NewFileInstallationStore(baseDir)
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U1", "xoxb-first")))
+
+		require.NoError(t, store.DeleteInstallation(ctx, oauth.InstallationQuery{TeamID: "T123", UserID: "U1"}))
+		_, err := store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123", UserID: "U1"})
+		assert.ErrorIs(t, err, oauth.ErrInstallationNotFound)
+		_, err = store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123"})
+		assert.NoError(t, err)
+
+		require.NoError(t, store.DeleteInstallation(ctx, oauth.InstallationQuery{TeamID: "T123"}))
+		assert.NoDirExists(t, filepath.Join(baseDir, "none-T123"))
+	})
+
+	t.Run("should reject IDs naming files outside of the store", func(t *testing.T) {
+		store := installstore.NewFileInstallationStore(t.TempDir())
+
+		_, err := store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "../../etc"})
+		assert.ErrorContains(t, err, "invalid ID")
+		err = store.StoreInstallation(ctx, workspaceInstall("../U1", "xoxb-first"))
+		assert.ErrorContains(t, err, "invalid ID")
+	})
+}
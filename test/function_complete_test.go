@@ -0,0 +1,81 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// functionExecuted builds a function_executed event of callbackID run with the bot token xwfp-token
+func functionExecuted(callbackID string) bolttest.Payload {
+	return bolttest.Event("function_executed", map[string]any{
+		"function":              map[string]any{"callback_id": callbackID},
+		"inputs":                map[string]any{"user_id": bolttest.UserID},
+		"function_execution_id": "Fx0000TEST",
+		"bot_access_token":      "xwfp-token",
+	})
+}
+
+func TestFunctionCompleteAndFail(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should complete the execution with its outputs", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Function("greet", func(args bolt.SlackCustomFunctionMiddlewareArgs) error {
+			return args.Complete(map[string]interface{}{"greeting": "hello", "count": 2})
+		})
+
+		h.Send(functionExecuted("greet")).AssertNoError(t)
+
+		calls := h.Slack.Calls("functions.completeSuccess")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "Fx0000TEST", calls[0].Params["function_execution_id"])
+		assert.Equal(t, map[string]any{"greeting": "hello", "count": "2"}, calls[0].Params["outputs"])
+		assert.Equal(t, bolttest.BotToken, calls[0].Token)
+	})
+
+	t.Run("should fail the execution with its error", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Function("greet", func(args bolt.SlackCustomFunctionMiddlewareArgs) error {
+			return args.Fail("no greeting today")
+		})
+
+		h.Send(functionExecuted("greet")).AssertNoError(t)
+
+		calls := h.Slack.Calls("functions.completeError")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "Fx0000TEST", calls[0].Params["function_execution_id"])
+		assert.Equal(t, "no greeting today", calls[0].Params["error"])
+	})
+
+	t.Run("should use the function bot token when AttachFunctionToken is set", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{AttachFunctionToken: true})
+		h.App.Function("greet", func(args bolt.SlackCustomFunctionMiddlewareArgs) error {
+			assert.Equal(t, "xwfp-token", args.Context.FunctionBotAccessToken)
+			return args.Complete(nil)
+		})
+
+		h.Send(functionExecuted("greet")).AssertNoError(t)
+
+		calls := h.Slack.Calls("functions.completeSuccess")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "xwfp-token", calls[0].Token)
+	})
+
+	t.Run("should return Slack API errors", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.Slack.Handle("functions.completeSuccess", map[string]any{"ok": false, "error": "function_execution_not_found"})
+		var completeErr error
+		h.App.Function("greet", func(args bolt.SlackCustomFunctionMiddlewareArgs) error {
+			completeErr = args.Complete(nil)
+			return nil
+		})
+
+		h.Send(functionExecuted("greet")).AssertNoError(t)
+
+		assert.ErrorContains(t, completeErr, "function_execution_not_found")
+	})
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/functions"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewSchema declares a custom function asking a user to review a message
+var reviewSchema = bolt.FunctionSchema{
+	Title:       "Request review",
+	Description: "Asks a reviewer to look at a message",
+	Inputs: []bolt.FunctionParameter{
+		{Name: "reviewer", Type: functions.TypeUserID, Title: "Reviewer", Required: true},
+		{Name: "priority", Type: functions.TypeInteger, Title: "Priority"},
+		{Name: "labels", Type: functions.TypeArray, Items: &bolt.FunctionParameter{Type: functions.TypeString}},
+	},
+	Outputs: []bolt.FunctionParameter{
+		{Name: "approved", Type: functions.TypeBoolean, Title: "Approved", Required: true},
+	},
+}
+
+type reviewInputs struct {
+	Reviewer string   `json:"reviewer"`
+	Priority int      `json:"priority"`
+	Labels   []string `json:"labels"`
+}
+
+type reviewOutputs struct {
+	Approved bool `json:"approved"`
+}
+
+func TestFunctionSchema(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should generate the manifest declaration", func(t *testing.T) {
+		data, err := json.Marshal(reviewSchema.Manifest())
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{
+			"title": "Request review",
+			"description": "Asks a reviewer to look at a message",
+			"input_parameters": {
+				"properties": {
+					"reviewer": {"type": "slack#/types/user_id", "title": "Reviewer"},
+					"priority": {"type": "integer", "title": "Priority"},
+					"labels": {"type": "array", "items": {"type": "string"}}
+				},
+				"required": ["reviewer"]
+			},
+			"output_parameters": {
+				"properties": {"approved": {"type": "boolean", "title": "Approved"}},
+				"required": ["approved"]
+			}
+		}`, string(data))
+	})
+
+	t.Run("should decode inputs into a struct", func(t *testing.T) {
+		inputs, err := functions.DecodeInputs[reviewInputs](reviewSchema, map[string]interface{}{
+			"reviewer": "U123",
+			"priority": float64(2),
+			"labels":   []interface{}{"copy", "legal"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, reviewInputs{Reviewer: "U123", Priority: 2, Labels: []string{"copy", "legal"}}, inputs)
+	})
+
+	t.Run("should report every invalid input", func(t *testing.T) {
+		_, err := functions.DecodeInputs[reviewInputs](reviewSchema, map[string]interface{}{
+			"priority": 1.5,
+			"labels":   []interface{}{"copy", true},
+		})
+
+		var paramErr *bolt.FunctionParameterError
+		require.ErrorAs(t, err, &paramErr)
+		assert.ErrorIs(t, err, bolt.FunctionParameterErrorCode)
+		assert.Equal(t, []string{
+			"reviewer is required",
+			"priority must be an integer",
+			"labels item 1 must be a string",
+		}, paramErr.Problems)
+	})
+
+	t.Run("should encode and validate outputs", func(t *testing.T) {
+		outputs, err := functions.EncodeOutputs(reviewSchema, reviewOutputs{Approved: true})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"approved": true}, outputs)
+
+		_, err = functions.EncodeOutputs(reviewSchema, map[string]string{"approved": "yes"})
+		assert.ErrorIs(t, err, bolt.FunctionParameterErrorCode)
+	})
+
+	t.Run("should fail the function execution when inputs are invalid", func(t *testing.T) {
+		var failed string
+		args := types.SlackCustomFunctionMiddlewareArgs{
+			AllMiddlewareArgs: types.AllMiddlewareArgs{Context: &types.Context{
+				FunctionInputs: types.FunctionInputs{"priority": "high"},
+			}},
+			Fail: func(message string) error {
+				failed = message
+				return nil
+			},
+		}
+
+		_, err := functions.BindInputs[reviewInputs](args, reviewSchema)
+		require.Error(t, err)
+		assert.Equal(t, "invalid function inputs: reviewer is required; priority must be an integer", failed)
+	})
+
+	t.Run("should bind the inputs of function_executed events", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+
+		var inputs reviewInputs
+		app.Function("request_review", func(args bolt.SlackCustomFunctionMiddlewareArgs) error {
+			inputs, err = functions.BindInputs[reviewInputs](args, reviewSchema)
+			return err
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    createFunctionExecutedEventBody("request_review", map[string]interface{}{"reviewer": "U123", "priority": 3}),
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		})
+		require.NoError(t, err)
+		assert.Equal(t, reviewInputs{Reviewer: "U123", Priority: 3}, inputs)
+	})
+}
@@ -1,10 +1,18 @@
 package test
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -150,6 +158,28 @@ func TestGetTypeAndConversation(t *testing.T) {
 		assert.Nil(t, result.Type, "Malformed JSON should return nil type")
 		assert.Nil(t, result.ConversationID, "Malformed JSON should not have conversation ID")
 	})
+
+	t.Run("should match results computed from an already parsed body", func(t *testing.T) {
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type":          "event_callback",
+			"team_id":       "T123456",
+			"enterprise_id": "E123456",
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"channel": "C123456",
+			},
+		})
+		parsed := helpers.ParseRequestBody(bodyBytes)
+
+		assert.Equal(t, helpers.GetTypeAndConversation(bodyBytes), helpers.GetTypeAndConversationFromParsed(parsed))
+		assert.Equal(t, "app_mention", helpers.ExtractEventTypeFromParsed(parsed))
+		assert.Equal(t, "T123456", *helpers.ExtractTeamIDFromParsed(parsed))
+		assert.Equal(t, "E123456", *helpers.ExtractEnterpriseIDFromParsed(parsed))
+		assert.Equal(t, "U123456", *helpers.ExtractUserIDFromParsed(parsed))
+		assert.False(t, helpers.IsParsedBodyWithTypeEnterpriseInstall(parsed))
+		assert.Nil(t, helpers.ExtractTeamIDFromParsed(nil))
+	})
 }
 
 func TestExtractTeamID(t *testing.T) {
@@ -461,3 +491,179 @@ func TestConversationIDExtraction(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestParseMessageMetadataEvent(t *testing.T) {
+	t.Parallel()
+
+	type taskPayload struct {
+		TaskID string `json:"task_id"`
+	}
+
+	t.Run("should decode a message_metadata_posted event", func(t *testing.T) {
+		event, err := helpers.ParseSlackEvent(map[string]interface{}{
+			"type":       "message_metadata_posted",
+			"app_id":     "A123",
+			"channel_id": "C123",
+			"message_ts": "1234567890.123456",
+			"event_ts":   "1234567890.123457",
+			"metadata": map[string]interface{}{
+				"event_type":    "task_created",
+				"event_payload": map[string]interface{}{"task_id": "T-1"},
+			},
+		})
+		assert.NoError(t, err)
+
+		metadataEvent, err := helpers.ParseMessageMetadataEvent[taskPayload](event)
+		assert.NoError(t, err)
+		assert.Equal(t, "message_metadata_posted", metadataEvent.GetType())
+		assert.Equal(t, "C123", metadataEvent.ChannelID)
+		assert.Equal(t, "1234567890.123456", metadataEvent.MessageTS)
+		if assert.NotNil(t, metadataEvent.Metadata) {
+			assert.Equal(t, "task_created", metadataEvent.Metadata.EventType)
+			assert.Equal(t, "T-1", metadataEvent.Metadata.EventPayload.TaskID)
+		}
+		assert.Nil(t, metadataEvent.PreviousMetadata)
+	})
+
+	t.Run("should decode previous metadata on a message_metadata_deleted event", func(t *testing.T) {
+		event, err := helpers.ParseSlackEvent(map[string]interface{}{
+			"type":       "message_metadata_deleted",
+			"channel_id": "C123",
+			"message_ts": "1234567890.123456",
+			"deleted_ts": "1234567899.000000",
+			"previous_metadata": map[string]interface{}{
+				"event_type":    "task_created",
+				"event_payload": map[string]interface{}{"task_id": "T-1"},
+			},
+		})
+		assert.NoError(t, err)
+
+		metadataEvent, err := helpers.ParseMessageMetadataEvent[taskPayload](event)
+		assert.NoError(t, err)
+		assert.Equal(t, "1234567899.000000", metadataEvent.DeletedTS)
+		assert.Nil(t, metadataEvent.Metadata)
+		if assert.NotNil(t, metadataEvent.PreviousMetadata) {
+			assert.Equal(t, "T-1", metadataEvent.PreviousMetadata.EventPayload.TaskID)
+		}
+	})
+
+	t.Run("should return an error for a nil event", func(t *testing.T) {
+		_, err := helpers.ParseMessageMetadataEvent[taskPayload](nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestFileHelpers(t *testing.T) {
+	t.Parallel()
+	newFileServer := func(t *testing.T, content string) (*httptest.Server, *int32) {
+		var lookups int32
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/files.info":
+				atomic.AddInt32(&lookups, 1)
+				_ = r.ParseForm()
+				fileID := r.Form.Get("file")
+				if fileID == "F_MISSING" {
+					_, _ = w.Write([]byte(`{"ok":false,"error":"file_not_found"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"ok":true,"file":{"id":"` + fileID + `","name":"` + fileID + `.txt","url_private_download":"` + server.URL + `/download/` + fileID + `"}}`))
+			case "/download/F1":
+				assert.Equal(t, "Bearer xoxb-test", r.Header.Get("Authorization"))
+				_, _ = w.Write([]byte(content))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server, &lookups
+	}
+
+	t.Run("should extract file IDs from file events", func(t *testing.T) {
+		event, err := helpers.ParseSlackEvent(map[string]interface{}{
+			"type":    "file_shared",
+			"file_id": "F1",
+			"file":    map[string]interface{}{"id": "F1"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"F1"}, helpers.FileIDsFromEvent(event))
+
+		event, err = helpers.ParseSlackEvent(map[string]interface{}{"type": "app_mention"})
+		assert.NoError(t, err)
+		assert.Empty(t, helpers.FileIDsFromEvent(event))
+	})
+
+	t.Run("should look up each file once and report failed lookups", func(t *testing.T) {
+		server, lookups := newFileServer(t, "")
+		client := slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"))
+
+		files, err := helpers.FetchFileInfos(context.Background(), client, []string{"F1", "F2", "F1", "F_MISSING"}, 2)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "F_MISSING")
+		assert.Len(t, files, 2)
+		assert.Equal(t, "F2.txt", files["F2"].Name)
+		assert.Equal(t, int32(3), atomic.LoadInt32(lookups))
+	})
+
+	t.Run("should stream file downloads", func(t *testing.T) {
+		content := strings.Repeat("file contents ", 10000)
+		server, _ := newFileServer(t, content)
+		client := slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/"))
+
+		files, err := helpers.FetchFileInfos(context.Background(), client, []string{"F1"}, 0)
+		assert.NoError(t, err)
+
+		reader, err := helpers.OpenFile(context.Background(), client, files["F1"])
+		assert.NoError(t, err)
+		defer reader.Close()
+
+		downloaded, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(downloaded))
+	})
+
+	t.Run("should reject files without a download URL", func(t *testing.T) {
+		_, err := helpers.OpenFile(context.Background(), slack.New("xoxb-test"), &slack.File{ID: "F1"})
+		assert.Error(t, err)
+	})
+}
+
+func TestCreateRespondFunction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should post the message to the response URL", func(t *testing.T) {
+		var posted map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		respond := helpers.CreateRespondFunction(server.URL + "/commands/T123/456")
+		response, err := respond(context.Background(), types.RespondString("Deployed"))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "ok", string(response.Body))
+		assert.Equal(t, "Deployed", posted["text"])
+	})
+
+	t.Run("should fail with the status of a rejected post", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("expired_url"))
+		}))
+		defer server.Close()
+
+		response, err := helpers.CreateRespondFunction(server.URL)(context.Background(), types.RespondString("Deployed"))
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+		assert.Equal(t, "expired_url", string(response.Body))
+	})
+
+	t.Run("should refuse response URLs outside the Slack hosts", func(t *testing.T) {
+		_, err := helpers.CreateRespondFunction("https://attacker.example.com/hook")(context.Background(), types.RespondString("Deployed"))
+		assert.ErrorContains(t, err, "is not allowed")
+	})
+}
@@ -165,7 +165,7 @@ func TestHTTPModuleFunctions(t *testing.T) {
 				currentTime := time.Now().Unix()
 
 				// Check if timestamp is too old (more than 5 minutes = 300 seconds)
-				assert.Greater(t, currentTime-oldTimestamp, 300)
+				assert.Greater(t, currentTime-oldTimestamp, int64(300))
 			})
 
 			t.Run("should detect an invalid signature", func(t *testing.T) {
@@ -1,11 +1,18 @@
 package test
 
 import (
+	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
 	"github.com/Asafrose/bolt-go/pkg/errors"
 	"github.com/Asafrose/bolt-go/pkg/receivers"
 	"github.com/Asafrose/bolt-go/pkg/types"
@@ -340,3 +347,62 @@ func TestHTTPResponseIntegration(t *testing.T) {
 		assert.NotNil(t, receiver, "Receiver should handle errors gracefully")
 	})
 }
+
+func TestHTTPConcurrentListenerAcks(t *testing.T) {
+	t.Parallel()
+	_, port, err := net.SplitHostPort(freeAddr(t))
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	receiver := receivers.NewHTTPReceiver(types.HTTPReceiverOptions{SigningSecret: fakeSigningSecret, Port: portNum})
+	app, err := bolt.New(bolt.AppOptions{
+		Token:               fakeToken,
+		Receiver:            receiver,
+		ListenerConcurrency: 2,
+	})
+	require.NoError(t, err)
+
+	// Both listeners ack once the other is running, so their acks race
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	ackErrs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		app.Action(bolt.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			barrier.Done()
+			barrier.Wait()
+			ackErrs <- args.Ack(nil)
+			return nil
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", "127.0.0.1:"+port)
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	body := string(bolttest.BlockAction("approve", "yes").Body)
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:"+port+"/slack/events", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Slack-Signature", createValidSignature(body, timestamp, fakeSigningSecret))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	first, second := <-ackErrs, <-ackErrs
+	assert.True(t, (first == nil) != (second == nil), "exactly one ack should succeed, got %v and %v", first, second)
+}
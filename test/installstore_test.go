@@ -0,0 +1,441 @@
+package test
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go/pkg/installstore"
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInstallationRedis implements the string and set commands of the installation and state stores
+// over maps
+type fakeInstallationRedis struct {
+	mu      sync.Mutex
+	strings map[string]string
+	sets    map[string]map[string]bool
+	ttls    map[string]time.Duration
+}
+
+func newFakeInstallationRedis() *fakeInstallationRedis {
+	return &fakeInstallationRedis{
+		strings: map[string]string{},
+		sets:    map[string]map[string]bool{},
+		ttls:    map[string]time.Duration{},
+	}
+}
+
+func (c *fakeInstallationRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.strings[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(value, nil)
+}
+
+func (c *fakeInstallationRedis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.strings[key] = string(value.([]byte))
+	c.ttls[key] = expiration
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (c *fakeInstallationRedis) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.strings, key)
+		delete(c.sets, key)
+		delete(c.ttls, key)
+	}
+	return redis.NewIntResult(int64(len(keys)), nil)
+}
+
+func (c *fakeInstallationRedis) GetDel(ctx context.Context, key string) *redis.StringCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.strings[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	delete(c.strings, key)
+	delete(c.ttls, key)
+	return redis.NewStringResult(value, nil)
+}
+
+func (c *fakeInstallationRedis) SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sets[key] == nil {
+		c.sets[key] = map[string]bool{}
+	}
+	for _, member := range members {
+		c.sets[key][member.(string)] = true
+	}
+	return redis.NewIntResult(int64(len(members)), nil)
+}
+
+func (c *fakeInstallationRedis) SRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, member := range members {
+		delete(c.sets[key], member.(string))
+	}
+	return redis.NewIntResult(int64(len(members)), nil)
+}
+
+func (c *fakeInstallationRedis) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var members []string
+	for member := range c.sets[key] {
+		members = append(members, member)
+	}
+	return redis.NewStringSliceResult(members, nil)
+}
+
+func (c *fakeInstallationRedis) Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttls[key] = expiration
+	return redis.NewBoolResult(true, nil)
+}
+
+func TestRedisInstallationStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	newStore := func(t *testing.T, options installstore.RedisInstallationStoreOptions) (*installstore.RedisInstallationStore, *fakeInstallationRedis) {
+		client := newFakeInstallationRedis()
+		options.Client = client
+		store, err := installstore.NewRedisInstallationStore(options)
+		require.NoError(t, err)
+		return store, client
+	}
+
+	workspaceInstall := func(userID, botToken string) *oauth.Installation {
+		return &oauth.Installation{
+			Team:       &oauth.Team{ID: "T123"},
+			AuthedUser: &oauth.AuthedUser{ID: userID, AccessToken: "xoxp-" + userID},
+			BotToken:   botToken,
+		}
+	}
+
+	t.Run("should require a client", func(t *testing.T) {
+		_, err := installstore.NewRedisInstallationStore(installstore.RedisInstallationStoreOptions{})
+		assert.ErrorContains(t, err, "redis client is required")
+	})
+
+	t.Run("should fetch the latest workspace and user installations", func(t *testing.T) {
+		store, _ := newStore(t, installstore.RedisInstallationStoreOptions{})
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U1", "xoxb-first")))
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U2", "xoxb-second")))
+
+		latest, err := store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123"})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-second", latest.BotToken)
+
+		user, err := store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123", UserID: "U1"})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxp-U1", user.AuthedUser.AccessToken)
+		assert.Equal(t, "xoxb-first", user.BotToken)
+	})
+
+	t.Run("should report missing installations", func(t *testing.T) {
+		store, _ := newStore(t, installstore.RedisInstallationStoreOptions{})
+
+		_, err := store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T404"})
+
+		assert.ErrorIs(t, err, installstore.ErrInstallationNotFound)
+	})
+
+	t.Run("should key org-wide installs by enterprise", func(t *testing.T) {
+		store, _ := newStore(t, installstore.RedisInstallationStoreOptions{})
+		require.NoError(t, store.StoreInstallation(ctx, &oauth.Installation{
+			Enterprise:          &oauth.Enterprise{ID: "E123"},
+			IsEnterpriseInstall: true,
+			BotToken:            "xoxb-org",
+		}))
+
+		installation, err := store.FetchInstallation(ctx, oauth.InstallationQuery{
+			EnterpriseID:        "E123",
+			TeamID:              "T999",
+			IsEnterpriseInstall: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-org", installation.BotToken)
+
+		_, err = store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E123", TeamID: "T999"})
+		assert.ErrorIs(t, err, installstore.ErrInstallationNotFound)
+	})
+
+	t.Run("should delete only the user's installation for user queries", func(t *testing.T) {
+		store, _ := newStore(t, installstore.RedisInstallationStoreOptions{})
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U1", "xoxb-first")))
+
+		require.NoError(t, store.DeleteInstallation(ctx, oauth.InstallationQuery{TeamID: "T123", UserID: "U1"}))
+
+		_, err := store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123", UserID: "U1"})
+		assert.ErrorIs(t, err, installstore.ErrInstallationNotFound)
+		_, err = store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should delete every installation of the workspace", func(t *testing.T) {
+		store, client := newStore(t, installstore.RedisInstallationStoreOptions{})
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U1", "xoxb-first")))
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U2", "xoxb-second")))
+
+		require.NoError(t, store.DeleteInstallation(ctx, oauth.InstallationQuery{TeamID: "T123"}))
+
+		_, err := store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123", UserID: "U2"})
+		assert.ErrorIs(t, err, installstore.ErrInstallationNotFound)
+		assert.Empty(t, client.strings)
+		assert.Empty(t, client.sets)
+	})
+
+	t.Run("should apply the prefix and TTL", func(t *testing.T) {
+		store, client := newStore(t, installstore.RedisInstallationStoreOptions{Prefix: "myapp:", TTL: time.Hour})
+		require.NoError(t, store.StoreInstallation(ctx, workspaceInstall("U1", "xoxb-first")))
+
+		assert.Equal(t, time.Hour, client.ttls["myapp:none:T123"])
+		assert.Equal(t, time.Hour, client.ttls["myapp:none:T123:user:U1"])
+		assert.Equal(t, time.Hour, client.ttls["myapp:none:T123:users"])
+	})
+}
+
+func TestRedisStateStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should require a client", func(t *testing.T) {
+		_, err := installstore.NewRedisStateStore(installstore.RedisStateStoreOptions{})
+		assert.ErrorContains(t, err, "redis client is required")
+	})
+
+	t.Run("should verify each state once", func(t *testing.T) {
+		client := newFakeInstallationRedis()
+		store, err := installstore.NewRedisStateStore(installstore.RedisStateStoreOptions{Client: client})
+		require.NoError(t, err)
+		installOptions := &oauth.InstallURLOptions{Scopes: []string{"commands"}, RedirectURI: "https://example.com/oauth"}
+
+		state, err := store.GenerateStateParam(ctx, installOptions)
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Minute, client.ttls["bolt:oauth-state:"+state])
+
+		// Another instance of the app verifies the redirect
+		other, err := installstore.NewRedisStateStore(installstore.RedisStateStoreOptions{Client: client})
+		require.NoError(t, err)
+		verified, err := other.VerifyStateParam(ctx, state)
+		require.NoError(t, err)
+		assert.Equal(t, installOptions, verified)
+
+		_, err = other.VerifyStateParam(ctx, state)
+		assert.ErrorContains(t, err, "invalid or expired state")
+	})
+
+	t.Run("should apply the prefix and expiration", func(t *testing.T) {
+		client := newFakeInstallationRedis()
+		store, err := installstore.NewRedisStateStore(installstore.RedisStateStoreOptions{Client: client, Prefix: "myapp:state:", Expiration: time.Minute})
+		require.NoError(t, err)
+
+		state, err := store.GenerateStateParam(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, client.ttls["myapp:state:"+state])
+	})
+}
+
+// fakeDynamoDB implements the item operations of the installation store over a map keyed by the
+// "pk" and "sk" attributes, enforcing its installed_at condition and paging queries item by item
+type fakeDynamoDB struct {
+	mu    sync.Mutex
+	items map[[2]string]map[string]dynamodbtypes.AttributeValue
+}
+
+func newFakeDynamoDB() *fakeDynamoDB {
+	return &fakeDynamoDB{items: map[[2]string]map[string]dynamodbtypes.AttributeValue{}}
+}
+
+func fakeDynamoDBKey(item map[string]dynamodbtypes.AttributeValue) [2]string {
+	return [2]string{
+		item["pk"].(*dynamodbtypes.AttributeValueMemberS).Value,
+		item["sk"].(*dynamodbtypes.AttributeValueMemberS).Value,
+	}
+}
+
+func (c *fakeDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &dynamodb.GetItemOutput{Item: c.items[fakeDynamoDBKey(params.Key)]}, nil
+}
+
+func (c *fakeDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := fakeDynamoDBKey(params.Item)
+	if stored, ok := c.items[key]; ok {
+		storedAt, _ := strconv.ParseInt(stored["installed_at"].(*dynamodbtypes.AttributeValueMemberN).Value, 10, 64)
+		installedAt, _ := strconv.ParseInt(params.ExpressionAttributeValues[":installed_at"].(*dynamodbtypes.AttributeValueMemberN).Value, 10, 64)
+		if storedAt > installedAt {
+			return nil, &dynamodbtypes.ConditionalCheckFailedException{}
+		}
+	}
+	c.items[key] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *fakeDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, fakeDynamoDBKey(params.Key))
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func (c *fakeDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	partition := params.ExpressionAttributeValues[":pk"].(*dynamodbtypes.AttributeValueMemberS).Value
+	var sorts []string
+	for key := range c.items {
+		if key[0] == partition {
+			sorts = append(sorts, key[1])
+		}
+	}
+	slices.Sort(sorts)
+	if params.ExclusiveStartKey != nil {
+		start := fakeDynamoDBKey(params.ExclusiveStartKey)[1]
+		sorts = slices.DeleteFunc(sorts, func(sort string) bool { return sort <= start })
+	}
+	if len(sorts) == 0 {
+		return &dynamodb.QueryOutput{}, nil
+	}
+	item := map[string]dynamodbtypes.AttributeValue{
+		"pk": &dynamodbtypes.AttributeValueMemberS{Value: partition},
+		"sk": &dynamodbtypes.AttributeValueMemberS{Value: sorts[0]},
+	}
+	output := &dynamodb.QueryOutput{Items: []map[string]dynamodbtypes.AttributeValue{item}}
+	if len(sorts) > 1 {
+		output.LastEvaluatedKey = item
+	}
+	return output, nil
+}
+
+func TestDynamoDBInstallationStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	newStore := func(t *testing.T, options installstore.DynamoDBInstallationStoreOptions) (*installstore.DynamoDBInstallationStore, *fakeDynamoDB) {
+		client := newFakeDynamoDB()
+		options.Client = client
+		options.TableName = "slack-app"
+		store, err := installstore.NewDynamoDBInstallationStore(options)
+		require.NoError(t, err)
+		return store, client
+	}
+
+	gridInstall := func(userID, botToken string) *oauth.Installation {
+		return &oauth.Installation{
+			Enterprise: &oauth.Enterprise{ID: "E123"},
+			Team:       &oauth.Team{ID: "T123"},
+			AuthedUser: &oauth.AuthedUser{ID: userID, AccessToken: "xoxp-" + userID},
+			BotToken:   botToken,
+		}
+	}
+	gridQuery := oauth.InstallationQuery{EnterpriseID: "E123", TeamID: "T123"}
+
+	t.Run("should require a client and a table", func(t *testing.T) {
+		_, err := installstore.NewDynamoDBInstallationStore(installstore.DynamoDBInstallationStoreOptions{TableName: "slack-app"})
+		assert.ErrorContains(t, err, "dynamodb client is required")
+		_, err = installstore.NewDynamoDBInstallationStore(installstore.DynamoDBInstallationStoreOptions{Client: newFakeDynamoDB()})
+		assert.ErrorContains(t, err, "dynamodb table name is required")
+	})
+
+	t.Run("should store workspaces as partitions of the table", func(t *testing.T) {
+		store, client := newStore(t, installstore.DynamoDBInstallationStoreOptions{})
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("U1", "xoxb-first")))
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("U2", "xoxb-second")))
+
+		assert.Len(t, client.items, 3)
+		assert.Contains(t, client.items, [2]string{"INSTALLATION#E123#T123", "LATEST"})
+		assert.Contains(t, client.items, [2]string{"INSTALLATION#E123#T123", "USER#U1"})
+
+		latest, err := store.FetchInstallation(ctx, gridQuery)
+		require.NoError(t, err)
+		assert.Equal(t, gridInstall("U2", "xoxb-second"), latest)
+
+		query := gridQuery
+		query.UserID = "U1"
+		user, err := store.FetchInstallation(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-first", user.BotToken)
+
+		_, err = store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T123"})
+		assert.ErrorIs(t, err, installstore.ErrInstallationNotFound)
+	})
+
+	t.Run("should keep installations stored later", func(t *testing.T) {
+		store, client := newStore(t, installstore.DynamoDBInstallationStoreOptions{})
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("U1", "xoxb-old")))
+		latest := client.items[[2]string{"INSTALLATION#E123#T123", "LATEST"}]
+		latest["installed_at"] = &dynamodbtypes.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Add(time.Hour).UnixNano(), 10)}
+		latest["installation"] = &dynamodbtypes.AttributeValueMemberS{Value: `{"bot_token":"xoxb-newer"}`}
+
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("U1", "xoxb-stale")))
+
+		installation, err := store.FetchInstallation(ctx, gridQuery)
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-newer", installation.BotToken)
+	})
+
+	t.Run("should key org-wide installs by enterprise", func(t *testing.T) {
+		store, _ := newStore(t, installstore.DynamoDBInstallationStoreOptions{})
+		install := gridInstall("U1", "xoxb-org")
+		install.IsEnterpriseInstall = true
+		require.NoError(t, store.StoreInstallation(ctx, install))
+
+		installation, err := store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E123", TeamID: "T999", IsEnterpriseInstall: true})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-org", installation.BotToken)
+	})
+
+	t.Run("should delete users or every item of the workspace", func(t *testing.T) {
+		store, client := newStore(t, installstore.DynamoDBInstallationStoreOptions{})
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("U1", "xoxb-first")))
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("U2", "xoxb-second")))
+
+		query := gridQuery
+		query.UserID = "U1"
+		require.NoError(t, store.DeleteInstallation(ctx, query))
+		assert.Len(t, client.items, 2)
+
+		require.NoError(t, store.DeleteInstallation(ctx, gridQuery))
+		assert.Empty(t, client.items)
+	})
+
+	t.Run("should set and honor the TTL", func(t *testing.T) {
+		store, client := newStore(t, installstore.DynamoDBInstallationStoreOptions{TTL: time.Hour, TTLAttribute: "expires_at"})
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("U1", "xoxb-first")))
+
+		latest := client.items[[2]string{"INSTALLATION#E123#T123", "LATEST"}]
+		expiresAt, err := strconv.ParseInt(latest["expires_at"].(*dynamodbtypes.AttributeValueMemberN).Value, 10, 64)
+		require.NoError(t, err)
+		assert.InDelta(t, time.Now().Add(time.Hour).Unix(), expiresAt, 5)
+
+		// DynamoDB removes expired items eventually, so they must not be returned meanwhile
+		latest["expires_at"] = &dynamodbtypes.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)}
+		_, err = store.FetchInstallation(ctx, gridQuery)
+		assert.ErrorIs(t, err, installstore.ErrInstallationNotFound)
+	})
+}
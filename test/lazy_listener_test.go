@@ -0,0 +1,242 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLazyRunner records the lazy functions it is asked to start instead of running them
+type recordingLazyRunner struct {
+	mu       sync.Mutex
+	requests []bolt.LazyListenerRequest
+}
+
+func (r *recordingLazyRunner) Start(ctx context.Context, request bolt.LazyListenerRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, request)
+	return nil
+}
+
+func TestLazyListeners(t *testing.T) {
+	t.Parallel()
+
+	commandEvent := func(headers map[string]string, acks *[]types.AckResponse) types.ReceiverEvent {
+		return types.ReceiverEvent{
+			Body:    createSlashCommandBody("/report", "weekly"),
+			Headers: headers,
+			Ack: func(response types.AckResponse) error {
+				*acks = append(*acks, response)
+				return nil
+			},
+		}
+	}
+
+	t.Run("should run lazy functions in goroutines after ack", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+
+		texts := make(chan string, 2)
+		var lazyCtxErr error
+		app.CommandLazy("/report",
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				return args.Ack(nil)
+			},
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				texts <- "first " + args.Command.Text
+				return nil
+			},
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				lazyCtxErr = args.Ctx.Err()
+				texts <- "second " + args.Command.Text
+				return nil
+			},
+		)
+
+		var acks []types.AckResponse
+		ctx, cancel := context.WithCancel(context.Background())
+		require.NoError(t, app.ProcessEvent(ctx, commandEvent(nil, &acks)))
+		cancel()
+		require.Len(t, acks, 1)
+
+		received := []string{receive(t, texts), receive(t, texts)}
+		assert.ElementsMatch(t, []string{"first weekly", "second weekly"}, received)
+		assert.NoError(t, lazyCtxErr, "lazy functions should outlive the request context")
+	})
+
+	t.Run("should pass lazy function errors to the error handler", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+
+		handled := make(chan error, 1)
+		app.Error(func(err error) error {
+			handled <- err
+			return nil
+		})
+		lazyErr := errors.New("report failed")
+		app.CommandLazy("/report",
+			func(args bolt.SlackCommandMiddlewareArgs) error { return args.Ack(nil) },
+			func(args bolt.SlackCommandMiddlewareArgs) error { return lazyErr },
+		)
+
+		var acks []types.AckResponse
+		require.NoError(t, app.ProcessEvent(context.Background(), commandEvent(nil, &acks)))
+
+		select {
+		case err := <-handled:
+			assert.ErrorIs(t, err, lazyErr)
+		case <-time.After(5 * time.Second):
+			t.Fatal("lazy function error was not handled")
+		}
+	})
+
+	t.Run("should only run the named lazy function when re-invoked", func(t *testing.T) {
+		runner := &recordingLazyRunner{}
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret, LazyListenerRunner: runner})
+		require.NoError(t, err)
+
+		var calls []string
+		app.Command("/report", func(args bolt.SlackCommandMiddlewareArgs) error {
+			calls = append(calls, "command")
+			return nil
+		})
+		app.CommandLazy("/report",
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				calls = append(calls, "ack")
+				return args.Ack(nil)
+			},
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				calls = append(calls, "first lazy")
+				return nil
+			},
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				calls = append(calls, "second lazy "+args.Command.Text)
+				return nil
+			},
+		)
+
+		var acks []types.AckResponse
+		require.NoError(t, app.ProcessEvent(context.Background(), commandEvent(map[string]string{"Content-Type": "application/json"}, &acks)))
+		assert.Equal(t, []string{"command", "ack"}, calls)
+		require.Len(t, runner.requests, 2)
+		assert.Equal(t, "1.0", runner.requests[0].ID)
+		assert.Equal(t, "1.1", runner.requests[1].ID)
+
+		// Re-invoke the app as a FaaS runner would
+		request := runner.requests[1]
+		headers := request.InvocationHeaders()
+		assert.Equal(t, "1.1", headers[bolt.LazyListenerHeader])
+		assert.NotEmpty(t, headers[bolt.LazyListenerSignatureHeader])
+		assert.Equal(t, "application/json", headers["Content-Type"])
+
+		calls, acks = nil, nil
+		require.NoError(t, app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    request.Body,
+			Headers: headers,
+			Ack: func(response types.AckResponse) error {
+				acks = append(acks, response)
+				return nil
+			},
+		}))
+		assert.Equal(t, []string{"second lazy weekly"}, calls)
+		assert.Len(t, acks, 1, "re-invocations should be acknowledged at once")
+		assert.Len(t, runner.requests, 2)
+	})
+
+	t.Run("should reject re-invocations without a valid signature", func(t *testing.T) {
+		runner := &recordingLazyRunner{}
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret, LazyListenerRunner: runner})
+		require.NoError(t, err)
+
+		var calls []string
+		app.CommandLazy("/report",
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				calls = append(calls, "ack")
+				return args.Ack(nil)
+			},
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				calls = append(calls, "lazy")
+				return nil
+			},
+		)
+
+		var acks []types.AckResponse
+		require.NoError(t, app.ProcessEvent(context.Background(), commandEvent(nil, &acks)))
+		require.Len(t, runner.requests, 1)
+		request := runner.requests[0]
+		for name, headers := range map[string]map[string]string{
+			"unsigned":         {bolt.LazyListenerHeader: request.ID},
+			"forged signature": {bolt.LazyListenerHeader: request.ID, bolt.LazyListenerSignatureHeader: "v0=forged"},
+			"other function":   {bolt.LazyListenerHeader: "0.1", bolt.LazyListenerSignatureHeader: request.Signature},
+		} {
+			t.Run(name, func(t *testing.T) {
+				calls, acks = nil, nil
+				err := app.ProcessEvent(context.Background(), commandEvent(headers, &acks))
+				require.ErrorIs(t, err, bolt.ReceiverAuthenticityErrorCode)
+				assert.Empty(t, calls)
+				assert.Empty(t, acks)
+			})
+		}
+
+		t.Run("other body", func(t *testing.T) {
+			calls, acks = nil, nil
+			err := app.ProcessEvent(context.Background(), types.ReceiverEvent{
+				Body:    createSlashCommandBody("/report", "yearly"),
+				Headers: request.InvocationHeaders(),
+				Ack: func(response types.AckResponse) error {
+					acks = append(acks, response)
+					return nil
+				},
+			})
+			require.ErrorIs(t, err, bolt.ReceiverAuthenticityErrorCode)
+			assert.Empty(t, calls)
+		})
+	})
+
+	t.Run("should bind say and respond to the event when event contexts are reused", func(t *testing.T) {
+		runner := &recordingLazyRunner{}
+		h := bolttest.New(t, bolt.AppOptions{ReuseEventContexts: true, LazyListenerRunner: runner})
+		h.App.CommandLazy("/report",
+			func(args bolt.SlackCommandMiddlewareArgs) error { return args.Ack(nil) },
+			func(args bolt.SlackCommandMiddlewareArgs) error {
+				if _, err := args.Say(types.SayString("report ready")); err != nil {
+					return err
+				}
+				_, err := args.Respond(args.Ctx, types.RespondString("report sent"))
+				return err
+			},
+		)
+
+		h.Send(bolttest.Command("/report", "weekly")).AssertNoError(t).AssertAcked(t)
+		require.Len(t, runner.requests, 1)
+
+		// The event's context was reset and returned to the pool when ProcessEvent returned
+		require.NoError(t, runner.requests[0].Run(context.Background()))
+		h.Slack.AssertCalledWith(t, "chat.postMessage", map[string]any{
+			"channel": bolttest.ChannelID,
+			"text":    "report ready",
+		})
+		h.Slack.AssertResponded(t, "report sent")
+	})
+}
+
+// receive returns the next value of ch, failing the test if none arrives in time
+func receive(t *testing.T, ch <-chan string) string {
+	t.Helper()
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a lazy function")
+		return ""
+	}
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listenerContextKey marks the context a test sends events with
+type listenerContextKey struct{}
+
+func TestListenerContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should pass the receiver's context to middleware and listeners", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var middlewareValue, listenerValue any
+		var correlationID string
+		h.App.Use(func(args bolt.AllMiddlewareArgs) error {
+			middlewareValue = args.Ctx.Value(listenerContextKey{})
+			return args.Next()
+		})
+		h.App.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error {
+			listenerValue = args.Ctx.Value(listenerContextKey{})
+			correlationID = bolt.CorrelationIDFromContext(args.Ctx)
+			assert.Equal(t, args.Ctx, args.API().Context())
+			return args.Ack(nil)
+		})
+
+		ctx := context.WithValue(context.Background(), listenerContextKey{}, "receiver")
+		h.Receiver.Send(ctx, bolttest.Command("/deploy", "")).AssertNoError(t)
+
+		assert.Equal(t, "receiver", middlewareValue)
+		assert.Equal(t, "receiver", listenerValue)
+		assert.NotEmpty(t, correlationID)
+	})
+
+	t.Run("should cancel say and respond with the event", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var sayErr, respondErr error
+		ctx, cancel := context.WithCancel(context.Background())
+		h.App.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			cancel()
+			_, sayErr = args.Say(types.SayString("deploying"))
+			_, respondErr = args.Respond(nil, types.RespondString("deploying"))
+			return nil
+		})
+
+		h.Receiver.Send(ctx, bolttest.Command("/deploy", "")).AssertNoError(t)
+
+		require.ErrorIs(t, sayErr, context.Canceled)
+		require.ErrorIs(t, respondErr, context.Canceled)
+		assert.Empty(t, h.Slack.Said())
+		assert.Empty(t, h.Slack.Responded())
+	})
+}
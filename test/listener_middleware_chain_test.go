@@ -0,0 +1,159 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// messageEvent returns a receiver event for a message event with text and subtype
+func messageEvent(text, subtype string) types.ReceiverEvent {
+	event := map[string]interface{}{
+		"type":    "message",
+		"user":    "U123456",
+		"text":    text,
+		"ts":      "1234567890.123456",
+		"channel": "C123456",
+	}
+	if subtype != "" {
+		event["subtype"] = subtype
+		event["bot_id"] = "B999999"
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"type":    "event_callback",
+		"team_id": "T123456",
+		"event":   event,
+	})
+	return types.ReceiverEvent{
+		Body: body,
+		Ack:  func(response types.AckResponse) error { return nil },
+	}
+}
+
+func TestListenerMiddlewareChain(t *testing.T) {
+	t.Parallel()
+
+	newApp := func(t *testing.T) *bolt.App {
+		t.Helper()
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+		return app
+	}
+
+	t.Run("should run middleware in order with code after next running once the rest returned", func(t *testing.T) {
+		app := newApp(t)
+		var calls []string
+		app.Use(func(args bolt.AllMiddlewareArgs) error {
+			calls = append(calls, "global")
+			return args.Next()
+		})
+		app.Message("deploy",
+			func(args bolt.SlackEventMiddlewareArgs) error {
+				calls = append(calls, "first")
+				err := args.Next()
+				calls = append(calls, "first after next")
+				return err
+			},
+			func(args bolt.SlackEventMiddlewareArgs) error {
+				calls = append(calls, "second")
+				return args.Next()
+			},
+			func(args bolt.SlackEventMiddlewareArgs) error {
+				calls = append(calls, "handler")
+				return nil
+			},
+		)
+
+		require.NoError(t, app.ProcessEvent(context.Background(), messageEvent("deploy now", "")))
+		assert.Equal(t, []string{"global", "first", "second", "handler", "first after next"}, calls)
+	})
+
+	t.Run("should short-circuit the chain without error when next is not called", func(t *testing.T) {
+		app := newApp(t)
+		handlerCalled := false
+		app.Message("deploy",
+			func(args bolt.SlackEventMiddlewareArgs) error { return nil },
+			func(args bolt.SlackEventMiddlewareArgs) error {
+				handlerCalled = true
+				return nil
+			},
+		)
+
+		require.NoError(t, app.ProcessEvent(context.Background(), messageEvent("deploy", "")))
+		assert.False(t, handlerCalled)
+	})
+
+	t.Run("should stop the chain and report the error a middleware returns", func(t *testing.T) {
+		app := newApp(t)
+		handlerCalled := false
+		app.Message("deploy",
+			func(args bolt.SlackEventMiddlewareArgs) error { return errors.New("not allowed") },
+			func(args bolt.SlackEventMiddlewareArgs) error {
+				handlerCalled = true
+				return nil
+			},
+		)
+
+		err := app.ProcessEvent(context.Background(), messageEvent("deploy", ""))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not allowed")
+		assert.False(t, handlerCalled)
+	})
+
+	t.Run("should run the rest of the chain once when next is called twice", func(t *testing.T) {
+		app := newApp(t)
+		handlerCalls := 0
+		var secondErr error
+		app.Message("deploy",
+			func(args bolt.SlackEventMiddlewareArgs) error {
+				if err := args.Next(); err != nil {
+					return err
+				}
+				secondErr = args.Next()
+				return nil
+			},
+			func(args bolt.SlackEventMiddlewareArgs) error {
+				handlerCalls++
+				return nil
+			},
+		)
+
+		require.NoError(t, app.ProcessEvent(context.Background(), messageEvent("deploy", "")))
+		assert.Equal(t, 1, handlerCalls)
+		assert.ErrorIs(t, secondErr, bolt.MultipleNextCallErrorCode)
+	})
+
+	t.Run("should apply the message pattern before the listener middleware", func(t *testing.T) {
+		app := newApp(t)
+		middlewareCalled := false
+		app.Message("deploy", func(args bolt.SlackEventMiddlewareArgs) error {
+			middlewareCalled = true
+			return args.Next()
+		})
+
+		require.NoError(t, app.ProcessEvent(context.Background(), messageEvent("hello", "")))
+		assert.False(t, middlewareCalled)
+	})
+
+	t.Run("should compose built-in middleware adapted with Listener", func(t *testing.T) {
+		app := newApp(t)
+		var handled []string
+		app.Message("",
+			bolt.Listener[bolt.SlackEventMiddlewareArgs](bolt.Subtype("bot_message")),
+			func(args bolt.SlackEventMiddlewareArgs) error {
+				handled = append(handled, args.Message.Text)
+				return nil
+			},
+		)
+
+		require.NoError(t, app.ProcessEvent(context.Background(), messageEvent("from a bot", "bot_message")))
+		require.NoError(t, app.ProcessEvent(context.Background(), messageEvent("from a user", "")))
+		assert.Equal(t, []string{"from a bot"}, handled)
+	})
+}
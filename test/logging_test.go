@@ -0,0 +1,151 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/logging"
+	boltzap "github.com/Asafrose/bolt-go/pkg/logging/zap"
+	boltzerolog "github.com/Asafrose/bolt-go/pkg/logging/zerolog"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// processFailingMention runs an app_mention event through app whose listener fails
+func processFailingMention(t *testing.T, app *bolt.App) {
+	app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+		return errors.New("listener error")
+	})
+
+	err := app.ProcessEvent(context.Background(), types.ReceiverEvent{
+		Body:    createAppMentionEventBody(),
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Ack:     func(response types.AckResponse) error { return nil },
+	})
+	require.Error(t, err)
+}
+
+func TestLoggingFields(t *testing.T) {
+	t.Parallel()
+	t.Run("should expand key value pairs, attributes and groups", func(t *testing.T) {
+		fields := map[string]any{}
+		logging.Fields([]any{
+			"event_type", "app_mention",
+			slog.Int("retry_num", 2),
+			slog.Group("listener", slog.String("name", "event")),
+			42,
+			"dangling",
+		}, func(key string, value any) {
+			if existing, ok := fields[key]; ok {
+				value = []any{existing, value}
+			}
+			fields[key] = value
+		})
+
+		assert.Equal(t, map[string]any{
+			"event_type":    "app_mention",
+			"retry_num":     int64(2),
+			"listener.name": "event",
+			"!BADKEY":       []any{42, "dangling"},
+		}, fields)
+	})
+}
+
+func TestZapLogger(t *testing.T) {
+	t.Parallel()
+	t.Run("should write messages and fields to the zap logger", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		logger := boltzap.New(zap.New(core))
+
+		logger.Debug("hidden")
+		logger.Warn("queue full", "event_type", "app_mention", slog.Int("retry_num", 1), "error", errors.New("boom"))
+
+		entries := logs.All()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "queue full", entries[0].Message)
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+		assert.Equal(t, map[string]interface{}{
+			"event_type": "app_mention",
+			"retry_num":  int64(1),
+			"error":      "boom",
+		}, entries[0].ContextMap())
+	})
+
+	t.Run("should be usable as the app logger", func(t *testing.T) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Logger:        boltzap.New(zap.New(core)),
+		})
+		require.NoError(t, err)
+
+		processFailingMention(t, app)
+
+		unhandled := logs.FilterMessage("Unhandled error").All()
+		require.Len(t, unhandled, 1)
+		assert.Equal(t, "app_mention", unhandled[0].ContextMap()["event_type"])
+	})
+}
+
+func TestZerologLogger(t *testing.T) {
+	t.Parallel()
+	t.Run("should write messages and fields to the zerolog logger", func(t *testing.T) {
+		var logs bytes.Buffer
+		logger := boltzerolog.New(zerolog.New(&logs).Level(zerolog.InfoLevel))
+
+		logger.Debug("hidden")
+		logger.Error("failed", "event_type", "app_mention", slog.Int("retry_num", 1), "error", errors.New("boom"))
+
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+		assert.Equal(t, map[string]interface{}{
+			"level":      "error",
+			"message":    "failed",
+			"event_type": "app_mention",
+			"retry_num":  float64(1),
+			"error":      "boom",
+		}, record)
+	})
+
+	t.Run("should be usable as the app logger", func(t *testing.T) {
+		var logs bytes.Buffer
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Logger:        boltzerolog.New(zerolog.New(&logs)),
+		})
+		require.NoError(t, err)
+
+		processFailingMention(t, app)
+
+		assert.Contains(t, logs.String(), `"message":"Unhandled error"`)
+		assert.Contains(t, logs.String(), `"event_type":"app_mention"`)
+	})
+}
+
+func TestLoggingWith(t *testing.T) {
+	t.Parallel()
+	t.Run("should add fields to every message of an adapted logger", func(t *testing.T) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		logger := logging.With(boltzap.New(zap.New(core)), "correlation_id", "abc")
+
+		logger.Info("first", "n", 1)
+		logger.Error("second")
+
+		entries := logs.All()
+		require.Len(t, entries, 2)
+		assert.Equal(t, map[string]interface{}{"correlation_id": "abc", "n": int64(1)}, entries[0].ContextMap())
+		assert.Equal(t, map[string]interface{}{"correlation_id": "abc"}, entries[1].ContextMap())
+	})
+}
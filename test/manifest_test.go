@@ -0,0 +1,97 @@
+package test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateManifest(t *testing.T) {
+	t.Parallel()
+	newManifestApp := func(t *testing.T, options bolt.AppOptions) *bolt.App {
+		options.Token = fakeToken
+		options.SigningSecret = fakeSigningSecret
+		options.Receiver = &FakeReceiver{}
+		app, err := bolt.New(options)
+		require.NoError(t, err)
+
+		noop := func(args bolt.SlackEventMiddlewareArgs) error { return nil }
+		app.Event("app_mention", noop)
+		app.Event("reaction_added", noop)
+		app.Message("hello", noop)
+		app.EventPattern(regexp.MustCompile("^pin_"), noop)
+		app.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error { return nil })
+		app.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error { return nil })
+		app.Shortcut(bolt.ShortcutConstraints{CallbackID: "open_ticket", Type: "message_action"}, func(args bolt.SlackShortcutMiddlewareArgs) error { return nil })
+		app.Action(bolt.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error { return nil })
+		return app
+	}
+
+	t.Run("should list events, commands, shortcuts and scopes of the registered listeners", func(t *testing.T) {
+		app := newManifestApp(t, bolt.AppOptions{Scopes: []string{"chat:write"}, RedirectURI: "https://example.com/oauth"})
+
+		manifest, err := app.GenerateManifest(bolt.ManifestOptions{
+			Name:                "Deployer",
+			RequestURL:          "https://example.com/slack/events",
+			CommandDescriptions: map[string]string{"/deploy": "Deploy a service"},
+			ShortcutNames:       map[string]string{"open_ticket": "Open ticket"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Deployer", manifest.DisplayInformation.Name)
+		assert.Equal(t, []bolt.ManifestSlashCommand{{Command: "/deploy", URL: "https://example.com/slack/events", Description: "Deploy a service"}}, manifest.Features.SlashCommands)
+		assert.Equal(t, []bolt.ManifestShortcut{{Name: "Open ticket", Type: "message", CallbackID: "open_ticket", Description: "Open ticket"}}, manifest.Features.Shortcuts)
+		require.NotNil(t, manifest.Settings.EventSubscriptions)
+		assert.Equal(t, []string{"app_mention", "message.channels", "message.groups", "message.im", "message.mpim", "reaction_added"}, manifest.Settings.EventSubscriptions.BotEvents)
+		require.NotNil(t, manifest.Settings.Interactivity)
+		assert.True(t, manifest.Settings.Interactivity.IsEnabled)
+		assert.Equal(t, []string{"app_mentions:read", "channels:history", "chat:write", "commands", "groups:history", "im:history", "mpim:history", "reactions:read"}, manifest.OAuthConfig.Scopes.Bot)
+		assert.Equal(t, []string{"https://example.com/oauth"}, manifest.OAuthConfig.RedirectURLs)
+	})
+
+	t.Run("should encode the manifest as JSON and YAML", func(t *testing.T) {
+		app := newManifestApp(t, bolt.AppOptions{})
+		manifest, err := app.GenerateManifest(bolt.ManifestOptions{Name: "Deployer", RequestURL: "https://example.com/slack/events"})
+		require.NoError(t, err)
+
+		jsonData, err := manifest.JSON()
+		require.NoError(t, err)
+		var fromJSON map[string]interface{}
+		require.NoError(t, json.Unmarshal(jsonData, &fromJSON))
+
+		yamlData, err := manifest.YAML()
+		require.NoError(t, err)
+		var fromYAML map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(yamlData, &fromYAML))
+
+		for _, decoded := range []map[string]interface{}{fromJSON, fromYAML} {
+			settings := decoded["settings"].(map[string]interface{})
+			events := settings["event_subscriptions"].(map[string]interface{})
+			assert.Equal(t, "https://example.com/slack/events", events["request_url"])
+			assert.Equal(t, false, settings["socket_mode_enabled"])
+		}
+	})
+
+	t.Run("should omit request URLs in Socket Mode", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, AppToken: "xapp-test", SocketMode: true})
+		require.NoError(t, err)
+		app.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error { return nil })
+
+		manifest, err := app.GenerateManifest(bolt.ManifestOptions{Name: "Deployer", RequestURL: "https://example.com/slack/events"})
+		require.NoError(t, err)
+		assert.True(t, manifest.Settings.SocketModeEnabled)
+		assert.Empty(t, manifest.Features.SlashCommands[0].URL)
+		assert.Equal(t, "/deploy", manifest.Features.SlashCommands[0].Description)
+	})
+
+	t.Run("should require a name", func(t *testing.T) {
+		app := newManifestApp(t, bolt.AppOptions{})
+		_, err := app.GenerateManifest(bolt.ManifestOptions{})
+		assert.EqualError(t, err, "manifest name is required")
+	})
+}
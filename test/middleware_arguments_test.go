@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Asafrose/bolt-go"
 	"github.com/Asafrose/bolt-go/pkg/app"
@@ -784,6 +788,49 @@ func TestMiddlewareArgumentsAdvanced(t *testing.T) {
 		// Authorization should be skipped for app_uninstalled events
 		// Handler may or may not be called depending on implementation
 	})
+
+	t.Run("should expose the raw body and headers of the request", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+
+		var globalRawBody, listenerRawBody []byte
+		var listenerHeaders map[string]string
+		app.Use(func(args types.AllMiddlewareArgs) error {
+			globalRawBody = args.RawBody()
+			return args.Next()
+		})
+		app.Command("/archive", func(args types.SlackCommandMiddlewareArgs) error {
+			listenerRawBody = args.RawBody()
+			listenerHeaders = args.Headers()
+			return args.Ack(nil)
+		})
+
+		body := []byte("command=%2Farchive&text=hello&team_id=T123456&user_id=U123456&channel_id=C123456")
+		headers := map[string]string{
+			"Content-Type":              "application/x-www-form-urlencoded",
+			"X-Slack-Request-Timestamp": "1531420618",
+		}
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    body,
+			Headers: headers,
+			Ack:     func(response types.AckResponse) error { return nil },
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, body, globalRawBody)
+		assert.Equal(t, body, listenerRawBody)
+		assert.Equal(t, headers, listenerHeaders)
+	})
+
+	t.Run("should return nil raw body and headers without a context", func(t *testing.T) {
+		args := types.AllMiddlewareArgs{}
+		assert.Nil(t, args.RawBody())
+		assert.Nil(t, args.Headers())
+	})
 }
 
 func TestMiddlewareArgumentsRespond(t *testing.T) {
@@ -848,10 +895,11 @@ func TestMiddlewareArgumentsRespond(t *testing.T) {
 		assert.NotNil(t, receivedArgs.Respond, "Respond function should be available")
 
 		// Test respond function
-		err = receivedArgs.Respond(&types.RespondArguments{
+		respondResponse, err := receivedArgs.Respond(context.Background(), &types.RespondArguments{
 			Text: "Button clicked!",
 		})
 		require.NoError(t, err, "Respond should work with response_url")
+		assert.Equal(t, http.StatusOK, respondResponse.StatusCode)
 		assert.True(t, responseReceived, "Response should be sent to mock server")
 	})
 
@@ -924,7 +972,7 @@ func TestMiddlewareArgumentsRespond(t *testing.T) {
 			ResponseType: types.ResponseTypeEphemeral,
 		}
 
-		err = receivedArgs.Respond(response)
+		_, err = receivedArgs.Respond(context.Background(), response)
 		require.NoError(t, err, "Respond should work with complex response object")
 		assert.True(t, responseReceived, "Response should be sent to mock server")
 	})
@@ -986,6 +1034,162 @@ func TestMiddlewareArgumentsRespond(t *testing.T) {
 		// This depends on the implementation of view middleware arguments
 		assert.NotNil(t, receivedArgs, "View args should be received")
 	})
+
+	t.Run("should use the configured HTTP client and return the response status and body", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("expired_url"))
+		}))
+		defer mockServer.Close()
+
+		transportUsed := false
+		httpClient := &http.Client{
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				transportUsed = true
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+		}
+
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			HTTPClient:    httpClient,
+		})
+		require.NoError(t, err)
+
+		var receivedArgs types.SlackActionMiddlewareArgs
+		app.Action(bolt.ActionConstraints{ActionID: "button_1"}, func(args types.SlackActionMiddlewareArgs) error {
+			receivedArgs = args
+			return nil
+		})
+
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type": "block_actions",
+			"actions": []interface{}{
+				map[string]interface{}{"action_id": "button_1", "type": "button"},
+			},
+			"response_url": mockServer.URL,
+			"user":         map[string]interface{}{"id": "U123456"},
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    bodyBytes,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		})
+		require.NoError(t, err)
+		require.NotNil(t, receivedArgs.Respond)
+
+		respondResponse, err := receivedArgs.Respond(context.Background(), types.RespondString("too late"))
+		require.Error(t, err)
+		require.NotNil(t, respondResponse)
+		assert.True(t, transportUsed, "Configured HTTP client should be used")
+		assert.Equal(t, http.StatusNotFound, respondResponse.StatusCode)
+		assert.Equal(t, "expired_url", string(respondResponse.Body))
+
+		cancelledCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = receivedArgs.Respond(cancelledCtx, types.RespondString("cancelled"))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("should share a pooled HTTP client configured by HTTPClientOptions with webhook posts", func(t *testing.T) {
+		var connections int32
+		mockServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+		mockServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&connections, 1)
+			}
+		}
+		mockServer.Start()
+		defer mockServer.Close()
+
+		var proxied int32
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			HTTPClientOptions: bolt.HTTPClientOptions{
+				Timeout: 5 * time.Second,
+				Proxy: func(req *http.Request) (*url.URL, error) {
+					atomic.AddInt32(&proxied, 1)
+					return nil, nil
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, app.HTTPClient().Timeout)
+
+		for i := 0; i < 3; i++ {
+			response, err := app.SendWebhook(context.Background(), mockServer.URL, types.RespondString("hello"))
+			require.NoError(t, err)
+			assert.Equal(t, "ok", string(response.Body))
+		}
+
+		assert.Equal(t, int32(3), atomic.LoadInt32(&proxied), "Configured proxy should be consulted for each post")
+		assert.Equal(t, int32(1), atomic.LoadInt32(&connections), "Posts should reuse a pooled connection")
+	})
+
+	t.Run("should only post to allowed response URL hosts", func(t *testing.T) {
+		var posted []string
+		client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			posted = append(posted, req.URL.String())
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+		})}
+
+		newApp := func(hosts []string) *bolt.App {
+			app, err := bolt.New(bolt.AppOptions{
+				Token:            fakeToken,
+				SigningSecret:    fakeSigningSecret,
+				HTTPClient:       client,
+				ResponseURLHosts: hosts,
+			})
+			require.NoError(t, err)
+			return app
+		}
+
+		tests := []struct {
+			name    string
+			hosts   []string
+			url     string
+			allowed bool
+		}{
+			{"default host", nil, "https://hooks.slack.com/commands/T1/2/abc", true},
+			{"default host in upper case", nil, "https://HOOKS.slack.com/commands/T1/2/abc", true},
+			{"default host over http", nil, "http://hooks.slack.com/commands/T1/2/abc", false},
+			{"host with the default as a prefix", nil, "https://hooks.slack.com.evil.example/commands", false},
+			{"userinfo spoofing the default host", nil, "https://hooks.slack.com@evil.example/commands", false},
+			{"unlisted host", nil, "https://proxy.example.com/commands", false},
+			{"configured host", []string{"proxy.example.com"}, "https://proxy.example.com/commands", true},
+			{"default host when others are configured", []string{"proxy.example.com"}, "https://hooks.slack.com/commands", false},
+			{"configured host and port", []string{"proxy.example.com:8443"}, "https://proxy.example.com:8443/commands", true},
+			{"wildcard subdomain", []string{"*.slack-gov.com"}, "https://hooks.slack-gov.com/commands", true},
+			{"wildcard parent domain", []string{"*.slack-gov.com"}, "https://slack-gov.com/commands", false},
+			{"loopback over http", nil, "http://127.0.0.1:3000/commands", true},
+			{"localhost over http", nil, "http://localhost:3000/commands", true},
+			{"relative URL", nil, "/commands", false},
+		}
+
+		for _, tt := range tests {
+			posted = nil
+			_, err := newApp(tt.hosts).SendWebhook(context.Background(), tt.url, types.RespondString("hello"))
+			if tt.allowed {
+				assert.NoError(t, err, tt.name)
+				assert.Equal(t, []string{tt.url}, posted, tt.name)
+			} else {
+				assert.Error(t, err, tt.name)
+				assert.Empty(t, posted, tt.name)
+			}
+		}
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
 }
 
 func TestMiddlewareArgumentsLogger(t *testing.T) {
@@ -1661,4 +1865,158 @@ func TestMiddlewareArgumentsSay(t *testing.T) {
 			assert.NotNil(t, receivedArgs.Context, "Context should be available")
 		})
 	})
+
+	t.Run("should return the posted message details", func(t *testing.T) {
+		mockAPIServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = r.ParseForm()
+			response := map[string]interface{}{
+				"ok":      true,
+				"channel": r.Form.Get("channel"),
+				"ts":      "1234567890.123456",
+			}
+			if r.Form.Get("channel") == "C_MISSING" {
+				response = map[string]interface{}{
+					"ok":    false,
+					"error": "channel_not_found",
+				}
+			}
+			if err := json.NewEncoder(w).Encode(response); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+			}
+		}))
+		defer mockAPIServer.Close()
+
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			BotID:         fakeBotID,
+			BotUserID:     fakeBotUserID,
+			ClientOptions: []slack.Option{slack.OptionAPIURL(mockAPIServer.URL + "/api/")},
+		})
+		require.NoError(t, err)
+
+		var receivedArgs types.SlackEventMiddlewareArgs
+		app.Event("app_mention", func(args types.SlackEventMiddlewareArgs) error {
+			receivedArgs = args
+			return nil
+		})
+
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type": "event_callback",
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"text":    "<@U987654> hello",
+				"channel": "C123456",
+			},
+			"team_id": "T123456",
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    bodyBytes,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		})
+		require.NoError(t, err)
+		require.NotNil(t, receivedArgs.Say)
+
+		response, err := receivedArgs.Say(types.SayArguments{Text: "Hello back!", ThreadTS: "1111.2222"})
+		require.NoError(t, err)
+		assert.True(t, response.OK)
+		assert.Equal(t, "C123456", response.Channel)
+		assert.Equal(t, "1234567890.123456", response.Timestamp)
+
+		response, err = receivedArgs.Say(types.SayArguments{Channel: "C_MISSING", Text: "Hello?"})
+		require.Error(t, err)
+		require.NotNil(t, response)
+		assert.False(t, response.OK)
+		assert.Equal(t, "channel_not_found", response.Error)
+	})
+
+	t.Run("should post typed message metadata", func(t *testing.T) {
+		type taskPayload struct {
+			TaskID   string `json:"task_id"`
+			Priority int    `json:"priority"`
+		}
+
+		var postedMetadata string
+		mockAPIServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = r.ParseForm()
+			postedMetadata = r.Form.Get("metadata")
+			if err := json.NewEncoder(w).Encode(map[string]interface{}{
+				"ok":      true,
+				"channel": r.Form.Get("channel"),
+				"ts":      "1234567890.123456",
+			}); err != nil {
+				t.Errorf("Failed to encode response: %v", err)
+			}
+		}))
+		defer mockAPIServer.Close()
+
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			ClientOptions: []slack.Option{slack.OptionAPIURL(mockAPIServer.URL + "/api/")},
+		})
+		require.NoError(t, err)
+
+		var receivedArgs types.SlackEventMiddlewareArgs
+		app.Event("app_mention", func(args types.SlackEventMiddlewareArgs) error {
+			receivedArgs = args
+			return nil
+		})
+
+		bodyBytes, _ := json.Marshal(map[string]interface{}{
+			"type": "event_callback",
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U123456",
+				"channel": "C123456",
+			},
+			"team_id": "T123456",
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    bodyBytes,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		})
+		require.NoError(t, err)
+		require.NotNil(t, receivedArgs.Say)
+
+		response, err := receivedArgs.Say(types.SayArguments{
+			Text: "Task created",
+			Metadata: types.MessageMetadata[taskPayload]{
+				EventType:    "task_created",
+				EventPayload: taskPayload{TaskID: "T-1", Priority: 2},
+			},
+		})
+		require.NoError(t, err)
+		assert.True(t, response.OK)
+
+		var metadata map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(postedMetadata), &metadata))
+		assert.Equal(t, "task_created", metadata["event_type"])
+		assert.Equal(t, map[string]interface{}{"task_id": "T-1", "priority": float64(2)}, metadata["event_payload"])
+
+		_, err = receivedArgs.Say(types.SayArguments{
+			Text:     "Invalid metadata",
+			Metadata: types.MessageMetadata[string]{EventType: "invalid", EventPayload: "not an object"},
+		})
+		require.Error(t, err)
+
+		// Raw slack metadata is posted as is
+		for _, raw := range []types.SayMetadata{
+			&slack.SlackMetadata{EventType: "task_closed", EventPayload: map[string]interface{}{"task_id": "T-2"}},
+			slack.SlackMetadata{EventType: "task_closed", EventPayload: map[string]interface{}{"task_id": "T-2"}},
+		} {
+			_, err = receivedArgs.Say(types.SayArguments{Text: "Task closed", Metadata: raw})
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal([]byte(postedMetadata), &metadata))
+			assert.Equal(t, "task_closed", metadata["event_type"])
+			assert.Equal(t, map[string]interface{}{"task_id": "T-2"}, metadata["event_payload"])
+		}
+	})
 }
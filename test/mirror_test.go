@@ -0,0 +1,166 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mirroredRequest is a request received by a mirror target
+type mirroredRequest struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+// newMirrorTarget starts a server handing the requests it receives to a channel
+func newMirrorTarget(t *testing.T) (*httptest.Server, chan mirroredRequest) {
+	t.Helper()
+	requests := make(chan mirroredRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- mirroredRequest{path: r.URL.Path, header: r.Header.Clone(), body: string(body)}
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// nextMirroredRequest waits for the next request received by a mirror target
+func nextMirroredRequest(t *testing.T, requests chan mirroredRequest) mirroredRequest {
+	t.Helper()
+	select {
+	case request := <-requests:
+		return request
+	case <-time.After(5 * time.Second):
+		t.Fatal("no request was mirrored")
+		return mirroredRequest{}
+	}
+}
+
+// startMirroringReceiver starts an HTTP receiver mirroring to options on a free port and returns
+// its base URL
+func startMirroringReceiver(t *testing.T, options *types.MirrorOptions) (*receivers.HTTPReceiver, string) {
+	t.Helper()
+	_, port, err := net.SplitHostPort(freeAddr(t))
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	receiver := receivers.NewHTTPReceiver(types.HTTPReceiverOptions{
+		SigningSecret: fakeSigningSecret,
+		Port:          portNum,
+		Mirror:        options,
+	})
+	app, err := bolt.New(bolt.AppOptions{Token: fakeToken, Receiver: receiver})
+	require.NoError(t, err)
+	app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+	require.NoError(t, receiver.Init(app))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = receiver.Start(ctx) }()
+
+	baseURL := "http://127.0.0.1:" + port
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", "127.0.0.1:"+port)
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return receiver, baseURL
+}
+
+// postSigned sends body to url signed with secret
+func postSigned(t *testing.T, url, secret, body string) *http.Response {
+	t.Helper()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", helpers.GenerateSlackSignature(secret, "v0:"+timestamp+":"+body))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp
+}
+
+const mirroredEventBody = `{"type":"event_callback","team_id":"T123","event_id":"Ev123","event":{"type":"app_mention","text":"hello","user":"U123","channel":"C123","ts":"1.2"}}`
+
+func TestHTTPReceiverMirror(t *testing.T) {
+	t.Parallel()
+	t.Run("should pass Slack's signature through or re-sign for each target", func(t *testing.T) {
+		passthrough, passthroughRequests := newMirrorTarget(t)
+		resigned, resignedRequests := newMirrorTarget(t)
+		_, baseURL := startMirroringReceiver(t, &types.MirrorOptions{Targets: []types.MirrorTarget{
+			{URL: passthrough.URL},
+			{URL: resigned.URL + "/", SigningSecret: "shadow-secret"},
+		}})
+
+		resp := postSigned(t, baseURL+"/slack/events", fakeSigningSecret, mirroredEventBody)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		request := nextMirroredRequest(t, passthroughRequests)
+		assert.Equal(t, "/slack/events", request.path)
+		assert.Equal(t, mirroredEventBody, request.body)
+		assert.Equal(t, "true", request.header.Get(receivers.MirroredHeader))
+		assert.Equal(t, "application/json", request.header.Get("Content-Type"))
+		assert.NoError(t, helpers.VerifySlackSignature(fakeSigningSecret, request.header.Get("X-Slack-Signature"),
+			request.header.Get("X-Slack-Request-Timestamp"), []byte(request.body)))
+
+		request = nextMirroredRequest(t, resignedRequests)
+		assert.Equal(t, "/slack/events", request.path)
+		assert.Equal(t, mirroredEventBody, request.body)
+		assert.Equal(t, "true", request.header.Get(receivers.MirroredHeader))
+		assert.NoError(t, helpers.VerifySlackSignature("shadow-secret", request.header.Get("X-Slack-Signature"),
+			request.header.Get("X-Slack-Request-Timestamp"), []byte(request.body)))
+	})
+
+	t.Run("should not mirror requests failing verification", func(t *testing.T) {
+		target, requests := newMirrorTarget(t)
+		_, baseURL := startMirroringReceiver(t, &types.MirrorOptions{Targets: []types.MirrorTarget{{URL: target.URL}}})
+
+		resp := postSigned(t, baseURL+"/slack/events", "wrong-secret", mirroredEventBody)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		select {
+		case request := <-requests:
+			t.Fatalf("unverified request was mirrored: %+v", request)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("should send queued requests when the receiver stops", func(t *testing.T) {
+		release := make(chan struct{})
+		received := make(chan struct{}, 10)
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			received <- struct{}{}
+		}))
+		defer target.Close()
+		receiver, baseURL := startMirroringReceiver(t, &types.MirrorOptions{Targets: []types.MirrorTarget{{URL: target.URL}}})
+
+		postSigned(t, baseURL+"/slack/events", fakeSigningSecret, mirroredEventBody)
+		postSigned(t, baseURL+"/slack/events", fakeSigningSecret, mirroredEventBody)
+		close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, receiver.Stop(ctx))
+		assert.Len(t, received, 2)
+	})
+}
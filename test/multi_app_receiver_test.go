@@ -0,0 +1,151 @@
+package test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostApp creates an app hosted by receiver that reports the mentions it handles to mentions
+func hostApp(t *testing.T, receiver *receivers.MultiAppReceiver, options types.HostedAppOptions, mentions chan<- string) *bolt.App {
+	t.Helper()
+	app, err := bolt.New(bolt.AppOptions{Token: fakeToken, Receiver: receiver.Host(options)})
+	require.NoError(t, err)
+	app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+		mentions <- options.Name
+		return nil
+	})
+	return app
+}
+
+// nextMention waits for the name of the next app handling a mention
+func nextMention(t *testing.T, mentions <-chan string) string {
+	t.Helper()
+	select {
+	case name := <-mentions:
+		return name
+	case <-time.After(5 * time.Second):
+		t.Fatal("no app handled the mention")
+		return ""
+	}
+}
+
+func TestMultiAppReceiver(t *testing.T) {
+	t.Parallel()
+	t.Run("should route shared endpoints by signing secret", func(t *testing.T) {
+		receiver := bolt.NewMultiAppReceiver(bolt.MultiAppReceiverOptions{})
+		mentions := make(chan string, 2)
+		hostApp(t, receiver, bolt.HostedAppOptions{Name: "acme", HTTPReceiverOptions: bolt.HTTPReceiverOptions{SigningSecret: "acme-secret"}}, mentions)
+		hostApp(t, receiver, bolt.HostedAppOptions{Name: "globex", HTTPReceiverOptions: bolt.HTTPReceiverOptions{SigningSecret: "globex-secret"}}, mentions)
+
+		handler, err := receiver.Handler()
+		require.NoError(t, err)
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		resp := postSigned(t, server.URL+"/slack/events", "globex-secret", mirroredEventBody)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "globex", nextMention(t, mentions))
+
+		resp = postSigned(t, server.URL+"/slack/events", "acme-secret", mirroredEventBody)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "acme", nextMention(t, mentions))
+
+		resp = postSigned(t, server.URL+"/slack/events", "unknown-secret", mirroredEventBody)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Empty(t, mentions)
+	})
+
+	t.Run("should serve apps with a path prefix under their own paths", func(t *testing.T) {
+		receiver := bolt.NewMultiAppReceiver(bolt.MultiAppReceiverOptions{})
+		mentions := make(chan string, 2)
+		hostApp(t, receiver, bolt.HostedAppOptions{
+			Name:       "acme",
+			PathPrefix: "/acme/",
+			HTTPReceiverOptions: bolt.HTTPReceiverOptions{
+				SigningSecret: fakeSigningSecret,
+				CustomRoutes: []types.CustomRoute{{Path: "/health", Handler: func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte("acme ok"))
+				}}},
+			},
+		}, mentions)
+		hostApp(t, receiver, bolt.HostedAppOptions{Name: "globex", PathPrefix: "/globex", HTTPReceiverOptions: bolt.HTTPReceiverOptions{SigningSecret: fakeSigningSecret}}, mentions)
+
+		handler, err := receiver.Handler()
+		require.NoError(t, err)
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		postSigned(t, server.URL+"/globex/slack/events", fakeSigningSecret, mirroredEventBody)
+		assert.Equal(t, "globex", nextMention(t, mentions))
+		postSigned(t, server.URL+"/acme/slack/events", fakeSigningSecret, mirroredEventBody)
+		assert.Equal(t, "acme", nextMention(t, mentions))
+
+		health, err := http.Get(server.URL + "/acme/health")
+		require.NoError(t, err)
+		_ = health.Body.Close()
+		assert.Equal(t, http.StatusOK, health.StatusCode)
+
+		notFound := postSigned(t, server.URL+"/slack/events", fakeSigningSecret, mirroredEventBody)
+		assert.Equal(t, http.StatusNotFound, notFound.StatusCode)
+	})
+
+	t.Run("should reject apps claiming the same paths", func(t *testing.T) {
+		receiver := bolt.NewMultiAppReceiver(bolt.MultiAppReceiverOptions{})
+		mentions := make(chan string)
+		hostApp(t, receiver, bolt.HostedAppOptions{Name: "acme", PathPrefix: "/team", HTTPReceiverOptions: bolt.HTTPReceiverOptions{SigningSecret: fakeSigningSecret}}, mentions)
+		hostApp(t, receiver, bolt.HostedAppOptions{Name: "globex", PathPrefix: "/team", HTTPReceiverOptions: bolt.HTTPReceiverOptions{SigningSecret: fakeSigningSecret}}, mentions)
+
+		_, err := receiver.Handler()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "app globex")
+		assert.Contains(t, err.Error(), "app acme")
+	})
+
+	t.Run("should share one server between the apps started", func(t *testing.T) {
+		_, port, err := net.SplitHostPort(freeAddr(t))
+		require.NoError(t, err)
+		portNum, err := strconv.Atoi(port)
+		require.NoError(t, err)
+
+		receiver := bolt.NewMultiAppReceiver(bolt.MultiAppReceiverOptions{Port: portNum})
+		mentions := make(chan string, 1)
+		acme := hostApp(t, receiver, bolt.HostedAppOptions{Name: "acme", HTTPReceiverOptions: bolt.HTTPReceiverOptions{SigningSecret: "acme-secret"}}, mentions)
+		globex := hostApp(t, receiver, bolt.HostedAppOptions{Name: "globex", HTTPReceiverOptions: bolt.HTTPReceiverOptions{SigningSecret: "globex-secret"}}, mentions)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		started := make(chan error, 2)
+		go func() { started <- acme.Start(ctx) }()
+		go func() { started <- globex.Start(ctx) }()
+
+		require.Eventually(t, func() bool {
+			conn, err := net.Dial("tcp", "127.0.0.1:"+port)
+			if err == nil {
+				_ = conn.Close()
+			}
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		postSigned(t, "http://127.0.0.1:"+port+"/slack/events", "globex-secret", mirroredEventBody)
+		assert.Equal(t, "globex", nextMention(t, mentions))
+
+		cancel()
+		for range 2 {
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatal("apps did not return after the receiver stopped")
+			}
+		}
+	})
+}
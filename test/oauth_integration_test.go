@@ -2,7 +2,16 @@ package test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Asafrose/bolt-go"
 	"github.com/Asafrose/bolt-go/pkg/oauth"
@@ -144,6 +153,48 @@ func TestOAuthIntegration(t *testing.T) {
 		})
 	})
 
+	t.Run("JWTStateStore", func(t *testing.T) {
+		ctx := context.Background()
+
+		t.Run("should generate and verify signed state", func(t *testing.T) {
+			store := oauth.NewJWTStateStore("test-secret", 0)
+			installOptions := &oauth.InstallURLOptions{Scopes: []string{"test-scope"}, Metadata: map[string]interface{}{"plan": "pro"}}
+
+			state, err := store.GenerateStateParam(ctx, installOptions)
+			require.NoError(t, err)
+			assert.Len(t, strings.Split(state, "."), 3, "state should be a JWT")
+
+			retrieved, err := oauth.NewJWTStateStore("test-secret", 0).VerifyStateParam(ctx, state)
+			require.NoError(t, err)
+			assert.Equal(t, installOptions, retrieved)
+		})
+
+		t.Run("should reject states signed with another secret or changed", func(t *testing.T) {
+			state, err := oauth.NewJWTStateStore("other-secret", 0).GenerateStateParam(ctx, &oauth.InstallURLOptions{})
+			require.NoError(t, err)
+			_, err = oauth.NewJWTStateStore("test-secret", 0).VerifyStateParam(ctx, state)
+			assert.ErrorContains(t, err, "signature")
+
+			store := oauth.NewJWTStateStore("test-secret", 0)
+			state, err = store.GenerateStateParam(ctx, &oauth.InstallURLOptions{Scopes: []string{"commands"}})
+			require.NoError(t, err)
+			parts := strings.Split(state, ".")
+			forged, err := oauth.NewJWTStateStore("test-secret", 0).GenerateStateParam(ctx, &oauth.InstallURLOptions{Scopes: []string{"admin"}})
+			require.NoError(t, err)
+			_, err = store.VerifyStateParam(ctx, parts[0]+"."+strings.Split(forged, ".")[1]+"."+parts[2])
+			assert.ErrorContains(t, err, "signature")
+		})
+
+		t.Run("should reject expired states", func(t *testing.T) {
+			store := oauth.NewJWTStateStore("test-secret", time.Nanosecond)
+			state, err := store.GenerateStateParam(ctx, &oauth.InstallURLOptions{})
+			require.NoError(t, err)
+
+			_, err = store.VerifyStateParam(ctx, state)
+			assert.ErrorContains(t, err, "expired")
+		})
+	})
+
 	t.Run("EncryptedStateStore", func(t *testing.T) {
 		t.Run("should generate and verify encrypted state", func(t *testing.T) {
 			store := oauth.NewEncryptedStateStore("test-secret")
@@ -314,3 +365,318 @@ func TestOAuthIntegration(t *testing.T) {
 		})
 	})
 }
+
+// staticStateStore always generates the same state and only accepts it
+type staticStateStore string
+
+func (s staticStateStore) GenerateStateParam(ctx context.Context, installOptions *oauth.InstallURLOptions) (string, error) {
+	return string(s), nil
+}
+
+func (s staticStateStore) VerifyStateParam(ctx context.Context, state string) (*oauth.InstallURLOptions, error) {
+	if state != string(s) {
+		return nil, errors.New("unknown state")
+	}
+	return &oauth.InstallURLOptions{}, nil
+}
+
+// startOAuthReceiver starts an HTTP receiver with OAuth configured by installerOptions on a free
+// port and returns its base URL
+func startOAuthReceiver(t *testing.T, installerOptions *types.InstallerOptions) string {
+	t.Helper()
+	_, port, err := net.SplitHostPort(freeAddr(t))
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	receiver := receivers.NewHTTPReceiver(types.HTTPReceiverOptions{
+		SigningSecret:    fakeSigningSecret,
+		Port:             portNum,
+		ClientID:         "test-client-id",
+		ClientSecret:     "test-client-secret",
+		Scopes:           []string{"chat:write"},
+		InstallerOptions: installerOptions,
+	})
+	app, err := bolt.New(bolt.AppOptions{Token: fakeToken, Receiver: receiver})
+	require.NoError(t, err)
+	require.NoError(t, receiver.Init(app))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = receiver.Start(ctx) }()
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", "127.0.0.1:"+port)
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return "http://127.0.0.1:" + port
+}
+
+// getPage returns the status and body of the page at url
+func getPage(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestOAuthInstallerHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should render the install page with the install URL", func(t *testing.T) {
+		baseURL := startOAuthReceiver(t, &types.InstallerOptions{
+			RenderInstallPage: func(installURL string, req *http.Request) string {
+				return `<a class="acme" href="` + installURL + `">Add Acme to Slack</a>`
+			},
+		})
+
+		status, body := getPage(t, baseURL+"/slack/install")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, `<a class="acme" href="https://slack.com/oauth/v2/authorize?`)
+		assert.Contains(t, body, "client_id=test-client-id")
+	})
+
+	t.Run("should call CallbackFailure when the installation failed", func(t *testing.T) {
+		var failure error
+		baseURL := startOAuthReceiver(t, &types.InstallerOptions{
+			SuccessRedirectURL: "https://acme.example.com/installed",
+			CallbackFailure: func(w http.ResponseWriter, req *http.Request, err error) {
+				failure = err
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte("Acme could not be installed"))
+			},
+		})
+
+		status, body := getPage(t, baseURL+"/slack/oauth_redirect?error=access_denied")
+
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Equal(t, "Acme could not be installed", body)
+		assert.ErrorContains(t, failure, "access_denied")
+	})
+
+	t.Run("should generate states with the configured state store", func(t *testing.T) {
+		baseURL := startOAuthReceiver(t, &types.InstallerOptions{
+			StateStore: staticStateStore("state-from-store"),
+			RenderInstallPage: func(installURL string, req *http.Request) string {
+				return installURL
+			},
+		})
+
+		_, body := getPage(t, baseURL+"/slack/install")
+		assert.Contains(t, body, "state=state-from-store")
+
+		status, _ := getPage(t, baseURL+"/slack/oauth_redirect?code=123&state=forged")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("should reject redirects without state", func(t *testing.T) {
+		baseURL := startOAuthReceiver(t, nil)
+
+		status, body := getPage(t, baseURL+"/slack/oauth_redirect?code=123")
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body, "missing state parameter")
+	})
+
+	t.Run("should escape the error on the default failure page", func(t *testing.T) {
+		baseURL := startOAuthReceiver(t, nil)
+
+		status, body := getPage(t, baseURL+"/slack/oauth_redirect?error=%3Cscript%3E")
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Contains(t, body, "&lt;script&gt;")
+		assert.False(t, strings.Contains(body, "<script>"))
+	})
+}
+
+// TestEnterpriseInstallAuthorize tests authorizing events with the installations of an
+// installation store, including org-wide installs shared by the workspaces of an org
+func TestEnterpriseInstallAuthorize(t *testing.T) {
+	t.Parallel()
+	orgInstallation := &oauth.Installation{
+		Enterprise:          &oauth.Enterprise{ID: "E123"},
+		IsEnterpriseInstall: true,
+		BotToken:            "xoxb-org",
+		BotID:               "B123",
+		BotUserID:           "UBOT",
+		AuthedUser:          &oauth.AuthedUser{ID: "U123", AccessToken: "xoxp-installer", Scope: "chat:write,users:read"},
+	}
+	workspaceInstallation := &oauth.Installation{
+		Team:       &oauth.Team{ID: "T900"},
+		Enterprise: &oauth.Enterprise{ID: "E900"},
+		BotToken:   "xoxb-workspace",
+	}
+	newStore := func(t *testing.T) *oauth.MemoryInstallationStore {
+		store := oauth.NewMemoryInstallationStore()
+		require.NoError(t, store.StoreInstallation(context.Background(), orgInstallation))
+		require.NoError(t, store.StoreInstallation(context.Background(), workspaceInstallation))
+		return store
+	}
+	newApp := func(t *testing.T) *bolt.App {
+		app, err := bolt.New(bolt.AppOptions{
+			SigningSecret:     fakeSigningSecret,
+			InstallationStore: newStore(t),
+		})
+		require.NoError(t, err)
+		return app
+	}
+	eventBody := func(teamID, enterpriseID string, isEnterpriseInstall bool) []byte {
+		body, _ := json.Marshal(map[string]interface{}{
+			"team_id":               teamID,
+			"enterprise_id":         enterpriseID,
+			"is_enterprise_install": isEnterpriseInstall,
+			"api_app_id":            "A123",
+			"type":                  "event_callback",
+			"event": map[string]interface{}{
+				"type":    "app_mention",
+				"user":    "U456",
+				"text":    "<@UBOT> hi",
+				"ts":      "1234567890.123456",
+				"channel": "C123",
+			},
+		})
+		return body
+	}
+	receiverEvent := func(body []byte, contentType string) types.ReceiverEvent {
+		return types.ReceiverEvent{
+			Body:    body,
+			Headers: map[string]string{"Content-Type": contentType},
+			Ack:     func(response types.AckResponse) error { return nil },
+		}
+	}
+
+	t.Run("should authorize events from any workspace of the org with the org-wide installation", func(t *testing.T) {
+		app := newApp(t)
+		var contexts []types.Context
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			contexts = append(contexts, *args.Context)
+			return nil
+		})
+
+		for _, teamID := range []string{"T111", "T222"} {
+			require.NoError(t, app.ProcessEvent(context.Background(), receiverEvent(eventBody(teamID, "E123", true), "application/json")))
+		}
+
+		require.Len(t, contexts, 2)
+		for i, teamID := range []string{"T111", "T222"} {
+			assert.Equal(t, "xoxb-org", contexts[i].BotToken)
+			assert.Equal(t, "UBOT", contexts[i].BotUserID)
+			assert.Equal(t, teamID, contexts[i].TeamID)
+			assert.Equal(t, "E123", contexts[i].EnterpriseID)
+			assert.True(t, contexts[i].IsEnterpriseInstall)
+		}
+	})
+
+	t.Run("should prefer the org-wide installation when the payload does not flag it", func(t *testing.T) {
+		app := newApp(t)
+		var appContext types.Context
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			appContext = *args.Context
+			return nil
+		})
+
+		require.NoError(t, app.ProcessEvent(context.Background(), receiverEvent(eventBody("T333", "E123", false), "application/json")))
+		assert.Equal(t, "xoxb-org", appContext.BotToken)
+		assert.True(t, appContext.IsEnterpriseInstall)
+	})
+
+	t.Run("should fall back to the workspace installation of an org without an org-wide install", func(t *testing.T) {
+		app := newApp(t)
+		var appContext types.Context
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			appContext = *args.Context
+			return nil
+		})
+
+		require.NoError(t, app.ProcessEvent(context.Background(), receiverEvent(eventBody("T900", "E900", false), "application/json")))
+		assert.Equal(t, "xoxb-workspace", appContext.BotToken)
+		assert.Equal(t, "T900", appContext.TeamID)
+		assert.False(t, appContext.IsEnterpriseInstall)
+	})
+
+	t.Run("should authorize commands with the org-wide installation", func(t *testing.T) {
+		app := newApp(t)
+		var appContext types.Context
+		app.Command("/org", func(args bolt.SlackCommandMiddlewareArgs) error {
+			appContext = *args.Context
+			return args.Ack(nil)
+		})
+
+		values := url.Values{}
+		values.Set("command", "/org")
+		values.Set("team_id", "T444")
+		values.Set("enterprise_id", "E123")
+		values.Set("is_enterprise_install", "true")
+		values.Set("user_id", "U123")
+		values.Set("channel_id", "C123")
+		require.NoError(t, app.ProcessEvent(context.Background(), receiverEvent([]byte(values.Encode()), "application/x-www-form-urlencoded")))
+
+		assert.Equal(t, "xoxb-org", appContext.BotToken)
+		assert.Equal(t, "xoxp-installer", appContext.UserToken)
+		assert.Equal(t, []string{"chat:write", "users:read"}, appContext.UserScopes)
+		assert.Equal(t, "E123", appContext.EnterpriseID)
+		assert.True(t, appContext.IsEnterpriseInstall)
+	})
+
+	t.Run("should authorize actions of org-wide installs without a team", func(t *testing.T) {
+		app := newApp(t)
+		var appContext types.Context
+		app.Action(bolt.ActionConstraints{ActionID: "button_1"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			appContext = *args.Context
+			return args.Ack(nil)
+		})
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"type":                  "block_actions",
+			"team":                  nil,
+			"enterprise":            map[string]interface{}{"id": "E123"},
+			"is_enterprise_install": true,
+			"user":                  map[string]interface{}{"id": "U456", "team_id": "T555"},
+			"actions":               []interface{}{map[string]interface{}{"action_id": "button_1", "block_id": "block_1", "type": "button"}},
+			"trigger_id":            "123456.123456.abcdef",
+		})
+		require.NoError(t, app.ProcessEvent(context.Background(), receiverEvent(body, "application/json")))
+
+		assert.Equal(t, "xoxb-org", appContext.BotToken)
+		assert.Empty(t, appContext.UserToken, "U456 did not install the app")
+		assert.Equal(t, "T555", appContext.TeamID)
+		assert.Equal(t, "E123", appContext.EnterpriseID)
+		assert.True(t, appContext.IsEnterpriseInstall)
+	})
+
+	t.Run("should fail authorization for orgs without an installation", func(t *testing.T) {
+		app := newApp(t)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			t.Error("listener should not run")
+			return nil
+		})
+
+		err := app.ProcessEvent(context.Background(), receiverEvent(eventBody("T111", "E404", true), "application/json"))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, oauth.ErrInstallationNotFound))
+	})
+
+	t.Run("should not fall back to a workspace installation for org-wide installs", func(t *testing.T) {
+		app := newApp(t)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+
+		err := app.ProcessEvent(context.Background(), receiverEvent(eventBody("T900", "E900", true), "application/json"))
+		assert.True(t, errors.Is(err, oauth.ErrInstallationNotFound))
+	})
+
+	t.Run("should reject an installation store with a token or authorize function", func(t *testing.T) {
+		_, err := bolt.New(bolt.AppOptions{
+			SigningSecret:     fakeSigningSecret,
+			Token:             fakeToken,
+			InstallationStore: newStore(t),
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	boltotel "github.com/Asafrose/bolt-go/pkg/observability/otel"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// otelTestProviders records spans and metrics in memory
+type otelTestProviders struct {
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+	traces *sdktrace.TracerProvider
+	meters *sdkmetric.MeterProvider
+}
+
+func newOtelTestProviders() *otelTestProviders {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	return &otelTestProviders{
+		spans:  spans,
+		reader: reader,
+		traces: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		meters: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}
+}
+
+// span returns the ended span called name
+func (p *otelTestProviders) span(t *testing.T, name string) sdktrace.ReadOnlySpan {
+	for _, span := range p.spans.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	require.Failf(t, "span not found", "no ended span named %q", name)
+	return nil
+}
+
+// histogram returns the data points of the histogram called name
+func (p *otelTestProviders) histogram(t *testing.T, name string) []metricdata.HistogramDataPoint[float64] {
+	var data metricdata.ResourceMetrics
+	require.NoError(t, p.reader.Collect(context.Background(), &data))
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m.Data.(metricdata.Histogram[float64]).DataPoints
+			}
+		}
+	}
+	require.Failf(t, "metric not found", "no histogram named %q", name)
+	return nil
+}
+
+// spanAttribute returns the value of key on span
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestOpenTelemetryInstrumentation(t *testing.T) {
+	t.Run("should trace and measure a request from receiver to Slack API call", func(t *testing.T) {
+		providers := newOtelTestProviders()
+		instrumentation, err := boltotel.New(boltotel.Options{TracerProvider: providers.traces, MeterProvider: providers.meters})
+		require.NoError(t, err)
+
+		slackAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true,"user_id":"U123456","team_id":"T123456"}`))
+		}))
+		defer slackAPI.Close()
+
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{SigningSecret: fakeSigningSecret})
+		app, err := bolt.New(bolt.AppOptions{
+			Token:           fakeToken,
+			SigningSecret:   fakeSigningSecret,
+			Receiver:        receiver,
+			Instrumentation: instrumentation,
+			ClientOptions:   []slack.Option{slack.OptionAPIURL(slackAPI.URL + "/api/")},
+		})
+		require.NoError(t, err)
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			_, err := args.Client.AuthTest()
+			return err
+		})
+
+		awsEvent := createDummyAWSEvent(string(createAppMentionEventBody()), time.Now().Unix(), fakeSigningSecret)
+		response, err := receiver.ToHandler()(awsEvent, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, 200, response.StatusCode)
+
+		receive := providers.span(t, "slack.receive")
+		process := providers.span(t, "slack.process_event")
+		authorize := providers.span(t, "slack.authorize")
+		apiCall := providers.span(t, "slack.api auth.test")
+
+		assert.Equal(t, trace.SpanKindServer, receive.SpanKind())
+		assert.Equal(t, "*receivers.AwsLambdaReceiver", spanAttribute(receive, boltotel.ReceiverKey).AsString())
+		assert.Equal(t, receive.SpanContext().SpanID(), process.Parent().SpanID())
+		assert.Equal(t, process.SpanContext().SpanID(), authorize.Parent().SpanID())
+		assert.Equal(t, "event", spanAttribute(process, boltotel.RequestTypeKey).AsString())
+		assert.Equal(t, "app_mention", spanAttribute(process, boltotel.EventTypeKey).AsString())
+		assert.True(t, spanAttribute(process, boltotel.HandledKey).AsBool())
+		assert.NotEmpty(t, spanAttribute(process, boltotel.CorrelationIDKey).AsString())
+		assert.Equal(t, codes.Unset, process.Status().Code)
+		assert.Equal(t, trace.SpanKindClient, apiCall.SpanKind())
+		assert.Equal(t, "auth.test", spanAttribute(apiCall, boltotel.APIMethodKey).AsString())
+		assert.Equal(t, int64(200), spanAttribute(apiCall, "http.response.status_code").AsInt64())
+
+		for _, name := range []string{"bolt.event.duration", "bolt.ack.duration", "bolt.authorize.duration", "bolt.http.client.duration"} {
+			points := providers.histogram(t, name)
+			require.Len(t, points, 1, name)
+			assert.Equal(t, uint64(1), points[0].Count, name)
+		}
+	})
+
+	t.Run("should record authorization failures as span errors", func(t *testing.T) {
+		providers := newOtelTestProviders()
+		instrumentation, err := boltotel.New(boltotel.Options{TracerProvider: providers.traces, MeterProvider: providers.meters})
+		require.NoError(t, err)
+
+		app, err := bolt.New(bolt.AppOptions{
+			SigningSecret:   fakeSigningSecret,
+			Instrumentation: instrumentation,
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				return nil, errors.New("unknown team")
+			},
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    createAppMentionEventBody(),
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		})
+		require.Error(t, err)
+
+		authorize := providers.span(t, "slack.authorize")
+		process := providers.span(t, "slack.process_event")
+		assert.Equal(t, codes.Error, authorize.Status().Code)
+		assert.Equal(t, codes.Error, process.Status().Code)
+		assert.Equal(t, string(bolt.AuthorizationErrorCode), spanAttribute(process, "error.type").AsString())
+	})
+
+	t.Run("should not record telemetry when OTEL_SDK_DISABLED is true", func(t *testing.T) {
+		t.Setenv("OTEL_SDK_DISABLED", "true")
+		providers := newOtelTestProviders()
+		instrumentation, err := boltotel.New(boltotel.Options{TracerProvider: providers.traces, MeterProvider: providers.meters})
+		require.NoError(t, err)
+
+		app, err := bolt.New(bolt.AppOptions{
+			Token:           fakeToken,
+			SigningSecret:   fakeSigningSecret,
+			Instrumentation: instrumentation,
+		})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+
+		require.NoError(t, app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body:    createAppMentionEventBody(),
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		}))
+		assert.Empty(t, providers.spans.Ended())
+	})
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	boltprometheus "github.com/Asafrose/bolt-go/pkg/observability/prometheus"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeMetrics returns the metrics served by route
+func scrapeMetrics(t *testing.T, route types.CustomRoute) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	route.Handler(recorder, httptest.NewRequest(http.MethodGet, route.Path, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
+
+func TestPrometheusInstrumentation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should measure requests, listeners and Slack API calls", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		metrics, err := boltprometheus.New(boltprometheus.Options{Registerer: registry, Gatherer: registry})
+		require.NoError(t, err)
+
+		slackAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(r.URL.Path, "/chat.postMessage") {
+				_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":true,"user_id":"U123456","team_id":"T123456"}`))
+		}))
+		defer slackAPI.Close()
+
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{SigningSecret: fakeSigningSecret})
+		app, err := bolt.New(bolt.AppOptions{
+			Token:           fakeToken,
+			SigningSecret:   fakeSigningSecret,
+			Receiver:        receiver,
+			Instrumentation: metrics,
+			ClientOptions:   []slack.Option{slack.OptionAPIURL(slackAPI.URL + "/api/")},
+		})
+		require.NoError(t, err)
+		metrics.ObserveListeners(app)
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			if _, err := args.Client.AuthTest(); err != nil {
+				return err
+			}
+			// The failed call is still counted, but the listener carries on
+			_, _, err := args.Client.PostMessage("C123456", slack.MsgOptionText("hi", false))
+			assert.EqualError(t, err, "channel_not_found")
+			return nil
+		})
+
+		awsEvent := createDummyAWSEvent(string(createAppMentionEventBody()), time.Now().Unix(), fakeSigningSecret)
+		response, err := receiver.ToHandler()(awsEvent, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, 200, response.StatusCode)
+
+		scraped := scrapeMetrics(t, metrics.Route("/metrics"))
+		for _, line := range []string{
+			`bolt_requests_total{receiver="*receivers.AwsLambdaReceiver"} 1`,
+			`bolt_ack_duration_seconds_count{receiver="*receivers.AwsLambdaReceiver"} 1`,
+			`bolt_events_total{error="",event_type="app_mention",handled="true",type="event"} 1`,
+			`bolt_event_duration_seconds_count{event_type="app_mention",type="event"} 1`,
+			`bolt_authorize_duration_seconds_count{error=""} 1`,
+			`bolt_listener_duration_seconds_count{error="",listener="event(type=app_mention)"} 1`,
+			`bolt_slack_api_calls_total{method="auth.test"} 1`,
+			`bolt_slack_api_calls_total{method="chat.postMessage"} 1`,
+			`bolt_slack_api_errors_total{error="channel_not_found",method="chat.postMessage"} 1`,
+		} {
+			assert.Contains(t, scraped, line)
+		}
+		assert.NotContains(t, scraped, `bolt_slack_api_errors_total{error="channel_not_found",method="auth.test"}`)
+	})
+
+	t.Run("should record Socket Mode connection churn and keep existing hooks", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		metrics, err := boltprometheus.New(boltprometheus.Options{Namespace: "slackbot", Registerer: registry, Gatherer: registry})
+		require.NoError(t, err)
+
+		var reasons []string
+		options := types.SocketModeReceiverOptions{OnDisconnect: func(reason string) { reasons = append(reasons, reason) }}
+		metrics.InstrumentSocketMode(&options)
+
+		options.OnConnected(types.SocketModeHello{})
+		options.OnDisconnect("refresh_requested")
+		options.OnReconnect()
+		options.OnConnected(types.SocketModeHello{})
+
+		assert.Equal(t, []string{"refresh_requested"}, reasons)
+		scraped := scrapeMetrics(t, metrics.Route("/metrics"))
+		assert.Contains(t, scraped, "slackbot_socket_mode_connected 1")
+		assert.Contains(t, scraped, "slackbot_socket_mode_reconnects_total 1")
+		assert.Contains(t, scraped, `slackbot_socket_mode_disconnects_total{reason="refresh_requested"} 1`)
+	})
+
+	t.Run("should export the concurrency queue of an app", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		metrics, err := boltprometheus.New(boltprometheus.Options{Registerer: registry, Gatherer: registry})
+		require.NoError(t, err)
+		app, err := bolt.New(bolt.AppOptions{
+			Token:            fakeToken,
+			SigningSecret:    fakeSigningSecret,
+			MaxConcurrency:   4,
+			ConcurrencyQueue: bolt.ConcurrencyQueueOptions{OverflowPolicy: bolt.OverflowPolicyDrop},
+		})
+		require.NoError(t, err)
+		require.NoError(t, metrics.ObserveConcurrency(app))
+
+		scraped := scrapeMetrics(t, metrics.Route("/metrics"))
+		assert.Contains(t, scraped, "bolt_events_in_flight 0")
+		assert.Contains(t, scraped, "bolt_events_queued 0")
+		assert.Contains(t, scraped, "bolt_events_dropped_total 0")
+		assert.Contains(t, scraped, "bolt_events_rejected_total 0")
+	})
+
+	t.Run("should fail when the metrics are already registered", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		_, err := boltprometheus.New(boltprometheus.Options{Registerer: registry})
+		require.NoError(t, err)
+
+		_, err = boltprometheus.New(boltprometheus.Options{Registerer: registry})
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		assert.True(t, errors.As(err, &alreadyRegistered))
+	})
+}
@@ -0,0 +1,208 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/options"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func optionTexts(opts []types.Option) []string {
+	texts := make([]string, len(opts))
+	for i, option := range opts {
+		texts[i] = option.Text.Text
+	}
+	return texts
+}
+
+func TestOptionsBuilders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should escape and truncate option texts", func(t *testing.T) {
+		option := options.Option("R&D <team>", "rnd")
+		assert.Equal(t, "plain_text", option.Text.Type)
+		assert.Equal(t, "R&amp;D &lt;team&gt;", option.Text.Text)
+
+		long := options.OptionWithDescription(strings.Repeat("a", 100), strings.Repeat("v", 200), strings.Repeat("d", 80))
+		assert.Equal(t, strings.Repeat("a", 74)+"…", long.Text.Text)
+		assert.Equal(t, strings.Repeat("v", options.MaxValueLength), long.Value)
+		assert.Equal(t, strings.Repeat("d", 74)+"…", long.Description.Text)
+	})
+
+	t.Run("should truncate responses to Slack limits", func(t *testing.T) {
+		var many []types.Option
+		for range 150 {
+			many = append(many, options.Option("option", "value"))
+		}
+
+		assert.Len(t, options.Response(many...).Options, options.MaxOptions)
+		assert.Len(t, options.Group("Many", many...).Options, options.MaxOptions)
+
+		var groups []types.OptionGroup
+		for range 120 {
+			groups = append(groups, options.Group("Group", many[:2]...))
+		}
+		assert.Len(t, options.GroupedResponse(groups...).OptionGroups, options.MaxOptionGroups)
+	})
+
+	t.Run("should drop empty groups", func(t *testing.T) {
+		response := options.GroupedResponse(options.Group("Empty"), options.Group("Fruit", options.Option("Apple", "apple")))
+		require.Len(t, response.OptionGroups, 1)
+		assert.Equal(t, "Fruit", response.OptionGroups[0].Label.Text)
+	})
+
+	t.Run("should filter options by the typed query, best matches first", func(t *testing.T) {
+		fruits := []types.Option{
+			options.Option("Pineapple", "pineapple"),
+			options.Option("Apple", "apple"),
+			options.Option("Green apple", "green_apple"),
+			options.Option("Papaya", "papaya"),
+			options.Option("Banana", "banana"),
+		}
+
+		assert.Equal(t, []string{"Apple", "Green apple", "Pineapple", "Papaya"}, optionTexts(options.Filter("ap", fruits)))
+		assert.Equal(t, []string{"Pineapple", "Apple", "Green apple"}, optionTexts(options.Filter("APL", fruits)))
+		assert.Len(t, options.Filter("  ", fruits), len(fruits))
+		assert.Empty(t, options.Filter("kiwi", fruits))
+	})
+
+	t.Run("should filter groups and drop those left empty", func(t *testing.T) {
+		groups := []types.OptionGroup{
+			options.Group("Fruit & veg", options.Option("Apple", "apple"), options.Option("Carrot", "carrot")),
+			options.Group("Dairy", options.Option("Milk", "milk")),
+		}
+
+		filtered := options.FilterGroups("car", groups)
+		require.Len(t, filtered, 1)
+		assert.Equal(t, "Fruit &amp; veg", filtered[0].Label.Text)
+		require.Len(t, filtered[0].Options, 1)
+		assert.Equal(t, "carrot", filtered[0].Options[0].Value)
+	})
+
+	t.Run("should answer Options listeners with the typed query filtered", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Options(types.OptionsConstraints{ActionID: "fruit"}, func(args bolt.SlackOptionsMiddlewareArgs) error {
+			response := options.Response(options.Filter(args.Options.Value, []types.Option{
+				options.Option("Apple", "apple"),
+				options.Option("Banana", "banana"),
+			})...)
+			return args.Ack(&response)
+		})
+
+		result := h.Send(bolttest.BlockSuggestion("fruit", "ban")).AssertNoError(t).AssertAcked(t)
+
+		assert.Equal(t, []any{map[string]any{
+			"text":  map[string]any{"type": "plain_text", "text": "Banana", "emoji": false},
+			"value": "banana",
+		}}, result.AckJSON(t)["options"])
+	})
+}
+
+// suggestionAfter builds an options request for the select "fruit" of a modal in which the
+// option with value is selected
+func suggestionAfter(t *testing.T, query, value string) bolttest.Payload {
+	t.Helper()
+	payload := bolttest.BlockSuggestion("fruit", query)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(payload.Body, &body))
+	body["view"] = map[string]any{
+		"id":   "V0000TEST",
+		"type": "modal",
+		"state": map[string]any{"values": map[string]any{
+			"bolttest_block": map[string]any{"fruit": map[string]any{
+				"type":            "external_select",
+				"selected_option": map[string]any{"value": value},
+			}},
+		}},
+	}
+	payload.Body, _ = json.Marshal(body)
+	return payload
+}
+
+func TestOptionsPager(t *testing.T) {
+	t.Parallel()
+
+	// items fetches numbered items containing query, with offsets as cursors
+	items := func(ctx context.Context, query, cursor string, limit int) ([]types.Option, string, error) {
+		var matching []types.Option
+		for i := range 120 {
+			if text := fmt.Sprintf("Item %d", i); strings.Contains(text, query) {
+				matching = append(matching, options.Option(text, strconv.Itoa(i)))
+			}
+		}
+		offset, _ := strconv.Atoi(cursor)
+		end := min(offset+limit, len(matching))
+		if end == len(matching) {
+			return matching[offset:end], "", nil
+		}
+		return matching[offset:end], strconv.Itoa(end), nil
+	}
+
+	pagedApp := func(t *testing.T) *bolttest.Harness {
+		h := bolttest.New(t, bolt.AppOptions{})
+		pager := options.Pager{Fetch: items}
+		h.App.Options(types.OptionsConstraints{ActionID: "fruit"}, func(args bolt.SlackOptionsMiddlewareArgs) error {
+			return pager.Respond(args)
+		})
+		return h
+	}
+
+	values := func(t *testing.T, result *bolttest.Result) []string {
+		var values []string
+		for _, option := range result.AckJSON(t)["options"].([]any) {
+			values = append(values, option.(map[string]any)["value"].(string))
+		}
+		return values
+	}
+
+	t.Run("should serve pages, following the selected load more option", func(t *testing.T) {
+		h := pagedApp(t)
+
+		first := values(t, h.Send(bolttest.BlockSuggestion("fruit", "")).AssertNoError(t))
+		require.Len(t, first, options.DefaultPageSize+1)
+		assert.Equal(t, "0", first[0])
+		loadMore := first[options.DefaultPageSize]
+		assert.True(t, options.IsLoadMore(loadMore))
+		assert.False(t, options.IsLoadMore(first[0]))
+
+		second := values(t, h.Send(suggestionAfter(t, "", loadMore)).AssertNoError(t))
+		require.Len(t, second, options.DefaultPageSize+1)
+		assert.Equal(t, "50", second[0])
+
+		last := values(t, h.Send(suggestionAfter(t, "", second[options.DefaultPageSize])).AssertNoError(t))
+		assert.Len(t, last, 20)
+		assert.Equal(t, "119", last[19])
+	})
+
+	t.Run("should start over when the query changes", func(t *testing.T) {
+		h := pagedApp(t)
+
+		first := values(t, h.Send(bolttest.BlockSuggestion("fruit", "")).AssertNoError(t))
+		filtered := values(t, h.Send(suggestionAfter(t, "Item 1", first[len(first)-1])).AssertNoError(t))
+
+		assert.Equal(t, "1", filtered[0])
+		assert.Len(t, filtered, 31) // Item 1, Item 10-19 and Item 100-119
+	})
+
+	t.Run("should acknowledge with no options when fetching fails", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		pager := options.Pager{Fetch: func(ctx context.Context, query, cursor string, limit int) ([]types.Option, string, error) {
+			return nil, "", errors.New("search is down")
+		}}
+		h.App.Options(types.OptionsConstraints{ActionID: "fruit"}, func(args bolt.SlackOptionsMiddlewareArgs) error {
+			return pager.Respond(args)
+		})
+
+		h.Send(bolttest.BlockSuggestion("fruit", "")).AssertError(t).AssertAcked(t)
+	})
+}
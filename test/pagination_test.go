@@ -0,0 +1,162 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/pagination"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cursorPages answers a paginated method with pages, keyed by the cursor requesting them ("" for
+// the first page); each page lists its items and the cursor of the next page
+func cursorPages(itemsKey string, pages map[string][]any, next map[string]string) func(bolttest.Call) any {
+	return func(call bolttest.Call) any {
+		cursor, _ := call.Params["cursor"].(string)
+		response := map[string]any{
+			"ok":                true,
+			itemsKey:            pages[cursor],
+			"has_more":          next[cursor] != "",
+			"response_metadata": map[string]any{"next_cursor": next[cursor]},
+		}
+		if itemsKey == "members" {
+			response["cache_ts"] = 0
+		}
+		return response
+	}
+}
+
+func TestPaginationIterators(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should iterate over conversation history from listener args", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.Slack.HandleFunc("conversations.history", cursorPages("messages", map[string][]any{
+			"":   {map[string]any{"text": "one"}, map[string]any{"text": "two"}},
+			"c2": {map[string]any{"text": "three"}},
+		}, map[string]string{"": "c2"}))
+
+		var texts []string
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			for page, err := range args.Pages().ConversationsHistoryIter(ctx, slack.GetConversationHistoryParameters{ChannelID: "C123"}) {
+				if err != nil {
+					return err
+				}
+				for _, message := range page.Messages {
+					texts = append(texts, message.Text)
+				}
+			}
+			return nil
+		})
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		assert.Equal(t, []string{"one", "two", "three"}, texts)
+		calls := h.Slack.Calls("conversations.history")
+		require.Len(t, calls, 2)
+		assert.Equal(t, "C123", calls[0].Params["channel"])
+		assert.Equal(t, "c2", calls[1].Params["cursor"])
+	})
+
+	t.Run("should iterate over conversations and users", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		fake.HandleFunc("conversations.list", cursorPages("channels", map[string][]any{
+			"":   {map[string]any{"id": "C1"}},
+			"c2": {map[string]any{"id": "C2"}, map[string]any{"id": "C3"}},
+		}, map[string]string{"": "c2"}))
+		fake.HandleFunc("users.list", cursorPages("members", map[string][]any{
+			"":   {map[string]any{"id": "U1"}},
+			"u2": {map[string]any{"id": "U2"}},
+		}, map[string]string{"": "u2"}))
+		pages := pagination.New(fake.APIClient())
+
+		var channels []string
+		for page, err := range pages.ConversationsListIter(ctx, slack.GetConversationsParameters{Limit: 2}) {
+			require.NoError(t, err)
+			for _, channel := range page {
+				channels = append(channels, channel.ID)
+			}
+		}
+		assert.Equal(t, []string{"C1", "C2", "C3"}, channels)
+
+		var users []string
+		for page, err := range pages.UsersListIter(ctx) {
+			require.NoError(t, err)
+			for _, user := range page {
+				users = append(users, user.ID)
+			}
+		}
+		assert.Equal(t, []string{"U1", "U2"}, users)
+		assert.Len(t, fake.Calls("users.list"), 2)
+	})
+
+	t.Run("should stop requesting pages when the loop breaks", func(t *testing.T) {
+		fake := bolttest.NewSlack(t)
+		fake.HandleFunc("conversations.list", cursorPages("channels", map[string][]any{
+			"":   {map[string]any{"id": "C1"}},
+			"c2": {map[string]any{"id": "C2"}},
+		}, map[string]string{"": "c2"}))
+
+		for range pagination.New(fake.APIClient()).ConversationsListIter(ctx, slack.GetConversationsParameters{}) {
+			break
+		}
+		assert.Len(t, fake.Calls("conversations.list"), 1)
+	})
+
+	t.Run("should wait out rate limits and yield other errors once", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch requests.Add(1) {
+			case 1:
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			case 2:
+				_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "channels": []any{map[string]any{"id": "C1"}}, "response_metadata": map[string]any{"next_cursor": "c2"}})
+			default:
+				_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_cursor"})
+			}
+		}))
+		defer server.Close()
+		client := slack.New(fakeToken, slack.OptionAPIURL(server.URL+"/"))
+
+		var pages int
+		var errs []error
+		for page, err := range pagination.New(client).ConversationsListIter(ctx, slack.GetConversationsParameters{}) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			pages++
+			assert.Equal(t, "C1", page[0].ID)
+		}
+		assert.Equal(t, 1, pages)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "invalid_cursor")
+		assert.EqualValues(t, 3, requests.Load())
+	})
+
+	t.Run("should return rate limit errors when retries are disabled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		client := slack.New(fakeToken, slack.OptionAPIURL(server.URL+"/"))
+
+		started := time.Now()
+		for _, err := range pagination.New(client, pagination.Options{MaxRetries: -1}).UsersListIter(ctx) {
+			var rateLimited *slack.RateLimitedError
+			assert.ErrorAs(t, err, &rateLimited)
+		}
+		assert.Less(t, time.Since(started), 5*time.Second)
+	})
+}
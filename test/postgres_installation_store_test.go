@@ -0,0 +1,186 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Asafrose/bolt-go/pkg/installstore"
+	"github.com/Asafrose/bolt-go/pkg/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePostgres is a database/sql driver running the statements of the Postgres installation store
+// over a map keyed by enterprise, team and user IDs
+type fakePostgres struct {
+	mu       sync.Mutex
+	migrated bool
+	rows     map[[3]string]string
+}
+
+func newFakePostgres() *fakePostgres {
+	return &fakePostgres{rows: map[[3]string]string{}}
+}
+
+func (p *fakePostgres) Connect(ctx context.Context) (driver.Conn, error) {
+	return fakePostgresConn{p}, nil
+}
+func (p *fakePostgres) Driver() driver.Driver { return nil }
+
+type fakePostgresConn struct{ db *fakePostgres }
+
+func (c fakePostgresConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (c fakePostgresConn) Close() error              { return nil }
+func (c fakePostgresConn) Begin() (driver.Tx, error) { return c, nil }
+func (c fakePostgresConn) Commit() error             { return nil }
+func (c fakePostgresConn) Rollback() error           { return nil }
+
+func (c fakePostgresConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE"):
+		c.db.migrated = true
+	case strings.HasPrefix(query, "INSERT"):
+		c.db.rows[fakePostgresKey(args)] = args[4].Value.(string)
+	case strings.HasPrefix(query, "DELETE") && strings.Contains(query, "user_id"):
+		delete(c.db.rows, fakePostgresKey(args))
+	case strings.HasPrefix(query, "DELETE"):
+		for key := range c.db.rows {
+			if key[0] == args[0].Value && key[1] == args[1].Value {
+				delete(c.db.rows, key)
+			}
+		}
+	default:
+		return nil, errors.New("unexpected statement: " + query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c fakePostgresConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	rows := &fakePostgresRows{}
+	if data, ok := c.db.rows[fakePostgresKey(args)]; ok {
+		rows.values = append(rows.values, []byte(data))
+	}
+	return rows, nil
+}
+
+func fakePostgresKey(args []driver.NamedValue) [3]string {
+	return [3]string{args[0].Value.(string), args[1].Value.(string), args[2].Value.(string)}
+}
+
+type fakePostgresRows struct{ values [][]byte }
+
+func (r *fakePostgresRows) Columns() []string { return []string{"installation"} }
+func (r *fakePostgresRows) Close() error      { return nil }
+func (r *fakePostgresRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func TestPostgresInstallationStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	newStore := func(t *testing.T) (*installstore.PostgresInstallationStore, *fakePostgres) {
+		fake := newFakePostgres()
+		db := sql.OpenDB(fake)
+		t.Cleanup(func() { _ = db.Close() })
+		store := installstore.NewPostgresInstallationStore(db)
+		require.NoError(t, store.Migrate(ctx))
+		return store, fake
+	}
+
+	gridInstall := func(enterpriseID, teamID, userID, botToken string) *oauth.Installation {
+		return &oauth.Installation{
+			Enterprise: &oauth.Enterprise{ID: enterpriseID},
+			Team:       &oauth.Team{ID: teamID},
+			AuthedUser: &oauth.AuthedUser{ID: userID, AccessToken: "xoxp-" + userID, Scope: "chat:write"},
+			BotToken:   botToken,
+			BotScopes:  []string{"commands"},
+			IncomingWebhook: &oauth.IncomingWebhook{
+				ChannelID: "C123",
+				URL:       "https://hooks.slack.com/services/T/B/x",
+			},
+		}
+	}
+
+	t.Run("should create its table", func(t *testing.T) {
+		_, fake := newStore(t)
+		assert.True(t, fake.migrated)
+	})
+
+	t.Run("should store the latest installation of workspaces and users", func(t *testing.T) {
+		store, _ := newStore(t)
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("E1", "T1", "U1", "xoxb-first")))
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("E1", "T1", "U2", "xoxb-second")))
+
+		installation, err := store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E1", TeamID: "T1"})
+		require.NoError(t, err)
+		assert.Equal(t, gridInstall("E1", "T1", "U2", "xoxb-second"), installation)
+
+		installation, err = store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E1", TeamID: "T1", UserID: "U1"})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-first", installation.BotToken)
+		assert.Equal(t, "xoxp-U1", installation.AuthedUser.AccessToken)
+	})
+
+	t.Run("should tell workspaces apart by enterprise and team", func(t *testing.T) {
+		store, _ := newStore(t)
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("E1", "T1", "U1", "xoxb-e1")))
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("E2", "T1", "U1", "xoxb-e2")))
+
+		installation, err := store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E2", TeamID: "T1"})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-e2", installation.BotToken)
+
+		_, err = store.FetchInstallation(ctx, oauth.InstallationQuery{TeamID: "T1"})
+		assert.ErrorIs(t, err, oauth.ErrInstallationNotFound)
+	})
+
+	t.Run("should store org-wide installations by enterprise", func(t *testing.T) {
+		store, _ := newStore(t)
+		install := gridInstall("E1", "T1", "U1", "xoxb-org")
+		install.IsEnterpriseInstall = true
+		require.NoError(t, store.StoreInstallation(ctx, install))
+
+		installation, err := store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E1", TeamID: "T9", IsEnterpriseInstall: true})
+		require.NoError(t, err)
+		assert.Equal(t, "xoxb-org", installation.BotToken)
+	})
+
+	t.Run("should delete users or whole workspaces", func(t *testing.T) {
+		store, _ := newStore(t)
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("E1", "T1", "U1", "xoxb-first")))
+		require.NoError(t, store.StoreInstallation(ctx, gridInstall("E1", "T1", "U2", "xoxb-second")))
+
+		require.NoError(t, store.DeleteInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E1", TeamID: "T1", UserID: "U1"}))
+		_, err := store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E1", TeamID: "T1", UserID: "U1"})
+		assert.ErrorIs(t, err, oauth.ErrInstallationNotFound)
+		_, err = store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E1", TeamID: "T1"})
+		assert.NoError(t, err)
+
+		require.NoError(t, store.DeleteInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E1", TeamID: "T1"}))
+		_, err = store.FetchInstallation(ctx, oauth.InstallationQuery{EnterpriseID: "E1", TeamID: "T1", UserID: "U2"})
+		assert.ErrorIs(t, err, oauth.ErrInstallationNotFound)
+	})
+
+	t.Run("should reject installations without a team or enterprise", func(t *testing.T) {
+		store, _ := newStore(t)
+		assert.Error(t, store.StoreInstallation(ctx, nil))
+		assert.Error(t, store.StoreInstallation(ctx, &oauth.Installation{BotToken: "xoxb"}))
+	})
+}
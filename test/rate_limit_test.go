@@ -0,0 +1,161 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rateLimitedSlack is a Slack API server answering the first calls of each method with HTTP 429
+type rateLimitedSlack struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	limited    map[string]int
+	retryAfter string
+	calls      map[string][]time.Time
+}
+
+func newRateLimitedSlack(t *testing.T, retryAfter string, limited map[string]int) *rateLimitedSlack {
+	t.Helper()
+	s := &rateLimitedSlack{limited: limited, retryAfter: retryAfter, calls: make(map[string][]time.Time)}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := strings.TrimPrefix(r.URL.Path, "/api/")
+		s.mu.Lock()
+		s.calls[method] = append(s.calls[method], time.Now())
+		limit := s.limited[method] > 0
+		if limit {
+			s.limited[method]--
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if limit {
+			w.Header().Set("Retry-After", s.retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"ok":false,"error":"ratelimited"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1.2","messages":[]}`))
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *rateLimitedSlack) Calls(method string) []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Time(nil), s.calls[method]...)
+}
+
+// newRateLimitedApp creates an app whose Slack API calls go to slack
+func newRateLimitedApp(t *testing.T, slackServer *rateLimitedSlack, options bolt.RateLimitOptions) (*bolt.App, *bolttest.Receiver) {
+	t.Helper()
+	receiver := &bolttest.Receiver{}
+	app, err := bolt.New(bolt.AppOptions{
+		Token:         bolttest.BotToken,
+		BotID:         bolttest.BotID,
+		BotUserID:     bolttest.BotUserID,
+		SigningSecret: bolttest.SigningSecret,
+		Receiver:      receiver,
+		RateLimit:     options,
+		ClientOptions: []slack.Option{slack.OptionAPIURL(slackServer.server.URL + "/api/")},
+	})
+	require.NoError(t, err)
+	return app, receiver
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+	t.Run("should retry calls of listeners' clients rate limited by Slack", func(t *testing.T) {
+		slackServer := newRateLimitedSlack(t, "0", map[string]int{"chat.postMessage": 2})
+		app, receiver := newRateLimitedApp(t, slackServer, bolt.RateLimitOptions{Enabled: true})
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, _, err := args.Client.PostMessage("C123", slack.MsgOptionText("hi", false))
+			return err
+		})
+
+		receiver.Send(context.Background(), bolttest.AppMention("hello")).AssertNoError(t)
+		assert.Len(t, slackServer.Calls("chat.postMessage"), 3)
+	})
+
+	t.Run("should hold calls back for the Retry-After of the method", func(t *testing.T) {
+		slackServer := newRateLimitedSlack(t, "1", map[string]int{"conversations.history": 1})
+		app, _ := newRateLimitedApp(t, slackServer, bolt.RateLimitOptions{Enabled: true})
+
+		_, err := app.Client.GetConversationHistory(&slack.GetConversationHistoryParameters{ChannelID: "C123"})
+		require.NoError(t, err)
+
+		calls := slackServer.Calls("conversations.history")
+		require.Len(t, calls, 2)
+		assert.GreaterOrEqual(t, calls[1].Sub(calls[0]), 900*time.Millisecond)
+	})
+
+	t.Run("should fail calls once retries are spent or Retry-After exceeds the maximum wait", func(t *testing.T) {
+		slackServer := newRateLimitedSlack(t, "0", map[string]int{"chat.update": 10})
+		app, _ := newRateLimitedApp(t, slackServer, bolt.RateLimitOptions{Enabled: true, MaxRetries: 2})
+
+		_, _, _, err := app.Client.UpdateMessage("C123", "1.2", slack.MsgOptionText("hi", false))
+		var rateLimited *slack.RateLimitedError
+		require.True(t, errors.As(err, &rateLimited), "got %v", err)
+		assert.Len(t, slackServer.Calls("chat.update"), 3)
+
+		slackServer = newRateLimitedSlack(t, "120", map[string]int{"chat.update": 10})
+		app, _ = newRateLimitedApp(t, slackServer, bolt.RateLimitOptions{Enabled: true})
+		_, _, _, err = app.Client.UpdateMessage("C123", "1.2", slack.MsgOptionText("hi", false))
+		require.True(t, errors.As(err, &rateLimited), "got %v", err)
+		assert.Equal(t, 120*time.Second, rateLimited.RetryAfter)
+		assert.Len(t, slackServer.Calls("chat.update"), 1)
+
+		budget := app.RateLimitBudget(bolttest.BotToken, "chat.update")
+		assert.Equal(t, 0, budget.Remaining)
+		assert.WithinDuration(t, time.Now().Add(120*time.Second), budget.RetryAfter, 5*time.Second)
+	})
+
+	t.Run("should report the remaining budget of each method tier", func(t *testing.T) {
+		slackServer := newRateLimitedSlack(t, "0", nil)
+		app, _ := newRateLimitedApp(t, slackServer, bolt.RateLimitOptions{
+			Enabled:     true,
+			MaxWait:     10 * time.Millisecond,
+			TierLimits:  map[int]int{2: 2},
+			MethodTiers: map[string]int{"conversations.history": 2},
+		})
+
+		budget := app.RateLimitBudget(bolttest.BotToken, "conversations.history")
+		assert.Equal(t, bolt.RateLimitBudget{Method: "conversations.history", Tier: 2, Limit: 2, Remaining: 2}, budget)
+
+		for range 3 {
+			_, err := app.Client.GetConversationHistory(&slack.GetConversationHistoryParameters{ChannelID: "C123"})
+			require.NoError(t, err)
+		}
+		// The third call would have waited a minute, longer than MaxWait, so it was sent right away
+		assert.Len(t, slackServer.Calls("conversations.history"), 3)
+
+		budget = app.RateLimitBudget(bolttest.BotToken, "conversations.history")
+		assert.Equal(t, 0, budget.Remaining)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), budget.ResetAt, 5*time.Second)
+		assert.Equal(t, 2, app.RateLimitBudget("xoxb-other", "conversations.history").Remaining)
+		assert.Equal(t, 4, app.RateLimitBudget(bolttest.BotToken, "users.info").Tier)
+	})
+
+	t.Run("should leave rate limited calls to the caller when disabled", func(t *testing.T) {
+		slackServer := newRateLimitedSlack(t, "0", map[string]int{"chat.postMessage": 1})
+		app, _ := newRateLimitedApp(t, slackServer, bolt.RateLimitOptions{})
+
+		_, _, err := app.Client.PostMessage("C123", slack.MsgOptionText("hi", false))
+		var rateLimited *slack.RateLimitedError
+		assert.True(t, errors.As(err, &rateLimited), "got %v", err)
+		assert.Equal(t, bolt.RateLimitBudget{Method: "chat.postMessage"}, app.RateLimitBudget(bolttest.BotToken, "chat.postMessage"))
+	})
+}
@@ -2,6 +2,9 @@ package test
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"strconv"
 	"testing"
 	"time"
 
@@ -330,3 +333,100 @@ func TestReceiverErrorHandling(t *testing.T) {
 		assert.Contains(t, err.Error(), "context", "Error should mention context cancellation")
 	})
 }
+
+// nextHandled waits for the next listener to report to handled
+func nextHandled(t *testing.T, handled <-chan string) string {
+	t.Helper()
+	select {
+	case name := <-handled:
+		return name
+	case <-time.After(5 * time.Second):
+		t.Fatal("no listener handled the request")
+		return ""
+	}
+}
+
+func TestHTTPReceiverEndpoints(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should default the endpoints left empty to the events path", func(t *testing.T) {
+		receiver := receivers.NewHTTPReceiver(types.HTTPReceiverOptions{
+			SigningSecret: fakeSigningSecret,
+			Endpoints:     &types.ReceiverEndpoints{Events: "/slack/events", Commands: "/slack/commands"},
+		})
+
+		assert.Equal(t, types.ReceiverEndpoints{
+			Events:      "/slack/events",
+			Interactive: "/slack/events",
+			Commands:    "/slack/commands",
+			Options:     "/slack/events",
+		}, receiver.Endpoints())
+		assert.Equal(t, []string{"/slack/events", "/slack/commands"}, receiver.Endpoints().Paths())
+	})
+
+	t.Run("should serve each payload type on its path with the path's middleware", func(t *testing.T) {
+		_, port, err := net.SplitHostPort(freeAddr(t))
+		require.NoError(t, err)
+		portNum, err := strconv.Atoi(port)
+		require.NoError(t, err)
+
+		throttled := make(chan string, 2)
+		throttle := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				throttled <- r.URL.Path
+				next.ServeHTTP(w, r)
+			})
+		}
+		receiver := receivers.NewHTTPReceiver(types.HTTPReceiverOptions{
+			SigningSecret: fakeSigningSecret,
+			Port:          portNum,
+			Endpoints: &types.ReceiverEndpoints{
+				Events:      "/slack/events",
+				Interactive: "/slack/interactive",
+				Commands:    "/slack/commands",
+			},
+			EndpointMiddleware: map[string][]func(http.Handler) http.Handler{
+				"/slack/commands": {throttle, receivers.SignatureMiddleware(fakeSigningSecret)},
+			},
+		})
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, Receiver: receiver})
+		require.NoError(t, err)
+		handled := make(chan string, 2)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			handled <- "app_mention"
+			return nil
+		})
+		app.Command("/deploy", func(args bolt.SlackCommandMiddlewareArgs) error {
+			handled <- args.Command.Command
+			return args.Ack(nil)
+		})
+
+		require.NoError(t, receiver.Init(app))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = receiver.Start(ctx) }()
+		require.Eventually(t, func() bool {
+			conn, err := net.Dial("tcp", "127.0.0.1:"+port)
+			if err == nil {
+				_ = conn.Close()
+			}
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		baseURL := "http://127.0.0.1:" + port
+
+		resp := postSigned(t, baseURL+"/slack/events", fakeSigningSecret, mirroredEventBody)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "app_mention", nextHandled(t, handled))
+
+		command := `{"command":"/deploy","text":"now","team_id":"T123","user_id":"U123","channel_id":"C123","is_enterprise_install":"false"}`
+		resp = postSigned(t, baseURL+"/slack/commands", fakeSigningSecret, command)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "/deploy", nextHandled(t, handled))
+		assert.Equal(t, "/slack/commands", <-throttled)
+		assert.Empty(t, throttled)
+
+		resp = postSigned(t, baseURL+"/slack/options", fakeSigningSecret, command)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
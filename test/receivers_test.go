@@ -1,9 +1,13 @@
 package test
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -100,6 +104,48 @@ func TestHTTPReceiver(t *testing.T) {
 			assert.NotNil(t, receiver)
 		})
 	})
+
+	t.Run("error status codes", func(t *testing.T) {
+		authenticityErr := bolt.NewReceiverAuthenticityError("Invalid signature")
+		authorizationErr := fmt.Errorf("processing failed: %w", bolt.NewAuthorizationError("Failed to authorize", errors.New("no installation")))
+		listenerErr := bolt.NewMultipleListenerError([]error{errors.New("boom")})
+
+		t.Run("should use default status codes", func(t *testing.T) {
+			codes := bolt.HTTPErrorStatusCodes{}
+
+			assert.Equal(t, http.StatusUnauthorized, codes.StatusCode(authenticityErr))
+			assert.Equal(t, http.StatusUnauthorized, codes.StatusCode(authorizationErr))
+			assert.Equal(t, http.StatusInternalServerError, codes.StatusCode(listenerErr))
+		})
+
+		t.Run("should withhold the ack only for retryable errors", func(t *testing.T) {
+			codes := bolt.HTTPErrorStatusCodes{Retryable: http.StatusServiceUnavailable}
+
+			retryable := bolt.NewMultipleListenerError([]error{bolt.NewRetryableError(errors.New("timeout"))})
+			nonRetryable := bolt.NewMultipleListenerError([]error{bolt.NewNonRetryableError(errors.New("bad input"))})
+
+			assert.Equal(t, http.StatusServiceUnavailable, codes.StatusCode(retryable))
+			assert.Equal(t, http.StatusOK, codes.StatusCode(nonRetryable))
+		})
+
+		t.Run("should use configured status codes", func(t *testing.T) {
+			codes := bolt.HTTPErrorStatusCodes{
+				Authenticity:      http.StatusForbidden,
+				Authorization:     http.StatusOK,
+				ProcessingFailure: http.StatusServiceUnavailable,
+			}
+
+			assert.Equal(t, http.StatusForbidden, codes.StatusCode(authenticityErr))
+			assert.Equal(t, http.StatusOK, codes.StatusCode(authorizationErr))
+			assert.Equal(t, http.StatusServiceUnavailable, codes.StatusCode(listenerErr))
+
+			receiver := bolt.NewHTTPReceiver(bolt.HTTPReceiverOptions{
+				SigningSecret:    fakeSigningSecret,
+				ErrorStatusCodes: &codes,
+			})
+			assert.NotNil(t, receiver)
+		})
+	})
 }
 
 func TestSocketModeReceiver(t *testing.T) {
@@ -203,3 +249,26 @@ func TestReceiverEvents(t *testing.T) {
 		// - Error handling
 	})
 }
+
+func TestHandleURLVerification(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should answer url_verification challenges", func(t *testing.T) {
+		response, ok := receivers.HandleURLVerification([]byte(`{"token":"t","challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P","type":"url_verification"}`))
+		require.True(t, ok)
+		assert.JSONEq(t, `{"challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"}`, string(response))
+	})
+
+	t.Run("should leave other requests to the app", func(t *testing.T) {
+		for _, body := range []string{
+			`{"type":"event_callback","event":{"type":"message","text":"url_verification"}}`,
+			`{"type":"url_verification"}`,
+			`command=%2Fhello&text=challenge`,
+			``,
+		} {
+			response, ok := receivers.HandleURLVerification([]byte(body))
+			assert.False(t, ok, body)
+			assert.Nil(t, response, body)
+		}
+	})
+}
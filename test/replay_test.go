@@ -0,0 +1,91 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventReplay(t *testing.T) {
+	t.Parallel()
+	t.Run("should record events and replay them through ProcessEvent", func(t *testing.T) {
+		dir := t.TempDir()
+		recorder, err := replay.NewRecorder(dir)
+		require.NoError(t, err)
+
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Receiver:      &FakeReceiver{},
+			DeveloperMode: true,
+			EventRecorder: recorder,
+		})
+		require.NoError(t, err)
+
+		var ids []string
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			ids = append(ids, args.Context.CorrelationID)
+			return args.Ack(nil)
+		})
+
+		require.NoError(t, processMention(context.Background(), app, map[string]string{"X-Request-Id": "first"}))
+		require.NoError(t, processMention(context.Background(), app, map[string]string{"X-Request-Id": "second"}))
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		recording, err := replay.Load(filepath.Join(dir, files[0].Name()))
+		require.NoError(t, err)
+		assert.Equal(t, "first", recording.CorrelationID)
+		assert.Equal(t, string(createAppMentionEventBody()), recording.Body)
+
+		results, err := replay.Dir(context.Background(), app, dir)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.NoError(t, result.Err)
+			assert.True(t, result.Acked)
+		}
+		assert.Equal(t, []string{"first", "second", "first", "second"}, ids)
+
+		// Replays are not recorded again
+		files, err = os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 2)
+	})
+
+	t.Run("should report listener errors in the replay result", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret, Receiver: &FakeReceiver{}})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			return assert.AnError
+		})
+
+		result := replay.Event(context.Background(), app, &replay.Recording{
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    string(createAppMentionEventBody()),
+		})
+		assert.ErrorIs(t, result.Err, assert.AnError)
+		assert.False(t, result.Acked)
+	})
+
+	t.Run("should require developer mode to record events", func(t *testing.T) {
+		recorder, err := replay.NewRecorder(t.TempDir())
+		require.NoError(t, err)
+
+		_, err = bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Receiver:      &FakeReceiver{},
+			EventRecorder: recorder,
+		})
+		assert.EqualError(t, err, "event recorder requires developer mode")
+	})
+}
@@ -1,12 +1,24 @@
 package test
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -213,3 +225,247 @@ func TestRequestVerification(t *testing.T) {
 		})
 	})
 }
+
+func TestSignatureVerifier(t *testing.T) {
+	t.Parallel()
+
+	signingSecret := "test_signing_secret"
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	body := []byte(`{"type":"event_callback","event":{"type":"app_mention"}}`)
+	signature := helpers.GenerateSlackSignature(signingSecret, fmt.Sprintf("v0:%s:%s", timestamp, string(body)))
+
+	t.Run("should accept a valid signature", func(t *testing.T) {
+		verifier := helpers.NewSignatureVerifier(signingSecret)
+		assert.True(t, verifier.Verify(signature, timestamp, body))
+	})
+
+	t.Run("should reject invalid signatures", func(t *testing.T) {
+		verifier := helpers.NewSignatureVerifier(signingSecret)
+
+		assert.False(t, verifier.Verify(signature, timestamp, []byte(`{"tampered":true}`)), "tampered body")
+		assert.False(t, verifier.Verify(signature, "1", body), "different timestamp")
+		assert.False(t, verifier.Verify("v1="+signature[3:], timestamp, body), "wrong version")
+		assert.False(t, verifier.Verify(signature[:len(signature)-2], timestamp, body), "truncated signature")
+		assert.False(t, verifier.Verify(signature[:len(signature)-2]+"zz", timestamp, body), "non-hex signature")
+		assert.False(t, verifier.Verify("", timestamp, body), "empty signature")
+		assert.False(t, helpers.NewSignatureVerifier("other_secret").Verify(signature, timestamp, body), "different secret")
+	})
+
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		verifier := helpers.NewSignatureVerifier(signingSecret)
+		results := make(chan bool, 50)
+
+		for i := 0; i < 50; i++ {
+			go func(i int) {
+				if i%2 == 0 {
+					results <- verifier.Verify(signature, timestamp, body)
+				} else {
+					results <- !verifier.Verify(signature, timestamp, []byte("other"))
+				}
+			}(i)
+		}
+
+		for i := 0; i < 50; i++ {
+			assert.True(t, <-results)
+		}
+	})
+}
+
+// legacyVerifySignature is the per-request HMAC verification the receivers used before
+// SignatureVerifier, kept to benchmark against
+func legacyVerifySignature(signingSecret, signature, timestamp string, body []byte) bool {
+	baseString := fmt.Sprintf("v0:%s:%s", timestamp, string(body))
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(baseString))
+	expectedSignature := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(signature), []byte(expectedSignature))
+}
+
+func BenchmarkSignatureVerification(b *testing.B) {
+	signingSecret := "test_signing_secret"
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	for _, size := range []int{256, 4096, 65536} {
+		body := make([]byte, size)
+		for i := range body {
+			body[i] = 'a'
+		}
+		signature := helpers.GenerateSlackSignature(signingSecret, fmt.Sprintf("v0:%s:%s", timestamp, string(body)))
+
+		b.Run(fmt.Sprintf("legacy/%dB", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !legacyVerifySignature(signingSecret, signature, timestamp, body) {
+					b.Fatal("signature should verify")
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("verifier/%dB", size), func(b *testing.B) {
+			verifier := helpers.NewSignatureVerifier(signingSecret)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !verifier.Verify(signature, timestamp, body) {
+					b.Fatal("signature should verify")
+				}
+			}
+		})
+	}
+}
+
+func TestSignatureMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"type":"event_callback","event":{"type":"app_mention"}}`
+
+	// serve passes a request with headers through SignatureMiddleware to a handler recording the
+	// body it read and the body stashed in its context
+	serve := func(t *testing.T, headers map[string]string) (*httptest.ResponseRecorder, []string) {
+		var seen []string
+		handler := receivers.SignatureMiddleware(fakeSigningSecret)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			read, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			verified, ok := receivers.VerifiedBody(req.Context())
+			require.True(t, ok)
+			seen = append(seen, string(read), string(verified))
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/slack/events", strings.NewReader(body))
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder, seen
+	}
+
+	t.Run("should pass verified requests on with their body", func(t *testing.T) {
+		timestamp := time.Now().Unix()
+		recorder, seen := serve(t, map[string]string{
+			"X-Slack-Request-Timestamp": strconv.FormatInt(timestamp, 10),
+			"X-Slack-Signature":         createValidSignature(body, timestamp, fakeSigningSecret),
+		})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, []string{body, body}, seen)
+	})
+
+	t.Run("should reject unsigned, forged, stale and future requests", func(t *testing.T) {
+		now := time.Now().Unix()
+		stale := now - 600
+		for name, headers := range map[string]map[string]string{
+			"unsigned": {},
+			"forged": {
+				"X-Slack-Request-Timestamp": strconv.FormatInt(now, 10),
+				"X-Slack-Signature":         createValidSignature(body, now, "another-secret"),
+			},
+			"stale": {
+				"X-Slack-Request-Timestamp": strconv.FormatInt(stale, 10),
+				"X-Slack-Signature":         createValidSignature(body, stale, fakeSigningSecret),
+			},
+			"future": {
+				"X-Slack-Request-Timestamp": strconv.FormatInt(now+600, 10),
+				"X-Slack-Signature":         createValidSignature(body, now+600, fakeSigningSecret),
+			},
+		} {
+			recorder, seen := serve(t, headers)
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code, name)
+			assert.Empty(t, seen, name)
+		}
+	})
+
+	t.Run("should leave the context of unverified requests empty", func(t *testing.T) {
+		_, ok := receivers.VerifiedBody(context.Background())
+		assert.False(t, ok)
+	})
+}
+
+// countingApp counts the events a receiver passes on
+type countingApp struct {
+	events atomic.Int32
+}
+
+func (a *countingApp) ProcessEvent(ctx context.Context, event types.ReceiverEvent) error {
+	a.events.Add(1)
+	return event.Ack(nil)
+}
+
+func TestHTTPReceiverSignatureVerification(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"type":"event_callback","event":{"type":"app_mention"}}`
+
+	// start starts a receiver with options on a free port and returns its events URL
+	start := func(t *testing.T, options types.HTTPReceiverOptions) (string, *countingApp) {
+		t.Helper()
+		_, port, err := net.SplitHostPort(freeAddr(t))
+		require.NoError(t, err)
+		options.Port, err = strconv.Atoi(port)
+		require.NoError(t, err)
+
+		receiver := receivers.NewHTTPReceiver(options)
+		app := &countingApp{}
+		require.NoError(t, receiver.Init(app))
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go func() { _ = receiver.Start(ctx) }()
+
+		require.Eventually(t, func() bool {
+			conn, err := net.Dial("tcp", "127.0.0.1:"+port)
+			if err == nil {
+				_ = conn.Close()
+			}
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		return "http://127.0.0.1:" + port + "/slack/events", app
+	}
+
+	// post sends body to url signed with secret at timestamp
+	post := func(t *testing.T, url, secret string, timestamp int64) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Slack-Request-Timestamp", strconv.FormatInt(timestamp, 10))
+		req.Header.Set("X-Slack-Signature", createValidSignature(body, timestamp, secret))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("should apply the signature tolerance to past and future timestamps", func(t *testing.T) {
+		var rejected []string
+		url, app := start(t, types.HTTPReceiverOptions{
+			SigningSecret:      fakeSigningSecret,
+			SignatureTolerance: time.Minute,
+			AuthenticityErrorHandler: func(ctx context.Context, args types.ReceiverAuthenticityErrorHandlerArgs) {
+				rejected = append(rejected, args.Error.Error())
+			},
+		})
+		now := time.Now().Unix()
+
+		assert.Equal(t, http.StatusOK, post(t, url, fakeSigningSecret, now-30))
+		assert.Equal(t, http.StatusUnauthorized, post(t, url, fakeSigningSecret, now-120))
+		assert.Equal(t, http.StatusUnauthorized, post(t, url, fakeSigningSecret, now+120))
+
+		assert.Equal(t, int32(1), app.events.Load())
+		require.Len(t, rejected, 2)
+		assert.Contains(t, rejected[0], "Request timestamp too old")
+		assert.Contains(t, rejected[1], "Request timestamp is in the future")
+	})
+
+	t.Run("should accept older requests with a wider tolerance", func(t *testing.T) {
+		url, app := start(t, types.HTTPReceiverOptions{SigningSecret: fakeSigningSecret, SignatureTolerance: 15 * time.Minute})
+
+		assert.Equal(t, http.StatusOK, post(t, url, fakeSigningSecret, time.Now().Unix()-600))
+		assert.Equal(t, int32(1), app.events.Load())
+	})
+
+	t.Run("should reject every request without a signing secret", func(t *testing.T) {
+		url, app := start(t, types.HTTPReceiverOptions{})
+
+		assert.Equal(t, http.StatusUnauthorized, post(t, url, "", time.Now().Unix()))
+		assert.Zero(t, app.events.Load())
+	})
+}
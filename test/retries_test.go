@@ -0,0 +1,141 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/helpers"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// retriedMention returns an app_mention delivered for the retryNum-th time for reason
+func retriedMention(retryNum int, reason string) bolttest.Payload {
+	payload := bolttest.AppMention("hello")
+	payload.RetryNum = retryNum
+	payload.RetryReason = reason
+	return payload
+}
+
+func TestRetriedDeliveries(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should process retries with their metadata by default", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var retryNum int
+		var retryReason string
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			retryNum, retryReason = args.Context.RetryNum, args.Context.RetryReason
+			return nil
+		})
+
+		h.Send(retriedMention(2, "http_timeout")).AssertNoError(t)
+
+		assert.Equal(t, 2, retryNum)
+		assert.Equal(t, "http_timeout", retryReason)
+	})
+
+	t.Run("should acknowledge skipped retries without running listeners", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Retries: bolt.RetryOptions{Skip: true, SkipReasons: []string{"http_timeout"}}})
+		var runs atomic.Int32
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			runs.Add(1)
+			return nil
+		})
+
+		h.Send(retriedMention(1, "http_timeout")).AssertNoError(t).AssertAcked(t)
+		assert.EqualValues(t, 0, runs.Load())
+
+		h.Send(retriedMention(1, "http_error")).AssertNoError(t)
+		h.Send(retriedMention(0, "")).AssertNoError(t)
+		assert.EqualValues(t, 2, runs.Load())
+	})
+
+	t.Run("should let ShouldSkip decide for each retry", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Retries: bolt.RetryOptions{
+			Skip: true,
+			ShouldSkip: func(ctx context.Context, event types.ReceiverEvent) bool {
+				return event.RetryNum > 1
+			},
+		}})
+		var runs atomic.Int32
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			runs.Add(1)
+			return nil
+		})
+
+		h.Send(retriedMention(1, "http_timeout")).AssertNoError(t)
+		h.Send(retriedMention(2, "http_timeout")).AssertNoError(t).AssertAcked(t)
+
+		assert.EqualValues(t, 1, runs.Load())
+	})
+}
+
+func TestReceiverRetryMetadata(t *testing.T) {
+	t.Parallel()
+
+	// retryRecordingApp creates an app with receiver reporting the retry metadata of mentions to retries
+	retryRecordingApp := func(t *testing.T, receiver types.Receiver, retries chan<- string) *bolt.App {
+		t.Helper()
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, Receiver: receiver})
+		require.NoError(t, err)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			retries <- strconv.Itoa(args.Context.RetryNum) + " " + args.Context.RetryReason
+			return nil
+		})
+		return app
+	}
+
+	t.Run("should read the retry headers in the HTTP receiver", func(t *testing.T) {
+		receiver := bolt.NewMultiAppReceiver(bolt.MultiAppReceiverOptions{})
+		retries := make(chan string, 1)
+		retryRecordingApp(t, receiver.Host(bolt.HostedAppOptions{
+			Name:                "retries",
+			HTTPReceiverOptions: bolt.HTTPReceiverOptions{SigningSecret: fakeSigningSecret},
+		}), retries)
+		handler, err := receiver.Handler()
+		require.NoError(t, err)
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/slack/events", strings.NewReader(mirroredEventBody))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		req.Header.Set("X-Slack-Signature", helpers.GenerateSlackSignature(fakeSigningSecret, "v0:"+timestamp+":"+mirroredEventBody))
+		req.Header.Set("X-Slack-Retry-Num", "1")
+		req.Header.Set("X-Slack-Retry-Reason", "http_timeout")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "1 http_timeout", <-retries)
+	})
+
+	t.Run("should read the retry headers in the AWS Lambda receiver", func(t *testing.T) {
+		receiver := receivers.NewAwsLambdaReceiver(types.AwsLambdaReceiverOptions{SigningSecret: fakeSigningSecret})
+		retries := make(chan string, 1)
+		app := retryRecordingApp(t, receiver, retries)
+		require.NoError(t, receiver.Init(app))
+
+		awsEvent := createDummyAWSEvent(mirroredEventBody, time.Now().Unix(), fakeSigningSecret)
+		awsEvent.Headers["x-slack-retry-num"] = "3"
+		awsEvent.Headers["x-slack-retry-reason"] = "http_error"
+		_, err := receiver.ToHandler()(awsEvent, nil, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "3 http_error", <-retries)
+	})
+}
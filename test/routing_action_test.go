@@ -3,9 +3,12 @@ package test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -339,4 +342,176 @@ func TestAppActionRouting(t *testing.T) {
 
 		assert.False(t, handlerCalled, "Action handler should not have been called when constraints don't all match")
 	})
+
+	t.Run("should run keyed and pattern listeners in registration order among many listeners", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+
+		var calls []string
+		for i := 0; i < 200; i++ {
+			actionID := fmt.Sprintf("other_%d", i)
+			app.Action(bolt.ActionConstraints{ActionID: actionID}, func(args bolt.SlackActionMiddlewareArgs) error {
+				calls = append(calls, actionID)
+				return nil
+			})
+		}
+		app.Action(bolt.ActionConstraints{ActionIDPattern: regexp.MustCompile(`^button_`)}, func(args bolt.SlackActionMiddlewareArgs) error {
+			calls = append(calls, "pattern")
+			return nil
+		})
+		app.Action(bolt.ActionConstraints{ActionID: "button_1"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			calls = append(calls, "action_id")
+			return nil
+		})
+		app.Action(bolt.ActionConstraints{BlockID: "block_1"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			calls = append(calls, "block_id")
+			return nil
+		})
+		app.Action(bolt.ActionConstraints{ActionID: "button_2"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			calls = append(calls, "other_button")
+			return nil
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body: createBlockActionBody("button_1", "block_1"),
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+			Ack: func(response types.AckResponse) error {
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"pattern", "action_id", "block_id"}, calls)
+	})
+}
+
+func TestActionContainerMessage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should update the message the action was taken in", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Action(types.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			return args.UpdateMessage(types.UpdateMessageString("Approved"))
+		})
+
+		h.Send(bolttest.BlockAction("approve", "yes")).AssertNoError(t).AssertAcked(t)
+
+		h.Slack.AssertCalledWith(t, "chat.update", map[string]any{
+			"channel": bolttest.ChannelID,
+			"ts":      "1700000000.000100",
+			"text":    "Approved",
+		})
+	})
+
+	t.Run("should delete the message the action was taken in", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Action(types.ActionConstraints{ActionID: "dismiss"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			return args.DeleteMessage()
+		})
+
+		h.Send(bolttest.BlockAction("dismiss", "")).AssertNoError(t)
+
+		h.Slack.AssertCalledWith(t, "chat.delete", map[string]any{
+			"channel": bolttest.ChannelID,
+			"ts":      "1700000000.000100",
+		})
+	})
+
+	t.Run("should refuse to change ephemeral messages", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var updateErr error
+		h.App.Action(types.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			updateErr = args.UpdateMessage(types.UpdateMessageArguments{Text: "Approved"})
+			return args.Ack(nil)
+		})
+
+		payload := bolttest.BlockAction("approve", "yes")
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(payload.Body, &body))
+		body["container"].(map[string]any)["is_ephemeral"] = true
+		payload.Body, _ = json.Marshal(body)
+		h.Send(payload).AssertNoError(t)
+
+		assert.ErrorIs(t, updateErr, bolt.ContextMissingPropertyErrorCode)
+		h.Slack.AssertNotCalled(t, "chat.update")
+	})
+}
+
+// modalBlockAction builds a block action taken in a modal whose inputs hold a title and priority
+func modalBlockAction(t *testing.T, actionID string) bolttest.Payload {
+	t.Helper()
+	payload := bolttest.BlockAction(actionID, "")
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(payload.Body, &body))
+	delete(body, "channel")
+	delete(body, "message")
+	body["container"] = map[string]any{"type": "view", "view_id": "V0000TEST"}
+	body["view"] = map[string]any{
+		"id":          "V0000TEST",
+		"type":        "modal",
+		"callback_id": "new_ticket",
+		"hash":        "1700000000.abc",
+		"state": map[string]any{"values": map[string]any{
+			"title_block":    map[string]any{"title": map[string]any{"type": "plain_text_input", "value": "Printer on fire"}},
+			"priority_block": map[string]any{"priority": map[string]any{"type": "static_select", "selected_option": map[string]any{"value": "high"}}},
+		}},
+	}
+	payload.Body, _ = json.Marshal(body)
+	return payload
+}
+
+func TestActionViewState(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should expose the view an action was taken in", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var args bolt.SlackActionMiddlewareArgs
+		h.App.Action(types.ActionConstraints{ActionID: "preview"}, func(a bolt.SlackActionMiddlewareArgs) error {
+			args = a
+			return a.Ack(nil)
+		})
+
+		h.Send(modalBlockAction(t, "preview")).AssertNoError(t).AssertAcked(t)
+
+		require.NotNil(t, args.View)
+		assert.Equal(t, "V0000TEST", args.View.ID)
+		assert.Equal(t, "new_ticket", args.View.CallbackID)
+		assert.Equal(t, "1700000000.abc", args.View.Hash)
+
+		title, ok := args.ViewStateValue("title_block", "title")
+		require.True(t, ok)
+		assert.Equal(t, "Printer on fire", title.Value)
+		priority, ok := args.ViewStateValue("priority_block", "priority")
+		require.True(t, ok)
+		assert.Equal(t, "high", priority.SelectedOption.Value)
+
+		_, ok = args.ViewStateValue("title_block", "missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("should leave the view unset for actions in messages", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var args bolt.SlackActionMiddlewareArgs
+		h.App.Action(types.ActionConstraints{ActionID: "approve"}, func(a bolt.SlackActionMiddlewareArgs) error {
+			args = a
+			return a.Ack(nil)
+		})
+
+		h.Send(bolttest.BlockAction("approve", "yes")).AssertNoError(t)
+
+		assert.Nil(t, args.View)
+		_, ok := args.ViewStateValue("title_block", "title")
+		assert.False(t, ok)
+	})
 }
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
 	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -112,9 +114,13 @@ func TestAppCommandRouting(t *testing.T) {
 		var capturedChannelID string
 		var sayError error
 
+		// Slack API calls go to a fake server that rejects the token
+		api := bolttest.NewSlack(t)
+		api.Handle("chat.postMessage", map[string]any{"ok": false, "error": "invalid_auth"})
 		app, err := bolt.New(bolt.AppOptions{
 			Token:         fakeToken,
 			SigningSecret: fakeSigningSecret,
+			ClientOptions: []slack.Option{slack.OptionHTTPClient(api.Client())},
 		})
 		require.NoError(t, err)
 
@@ -169,6 +175,7 @@ func TestAppCommandRouting(t *testing.T) {
 		if sayError != nil {
 			assert.Contains(t, sayError.Error(), "invalid_auth", "Should fail with auth error, not channel context error")
 		}
+		api.AssertCalledWith(t, "chat.postMessage", map[string]any{"channel": "C09F2AV5M9B", "text": "You have triggered the command"})
 	})
 
 	t.Run("should route slash command to handler", func(t *testing.T) {
@@ -3,9 +3,14 @@ package test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Asafrose/bolt-go"
+	bolterrors "github.com/Asafrose/bolt-go/pkg/errors"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -248,3 +253,212 @@ func TestAppEventRouting(t *testing.T) {
 		assert.Equal(t, "U123456", eventMap["user"], "Event user should be correct")
 	})
 }
+
+func TestEventContextReuse(t *testing.T) {
+	t.Parallel()
+	t.Run("should give each event a clean context when reusing contexts", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:              fakeToken,
+			SigningSecret:      fakeSigningSecret,
+			ReuseEventContexts: true,
+		})
+		require.NoError(t, err)
+
+		var seen []bool
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			_, leaked := args.Context.Custom["listener_value"]
+			seen = append(seen, leaked)
+			args.Context.Custom["listener_value"] = true
+			args.Context.Matches = []string{"stale"}
+
+			assert.Equal(t, "C123456", args.Context.Custom["channel"])
+			assert.Equal(t, "T123456", args.Context.TeamID)
+			return nil
+		})
+
+		for i := 0; i < 3; i++ {
+			event := types.ReceiverEvent{
+				Body:    createAppMentionEventBody(),
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Ack:     func(response types.AckResponse) error { return nil },
+			}
+			require.NoError(t, app.ProcessEvent(context.Background(), event))
+		}
+
+		assert.Equal(t, []bool{false, false, false}, seen, "custom values should not leak between events")
+	})
+}
+
+func BenchmarkProcessEvent(b *testing.B) {
+	for _, reuse := range []bool{false, true} {
+		name := "allocate"
+		if reuse {
+			name = "reuse"
+		}
+		b.Run(name, func(b *testing.B) {
+			app, err := bolt.New(bolt.AppOptions{
+				Token:              fakeToken,
+				SigningSecret:      fakeSigningSecret,
+				ReuseEventContexts: reuse,
+			})
+			require.NoError(b, err)
+			app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+				return nil
+			})
+
+			event := types.ReceiverEvent{
+				Body:    createAppMentionEventBody(),
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Ack:     func(response types.AckResponse) error { return nil },
+			}
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := app.ProcessEvent(ctx, event); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestUnhandledEventFastPath(t *testing.T) {
+	t.Parallel()
+	newEvent := func() types.ReceiverEvent {
+		return types.ReceiverEvent{
+			Body:    createAppMentionEventBody(),
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		}
+	}
+
+	t.Run("should skip authorization for events no listener handles", func(t *testing.T) {
+		authorizeCalls := 0
+		app, err := bolt.New(bolt.AppOptions{
+			SigningSecret: fakeSigningSecret,
+			Authorize: func(ctx context.Context, source bolt.AuthorizeSourceData, body interface{}) (*bolt.AuthorizeResult, error) {
+				authorizeCalls++
+				return &bolt.AuthorizeResult{BotToken: fakeToken}, nil
+			},
+		})
+		require.NoError(t, err)
+		app.Event("reaction_added", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+
+		require.NoError(t, app.ProcessEvent(context.Background(), newEvent()))
+		assert.Equal(t, 0, authorizeCalls)
+
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+		require.NoError(t, app.ProcessEvent(context.Background(), newEvent()))
+		assert.Equal(t, 1, authorizeCalls)
+	})
+
+	t.Run("should still run global middleware for events no listener handles", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+
+		middlewareCalled := false
+		app.Use(func(args bolt.AllMiddlewareArgs) error {
+			middlewareCalled = true
+			return args.Next()
+		})
+
+		require.NoError(t, app.ProcessEvent(context.Background(), newEvent()))
+		assert.True(t, middlewareCalled)
+	})
+}
+
+func TestConcurrentListeners(t *testing.T) {
+	t.Parallel()
+	newEvent := func() types.ReceiverEvent {
+		return types.ReceiverEvent{
+			Body:    createAppMentionEventBody(),
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Ack:     func(response types.AckResponse) error { return nil },
+		}
+	}
+
+	t.Run("should run matching listeners concurrently up to the limit", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:               fakeToken,
+			SigningSecret:       fakeSigningSecret,
+			ListenerConcurrency: 2,
+		})
+		require.NoError(t, err)
+
+		// Each listener waits for the other, so they only finish if both run at the same time
+		var barrier sync.WaitGroup
+		barrier.Add(2)
+		for i := 0; i < 2; i++ {
+			app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+				args.Context.Custom["listener"] = i
+				barrier.Done()
+				barrier.Wait()
+				return nil
+			})
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- app.ProcessEvent(context.Background(), newEvent()) }()
+
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("listeners did not run concurrently")
+		}
+	})
+
+	t.Run("should aggregate listener errors in registration order", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:               fakeToken,
+			SigningSecret:       fakeSigningSecret,
+			ListenerConcurrency: 4,
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 4; i++ {
+			app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+				// Later listeners finish first
+				time.Sleep(time.Duration(4-i) * 5 * time.Millisecond)
+				if i%2 == 1 {
+					return fmt.Errorf("listener %d failed", i)
+				}
+				return nil
+			})
+		}
+
+		err = app.ProcessEvent(context.Background(), newEvent())
+		require.Error(t, err)
+
+		var multipleErr *bolterrors.MultipleListenerError
+		require.True(t, errors.As(err, &multipleErr))
+		failures := multipleErr.Failures()
+		require.Len(t, failures, 2)
+		assert.Equal(t, 1, failures[0].Index)
+		assert.Equal(t, 3, failures[1].Index)
+	})
+
+	t.Run("should run listeners one at a time by default", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+
+		var order []int
+		for i := 0; i < 3; i++ {
+			app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+				order = append(order, i)
+				return nil
+			})
+		}
+
+		require.NoError(t, app.ProcessEvent(context.Background(), newEvent()))
+		assert.Equal(t, []int{0, 1, 2}, order)
+	})
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"context"
 	"encoding/json"
+	"regexp"
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
@@ -334,4 +335,51 @@ func TestAppMessageRouting(t *testing.T) {
 
 		assert.True(t, handlerCalled, "Message handler should match partial text")
 	})
+
+	t.Run("should expose RegExp submatches of the matched pattern on context.Matches", func(t *testing.T) {
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+		})
+		require.NoError(t, err)
+
+		var greetingMatches, plainMatches []string
+		app.Message(regexp.MustCompile(`hello (\w+)`), func(args bolt.SlackEventMiddlewareArgs) error {
+			greetingMatches = args.Context.Matches
+			return nil
+		})
+		app.Message("hello", func(args bolt.SlackEventMiddlewareArgs) error {
+			plainMatches = args.Context.Matches
+			return nil
+		})
+
+		err = app.ProcessEvent(context.Background(), types.ReceiverEvent{
+			Body: createMessageEventBodyWithText("hello world"),
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+			Ack: func(response types.AckResponse) error {
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"hello world", "world"}, greetingMatches)
+		assert.Nil(t, plainMatches, "String patterns should not leave matches from other listeners")
+	})
+}
+
+func TestContextFindStringSubmatch(t *testing.T) {
+	t.Parallel()
+	t.Run("should reuse results for the same pattern and text", func(t *testing.T) {
+		ctx := &types.Context{}
+		pattern := regexp.MustCompile(`(\d+)`)
+
+		first := ctx.FindStringSubmatch(pattern, "order 42")
+		second := ctx.FindStringSubmatch(pattern, "order 42")
+
+		assert.Equal(t, []string{"42", "42"}, first)
+		assert.Same(t, &first[0], &second[0], "Second lookup should return the cached slice")
+		assert.Nil(t, ctx.FindStringSubmatch(pattern, "no digits"))
+	})
 }
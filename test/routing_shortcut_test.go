@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
 	"github.com/Asafrose/bolt-go/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -362,3 +363,59 @@ func TestAppShortcutRouting(t *testing.T) {
 		assert.False(t, handlerCalled, "Shortcut handler should not have been called when type constraint doesn't match")
 	})
 }
+
+func TestMessageShortcutSay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should reply in the thread of the selected message", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.ShortcutString("summarize", func(args bolt.SlackShortcutMiddlewareArgs) error {
+			_, err := (*args.Say)(types.SayString("Summary coming up"))
+			return err
+		})
+
+		h.Send(bolttest.MessageShortcut("summarize", "long message")).AssertNoError(t)
+
+		h.Slack.AssertCalledWith(t, "chat.postMessage", map[string]any{
+			"channel":   bolttest.ChannelID,
+			"thread_ts": "1700000000.000100",
+			"text":      "Summary coming up",
+		})
+	})
+
+	t.Run("should reply in the thread the selected message was posted in", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.ShortcutString("summarize", func(args bolt.SlackShortcutMiddlewareArgs) error {
+			_, err := (*args.Say)(types.SayString("Summary coming up"))
+			return err
+		})
+
+		payload := bolttest.MessageShortcut("summarize", "reply")
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(payload.Body, &body))
+		body["message"].(map[string]any)["thread_ts"] = "1699999999.000100"
+		payload.Body, _ = json.Marshal(body)
+		h.Send(payload).AssertNoError(t)
+
+		h.Slack.AssertCalledWith(t, "chat.postMessage", map[string]any{"thread_ts": "1699999999.000100"})
+	})
+
+	t.Run("should post in the channel or another thread when asked to", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.ShortcutString("summarize", func(args bolt.SlackShortcutMiddlewareArgs) error {
+			say := *args.Say
+			if _, err := say(types.SayArguments{Text: "top level", InChannel: true}); err != nil {
+				return err
+			}
+			_, err := say(types.SayArguments{Text: "elsewhere", ThreadTS: "1600000000.000100"})
+			return err
+		})
+
+		h.Send(bolttest.MessageShortcut("summarize", "long message")).AssertNoError(t)
+
+		calls := h.Slack.Calls("chat.postMessage")
+		require.Len(t, calls, 2)
+		assert.NotContains(t, calls[0].Params, "thread_ts")
+		assert.Equal(t, "1600000000.000100", calls[1].Params["thread_ts"])
+	})
+}
@@ -0,0 +1,234 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/socketmodesim"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent logging
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// freeAddr returns a local address nothing listens on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+// probe returns the status code and body of a health server endpoint
+func probe(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// newSimulatedSocketModeApp creates an app whose SocketModeReceiver and Web API calls go to sim
+func newSimulatedSocketModeApp(t *testing.T, sim *socketmodesim.Server, logs io.Writer) *bolt.App {
+	t.Helper()
+	receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{
+		AppToken: "xapp-sim",
+		BotToken: fakeToken,
+		APIURL:   sim.APIURL(),
+	})
+	app, err := bolt.New(bolt.AppOptions{
+		Token:         fakeToken,
+		BotID:         "B0000SIM",
+		BotUserID:     "U0000SIM",
+		Receiver:      receiver,
+		ClientOptions: []slack.Option{slack.OptionAPIURL(sim.APIURL())},
+		Logger:        slog.New(slog.NewTextHandler(logs, nil)),
+	})
+	require.NoError(t, err)
+	return app
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+	t.Run("should report readiness once connected and drain in-flight events when stopping", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{AppToken: "xapp-sim"})
+		defer sim.Close()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		finished := make(chan struct{})
+		app := newSimulatedSocketModeApp(t, sim, io.Discard)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			close(started)
+			<-release
+			close(finished)
+			return nil
+		})
+
+		addr := freeAddr(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		served := make(chan error, 1)
+		go func() {
+			served <- app.Serve(ctx, bolt.ServeOptions{
+				HealthAddr:        addr,
+				PreStopDelay:      200 * time.Millisecond,
+				DrainTimeout:      5 * time.Second,
+				ReadinessInterval: 20 * time.Millisecond,
+			})
+		}()
+
+		require.Eventually(t, func() bool {
+			status, _ := probe(t, "http://"+addr+"/readyz")
+			return status == http.StatusOK
+		}, 5*time.Second, 20*time.Millisecond)
+		status, _ := probe(t, "http://"+addr+"/healthz")
+		assert.Equal(t, http.StatusOK, status)
+
+		go func() {
+			_, _ = sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("slow").Body))
+		}()
+		<-started
+
+		cancel()
+		require.Eventually(t, func() bool {
+			status, body := probe(t, "http://"+addr+"/readyz")
+			return status == http.StatusServiceUnavailable && body == "stopping\n"
+		}, time.Second, 10*time.Millisecond)
+
+		select {
+		case err := <-served:
+			t.Fatalf("Serve returned before in-flight events finished: %v", err)
+		case <-time.After(300 * time.Millisecond):
+		}
+		close(release)
+
+		select {
+		case err := <-served:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Serve did not return after draining")
+		}
+		select {
+		case <-finished:
+		default:
+			t.Fatal("in-flight event did not finish")
+		}
+		status, _ = probe(t, "http://"+addr+"/healthz")
+		assert.Equal(t, 0, status, "health server should be stopped")
+	})
+
+	t.Run("should stop waiting for in-flight events after the drain timeout", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{AppToken: "xapp-sim"})
+		defer sim.Close()
+
+		var logs syncBuffer
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		app := newSimulatedSocketModeApp(t, sim, &logs)
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		served := make(chan error, 1)
+		go func() {
+			served <- app.Serve(ctx, bolt.ServeOptions{DrainTimeout: 100 * time.Millisecond})
+		}()
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+		defer waitCancel()
+		require.NoError(t, sim.WaitForConnections(waitCtx, 1))
+		go func() {
+			_, _ = sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("stuck").Body))
+		}()
+		<-started
+
+		cancel()
+		select {
+		case <-served:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Serve did not return after the drain timeout")
+		}
+		assert.Contains(t, logs.String(), "Stopping app before in-flight events finished")
+		assert.Contains(t, logs.String(), "in_flight=1")
+	})
+
+	t.Run("should fail readiness probes with the reason the check failed", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{AppToken: "xapp-sim"})
+		defer sim.Close()
+		app := newSimulatedSocketModeApp(t, sim, io.Discard)
+
+		addr := freeAddr(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		served := make(chan error, 1)
+		go func() {
+			served <- app.Serve(ctx, bolt.ServeOptions{
+				HealthAddr: addr,
+				ReadinessCheck: func(ctx context.Context) error {
+					return errors.New("slack unreachable")
+				},
+				ReadinessInterval: 20 * time.Millisecond,
+			})
+		}()
+
+		require.Eventually(t, func() bool {
+			status, body := probe(t, "http://"+addr+"/readyz")
+			return status == http.StatusServiceUnavailable && body == "slack unreachable\n"
+		}, 5*time.Second, 20*time.Millisecond)
+
+		cancel()
+		require.NoError(t, <-served)
+	})
+
+	t.Run("should fail when the health server cannot listen", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, SigningSecret: fakeSigningSecret})
+		require.NoError(t, err)
+		err = app.Serve(context.Background(), bolt.ServeOptions{HealthAddr: listener.Addr().String()})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "starting health server")
+	})
+}
@@ -512,6 +512,54 @@ func TestSocketModeAdvanced(t *testing.T) {
 			// Start() should complete successfully even with invalid token since connection happens in background
 			require.NoError(t, err, "Start should return without error even with invalid token")
 		})
+
+		t.Run("should start and stop the envelope worker pool", func(t *testing.T) {
+			receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{
+				AppToken:        fakeAppToken,
+				WorkerPoolSize:  4,
+				WorkerQueueSize: 16,
+			})
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:         fakeToken,
+				SigningSecret: fakeSigningSecret,
+			})
+			require.NoError(t, err)
+			require.NoError(t, receiver.Init(app))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			// Start returns only after the workers have exited
+			require.NoError(t, receiver.Start(ctx))
+		})
+
+		t.Run("should report empty queue stats for an idle worker pool with a shedding policy", func(t *testing.T) {
+			receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{
+				AppToken:            fakeAppToken,
+				WorkerPoolSize:      2,
+				WorkerQueueSize:     1,
+				OverloadPolicy:      types.OverloadPolicyShedByPriority,
+				EventTypePriorities: map[string]int{"block_actions": 10, "message": -1},
+			})
+			assert.Equal(t, int64(0), receiver.QueueStats().Dropped)
+
+			app, err := bolt.New(bolt.AppOptions{
+				Token:         fakeToken,
+				SigningSecret: fakeSigningSecret,
+			})
+			require.NoError(t, err)
+			require.NoError(t, receiver.Init(app))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			require.NoError(t, receiver.Start(ctx))
+
+			stats := receiver.QueueStats()
+			assert.Equal(t, 0, stats.Queued)
+			assert.Equal(t, int64(0), stats.Dropped)
+			assert.Empty(t, stats.DroppedByEventType)
+		})
 	})
 
 	t.Run("#stop()", func(t *testing.T) {
@@ -0,0 +1,556 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/options"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/socketmodesim"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSimulatedSocketModeApp runs an app behind a SocketModeReceiver connected to sim until the test ends
+func startSimulatedSocketModeApp(t *testing.T, sim *socketmodesim.Server, register func(app *bolt.App)) {
+	t.Helper()
+	startSimulatedSocketModeAppWithOptions(t, sim, types.SocketModeReceiverOptions{}, register)
+}
+
+// startSimulatedSocketModeAppWithOptions is startSimulatedSocketModeApp with receiver options
+func startSimulatedSocketModeAppWithOptions(t *testing.T, sim *socketmodesim.Server, options types.SocketModeReceiverOptions, register func(app *bolt.App)) {
+	t.Helper()
+	options.AppToken = "xapp-sim"
+	options.BotToken = fakeToken
+	options.APIURL = sim.APIURL()
+	receiver := receivers.NewSocketModeReceiver(options)
+	app, err := bolt.New(bolt.AppOptions{
+		Token:     fakeToken,
+		BotID:     "B0000SIM",
+		BotUserID: "U0000SIM",
+		Receiver:  receiver,
+	})
+	require.NoError(t, err)
+	register(app)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = app.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer waitCancel()
+	require.NoError(t, sim.WaitForConnections(waitCtx, 1))
+}
+
+func TestSocketModeSimulator(t *testing.T) {
+	t.Parallel()
+	t.Run("should deliver envelopes to a SocketModeReceiver and collect acks", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{AppToken: "xapp-sim"})
+		defer sim.Close()
+
+		mentions := make(chan string, 1)
+		startSimulatedSocketModeApp(t, sim, func(app *bolt.App) {
+			app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+				mentions <- args.Event.GetType()
+				return nil
+			})
+			app.Command("/echo", func(args bolt.SlackCommandMiddlewareArgs) error {
+				return args.Ack(&types.CommandResponse{Text: "echo " + args.Command.Text})
+			})
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		ack, err := sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("hello").Body))
+		require.NoError(t, err)
+		assert.NotEmpty(t, ack.EnvelopeID)
+		assert.Equal(t, "app_mention", <-mentions)
+
+		ack, err = sim.SendSlashCommand(ctx, map[string]any{
+			"command":               "/echo",
+			"text":                  "hi",
+			"team_id":               bolttest.TeamID,
+			"user_id":               bolttest.UserID,
+			"channel_id":            bolttest.ChannelID,
+			"is_enterprise_install": "false",
+		})
+		require.NoError(t, err)
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(ack.Payload, &response))
+		assert.Equal(t, "echo hi", response["text"])
+	})
+
+	t.Run("should reconnect clients after a disconnect or a dropped connection", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{})
+		defer sim.Close()
+
+		startSimulatedSocketModeApp(t, sim, func(app *bolt.App) {
+			app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		require.NoError(t, sim.Disconnect(socketmodesim.DisconnectRefreshRequested))
+		require.NoError(t, sim.WaitForConnections(ctx, 2))
+
+		sim.Drop()
+		require.NoError(t, sim.WaitForConnections(ctx, 3))
+		assert.Equal(t, 3, sim.Opened())
+
+		_, err := sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("still there").Body))
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject connections with an unknown app token", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{AppToken: "xapp-sim"})
+		defer sim.Close()
+
+		client := slack.New("", slack.OptionAppLevelToken("xapp-other"), slack.OptionAPIURL(sim.APIURL()))
+		_, _, err := client.StartSocketModeContext(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid_auth")
+
+		_, err = sim.SendEvent(context.Background(), map[string]any{})
+		assert.ErrorIs(t, err, socketmodesim.ErrNotConnected)
+		assert.Equal(t, 0, sim.Opened())
+	})
+}
+
+func TestSocketModeEnvelopeTypes(t *testing.T) {
+	t.Parallel()
+
+	sim := socketmodesim.New(socketmodesim.Options{})
+	defer sim.Close()
+
+	actions := make(chan string, 1)
+	startSimulatedSocketModeApp(t, sim, func(app *bolt.App) {
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+		app.Action(types.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			actions <- "approve"
+			return args.Ack(nil)
+		})
+		app.ViewString("form", func(args bolt.SlackViewMiddlewareArgs) error {
+			return args.Ack(&types.ViewResponse{
+				ResponseAction: "errors",
+				Errors:         map[string]string{"title": "Required"},
+			})
+		})
+		app.Options(types.OptionsConstraints{ActionID: "owner"}, func(args bolt.SlackOptionsMiddlewareArgs) error {
+			response := options.Response(options.Option("Ada", "ada"))
+			return args.Ack(&response)
+		})
+		app.Command("/quiet", func(args bolt.SlackCommandMiddlewareArgs) error {
+			return args.Ack(nil)
+		})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ackPayload := func(t *testing.T, ack socketmodesim.Ack) map[string]any {
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(ack.Payload, &payload))
+		return payload
+	}
+
+	t.Run("should acknowledge events_api envelopes without a payload", func(t *testing.T) {
+		ack, err := sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("hello").Body))
+		require.NoError(t, err)
+		assert.Empty(t, ack.Payload)
+	})
+
+	t.Run("should dispatch interactive envelopes to action listeners", func(t *testing.T) {
+		ack, err := sim.SendInteractive(ctx, json.RawMessage(bolttest.BlockAction("approve", "yes").Body))
+		require.NoError(t, err)
+		assert.Equal(t, "approve", <-actions)
+		assert.Empty(t, ack.Payload)
+	})
+
+	t.Run("should send the view submission response in the ack", func(t *testing.T) {
+		ack, err := sim.SendInteractive(ctx, json.RawMessage(bolttest.ViewSubmission("form", nil).Body))
+		require.NoError(t, err)
+		payload := ackPayload(t, ack)
+		assert.Equal(t, "errors", payload["response_action"])
+		assert.Equal(t, map[string]any{"title": "Required"}, payload["errors"])
+	})
+
+	t.Run("should send the options in the ack of a block_suggestion", func(t *testing.T) {
+		ack, err := sim.SendInteractive(ctx, json.RawMessage(bolttest.BlockSuggestion("owner", "a").Body))
+		require.NoError(t, err)
+		served, _ := ackPayload(t, ack)["options"].([]any)
+		require.Len(t, served, 1)
+		assert.Equal(t, "ada", served[0].(map[string]any)["value"])
+	})
+
+	t.Run("should dispatch slash_commands envelopes missing fields the socketmode client requires", func(t *testing.T) {
+		ack, err := sim.SendSlashCommand(ctx, map[string]any{
+			"command":    "/quiet",
+			"text":       "",
+			"team_id":    bolttest.TeamID,
+			"user_id":    bolttest.UserID,
+			"channel_id": bolttest.ChannelID,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, ack.Payload)
+	})
+}
+
+func TestSocketModeConnectionHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report connections, disconnects and reconnects", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{AppID: "A0000HOOK"})
+		defer sim.Close()
+
+		lifecycle := make(chan string, 32)
+		hellos := make(chan bolt.SocketModeHello, 8)
+		startSimulatedSocketModeAppWithOptions(t, sim, types.SocketModeReceiverOptions{
+			OnConnecting: func() { lifecycle <- "connecting" },
+			OnConnected: func(hello bolt.SocketModeHello) {
+				hellos <- hello
+				lifecycle <- "connected"
+			},
+			OnDisconnect: func(reason string) { lifecycle <- "disconnect:" + reason },
+			OnReconnect:  func() { lifecycle <- "reconnect" },
+		}, func(app *bolt.App) {})
+
+		next := func() string {
+			select {
+			case event := <-lifecycle:
+				return event
+			case <-time.After(5 * time.Second):
+				return "timeout"
+			}
+		}
+		assert.Equal(t, "connecting", next())
+		assert.Equal(t, "connected", next())
+		hello := <-hellos
+		assert.Equal(t, "A0000HOOK", hello.AppID)
+		assert.Equal(t, 1, hello.NumConnections)
+		assert.Equal(t, "socketmodesim", hello.Host)
+
+		require.NoError(t, sim.Disconnect(socketmodesim.DisconnectRefreshRequested))
+		assert.Equal(t, "disconnect:refresh_requested", next())
+		assert.Equal(t, "reconnect", next())
+		assert.Equal(t, "connecting", next())
+		assert.Equal(t, "connected", next())
+
+		sim.Drop()
+		assert.Equal(t, "disconnect:", next())
+		assert.Equal(t, "reconnect", next())
+		assert.Equal(t, "connecting", next())
+		assert.Equal(t, "connected", next())
+	})
+
+	t.Run("should give up after MaxReconnectAttempts failed attempts", func(t *testing.T) {
+		var opens atomic.Int32
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			opens.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":false,"error":"internal_error"}`))
+		}))
+		defer api.Close()
+
+		type reconnecting struct {
+			attempt int
+			delay   time.Duration
+		}
+		var calls []reconnecting
+		receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{
+			AppToken:             "xapp-sim",
+			APIURL:               api.URL + "/api/",
+			MaxReconnectAttempts: 3,
+			ReconnectBackoff:     bolt.ExponentialBackoff(5*time.Millisecond, 8*time.Millisecond),
+			OnReconnecting: func(attempt int, delay time.Duration, err error) {
+				assert.ErrorContains(t, err, "internal_error")
+				calls = append(calls, reconnecting{attempt, delay})
+			},
+		})
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, BotID: "B0000SIM", BotUserID: "U0000SIM", Receiver: receiver})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = app.Start(ctx)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "after 3 attempts")
+		assert.ErrorContains(t, err, "internal_error")
+		assert.Equal(t, int32(3), opens.Load())
+		assert.Equal(t, []reconnecting{{1, 5 * time.Millisecond}, {2, 8 * time.Millisecond}}, calls)
+	})
+
+	t.Run("should connect once Slack accepts connections again", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{})
+		defer sim.Close()
+		simURL, err := url.Parse(sim.APIURL())
+		require.NoError(t, err)
+		proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: simURL.Scheme, Host: simURL.Host})
+		var failures atomic.Int32
+		failures.Store(2)
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if failures.Add(-1) >= 0 {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"ok":false,"error":"internal_error"}`))
+				return
+			}
+			proxy.ServeHTTP(w, r)
+		}))
+		defer api.Close()
+
+		attempts := make(chan int, 8)
+		receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{
+			AppToken:             "xapp-sim",
+			APIURL:               api.URL + "/api/",
+			MaxReconnectAttempts: 3,
+			ReconnectBackoff:     bolt.ConstantBackoff(time.Millisecond),
+			OnReconnecting:       func(attempt int, delay time.Duration, err error) { attempts <- attempt },
+		})
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, BotID: "B0000SIM", BotUserID: "U0000SIM", Receiver: receiver})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- app.Start(ctx) }()
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+		defer waitCancel()
+		require.NoError(t, sim.WaitForConnections(waitCtx, 1))
+		assert.Equal(t, 1, <-attempts)
+		assert.Equal(t, 2, <-attempts)
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("should wait the Retry-After of rate limited attempts", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{})
+		defer sim.Close()
+		simURL, err := url.Parse(sim.APIURL())
+		require.NoError(t, err)
+		proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: simURL.Scheme, Host: simURL.Host})
+		var limited atomic.Bool
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limited.CompareAndSwap(false, true) {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			proxy.ServeHTTP(w, r)
+		}))
+		defer api.Close()
+
+		delays := make(chan time.Duration, 8)
+		receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{
+			AppToken:         "xapp-sim",
+			APIURL:           api.URL + "/api/",
+			ReconnectBackoff: bolt.ConstantBackoff(time.Millisecond),
+			OnReconnecting:   func(attempt int, delay time.Duration, err error) { delays <- delay },
+		})
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, BotID: "B0000SIM", BotUserID: "U0000SIM", Receiver: receiver})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		started := time.Now()
+		go func() { done <- app.Start(ctx) }()
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+		defer waitCancel()
+		require.NoError(t, sim.WaitForConnections(waitCtx, 1))
+		assert.Equal(t, time.Second, <-delays)
+		assert.GreaterOrEqual(t, time.Since(started), time.Second)
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should double the exponential backoff up to its maximum", func(t *testing.T) {
+		backoff := bolt.ExponentialBackoff(100*time.Millisecond, time.Second)
+		assert.Equal(t, 100*time.Millisecond, backoff(1))
+		assert.Equal(t, 200*time.Millisecond, backoff(2))
+		assert.Equal(t, 800*time.Millisecond, backoff(4))
+		assert.Equal(t, time.Second, backoff(5))
+		assert.Equal(t, time.Second, backoff(1000))
+	})
+
+	t.Run("should always wait the constant backoff", func(t *testing.T) {
+		backoff := bolt.ConstantBackoff(time.Second)
+		assert.Equal(t, time.Second, backoff(1))
+		assert.Equal(t, time.Second, backoff(10))
+	})
+}
+
+func TestSocketModeWorkerPoolAcks(t *testing.T) {
+	t.Parallel()
+
+	// holdMentions registers a listener reporting the last word of each mention on processed,
+	// holding mentions of "slow" until release is closed
+	holdMentions := func(app *bolt.App, processed chan<- string, release <-chan struct{}) <-chan struct{} {
+		holding := make(chan struct{})
+		app.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			var body struct {
+				Event struct {
+					Text string `json:"text"`
+				} `json:"event"`
+			}
+			_ = json.Unmarshal(args.Context.RawBody, &body)
+			words := strings.Fields(body.Event.Text)
+			text := words[len(words)-1]
+			if text == "slow" {
+				close(holding)
+				<-release
+			}
+			processed <- text
+			return nil
+		})
+		return holding
+	}
+
+	t.Run("should shed the incoming envelope instead of an acknowledged one", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{})
+		defer sim.Close()
+
+		processed := make(chan string, 8)
+		dropped := make(chan string, 8)
+		release := make(chan struct{})
+		releaseOnce := sync.OnceFunc(func() { close(release) })
+		defer releaseOnce()
+		var holding <-chan struct{}
+		startSimulatedSocketModeAppWithOptions(t, sim, types.SocketModeReceiverOptions{
+			WorkerPoolSize:    1,
+			WorkerQueueSize:   1,
+			OverloadPolicy:    types.OverloadPolicyShedOldest,
+			OnEnvelopeDropped: func(eventType string) { dropped <- eventType },
+		}, func(app *bolt.App) { holding = holdMentions(app, processed, release) })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("slow").Body))
+		require.NoError(t, err)
+		<-holding
+		_, err = sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("queued").Body))
+		require.NoError(t, err, "the queued envelope should be acknowledged")
+
+		shedCtx, shedCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer shedCancel()
+		_, err = sim.SendEvent(shedCtx, json.RawMessage(bolttest.AppMention("shed").Body))
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "the shed envelope should be left for Slack to redeliver")
+		assert.Equal(t, "app_mention", <-dropped)
+
+		releaseOnce()
+		assert.Equal(t, "slow", <-processed)
+		assert.Equal(t, "queued", <-processed)
+		select {
+		case text := <-processed:
+			t.Fatalf("unexpected mention %q processed", text)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("should process acknowledged envelopes still queued when the receiver stops", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{})
+		defer sim.Close()
+
+		processed := make(chan string, 8)
+		release := make(chan struct{})
+		receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{
+			AppToken:        "xapp-sim",
+			APIURL:          sim.APIURL(),
+			WorkerPoolSize:  1,
+			WorkerQueueSize: 4,
+		})
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, BotID: "B0000SIM", BotUserID: "U0000SIM", Receiver: receiver})
+		require.NoError(t, err)
+		holding := holdMentions(app, processed, release)
+
+		appCtx, stop := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- app.Start(appCtx) }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, sim.WaitForConnections(ctx, 1))
+		_, err = sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("slow").Body))
+		require.NoError(t, err)
+		<-holding
+		_, err = sim.SendEvent(ctx, json.RawMessage(bolttest.AppMention("queued").Body))
+		require.NoError(t, err)
+
+		stop()
+		close(release)
+		require.NoError(t, <-done)
+		assert.Equal(t, "slow", <-processed)
+		assert.Equal(t, "queued", <-processed)
+	})
+}
+
+func TestSocketModeConcurrentListenerAcks(t *testing.T) {
+	t.Parallel()
+	sim := socketmodesim.New(socketmodesim.Options{})
+	defer sim.Close()
+
+	receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{AppToken: "xapp-sim", APIURL: sim.APIURL()})
+	app, err := bolt.New(bolt.AppOptions{
+		Token:               fakeToken,
+		BotID:               "B0000SIM",
+		BotUserID:           "U0000SIM",
+		Receiver:            receiver,
+		ListenerConcurrency: 2,
+	})
+	require.NoError(t, err)
+
+	// Both listeners ack once the other is running, so their acks race
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	ackErrs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		app.Action(bolt.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			barrier.Done()
+			barrier.Wait()
+			ackErrs <- args.Ack(nil)
+			return nil
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- app.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	require.NoError(t, sim.WaitForConnections(ctx, 1))
+
+	_, err = sim.SendInteractive(ctx, json.RawMessage(bolttest.BlockAction("approve", "yes").Body))
+	require.NoError(t, err)
+
+	first, second := <-ackErrs, <-ackErrs
+	assert.True(t, (first == nil) != (second == nil), "exactly one ack should succeed, got %v and %v", first, second)
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/receivers"
+	"github.com/Asafrose/bolt-go/pkg/socketmodesim"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubbornReceiver serves until it is stopped, ignoring the context it was started with, or
+// fails with err once failed is closed
+type stubbornReceiver struct {
+	stopped chan struct{}
+	failed  chan struct{}
+	err     error
+}
+
+func newStubbornReceiver() *stubbornReceiver {
+	return &stubbornReceiver{stopped: make(chan struct{}), failed: make(chan struct{})}
+}
+
+func (r *stubbornReceiver) Init(app types.App) error { return nil }
+
+func (r *stubbornReceiver) Start(ctx context.Context) error {
+	select {
+	case <-r.stopped:
+		return nil
+	case <-r.failed:
+		return r.err
+	}
+}
+
+func (r *stubbornReceiver) Stop(ctx context.Context) error {
+	close(r.stopped)
+	return nil
+}
+
+func TestStartAsync(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report receiver failures on the channel", func(t *testing.T) {
+		receiver := newStubbornReceiver()
+		receiver.err = errors.New("listener socket closed")
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, Receiver: receiver})
+		require.NoError(t, err)
+
+		errs, err := app.StartAsync(context.Background())
+		require.NoError(t, err)
+		select {
+		case err := <-errs:
+			t.Fatalf("receiver stopped early: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(receiver.failed)
+		assert.EqualError(t, <-errs, "listener socket closed")
+		_, open := <-errs
+		assert.False(t, open, "channel should be closed once the receiver stopped")
+	})
+
+	t.Run("should report a Socket Mode connection failing for good", func(t *testing.T) {
+		sim := socketmodesim.New(socketmodesim.Options{AppToken: "xapp-current"})
+		defer sim.Close()
+		receiver := receivers.NewSocketModeReceiver(types.SocketModeReceiverOptions{AppToken: "xapp-revoked", APIURL: sim.APIURL()})
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, Receiver: receiver})
+		require.NoError(t, err)
+
+		errs, err := app.StartAsync(context.Background())
+		require.NoError(t, err)
+		select {
+		case err := <-errs:
+			assert.ErrorContains(t, err, "invalid_auth")
+		case <-time.After(5 * time.Second):
+			t.Fatal("receiver failure was not reported")
+		}
+	})
+
+	t.Run("should stop receivers ignoring a done context", func(t *testing.T) {
+		receiver := newStubbornReceiver()
+		app, err := bolt.New(bolt.AppOptions{Token: fakeToken, Receiver: receiver})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- app.Start(ctx) }()
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(15 * time.Second):
+			t.Fatal("Start did not return after its context was cancelled")
+		}
+		assert.True(t, isClosed(receiver.stopped), "receiver should be stopped")
+	})
+}
+
+// isClosed reports whether ch is closed
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubcommands(t *testing.T) {
+	t.Parallel()
+
+	newTodoApp := func(t *testing.T, added *[]string) *bolttest.Harness {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Command("/todo", bolt.Subcommands(map[string]bolt.Subcommand{
+			"add": {
+				Description: "Add a todo",
+				Usage:       "<title>",
+				Handler: func(args bolt.SlackCommandMiddlewareArgs, arguments string) error {
+					*added = append(*added, arguments)
+					return args.Ack(&types.CommandResponse{Text: "Added " + arguments})
+				},
+			},
+			"list": {
+				Description: "List your todos",
+				Handler: func(args bolt.SlackCommandMiddlewareArgs, arguments string) error {
+					return args.Ack(nil)
+				},
+			},
+		}))
+		return h
+	}
+
+	t.Run("should dispatch to the subcommand with the rest of the text", func(t *testing.T) {
+		var added []string
+		h := newTodoApp(t, &added)
+
+		result := h.Send(bolttest.Command("/todo", "ADD  Buy milk ")).AssertNoError(t).AssertAcked(t)
+
+		assert.Equal(t, []string{"Buy milk"}, added)
+		assert.Equal(t, "Added Buy milk", result.AckJSON(t)["text"])
+	})
+
+	t.Run("should answer help and empty texts with the subcommands", func(t *testing.T) {
+		var added []string
+		h := newTodoApp(t, &added)
+
+		expected := "*Usage:* `/todo <subcommand>`\n" +
+			"\n• `/todo add <title>` – Add a todo" +
+			"\n• `/todo list` – List your todos" +
+			"\n• `/todo help` – Show this message"
+		for _, text := range []string{"help", ""} {
+			ack := h.Send(bolttest.Command("/todo", text)).AssertNoError(t).AssertAcked(t).AckJSON(t)
+			assert.Equal(t, expected, ack["text"])
+			assert.Equal(t, "ephemeral", ack["response_type"])
+		}
+		assert.Empty(t, added)
+	})
+
+	t.Run("should suggest the closest subcommands for unknown ones", func(t *testing.T) {
+		var added []string
+		h := newTodoApp(t, &added)
+
+		typo := h.Send(bolttest.Command("/todo", "lsit")).AssertNoError(t).AckJSON(t)
+		assert.Contains(t, typo["text"], "Unknown subcommand `lsit`. Did you mean `/todo list`?\n\n*Usage:*")
+
+		prefix := h.Send(bolttest.Command("/todo", "a milk")).AssertNoError(t).AckJSON(t)
+		assert.Contains(t, prefix["text"], "Did you mean `/todo add`?")
+
+		unrelated := h.Send(bolttest.Command("/todo", "deploy")).AssertNoError(t).AckJSON(t)
+		assert.Contains(t, unrelated["text"], "Unknown subcommand `deploy`.\n\n*Usage:*")
+	})
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+// hookRecorder records the tracing hook calls of an app
+type hookRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *hookRecorder) add(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *hookRecorder) Calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func TestTracingHooks(t *testing.T) {
+	t.Parallel()
+	t.Run("should call hooks around events and listeners with their outcomes", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		recorder := &hookRecorder{}
+		var endInfo bolt.ProcessEventInfo
+		var endErr, listenerErr error
+
+		h.App.OnEventStart(func(ctx context.Context, event bolt.ReceiverEvent) context.Context {
+			recorder.add("event start")
+			return context.WithValue(ctx, spanKey{}, "event span")
+		})
+		h.App.OnListenerStart(func(ctx context.Context, listener string) context.Context {
+			recorder.add("listener start " + listener + " in " + ctx.Value(spanKey{}).(string))
+			return context.WithValue(ctx, spanKey{}, "listener span")
+		})
+		h.App.OnListenerEnd(func(ctx context.Context, listener string, err error) {
+			recorder.add("listener end " + listener + " in " + ctx.Value(spanKey{}).(string))
+			listenerErr = err
+		})
+		h.App.OnEventEnd(func(ctx context.Context, event bolt.ReceiverEvent, info bolt.ProcessEventInfo, err error) {
+			recorder.add("event end in " + ctx.Value(spanKey{}).(string))
+			endInfo, endErr = info, err
+		})
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			recorder.add("listener")
+			return errors.New("listener failed")
+		})
+
+		h.Send(bolttest.AppMention("hello"))
+
+		assert.Equal(t, []string{
+			"event start",
+			"listener start event(type=app_mention) in event span",
+			"listener",
+			"listener end event(type=app_mention) in listener span",
+			"event end in event span",
+		}, recorder.Calls())
+		assert.EqualError(t, listenerErr, "listener failed")
+		assert.Error(t, endErr)
+		assert.Equal(t, "event", endInfo.Type)
+		assert.Equal(t, "app_mention", endInfo.EventType)
+		assert.Equal(t, bolttest.TeamID, endInfo.TeamID)
+		require.Len(t, endInfo.Listeners, 1)
+		assert.Equal(t, "event(type=app_mention)", endInfo.Listeners[0].Listener)
+	})
+
+	t.Run("should nest hooks registered by several integrations", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		recorder := &hookRecorder{}
+		for _, name := range []string{"a", "b"} {
+			h.App.OnEventStart(func(ctx context.Context, event bolt.ReceiverEvent) context.Context {
+				recorder.add("start " + name)
+				return nil
+			})
+			h.App.OnEventEnd(func(ctx context.Context, event bolt.ReceiverEvent, info bolt.ProcessEventInfo, err error) {
+				recorder.add("end " + name)
+			})
+		}
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error { return nil })
+
+		h.Send(bolttest.AppMention("hello"))
+		assert.Equal(t, []string{"start a", "start b", "end b", "end a"}, recorder.Calls())
+	})
+
+	t.Run("should pass recovered panics to listener end hooks", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var listenerErr error
+		h.App.OnListenerEnd(func(ctx context.Context, listener string, err error) {
+			listenerErr = err
+		})
+		h.App.Command("/boom", func(args bolt.SlackCommandMiddlewareArgs) error {
+			panic("boom")
+		})
+
+		h.Send(bolttest.Command("/boom", ""))
+		require.Error(t, listenerErr)
+		assert.Contains(t, listenerErr.Error(), "boom")
+	})
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/tunnel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTunnelProvider records the address it was started with
+type fakeTunnelProvider struct {
+	addr   string
+	url    string
+	closed bool
+	// started is called once the tunnel is up
+	started func()
+}
+
+func (p *fakeTunnelProvider) Start(ctx context.Context, addr string) (tunnel.Tunnel, error) {
+	p.addr = addr
+	if p.started != nil {
+		p.started()
+	}
+	return p, nil
+}
+
+func (p *fakeTunnelProvider) URL() string { return p.url }
+
+func (p *fakeTunnelProvider) Close() error {
+	p.closed = true
+	return nil
+}
+
+// fakeNgrok writes a shell script standing in for the ngrok agent
+func fakeNgrok(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ngrok agent is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "ngrok")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o700))
+	return path
+}
+
+func TestTunnel(t *testing.T) {
+	t.Parallel()
+	t.Run("should require developer mode", func(t *testing.T) {
+		_, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			Tunnel:        &fakeTunnelProvider{},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tunnel requires developer mode")
+	})
+
+	t.Run("should tunnel to the HTTP receiver and log its request URLs", func(t *testing.T) {
+		var logs bytes.Buffer
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		// Cancel before the receiver listens, so the test does not bind its port
+		provider := &fakeTunnelProvider{url: "https://bolt.ngrok.app/", started: cancel}
+
+		app, err := bolt.New(bolt.AppOptions{
+			Token:         fakeToken,
+			SigningSecret: fakeSigningSecret,
+			DeveloperMode: true,
+			Logger:        slog.New(slog.NewTextHandler(&logs, nil)),
+			Tunnel:        provider,
+		})
+		require.NoError(t, err)
+
+		err = app.Start(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, "localhost:3000", provider.addr)
+		assert.Contains(t, logs.String(), "events_url=https://bolt.ngrok.app/slack/events")
+		assert.True(t, provider.closed, "tunnel should be closed when the receiver stops")
+	})
+
+	t.Run("should start the ngrok agent and report its public URL", func(t *testing.T) {
+		args := filepath.Join(t.TempDir(), "args")
+		path := fakeNgrok(t, `echo "$@" > `+args+`
+echo '{"lvl":"info","msg":"client session established"}'
+echo '{"lvl":"info","msg":"started tunnel","name":"command_line","addr":"http://localhost:3000","url":"https://abc.ngrok.app"}'
+exec sleep 60
+`)
+		provider := &tunnel.Ngrok{Path: path, URL: "abc.ngrok.app", StartTimeout: 10 * time.Second}
+
+		started, err := provider.Start(context.Background(), "localhost:3000")
+		require.NoError(t, err)
+		assert.Equal(t, "https://abc.ngrok.app", started.URL())
+		require.NoError(t, started.Close())
+
+		data, err := os.ReadFile(args)
+		require.NoError(t, err)
+		assert.Equal(t, "http localhost:3000 --log stdout --log-format json --url abc.ngrok.app\n", string(data))
+	})
+
+	t.Run("should report why the ngrok agent failed", func(t *testing.T) {
+		path := fakeNgrok(t, `echo '{"lvl":"crit","msg":"command failed","err":"authentication failed: invalid authtoken"}'
+exit 1
+`)
+		_, err := (&tunnel.Ngrok{Path: path}).Start(context.Background(), "localhost:3000")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid authtoken")
+	})
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTyped(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should deliver app mentions decoded", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var mention bolt.AppMentionEvent
+		bolt.EventTyped(h.App, func(args bolt.TypedEventArgs[bolt.AppMentionEvent]) error {
+			mention = args.Event
+			_, err := args.Say(types.SayString("hi <@" + args.Event.User + ">"))
+			return err
+		})
+
+		h.Send(bolttest.AppMention("deploy")).AssertNoError(t)
+
+		assert.Equal(t, "app_mention", mention.GetType())
+		assert.Equal(t, "<@"+bolttest.BotUserID+"> deploy", mention.Text)
+		assert.Equal(t, bolttest.UserID, mention.User)
+		assert.Equal(t, bolttest.ChannelID, mention.Channel)
+		require.Len(t, h.Slack.Said(), 1)
+		assert.Equal(t, "hi <@"+bolttest.UserID+">", h.Slack.Said()[0].Text())
+	})
+
+	t.Run("should only run for the event type of the struct", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var reactions []bolt.ReactionAddedEvent
+		bolt.EventTyped(h.App, func(args bolt.TypedEventArgs[bolt.ReactionAddedEvent]) error {
+			reactions = append(reactions, args.Event)
+			return nil
+		})
+
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+		h.Send(bolttest.Event("reaction_added", map[string]any{
+			"reaction":  "tada",
+			"item_user": "U0000ITEM",
+			"item":      map[string]any{"type": "message", "channel": bolttest.ChannelID, "ts": "1700000000.000200"},
+		})).AssertNoError(t)
+
+		require.Len(t, reactions, 1)
+		assert.Equal(t, "tada", reactions[0].Reaction)
+		assert.Equal(t, "U0000ITEM", reactions[0].ItemUser)
+		assert.Equal(t, bolt.ReactionItem{Type: "message", Channel: bolttest.ChannelID, TS: "1700000000.000200"}, reactions[0].Item)
+	})
+
+	t.Run("should fail events that do not decode", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		ran := false
+		bolt.EventTyped(h.App, func(args bolt.TypedEventArgs[bolt.ReactionAddedEvent]) error {
+			ran = true
+			return nil
+		})
+
+		result := h.Send(bolttest.Event("reaction_added", map[string]any{"item": "not an object"})).AssertError(t)
+
+		assert.ErrorContains(t, result.Err, "failed to parse reaction_added event")
+		assert.False(t, ran)
+	})
+}
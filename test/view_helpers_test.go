@@ -0,0 +1,103 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
+	"github.com/Asafrose/bolt-go/pkg/types"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func helperModal(title string) slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:  slack.VTModal,
+		Title: slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+	}
+}
+
+func TestViewHelpers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should open a view with the trigger_id of a shortcut", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.ShortcutString("open", func(args bolt.SlackShortcutMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			response, err := args.OpenView(helperModal("Hello"))
+			if err == nil {
+				assert.NotEmpty(t, response.ID)
+			}
+			return err
+		})
+
+		h.Send(bolttest.GlobalShortcut("open")).AssertNoError(t).AssertAcked(t)
+
+		calls := h.Slack.Calls("views.open")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "1.0.bolttest", calls[0].Params["trigger_id"])
+	})
+
+	t.Run("should open, push and update views from actions", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.Action(types.ActionConstraints{ActionID: "step"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			if _, err := args.OpenView(helperModal("Opened")); err != nil {
+				return err
+			}
+			if _, err := args.PushView(helperModal("Pushed")); err != nil {
+				return err
+			}
+			_, err := args.UpdateView(helperModal("Updated"))
+			return err
+		})
+
+		h.Send(modalBlockAction(t, "step")).AssertNoError(t)
+
+		assert.Equal(t, "1.0.bolttest", h.Slack.Calls("views.open")[0].Params["trigger_id"])
+		assert.Equal(t, "1.0.bolttest", h.Slack.Calls("views.push")[0].Params["trigger_id"])
+		h.Slack.AssertCalledWith(t, "views.update", map[string]any{
+			"view_id": "V0000TEST",
+			"hash":    "1700000000.abc",
+		})
+	})
+
+	t.Run("should update the submitted view", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		h.App.ViewString("form", func(args bolt.SlackViewMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			_, err := args.UpdateView(helperModal("Thanks"))
+			return err
+		})
+
+		h.Send(bolttest.ViewSubmission("form", nil)).AssertNoError(t)
+
+		h.Slack.AssertCalledWith(t, "views.update", map[string]any{
+			"view_id": "V0000TEST",
+			"hash":    "bolttest-hash",
+		})
+	})
+
+	t.Run("should fail to update a view from an action in a message", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{})
+		var updateErr error
+		h.App.Action(types.ActionConstraints{ActionID: "approve"}, func(args bolt.SlackActionMiddlewareArgs) error {
+			_, updateErr = args.UpdateView(helperModal("Updated"))
+			return args.Ack(nil)
+		})
+
+		h.Send(bolttest.BlockAction("approve", "yes")).AssertNoError(t)
+
+		var missing *bolt.ContextMissingPropertyError
+		require.ErrorAs(t, updateErr, &missing)
+		assert.Equal(t, "view_id", missing.MissingProperty)
+		h.Slack.AssertNotCalled(t, "views.update")
+	})
+}
@@ -1,9 +1,11 @@
 package test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/Asafrose/bolt-go"
+	"github.com/Asafrose/bolt-go/pkg/bolttest"
 	"github.com/Asafrose/bolt-go/pkg/workflow"
 	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
@@ -358,3 +360,221 @@ func TestWorkflowStepArgumentAugmentation(t *testing.T) {
 		// Here we verify the structure and function availability
 	})
 }
+
+// stepPayload encodes a workflow step request body as receivers pass it to the app
+func stepPayload(body map[string]any) bolttest.Payload {
+	data, _ := json.Marshal(body)
+	return bolttest.Payload{Body: data, Headers: map[string]string{"Content-Type": "application/json"}}
+}
+
+// stepEdit builds the request sent when a user adds or edits the step with callbackID
+func stepEdit(callbackID string) bolttest.Payload {
+	return stepPayload(map[string]any{
+		"type":          "workflow_step_edit",
+		"callback_id":   callbackID,
+		"trigger_id":    "1.0.step",
+		"team":          map[string]any{"id": bolttest.TeamID},
+		"user":          map[string]any{"id": bolttest.UserID},
+		"workflow_step": map[string]any{"workflow_id": "W123", "step_id": "S123", "workflow_step_edit_id": "WSE123"},
+	})
+}
+
+// stepSave builds the submission of the configuration modal of the step with callbackID
+func stepSave(callbackID string) bolttest.Payload {
+	return stepPayload(map[string]any{
+		"type":          "view_submission",
+		"team":          map[string]any{"id": bolttest.TeamID},
+		"user":          map[string]any{"id": bolttest.UserID},
+		"view":          map[string]any{"id": "V123", "type": "workflow_step", "callback_id": callbackID, "state": map[string]any{"values": map[string]any{}}},
+		"workflow_step": map[string]any{"workflow_id": "W123", "step_id": "S123", "workflow_step_edit_id": "WSE123"},
+	})
+}
+
+// stepExecute builds the event sent when a workflow runs the step with callbackID
+func stepExecute(callbackID string) bolttest.Payload {
+	return bolttest.Event("workflow_step_execute", map[string]any{
+		"callback_id":   callbackID,
+		"workflow_step": map[string]any{"workflow_step_execute_id": "WSX123", "inputs": map[string]any{"task": map[string]any{"value": "ship"}}},
+	})
+}
+
+// noopStep returns step middleware that continues the chain
+func noopStep() bolt.WorkflowStepConfig {
+	return bolt.WorkflowStepConfig{
+		Edit:    []bolt.WorkflowStepEditMiddleware{func(args bolt.WorkflowStepEditMiddlewareArgs) error { return args.Next() }},
+		Save:    []bolt.WorkflowStepSaveMiddleware{func(args bolt.WorkflowStepSaveMiddlewareArgs) error { return args.Next() }},
+		Execute: []bolt.WorkflowStepExecuteMiddleware{func(args bolt.WorkflowStepExecuteMiddlewareArgs) error { return args.Next() }},
+	}
+}
+
+func TestWorkflowStepLifecycle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should ack an edit and open the configuration modal", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("xoxb-bot", "")})
+		config := noopStep()
+		var step any
+		config.Edit = []bolt.WorkflowStepEditMiddleware{func(args bolt.WorkflowStepEditMiddlewareArgs) error {
+			step = args.Step
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			metadata := "meta"
+			return args.Configure(workflow.StepConfigureArguments{
+				Blocks:          []slack.Block{slack.NewDividerBlock()},
+				PrivateMetadata: &metadata,
+			})
+		}}
+		ws, err := bolt.NewWorkflowStep("copy_review", config)
+		require.NoError(t, err)
+		h.App.Use(ws.GetMiddleware())
+
+		h.Send(stepEdit("copy_review")).AssertNoError(t).AssertAcked(t)
+
+		assert.Equal(t, "WSE123", step.(map[string]any)["workflow_step_edit_id"])
+		calls := h.Slack.Calls("views.open")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "xoxb-bot", calls[0].Token)
+		assert.Equal(t, "1.0.step", calls[0].Params["trigger_id"])
+		view := calls[0].Params["view"].(map[string]any)
+		assert.Equal(t, "workflow_step", view["type"])
+		assert.Equal(t, "copy_review", view["callback_id"])
+		assert.Equal(t, "meta", view["private_metadata"])
+		assert.Len(t, view["blocks"], 1)
+	})
+
+	t.Run("should update the step when its modal is saved", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("xoxb-bot", "")})
+		config := noopStep()
+		config.Save = []bolt.WorkflowStepSaveMiddleware{func(args bolt.WorkflowStepSaveMiddlewareArgs) error {
+			if err := args.Ack(nil); err != nil {
+				return err
+			}
+			name := "Review copy"
+			return args.Update(&workflow.StepUpdateArguments{
+				Inputs:   map[string]workflow.StepInput{"task": {Value: "{{user}}"}},
+				Outputs:  []workflow.StepOutput{{Name: "reviewer", Type: "user", Label: "Reviewer"}},
+				StepName: &name,
+			})
+		}}
+		ws, err := bolt.NewWorkflowStep("copy_review", config)
+		require.NoError(t, err)
+		h.App.Use(ws.GetMiddleware())
+
+		h.Send(stepSave("copy_review")).AssertNoError(t).AssertAcked(t)
+
+		calls := h.Slack.Calls("workflows.updateStep")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "WSE123", calls[0].Params["workflow_step_edit_id"])
+		assert.Equal(t, "Review copy", calls[0].Params["step_name"])
+		assert.Equal(t, map[string]any{"task": map[string]any{"value": "{{user}}"}}, calls[0].Params["inputs"])
+		assert.Len(t, calls[0].Params["outputs"], 1)
+	})
+
+	t.Run("should complete and fail step executions", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("xoxb-bot", "")})
+		config := noopStep()
+		config.Execute = []bolt.WorkflowStepExecuteMiddleware{
+			func(args bolt.WorkflowStepExecuteMiddlewareArgs) error {
+				inputs := args.Step.(map[string]any)["inputs"].(map[string]any)
+				if err := args.Complete(&workflow.StepCompleteArguments{Outputs: map[string]any{"task": inputs["task"]}}); err != nil {
+					return err
+				}
+				return args.Next()
+			},
+			func(args bolt.WorkflowStepExecuteMiddlewareArgs) error {
+				return args.Fail(workflow.StepFailArguments{Error: workflow.StepError{Message: "second attempt"}})
+			},
+		}
+		ws, err := bolt.NewWorkflowStep("copy_review", config)
+		require.NoError(t, err)
+		h.App.Use(ws.GetMiddleware())
+
+		h.Send(stepExecute("copy_review")).AssertNoError(t)
+
+		h.Slack.AssertCalledWith(t, "workflows.stepCompleted", map[string]any{
+			"workflow_step_execute_id": "WSX123",
+			"outputs":                  map[string]any{"task": map[string]any{"value": "ship"}},
+		})
+		h.Slack.AssertCalledWith(t, "workflows.stepFailed", map[string]any{
+			"workflow_step_execute_id": "WSX123",
+			"error":                    map[string]any{"message": "second attempt"},
+		})
+	})
+
+	t.Run("should surface Slack errors from step utilities", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("xoxb-bot", "")})
+		h.Slack.Handle("workflows.stepCompleted", map[string]any{"ok": false, "error": "invalid_workflow_step_execute_id"})
+		config := noopStep()
+		var completeErr error
+		config.Execute = []bolt.WorkflowStepExecuteMiddleware{func(args bolt.WorkflowStepExecuteMiddlewareArgs) error {
+			completeErr = args.Complete(nil)
+			return nil
+		}}
+		ws, err := bolt.NewWorkflowStep("copy_review", config)
+		require.NoError(t, err)
+		h.App.Use(ws.GetMiddleware())
+
+		h.Send(stepExecute("copy_review")).AssertNoError(t)
+
+		var slackErr slack.SlackErrorResponse
+		require.ErrorAs(t, completeErr, &slackErr)
+		assert.Equal(t, "invalid_workflow_step_execute_id", slackErr.Err)
+	})
+
+	t.Run("should pass on requests for other steps and listeners", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("xoxb-bot", "")})
+		config := noopStep()
+		var stepRan bool
+		config.Execute = []bolt.WorkflowStepExecuteMiddleware{func(args bolt.WorkflowStepExecuteMiddlewareArgs) error {
+			stepRan = true
+			return nil
+		}}
+		ws, err := bolt.NewWorkflowStep("copy_review", config)
+		require.NoError(t, err)
+		h.App.Use(ws.GetMiddleware())
+
+		var mentioned bool
+		h.App.Event("app_mention", func(args bolt.SlackEventMiddlewareArgs) error {
+			mentioned = true
+			return nil
+		})
+
+		h.Send(stepExecute("other_step")).AssertNoError(t)
+		h.Send(bolttest.AppMention("hello")).AssertNoError(t)
+
+		assert.False(t, stepRan)
+		assert.True(t, mentioned)
+		h.Slack.AssertNotCalled(t, "workflows.stepCompleted")
+	})
+
+	t.Run("should ack requests the step's middleware does not", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("xoxb-bot", "")})
+		ws, err := bolt.NewWorkflowStep("copy_review", noopStep())
+		require.NoError(t, err)
+		h.App.Step(ws)
+
+		h.Send(stepEdit("copy_review")).AssertNoError(t).AssertAcked(t)
+		h.Send(stepSave("copy_review")).AssertNoError(t).AssertAcked(t)
+		h.Send(stepExecute("copy_review")).AssertNoError(t).AssertAcked(t)
+	})
+
+	t.Run("should keep the errors saves are acked with", func(t *testing.T) {
+		h := bolttest.New(t, bolt.AppOptions{Authorize: authorizeTokens("xoxb-bot", "")})
+		config := noopStep()
+		config.Save = []bolt.WorkflowStepSaveMiddleware{func(args bolt.WorkflowStepSaveMiddlewareArgs) error {
+			return args.Ack(&bolt.ViewResponse{
+				ResponseAction: "errors",
+				Errors:         map[string]string{"task": "Pick a task"},
+			})
+		}}
+		ws, err := bolt.NewWorkflowStep("copy_review", config)
+		require.NoError(t, err)
+		h.App.Step(ws)
+
+		result := h.Send(stepSave("copy_review")).AssertNoError(t).AssertAcked(t)
+
+		assert.Equal(t, map[string]any{"response_action": "errors", "errors": map[string]any{"task": "Pick a task"}}, result.AckJSON(t))
+		h.Slack.AssertNotCalled(t, "workflows.updateStep")
+	})
+}